@@ -0,0 +1,179 @@
+package tabula
+
+import (
+	"fmt"
+
+	"github.com/tsawler/tabula/format"
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/pages"
+	"github.com/tsawler/tabula/text"
+)
+
+// Annotations returns the annotations on the selected pages: links with
+// their URI or resolved internal target, sticky notes, free text, text
+// markup with the text it covers, and stamps. Form field widgets are not
+// included. Formats other than PDF have no annotations.
+// This is a terminal operation that closes the underlying reader.
+//
+// Example:
+//
+//	annots, _, err := tabula.Open("reviewed.pdf").Annotations()
+//	for _, a := range annots {
+//	    fmt.Printf("p%d %s by %s: %s\n", a.Page, a.Type, a.Author, a.Contents)
+//	}
+func (e *Extractor) Annotations() ([]*model.Annotation, []Warning, error) {
+	if e.err != nil {
+		return nil, nil, e.err
+	}
+
+	if err := e.ensureReader(); err != nil {
+		return nil, nil, err
+	}
+	defer e.Close()
+
+	if e.format != format.PDF {
+		return nil, e.warnings, nil
+	}
+
+	pageIndices, err := e.resolvePages()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result []*model.Annotation
+	for _, pageNum := range pageIndices {
+		if err := e.ctxErr(); err != nil {
+			return nil, nil, err
+		}
+		page, err := e.reader.GetPage(pageNum)
+		if err != nil {
+			return nil, nil, fmt.Errorf("page %d: %w", pageNum+1, err)
+		}
+
+		annots, err := e.reader.Annotations(page)
+		if err != nil {
+			return nil, nil, fmt.Errorf("page %d: %w", pageNum+1, err)
+		}
+
+		// Text is only needed to recover what markup annotations cover; it is
+		// filtered like the text Text() returns
+		var fragments []text.TextFragment
+		for _, annot := range annots {
+			if model.AnnotationTypeFromSubtype(annot.Subtype).IsTextMarkup() {
+				fragments, err = e.pageFragments(page, pageNum)
+				if err != nil {
+					return nil, nil, fmt.Errorf("page %d: %w", pageNum+1, err)
+				}
+				break
+			}
+		}
+
+		result = append(result, modelAnnotations(annots, pageNum+1, fragments)...)
+	}
+
+	return result, e.warnings, nil
+}
+
+// modelAnnotations converts a page's annotations, skipping form field widgets
+func modelAnnotations(annots []*pages.Annotation, pageNum int, fragments []text.TextFragment) []*model.Annotation {
+	var result []*model.Annotation
+	for _, annot := range annots {
+		if annot.Subtype == "Widget" {
+			continue
+		}
+
+		a := &model.Annotation{
+			Type:     model.AnnotationTypeFromSubtype(annot.Subtype),
+			Subtype:  annot.Subtype,
+			Page:     pageNum,
+			BBox:     model.BBox{X: annot.Rect[0], Y: annot.Rect[1], Width: annot.Rect[2] - annot.Rect[0], Height: annot.Rect[3] - annot.Rect[1]},
+			Author:   annot.Author,
+			Subject:  annot.Subject,
+			Contents: annot.Contents,
+			Name:     annot.Name,
+			URI:      annot.URI,
+		}
+		if annot.HasDest && annot.Dest.PageIndex >= 0 {
+			a.TargetPage = annot.Dest.PageIndex + 1
+			a.TargetY = annot.Dest.Y
+			a.HasTargetY = annot.Dest.HasY
+		}
+		if a.Type.IsTextMarkup() {
+			a.MarkedText = markedText(annot, fragments)
+		}
+		result = append(result, a)
+	}
+	return result
+}
+
+// markedText returns the text of the fragments a markup annotation covers
+func markedText(annot *pages.Annotation, fragments []text.TextFragment) string {
+	var covered []text.TextFragment
+	for _, f := range fragments {
+		if annot.Contains(f.X+f.Width/2, f.Y+f.Height/2) {
+			covered = append(covered, f)
+		}
+	}
+	return joinTagged(covered)
+}
+
+// pageLinks groups consecutive fragments that share a URI into links
+func pageLinks(fragments []text.TextFragment) []model.Link {
+	var links []model.Link
+	for i := 0; i < len(fragments); {
+		uri := fragments[i].URI
+		j := i + 1
+		for j < len(fragments) && fragments[j].URI == uri {
+			j++
+		}
+		if uri != "" {
+			if linkText := joinTagged(fragments[i:j]); linkText != "" {
+				links = append(links, model.Link{Text: linkText, URI: uri, BBox: taggedBBox(fragments[i:j])})
+			}
+		}
+		i = j
+	}
+	return links
+}
+
+// addComments inserts the page's comment annotations into its elements,
+// each after the content above it. Links and widgets are not comments, and
+// comments without any text are skipped.
+func (e *Extractor) addComments(modelPage *model.Page, page *pages.Page, fragments []text.TextFragment) {
+	annots, err := e.reader.Annotations(page)
+	if err != nil {
+		return
+	}
+
+	for _, a := range modelAnnotations(annots, modelPage.Number, fragments) {
+		if a.Type == model.AnnotationLink {
+			continue
+		}
+		comment := &model.Comment{Annotation: *a}
+		if comment.GetText() == "" {
+			continue
+		}
+		insertComment(modelPage, comment)
+	}
+}
+
+// insertComment inserts a comment before the first content element that
+// starts below it, so it follows the text it annotates.
+func insertComment(p *model.Page, comment *model.Comment) {
+	pos := len(p.Elements)
+	for i, elem := range p.Elements {
+		switch elem.(type) {
+		case *model.Paragraph, *model.Heading, *model.List, *model.Table:
+		default:
+			continue
+		}
+		if elem.BoundingBox().Top() < comment.Annotation.BBox.Y {
+			pos = i
+			break
+		}
+	}
+
+	p.Elements = append(p.Elements, nil)
+	copy(p.Elements[pos+1:], p.Elements[pos:])
+	p.Elements[pos] = comment
+}
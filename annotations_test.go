@@ -0,0 +1,149 @@
+package tabula
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+)
+
+// buildAnnotatedPDF assembles a one-page PDF with a URI link over "the
+// project site", an internal link, a highlight with a comment, a sticky
+// note and a popup.
+func buildAnnotatedPDF() []byte {
+	content := `BT /F1 12 Tf 72 700 Td (Read more on) Tj ET
+BT /F1 12 Tf 150 700 Td (the project site) Tj ET
+BT /F1 12 Tf 72 600 Td (Revenue doubled in the second quarter of the year.) Tj ET
+BT /F1 12 Tf 72 500 Td (Closing remarks follow below.) Tj ET`
+	return buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 3 0 R >> >> /Annots [6 0 R 7 0 R 8 0 R 9 0 R 10 0 R] >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Annot /Subtype /Link /Rect [148 695 240 712] /A << /S /URI /URI (https://example.com/project) >> >>",
+		"<< /Type /Annot /Subtype /Link /Rect [72 495 200 512] /Dest [4 0 R /XYZ 0 792 null] >>",
+		"<< /Type /Annot /Subtype /Highlight /Rect [70 595 260 615] /QuadPoints [72 612 250 612 72 598 250 598] /T (Reviewer) /Contents (Source?) /Popup 9 0 R >>",
+		"<< /Type /Annot /Subtype /Popup /Rect [300 600 400 700] /Parent 8 0 R >>",
+		"<< /Type /Annot /Subtype /Text /Rect [500 700 520 720] /T (Editor) /Contents (Please update the intro) >>",
+	)
+}
+
+func TestAnnotations(t *testing.T) {
+	annots, _, err := FromBytes(buildAnnotatedPDF(), "").Annotations()
+	if err != nil {
+		t.Fatalf("Annotations() error: %v", err)
+	}
+	if len(annots) != 4 {
+		t.Fatalf("got %d annotations, want 4", len(annots))
+	}
+
+	if a := annots[0]; a.Type != model.AnnotationLink || a.URI != "https://example.com/project" || a.Page != 1 {
+		t.Errorf("annotation 0 = %+v, want URI link on page 1", a)
+	}
+	if a := annots[1]; a.TargetPage != 1 || !a.HasTargetY || a.TargetY != 792 {
+		t.Errorf("annotation 1 = %+v, want internal link to page 1 at 792", a)
+	}
+
+	highlight := annots[2]
+	if highlight.Type != model.AnnotationHighlight || highlight.Author != "Reviewer" || highlight.Contents != "Source?" {
+		t.Errorf("annotation 2 = %+v, want highlight by Reviewer", highlight)
+	}
+	if !strings.HasPrefix(highlight.MarkedText, "Revenue doubled") {
+		t.Errorf("highlight marked text = %q, want the highlighted line", highlight.MarkedText)
+	}
+
+	if a := annots[3]; a.Type != model.AnnotationNote || a.Contents != "Please update the intro" {
+		t.Errorf("annotation 3 = %+v, want sticky note", a)
+	}
+}
+
+func TestAnnotationsMarkedTextVisibleOnly(t *testing.T) {
+	// White text with nothing behind it is under the highlight too
+	content := `BT /F1 12 Tf 72 600 Td (Visible words) Tj ET
+1 1 1 rg BT /F1 12 Tf 180 600 Td (hidden words) Tj ET`
+	data := buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 3 0 R >> >> /Annots [6 0 R] >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Annot /Subtype /Highlight /Rect [70 595 300 615] /QuadPoints [72 612 290 612 72 598 290 598] >>",
+	)
+
+	annots, warnings, err := FromBytes(data, "").Annotations()
+	if err != nil {
+		t.Fatalf("Annotations() error: %v", err)
+	}
+	if len(annots) != 1 {
+		t.Fatalf("got %d annotations, want 1", len(annots))
+	}
+	if got := annots[0].MarkedText; got != "Visible words" {
+		t.Errorf("highlight marked text = %q, want %q", got, "Visible words")
+	}
+	found := false
+	for _, w := range warnings {
+		found = found || w.Code == WarningHiddenText
+	}
+	if !found {
+		t.Errorf("got warnings %v, want a hidden text warning", warnings)
+	}
+}
+
+func TestFragmentsLinked(t *testing.T) {
+	fragments, _, err := FromBytes(buildAnnotatedPDF(), "").Fragments()
+	if err != nil {
+		t.Fatalf("Fragments() error: %v", err)
+	}
+	for _, f := range fragments {
+		want := ""
+		if f.Text == "the project site" {
+			want = "https://example.com/project"
+		}
+		if f.URI != want {
+			t.Errorf("fragment %q URI = %q, want %q", f.Text, f.URI, want)
+		}
+	}
+}
+
+func TestToMarkdownLinks(t *testing.T) {
+	md, _, err := FromBytes(buildAnnotatedPDF(), "").ToMarkdown()
+	if err != nil {
+		t.Fatalf("ToMarkdown() error: %v", err)
+	}
+	if !strings.Contains(md, "[the project site](https://example.com/project)") {
+		t.Errorf("markdown is missing the link:\n%s", md)
+	}
+}
+
+func TestIncludeComments(t *testing.T) {
+	doc, _, err := FromBytes(buildAnnotatedPDF(), "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	for _, elem := range doc.Pages[0].Elements {
+		if _, ok := elem.(*model.Comment); ok {
+			t.Fatal("got comment elements without IncludeComments()")
+		}
+	}
+
+	chunks, _, err := FromBytes(buildAnnotatedPDF(), "").IncludeComments().Chunks()
+	if err != nil {
+		t.Fatalf("Chunks() error: %v", err)
+	}
+	var text string
+	types := map[string]bool{}
+	for _, chunk := range chunks.Chunks {
+		text += chunk.Text + "\n"
+		for _, et := range chunk.Metadata.ElementTypes {
+			types[et] = true
+		}
+	}
+	if !strings.Contains(text, "[Highlight by Reviewer: Revenue doubled") || !strings.Contains(text, "[Comment by Editor: Please update the intro]") {
+		t.Errorf("chunk text is missing the comments:\n%s", text)
+	}
+	if !types["highlight"] || !types["comment"] {
+		t.Errorf("chunk element types = %v, want highlight and comment", types)
+	}
+}
@@ -0,0 +1,194 @@
+package tabula
+
+import (
+	"fmt"
+
+	"github.com/tsawler/tabula/attachments"
+	"github.com/tsawler/tabula/format"
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/rag"
+)
+
+// maxAttachmentDepth limits how deeply attachments of attachments are
+// extracted by IncludeAttachments
+const maxAttachmentDepth = 3
+
+// Attachments returns the files embedded in the document: the files
+// attached to the document as a whole, including the files of a PDF
+// portfolio and PDF/A-3 associated files such as the XML data of a ZUGFeRD
+// or Factur-X invoice, followed by the files of file attachment annotations.
+// Page selection does not apply. Formats other than PDF have no
+// attachments.
+// This is a terminal operation that closes the underlying reader.
+//
+// Example:
+//
+//	files, _, err := tabula.Open("invoice.pdf").Attachments()
+//	for _, f := range files {
+//	    fmt.Printf("%s (%s): %d bytes\n", f.Name, f.MIMEType, len(f.Data))
+//	}
+func (e *Extractor) Attachments() ([]*model.Attachment, []Warning, error) {
+	if e.err != nil {
+		return nil, nil, e.err
+	}
+
+	if err := e.ensureReader(); err != nil {
+		return nil, nil, err
+	}
+	defer e.Close()
+
+	if e.format != format.PDF {
+		return nil, e.warnings, nil
+	}
+
+	files, err := e.reader.Attachments()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]*model.Attachment, 0, len(files))
+	for _, f := range files {
+		result = append(result, modelAttachment(f))
+	}
+	return result, e.warnings, nil
+}
+
+// modelAttachment converts an embedded file
+func modelAttachment(f *attachments.File) *model.Attachment {
+	return &model.Attachment{
+		Name:         f.Name,
+		Description:  f.Description,
+		MIMEType:     f.MIMEType,
+		Data:         f.Data,
+		CreationDate: f.CreationDate,
+		ModDate:      f.ModDate,
+		Relationship: f.Relationship,
+		Page:         f.Page + 1,
+	}
+}
+
+// includedAttachments returns the PDF's attachments when IncludeAttachments
+// is set and the nesting limit has not been reached. Attachments that
+// cannot be read are left out.
+func (e *Extractor) includedAttachments() []*model.Attachment {
+	if !e.options.includeAttachments || e.attachmentDepth >= maxAttachmentDepth || e.reader == nil {
+		return nil
+	}
+	files, err := e.reader.Attachments()
+	if err != nil {
+		return nil
+	}
+	result := make([]*model.Attachment, 0, len(files))
+	for _, f := range files {
+		result = append(result, modelAttachment(f))
+	}
+	return result
+}
+
+// attachmentExtractor returns an extractor for an attachment's contents
+// with the options of e, apart from page selection and the password, or
+// nil, with a warning, if the attachment's format is not supported
+func (e *Extractor) attachmentExtractor(a *model.Attachment) *Extractor {
+	sub := FromBytes(a.Data, a.Name)
+	if sub.format == format.Unknown {
+		e.warnings = append(e.warnings, Warning{
+			Code:    WarningAttachment,
+			Message: fmt.Sprintf("Attachment %q: unsupported format (%s)", a.Name, a.MIMEType),
+		})
+		return nil
+	}
+	sub.options = e.options.clone()
+	sub.options.pages = nil
+	sub.options.pageLabels = nil
+	sub.options.password = ""
+	sub.ctx = e.ctx
+	sub.attachmentDepth = e.attachmentDepth + 1
+	return sub
+}
+
+// addAttachmentWarnings records the outcome of extracting an attachment:
+// its warnings, prefixed with its name, or the error that left it out
+func (e *Extractor) addAttachmentWarnings(a *model.Attachment, warnings []Warning, err error) {
+	if err != nil {
+		e.warnings = append(e.warnings, Warning{
+			Code:    WarningAttachment,
+			Message: fmt.Sprintf("Attachment %q: %v", a.Name, err),
+		})
+		return
+	}
+	for _, w := range warnings {
+		e.warnings = append(e.warnings, Warning{
+			Code:    w.Code,
+			Message: fmt.Sprintf("Attachment %q: %s", a.Name, w.Message),
+		})
+	}
+}
+
+// attachmentTexts returns the text of each included attachment, headed by
+// an "[Attachment: name]" line
+func (e *Extractor) attachmentTexts() []string {
+	var texts []string
+	for _, a := range e.includedAttachments() {
+		sub := e.attachmentExtractor(a)
+		if sub == nil {
+			continue
+		}
+		text, warnings, err := sub.Text()
+		e.addAttachmentWarnings(a, warnings, err)
+		if err == nil {
+			texts = append(texts, fmt.Sprintf("[Attachment: %s]\n\n%s", a.Name, text))
+		}
+	}
+	return texts
+}
+
+// attachmentDocuments returns the included attachments with their
+// extracted documents. Attachments that cannot be extracted are returned
+// without a document.
+func (e *Extractor) attachmentDocuments() []*model.Attachment {
+	included := e.includedAttachments()
+	for _, a := range included {
+		sub := e.attachmentExtractor(a)
+		if sub == nil {
+			continue
+		}
+		doc, warnings, err := sub.Document()
+		e.addAttachmentWarnings(a, warnings, err)
+		a.Document = doc
+	}
+	if len(included) == 0 {
+		return nil
+	}
+	return included
+}
+
+// chunkWithAttachments chunks a document with chunk and appends the chunks
+// of its extracted attachments, whose Metadata.Attachment names the file.
+// Chunk indexes and IDs run on across the attachments.
+func chunkWithAttachments(doc *model.Document, chunk func(*model.Document) *rag.ChunkCollection) *rag.ChunkCollection {
+	collection := chunk(doc)
+	if len(doc.Attachments) == 0 {
+		return collection
+	}
+
+	for _, a := range doc.Attachments {
+		if a.Document == nil {
+			continue
+		}
+		for _, c := range chunkWithAttachments(a.Document, chunk).Chunks {
+			if c.Metadata.Attachment == "" {
+				c.Metadata.Attachment = a.Name
+			} else {
+				c.Metadata.Attachment = a.Name + "/" + c.Metadata.Attachment
+			}
+			collection.Chunks = append(collection.Chunks, c)
+		}
+	}
+
+	for i, c := range collection.Chunks {
+		c.ID = fmt.Sprintf("chunk-%d", i)
+		c.Metadata.ChunkIndex = i
+		c.Metadata.TotalChunks = len(collection.Chunks)
+	}
+	return collection
+}
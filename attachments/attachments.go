@@ -0,0 +1,208 @@
+package attachments
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/font"
+	"github.com/tsawler/tabula/metadata"
+)
+
+// maxDepth limits how deep the /EmbeddedFiles name tree is followed
+const maxDepth = 64
+
+// Resolver resolves indirect references
+type Resolver func(core.IndirectRef) (core.Object, error)
+
+// File is a file embedded in a PDF
+type File struct {
+	Name         string // File name (/UF, or else /F)
+	Description  string // Description (/Desc)
+	MIMEType     string // From the embedded file's /Subtype, or else guessed from the name
+	Data         []byte // Decoded contents
+	CreationDate time.Time
+	ModDate      time.Time
+	Relationship string // /AFRelationship of an associated file, e.g. "Alternative" or "Data"
+	Page         int    // 0-based page of a file attachment annotation, or -1 for document-level files
+}
+
+// Parse reads the files embedded in a document: those of the catalog's
+// /Names /EmbeddedFiles name tree, which also holds the files of a PDF
+// portfolio, then the catalog's associated files (/AF), then the files of
+// the FileAttachment annotations in pageAnnots, which holds each page's
+// /Annots array. A file reachable in more than one way is returned once.
+// File specifications that refer to external files rather than embedding
+// them, and embedded streams that cannot be decoded, are skipped.
+func Parse(catalog core.Dict, pageAnnots []core.Array, resolver Resolver) []*File {
+	p := &parser{resolver: resolver, seen: make(map[int]bool), visited: make(map[int]bool)}
+
+	if names, ok := p.resolve(catalog.Get("Names")).(core.Dict); ok {
+		p.nameTree(names.Get("EmbeddedFiles"), 0)
+	}
+
+	if af, ok := p.resolve(catalog.Get("AF")).(core.Array); ok {
+		for _, spec := range af {
+			p.fileSpec(spec, -1)
+		}
+	}
+
+	for page, annots := range pageAnnots {
+		for _, annotObj := range annots {
+			annot, ok := p.resolve(annotObj).(core.Dict)
+			if !ok {
+				continue
+			}
+			if subtype, _ := p.resolve(annot.Get("Subtype")).(core.Name); subtype != "FileAttachment" {
+				continue
+			}
+			p.fileSpec(annot.Get("FS"), page)
+		}
+	}
+	return p.files
+}
+
+// parser holds the state for a single Parse call
+type parser struct {
+	resolver Resolver
+	files    []*File
+	seen     map[int]bool // Object numbers of the embedded file streams already read
+	visited  map[int]bool // Object numbers of the name tree nodes already walked
+}
+
+// resolve follows an indirect reference, returning nil if it cannot be resolved
+func (p *parser) resolve(obj core.Object) core.Object {
+	if ref, ok := obj.(core.IndirectRef); ok && p.resolver != nil {
+		resolved, err := p.resolver(ref)
+		if err != nil {
+			return nil
+		}
+		return resolved
+	}
+	return obj
+}
+
+// text resolves and decodes a text string
+func (p *parser) text(obj core.Object) string {
+	if s, ok := p.resolve(obj).(core.String); ok {
+		return font.DecodeTextString([]byte(s))
+	}
+	return ""
+}
+
+// nameTree reads the file specifications of a name tree node and its kids,
+// in key order
+func (p *parser) nameTree(obj core.Object, depth int) {
+	if depth > maxDepth {
+		return
+	}
+	if ref, ok := obj.(core.IndirectRef); ok {
+		if p.visited[ref.Number] {
+			return
+		}
+		p.visited[ref.Number] = true
+	}
+	node, ok := p.resolve(obj).(core.Dict)
+	if !ok {
+		return
+	}
+
+	if names, ok := p.resolve(node.Get("Names")).(core.Array); ok {
+		for i := 0; i+1 < len(names); i += 2 {
+			p.fileSpec(names[i+1], -1)
+		}
+	}
+	if kids, ok := p.resolve(node.Get("Kids")).(core.Array); ok {
+		for _, kid := range kids {
+			p.nameTree(kid, depth+1)
+		}
+	}
+}
+
+// fileSpec reads the embedded file of a file specification
+// (PDF 32000-1:2008, 7.11)
+func (p *parser) fileSpec(obj core.Object, page int) {
+	spec, ok := p.resolve(obj).(core.Dict)
+	if !ok {
+		return
+	}
+	ef, ok := p.resolve(spec.Get("EF")).(core.Dict)
+	if !ok {
+		return
+	}
+	streamObj := ef.Get("UF")
+	if streamObj == nil {
+		streamObj = ef.Get("F")
+	}
+	if ref, ok := streamObj.(core.IndirectRef); ok {
+		if p.seen[ref.Number] {
+			return
+		}
+		p.seen[ref.Number] = true
+	}
+	stream, ok := p.resolve(streamObj).(*core.Stream)
+	if !ok {
+		return
+	}
+	data, err := stream.Decode()
+	if err != nil {
+		return
+	}
+
+	f := &File{
+		Name:        p.text(spec.Get("UF")),
+		Description: p.text(spec.Get("Desc")),
+		Data:        data,
+		Page:        page,
+	}
+	if f.Name == "" {
+		f.Name = p.text(spec.Get("F"))
+	}
+	if rel, ok := p.resolve(spec.Get("AFRelationship")).(core.Name); ok {
+		f.Relationship = string(rel)
+	}
+	if subtype, ok := p.resolve(stream.Dict.Get("Subtype")).(core.Name); ok {
+		f.MIMEType = string(subtype)
+	}
+	if f.MIMEType == "" {
+		f.MIMEType = MIMEType(f.Name)
+	}
+	if params, ok := p.resolve(stream.Dict.Get("Params")).(core.Dict); ok {
+		f.CreationDate, _ = metadata.ParseDate(p.text(params.Get("CreationDate")))
+		f.ModDate, _ = metadata.ParseDate(p.text(params.Get("ModDate")))
+	}
+	p.files = append(p.files, f)
+}
+
+// mimeTypes maps file extensions to the MIME types of common attachments
+var mimeTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".xml":  "application/xml",
+	".json": "application/json",
+	".txt":  "text/plain",
+	".csv":  "text/csv",
+	".htm":  "text/html",
+	".html": "text/html",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".epub": "application/epub+zip",
+	".zip":  "application/zip",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+}
+
+// MIMEType guesses the MIME type of a file from its name's extension. It
+// returns "application/octet-stream" for unknown extensions.
+func MIMEType(name string) string {
+	if mimeType, ok := mimeTypes[strings.ToLower(path.Ext(name))]; ok {
+		return mimeType
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,103 @@
+package attachments
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// testResolver resolves references to numbered objects
+func testResolver(objects map[int]core.Object) Resolver {
+	return func(ref core.IndirectRef) (core.Object, error) {
+		if obj, ok := objects[ref.Number]; ok {
+			return obj, nil
+		}
+		return nil, fmt.Errorf("object %d not found", ref.Number)
+	}
+}
+
+func TestParse(t *testing.T) {
+	objects := map[int]core.Object{
+		// A two-level /EmbeddedFiles name tree
+		1: core.Dict{"Kids": core.Array{core.IndirectRef{Number: 2}}},
+		2: core.Dict{
+			"Limits": core.Array{core.String("factur-x.xml"), core.String("notes.txt")},
+			"Names": core.Array{
+				core.String("factur-x.xml"), core.IndirectRef{Number: 3},
+				core.String("notes.txt"), core.IndirectRef{Number: 5},
+			},
+		},
+		3: core.Dict{
+			"Type":           core.Name("Filespec"),
+			"F":              core.String("factur-x.xml"),
+			"UF":             core.String("\xfe\xff\x00f\x00a\x00c\x00t\x00u\x00r\x00-\x00x\x00.\x00x\x00m\x00l"),
+			"Desc":           core.String("Invoice data"),
+			"AFRelationship": core.Name("Alternative"),
+			"EF":             core.Dict{"F": core.IndirectRef{Number: 4}},
+		},
+		4: &core.Stream{
+			Dict: core.Dict{"Type": core.Name("EmbeddedFile"), "Subtype": core.Name("text/xml"), "Params": core.Dict{"ModDate": core.String("D:20240102030405Z")}},
+			Data: []byte("<Invoice/>"),
+		},
+		// A file specification without an embedded file
+		5: core.Dict{"Type": core.Name("Filespec"), "F": core.String("notes.txt")},
+		6: core.Dict{"Type": core.Name("Filespec"), "F": core.String("photo.PNG"), "EF": core.Dict{"F": core.IndirectRef{Number: 7}}},
+		7: &core.Stream{Dict: core.Dict{}, Data: []byte("png")},
+	}
+
+	catalog := core.Dict{
+		"Names": core.Dict{"EmbeddedFiles": core.IndirectRef{Number: 1}},
+		// The invoice is also an associated file, and must be returned once
+		"AF": core.Array{core.IndirectRef{Number: 3}},
+	}
+	pageAnnots := []core.Array{
+		nil,
+		{
+			core.Dict{"Subtype": core.Name("Link")},
+			core.Dict{"Subtype": core.Name("FileAttachment"), "FS": core.IndirectRef{Number: 6}},
+		},
+	}
+
+	files := Parse(catalog, pageAnnots, testResolver(objects))
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(files))
+	}
+
+	invoice := files[0]
+	if invoice.Name != "factur-x.xml" || invoice.Description != "Invoice data" || invoice.Relationship != "Alternative" {
+		t.Errorf("Expected the factur-x.xml invoice data, got %+v", invoice)
+	}
+	if invoice.MIMEType != "text/xml" || string(invoice.Data) != "<Invoice/>" || invoice.Page != -1 {
+		t.Errorf("Expected document-level text/xml contents, got %+v", invoice)
+	}
+	if !invoice.ModDate.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Expected modification date 2024-01-02, got %v", invoice.ModDate)
+	}
+
+	photo := files[1]
+	if photo.Name != "photo.PNG" || photo.MIMEType != "image/png" || photo.Page != 1 {
+		t.Errorf("Expected photo.PNG on page 1 with a guessed MIME type, got %+v", photo)
+	}
+}
+
+func TestParseNone(t *testing.T) {
+	if files := Parse(core.Dict{"Type": core.Name("Catalog")}, nil, nil); len(files) != 0 {
+		t.Errorf("Expected no files, got %v", files)
+	}
+}
+
+func TestMIMEType(t *testing.T) {
+	tests := map[string]string{
+		"report.docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		"data.XML":    "application/xml",
+		"archive.7z":  "application/octet-stream",
+		"README":      "application/octet-stream",
+	}
+	for name, want := range tests {
+		if got := MIMEType(name); got != want {
+			t.Errorf("MIMEType(%q): Expected %q, got %q", name, want, got)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Package attachments reads the files embedded in PDF documents.
+//
+// A PDF embeds a file in a file specification dictionary (PDF 32000-1:2008,
+// 7.11) whose /EF entry holds the file's contents as a stream. Files are
+// attached to the document as a whole through the catalog's /Names
+// /EmbeddedFiles name tree, which is also where a PDF portfolio keeps its
+// files, and through the catalog's /AF array of associated files, used by
+// PDF/A-3 and by e-invoices such as ZUGFeRD and Factur-X for their XML
+// data. A file can also be attached to a page by a FileAttachment
+// annotation.
+//
+// # Parsing
+//
+// [Parse] reads the embedded files from the document catalog and the pages'
+// annotations:
+//
+//	files := attachments.Parse(catalog, pageAnnots, resolver)
+//	for _, f := range files {
+//	    fmt.Printf("%s (%s, %d bytes)\n", f.Name, f.MIMEType, len(f.Data))
+//	}
+//
+// Each [File] carries its name, description, MIME type, decoded contents,
+// dates and, for associated files, its relationship to the document. The
+// MIME type comes from the embedded stream's /Subtype, or is guessed from
+// the file name by [MIMEType].
+package attachments
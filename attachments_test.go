@@ -0,0 +1,101 @@
+package tabula
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildAttachmentPDF assembles a one-page PDF with two document-level
+// attachments, a PDF and an XML file
+func buildAttachmentPDF() []byte {
+	inner := buildTextPDF("BT /F1 12 Tf 72 720 Td (Text of the attached report) Tj ET")
+	xml := "<Invoice><ID>42</ID></Invoice>"
+	content := "BT /F1 12 Tf 72 720 Td (Cover letter) Tj ET"
+	return buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R /Names << /EmbeddedFiles << /Names [(report.pdf) 6 0 R (invoice.xml) 8 0 R] >> >> >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 3 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Filespec /F (report.pdf) /Desc (Quarterly report) /EF << /F 7 0 R >> >>",
+		fmt.Sprintf("<< /Type /EmbeddedFile /Subtype /application#2Fpdf /Length %d >>\nstream\n%s\nendstream", len(inner), inner),
+		"<< /Type /Filespec /F (invoice.xml) /EF << /F 9 0 R >> >>",
+		fmt.Sprintf("<< /Type /EmbeddedFile /Length %d >>\nstream\n%s\nendstream", len(xml), xml),
+	)
+}
+
+func TestAttachments(t *testing.T) {
+	files, _, err := FromBytes(buildAttachmentPDF(), "").Attachments()
+	if err != nil {
+		t.Fatalf("Attachments() error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("got %d attachments, want 2", len(files))
+	}
+	report := files[0]
+	if report.Name != "report.pdf" || report.MIMEType != "application/pdf" || report.Description != "Quarterly report" {
+		t.Errorf("attachment 0 = %+v, want report.pdf", report)
+	}
+	if !strings.HasPrefix(string(report.Data), "%PDF-") || report.Page != 0 {
+		t.Errorf("attachment 0 data = %.10q, page %d, want a document-level PDF", report.Data, report.Page)
+	}
+	if invoice := files[1]; invoice.MIMEType != "application/xml" || string(invoice.Data) != "<Invoice><ID>42</ID></Invoice>" {
+		t.Errorf("attachment 1 = %+v, want invoice.xml", invoice)
+	}
+}
+
+func TestIncludeAttachmentsText(t *testing.T) {
+	plain, _, err := FromBytes(buildAttachmentPDF(), "").Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if strings.Contains(plain, "attached report") {
+		t.Errorf("got attachment text without IncludeAttachments():\n%s", plain)
+	}
+
+	text, warnings, err := FromBytes(buildAttachmentPDF(), "").IncludeAttachments().Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	for _, want := range []string{"Cover letter", "[Attachment: report.pdf]", "Text of the attached report"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text is missing %q:\n%s", want, text)
+		}
+	}
+
+	var skipped bool
+	for _, w := range warnings {
+		if w.Code == WarningAttachment && strings.Contains(w.Message, "invoice.xml") {
+			skipped = true
+		}
+	}
+	if !skipped {
+		t.Errorf("warnings = %v, want invoice.xml reported as unsupported", warnings)
+	}
+}
+
+func TestIncludeAttachmentsChunks(t *testing.T) {
+	chunks, _, err := FromBytes(buildAttachmentPDF(), "").IncludeAttachments().Chunks()
+	if err != nil {
+		t.Fatalf("Chunks() error: %v", err)
+	}
+
+	var found bool
+	for i, c := range chunks.Chunks {
+		if c.Metadata.ChunkIndex != i || c.Metadata.TotalChunks != len(chunks.Chunks) {
+			t.Errorf("chunk %d index = %d of %d, want contiguous", i, c.Metadata.ChunkIndex, c.Metadata.TotalChunks)
+		}
+		if strings.Contains(c.Text, "attached report") {
+			found = true
+			if c.Metadata.Attachment != "report.pdf" {
+				t.Errorf("attachment chunk tagged %q, want report.pdf", c.Metadata.Attachment)
+			}
+		} else if c.Metadata.Attachment != "" {
+			t.Errorf("document chunk tagged %q, want none", c.Metadata.Attachment)
+		}
+	}
+	if !found {
+		t.Error("got no chunk from the attached report")
+	}
+}
@@ -33,6 +33,14 @@
 //   - re - Rectangle
 //   - S, s, f, f* - Stroke and fill paths
 //
+// # Inline Images
+//
+// Inline images (BI ... ID ... EI) embed binary image data in the content
+// stream. The parser reads each one as a single "BI" operation whose operand
+// is a *core.Stream: the image dictionary, with abbreviated keys such as /W
+// and /BPC and names such as /Fl and /RGB expanded to their full forms, and
+// the raw image data, which Stream.Decode decodes.
+//
 // # Operand Types
 //
 // Operands can be any PDF object type:
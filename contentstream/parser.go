@@ -10,6 +10,10 @@ import (
 
 // Operation represents a single content stream operation consisting of an
 // operator and its operands. Operands are PDF objects that precede the operator.
+//
+// An inline image (BI ... ID ... EI) is returned as a single "BI" operation
+// whose only operand is a *core.Stream holding the image dictionary, with
+// abbreviated keys and names expanded, and the image data.
 type Operation struct {
 	Operator string        // The operator (e.g., "Tj", "Tm", "q")
 	Operands []core.Object // The operands
@@ -104,6 +108,16 @@ func (p *Parser) parseOperator() error {
 		return fmt.Errorf("empty operator at position %d", start)
 	}
 
+	if operator == "BI" {
+		image, err := p.parseInlineImage()
+		if err != nil {
+			return fmt.Errorf("inline image at position %d: %w", start, err)
+		}
+		p.ops = append(p.ops, Operation{Operator: operator, Operands: []core.Object{image}})
+		operandStack = nil
+		return nil
+	}
+
 	// Create operation with current operand stack
 	operation := Operation{
 		Operator: operator,
@@ -119,6 +133,155 @@ func (p *Parser) parseOperator() error {
 	return nil
 }
 
+// inlineImageKeys maps the abbreviated keys of inline image dictionaries to
+// the full names used by image XObjects (PDF 32000-1:2008, Table 93)
+var inlineImageKeys = map[string]string{
+	"BPC": "BitsPerComponent",
+	"CS":  "ColorSpace",
+	"D":   "Decode",
+	"DP":  "DecodeParms",
+	"F":   "Filter",
+	"H":   "Height",
+	"IM":  "ImageMask",
+	"I":   "Interpolate",
+	"L":   "Length",
+	"W":   "Width",
+}
+
+// inlineImageNames maps the abbreviated filter and color space names of
+// inline images to their full names (PDF 32000-1:2008, Table 94)
+var inlineImageNames = map[string]string{
+	"AHx":  "ASCIIHexDecode",
+	"A85":  "ASCII85Decode",
+	"LZW":  "LZWDecode",
+	"Fl":   "FlateDecode",
+	"RL":   "RunLengthDecode",
+	"CCF":  "CCITTFaxDecode",
+	"DCT":  "DCTDecode",
+	"G":    "DeviceGray",
+	"RGB":  "DeviceRGB",
+	"CMYK": "DeviceCMYK",
+	"I":    "Indexed",
+}
+
+// parseInlineImage parses the rest of an inline image after its BI
+// operator: the image dictionary up to ID, then the image data up to EI.
+func (p *Parser) parseInlineImage() (*core.Stream, error) {
+	dict := make(core.Dict)
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil, fmt.Errorf("missing ID")
+		}
+		if p.data[p.pos] != '/' {
+			if p.pos+1 < len(p.data) && p.data[p.pos] == 'I' && p.data[p.pos+1] == 'D' {
+				p.pos += 2
+				break
+			}
+			return nil, fmt.Errorf("unexpected character %c in image dictionary", p.data[p.pos])
+		}
+
+		key, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		name := string(key.(core.Name))
+		if full, ok := inlineImageKeys[name]; ok {
+			name = full
+		}
+		if name == "Filter" || name == "ColorSpace" {
+			value = expandInlineImageNames(value)
+		}
+		dict[name] = value
+	}
+
+	// A single white-space character separates ID from the data
+	if p.pos < len(p.data) && isWhitespace(p.data[p.pos]) {
+		p.pos++
+	}
+	start := p.pos
+
+	// PDF 2.0 gives the data length; older files are scanned for EI
+	searchFrom := start
+	if length, ok := dict.Get("Length").(core.Int); ok && length >= 0 && start+int(length) <= len(p.data) {
+		searchFrom = start + int(length)
+	}
+	end := p.findEI(searchFrom)
+	if end < 0 {
+		return nil, fmt.Errorf("missing EI")
+	}
+	p.pos = end + 2
+
+	dataEnd := end
+	if searchFrom > start {
+		dataEnd = searchFrom
+	} else if dataEnd > start && isWhitespace(p.data[dataEnd-1]) {
+		dataEnd-- // The white space before EI is not part of the data
+	}
+
+	return &core.Stream{Dict: dict, Data: p.data[start:dataEnd]}, nil
+}
+
+// findEI returns the position of the EI operator ending inline image data,
+// searching from pos, or -1 if there is none. Binary image data may contain
+// the bytes "EI" too, so the operator must be delimited by white space and
+// followed by what looks like content stream text.
+func (p *Parser) findEI(pos int) int {
+	for i := pos; i+1 < len(p.data); i++ {
+		if p.data[i] != 'E' || p.data[i+1] != 'I' {
+			continue
+		}
+		if i > pos && !isWhitespace(p.data[i-1]) {
+			continue
+		}
+		if i+2 < len(p.data) && !isWhitespace(p.data[i+2]) {
+			continue
+		}
+		if isContentText(p.data[i+2:]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isContentText reports whether the start of data is printable ASCII, as
+// the operators that follow inline images are
+func isContentText(data []byte) bool {
+	const n = 32
+	if len(data) > n {
+		data = data[:n]
+	}
+	for _, c := range data {
+		if !isWhitespace(c) && (c < 0x20 || c > 0x7e) {
+			return false
+		}
+	}
+	return true
+}
+
+// expandInlineImageNames expands abbreviated names in an inline image's
+// filter or color space, which may be a name or an array
+func expandInlineImageNames(obj core.Object) core.Object {
+	switch v := obj.(type) {
+	case core.Name:
+		if full, ok := inlineImageNames[string(v)]; ok {
+			return core.Name(full)
+		}
+	case core.Array:
+		expanded := make(core.Array, len(v))
+		for i, elem := range v {
+			expanded[i] = expandInlineImageNames(elem)
+		}
+		return expanded
+	}
+	return obj
+}
+
 // parseOperand parses a single operand, which can be a number, string, name,
 // array, dictionary, boolean, or null.
 func (p *Parser) parseOperand() (core.Object, error) {
@@ -939,6 +939,69 @@ EI`)
 	}
 }
 
+// TestParseInlineImageData tests that inline image data is read as a stream
+// with expanded keys, and that binary data does not disturb later operators
+func TestParseInlineImageData(t *testing.T) {
+	data := []byte{0x00, 'E', 'I', 0xff, ' ', 'E', 'I', 0x80, 0x01}
+	input := append([]byte("q 10 0 0 10 72 700 cm BI /W 3 /H 3 /BPC 8 /CS /G /F [/AHx /Fl] ID "), data...)
+	input = append(input, []byte("\nEI Q BT (after) Tj ET")...)
+
+	ops, err := NewParser(input).Parse()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var operators []string
+	for _, op := range ops {
+		operators = append(operators, op.Operator)
+	}
+	if got := strings.Join(operators, " "); got != "q cm BI Q BT Tj ET" {
+		t.Fatalf("expected operators 'q cm BI Q BT Tj ET', got %q", got)
+	}
+
+	stream, ok := ops[2].Operands[0].(*core.Stream)
+	if !ok {
+		t.Fatalf("expected *core.Stream operand, got %T", ops[2].Operands[0])
+	}
+	if string(stream.Data) != string(data) {
+		t.Errorf("expected data %v, got %v", data, stream.Data)
+	}
+	if stream.Dict.Get("Width") != core.Int(3) || stream.Dict.Get("BitsPerComponent") != core.Int(8) {
+		t.Errorf("expected expanded keys, got %v", stream.Dict)
+	}
+	if stream.Dict.Get("ColorSpace") != core.Name("DeviceGray") {
+		t.Errorf("expected DeviceGray, got %v", stream.Dict.Get("ColorSpace"))
+	}
+	filters, _ := stream.Dict.Get("Filter").(core.Array)
+	if len(filters) != 2 || filters[0] != core.Name("ASCIIHexDecode") || filters[1] != core.Name("FlateDecode") {
+		t.Errorf("expected expanded filters, got %v", stream.Dict.Get("Filter"))
+	}
+}
+
+// TestParseInlineImageLength tests inline image data delimited by /L
+func TestParseInlineImageLength(t *testing.T) {
+	input := []byte("BI /W 2 /H 1 /L 4 ID \nEI\n EI Q")
+
+	ops, err := NewParser(input).Parse()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(ops) != 2 || ops[1].Operator != "Q" {
+		t.Fatalf("expected BI and Q operations, got %v", ops)
+	}
+	stream := ops[0].Operands[0].(*core.Stream)
+	if string(stream.Data) != "\nEI\n" {
+		t.Errorf("expected data %q, got %q", "\nEI\n", stream.Data)
+	}
+}
+
+// TestParseInlineImageMissingEI tests that an unterminated inline image is an error
+func TestParseInlineImageMissingEI(t *testing.T) {
+	if _, err := NewParser([]byte("BI /W 1 /H 1 ID \x00\x01")).Parse(); err == nil {
+		t.Error("expected error for inline image without EI")
+	}
+}
+
 // TestParseWithComments tests parsing with PDF comments
 func TestParseWithComments(t *testing.T) {
 	// Note: This parser may not support comments in content streams
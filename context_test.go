@@ -0,0 +1,109 @@
+package tabula
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextContextCanceled(t *testing.T) {
+	data := buildTextPDF("BT /F1 12 Tf 72 720 Td (Hello) Tj ET")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := FromBytes(data, "").TextContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("TextContext() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestDocumentContextCanceled(t *testing.T) {
+	data := buildTextPDF("BT /F1 12 Tf 72 720 Td (Hello) Tj ET")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	doc, _, err := FromBytes(data, "").DocumentContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("DocumentContext() error = %v, want context.Canceled", err)
+	}
+	if doc != nil {
+		t.Errorf("DocumentContext() returned a document after cancellation")
+	}
+}
+
+func TestChunksContextDeadlineExceeded(t *testing.T) {
+	data := buildTextPDF("BT /F1 12 Tf 72 720 Td (Hello) Tj ET")
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	_, _, err := FromBytes(data, "").ExcludeHeadersAndFooters().ChunksContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ChunksContext() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestWithContextLive(t *testing.T) {
+	data := buildTextPDF("BT /F1 12 Tf 72 720 Td (Hello context) Tj ET")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	text, _, err := FromBytes(data, "").ExcludeHeadersAndFooters().WithContext(ctx).Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if !strings.Contains(text, "Hello context") {
+		t.Errorf("Text() = %q, want it to contain %q", text, "Hello context")
+	}
+}
+
+func TestWithContextImmutable(t *testing.T) {
+	data := buildTextPDF("BT /F1 12 Tf 72 720 Td (Hello) Tj ET")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	base := FromBytes(data, "")
+	_ = base.WithContext(ctx)
+
+	if base.ctx != nil {
+		t.Fatalf("WithContext modified the original extractor")
+	}
+	if _, _, err := base.Text(); err != nil {
+		t.Errorf("Text() on original extractor error: %v", err)
+	}
+}
+
+func TestTextContextCanceledDOCX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.docx")
+	if err := createMinimalDOCX(path, "Hello"); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := Open(path).TextContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("TextContext() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRunOCRJobsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	e := Open("scan.pdf").WithContext(ctx)
+	jobs := []ocrJob{{index: 0, images: []preparedImage{{png: []byte("not a png")}}}}
+
+	// Must return promptly without dispatching work, with or without -tags ocr.
+	if results := e.runOCRJobs(jobs); results[0].text != "" {
+		t.Errorf("runOCRJobs() = %q for job 0, want no text", results[0].text)
+	}
+}
@@ -12,9 +12,25 @@ import (
 	"fmt"
 )
 
-// ErrEncryptedNeedsPassword is returned when a PDF is encrypted and neither the
-// empty user password nor the empty owner password unlocks it.
-var ErrEncryptedNeedsPassword = errors.New("pdf is encrypted and requires a password")
+var (
+	// ErrPasswordRequired is returned when a PDF is encrypted, no password was
+	// supplied, and neither the empty user password nor the empty owner
+	// password unlocks it.
+	ErrPasswordRequired = errors.New("pdf is encrypted and requires a password")
+
+	// ErrWrongPassword is returned when a password was supplied but it is
+	// neither the user nor the owner password of the document.
+	ErrWrongPassword = errors.New("pdf password is incorrect")
+)
+
+// ErrEncryptedNeedsPassword is the former name of ErrPasswordRequired.
+//
+// Deprecated: use ErrPasswordRequired.
+var ErrEncryptedNeedsPassword = ErrPasswordRequired
+
+// maxPasswordLenV5 is the UTF-8 byte limit for R5/R6 passwords
+// (ISO 32000-2, 7.6.4.3.3).
+const maxPasswordLenV5 = 127
 
 // passwordPad is the 32-byte padding string from the PDF standard security
 // handler (ISO 32000-1, 7.6.3.3).
@@ -33,7 +49,8 @@ const (
 )
 
 // StdSecurityHandler decrypts strings and streams in a PDF that uses the
-// standard security handler. Only the empty user/owner password is supported.
+// standard security handler. The file key is derived from a user or owner
+// password (empty unless one is supplied).
 type StdSecurityHandler struct {
 	key             []byte // file encryption key
 	stmMethod       cryptMethod
@@ -48,6 +65,18 @@ func (h *StdSecurityHandler) EncryptMetadata() bool { return h.encryptMetadata }
 // first element of the document /ID. It derives the file key for the empty
 // password, falling back from user to owner.
 func NewStdSecurityHandler(enc Dict, id []byte) (*StdSecurityHandler, error) {
+	return NewStdSecurityHandlerWithPassword(enc, id, "")
+}
+
+// NewStdSecurityHandlerWithPassword is like NewStdSecurityHandler but derives
+// the file key from password, trying it first as the user password and then
+// as the owner password. If password does not authenticate, the empty
+// password is still tried so that documents with only an owner password set
+// open regardless of what the caller supplied.
+//
+// It returns ErrPasswordRequired when password is empty and the document
+// needs one, and ErrWrongPassword when a non-empty password is rejected.
+func NewStdSecurityHandlerWithPassword(enc Dict, id []byte, password string) (*StdSecurityHandler, error) {
 	if name, _ := enc.Get("Filter").(Name); string(name) != "Standard" {
 		return nil, fmt.Errorf("unsupported security handler: %v", enc.Get("Filter"))
 	}
@@ -76,27 +105,64 @@ func NewStdSecurityHandler(enc Dict, id []byte) (*StdSecurityHandler, error) {
 	}
 
 	// Derive the file key.
+	pw := passwordBytes(password, r)
+	var derive func(pw []byte) ([]byte, bool)
 	if v == 5 {
-		key, err := computeKeyV5(o, u, stringBytes(enc, "OE"), stringBytes(enc, "UE"))
-		if err != nil {
-			return nil, err
+		oe, ue := stringBytes(enc, "OE"), stringBytes(enc, "UE")
+		derive = func(pw []byte) ([]byte, bool) {
+			return computeKeyV5(pw, o, u, oe, ue, r)
+		}
+	} else {
+		keyLen := length / 8
+		if v <= 1 {
+			keyLen = 5
+		}
+		derive = func(pw []byte) ([]byte, bool) {
+			return computeKeyPassword(pw, o, p, id, r, keyLen, encMeta, u)
 		}
-		h.key = key
-		return h, nil
 	}
 
-	keyLen := length / 8
-	if v <= 1 {
-		keyLen = 5
+	key, ok := derive(pw)
+	if !ok && len(pw) > 0 {
+		key, ok = derive(nil)
 	}
-	key, ok := computeKeyEmptyPassword(o, p, id, r, keyLen, encMeta, u)
 	if !ok {
-		return nil, ErrEncryptedNeedsPassword
+		if password == "" {
+			return nil, ErrPasswordRequired
+		}
+		return nil, ErrWrongPassword
 	}
 	h.key = key
 	return h, nil
 }
 
+// passwordBytes encodes a caller-supplied password for revision r. R2–R4
+// passwords are PDFDocEncoding, which agrees with Latin-1 for printable
+// characters, so runes below 256 become single bytes. R5/R6 passwords are
+// UTF-8 limited to 127 bytes.
+func passwordBytes(password string, r int) []byte {
+	if password == "" {
+		return nil
+	}
+	if r >= 5 {
+		b := []byte(password)
+		if len(b) > maxPasswordLenV5 {
+			b = b[:maxPasswordLenV5]
+		}
+		return b
+	}
+	b := make([]byte, 0, len(password))
+	for _, c := range password {
+		if c > 0xff {
+			// Not representable in PDFDocEncoding; fall back to the raw
+			// UTF-8 bytes, which is what many producers wrote anyway.
+			return []byte(password)
+		}
+		b = append(b, byte(c))
+	}
+	return b
+}
+
 // Decrypt decrypts a string or stream body belonging to object (num, gen).
 func (h *StdSecurityHandler) Decrypt(data []byte, num, gen int, isString bool) ([]byte, error) {
 	method := h.stmMethod
@@ -137,16 +203,16 @@ func (h *StdSecurityHandler) objectKey(num, gen int, aesV2 bool) []byte {
 
 // --- key derivation: RC4 / AESV2 (R2–R4) ---
 
-// computeKeyEmptyPassword derives the file key for the empty password, trying the
-// user password first and the owner password as a fallback.
-func computeKeyEmptyPassword(o []byte, p int32, id []byte, r, keyLen int, encMeta bool, u []byte) ([]byte, bool) {
-	// Empty user password.
-	key := fileKeyRC4(nil, o, p, id, r, keyLen, encMeta)
+// computeKeyPassword derives the file key for pw (nil for the empty password),
+// trying it as the user password first and as the owner password as a fallback.
+func computeKeyPassword(pw, o []byte, p int32, id []byte, r, keyLen int, encMeta bool, u []byte) ([]byte, bool) {
+	// As user password.
+	key := fileKeyRC4(pw, o, p, id, r, keyLen, encMeta)
 	if validateUserPassword(key, u, id, r) {
 		return key, true
 	}
-	// Empty owner password: recover the user password from /O, then re-derive.
-	if userPw, ok := userPasswordFromOwner(nil, o, r, keyLen); ok {
+	// As owner password: recover the user password from /O, then re-derive.
+	if userPw, ok := userPasswordFromOwner(pw, o, r, keyLen); ok {
 		key = fileKeyRC4(userPw, o, p, id, r, keyLen, encMeta)
 		if validateUserPassword(key, u, id, r) {
 			return key, true
@@ -205,8 +271,8 @@ func validateUserPassword(key, u, id []byte, r int) bool {
 }
 
 // userPasswordFromOwner implements Algorithm 7 (authenticate owner password):
-// derive the RC4 owner key from the (empty) owner password and decrypt /O to
-// recover the user password.
+// derive the RC4 owner key from the owner password and decrypt /O to recover
+// the user password.
 func userPasswordFromOwner(ownerPw, o []byte, r, keyLen int) ([]byte, bool) {
 	if len(o) < 32 {
 		return nil, false
@@ -236,32 +302,45 @@ func userPasswordFromOwner(ownerPw, o []byte, r, keyLen int) ([]byte, bool) {
 	return val, true
 }
 
-// --- key derivation: AESV3 (R6 / V5) ---
+// --- key derivation: AESV3 (R5/R6 / V5) ---
 
-// computeKeyV5 derives the 32-byte AES-256 file key for the empty password
-// (ISO 32000-2, Algorithm 2.A), trying the user then the owner path.
-func computeKeyV5(o, u, oe, ue []byte) ([]byte, error) {
+// computeKeyV5 derives the 32-byte AES-256 file key for pw (ISO 32000-2,
+// Algorithm 2.A), trying the user then the owner path.
+func computeKeyV5(pw, o, u, oe, ue []byte, r int) ([]byte, bool) {
 	if len(u) >= 48 && len(ue) >= 32 {
 		valSalt, keySalt := u[32:40], u[40:48]
-		if bytes.Equal(hash2B(nil, valSalt, nil), u[:32]) {
-			ik := hash2B(nil, keySalt, nil)
+		if bytes.Equal(hashV5(r, pw, valSalt, nil), u[:32]) {
+			ik := hashV5(r, pw, keySalt, nil)
 			key, err := aesCBCNoPad(ik, make([]byte, 16), ue[:32])
 			if err == nil {
-				return key, nil
+				return key, true
 			}
 		}
 	}
 	if len(o) >= 48 && len(oe) >= 32 && len(u) >= 48 {
 		valSalt, keySalt := o[32:40], o[40:48]
-		if bytes.Equal(hash2B(nil, valSalt, u[:48]), o[:32]) {
-			ik := hash2B(nil, keySalt, u[:48])
+		if bytes.Equal(hashV5(r, pw, valSalt, u[:48]), o[:32]) {
+			ik := hashV5(r, pw, keySalt, u[:48])
 			key, err := aesCBCNoPad(ik, make([]byte, 16), oe[:32])
 			if err == nil {
-				return key, nil
+				return key, true
 			}
 		}
 	}
-	return nil, ErrEncryptedNeedsPassword
+	return nil, false
+}
+
+// hashV5 computes the password hash for revision r: a single SHA-256 for the
+// deprecated R5 (Adobe extension level 3) and Algorithm 2.B for R6.
+func hashV5(r int, pw, salt, udata []byte) []byte {
+	if r == 5 {
+		h := sha256.New()
+		h.Write(pw)
+		h.Write(salt)
+		h.Write(udata)
+		return h.Sum(nil)
+	}
+	return hash2B(pw, salt, udata)
 }
 
 // hash2B implements the R6 hashing algorithm (ISO 32000-2, Algorithm 2.B).
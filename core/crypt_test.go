@@ -0,0 +1,198 @@
+package core
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"testing"
+)
+
+// testDocID is the first /ID element used by the synthetic /Encrypt dicts.
+var testDocID = []byte("0123456789abcdef")
+
+// encryptDictRC4 builds an /Encrypt dictionary for revision r (2–4) with the
+// given passwords by running Algorithms 3, 4 and 5 of ISO 32000-1.
+func encryptDictRC4(t *testing.T, userPw, ownerPw string, r, keyLen int, cfm string) Dict {
+	t.Helper()
+	p := int32(-4)
+
+	// Algorithm 3: /O from the owner (or user) password.
+	owner := []byte(ownerPw)
+	if len(owner) == 0 {
+		owner = []byte(userPw)
+	}
+	sum := md5.Sum(padPassword(owner))
+	ok := sum[:]
+	if r >= 3 {
+		for i := 0; i < 50; i++ {
+			s := md5.Sum(ok[:keyLen])
+			ok = s[:]
+		}
+	}
+	ok = ok[:keyLen]
+	o := rc4Crypt(ok, padPassword([]byte(userPw)))
+	if r >= 3 {
+		for i := 1; i <= 19; i++ {
+			o = rc4Crypt(xorKey(ok, byte(i)), o)
+		}
+	}
+
+	// Algorithms 4/5: /U from the file key.
+	key := fileKeyRC4([]byte(userPw), o, p, testDocID, r, keyLen, true)
+	var u []byte
+	if r == 2 {
+		u = rc4Crypt(key, padPassword(nil))
+	} else {
+		m := md5.New()
+		m.Write(passwordPad)
+		m.Write(testDocID)
+		u = rc4Crypt(key, m.Sum(nil))
+		for i := 1; i <= 19; i++ {
+			u = rc4Crypt(xorKey(key, byte(i)), u)
+		}
+		u = append(u, make([]byte, 16)...)
+	}
+
+	enc := Dict{
+		"Filter": Name("Standard"),
+		"R":      Int(r),
+		"Length": Int(keyLen * 8),
+		"P":      Int(p),
+		"O":      String(o),
+		"U":      String(u),
+	}
+	switch {
+	case r == 2:
+		enc["V"] = Int(1)
+	case r == 3:
+		enc["V"] = Int(2)
+	default:
+		enc["V"] = Int(4)
+		enc["CF"] = Dict{"StdCF": Dict{"CFM": Name(cfm), "Length": Int(16)}}
+		enc["StmF"] = Name("StdCF")
+		enc["StrF"] = Name("StdCF")
+	}
+	return enc
+}
+
+// encryptDictV5 builds an AESV3 /Encrypt dictionary for revision r (5 or 6)
+// and returns it with the file key it protects.
+func encryptDictV5(t *testing.T, userPw, ownerPw string, r int) (Dict, []byte) {
+	t.Helper()
+	fileKey := bytes.Repeat([]byte{0x5a}, 32)
+	zeroIV := make([]byte, 16)
+
+	uSalts := []byte("uvalsaltukeysalt")
+	u := append(hashV5(r, []byte(userPw), uSalts[:8], nil), uSalts...)
+	ue, err := aesCBCEncryptNoPad(hashV5(r, []byte(userPw), uSalts[8:], nil), zeroIV, fileKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oSalts := []byte("ovalsaltokeysalt")
+	o := append(hashV5(r, []byte(ownerPw), oSalts[:8], u), oSalts...)
+	oe, err := aesCBCEncryptNoPad(hashV5(r, []byte(ownerPw), oSalts[8:], u), zeroIV, fileKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Dict{
+		"Filter": Name("Standard"),
+		"V":      Int(5),
+		"R":      Int(r),
+		"Length": Int(256),
+		"P":      Int(-4),
+		"O":      String(o),
+		"U":      String(u),
+		"OE":     String(oe),
+		"UE":     String(ue),
+	}, fileKey
+}
+
+// TestStdSecurityHandlerPasswords verifies user and owner password
+// authentication and the typed errors for every revision.
+func TestStdSecurityHandlerPasswords(t *testing.T) {
+	type dictFunc func(t *testing.T, user, owner string) Dict
+	rc4 := func(r, keyLen int, cfm string) dictFunc {
+		return func(t *testing.T, user, owner string) Dict {
+			return encryptDictRC4(t, user, owner, r, keyLen, cfm)
+		}
+	}
+	v5 := func(r int) dictFunc {
+		return func(t *testing.T, user, owner string) Dict {
+			d, _ := encryptDictV5(t, user, owner, r)
+			return d
+		}
+	}
+
+	methods := []struct {
+		name string
+		dict dictFunc
+	}{
+		{"R2 RC4-40", rc4(2, 5, "")},
+		{"R3 RC4-128", rc4(3, 16, "")},
+		{"R4 RC4-128", rc4(4, 16, "V2")},
+		{"R4 AESV2", rc4(4, 16, "AESV2")},
+		{"R5 AESV3", v5(5)},
+		{"R6 AESV3", v5(6)},
+	}
+
+	for _, m := range methods {
+		t.Run(m.name, func(t *testing.T) {
+			enc := m.dict(t, "user", "owner")
+
+			if _, err := NewStdSecurityHandlerWithPassword(enc, testDocID, "user"); err != nil {
+				t.Errorf("user password: %v", err)
+			}
+			if _, err := NewStdSecurityHandlerWithPassword(enc, testDocID, "owner"); err != nil {
+				t.Errorf("owner password: %v", err)
+			}
+			if _, err := NewStdSecurityHandlerWithPassword(enc, testDocID, "wrong"); !errors.Is(err, ErrWrongPassword) {
+				t.Errorf("wrong password: got %v, want ErrWrongPassword", err)
+			}
+			if _, err := NewStdSecurityHandler(enc, testDocID); !errors.Is(err, ErrPasswordRequired) {
+				t.Errorf("no password: got %v, want ErrPasswordRequired", err)
+			}
+
+			// Only an owner password set: any supplied password still opens it.
+			open := m.dict(t, "", "owner")
+			if _, err := NewStdSecurityHandler(open, testDocID); err != nil {
+				t.Errorf("empty user password: %v", err)
+			}
+			if _, err := NewStdSecurityHandlerWithPassword(open, testDocID, "unrelated"); err != nil {
+				t.Errorf("empty user password with unrelated password: %v", err)
+			}
+		})
+	}
+}
+
+// TestStdSecurityHandlerKeyMatches verifies the derived AESV3 key is the
+// one the /UE and /OE entries protect, from both password paths.
+func TestStdSecurityHandlerKeyMatches(t *testing.T) {
+	enc, fileKey := encryptDictV5(t, "käse", "owner", 6)
+	for _, pw := range []string{"käse", "owner"} {
+		h, err := NewStdSecurityHandlerWithPassword(enc, testDocID, pw)
+		if err != nil {
+			t.Fatalf("%q: %v", pw, err)
+		}
+		if !bytes.Equal(h.key, fileKey) {
+			t.Errorf("%q: derived key does not match file key", pw)
+		}
+	}
+}
+
+// TestPasswordBytes checks password encoding per revision.
+func TestPasswordBytes(t *testing.T) {
+	if got := passwordBytes("café", 4); !bytes.Equal(got, []byte{'c', 'a', 'f', 0xe9}) {
+		t.Errorf("R4 Latin-1: got %v", got)
+	}
+	if got := passwordBytes("café", 6); !bytes.Equal(got, []byte("café")) {
+		t.Errorf("R6 UTF-8: got %v", got)
+	}
+	if got := passwordBytes(string(bytes.Repeat([]byte("a"), 200)), 6); len(got) != maxPasswordLenV5 {
+		t.Errorf("R6 truncation: got %d bytes", len(got))
+	}
+	if got := passwordBytes("", 4); got != nil {
+		t.Errorf("empty password: got %v, want nil", got)
+	}
+}
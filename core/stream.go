@@ -7,8 +7,8 @@ import (
 )
 
 // Decode decodes the stream data according to the Filter(s) specified in the
-// stream dictionary. It supports FlateDecode, ASCIIHexDecode, ASCII85Decode,
-// and filter chains. Returns the decoded data or an error.
+// stream dictionary. It supports FlateDecode, LZWDecode, RunLengthDecode,
+// ASCIIHexDecode, ASCII85Decode, CCITTFaxDecode, and filter chains. Returns the decoded data or an error.
 func (s *Stream) Decode() ([]byte, error) {
 	// Check if there's a filter
 	filterObj := s.Dict.Get("Filter")
@@ -74,10 +74,10 @@ func decodeWithFilter(data []byte, filterName string, params Dict) ([]byte, erro
 		return filters.ASCII85Decode(data)
 
 	case "LZWDecode", "LZW":
-		return nil, fmt.Errorf("LZWDecode not yet implemented")
+		return filters.LZWDecode(data, dictToParams(params))
 
 	case "RunLengthDecode", "RL":
-		return nil, fmt.Errorf("RunLengthDecode not yet implemented")
+		return filters.RunLengthDecode(data)
 
 	case "CCITTFaxDecode", "CCF":
 		return filters.CCITTFaxDecode(data, dictToParams(params))
@@ -251,6 +251,47 @@ func TestStreamDecodeDCTDecode(t *testing.T) {
 	}
 }
 
+// TestStreamDecodeLZWDecode tests LZWDecode filter with abbreviation
+func TestStreamDecodeLZWDecode(t *testing.T) {
+	// "-----A---B" from the PDF specification's LZW example
+	stream := &Stream{
+		Dict: Dict{
+			"Filter":      Name("LZW"),
+			"DecodeParms": Dict{"EarlyChange": Int(1)},
+		},
+		Data: []byte{0x80, 0x0B, 0x60, 0x50, 0x22, 0x0C, 0x0C, 0x85, 0x01},
+	}
+
+	decoded, err := stream.Decode()
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if string(decoded) != "-----A---B" {
+		t.Errorf("got %q, want %q", decoded, "-----A---B")
+	}
+}
+
+// TestStreamDecodeRunLengthDecode tests RunLengthDecode in a filter chain
+func TestStreamDecodeRunLengthDecode(t *testing.T) {
+	// Hex-encoded run-length data: literal "BT", repeat ' ' 3 times, EOD
+	stream := &Stream{
+		Dict: Dict{
+			"Filter": Array{Name("ASCIIHexDecode"), Name("RunLengthDecode")},
+		},
+		Data: []byte("01 42 54 FE 20 80>"),
+	}
+
+	decoded, err := stream.Decode()
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if string(decoded) != "BT   " {
+		t.Errorf("got %q, want %q", decoded, "BT   ")
+	}
+}
+
 // TestStreamDecodeUnknownFilter tests error handling for unknown filter
 func TestStreamDecodeUnknownFilter(t *testing.T) {
 	stream := &Stream{
@@ -9,6 +9,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tsawler/tabula/model"
 	"github.com/tsawler/tabula/rag"
@@ -17,13 +18,15 @@ import (
 // Reader provides access to DOCX document content.
 type Reader struct {
 	file              *os.File
-	zipReader         *zip.ReadCloser
+	zipReader         *zip.Reader
+	closer            io.Closer // underlying archive when opened from a path
 	document          *documentXML
 	styles            *stylesXML
 	numbering         *numberingXML
 	rels              *relationshipsXML
 	coreProps         *corePropertiesXML
 	appProps          *appPropertiesXML
+	customProps       *customPropertiesXML
 	styleResolver     *StyleResolver
 	numberingResolver *NumberingResolver
 	tableParser       *TableParser
@@ -90,19 +93,41 @@ func Open(filename string) (*Reader, error) {
 		return nil, fmt.Errorf("opening ZIP archive: %w", err)
 	}
 
+	r, err := newReader(&zr.Reader)
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	r.closer = zr
+
+	return r, nil
+}
+
+// OpenReader opens a DOCX document from an io.ReaderAt of the given size,
+// such as a bytes.Reader over a file held in memory. The caller keeps
+// ownership of ra; Close does not close it.
+func OpenReader(ra io.ReaderAt, size int64) (*Reader, error) {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, fmt.Errorf("opening ZIP archive: %w", err)
+	}
+
+	return newReader(zr)
+}
+
+// newReader parses the document parts of an opened archive.
+func newReader(zr *zip.Reader) (*Reader, error) {
 	r := &Reader{
 		zipReader: zr,
 	}
 
 	// Validate required files exist
 	if err := r.validate(); err != nil {
-		zr.Close()
 		return nil, err
 	}
 
 	// Parse relationships first (needed for other parts)
 	if err := r.parseRelationships(); err != nil {
-		zr.Close()
 		return nil, fmt.Errorf("parsing relationships: %w", err)
 	}
 
@@ -119,7 +144,6 @@ func Open(filename string) (*Reader, error) {
 
 	// Parse document.xml (now that styleResolver and tableParser are ready)
 	if err := r.parseDocument(); err != nil {
-		zr.Close()
 		return nil, fmt.Errorf("parsing document: %w", err)
 	}
 
@@ -136,6 +160,7 @@ func Open(filename string) (*Reader, error) {
 	// Parse metadata (optional)
 	r.parseCoreProperties()
 	r.parseAppProperties()
+	r.parseCustomProperties()
 
 	// Parse headers and footers (optional)
 	r.parseHeadersAndFooters()
@@ -145,9 +170,9 @@ func Open(filename string) (*Reader, error) {
 
 // Close releases resources associated with the Reader.
 func (r *Reader) Close() error {
-	if r.zipReader != nil {
-		err := r.zipReader.Close()
-		r.zipReader = nil
+	if r.closer != nil {
+		err := r.closer.Close()
+		r.closer = nil
 		return err
 	}
 	return nil
@@ -731,20 +756,7 @@ func (r *Reader) Document() (*model.Document, error) {
 	doc := model.NewDocument()
 
 	// Set metadata
-	if r.coreProps != nil {
-		doc.Metadata.Title = r.coreProps.Title
-		doc.Metadata.Author = r.coreProps.Creator
-		doc.Metadata.Subject = r.coreProps.Subject
-		if r.coreProps.Keywords != "" {
-			doc.Metadata.Keywords = strings.Split(r.coreProps.Keywords, ",")
-			for i, kw := range doc.Metadata.Keywords {
-				doc.Metadata.Keywords[i] = strings.TrimSpace(kw)
-			}
-		}
-	}
-	if r.appProps != nil {
-		doc.Metadata.Creator = r.appProps.Application
-	}
+	doc.Metadata = r.Metadata()
 
 	// Create single page for entire document (DOCX doesn't have fixed pages)
 	page := model.NewPage(612, 792) // Standard US Letter dimensions
@@ -902,9 +914,10 @@ func (r *Reader) Document() (*model.Document, error) {
 	return doc, nil
 }
 
-// Metadata returns document metadata.
+// Metadata returns document metadata from the core, application and custom
+// document properties.
 func (r *Reader) Metadata() model.Metadata {
-	meta := model.Metadata{}
+	meta := model.Metadata{Custom: make(map[string]string)}
 	if r.coreProps != nil {
 		meta.Title = r.coreProps.Title
 		meta.Author = r.coreProps.Creator
@@ -915,10 +928,23 @@ func (r *Reader) Metadata() model.Metadata {
 				meta.Keywords[i] = strings.TrimSpace(kw)
 			}
 		}
+		meta.Language = r.coreProps.Language
+		if r.coreProps.Identifier != "" {
+			meta.Identifiers = []string{r.coreProps.Identifier}
+		}
+		meta.CreationDate, _ = time.Parse(time.RFC3339, r.coreProps.Created)
+		meta.ModDate, _ = time.Parse(time.RFC3339, r.coreProps.Modified)
 	}
 	if r.appProps != nil {
 		meta.Creator = r.appProps.Application
 	}
+	if r.customProps != nil {
+		for _, p := range r.customProps.Properties {
+			if p.Name != "" {
+				meta.Custom[p.Name] = strings.TrimSpace(p.Value.Text)
+			}
+		}
+	}
 	return meta
 }
 
@@ -1081,6 +1107,17 @@ func (r *Reader) parseAppProperties() {
 	xml.Unmarshal(data, r.appProps)
 }
 
+// parseCustomProperties parses custom document properties.
+func (r *Reader) parseCustomProperties() {
+	data, err := r.getFileContent("docProps/custom.xml")
+	if err != nil {
+		return
+	}
+
+	r.customProps = &customPropertiesXML{}
+	xml.Unmarshal(data, r.customProps)
+}
+
 // parseHeadersAndFooters parses header and footer files linked via relationships.
 // In DOCX, headers/footers are stored in separate files (word/header1.xml, word/footer1.xml, etc.)
 // and linked via relationships in word/_rels/document.xml.rels.
@@ -2,6 +2,7 @@ package docx
 
 import (
 	"archive/zip"
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
@@ -128,6 +129,35 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func TestOpenReader(t *testing.T) {
+	content := `<w:p><w:r><w:t>Hello from memory</w:t></w:r></w:p>`
+	data, err := os.ReadFile(createTestDOCX(t, content))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	r, err := OpenReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer r.Close()
+
+	text, err := r.Text()
+	if err != nil {
+		t.Fatalf("Text() error = %v", err)
+	}
+	if !strings.Contains(text, "Hello from memory") {
+		t.Errorf("Text() = %q, want it to contain %q", text, "Hello from memory")
+	}
+}
+
+func TestOpenReader_InvalidZip(t *testing.T) {
+	data := []byte("not a zip file")
+	if _, err := OpenReader(bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Error("OpenReader() should return error for invalid ZIP")
+	}
+}
+
 func TestOpen_NotFound(t *testing.T) {
 	_, err := Open("/nonexistent/file.docx")
 	if err == nil {
@@ -1111,3 +1141,61 @@ func BenchmarkMarkdown(b *testing.B) {
 		}
 	}
 }
+
+func TestMetadataProperties(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "props.docx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	files := map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+  <Default Extension="xml" ContentType="application/xml"/>
+</Types>`,
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:body><w:p><w:r><w:t>Hello</w:t></w:r></w:p></w:body>
+</w:document>`,
+		"docProps/core.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"
+    xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/">
+  <dc:title>Quarterly Report</dc:title>
+  <dc:language>en-GB</dc:language>
+  <dc:identifier>urn:isbn:9780000000000</dc:identifier>
+  <dcterms:created>2023-04-15T10:30:00Z</dcterms:created>
+</cp:coreProperties>`,
+		"docProps/custom.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties"
+    xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes">
+  <property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="2" name="Department"><vt:lpwstr>Research</vt:lpwstr></property>
+</Properties>`,
+	}
+	for name, content := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	f.Close()
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer r.Close()
+
+	meta := r.Metadata()
+	if meta.Title != "Quarterly Report" || meta.Language != "en-GB" {
+		t.Errorf("Metadata() title, language = %q, %q", meta.Title, meta.Language)
+	}
+	if len(meta.Identifiers) != 1 || meta.Identifiers[0] != "urn:isbn:9780000000000" {
+		t.Errorf("Metadata() identifiers = %v", meta.Identifiers)
+	}
+	if meta.CreationDate.Year() != 2023 {
+		t.Errorf("Metadata() creation date = %v, want 2023", meta.CreationDate)
+	}
+	if meta.Custom["Department"] != "Research" {
+		t.Errorf("Metadata() custom = %v, want Department = Research", meta.Custom)
+	}
+}
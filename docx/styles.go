@@ -148,6 +148,23 @@ type corePropertiesXML struct {
 	Created        string   `xml:"created"`
 	Modified       string   `xml:"modified"`
 	Category       string   `xml:"category"`
+	Language       string   `xml:"language"`
+	Identifier     string   `xml:"identifier"`
+}
+
+// customPropertiesXML represents docProps/custom.xml.
+type customPropertiesXML struct {
+	XMLName    xml.Name            `xml:"Properties"`
+	Properties []customPropertyXML `xml:"property"`
+}
+
+// customPropertyXML is a custom document property. Its value is held in a
+// single typed child element such as vt:lpwstr, vt:i4 or vt:bool.
+type customPropertyXML struct {
+	Name  string `xml:"name,attr"`
+	Value struct {
+		Text string `xml:",chardata"`
+	} `xml:",any"`
 }
 
 // appPropertiesXML represents docProps/app.xml
@@ -146,9 +146,14 @@ func convertMetadata(m *opfMetadata) Metadata {
 		meta.Language = strings.TrimSpace(m.Language[0].Content)
 	}
 
-	// Identifier - take first
-	if len(m.Identifier) > 0 {
-		meta.Identifier = strings.TrimSpace(m.Identifier[0].Content)
+	// Identifiers
+	for _, id := range m.Identifier {
+		if s := strings.TrimSpace(id.Content); s != "" {
+			meta.Identifiers = append(meta.Identifiers, s)
+		}
+	}
+	if len(meta.Identifiers) > 0 {
+		meta.Identifier = meta.Identifiers[0]
 	}
 
 	// Publisher - take first
@@ -178,18 +183,36 @@ func convertMetadata(m *opfMetadata) Metadata {
 		meta.Rights = strings.TrimSpace(m.Rights[0].Content)
 	}
 
-	// Check meta elements for modified date (EPUB 3)
+	// Check meta elements for modified date (EPUB 3); other properties of
+	// the publication, and EPUB 2 name/content pairs, are kept as custom
+	// metadata
 	for _, mt := range m.Meta {
-		if mt.Property == "dcterms:modified" {
+		switch {
+		case mt.Property == "dcterms:modified":
 			if t, err := time.Parse(time.RFC3339, mt.Value); err == nil {
 				meta.Modified = t
 			}
+		case mt.Property != "" && mt.Refines == "":
+			addCustomMeta(&meta, mt.Property, mt.Value)
+		case mt.Name != "" && mt.Name != "cover":
+			addCustomMeta(&meta, mt.Name, mt.Content)
 		}
 	}
 
 	return meta
 }
 
+// addCustomMeta records a custom metadata property, if it has a value
+func addCustomMeta(meta *Metadata, name, value string) {
+	if value = strings.TrimSpace(value); value == "" {
+		return
+	}
+	if meta.Custom == nil {
+		meta.Custom = make(map[string]string)
+	}
+	meta.Custom[name] = value
+}
+
 func convertManifest(m *opfManifest) map[string]ManifestItem {
 	manifest := make(map[string]ManifestItem, len(m.Items))
 
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/tsawler/tabula/htmldoc"
 	"github.com/tsawler/tabula/model"
@@ -233,6 +234,33 @@ func (r *Reader) Metadata() Metadata {
 	return r.pkg.Metadata
 }
 
+// modelMetadata converts the package metadata for the document model
+func (r *Reader) modelMetadata() model.Metadata {
+	m := r.pkg.Metadata
+	meta := model.Metadata{
+		Title:       m.Title,
+		Author:      strings.Join(m.Creator, ", "),
+		Subject:     strings.Join(m.Subjects, ", "),
+		Language:    m.Language,
+		Identifiers: m.Identifiers,
+		ModDate:     m.Modified,
+		Custom:      make(map[string]string, len(m.Custom)+1),
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, m.Date); err == nil {
+			meta.CreationDate = t
+			break
+		}
+	}
+	if m.Publisher != "" {
+		meta.Custom["publisher"] = m.Publisher
+	}
+	for k, v := range m.Custom {
+		meta.Custom[k] = v
+	}
+	return meta
+}
+
 // ChapterCount returns the number of chapters.
 func (r *Reader) ChapterCount() int {
 	return len(r.chapters)
@@ -308,12 +336,8 @@ func (r *Reader) MarkdownWithOptions(opts ExtractOptions) (string, error) {
 // Document returns the document model.
 func (r *Reader) Document() (*model.Document, error) {
 	doc := &model.Document{
-		Metadata: model.Metadata{
-			Title:   r.pkg.Metadata.Title,
-			Author:  strings.Join(r.pkg.Metadata.Creator, ", "),
-			Subject: strings.Join(r.pkg.Metadata.Subjects, ", "),
-		},
-		Pages: make([]*model.Page, 0, len(r.chapters)),
+		Metadata: r.modelMetadata(),
+		Pages:    make([]*model.Page, 0, len(r.chapters)),
 	}
 
 	for i, chapter := range r.chapters {
@@ -18,13 +18,15 @@ type Metadata struct {
 	Title       string
 	Creator     []string // Multiple authors possible
 	Language    string
-	Identifier  string // ISBN, UUID, etc.
+	Identifier  string   // ISBN, UUID, etc.
+	Identifiers []string // All identifiers, the first being Identifier
 	Publisher   string
 	Date        string
 	Description string
 	Subjects    []string
 	Rights      string
 	Modified    time.Time
+	Custom      map[string]string // Other <meta> properties, by name or property
 }
 
 // ManifestItem represents a file in the EPUB.
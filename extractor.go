@@ -1,7 +1,9 @@
 package tabula
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"sort"
@@ -9,7 +11,6 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/tsawler/tabula/core"
 	"github.com/tsawler/tabula/docx"
 	"github.com/tsawler/tabula/epubdoc"
 	"github.com/tsawler/tabula/format"
@@ -37,9 +38,15 @@ type extractedPage struct {
 // Each configuration method returns a new Extractor instance, making it
 // safe for concurrent use and allowing method chaining.
 type Extractor struct {
-	// Source
-	filename string
-	format   format.Format
+	// Source (a filename, or an in-memory io.ReaderAt)
+	filename   string
+	source     io.ReaderAt
+	sourceSize int64
+	format     format.Format
+
+	// formatResolved is true once format is settled, either set explicitly
+	// via Format or sniffed from the content, so sniffing runs at most once.
+	formatResolved bool
 
 	// Readers (only one will be used based on format)
 	reader     *reader.Reader  // PDF reader
@@ -57,47 +64,73 @@ type Extractor struct {
 	// Configuration
 	options ExtractOptions
 
+	// ctx bounds terminal operations; nil means context.Background().
+	ctx context.Context
+
 	// Accumulated error (fail-fast)
 	err error
 
 	// Warnings accumulated during processing
 	warnings []Warning
+
+	// formFragments holds the inlined form field values by page index,
+	// loaded by the first page that needs them (see InlineFormFields)
+	formFragments map[int][]text.TextFragment
+
+	// attachmentDepth is the nesting level of an extractor reading an
+	// attachment (see IncludeAttachments); 0 for the source document
+	attachmentDepth int
 }
 
 // clone creates a shallow copy of the Extractor with a deep copy of options.
 // This ensures immutability - each chain method returns a new instance.
 func (e *Extractor) clone() *Extractor {
 	newExt := &Extractor{
-		filename:     e.filename,
-		format:       e.format,
-		reader:       e.reader,
-		docxReader:   e.docxReader,
-		odtReader:    e.odtReader,
-		xlsxReader:   e.xlsxReader,
-		pptxReader:   e.pptxReader,
-		htmlReader:   e.htmlReader,
-		epubReader:   e.epubReader,
-		ownsReader:   e.ownsReader,
-		readerOpened: e.readerOpened,
-		options:      e.options.clone(),
-		err:          e.err,
-		warnings:     append([]Warning(nil), e.warnings...),
+		filename:       e.filename,
+		source:         e.source,
+		sourceSize:     e.sourceSize,
+		format:         e.format,
+		formatResolved: e.formatResolved,
+		reader:         e.reader,
+		docxReader:     e.docxReader,
+		odtReader:      e.odtReader,
+		xlsxReader:     e.xlsxReader,
+		pptxReader:     e.pptxReader,
+		htmlReader:     e.htmlReader,
+		epubReader:     e.epubReader,
+		ownsReader:     e.ownsReader,
+		readerOpened:   e.readerOpened,
+		options:        e.options.clone(),
+		ctx:            e.ctx,
+		err:            e.err,
+		warnings:       append([]Warning(nil), e.warnings...),
+
+		attachmentDepth: e.attachmentDepth,
 	}
 	return newExt
 }
 
-// ensureReader opens the reader if not already open.
+// ensureReader opens the reader if not already open. It fails with the
+// context's error when the extractor's context is already done.
 func (e *Extractor) ensureReader() error {
+	if err := e.ctxErr(); err != nil {
+		return err
+	}
 	if e.readerOpened {
 		return nil
 	}
+	if e.source != nil {
+		return e.openSource()
+	}
 	if e.filename == "" {
 		return fmt.Errorf("no filename specified")
 	}
 
-	// Validate that file content matches the expected format from extension
-	if err := e.validateFormat(); err != nil {
-		return err
+	// Sniff the content so misnamed files are read as what they are
+	if !e.formatResolved {
+		if err := e.resolveFormat(); err != nil {
+			return err
+		}
 	}
 
 	switch e.format {
@@ -162,7 +195,7 @@ func (e *Extractor) ensureReader() error {
 		return nil
 
 	case format.PDF:
-		r, err := reader.Open(e.filename)
+		r, err := reader.OpenWithPassword(e.filename, e.options.password)
 		if err != nil {
 			return fmt.Errorf("failed to open PDF: %w", err)
 		}
@@ -176,6 +209,68 @@ func (e *Extractor) ensureReader() error {
 	}
 }
 
+// openSource opens the reader for an Extractor created from an io.ReaderAt.
+// The source is owned by the caller, so closing the readers never closes it.
+func (e *Extractor) openSource() error {
+	switch e.format {
+	case format.DOCX:
+		dr, err := docx.OpenReader(e.source, e.sourceSize)
+		if err != nil {
+			return fmt.Errorf("failed to open DOCX: %w", err)
+		}
+		e.docxReader = dr
+
+	case format.ODT:
+		or, err := odt.OpenReader(e.source, e.sourceSize)
+		if err != nil {
+			return fmt.Errorf("failed to open ODT: %w", err)
+		}
+		e.odtReader = or
+
+	case format.XLSX:
+		xr, err := xlsx.OpenReader(e.source, e.sourceSize)
+		if err != nil {
+			return fmt.Errorf("failed to open XLSX: %w", err)
+		}
+		e.xlsxReader = xr
+
+	case format.PPTX:
+		pr, err := pptx.OpenReader(e.source, e.sourceSize)
+		if err != nil {
+			return fmt.Errorf("failed to open PPTX: %w", err)
+		}
+		e.pptxReader = pr
+
+	case format.HTML:
+		hr, err := htmldoc.OpenReader(io.NewSectionReader(e.source, 0, e.sourceSize))
+		if err != nil {
+			return fmt.Errorf("failed to open HTML: %w", err)
+		}
+		e.htmlReader = hr
+
+	case format.EPUB:
+		er, err := epubdoc.OpenReader(e.source, e.sourceSize)
+		if err != nil {
+			return fmt.Errorf("failed to open EPUB: %w", err)
+		}
+		e.epubReader = er
+
+	case format.PDF:
+		r, err := reader.NewReaderAtWithPassword(e.source, e.sourceSize, e.options.password)
+		if err != nil {
+			return fmt.Errorf("failed to open PDF: %w", err)
+		}
+		e.reader = r
+
+	default:
+		return fmt.Errorf("unsupported file format: %s", e.format)
+	}
+
+	e.ownsReader = true
+	e.readerOpened = true
+	return nil
+}
+
 // Close releases resources associated with the Extractor.
 // It is safe to call Close multiple times.
 func (e *Extractor) Close() error {
@@ -262,6 +357,24 @@ func (e *Extractor) PageRange(start, end int) *Extractor {
 	return newExt
 }
 
+// PagesByLabel specifies pages to extract by their printed labels, such as
+// "iv" for roman-numbered front matter or "A-3" for an appendix page, as
+// defined by the PDF's /PageLabels. In a document without page labels, the
+// labels are the decimal page numbers. Multiple calls, and calls to Pages
+// or PageRange, are cumulative. A label that no page carries makes the
+// terminal operation fail. A label that several pages carry, as when each
+// part of a book restarts its numbering, selects the first of them; use
+// Pages for the others.
+//
+// Example:
+//
+//	text, _, err := tabula.Open("book.pdf").PagesByLabel("iv", "A-3").Text()
+func (e *Extractor) PagesByLabel(labels ...string) *Extractor {
+	newExt := e.clone()
+	newExt.options.pageLabels = append(newExt.options.pageLabels, labels...)
+	return newExt
+}
+
 // ExcludeHeaders configures the extractor to exclude detected headers.
 //
 // Example:
@@ -300,7 +413,8 @@ func (e *Extractor) ExcludeHeadersAndFooters() *Extractor {
 
 // OCRLanguage sets the Tesseract language(s) used when OCR'ing scanned pages,
 // e.g. "eng" or "eng+fra". The corresponding tessdata language packs must be
-// installed. Has effect only when built with -tags ocr.
+// installed. Has effect only when OCR is available (built with -tags ocr, or
+// an engine set with OCREngine, which receives it in ocr.Options).
 //
 // Example:
 //
@@ -312,7 +426,8 @@ func (e *Extractor) OCRLanguage(lang string) *Extractor {
 }
 
 // OCRPageSegMode sets the Tesseract page segmentation mode for OCR (see the
-// ocr.PSM_* constants). Has effect only when built with -tags ocr.
+// ocr.PSM_* constants). Has effect only when OCR is available (built with
+// -tags ocr, or an engine set with OCREngine).
 //
 // Example:
 //
@@ -325,6 +440,36 @@ func (e *Extractor) OCRPageSegMode(mode ocr.PageSegMode) *Extractor {
 	return newExt
 }
 
+// OCREngine sets the engine that recognizes text on scanned pages, in place
+// of the Tesseract library. Any ocr.Engine can be used: ocr.NewExecEngine
+// runs the tesseract program and needs neither CGO nor -tags ocr, and a
+// cloud OCR service can be wrapped the same way. The engine is called from
+// several goroutines at once.
+//
+// Example:
+//
+//	text, _, err := tabula.Open("scan.pdf").OCREngine(ocr.NewExecEngine("")).Text()
+func (e *Extractor) OCREngine(engine ocr.Engine) *Extractor {
+	newExt := e.clone()
+	newExt.options.ocrEngine = engine
+	return newExt
+}
+
+// OCRRenderer selects how scanned pages are rasterized for OCR. The default,
+// RendererBuiltin, renders pages in pure Go; RendererPdftoppm uses pdftoppm
+// from poppler-utils when it is installed, falling back to the built-in
+// renderer when it isn't, or when a Password is set. Has effect only when OCR
+// is available (built with -tags ocr, or an engine set with OCREngine).
+//
+// Example:
+//
+//	text, _, err := tabula.Open("scan.pdf").OCRRenderer(tabula.RendererPdftoppm).Text()
+func (e *Extractor) OCRRenderer(r OCRRenderer) *Extractor {
+	newExt := e.clone()
+	newExt.options.ocrRenderer = r
+	return newExt
+}
+
 // JoinParagraphs configures the extractor to join lines within paragraphs
 // using spaces instead of newlines. This produces cleaner text output where
 // paragraph breaks are preserved but soft line breaks within paragraphs are removed.
@@ -339,6 +484,86 @@ func (e *Extractor) JoinParagraphs() *Extractor {
 	return newExt
 }
 
+// IncludeComments adds the comment annotations on each PDF page (sticky
+// notes, free text, highlights and other text markup, and stamps) to
+// Document() pages as model.Comment elements, so that they reach text,
+// markdown and chunk output. Highlights carry the text they cover. Has no
+// effect for other formats.
+//
+// Example:
+//
+//	chunks, _, err := tabula.Open("reviewed.pdf").IncludeComments().Chunks()
+func (e *Extractor) IncludeComments() *Extractor {
+	newExt := e.clone()
+	newExt.options.includeComments = true
+	return newExt
+}
+
+// VisibleTextOnly controls whether PDF text that cannot be seen when the
+// page is displayed is extracted. It is on by default, dropping text
+// clipped away or positioned outside the page's crop box, white text with
+// nothing painted behind it, and text drawn in the invisible rendering mode
+// where visible text covers the same area. Invisible text with nothing
+// visible over it, such as the text layer OCR software puts over a scanned
+// page image, is kept. A WarningHiddenText warning reports each page where
+// text was dropped. Pass false to keep all text; Fragments() then reports
+// each fragment's Visible flag. Has no effect for other formats.
+//
+// Example:
+//
+//	fragments, _, err := tabula.Open("doc.pdf").VisibleTextOnly(false).Fragments()
+func (e *Extractor) VisibleTextOnly(enabled bool) *Extractor {
+	newExt := e.clone()
+	newExt.options.keepHiddenText = !enabled
+	return newExt
+}
+
+// InlineFormFields adds the values of interactive form fields to the text
+// of each PDF page at the positions of their widgets, so that filled-in
+// forms read as they appear on screen: text and choice fields show their
+// values and check boxes and radio buttons show "[X]" or "[ ]". Has no
+// effect for other formats.
+//
+// Example:
+//
+//	text, _, err := tabula.Open("application.pdf").InlineFormFields().Text()
+func (e *Extractor) InlineFormFields() *Extractor {
+	newExt := e.clone()
+	newExt.options.inlineFormFields = true
+	return newExt
+}
+
+// IncludeAttachments extracts the files embedded in a PDF, such as the
+// files of a portfolio or the XML data of an e-invoice, with the reader for
+// their format and appends their content to the output: Text() adds each
+// attachment's text after a "[Attachment: name]" line, Document() sets
+// Attachments with each file's extracted document, and Chunks() and
+// ToMarkdown() add the attachments' chunks, whose Metadata.Attachment names
+// the file. PDF attachments are searched for attachments in turn. Files in
+// formats tabula cannot read are skipped with a WarningAttachment. Has no
+// effect for other formats.
+//
+// Example:
+//
+//	chunks, _, err := tabula.Open("portfolio.pdf").IncludeAttachments().Chunks()
+func (e *Extractor) IncludeAttachments() *Extractor {
+	newExt := e.clone()
+	newExt.options.includeAttachments = true
+	return newExt
+}
+
+// SnapshotFormat sets how RenderPage and RenderRegion encode their images:
+// ImagePNG (the default) or ImageJPEG.
+//
+// Example:
+//
+//	thumb, err := tabula.Open("doc.pdf").SnapshotFormat(tabula.ImageJPEG).RenderPage(1, 36)
+func (e *Extractor) SnapshotFormat(f ImageFormat) *Extractor {
+	newExt := e.clone()
+	newExt.options.snapshotFormat = f
+	return newExt
+}
+
 // ByColumn configures the extractor to process text column by column
 // in reading order, rather than line by line across the full page width.
 // This is useful for multi-column documents like newspapers or academic papers.
@@ -364,6 +589,59 @@ func (e *Extractor) PreserveLayout() *Extractor {
 	return newExt
 }
 
+// Format overrides format detection and reads the source as f, for cases
+// where neither the extension nor the content identifies it reliably.
+// Content sniffing is skipped entirely.
+//
+// Example:
+//
+//	text, _, err := tabula.Open("export.dat").Format(format.XLSX).Text()
+func (e *Extractor) Format(f format.Format) *Extractor {
+	newExt := e.clone()
+	newExt.format = f
+	newExt.formatResolved = true
+	return newExt
+}
+
+// Password sets the password used to open an encrypted PDF. It may be either
+// the user or the owner password. PDFs whose user password is empty open
+// without one. Has no effect for other formats.
+//
+// When the document cannot be decrypted, terminal operations return an error
+// wrapping ErrPasswordRequired or ErrWrongPassword.
+//
+// Example:
+//
+//	text, _, err := tabula.Open("contract.pdf").Password("secret").Text()
+//	if errors.Is(err, tabula.ErrWrongPassword) {
+//	    // prompt again
+//	}
+func (e *Extractor) Password(password string) *Extractor {
+	newExt := e.clone()
+	newExt.options.password = password
+	return newExt
+}
+
+// WithContext sets the context that bounds terminal operations. Cancellation
+// is checked between pages and propagated into OCR and page rendering, so a
+// canceled extraction stops promptly and returns an error wrapping ctx.Err().
+// Non-PDF formats are parsed in one step and check the context only before
+// opening the file.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	text, _, err := tabula.Open("scan.pdf").WithContext(ctx).Text()
+//	if errors.Is(err, context.DeadlineExceeded) {
+//	    // gave up on this document
+//	}
+func (e *Extractor) WithContext(ctx context.Context) *Extractor {
+	newExt := e.clone()
+	newExt.ctx = ctx
+	return newExt
+}
+
 // IsCharacterLevel checks if the first page of the PDF uses character-level
 // text fragments (one character per fragment). This requires special handling
 // for proper text extraction.
@@ -544,12 +822,15 @@ func (e *Extractor) Text() (string, []Warning, error) {
 	requestedPages := make([]extractedPage, 0, len(pageIndices))
 
 	for _, pageNum := range pageIndices {
+		if err := e.ctxErr(); err != nil {
+			return "", nil, err
+		}
 		page, err := e.reader.GetPage(pageNum)
 		if err != nil {
 			return "", nil, fmt.Errorf("page %d: %w", pageNum+1, err)
 		}
 
-		fragments, err := e.reader.ExtractTextFragments(page)
+		fragments, err := e.pageFragments(page, pageNum)
 		if err != nil {
 			return "", nil, fmt.Errorf("page %d: %w", pageNum+1, err)
 		}
@@ -565,6 +846,9 @@ func (e *Extractor) Text() (string, []Warning, error) {
 	var headerFooterResult *layout.HeaderFooterResult
 	if e.options.excludeHeaders || e.options.excludeFooters {
 		allPages, err := e.collectAllPages()
+		if err != nil && e.ctxErr() != nil {
+			return "", nil, err
+		}
 		if err == nil && len(allPages) > 0 {
 			headerFooterResult = e.detectHeaderFooter(allPages)
 		}
@@ -581,6 +865,10 @@ func (e *Extractor) Text() (string, []Warning, error) {
 	var jobs []ocrJob
 	var ctxs []ocrCtx
 	for i, pd := range requestedPages {
+		if err := e.ctxErr(); err != nil {
+			return "", nil, err
+		}
+
 		// Check for messy PDF traits on the first page processed
 		if i == 0 {
 			e.checkMessyPDF(pd.fragments)
@@ -598,7 +886,7 @@ func (e *Extractor) Text() (string, []Warning, error) {
 		// pages and scans carrying only a native stamp/watermark overlay).
 		if e.shouldTryOCR(fragments) {
 			if prepared := e.prepareOCRImages(pd.page, pd.index+1); len(prepared) > 0 {
-				jobs = append(jobs, ocrJob{index: i, images: prepared})
+				jobs = append(jobs, ocrJob{index: i, pageNum: pd.index + 1, images: prepared})
 				ctxs = append(ctxs, ocrCtx{pageNum: pd.index + 1, fragments: fragments})
 			}
 		}
@@ -606,10 +894,13 @@ func (e *Extractor) Text() (string, []Warning, error) {
 
 	// Phase 2 (parallel): OCR the queued pages and override their text.
 	results := e.runOCRJobs(jobs)
+	if err := e.ctxErr(); err != nil {
+		return "", nil, err
+	}
 	for k, job := range jobs {
-		if strings.TrimSpace(results[job.index]) != "" {
-			pageTexts[job.index] = mergeNativeAndOCR(ctxs[k].fragments, results[job.index])
-			e.warnings = append(e.warnings, ocrWarning(ctxs[k].pageNum))
+		if strings.TrimSpace(results[job.index].text) != "" {
+			pageTexts[job.index] = mergeNativeAndOCR(ctxs[k].fragments, results[job.index].text)
+			e.warnings = append(e.warnings, ocrWarnings(ctxs[k].pageNum, results[job.index])...)
 		}
 	}
 
@@ -620,9 +911,16 @@ func (e *Extractor) Text() (string, []Warning, error) {
 			parts = append(parts, t)
 		}
 	}
+	parts = append(parts, e.attachmentTexts()...)
 	return strings.Join(parts, "\n\n"), e.warnings, nil
 }
 
+// TextContext is like Text but stops when ctx is canceled.
+// It is shorthand for e.WithContext(ctx).Text().
+func (e *Extractor) TextContext(ctx context.Context) (string, []Warning, error) {
+	return e.WithContext(ctx).Text()
+}
+
 // extractWithParagraphs uses paragraph detection to join lines within paragraphs
 // with spaces instead of newlines, producing cleaner text output.
 // It respects multi-column layouts by using reading order detection.
@@ -717,63 +1015,57 @@ func (e *Extractor) ToMarkdown() (string, []Warning, error) {
 //	md, warnings, err := tabula.Open("document.pdf").ToMarkdownWithOptions(opts)
 //	md, warnings, err := tabula.Open("document.docx").ToMarkdownWithOptions(opts)
 func (e *Extractor) ToMarkdownWithOptions(opts rag.MarkdownOptions) (string, []Warning, error) {
+	if e.err != nil {
+		return "", nil, e.err
+	}
+
+	// Resolve the format from the content before choosing a path
+	if err := e.ensureReader(); err != nil {
+		return "", nil, err
+	}
+	defer e.Close()
+
 	// For DOCX files, use the native markdown method which preserves document order
 	if e.format == format.DOCX {
-		if err := e.ensureReader(); err != nil {
-			return "", nil, err
-		}
-		defer e.Close()
 		docxOpts := docx.ExtractOptions{
 			ExcludeHeaders: e.options.excludeHeaders,
 			ExcludeFooters: e.options.excludeFooters,
 		}
 		md, err := e.docxReader.MarkdownWithRAGOptions(docxOpts, opts)
 		if err != nil {
-			return "", nil, err
+			return "", e.warnings, err
 		}
-		return md, nil, nil
+		return md, e.warnings, nil
 	}
 
 	// For ODT files, use the native markdown method which preserves document order
 	if e.format == format.ODT {
-		if err := e.ensureReader(); err != nil {
-			return "", nil, err
-		}
-		defer e.Close()
 		odtOpts := odt.ExtractOptions{
 			ExcludeHeaders: e.options.excludeHeaders,
 			ExcludeFooters: e.options.excludeFooters,
 		}
 		md, err := e.odtReader.MarkdownWithRAGOptions(odtOpts, opts)
 		if err != nil {
-			return "", nil, err
+			return "", e.warnings, err
 		}
-		return md, nil, nil
+		return md, e.warnings, nil
 	}
 
 	// For XLSX files, use the native markdown method
 	if e.format == format.XLSX {
-		if err := e.ensureReader(); err != nil {
-			return "", nil, err
-		}
-		defer e.Close()
 		xlsxOpts := xlsx.ExtractOptions{
 			ExcludeHeaders: e.options.excludeHeaders,
 			ExcludeFooters: e.options.excludeFooters,
 		}
 		md, err := e.xlsxReader.MarkdownWithRAGOptions(xlsxOpts, opts)
 		if err != nil {
-			return "", nil, err
+			return "", e.warnings, err
 		}
-		return md, nil, nil
+		return md, e.warnings, nil
 	}
 
 	// For PPTX files, use the native markdown method
 	if e.format == format.PPTX {
-		if err := e.ensureReader(); err != nil {
-			return "", nil, err
-		}
-		defer e.Close()
 		pptxOpts := pptx.ExtractOptions{
 			ExcludeHeaders: e.options.excludeHeaders,
 			ExcludeFooters: e.options.excludeFooters,
@@ -782,39 +1074,31 @@ func (e *Extractor) ToMarkdownWithOptions(opts rag.MarkdownOptions) (string, []W
 		}
 		md, err := e.pptxReader.MarkdownWithRAGOptions(pptxOpts, opts)
 		if err != nil {
-			return "", nil, err
+			return "", e.warnings, err
 		}
-		return md, nil, nil
+		return md, e.warnings, nil
 	}
 
 	// For HTML files, use the native markdown method
 	if e.format == format.HTML {
-		if err := e.ensureReader(); err != nil {
-			return "", nil, err
-		}
-		defer e.Close()
 		htmlOpts := htmldoc.ExtractOptions{
 			ExcludeHeaders: e.options.excludeHeaders,
 			ExcludeFooters: e.options.excludeFooters,
 		}
 		md, err := e.htmlReader.MarkdownWithRAGOptions(htmlOpts, opts)
 		if err != nil {
-			return "", nil, err
+			return "", e.warnings, err
 		}
-		return md, nil, nil
+		return md, e.warnings, nil
 	}
 
 	// For EPUB files, use the native markdown method
 	if e.format == format.EPUB {
-		if err := e.ensureReader(); err != nil {
-			return "", nil, err
-		}
-		defer e.Close()
 		md, err := e.epubReader.Markdown()
 		if err != nil {
-			return "", nil, err
+			return "", e.warnings, err
 		}
-		return md, nil, nil
+		return md, e.warnings, nil
 	}
 
 	// For PDF files, use the RAG chunking pipeline
@@ -852,12 +1136,15 @@ func (e *Extractor) Fragments() ([]text.TextFragment, []Warning, error) {
 
 	var allFragments []text.TextFragment
 	for i, pageNum := range pageIndices {
+		if err := e.ctxErr(); err != nil {
+			return nil, nil, err
+		}
 		page, err := e.reader.GetPage(pageNum)
 		if err != nil {
 			return nil, nil, fmt.Errorf("page %d: %w", pageNum+1, err)
 		}
 
-		fragments, err := e.reader.ExtractTextFragments(page)
+		fragments, err := e.pageFragments(page, pageNum)
 		if err != nil {
 			return nil, nil, fmt.Errorf("page %d: %w", pageNum+1, err)
 		}
@@ -942,53 +1229,19 @@ func (e *Extractor) Lines() ([]layout.Line, error) {
 		return nil, err
 	}
 
-	// Collect requested page data
-	requestedPages := make([]extractedPage, 0, len(pageIndices))
-	for _, pageNum := range pageIndices {
-		page, err := e.reader.GetPage(pageNum)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		fragments, err := e.reader.ExtractTextFragments(page)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		requestedPages = append(requestedPages, extractedPage{
-			index:     pageNum,
-			fragments: fragments,
-			page:      page,
-		})
-	}
-
-	// Detect headers/footers if needed (requires ALL pages for pattern detection)
-	var headerFooterResult *layout.HeaderFooterResult
-	if e.options.excludeHeaders || e.options.excludeFooters {
-		allPages, err := e.collectAllPages()
-		if err == nil && len(allPages) > 0 {
-			headerFooterResult = e.detectHeaderFooter(allPages)
-		}
-	}
-
 	// Process each requested page and collect lines
 	var allLines []layout.Line
 	lineDetector := layout.NewLineDetector()
 
-	for _, pd := range requestedPages {
-		fragments := pd.fragments
-
-		// Filter headers/footers
-		if headerFooterResult != nil {
-			height, _ := pd.page.Height()
-			fragments = headerFooterResult.FilterFragments(pd.index, fragments, height)
-		}
-
+	err = e.eachLayoutPage(pageIndices, func(pd extractedPage) {
 		width, _ := pd.page.Width()
 		height, _ := pd.page.Height()
 
-		lineLayout := lineDetector.Detect(fragments, width, height)
+		lineLayout := lineDetector.Detect(pd.fragments, width, height)
 		allLines = append(allLines, lineLayout.Lines...)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return allLines, nil
@@ -1022,55 +1275,21 @@ func (e *Extractor) Paragraphs() ([]layout.Paragraph, error) {
 		return nil, err
 	}
 
-	// Collect requested page data
-	requestedPages := make([]extractedPage, 0, len(pageIndices))
-	for _, pageNum := range pageIndices {
-		page, err := e.reader.GetPage(pageNum)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		fragments, err := e.reader.ExtractTextFragments(page)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		requestedPages = append(requestedPages, extractedPage{
-			index:     pageNum,
-			fragments: fragments,
-			page:      page,
-		})
-	}
-
-	// Detect headers/footers if needed (requires ALL pages for pattern detection)
-	var headerFooterResult *layout.HeaderFooterResult
-	if e.options.excludeHeaders || e.options.excludeFooters {
-		allPages, err := e.collectAllPages()
-		if err == nil && len(allPages) > 0 {
-			headerFooterResult = e.detectHeaderFooter(allPages)
-		}
-	}
-
 	// Process each requested page and collect paragraphs
 	var allParagraphs []layout.Paragraph
 	roDetector := layout.NewReadingOrderDetector()
 
-	for _, pd := range requestedPages {
-		fragments := pd.fragments
-
-		// Filter headers/footers
-		if headerFooterResult != nil {
-			height, _ := pd.page.Height()
-			fragments = headerFooterResult.FilterFragments(pd.index, fragments, height)
-		}
-
-		width, _ := pd.page.Width()
-		height, _ := pd.page.Height()
+	err = e.eachLayoutPage(pageIndices, func(pd extractedPage) {
+		width, _ := pd.page.Width()
+		height, _ := pd.page.Height()
 
 		// Use reading order detection for multi-column support
-		roResult := roDetector.Detect(fragments, width, height)
+		roResult := roDetector.Detect(pd.fragments, width, height)
 		paraLayout := roResult.GetParagraphs()
 		allParagraphs = append(allParagraphs, paraLayout.Paragraphs...)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return allParagraphs, nil
@@ -1107,41 +1326,16 @@ func (e *Extractor) ReadingOrder() (*layout.ReadingOrderResult, error) {
 		return nil, fmt.Errorf("no pages to process")
 	}
 
-	// Detect headers/footers if requested (needs all pages)
-	var headerFooterResult *layout.HeaderFooterResult
-	if e.options.excludeHeaders || e.options.excludeFooters {
-		allPages, err := e.collectAllPages()
-		if err == nil && len(allPages) > 0 {
-			headerFooterResult = e.detectHeaderFooter(allPages)
-		}
-	}
-
 	roDetector := layout.NewReadingOrderDetector()
 
 	// Combined result across all pages
 	combined := &layout.ReadingOrderResult{}
 
-	for _, pageNum := range pageIndices {
-		page, err := e.reader.GetPage(pageNum)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		fragments, err := e.reader.ExtractTextFragments(page)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		// Filter headers/footers if requested
-		if headerFooterResult != nil {
-			height, _ := page.Height()
-			fragments = headerFooterResult.FilterFragments(pageNum, fragments, height)
-		}
-
-		width, _ := page.Width()
-		height, _ := page.Height()
+	err = e.eachLayoutPage(pageIndices, func(pd extractedPage) {
+		width, _ := pd.page.Width()
+		height, _ := pd.page.Height()
 
-		pageResult := roDetector.Detect(fragments, width, height)
+		pageResult := roDetector.Detect(pd.fragments, width, height)
 
 		// Combine results
 		combined.Fragments = append(combined.Fragments, pageResult.Fragments...)
@@ -1159,6 +1353,9 @@ func (e *Extractor) ReadingOrder() (*layout.ReadingOrderResult, error) {
 			combined.PageHeight = pageResult.PageHeight
 			combined.Direction = pageResult.Direction
 		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return combined, nil
@@ -1194,41 +1391,16 @@ func (e *Extractor) Analyze() (*layout.AnalysisResult, error) {
 		return nil, fmt.Errorf("no pages to process")
 	}
 
-	// Detect headers/footers if requested (needs all pages)
-	var headerFooterResult *layout.HeaderFooterResult
-	if e.options.excludeHeaders || e.options.excludeFooters {
-		allPages, err := e.collectAllPages()
-		if err == nil && len(allPages) > 0 {
-			headerFooterResult = e.detectHeaderFooter(allPages)
-		}
-	}
-
 	analyzer := layout.NewAnalyzer()
 
 	// Combined result across all pages
 	combined := &layout.AnalysisResult{}
 
-	for _, pageNum := range pageIndices {
-		page, err := e.reader.GetPage(pageNum)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		fragments, err := e.reader.ExtractTextFragments(page)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		// Filter headers/footers if requested
-		if headerFooterResult != nil {
-			height, _ := page.Height()
-			fragments = headerFooterResult.FilterFragments(pageNum, fragments, height)
-		}
-
-		width, _ := page.Width()
-		height, _ := page.Height()
+	err = e.eachLayoutPage(pageIndices, func(pd extractedPage) {
+		width, _ := pd.page.Width()
+		height, _ := pd.page.Height()
 
-		pageResult := analyzer.Analyze(fragments, width, height)
+		pageResult := analyzer.Analyze(pd.fragments, width, height)
 
 		// Update element page indices and combine
 		for i := range pageResult.Elements {
@@ -1250,6 +1422,9 @@ func (e *Extractor) Analyze() (*layout.AnalysisResult, error) {
 			combined.PageWidth = pageResult.PageWidth
 			combined.PageHeight = pageResult.PageHeight
 		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return combined, nil
@@ -1279,45 +1454,23 @@ func (e *Extractor) Headings() ([]layout.Heading, error) {
 		return nil, err
 	}
 
-	// Detect headers/footers if requested (once, outside the loop)
-	var headerFooterResult *layout.HeaderFooterResult
-	if e.options.excludeHeaders || e.options.excludeFooters {
-		allPages, err := e.collectAllPages()
-		if err == nil && len(allPages) > 0 {
-			headerFooterResult = e.detectHeaderFooter(allPages)
-		}
-	}
-
 	var allHeadings []layout.Heading
 	detector := layout.NewHeadingDetector()
 
-	for _, pageNum := range pageIndices {
-		page, err := e.reader.GetPage(pageNum)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		fragments, err := e.reader.ExtractTextFragments(page)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		// Filter headers/footers if requested
-		if headerFooterResult != nil {
-			height, _ := page.Height()
-			fragments = headerFooterResult.FilterFragments(pageNum, fragments, height)
-		}
-
-		width, _ := page.Width()
-		height, _ := page.Height()
+	err = e.eachLayoutPage(pageIndices, func(pd extractedPage) {
+		width, _ := pd.page.Width()
+		height, _ := pd.page.Height()
 
-		result := detector.DetectFromFragments(fragments, width, height)
+		result := detector.DetectFromFragments(pd.fragments, width, height)
 		if result != nil {
 			for i := range result.Headings {
-				result.Headings[i].PageIndex = pageNum
+				result.Headings[i].PageIndex = pd.index
 			}
 			allHeadings = append(allHeadings, result.Headings...)
 		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return allHeadings, nil
@@ -1347,42 +1500,20 @@ func (e *Extractor) Lists() ([]layout.List, error) {
 		return nil, err
 	}
 
-	// Detect headers/footers if requested (once, outside the loop)
-	var headerFooterResult *layout.HeaderFooterResult
-	if e.options.excludeHeaders || e.options.excludeFooters {
-		allPages, err := e.collectAllPages()
-		if err == nil && len(allPages) > 0 {
-			headerFooterResult = e.detectHeaderFooter(allPages)
-		}
-	}
-
 	var allLists []layout.List
 	detector := layout.NewListDetector()
 
-	for _, pageNum := range pageIndices {
-		page, err := e.reader.GetPage(pageNum)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		fragments, err := e.reader.ExtractTextFragments(page)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		// Filter headers/footers if requested
-		if headerFooterResult != nil {
-			height, _ := page.Height()
-			fragments = headerFooterResult.FilterFragments(pageNum, fragments, height)
-		}
-
-		width, _ := page.Width()
-		height, _ := page.Height()
+	err = e.eachLayoutPage(pageIndices, func(pd extractedPage) {
+		width, _ := pd.page.Width()
+		height, _ := pd.page.Height()
 
-		result := detector.DetectFromFragments(fragments, width, height)
+		result := detector.DetectFromFragments(pd.fragments, width, height)
 		if result != nil {
 			allLists = append(allLists, result.Lists...)
 		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return allLists, nil
@@ -1414,42 +1545,20 @@ func (e *Extractor) Blocks() ([]layout.Block, error) {
 		return nil, err
 	}
 
-	// Detect headers/footers if requested (once, outside the loop)
-	var headerFooterResult *layout.HeaderFooterResult
-	if e.options.excludeHeaders || e.options.excludeFooters {
-		allPages, err := e.collectAllPages()
-		if err == nil && len(allPages) > 0 {
-			headerFooterResult = e.detectHeaderFooter(allPages)
-		}
-	}
-
 	var allBlocks []layout.Block
 	detector := layout.NewBlockDetector()
 
-	for _, pageNum := range pageIndices {
-		page, err := e.reader.GetPage(pageNum)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		fragments, err := e.reader.ExtractTextFragments(page)
-		if err != nil {
-			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
-		}
-
-		// Filter headers/footers if requested
-		if headerFooterResult != nil {
-			height, _ := page.Height()
-			fragments = headerFooterResult.FilterFragments(pageNum, fragments, height)
-		}
-
-		width, _ := page.Width()
-		height, _ := page.Height()
+	err = e.eachLayoutPage(pageIndices, func(pd extractedPage) {
+		width, _ := pd.page.Width()
+		height, _ := pd.page.Height()
 
-		result := detector.Detect(fragments, width, height)
+		result := detector.Detect(pd.fragments, width, height)
 		if result != nil {
 			allBlocks = append(allBlocks, result.Blocks...)
 		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return allBlocks, nil
@@ -1565,63 +1674,43 @@ func (e *Extractor) Document() (*model.Document, []Warning, error) {
 	// Create new document
 	doc := model.NewDocument()
 
-	// Try to get metadata from the PDF info dictionary
+	// Metadata from the info dictionary and XMP, which are optional; a
+	// malformed info dictionary leaves the XMP metadata
 	if e.reader != nil {
-		if info, err := e.reader.GetInfo(); err == nil && info != nil {
-			if title := info.Get("Title"); title != nil {
-				if s, ok := title.(core.String); ok {
-					doc.Metadata.Title = string(s)
-				}
-			}
-			if author := info.Get("Author"); author != nil {
-				if s, ok := author.(core.String); ok {
-					doc.Metadata.Author = string(s)
-				}
-			}
-			if subject := info.Get("Subject"); subject != nil {
-				if s, ok := subject.(core.String); ok {
-					doc.Metadata.Subject = string(s)
-				}
-			}
-			if creator := info.Get("Creator"); creator != nil {
-				if s, ok := creator.(core.String); ok {
-					doc.Metadata.Creator = string(s)
-				}
-			}
-			if producer := info.Get("Producer"); producer != nil {
-				if s, ok := producer.(core.String); ok {
-					doc.Metadata.Producer = string(s)
-				}
-			}
-			if keywords := info.Get("Keywords"); keywords != nil {
-				if s, ok := keywords.(core.String); ok {
-					doc.Metadata.Keywords = strings.Split(string(s), ",")
-					for i, kw := range doc.Metadata.Keywords {
-						doc.Metadata.Keywords[i] = strings.TrimSpace(kw)
-					}
-				}
-			}
+		meta, err := e.reader.Metadata()
+		doc.Metadata = meta
+		if err != nil {
+			e.warnings = append(e.warnings, Warning{
+				Code:    WarningMetadata,
+				Message: fmt.Sprintf("Document information dictionary ignored: %v", err),
+			})
 		}
 	}
 
+	// Bookmarks, used by the RAG chunker for section paths
+	doc.Outline = e.loadOutline()
+
 	// Detect headers/footers if needed (requires ALL pages for pattern detection)
 	var headerFooterResult *layout.HeaderFooterResult
 	if e.options.excludeHeaders || e.options.excludeFooters {
 		allPages, err := e.collectAllPages()
+		if err != nil && e.ctxErr() != nil {
+			return nil, nil, err
+		}
 		if err == nil && len(allPages) > 0 {
 			headerFooterResult = e.detectHeaderFooter(allPages)
 		}
 	}
 
-	roDetector := layout.NewReadingOrderDetector()
-	paraDetector := layout.NewParagraphDetector()
-	headingDetector := layout.NewHeadingDetector()
-	listDetector := layout.NewListDetector()
+	analyzers := newPageAnalyzers()
+	analyzers.tagged = e.loadTaggedContent()
+	labels := e.definedPageLabels()
 
 	// OCR is queued during this sequential (reader-bound) pass and run in
 	// parallel afterward; a queued page's content is replaced if OCR yields text.
 	type ocrTarget struct {
 		page      *model.Page
+		pdfPage   *pages.Page
 		fragments []text.TextFragment
 		pageNum   int
 	}
@@ -1629,12 +1718,15 @@ func (e *Extractor) Document() (*model.Document, []Warning, error) {
 	var ocrTargets []ocrTarget
 
 	for _, pageNum := range pageIndices {
+		if err := e.ctxErr(); err != nil {
+			return nil, nil, err
+		}
 		page, err := e.reader.GetPage(pageNum)
 		if err != nil {
 			return nil, nil, fmt.Errorf("page %d: %w", pageNum+1, err)
 		}
 
-		fragments, err := e.reader.ExtractTextFragments(page)
+		fragments, err := e.pageFragments(page, pageNum)
 		if err != nil {
 			return nil, nil, fmt.Errorf("page %d: %w", pageNum+1, err)
 		}
@@ -1656,6 +1748,8 @@ func (e *Extractor) Document() (*model.Document, []Warning, error) {
 		// Create model page
 		modelPage := model.NewPage(width, height)
 		modelPage.Number = pageNum + 1
+		modelPage.SourceNumber = pageNum + 1
+		modelPage.PageLabel = pageLabel(labels, pageNum)
 
 		// Sparse or no native text: queue an OCR fallback so Document()/Chunks()/
 		// ToMarkdown() recover scanned content the same way Text() does. The page
@@ -1665,124 +1759,305 @@ func (e *Extractor) Document() (*model.Document, []Warning, error) {
 		// born-digital sparse page keeps its real layout if OCR finds nothing).
 		if e.shouldTryOCR(fragments) {
 			if prepared := e.prepareOCRImages(page, pageNum+1); len(prepared) > 0 {
-				ocrJobsList = append(ocrJobsList, ocrJob{index: len(ocrJobsList), images: prepared})
-				ocrTargets = append(ocrTargets, ocrTarget{page: modelPage, fragments: fragments, pageNum: pageNum + 1})
+				ocrJobsList = append(ocrJobsList, ocrJob{index: len(ocrJobsList), pageNum: pageNum + 1, images: prepared})
+				ocrTargets = append(ocrTargets, ocrTarget{page: modelPage, pdfPage: page, fragments: fragments, pageNum: pageNum + 1})
 			}
 		}
 
-		// Perform layout analysis
-		roResult := roDetector.Detect(fragments, width, height)
+		e.analyzePDFPage(analyzers, modelPage, page, fragments)
 
-		// Get lines for paragraph detection
-		var lines []layout.Line
-		if roResult != nil && len(roResult.Lines) > 0 {
-			lines = roResult.Lines
+		doc.AddPage(modelPage)
+	}
+
+	// Parallel OCR pass: rebuild queued pages from their native text and the
+	// words OCR found.
+	results := e.runOCRJobs(ocrJobsList)
+	if err := e.ctxErr(); err != nil {
+		return nil, nil, err
+	}
+	for k, job := range ocrJobsList {
+		if strings.TrimSpace(results[job.index].text) == "" {
+			continue
 		}
+		t := ocrTargets[k]
+		e.setOCRContent(analyzers, t.page, t.pdfPage, t.fragments, results[job.index])
+		e.warnings = append(e.warnings, ocrWarnings(t.pageNum, results[job.index])...)
+	}
 
-		// Detect paragraphs
-		var paragraphs []model.ParagraphInfo
-		if len(lines) > 0 {
-			paraLayout := paraDetector.Detect(lines, width, height)
-			for _, para := range paraLayout.Paragraphs {
-				paragraphs = append(paragraphs, model.ParagraphInfo{
-					BBox:      model.BBox{X: para.BBox.X, Y: para.BBox.Y, Width: para.BBox.Width, Height: para.BBox.Height},
-					Text:      para.Text,
-					LineCount: len(para.Lines),
-				})
+	doc.Attachments = e.attachmentDocuments()
+
+	return doc, e.warnings, nil
+}
+
+// pageAnalyzers holds the layout detectors used to build a model.Page from a
+// PDF page's fragments, so they can be reused across pages.
+type pageAnalyzers struct {
+	readingOrder *layout.ReadingOrderDetector
+	paragraphs   *layout.ParagraphDetector
+	headings     *layout.HeadingDetector
+	lists        *layout.ListDetector
+	tagged       *taggedContent // Structure tree of a tagged PDF, or nil
+}
+
+// newPageAnalyzers creates the detectors with their default configuration.
+func newPageAnalyzers() *pageAnalyzers {
+	return &pageAnalyzers{
+		readingOrder: layout.NewReadingOrderDetector(),
+		paragraphs:   layout.NewParagraphDetector(),
+		headings:     layout.NewHeadingDetector(),
+		lists:        layout.NewListDetector(),
+	}
+}
+
+// analyze runs layout analysis over a page's fragments and fills in
+// modelPage's layout info and elements (headings, paragraphs, lists).
+func (a *pageAnalyzers) analyze(modelPage *model.Page, fragments []text.TextFragment, width, height float64) {
+	// Perform layout analysis
+	roResult := a.readingOrder.Detect(fragments, width, height)
+
+	// Get lines for paragraph detection
+	var lines []layout.Line
+	if roResult != nil && len(roResult.Lines) > 0 {
+		lines = roResult.Lines
+	}
+
+	// Detect paragraphs
+	var paragraphs []model.ParagraphInfo
+	var paragraphStyles []model.TextStyle
+	if len(lines) > 0 {
+		paraLayout := a.paragraphs.Detect(lines, width, height)
+		for _, para := range paraLayout.Paragraphs {
+			frags := para.GetFragments()
+			paragraphStyles = append(paragraphStyles, layout.DominantStyle(frags))
+			// Reading order measures line positions from their column's left
+			// edge; the fragments give the paragraph's place on the page
+			bbox := model.BBox{X: para.BBox.X, Y: para.BBox.Y, Width: para.BBox.Width, Height: para.BBox.Height}
+			if len(frags) > 0 {
+				bbox = taggedBBox(frags)
 			}
+			paragraphs = append(paragraphs, model.ParagraphInfo{
+				BBox:      bbox,
+				Text:      para.Text,
+				LineCount: len(para.Lines),
+			})
+		}
+	}
+
+	// Detect headings
+	var headings []model.HeadingInfo
+	var headingStyles []model.TextStyle
+	headingResult := a.headings.DetectFromFragments(fragments, width, height)
+	if headingResult != nil {
+		for _, h := range headingResult.Headings {
+			style := layout.DominantStyle(h.Fragments)
+			style.Bold, style.Italic = h.IsBold, h.IsItalic
+			headingStyles = append(headingStyles, style)
+			headings = append(headings, model.HeadingInfo{
+				Level:      int(h.Level),
+				Text:       h.Text,
+				BBox:       model.BBox{X: h.BBox.X, Y: h.BBox.Y, Width: h.BBox.Width, Height: h.BBox.Height},
+				FontSize:   h.FontSize,
+				Confidence: h.Confidence,
+			})
 		}
+	}
 
-		// Detect headings
-		var headings []model.HeadingInfo
-		headingResult := headingDetector.DetectFromFragments(fragments, width, height)
-		if headingResult != nil {
-			for _, h := range headingResult.Headings {
-				headings = append(headings, model.HeadingInfo{
-					Level:      int(h.Level),
-					Text:       h.Text,
-					BBox:       model.BBox{X: h.BBox.X, Y: h.BBox.Y, Width: h.BBox.Width, Height: h.BBox.Height},
-					FontSize:   h.FontSize,
-					Confidence: h.Confidence,
+	// Detect lists
+	var lists []model.ListInfo
+	listResult := a.lists.DetectFromFragments(fragments, width, height)
+	if listResult != nil {
+		for _, l := range listResult.Lists {
+			listInfo := model.ListInfo{
+				Type:   convertListType(l.Type),
+				BBox:   model.BBox{X: l.BBox.X, Y: l.BBox.Y, Width: l.BBox.Width, Height: l.BBox.Height},
+				Nested: l.Level > 0, // Consider nested if level > 0
+			}
+			for _, item := range l.Items {
+				listInfo.Items = append(listInfo.Items, model.ListItem{
+					Text:   item.Text,
+					Level:  item.Level,
+					Bullet: item.Prefix,
 				})
 			}
-		}
+			lists = append(lists, listInfo)
+		}
+	}
+
+	// Create layout info
+	modelPage.Layout = &model.PageLayout{
+		Paragraphs: paragraphs,
+		Headings:   headings,
+		Lists:      lists,
+		Stats: model.LayoutStats{
+			FragmentCount:  len(fragments),
+			ParagraphCount: len(paragraphs),
+			HeadingCount:   len(headings),
+			ListCount:      len(lists),
+		},
+	}
+
+	// Add elements to page
+	for i, h := range headings {
+		modelPage.AddElement(&model.Heading{
+			Level: h.Level,
+			Text:  h.Text,
+			BBox:  h.BBox,
+			Style: headingStyles[i],
+		})
+	}
+	for i, p := range paragraphs {
+		modelPage.AddElement(&model.Paragraph{
+			Text:  p.Text,
+			BBox:  p.BBox,
+			Style: paragraphStyles[i],
+		})
+	}
+	for _, l := range lists {
+		modelPage.AddElement(&model.List{
+			Items:   l.Items,
+			Ordered: l.Type == model.ListTypeNumbered || l.Type == model.ListTypeLettered || l.Type == model.ListTypeRoman,
+			BBox:    l.BBox,
+		})
+	}
+}
 
-		// Detect lists
-		var lists []model.ListInfo
-		listResult := listDetector.DetectFromFragments(fragments, width, height)
-		if listResult != nil {
-			for _, l := range listResult.Lists {
-				listInfo := model.ListInfo{
-					Type:   convertListType(l.Type),
-					BBox:   model.BBox{X: l.BBox.X, Y: l.BBox.Y, Width: l.BBox.Width, Height: l.BBox.Height},
-					Nested: l.Level > 0, // Consider nested if level > 0
-				}
-				for _, item := range l.Items {
-					listInfo.Items = append(listInfo.Items, model.ListItem{
-						Text:   item.Text,
-						Level:  item.Level,
-						Bullet: item.Prefix,
-					})
-				}
-				lists = append(lists, listInfo)
+// setOCRContent rebuilds a page from its native fragments and the words OCR
+// found on it, so a scanned page gets headings, paragraphs, lists and
+// bounding boxes like a born-digital one. When the engine placed no words
+// (it reported no boxes, or the image's position is unknown), the page's
+// content is replaced by its native text merged with the OCR text.
+func (e *Extractor) setOCRContent(a *pageAnalyzers, p *model.Page, page *pages.Page, fragments []text.TextFragment, res ocrResult) {
+	if len(res.fragments) > 0 {
+		p.Elements, p.Layout = nil, nil
+		e.analyzePDFPage(a, p, page, append(fragments[:len(fragments):len(fragments)], res.fragments...))
+		return
+	}
+
+	merged := mergeNativeAndOCR(fragments, res.text)
+	p.Elements = []model.Element{&model.Paragraph{Text: merged}}
+	p.Layout = &model.PageLayout{
+		Paragraphs: []model.ParagraphInfo{{Text: merged, LineCount: strings.Count(merged, "\n") + 1}},
+		Stats:      model.LayoutStats{FragmentCount: len(fragments), ParagraphCount: 1},
+	}
+}
+
+// eachLayoutPage calls fn with the fragments of each requested page, in
+// order, for the layout operations (Lines, Paragraphs, ReadingOrder, Analyze,
+// Headings, Lists, Blocks and Tables). Scanned pages get the words OCR finds
+// on them first. Pages are read and passed on one at a time, unless headers
+// or footers are excluded: their detection needs every page, so the
+// requested pages are collected first, OCRed together, and have the headers
+// and footers found across the document removed from them like from
+// born-digital pages.
+func (e *Extractor) eachLayoutPage(pageIndices []int, fn func(pd extractedPage)) error {
+	if !e.options.excludeHeaders && !e.options.excludeFooters {
+		for _, pageNum := range pageIndices {
+			pd, err := e.layoutPage(pageNum)
+			if err != nil {
+				return err
 			}
+			page := []extractedPage{pd}
+			if err := e.addOCRFragments(page); err != nil {
+				return err
+			}
+			fn(page[0])
 		}
+		return nil
+	}
 
-		// Create layout info
-		modelPage.Layout = &model.PageLayout{
-			Paragraphs: paragraphs,
-			Headings:   headings,
-			Lists:      lists,
-			Stats: model.LayoutStats{
-				FragmentCount:  len(fragments),
-				ParagraphCount: len(paragraphs),
-				HeadingCount:   len(headings),
-				ListCount:      len(lists),
-			},
-		}
-
-		// Add elements to page
-		for _, h := range headings {
-			modelPage.AddElement(&model.Heading{
-				Level: h.Level,
-				Text:  h.Text,
-				BBox:  h.BBox,
-			})
+	requestedPages := make([]extractedPage, 0, len(pageIndices))
+	for _, pageNum := range pageIndices {
+		pd, err := e.layoutPage(pageNum)
+		if err != nil {
+			return err
 		}
-		for _, p := range paragraphs {
-			modelPage.AddElement(&model.Paragraph{
-				Text: p.Text,
-				BBox: p.BBox,
-			})
+		requestedPages = append(requestedPages, pd)
+	}
+
+	if err := e.addOCRFragments(requestedPages); err != nil {
+		return err
+	}
+
+	// Detect headers/footers across ALL pages, with the requested pages'
+	// OCR words
+	allPages, err := e.collectAllPages()
+	if err != nil && e.ctxErr() != nil {
+		return err
+	}
+	if err == nil && len(allPages) > 0 {
+		requested := make(map[int][]text.TextFragment, len(requestedPages))
+		for _, pd := range requestedPages {
+			requested[pd.index] = pd.fragments
 		}
-		for _, l := range lists {
-			modelPage.AddElement(&model.List{
-				Items:   l.Items,
-				Ordered: l.Type == model.ListTypeNumbered || l.Type == model.ListTypeLettered || l.Type == model.ListTypeRoman,
-				BBox:    l.BBox,
-			})
+		for i, pd := range allPages {
+			if fragments, ok := requested[pd.index]; ok {
+				allPages[i].fragments = fragments
+			}
 		}
 
-		doc.AddPage(modelPage)
+		headerFooterResult := e.detectHeaderFooter(allPages)
+		for i, pd := range requestedPages {
+			height, _ := pd.page.Height()
+			requestedPages[i].fragments = headerFooterResult.FilterFragments(pd.index, pd.fragments, height)
+		}
 	}
 
-	// Parallel OCR pass: replace queued pages' content with their OCR text.
-	results := e.runOCRJobs(ocrJobsList)
-	for k, job := range ocrJobsList {
-		if strings.TrimSpace(results[job.index]) == "" {
+	for _, pd := range requestedPages {
+		fn(pd)
+	}
+	return nil
+}
+
+// layoutPage reads the page at index pageNum and its fragments.
+func (e *Extractor) layoutPage(pageNum int) (extractedPage, error) {
+	if err := e.ctxErr(); err != nil {
+		return extractedPage{}, err
+	}
+	page, err := e.reader.GetPage(pageNum)
+	if err != nil {
+		return extractedPage{}, fmt.Errorf("page %d: %w", pageNum+1, err)
+	}
+
+	fragments, err := e.pageFragments(page, pageNum)
+	if err != nil {
+		return extractedPage{}, fmt.Errorf("page %d: %w", pageNum+1, err)
+	}
+
+	return extractedPage{index: pageNum, fragments: fragments, page: page}, nil
+}
+
+// addOCRFragments adds the words OCR finds on pages with sparse native text
+// to their fragments, positioned on the page. The images of all such pages
+// are prepared first, then recognized together in one pass. Pages with
+// enough native text, and pages OCR finds nothing on, keep their fragments.
+func (e *Extractor) addOCRFragments(requestedPages []extractedPage) error {
+	var jobs []ocrJob
+	for i, pd := range requestedPages {
+		if !e.shouldTryOCR(pd.fragments) {
 			continue
 		}
-		t := ocrTargets[k]
-		merged := mergeNativeAndOCR(t.fragments, results[job.index])
-		t.page.Elements = []model.Element{&model.Paragraph{Text: merged}}
-		t.page.Layout = &model.PageLayout{
-			Paragraphs: []model.ParagraphInfo{{Text: merged, LineCount: strings.Count(merged, "\n") + 1}},
-			Stats:      model.LayoutStats{FragmentCount: len(t.fragments), ParagraphCount: 1},
+		if prepared := e.prepareOCRImages(pd.page, pd.index+1); len(prepared) > 0 {
+			jobs = append(jobs, ocrJob{index: i, pageNum: pd.index + 1, images: prepared})
 		}
-		e.warnings = append(e.warnings, ocrWarning(t.pageNum))
 	}
 
-	return doc, e.warnings, nil
+	results := e.runOCRJobs(jobs)
+	if err := e.ctxErr(); err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		pd := &requestedPages[job.index]
+		if words := results[job.index].fragments; len(words) > 0 {
+			pd.fragments = append(pd.fragments[:len(pd.fragments):len(pd.fragments)], words...)
+		}
+	}
+	return nil
+}
+
+// DocumentContext is like Document but stops when ctx is canceled.
+// It is shorthand for e.WithContext(ctx).Document().
+func (e *Extractor) DocumentContext(ctx context.Context) (*model.Document, []Warning, error) {
+	return e.WithContext(ctx).Document()
 }
 
 // Chunks extracts content and returns semantic chunks for RAG workflows.
@@ -1806,10 +2081,16 @@ func (e *Extractor) Chunks() (*rag.ChunkCollection, []Warning, error) {
 		return nil, warnings, err
 	}
 
-	chunks := rag.ChunkDocument(doc)
+	chunks := chunkWithAttachments(doc, rag.ChunkDocument)
 	return chunks, warnings, nil
 }
 
+// ChunksContext is like Chunks but stops when ctx is canceled.
+// It is shorthand for e.WithContext(ctx).Chunks().
+func (e *Extractor) ChunksContext(ctx context.Context) (*rag.ChunkCollection, []Warning, error) {
+	return e.WithContext(ctx).Chunks()
+}
+
 // ChunksWithConfig extracts content and returns semantic chunks using custom configuration.
 // This allows fine-tuning of chunk sizes, overlap, and other parameters.
 // This is a terminal operation that closes the underlying reader.
@@ -1831,7 +2112,9 @@ func (e *Extractor) ChunksWithConfig(config rag.ChunkerConfig, sizeConfig rag.Si
 		return nil, warnings, err
 	}
 
-	chunks := rag.ChunkDocumentWithConfig(doc, config, sizeConfig)
+	chunks := chunkWithAttachments(doc, func(d *model.Document) *rag.ChunkCollection {
+		return rag.ChunkDocumentWithConfig(d, config, sizeConfig)
+	})
 	return chunks, warnings, nil
 }
 
@@ -1839,10 +2122,33 @@ func (e *Extractor) ChunksWithConfig(config rag.ChunkerConfig, sizeConfig rag.Si
 // Internal helpers
 // ============================================================================
 
-// validateFormat checks that the file's actual content matches the format
-// determined from the file extension. Returns an error if they don't match.
-func (e *Extractor) validateFormat() error {
-	// Open the file to check its content
+// context returns the extractor's context, or context.Background() when none
+// was set.
+func (e *Extractor) context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// ctxErr returns a non-nil error once the extractor's context is done. It is
+// checked between pages so long extractions stop promptly.
+func (e *Extractor) ctxErr() error {
+	if e.ctx == nil {
+		return nil
+	}
+	if err := e.ctx.Err(); err != nil {
+		return fmt.Errorf("extraction canceled: %w", err)
+	}
+	return nil
+}
+
+// resolveFormat settles the format of a file-backed source by sniffing its
+// magic bytes and, for ZIP containers, the archive manifest. Content wins over
+// the extension: a missing or unrecognized extension is replaced silently, and
+// an extension that disagrees with the content adds a WarningFormatMismatch.
+// When the content is inconclusive the extension-based format is kept.
+func (e *Extractor) resolveFormat() error {
 	f, err := os.Open(e.filename)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
@@ -1855,23 +2161,21 @@ func (e *Extractor) validateFormat() error {
 		return fmt.Errorf("failed to stat file: %w", err)
 	}
 
-	// Detect format from file content
+	// A read or ZIP error leaves the content inconclusive; the format's own
+	// reader reports a better error than the sniffer would.
 	detected, err := format.DetectFromReader(f, info.Size())
-	if err != nil {
-		return fmt.Errorf("failed to detect file format: %w", err)
-	}
-
-	// If we couldn't detect the format from content, allow the extension-based format
-	// This handles cases where magic byte detection isn't definitive
-	if detected == format.Unknown {
-		return nil
-	}
-
-	// Check if detected format matches expected format
-	if detected != e.format {
-		return fmt.Errorf("file format mismatch: extension indicates %s but content is %s", e.format, detected)
+	if err == nil && detected != format.Unknown && detected != e.format {
+		if e.format != format.Unknown {
+			e.warnings = append(e.warnings, Warning{
+				Code: WarningFormatMismatch,
+				Message: fmt.Sprintf("file extension indicates %s but content is %s; reading as %s",
+					e.format, detected, detected),
+			})
+		}
+		e.format = detected
 	}
 
+	e.formatResolved = true
 	return nil
 }
 
@@ -1884,7 +2188,7 @@ func (e *Extractor) resolvePages() ([]int, error) {
 	}
 
 	// If no pages specified, use all pages
-	if len(e.options.pages) == 0 {
+	if len(e.options.pages) == 0 && len(e.options.pageLabels) == 0 {
 		pageIndices := make([]int, pageCount)
 		for i := 0; i < pageCount; i++ {
 			pageIndices[i] = i
@@ -1906,11 +2210,70 @@ func (e *Extractor) resolvePages() ([]int, error) {
 		}
 	}
 
+	if len(e.options.pageLabels) > 0 {
+		labels := e.loadPageLabels()
+		for _, label := range e.options.pageLabels {
+			index := -1
+			for i, l := range labels {
+				if l == label {
+					index = i
+					break
+				}
+			}
+			if index < 0 {
+				return nil, fmt.Errorf("page label %q not found", label)
+			}
+			if !seen[index] {
+				seen[index] = true
+				pageIndices = append(pageIndices, index)
+			}
+		}
+	}
+
 	// Sort pages in order
 	sort.Ints(pageIndices)
 	return pageIndices, nil
 }
 
+// loadPageLabels returns the printed label of each page. Pages of a
+// document without page labels, or whose labels cannot be read, are
+// labeled with their decimal page numbers.
+func (e *Extractor) loadPageLabels() []string {
+	if labels := e.definedPageLabels(); labels != nil {
+		return labels
+	}
+	pageCount, err := e.reader.PageCount()
+	if err != nil {
+		return nil
+	}
+	labels := make([]string, pageCount)
+	for i := range labels {
+		labels[i] = strconv.Itoa(i + 1)
+	}
+	return labels
+}
+
+// definedPageLabels returns the page labels defined by the document, or nil
+// if it defines none
+func (e *Extractor) definedPageLabels() []string {
+	if e.reader == nil {
+		return nil
+	}
+	labels, err := e.reader.PageLabels()
+	if err != nil {
+		return nil
+	}
+	return labels
+}
+
+// pageLabel returns the label of a page index, or "" if there is none
+func pageLabel(labels []string, pageNum int) string {
+	if pageNum < 0 || pageNum >= len(labels) {
+		return ""
+	}
+	return labels[pageNum]
+}
+
 // detectHeaderFooter runs header/footer detection across multiple pages.
 func (e *Extractor) detectHeaderFooter(allPages []extractedPage) *layout.HeaderFooterResult {
 	// Convert to layout.PageFragments format
@@ -1930,8 +2293,95 @@ func (e *Extractor) detectHeaderFooter(allPages []extractedPage) *layout.HeaderF
 	return detector.Detect(pageFragments)
 }
 
+// pageFragments extracts a page's text fragments for the terminal
+// operations. Text that cannot be seen is dropped, with a warning, unless
+// VisibleTextOnly(false) is set; with InlineFormFields, the values of the
+// form fields on the page are added.
+func (e *Extractor) pageFragments(page *pages.Page, pageNum int) ([]text.TextFragment, error) {
+	fragments, hidden, err := e.filterPageFragments(page, pageNum)
+	if err != nil {
+		return nil, err
+	}
+	if hidden > 0 {
+		e.warnings = append(e.warnings, Warning{
+			Code:    WarningHiddenText,
+			Message: fmt.Sprintf("Page %d: Discarded %d hidden text fragment(s)", pageNum+1, hidden),
+		})
+	}
+	return fragments, nil
+}
+
+// filterPageFragments is pageFragments without the warning, for pages read
+// only to detect headers and footers; it returns the number of hidden
+// fragments dropped.
+func (e *Extractor) filterPageFragments(page *pages.Page, pageNum int) ([]text.TextFragment, int, error) {
+	fragments, err := e.reader.ExtractTextFragments(page)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var hidden int
+	if !e.options.keepHiddenText {
+		fragments, hidden = visibleFragments(fragments)
+	}
+
+	if e.options.inlineFormFields {
+		fragments = append(fragments, e.pageFormFragments(pageNum)...)
+	}
+	return fragments, hidden, nil
+}
+
+// visibleFragments returns the fragments to keep and the number dropped.
+// Text that is not visible is dropped, except text drawn in an invisible
+// rendering mode that no visible text covers: the text layer of a
+// searchable scan, drawn over the page image, is the only text the page has.
+func visibleFragments(fragments []text.TextFragment) ([]text.TextFragment, int) {
+	var shown []model.BBox
+	for _, f := range fragments {
+		if f.Visible {
+			shown = append(shown, fragmentBox(f))
+		}
+	}
+
+	kept := fragments[:0:0]
+	for _, f := range fragments {
+		invisible := f.RenderMode == 3 || f.RenderMode == 7
+		if f.Visible || invisible && !coveredBy(fragmentBox(f), shown) {
+			kept = append(kept, f)
+		}
+	}
+	return kept, len(fragments) - len(kept)
+}
+
+// fragmentBox returns a fragment's bounding box
+func fragmentBox(f text.TextFragment) model.BBox {
+	return model.BBox{X: f.X, Y: f.Y, Width: f.Width, Height: f.Height}
+}
+
+// coveredBy reports whether boxes cover at least half of box's area, or
+// its center when it has no area
+func coveredBy(box model.BBox, boxes []model.BBox) bool {
+	area := box.Area()
+	if area <= 0 {
+		cx, cy := box.X+box.Width/2, box.Y+box.Height/2
+		for _, b := range boxes {
+			if cx >= b.X && cx <= b.X+b.Width && cy >= b.Y && cy <= b.Y+b.Height {
+				return true
+			}
+		}
+		return false
+	}
+	covered := 0.0
+	for _, b := range boxes {
+		covered += box.Intersection(b).Area()
+	}
+	return covered*2 >= area
+}
+
 // collectAllPages collects fragment data from ALL pages in the document.
 // This is needed for header/footer detection which requires multi-page patterns.
+// The fragments are filtered like the requested pages' (see pageFragments),
+// so text they leave out doesn't count toward a header or footer.
 func (e *Extractor) collectAllPages() ([]extractedPage, error) {
 	pageCount, err := e.reader.PageCount()
 	if err != nil {
@@ -1940,12 +2390,15 @@ func (e *Extractor) collectAllPages() ([]extractedPage, error) {
 
 	allPages := make([]extractedPage, 0, pageCount)
 	for i := 0; i < pageCount; i++ {
+		if err := e.ctxErr(); err != nil {
+			return nil, err
+		}
 		page, err := e.reader.GetPage(i)
 		if err != nil {
 			continue // Skip pages that can't be read
 		}
 
-		fragments, err := e.reader.ExtractTextFragments(page)
+		fragments, _, err := e.filterPageFragments(page, i)
 		if err != nil {
 			continue // Skip pages that can't be extracted
 		}
@@ -2351,29 +2804,42 @@ func mergeNativeAndOCR(fragments []text.TextFragment, ocrText string) string {
 const maxOCRWorkers = 4
 
 // preparedImage is a page image rendered to PNG and ready for OCR, with the
-// effective DPI to hint Tesseract (0 = unknown).
+// effective DPI to hint Tesseract (0 = unknown) and the matrix mapping its
+// pixels to the page's user space (zero when the placement is unknown).
 type preparedImage struct {
-	png []byte
-	dpi int
+	png    []byte
+	dpi    int
+	toPage model.Matrix
+}
+
+// ocrResult is what OCR found on a page: its text, its words as fragments
+// positioned on the page, and the preprocessing its images went through.
+type ocrResult struct {
+	text         string
+	fragments    []text.TextFragment
+	preprocessed []string // preprocessing steps applied, in order
+	err          error    // first error the engine returned for the page's images
 }
 
 // ocrJob is one page's prepared images awaiting OCR, identified by index.
 type ocrJob struct {
-	index  int
-	images []preparedImage
+	index   int
+	pageNum int // 1-based page number, for warnings
+	images  []preparedImage
 }
 
 // prepareOCRImages prepares a page's image(s) for OCR. It first tries a
 // full-page render (pageNum is the 1-based physical page), which captures
 // vector-outlined text and vector artwork that extracting embedded images
-// misses; when no renderer is available it falls back to extracting and
-// pre-processing the page's embedded images. It must run on the single reader
-// goroutine (the embedded-image path seeks the file); the returned PNGs are then
-// safe to OCR concurrently. Returns nil when neither path yields an image.
+// misses; when the page cannot be rendered it falls back to extracting and
+// pre-processing the page's embedded images, inline images included. It
+// must run on the single reader goroutine (the embedded-image path seeks the
+// file); the returned PNGs are then safe to OCR concurrently. Returns nil
+// when neither path yields an image.
 func (e *Extractor) prepareOCRImages(page *pages.Page, pageNum int) []preparedImage {
 	// Preferred: rasterize the whole page so OCR sees everything on it. Falls
 	// through to embedded-image extraction when the renderer isn't available.
-	if rendered := e.renderPageForOCR(pageNum); len(rendered) > 0 {
+	if rendered := e.renderPageForOCR(page, pageNum); len(rendered) > 0 {
 		return rendered
 	}
 
@@ -2386,6 +2852,11 @@ func (e *Extractor) prepareOCRImages(page *pages.Page, pageNum int) []preparedIm
 	pageW, _ := page.Width()
 	pageH, _ := page.Height()
 
+	// Where the images are drawn, to place the words OCR finds on the page
+	placed, _ := e.reader.ExtractPlacedImages(page)
+	used := make([]bool, len(placed))
+	pageBox, _ := page.MediaBox()
+
 	var prepared []preparedImage
 	for _, img := range images {
 		dpi := estimateImageDPI(img.Width, img.Height, pageW, pageH)
@@ -2398,17 +2869,30 @@ func (e *Extractor) prepareOCRImages(page *pages.Page, pageNum int) []preparedIm
 		if dpi >= minPlausibleDPI {
 			eff = int(float64(dpi)*scale + 0.5)
 		}
-		prepared = append(prepared, preparedImage{png: png, dpi: eff})
+		ctm := imagePlacement(img, placed, used, pageBox)
+		prepared = append(prepared, preparedImage{
+			png:    png,
+			dpi:    eff,
+			toPage: embeddedImageToPage(img, ctm, rotation, png),
+		})
 	}
 	return prepared
 }
 
-// runOCRJobs runs Tesseract over the prepared page images concurrently and
-// returns the recognized text keyed by job index. Each worker uses its own OCR
-// client (Tesseract is not safe to share across goroutines). Returns an empty
-// map when OCR is unavailable (e.g. built without -tags ocr).
-func (e *Extractor) runOCRJobs(jobs []ocrJob) map[int]string {
-	results := make(map[int]string, len(jobs))
+// runOCRJobs runs the OCR engine over the prepared page images concurrently
+// and returns what it recognized keyed by job index: the text, and the words
+// as fragments positioned on the page. The engine is the one
+// set with OCREngine, or else Tesseract (which keeps a client per concurrent
+// recognition, since a client can't be shared across goroutines). Returns an
+// empty map when OCR is unavailable (e.g. no engine set and built without
+// -tags ocr). The first error the engine returns for each page is reported as
+// a WarningOCRFailed warning; the page's other images are still recognized.
+//
+// When the extractor's context is canceled, no further jobs are dispatched and
+// workers skip their remaining images; the partial results are returned and
+// the caller reports the context error.
+func (e *Extractor) runOCRJobs(jobs []ocrJob) map[int]ocrResult {
+	results := make(map[int]ocrResult, len(jobs))
 	if len(jobs) == 0 {
 		return results
 	}
@@ -2421,59 +2905,82 @@ func (e *Extractor) runOCRJobs(jobs []ocrJob) map[int]string {
 		workers = len(jobs)
 	}
 
-	// Pre-create one client per worker; if none can start, OCR is unavailable.
-	var clients []*ocr.Client
-	for i := 0; i < workers; i++ {
-		c, err := ocr.New()
+	// Without a configured engine, use Tesseract; if it can't start, OCR is
+	// unavailable.
+	engine := e.options.ocrEngine
+	if engine == nil {
+		t, err := ocr.NewTesseractEngine()
 		if err != nil {
-			break
-		}
-		if e.options.ocrLanguage != "" {
-			_ = c.SetLanguage(e.options.ocrLanguage)
+			return results
 		}
-		if e.options.ocrPSMSet {
-			_ = c.SetPageSegMode(e.options.ocrPSM)
-		}
-		clients = append(clients, c)
+		defer t.Close()
+		engine = t
 	}
-	if len(clients) == 0 {
-		return results
+	opts := ocr.Options{
+		Language:       e.options.ocrLanguage,
+		PageSegMode:    e.options.ocrPSM,
+		PageSegModeSet: e.options.ocrPSMSet,
 	}
-	defer func() {
-		for _, c := range clients {
-			c.Close()
-		}
-	}()
 
+	ctx := e.context()
 	jobCh := make(chan ocrJob)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
-	for _, c := range clients {
+	for i := 0; i < workers; i++ {
 		wg.Add(1)
-		go func(client *ocr.Client) {
+		go func() {
 			defer wg.Done()
 			for job := range jobCh {
 				var texts []string
+				var res ocrResult
 				for _, pi := range job.images {
-					if pi.dpi > 0 {
-						_ = client.SetVariable("user_defined_dpi", strconv.Itoa(pi.dpi))
+					if ctx.Err() != nil {
+						break
+					}
+					pi, applied := e.preprocessForOCR(ctx, engine, pi)
+					res.preprocessed = mergeSteps(res.preprocessed, applied)
+					imgOpts := opts
+					imgOpts.DPI = pi.dpi
+					words, err := engine.Recognize(ctx, pi.png, imgOpts)
+					if err != nil {
+						if res.err == nil {
+							res.err = err
+						}
+						continue
 					}
-					if t, err := client.RecognizeImage(pi.png); err == nil && t != "" {
+					if t := ocr.Text(words); t != "" {
 						texts = append(texts, t)
 					}
+					res.fragments = append(res.fragments, ocrFragments(words, pi.toPage)...)
 				}
-				text := strings.Join(texts, "\n")
+				res.text = strings.Join(texts, "\n")
 				mu.Lock()
-				results[job.index] = text
+				results[job.index] = res
 				mu.Unlock()
 			}
-		}(c)
+		}()
 	}
+dispatch:
 	for _, job := range jobs {
-		jobCh <- job
+		select {
+		case jobCh <- job:
+		case <-ctx.Done():
+			break dispatch
+		}
 	}
 	close(jobCh)
 	wg.Wait()
+
+	if ctx.Err() == nil {
+		for _, job := range jobs {
+			if err := results[job.index].err; err != nil {
+				e.warnings = append(e.warnings, Warning{
+					Code:    WarningOCRFailed,
+					Message: fmt.Sprintf("Page %d: OCR failed: %v", job.pageNum, err),
+				})
+			}
+		}
+	}
 	return results
 }
 
@@ -0,0 +1,478 @@
+package font
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Top DICT operators used for Unicode recovery
+const (
+	cffOpCharset     = 15
+	cffOpEncoding    = 16
+	cffOpCharStrings = 17
+	cffOpROS         = 1230 // 12 30: marks a CID-keyed font
+)
+
+// DICT operators used to read glyph outlines
+const (
+	cffOpPrivate  = 18
+	cffOpSubrs    = 19   // Private DICT: local subroutines, relative to the Private DICT
+	cffOpFDArray  = 1236 // 12 36
+	cffOpFDSelect = 1237 // 12 37
+)
+
+// cffFont holds the parts of a Compact Font Format (Type1C / CIDFontType0C)
+// program needed to name glyphs and draw their outlines
+type cffFont struct {
+	name      string
+	numGlyphs int
+	isCID     bool
+
+	// charset maps glyph ID to a string ID (SID), or to a CID for
+	// CID-keyed fonts
+	charset []uint16
+
+	// encoding maps single-byte codes to glyph IDs for fonts with a
+	// custom built-in encoding; nil for the predefined encodings
+	encoding map[byte]uint16
+
+	strings [][]byte // String INDEX, for SIDs >= 391
+
+	// Glyph programs (Type 2 charstrings) and the subroutines they call
+	charStrings [][]byte
+	globalSubrs [][]byte
+	localSubrs  [][]byte   // Subroutines of the Private DICT of a name-keyed font
+	fdSubrs     [][][]byte // Subroutines of each Font DICT of a CID-keyed font
+	fdSelect    []byte     // Font DICT of each glyph of a CID-keyed font
+}
+
+// parseCFF parses a CFF font program, reading the first font in its FontSet
+func parseCFF(data []byte) (*cffFont, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("CFF data too short")
+	}
+	if data[0] != 1 {
+		return nil, fmt.Errorf("unsupported CFF major version %d", data[0])
+	}
+
+	pos := int(data[2]) // hdrSize
+
+	names, pos, err := readCFFIndex(data, pos)
+	if err != nil {
+		return nil, fmt.Errorf("name index: %w", err)
+	}
+	topDicts, pos, err := readCFFIndex(data, pos)
+	if err != nil {
+		return nil, fmt.Errorf("top dict index: %w", err)
+	}
+	strs, pos, err := readCFFIndex(data, pos)
+	if err != nil {
+		return nil, fmt.Errorf("string index: %w", err)
+	}
+	gsubrs, _, err := readCFFIndex(data, pos)
+	if err != nil {
+		return nil, fmt.Errorf("global subr index: %w", err)
+	}
+	if len(topDicts) == 0 {
+		return nil, fmt.Errorf("CFF has no top dict")
+	}
+
+	cff := &cffFont{strings: strs, globalSubrs: gsubrs}
+	if len(names) > 0 {
+		cff.name = string(names[0])
+	}
+
+	top, err := parseCFFDict(topDicts[0])
+	if err != nil {
+		return nil, fmt.Errorf("top dict: %w", err)
+	}
+	_, cff.isCID = top[cffOpROS]
+
+	// The CharStrings INDEX count is the number of glyphs
+	charStrings, ok := top[cffOpCharStrings]
+	if !ok || len(charStrings) == 0 {
+		return nil, fmt.Errorf("CFF has no CharStrings")
+	}
+	glyphs, _, err := readCFFIndex(data, int(charStrings[0]))
+	if err != nil {
+		return nil, fmt.Errorf("charstrings index: %w", err)
+	}
+	cff.numGlyphs = len(glyphs)
+	cff.charStrings = glyphs
+
+	charsetOffset := 0 // ISOAdobe
+	if v, ok := top[cffOpCharset]; ok && len(v) > 0 {
+		charsetOffset = int(v[0])
+	}
+	if err := cff.parseCharset(data, charsetOffset); err != nil {
+		return nil, fmt.Errorf("charset: %w", err)
+	}
+
+	// Subroutines are read on a best-effort basis: glyphs that call a
+	// missing subroutine are drawn incompletely
+	if cff.isCID {
+		cff.parseFDArray(data, top)
+	} else if private, ok := top[cffOpPrivate]; ok && len(private) == 2 {
+		cff.localSubrs = privateSubrs(data, int(private[0]), int(private[1]))
+	}
+
+	// CID-keyed fonts have no encoding; codes are CIDs
+	if !cff.isCID {
+		if v, ok := top[cffOpEncoding]; ok && len(v) > 0 && v[0] > 1 {
+			if err := cff.parseEncoding(data, int(v[0])); err != nil {
+				return nil, fmt.Errorf("encoding: %w", err)
+			}
+		}
+	}
+
+	return cff, nil
+}
+
+// privateSubrs returns the local subroutines of the Private DICT of the
+// given size at offset
+func privateSubrs(data []byte, size, offset int) [][]byte {
+	if size <= 0 || offset < 0 || offset+size > len(data) {
+		return nil
+	}
+	private, err := parseCFFDict(data[offset : offset+size])
+	if err != nil {
+		return nil
+	}
+	subrs, ok := private[cffOpSubrs]
+	if !ok || len(subrs) == 0 {
+		return nil
+	}
+	items, _, err := readCFFIndex(data, offset+int(subrs[0]))
+	if err != nil {
+		return nil
+	}
+	return items
+}
+
+// parseFDArray reads the local subroutines of each Font DICT of a CID-keyed
+// font and the FDSelect table that assigns glyphs to Font DICTs
+func (cff *cffFont) parseFDArray(data []byte, top map[int][]float64) {
+	if v, ok := top[cffOpFDArray]; ok && len(v) > 0 {
+		fontDicts, _, err := readCFFIndex(data, int(v[0]))
+		if err != nil {
+			return
+		}
+		cff.fdSubrs = make([][][]byte, len(fontDicts))
+		for i, fd := range fontDicts {
+			dict, err := parseCFFDict(fd)
+			if err != nil {
+				continue
+			}
+			if private, ok := dict[cffOpPrivate]; ok && len(private) == 2 {
+				cff.fdSubrs[i] = privateSubrs(data, int(private[0]), int(private[1]))
+			}
+		}
+	}
+
+	v, ok := top[cffOpFDSelect]
+	if !ok || len(v) == 0 || int(v[0]) >= len(data) || v[0] < 0 {
+		return
+	}
+	pos := int(v[0])
+	cff.fdSelect = make([]byte, cff.numGlyphs)
+	switch data[pos] {
+	case 0:
+		if pos+1+cff.numGlyphs <= len(data) {
+			copy(cff.fdSelect, data[pos+1:])
+		}
+	case 3:
+		if pos+3 > len(data) {
+			return
+		}
+		nRanges := int(binary.BigEndian.Uint16(data[pos+1:]))
+		pos += 3
+		for i := 0; i < nRanges && pos+5 <= len(data); i++ {
+			first := int(binary.BigEndian.Uint16(data[pos:]))
+			fd := data[pos+2]
+			next := int(binary.BigEndian.Uint16(data[pos+3:]))
+			for gid := first; gid < next && gid < cff.numGlyphs; gid++ {
+				cff.fdSelect[gid] = fd
+			}
+			pos += 3
+		}
+	}
+}
+
+// parseCharset reads the glyph ID to SID (or CID) mapping. Offsets 0-2 select
+// the predefined ISOAdobe, Expert and ExpertSubset charsets.
+func (cff *cffFont) parseCharset(data []byte, offset int) error {
+	cff.charset = make([]uint16, cff.numGlyphs)
+
+	if offset <= 2 {
+		if offset == 0 {
+			// ISOAdobe: glyph ID i has SID i
+			for gid := range cff.charset {
+				if gid <= 228 {
+					cff.charset[gid] = uint16(gid)
+				}
+			}
+		}
+		// The Expert charsets name small caps and oldstyle figures only;
+		// they are left unmapped
+		return nil
+	}
+
+	if offset >= len(data) {
+		return fmt.Errorf("charset offset out of range")
+	}
+
+	format := data[offset]
+	pos := offset + 1
+	gid := 1 // glyph 0 is always .notdef
+	switch format {
+	case 0:
+		for ; gid < cff.numGlyphs; gid++ {
+			if pos+2 > len(data) {
+				return fmt.Errorf("charset format 0 truncated")
+			}
+			cff.charset[gid] = binary.BigEndian.Uint16(data[pos:])
+			pos += 2
+		}
+	case 1, 2:
+		for gid < cff.numGlyphs {
+			size := 3
+			if format == 2 {
+				size = 4
+			}
+			if pos+size > len(data) {
+				return fmt.Errorf("charset format %d truncated", format)
+			}
+			first := binary.BigEndian.Uint16(data[pos:])
+			nLeft := int(data[pos+2])
+			if format == 2 {
+				nLeft = int(binary.BigEndian.Uint16(data[pos+2:]))
+			}
+			pos += size
+			for i := 0; i <= nLeft && gid < cff.numGlyphs; i++ {
+				cff.charset[gid] = first + uint16(i)
+				gid++
+			}
+		}
+	default:
+		return fmt.Errorf("unknown charset format %d", format)
+	}
+
+	return nil
+}
+
+// parseEncoding reads a custom built-in encoding, mapping codes to glyph IDs.
+// Supplements map further codes to glyphs by SID.
+func (cff *cffFont) parseEncoding(data []byte, offset int) error {
+	if offset >= len(data) {
+		return fmt.Errorf("encoding offset out of range")
+	}
+
+	cff.encoding = make(map[byte]uint16)
+	format := data[offset]
+	pos := offset + 1
+
+	switch format & 0x7F {
+	case 0:
+		if pos >= len(data) {
+			return fmt.Errorf("encoding format 0 truncated")
+		}
+		nCodes := int(data[pos])
+		pos++
+		if pos+nCodes > len(data) {
+			return fmt.Errorf("encoding format 0 truncated")
+		}
+		for i := 0; i < nCodes; i++ {
+			cff.encoding[data[pos+i]] = uint16(i + 1)
+		}
+		pos += nCodes
+	case 1:
+		if pos >= len(data) {
+			return fmt.Errorf("encoding format 1 truncated")
+		}
+		nRanges := int(data[pos])
+		pos++
+		gid := 1
+		for i := 0; i < nRanges; i++ {
+			if pos+2 > len(data) {
+				return fmt.Errorf("encoding format 1 truncated")
+			}
+			first := int(data[pos])
+			nLeft := int(data[pos+1])
+			pos += 2
+			for j := 0; j <= nLeft && first+j <= 255; j++ {
+				cff.encoding[byte(first+j)] = uint16(gid)
+				gid++
+			}
+		}
+	default:
+		return fmt.Errorf("unknown encoding format %d", format&0x7F)
+	}
+
+	// Supplements: additional codes for glyphs identified by SID
+	if format&0x80 != 0 && pos < len(data) {
+		nSups := int(data[pos])
+		pos++
+		for i := 0; i < nSups && pos+3 <= len(data); i++ {
+			code := data[pos]
+			sid := binary.BigEndian.Uint16(data[pos+1:])
+			pos += 3
+			for gid, s := range cff.charset {
+				if s == sid {
+					cff.encoding[code] = uint16(gid)
+					break
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// glyphName returns the name of a glyph, or "" for CID-keyed fonts and
+// unknown glyphs
+func (cff *cffFont) glyphName(gid int) string {
+	if cff.isCID || gid <= 0 || gid >= len(cff.charset) {
+		return ""
+	}
+	return cff.sidString(int(cff.charset[gid]))
+}
+
+// sidString resolves a string ID against the standard strings and the
+// font's String INDEX
+func (cff *cffFont) sidString(sid int) string {
+	if sid < len(cffStandardStrings) {
+		return cffStandardStrings[sid]
+	}
+	if i := sid - len(cffStandardStrings); i < len(cff.strings) {
+		return string(cff.strings[i])
+	}
+	return ""
+}
+
+// cidToGID returns the mapping from CID to glyph ID for a CID-keyed font
+func (cff *cffFont) cidToGID() map[uint16]uint16 {
+	mapping := make(map[uint16]uint16, len(cff.charset))
+	for gid, cid := range cff.charset {
+		if gid > 0 {
+			mapping[cid] = uint16(gid)
+		}
+	}
+	return mapping
+}
+
+// readCFFIndex reads an INDEX structure at pos, returning its items and the
+// position just past it
+func readCFFIndex(data []byte, pos int) ([][]byte, int, error) {
+	if pos < 0 || pos+2 > len(data) {
+		return nil, pos, fmt.Errorf("index out of range")
+	}
+
+	count := int(binary.BigEndian.Uint16(data[pos:]))
+	pos += 2
+	if count == 0 {
+		return nil, pos, nil
+	}
+
+	if pos >= len(data) {
+		return nil, pos, fmt.Errorf("index truncated")
+	}
+	offSize := int(data[pos])
+	pos++
+	if offSize < 1 || offSize > 4 {
+		return nil, pos, fmt.Errorf("invalid offset size %d", offSize)
+	}
+	if pos+(count+1)*offSize > len(data) {
+		return nil, pos, fmt.Errorf("index offsets truncated")
+	}
+
+	offsets := make([]int, count+1)
+	for i := range offsets {
+		v := 0
+		for j := 0; j < offSize; j++ {
+			v = v<<8 | int(data[pos+i*offSize+j])
+		}
+		offsets[i] = v
+	}
+	pos += (count + 1) * offSize
+
+	// Offsets are 1-based, relative to the byte before the object data
+	base := pos - 1
+	items := make([][]byte, count)
+	for i := 0; i < count; i++ {
+		start, end := base+offsets[i], base+offsets[i+1]
+		if start < pos || end < start || end > len(data) {
+			return nil, pos, fmt.Errorf("index item %d out of range", i)
+		}
+		items[i] = data[start:end]
+	}
+
+	return items, base + offsets[count], nil
+}
+
+// parseCFFDict parses a DICT into operands keyed by operator. Two-byte
+// operators (12 x) are keyed as 1200+x.
+func parseCFFDict(data []byte) (map[int][]float64, error) {
+	dict := make(map[int][]float64)
+	var operands []float64
+
+	for pos := 0; pos < len(data); {
+		b0 := data[pos]
+		switch {
+		case b0 <= 21:
+			op := int(b0)
+			pos++
+			if b0 == 12 {
+				if pos >= len(data) {
+					return nil, fmt.Errorf("truncated escape operator")
+				}
+				op = 1200 + int(data[pos])
+				pos++
+			}
+			dict[op] = operands
+			operands = nil
+		case b0 == 28:
+			if pos+3 > len(data) {
+				return nil, fmt.Errorf("truncated integer")
+			}
+			operands = append(operands, float64(int16(binary.BigEndian.Uint16(data[pos+1:]))))
+			pos += 3
+		case b0 == 29:
+			if pos+5 > len(data) {
+				return nil, fmt.Errorf("truncated integer")
+			}
+			operands = append(operands, float64(int32(binary.BigEndian.Uint32(data[pos+1:]))))
+			pos += 5
+		case b0 == 30:
+			// Real number: nibbles until 0xf; the value is not needed, so
+			// it is skipped and recorded as zero
+			pos++
+			for pos < len(data) {
+				b := data[pos]
+				pos++
+				if b&0x0F == 0x0F || b>>4 == 0x0F {
+					break
+				}
+			}
+			operands = append(operands, 0)
+		case b0 >= 32 && b0 <= 246:
+			operands = append(operands, float64(int(b0)-139))
+			pos++
+		case b0 >= 247 && b0 <= 250:
+			if pos+2 > len(data) {
+				return nil, fmt.Errorf("truncated integer")
+			}
+			operands = append(operands, float64((int(b0)-247)*256+int(data[pos+1])+108))
+			pos += 2
+		case b0 >= 251 && b0 <= 254:
+			if pos+2 > len(data) {
+				return nil, fmt.Errorf("truncated integer")
+			}
+			operands = append(operands, float64(-(int(b0)-251)*256-int(data[pos+1])-108))
+			pos += 2
+		default:
+			return nil, fmt.Errorf("invalid DICT byte %d", b0)
+		}
+	}
+
+	return dict, nil
+}
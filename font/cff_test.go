@@ -0,0 +1,234 @@
+package font
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// cffIndex encodes an INDEX with 2-byte offsets
+func cffIndex(items ...[]byte) []byte {
+	if len(items) == 0 {
+		return []byte{0, 0}
+	}
+
+	data := make([]byte, 3+2*(len(items)+1))
+	binary.BigEndian.PutUint16(data[0:], uint16(len(items)))
+	data[2] = 2
+	offset := 1
+	for i, item := range items {
+		binary.BigEndian.PutUint16(data[3+i*2:], uint16(offset))
+		offset += len(item)
+	}
+	binary.BigEndian.PutUint16(data[3+len(items)*2:], uint16(offset))
+	for _, item := range items {
+		data = append(data, item...)
+	}
+	return data
+}
+
+// cffInt encodes a DICT integer operand in the fixed-size 5-byte form
+func cffInt(v int) []byte {
+	b := []byte{29, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(b[1:], uint32(int32(v)))
+	return b
+}
+
+// testCFF describes a CFF font for buildCFF
+type testCFF struct {
+	numGlyphs int
+	strings   [][]byte // String INDEX entries (SID 391 onwards)
+	charset   []byte   // charset data, or nil for ISOAdobe
+	encoding  []byte   // encoding data, or nil for StandardEncoding
+	cid       bool     // add an ROS operator
+}
+
+// buildCFF assembles a CFF program. The Top DICT uses fixed-size operands, so
+// offsets can be computed before the DICT is written.
+func buildCFF(f testCFF) []byte {
+	topDict := func(charStrings, charset, encoding int) []byte {
+		var d []byte
+		if f.cid {
+			d = append(d, cffInt(391)...)
+			d = append(d, cffInt(392)...)
+			d = append(d, cffInt(0)...)
+			d = append(d, 12, 30)
+		}
+		if f.charset != nil {
+			d = append(d, cffInt(charset)...)
+			d = append(d, cffOpCharset)
+		}
+		if f.encoding != nil {
+			d = append(d, cffInt(encoding)...)
+			d = append(d, cffOpEncoding)
+		}
+		d = append(d, cffInt(charStrings)...)
+		d = append(d, cffOpCharStrings)
+		return d
+	}
+
+	glyphs := make([][]byte, f.numGlyphs)
+	for i := range glyphs {
+		glyphs[i] = []byte{14} // endchar
+	}
+
+	header := []byte{1, 0, 4, 2}
+	names := cffIndex([]byte("TestFont"))
+	strs := cffIndex(f.strings...)
+	gsubrs := cffIndex()
+
+	topLen := len(cffIndex(topDict(0, 0, 0)))
+	pos := len(header) + len(names) + topLen + len(strs) + len(gsubrs)
+	charStrings := pos
+	pos += len(cffIndex(glyphs...))
+	charset := pos
+	pos += len(f.charset)
+	encoding := pos
+
+	data := append([]byte{}, header...)
+	data = append(data, names...)
+	data = append(data, cffIndex(topDict(charStrings, charset, encoding))...)
+	data = append(data, strs...)
+	data = append(data, gsubrs...)
+	data = append(data, cffIndex(glyphs...)...)
+	data = append(data, f.charset...)
+	data = append(data, f.encoding...)
+	return data
+}
+
+// charsetFormat0 encodes SIDs (or CIDs) for glyphs 1..n
+func charsetFormat0(ids ...uint16) []byte {
+	data := []byte{0}
+	for _, id := range ids {
+		data = append(data, byte(id>>8), byte(id))
+	}
+	return data
+}
+
+func TestParseCFF_Charset(t *testing.T) {
+	// Glyphs: .notdef, A (SID 34), fi (SID 109), a custom name (SID 391)
+	data := buildCFF(testCFF{
+		numGlyphs: 4,
+		strings:   [][]byte{[]byte("uni2192")},
+		charset:   charsetFormat0(34, 109, 391),
+	})
+
+	cff, err := parseCFF(data)
+	if err != nil {
+		t.Fatalf("parseCFF failed: %v", err)
+	}
+
+	if cff.name != "TestFont" {
+		t.Errorf("Expected name 'TestFont', got '%s'", cff.name)
+	}
+	if cff.numGlyphs != 4 {
+		t.Errorf("Expected 4 glyphs, got %d", cff.numGlyphs)
+	}
+	if cff.isCID {
+		t.Error("Expected a name-keyed font")
+	}
+
+	expected := []string{"", "A", "fi", "uni2192"}
+	for gid, name := range expected {
+		if got := cff.glyphName(gid); got != name {
+			t.Errorf("Expected glyph %d named %q, got %q", gid, name, got)
+		}
+	}
+}
+
+func TestParseCFF_ISOAdobeCharset(t *testing.T) {
+	cff, err := parseCFF(buildCFF(testCFF{numGlyphs: 3}))
+	if err != nil {
+		t.Fatalf("parseCFF failed: %v", err)
+	}
+
+	if got := cff.glyphName(2); got != "exclam" {
+		t.Errorf("Expected glyph 2 named 'exclam', got %q", got)
+	}
+}
+
+func TestParseCFF_Encoding(t *testing.T) {
+	// Format 1 encoding: codes 0x41-0x42 -> glyphs 1-2, plus a supplement
+	// mapping code 0x61 to the glyph with SID 34
+	data := buildCFF(testCFF{
+		numGlyphs: 3,
+		charset:   charsetFormat0(34, 35),
+		encoding:  []byte{0x81, 1, 0x41, 1, 1, 0x61, 0, 34},
+	})
+
+	cff, err := parseCFF(data)
+	if err != nil {
+		t.Fatalf("parseCFF failed: %v", err)
+	}
+
+	if cff.encoding[0x41] != 1 || cff.encoding[0x42] != 2 {
+		t.Errorf("Unexpected encoding ranges: %v", cff.encoding)
+	}
+	if cff.encoding[0x61] != 1 {
+		t.Errorf("Expected supplement code 0x61 -> glyph 1, got %d", cff.encoding[0x61])
+	}
+}
+
+func TestParseCFF_CIDKeyed(t *testing.T) {
+	// Format 2 charset: glyphs 1-3 are CIDs 100-102
+	charset := []byte{2, 0, 100, 0, 2}
+	data := buildCFF(testCFF{
+		numGlyphs: 4,
+		strings:   [][]byte{[]byte("Adobe"), []byte("Identity")},
+		charset:   charset,
+		cid:       true,
+	})
+
+	cff, err := parseCFF(data)
+	if err != nil {
+		t.Fatalf("parseCFF failed: %v", err)
+	}
+
+	if !cff.isCID {
+		t.Fatal("Expected a CID-keyed font")
+	}
+	if got := cff.glyphName(1); got != "" {
+		t.Errorf("Expected no glyph names in a CID-keyed font, got %q", got)
+	}
+
+	mapping := cff.cidToGID()
+	if mapping[101] != 2 {
+		t.Errorf("Expected CID 101 -> glyph 2, got %d", mapping[101])
+	}
+}
+
+func TestParseCFFDict(t *testing.T) {
+	// 100 (1 byte), 1000 (2 bytes), -1000 (2 bytes), 10000 (28), real
+	data := []byte{
+		239,
+		250, 124,
+		254, 124,
+		28, 0x27, 0x10,
+		30, 0x1a, 0x5f,
+		cffOpCharset,
+	}
+
+	dict, err := parseCFFDict(data)
+	if err != nil {
+		t.Fatalf("parseCFFDict failed: %v", err)
+	}
+
+	expected := []float64{100, 1000, -1000, 10000, 0}
+	operands := dict[cffOpCharset]
+	if len(operands) != len(expected) {
+		t.Fatalf("Expected %d operands, got %v", len(expected), operands)
+	}
+	for i, v := range expected {
+		if operands[i] != v {
+			t.Errorf("Expected operand %d = %v, got %v", i, v, operands[i])
+		}
+	}
+}
+
+func TestParseCFF_Invalid(t *testing.T) {
+	if _, err := parseCFF([]byte{1, 0}); err == nil {
+		t.Error("Expected error for truncated data")
+	}
+	if _, err := parseCFF([]byte{2, 0, 4, 1, 0, 0}); err == nil {
+		t.Error("Expected error for CFF2 data")
+	}
+}
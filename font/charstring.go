@@ -0,0 +1,437 @@
+package font
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Limits for running charstrings
+const (
+	maxSubrDepth     = 10  // Subroutine nesting (the Type 2 limit)
+	maxCharStringOps = 1e5 // Operators run for one glyph
+	maxCharStack     = 96  // Argument stack depth (48 in Type 2, with slack)
+)
+
+// cffGlyphScale maps CFF glyph space to text space. The FontMatrix of
+// almost every CFF font is [0.001 0 0 0.001 0 0].
+const cffGlyphScale = 0.001
+
+// subrBias returns the bias added to subroutine numbers for a subroutine
+// INDEX of count entries
+func subrBias(count int) int {
+	switch {
+	case count < 1240:
+		return 107
+	case count < 33900:
+		return 1131
+	}
+	return 32768
+}
+
+// glyphIndex returns the glyph ID of a named glyph of a name-keyed font
+func (cff *cffFont) glyphIndex(name string) (int, bool) {
+	if cff.isCID || name == "" {
+		return 0, false
+	}
+	for gid := 1; gid < len(cff.charset); gid++ {
+		if cff.sidString(int(cff.charset[gid])) == name {
+			return gid, true
+		}
+	}
+	return 0, false
+}
+
+// outline returns a glyph's outline, or nil if the glyph does not exist
+func (cff *cffFont) outline(gid int) Outline {
+	if gid < 0 || gid >= len(cff.charStrings) {
+		return nil
+	}
+	b := newOutlineBuilder(cffGlyphScale)
+	cff.appendGlyph(b, gid, 0, 0, 0)
+	return b.outline()
+}
+
+// appendGlyph runs a glyph's charstring, offset by (dx, dy)
+func (cff *cffFont) appendGlyph(b *outlineBuilder, gid int, dx, dy float64, depth int) {
+	if gid < 0 || gid >= len(cff.charStrings) || depth > 1 {
+		return
+	}
+	local := cff.localSubrs
+	if cff.fdSubrs != nil && gid < len(cff.fdSelect) {
+		if fd := int(cff.fdSelect[gid]); fd < len(cff.fdSubrs) {
+			local = cff.fdSubrs[fd]
+		}
+	}
+	in := &type2Interp{cff: cff, b: b, local: local, x: dx, y: dy, dx: dx, dy: dy, depth: depth}
+	in.run(cff.charStrings[gid], 0)
+	b.closePath()
+}
+
+// type2Interp runs Type 2 charstrings (Adobe Technical Note #5177). Hints
+// are counted, to skip hint masks, but otherwise ignored.
+type type2Interp struct {
+	cff    *cffFont
+	b      *outlineBuilder
+	local  [][]byte
+	stack  []float64
+	x, y   float64
+	dx, dy float64 // Offset of an accent glyph
+	depth  int     // Accent nesting
+
+	stems     int
+	seenWidth bool // Whether the optional width argument has been consumed
+	ops       int
+	done      bool
+}
+
+// takeWidth drops the optional leading width argument of the first
+// stack-clearing operator when the stack holds one more argument than the
+// operator takes
+func (in *type2Interp) takeWidth(extra bool) {
+	if !in.seenWidth {
+		in.seenWidth = true
+		if extra && len(in.stack) > 0 {
+			in.stack = in.stack[1:]
+		}
+	}
+}
+
+func (in *type2Interp) moveTo(dx, dy float64) {
+	in.x += dx
+	in.y += dy
+	in.b.moveTo(in.x, in.y)
+}
+
+func (in *type2Interp) lineTo(dx, dy float64) {
+	in.x += dx
+	in.y += dy
+	in.b.lineTo(in.x, in.y)
+}
+
+func (in *type2Interp) curveTo(dxa, dya, dxb, dyb, dxc, dyc float64) {
+	x1, y1 := in.x+dxa, in.y+dya
+	x2, y2 := x1+dxb, y1+dyb
+	in.x, in.y = x2+dxc, y2+dyc
+	in.b.cubeTo(x1, y1, x2, y2, in.x, in.y)
+}
+
+// run interprets a charstring, calling subroutines recursively
+func (in *type2Interp) run(code []byte, level int) {
+	if level > maxSubrDepth {
+		in.done = true
+		return
+	}
+	for pos := 0; pos < len(code) && !in.done; {
+		in.ops++
+		if in.ops > maxCharStringOps {
+			in.done = true
+			return
+		}
+		b0 := code[pos]
+		switch {
+		case b0 == 28:
+			if pos+3 > len(code) {
+				in.done = true
+				return
+			}
+			in.push(float64(int16(binary.BigEndian.Uint16(code[pos+1:]))))
+			pos += 3
+			continue
+		case b0 >= 32 && b0 <= 246:
+			in.push(float64(int(b0) - 139))
+			pos++
+			continue
+		case b0 >= 247 && b0 <= 250:
+			if pos+2 > len(code) {
+				in.done = true
+				return
+			}
+			in.push(float64((int(b0)-247)*256 + int(code[pos+1]) + 108))
+			pos += 2
+			continue
+		case b0 >= 251 && b0 <= 254:
+			if pos+2 > len(code) {
+				in.done = true
+				return
+			}
+			in.push(float64(-(int(b0)-251)*256 - int(code[pos+1]) - 108))
+			pos += 2
+			continue
+		case b0 == 255:
+			if pos+5 > len(code) {
+				in.done = true
+				return
+			}
+			in.push(float64(int32(binary.BigEndian.Uint32(code[pos+1:]))) / 65536)
+			pos += 5
+			continue
+		}
+
+		pos++
+		op := int(b0)
+		if b0 == 12 {
+			if pos >= len(code) {
+				in.done = true
+				return
+			}
+			op = 1200 + int(code[pos])
+			pos++
+		}
+
+		switch op {
+		case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm
+			in.takeWidth(len(in.stack)%2 == 1)
+			in.stems += len(in.stack) / 2
+			in.stack = in.stack[:0]
+		case 19, 20: // hintmask, cntrmask
+			// Arguments before a mask are an implied vstem
+			in.takeWidth(len(in.stack)%2 == 1)
+			in.stems += len(in.stack) / 2
+			in.stack = in.stack[:0]
+			pos += (in.stems + 7) / 8
+		case 21: // rmoveto
+			in.takeWidth(len(in.stack) > 2)
+			if len(in.stack) >= 2 {
+				in.moveTo(in.stack[0], in.stack[1])
+			}
+			in.stack = in.stack[:0]
+		case 22: // hmoveto
+			in.takeWidth(len(in.stack) > 1)
+			if len(in.stack) >= 1 {
+				in.moveTo(in.stack[0], 0)
+			}
+			in.stack = in.stack[:0]
+		case 4: // vmoveto
+			in.takeWidth(len(in.stack) > 1)
+			if len(in.stack) >= 1 {
+				in.moveTo(0, in.stack[0])
+			}
+			in.stack = in.stack[:0]
+		case 5: // rlineto
+			for i := 0; i+1 < len(in.stack); i += 2 {
+				in.lineTo(in.stack[i], in.stack[i+1])
+			}
+			in.stack = in.stack[:0]
+		case 6, 7: // hlineto, vlineto
+			horizontal := op == 6
+			for _, d := range in.stack {
+				if horizontal {
+					in.lineTo(d, 0)
+				} else {
+					in.lineTo(0, d)
+				}
+				horizontal = !horizontal
+			}
+			in.stack = in.stack[:0]
+		case 8: // rrcurveto
+			for i := 0; i+5 < len(in.stack); i += 6 {
+				s := in.stack[i:]
+				in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			}
+			in.stack = in.stack[:0]
+		case 24: // rcurveline
+			i := 0
+			for ; i+5 < len(in.stack)-2; i += 6 {
+				s := in.stack[i:]
+				in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			}
+			if i+1 < len(in.stack) {
+				in.lineTo(in.stack[i], in.stack[i+1])
+			}
+			in.stack = in.stack[:0]
+		case 25: // rlinecurve
+			i := 0
+			for ; i+1 < len(in.stack)-6; i += 2 {
+				in.lineTo(in.stack[i], in.stack[i+1])
+			}
+			if i+5 < len(in.stack) {
+				s := in.stack[i:]
+				in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			}
+			in.stack = in.stack[:0]
+		case 26: // vvcurveto
+			s := in.stack
+			dx1 := 0.0
+			if len(s)%2 == 1 {
+				dx1, s = s[0], s[1:]
+			}
+			for ; len(s) >= 4; s = s[4:] {
+				in.curveTo(dx1, s[0], s[1], s[2], 0, s[3])
+				dx1 = 0
+			}
+			in.stack = in.stack[:0]
+		case 27: // hhcurveto
+			s := in.stack
+			dy1 := 0.0
+			if len(s)%2 == 1 {
+				dy1, s = s[0], s[1:]
+			}
+			for ; len(s) >= 4; s = s[4:] {
+				in.curveTo(s[0], dy1, s[1], s[2], s[3], 0)
+				dy1 = 0
+			}
+			in.stack = in.stack[:0]
+		case 30, 31: // vhcurveto, hvcurveto
+			in.alternatingCurves(op == 31)
+			in.stack = in.stack[:0]
+		case 1234: // hflex
+			if s := in.stack; len(s) >= 7 {
+				y := in.y
+				in.curveTo(s[0], 0, s[1], s[2], s[3], 0)
+				in.curveTo(s[4], 0, s[5], y-in.y, s[6], 0)
+			}
+			in.stack = in.stack[:0]
+		case 1235: // flex
+			if s := in.stack; len(s) >= 12 {
+				in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+				in.curveTo(s[6], s[7], s[8], s[9], s[10], s[11])
+			}
+			in.stack = in.stack[:0]
+		case 1236: // hflex1
+			if s := in.stack; len(s) >= 9 {
+				y := in.y
+				in.curveTo(s[0], s[1], s[2], s[3], s[4], 0)
+				in.curveTo(s[5], 0, s[6], s[7], s[8], y-(in.y+s[7]))
+			}
+			in.stack = in.stack[:0]
+		case 1237: // flex1
+			if s := in.stack; len(s) >= 11 {
+				x, y := in.x, in.y
+				var sx, sy float64
+				for i := 0; i < 10; i += 2 {
+					sx += s[i]
+					sy += s[i+1]
+				}
+				in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+				x2, y2 := in.x+s[6], in.y+s[7]
+				x3, y3 := x2+s[8], y2+s[9]
+				if math.Abs(sx) > math.Abs(sy) {
+					in.b.cubeTo(x2, y2, x3, y3, x+sx+s[10], y)
+					in.x, in.y = x+sx+s[10], y
+				} else {
+					in.b.cubeTo(x2, y2, x3, y3, x, y+sy+s[10])
+					in.x, in.y = x, y+sy+s[10]
+				}
+			}
+			in.stack = in.stack[:0]
+		case 10, 29: // callsubr, callgsubr
+			if len(in.stack) == 0 {
+				in.done = true
+				return
+			}
+			subrs := in.local
+			if op == 29 {
+				subrs = in.cff.globalSubrs
+			}
+			n := int(in.stack[len(in.stack)-1]) + subrBias(len(subrs))
+			in.stack = in.stack[:len(in.stack)-1]
+			if n < 0 || n >= len(subrs) {
+				in.done = true
+				return
+			}
+			in.run(subrs[n], level+1)
+		case 11: // return
+			return
+		case 14: // endchar
+			in.takeWidth(len(in.stack) == 1 || len(in.stack) == 5)
+			if len(in.stack) >= 4 {
+				// Accented character, as with the Type 1 seac operator
+				s := in.stack[len(in.stack)-4:]
+				in.seac(s[0], s[1], int(s[2]), int(s[3]))
+			}
+			in.b.closePath()
+			in.done = true
+			return
+		case 1203, 1204, 1205: // and, or, not
+			in.stack = in.stack[:0]
+		case 1209: // abs
+			if n := len(in.stack); n > 0 {
+				in.stack[n-1] = math.Abs(in.stack[n-1])
+			}
+		case 1210, 1211, 1212, 1224: // add, sub, div, mul
+			if n := len(in.stack); n >= 2 {
+				a, c := in.stack[n-2], in.stack[n-1]
+				var v float64
+				switch op {
+				case 1210:
+					v = a + c
+				case 1211:
+					v = a - c
+				case 1212:
+					if c != 0 {
+						v = a / c
+					}
+				case 1224:
+					v = a * c
+				}
+				in.stack = append(in.stack[:n-2], v)
+			}
+		case 1214: // neg
+			if n := len(in.stack); n > 0 {
+				in.stack[n-1] = -in.stack[n-1]
+			}
+		case 1218: // drop
+			if n := len(in.stack); n > 0 {
+				in.stack = in.stack[:n-1]
+			}
+		case 1227: // dup
+			if n := len(in.stack); n > 0 {
+				in.push(in.stack[n-1])
+			}
+		case 1228: // exch
+			if n := len(in.stack); n >= 2 {
+				in.stack[n-2], in.stack[n-1] = in.stack[n-1], in.stack[n-2]
+			}
+		default:
+			// Unsupported or reserved operators clear the stack
+			in.stack = in.stack[:0]
+		}
+	}
+}
+
+// push adds an argument, ending the glyph if the stack overflows
+func (in *type2Interp) push(v float64) {
+	if len(in.stack) >= maxCharStack {
+		in.done = true
+		return
+	}
+	in.stack = append(in.stack, v)
+}
+
+// alternatingCurves draws the curves of hvcurveto and vhcurveto, whose
+// tangents alternate between horizontal and vertical
+func (in *type2Interp) alternatingCurves(horizontal bool) {
+	s := in.stack
+	for len(s) >= 4 {
+		last := 0.0
+		if len(s) == 5 {
+			last = s[4]
+		}
+		if horizontal {
+			in.curveTo(s[0], 0, s[1], s[2], last, s[3])
+		} else {
+			in.curveTo(0, s[0], s[1], s[2], s[3], last)
+		}
+		horizontal = !horizontal
+		s = s[4:]
+		if len(s) == 1 {
+			break
+		}
+	}
+}
+
+// seac draws an accented character from two glyphs of the font named by
+// their StandardEncoding codes, with the accent offset by (adx, ady)
+func (in *type2Interp) seac(adx, ady float64, base, accent int) {
+	if base < 0 || base > 255 || accent < 0 || accent > 255 {
+		return
+	}
+	baseGID, ok1 := in.cff.glyphIndex(standardGlyphName(byte(base)))
+	accentGID, ok2 := in.cff.glyphIndex(standardGlyphName(byte(accent)))
+	if !ok1 || !ok2 {
+		return
+	}
+	in.b.closePath()
+	in.cff.appendGlyph(in.b, baseGID, in.dx, in.dy, in.depth+1)
+	in.cff.appendGlyph(in.b, accentGID, in.dx+adx, in.dy+ady, in.depth+1)
+}
@@ -0,0 +1,91 @@
+package font
+
+import (
+	"testing"
+)
+
+// csNums encodes small charstring operands (-107 to 107), which Type 1 and
+// Type 2 charstrings encode alike
+func csNums(values ...int) []byte {
+	var b []byte
+	for _, v := range values {
+		b = append(b, byte(v+139))
+	}
+	return b
+}
+
+func TestType2Outline(t *testing.T) {
+	// 50 (width) 10 20 rmoveto 100 0 rlineto 0 100 rlineto endchar
+	code := append(csNums(50, 10, 20), 21)
+	code = append(code, csNums(100, 0)...)
+	code = append(code, 5)
+	code = append(code, csNums(0, 100)...)
+	code = append(code, 5, 14)
+
+	cff := &cffFont{charStrings: [][]byte{code}}
+	o := cff.outline(0)
+	if len(o) != 4 {
+		t.Fatalf("Expected 4 segments, got %d: %v", len(o), o)
+	}
+	if p := o[0].Points[0]; p.X != 0.01 || p.Y != 0.02 {
+		t.Errorf("Expected the width to be skipped and a move to (0.01, 0.02), got %v", p)
+	}
+	if p := o[2].Points[0]; p.X != 0.11 || p.Y != 0.12 {
+		t.Errorf("Expected a line to (0.11, 0.12), got %v", p)
+	}
+	if o[3].Op != OutlineClose {
+		t.Errorf("Expected endchar to close the contour, got %v", o[3].Op)
+	}
+}
+
+func TestType2Subroutines(t *testing.T) {
+	// The local subroutine draws a curve; subroutine 0 is called as -107
+	subr := append(csNums(10, 0, 10, 10, 0, 10), 8, 11)
+	code := append(csNums(0, 0), 21)
+	code = append(code, csNums(-107)...)
+	code = append(code, 10, 14)
+
+	cff := &cffFont{charStrings: [][]byte{code}, localSubrs: [][]byte{subr}}
+	o := cff.outline(0)
+	if len(o) != 3 || o[1].Op != OutlineCubeTo {
+		t.Fatalf("Expected a move, a curve and a close, got %v", o)
+	}
+	if p := o[1].Points[2]; p.X != 0.02 || p.Y != 0.02 {
+		t.Errorf("Expected the curve to end at (0.02, 0.02), got %v", p)
+	}
+}
+
+func TestType2Malformed(t *testing.T) {
+	tests := [][]byte{
+		{},
+		{21},                     // Operator without operands
+		append(csNums(5), 10),    // Missing subroutine
+		{28, 1},                  // Truncated number
+		append(csNums(-107), 29), // Missing global subroutine
+		{255, 0, 0},              // Truncated fixed-point number
+	}
+	for i, code := range tests {
+		cff := &cffFont{charStrings: [][]byte{code}}
+		_ = cff.outline(0) // Must not panic
+		if o := cff.outline(1); o != nil {
+			t.Errorf("%d: Expected no outline for a missing glyph, got %v", i, o)
+		}
+	}
+}
+
+func TestSubrBias(t *testing.T) {
+	tests := []struct {
+		count, bias int
+	}{
+		{0, 107},
+		{1239, 107},
+		{1240, 1131},
+		{33899, 1131},
+		{33900, 32768},
+	}
+	for _, tt := range tests {
+		if got := subrBias(tt.count); got != tt.bias {
+			t.Errorf("subrBias(%d): Expected %d, got %d", tt.count, tt.bias, got)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package font
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// ParseEncodingCMap parses an embedded CMap stream used as the /Encoding of
+// a Type0 font. The stream's /UseCMap entry may name a predefined CMap or
+// be another embedded CMap stream.
+func ParseEncodingCMap(stream *core.Stream, resolver func(core.IndirectRef) (core.Object, error)) (*CMap, error) {
+	return parseEncodingCMap(stream, resolver, 0)
+}
+
+// parseEncodingCMap parses an embedded CMap stream, following /UseCMap
+// streams up to a fixed depth
+func parseEncodingCMap(stream *core.Stream, resolver func(core.IndirectRef) (core.Object, error), depth int) (*CMap, error) {
+	if depth > 8 {
+		return nil, fmt.Errorf("usecmap chain too deep")
+	}
+
+	cmap, err := ParseToUnicodeCMap(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stream dictionary takes precedence over the CMap program
+	if name := extractName(stream.Dict.Get("CMapName")); name != "" {
+		cmap.name = name
+	}
+	if wmode := stream.Dict.Get("WMode"); wmode != nil {
+		cmap.vertical = getNumber(wmode) == 1
+	}
+
+	if useObj := stream.Dict.Get("UseCMap"); useObj != nil {
+		if resolver != nil {
+			if ref, ok := useObj.(core.IndirectRef); ok {
+				if obj, err := resolver(ref); err == nil {
+					useObj = obj
+				}
+			}
+		}
+		switch use := useObj.(type) {
+		case core.Name:
+			cmap.useCMap = string(use)
+			cmap.parent, _ = PredefinedCMap(cmap.useCMap)
+		case *core.Stream:
+			if parent, err := parseEncodingCMap(use, resolver, depth+1); err == nil {
+				cmap.useCMap = parent.name
+				cmap.parent = parent
+			}
+		}
+	}
+
+	return cmap, nil
+}
+
+// Name returns the CMap's name, from /CMapName
+func (cm *CMap) Name() string {
+	return cm.name
+}
+
+// IsVertical returns true if the CMap is for vertical writing (WMode 1)
+func (cm *CMap) IsVertical() bool {
+	return cm.vertical
+}
+
+// LookupCID returns the CID for a character code, following usecmap
+func (cm *CMap) LookupCID(code uint32) (uint32, bool) {
+	if cid, ok := cm.cidChars[code]; ok {
+		return cid, true
+	}
+	if cm.cidRangesSorted {
+		i := sort.Search(len(cm.cidRanges), func(i int) bool { return cm.cidRanges[i].endCode >= code })
+		if i < len(cm.cidRanges) && code >= cm.cidRanges[i].startCode {
+			r := cm.cidRanges[i]
+			return r.startCID + (code - r.startCode), true
+		}
+	} else {
+		for _, r := range cm.cidRanges {
+			if code >= r.startCode && code <= r.endCode {
+				return r.startCID + (code - r.startCode), true
+			}
+		}
+	}
+	if cm.parent != nil {
+		return cm.parent.LookupCID(code)
+	}
+	return 0, false
+}
+
+// nextCode reads the character code at the start of data, matching it
+// against the code space ranges. It returns the code and its length in bytes.
+func (cm *CMap) nextCode(data []byte) (uint32, int) {
+	codespaces := cm.codespaces
+	for c := cm.parent; len(codespaces) == 0 && c != nil; c = c.parent {
+		codespaces = c.codespaces
+	}
+
+	shortest := 0
+	for n := 1; n <= 4 && n <= len(data); n++ {
+		for _, cs := range codespaces {
+			if len(cs.low) != n {
+				continue
+			}
+			if shortest == 0 {
+				shortest = n
+			}
+			if codeInRange(data[:n], cs) {
+				return codeValue(data[:n]), n
+			}
+		}
+	}
+
+	// No match: consume the shortest code length in use, or two bytes as
+	// for Identity-H
+	n := shortest
+	if n == 0 {
+		n = 2
+	}
+	if n > len(data) {
+		n = len(data)
+	}
+	return codeValue(data[:n]), n
+}
+
+// codeInRange reports whether each byte of code lies within the range
+func codeInRange(code []byte, cs codespaceRange) bool {
+	for i, b := range code {
+		if b < cs.low[i] || b > cs.high[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// codeValue returns a big-endian byte sequence as a character code
+func codeValue(code []byte) uint32 {
+	var v uint32
+	for _, b := range code {
+		v = v<<8 | uint32(b)
+	}
+	return v
+}
+
+// parseHeader parses the CMap name, writing mode and usecmap operator from
+// the part of the CMap before its mapping sections
+func (cm *CMap) parseHeader(content string) {
+	end := len(content)
+	for _, op := range []string{"begincodespacerange", "begincidchar", "begincidrange", "beginbfchar", "beginbfrange", "beginnotdefrange"} {
+		if idx := strings.Index(content, op); idx >= 0 && idx < end {
+			end = idx
+		}
+	}
+
+	tokens := strings.Fields(content[:end])
+	for i, tok := range tokens {
+		switch {
+		case tok == "/CMapName" && i+1 < len(tokens) && cm.name == "":
+			cm.name = strings.TrimPrefix(tokens[i+1], "/")
+		case tok == "/WMode" && i+1 < len(tokens):
+			cm.vertical = tokens[i+1] == "1"
+		case tok == "usecmap" && i > 0 && strings.HasPrefix(tokens[i-1], "/"):
+			cm.useCMap = tokens[i-1][1:]
+		}
+	}
+}
+
+// parseCodeSpaces parses every code space range, keeping their byte lengths
+func (cm *CMap) parseCodeSpaces(content string) {
+	for _, section := range cmapSections(content, "codespacerange") {
+		tokens := cmapTokens(section)
+		for i := 0; i+1 < len(tokens); i += 2 {
+			low, err1 := hexBytes(tokens[i])
+			high, err2 := hexBytes(tokens[i+1])
+			if err1 != nil || err2 != nil || len(low) == 0 || len(low) != len(high) {
+				continue
+			}
+			cm.codespaces = append(cm.codespaces, codespaceRange{low: low, high: high})
+		}
+	}
+}
+
+// parseCIDMappings parses begincidchar and begincidrange sections
+// Formats: <code> cid and <low> <high> cid
+func (cm *CMap) parseCIDMappings(content string) {
+	for _, section := range cmapSections(content, "cidchar") {
+		tokens := cmapTokens(section)
+		for i := 0; i+1 < len(tokens); i += 2 {
+			code, err1 := hexTokenValue(tokens[i])
+			cid, err2 := strconv.ParseUint(tokens[i+1], 10, 32)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			if cm.cidChars == nil {
+				cm.cidChars = make(map[uint32]uint32)
+			}
+			cm.cidChars[code] = uint32(cid)
+		}
+	}
+
+	for _, section := range cmapSections(content, "cidrange") {
+		tokens := cmapTokens(section)
+		for i := 0; i+2 < len(tokens); i += 3 {
+			start, err1 := hexTokenValue(tokens[i])
+			end, err2 := hexTokenValue(tokens[i+1])
+			cid, err3 := strconv.ParseUint(tokens[i+2], 10, 32)
+			if err1 != nil || err2 != nil || err3 != nil || end < start {
+				continue
+			}
+			cm.cidRanges = append(cm.cidRanges, cidRange{
+				startCode: start,
+				endCode:   end,
+				startCID:  uint32(cid),
+			})
+		}
+	}
+	cm.sortCIDRanges()
+}
+
+// sortCIDRanges sorts the CID ranges by code for binary search, unless they
+// overlap, in which case the first matching range wins and they are left
+// in order
+func (cm *CMap) sortCIDRanges() {
+	if len(cm.cidRanges) < 2 {
+		return
+	}
+	sorted := make([]cidRange, len(cm.cidRanges))
+	copy(sorted, cm.cidRanges)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].startCode < sorted[j].startCode })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].startCode <= sorted[i-1].endCode {
+			return
+		}
+	}
+	cm.cidRanges = sorted
+	cm.cidRangesSorted = true
+}
+
+// cmapSections returns the contents of every begin<op> ... end<op> section
+func cmapSections(content, op string) []string {
+	begin, end := "begin"+op, "end"+op
+
+	var sections []string
+	start := 0
+	for {
+		beginIdx := strings.Index(content[start:], begin)
+		if beginIdx == -1 {
+			break
+		}
+		beginIdx += start + len(begin)
+
+		endIdx := strings.Index(content[beginIdx:], end)
+		if endIdx == -1 {
+			break
+		}
+		endIdx += beginIdx
+
+		sections = append(sections, content[beginIdx:endIdx])
+		start = endIdx + len(end)
+	}
+	return sections
+}
+
+// cmapTokens splits a CMap section into hex strings (kept with their angle
+// brackets) and whitespace-separated tokens, tolerating tightly packed
+// entries like <20><7e>1
+func cmapTokens(section string) []string {
+	var tokens []string
+	for i := 0; i < len(section); {
+		c := section[i]
+		switch {
+		case c == '<':
+			end := strings.IndexByte(section[i:], '>')
+			if end == -1 {
+				return tokens
+			}
+			tokens = append(tokens, section[i:i+end+1])
+			i += end + 1
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		default:
+			j := i
+			for j < len(section) && !strings.ContainsRune(" \t\n\r<", rune(section[j])) {
+				j++
+			}
+			tokens = append(tokens, section[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+// hexBytes decodes a <...> hex token to bytes
+func hexBytes(token string) ([]byte, error) {
+	hexStr := extractHexString(token)
+	if hexStr == "" {
+		return nil, fmt.Errorf("not a hex string: %s", token)
+	}
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+	return hex.DecodeString(hexStr)
+}
+
+// hexTokenValue parses a <...> hex token as a character code
+func hexTokenValue(token string) (uint32, error) {
+	hexStr := extractHexString(token)
+	if hexStr == "" {
+		return 0, fmt.Errorf("not a hex string: %s", token)
+	}
+	return parseHexToUint32(hexStr)
+}
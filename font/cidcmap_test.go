@@ -0,0 +1,192 @@
+package font
+
+import (
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// rksjCMap is a cut-down 90ms-RKSJ-H style CMap mixing 1- and 2-byte codes
+const rksjCMap = `%!PS-Adobe-3.0 Resource-CMap
+/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+/CIDSystemInfo 3 dict dup begin
+  /Registry (Adobe) def
+  /Ordering (Japan1) def
+  /Supplement 2 def
+end def
+/CMapName /Test-RKSJ-H def
+/CMapType 1 def
+4 begincodespacerange
+<00>   <80>
+<8140> <9FFC>
+<A0>   <DF>
+<E040> <FCFC>
+endcodespacerange
+2 begincidchar
+<7e> 631
+<815f> 97
+endcidchar
+3 begincidrange
+<20> <7d> 231
+<a0> <df> 326
+<8140> <817e> 633
+endcidrange
+endcmap
+CMapName currentdict /CMap defineresource pop
+end
+end
+`
+
+func TestCMapParseCIDMappings(t *testing.T) {
+	cmap, err := parseCMapData([]byte(rksjCMap))
+	if err != nil {
+		t.Fatalf("Failed to parse CMap: %v", err)
+	}
+
+	if cmap.Name() != "Test-RKSJ-H" {
+		t.Errorf("Expected name 'Test-RKSJ-H', got '%s'", cmap.Name())
+	}
+	if cmap.IsVertical() {
+		t.Error("Expected horizontal CMap")
+	}
+	if len(cmap.codespaces) != 4 {
+		t.Errorf("Expected 4 code space ranges, got %d", len(cmap.codespaces))
+	}
+
+	tests := []struct {
+		code     uint32
+		expected uint32
+	}{
+		{0x20, 231},
+		{0x41, 264},
+		{0x7e, 631},
+		{0xb1, 343},
+		{0x8140, 633},
+		{0x815f, 97},
+	}
+	for _, tt := range tests {
+		cid, ok := cmap.LookupCID(tt.code)
+		if !ok || cid != tt.expected {
+			t.Errorf("LookupCID(%x) = %d, %v, want %d", tt.code, cid, ok, tt.expected)
+		}
+	}
+
+	if _, ok := cmap.LookupCID(0x9000); ok {
+		t.Error("Expected no CID for unmapped code")
+	}
+}
+
+func TestCMapNextCode(t *testing.T) {
+	cmap, err := parseCMapData([]byte(rksjCMap))
+	if err != nil {
+		t.Fatalf("Failed to parse CMap: %v", err)
+	}
+
+	// "A", halfwidth katakana, a 2-byte code, then a truncated lead byte
+	data := []byte{0x41, 0xb1, 0x81, 0x40, 0x81}
+	expected := []struct {
+		code uint32
+		n    int
+	}{
+		{0x41, 1},
+		{0xb1, 1},
+		{0x8140, 2},
+		{0x81, 1},
+	}
+
+	for i, want := range expected {
+		code, n := cmap.nextCode(data)
+		if code != want.code || n != want.n {
+			t.Errorf("Code %d: got %x (%d bytes), want %x (%d bytes)", i, code, n, want.code, want.n)
+		}
+		data = data[n:]
+	}
+	if len(data) != 0 {
+		t.Errorf("Expected all data consumed, %d bytes left", len(data))
+	}
+}
+
+func TestCMapUseCMap(t *testing.T) {
+	if err := RegisterCMap("", []byte(rksjCMap)); err != nil {
+		t.Fatalf("RegisterCMap failed: %v", err)
+	}
+
+	// A vertical variant overriding one code and inheriting the rest
+	vertical := `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+/Test-RKSJ-H usecmap
+/CMapName /Test-RKSJ-V def
+/WMode 1 def
+1 begincidchar
+<8141> 7887
+endcidchar
+endcmap
+`
+	cmap, err := parseCMapData([]byte(vertical))
+	if err != nil {
+		t.Fatalf("Failed to parse CMap: %v", err)
+	}
+
+	if !cmap.IsVertical() {
+		t.Error("Expected vertical CMap")
+	}
+	if cmap.parent == nil || cmap.parent.Name() != "Test-RKSJ-H" {
+		t.Fatal("Expected usecmap to link the parent CMap")
+	}
+	if cid, _ := cmap.LookupCID(0x8141); cid != 7887 {
+		t.Errorf("Expected override CID 7887, got %d", cid)
+	}
+	if cid, _ := cmap.LookupCID(0x8142); cid != 635 {
+		t.Errorf("Expected inherited CID 635, got %d", cid)
+	}
+	if code, n := cmap.nextCode([]byte{0x81, 0x42}); code != 0x8142 || n != 2 {
+		t.Errorf("Expected inherited code space, got %x (%d bytes)", code, n)
+	}
+}
+
+func TestParseEncodingCMap_UseCMapStream(t *testing.T) {
+	parent := &core.Stream{
+		Dict: core.Dict{"CMapName": core.Name("Test-Parent")},
+		Data: []byte("1 begincodespacerange\n<00> <FF>\nendcodespacerange\n1 begincidrange\n<00> <FF> 100\nendcidrange\n"),
+	}
+	child := &core.Stream{
+		Dict: core.Dict{
+			"CMapName": core.Name("Test-Child"),
+			"WMode":    core.Int(1),
+			"UseCMap":  core.IndirectRef{Number: 5},
+		},
+		Data: []byte("1 begincidchar\n<41> 7\nendcidchar\n"),
+	}
+	resolver := mapResolver(map[int]core.Object{5: parent})
+
+	cmap, err := ParseEncodingCMap(child, resolver)
+	if err != nil {
+		t.Fatalf("ParseEncodingCMap failed: %v", err)
+	}
+
+	if cmap.Name() != "Test-Child" || !cmap.IsVertical() {
+		t.Errorf("Expected vertical Test-Child, got %s (vertical %v)", cmap.Name(), cmap.IsVertical())
+	}
+	if cid, _ := cmap.LookupCID(0x41); cid != 7 {
+		t.Errorf("Expected CID 7 for 0x41, got %d", cid)
+	}
+	if cid, _ := cmap.LookupCID(0x42); cid != 166 {
+		t.Errorf("Expected CID 166 from parent stream, got %d", cid)
+	}
+}
+
+func TestCMapTokens(t *testing.T) {
+	tokens := cmapTokens("<20><7e>1\n<8140> <817e>  633")
+	expected := []string{"<20>", "<7e>", "1", "<8140>", "<817e>", "633"}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %v", len(expected), tokens)
+	}
+	for i, tok := range expected {
+		if tokens[i] != tok {
+			t.Errorf("Token %d: expected %q, got %q", i, tok, tokens[i])
+		}
+	}
+}
@@ -15,7 +15,8 @@ type Type0Font struct {
 	Encoding       string
 	DescendantFont *CIDFont     // The actual CIDFont
 	ToUnicode      *core.Stream // CMap for CID to Unicode mapping
-	IsVertical     bool         // true for Identity-V, false for Identity-H
+	IsVertical     bool         // true for Identity-V and other vertical CMaps
+	CMap           *CMap        // Encoding CMap, nil for Identity-H/V
 }
 
 // CIDFont represents a CIDFont (Character ID keyed font)
@@ -80,15 +81,39 @@ func NewType0Font(fontDict core.Dict, resolver func(core.IndirectRef) (core.Obje
 		Font: baseF,
 	}
 
-	// Parse encoding
+	// Parse encoding: Identity-H/V, a predefined CMap name, or an embedded
+	// CMap stream
 	if encodingObj := fontDict.Get("Encoding"); encodingObj != nil {
-		t0.Encoding = extractName(encodingObj)
+		if ref, ok := encodingObj.(core.IndirectRef); ok {
+			obj, err := resolver(ref)
+			if err == nil {
+				encodingObj = obj
+			}
+		}
 
-		// Determine if vertical writing mode
-		t0.IsVertical = (t0.Encoding == "Identity-V")
-	} else {
+		if stream, ok := encodingObj.(*core.Stream); ok {
+			if cmap, err := ParseEncodingCMap(stream, resolver); err == nil {
+				t0.CMap = cmap
+				t0.Encoding = cmap.Name()
+				t0.IsVertical = cmap.IsVertical()
+			}
+		} else {
+			t0.Encoding = extractName(encodingObj)
+
+			// Determine if vertical writing mode
+			t0.IsVertical = (t0.Encoding == "Identity-V")
+			if t0.Encoding != "Identity-H" && t0.Encoding != "Identity-V" {
+				if cmap, ok := PredefinedCMap(t0.Encoding); ok {
+					t0.CMap = cmap
+					t0.IsVertical = cmap.IsVertical()
+				}
+			}
+		}
+	}
+	if t0.Encoding == "" {
 		t0.Encoding = "Identity-H" // Default
 	}
+	t0.Font.encodingCMap = t0.CMap
 
 	// Parse ToUnicode CMap if present
 	if toUnicodeObj := fontDict.Get("ToUnicode"); toUnicodeObj != nil {
@@ -121,6 +146,11 @@ func NewType0Font(fontDict core.Dict, resolver func(core.IndirectRef) (core.Obje
 		return nil, fmt.Errorf("failed to parse descendant font: %w", err)
 	}
 
+	// Without a ToUnicode CMap, recover Unicode from the embedded font program
+	// and the character collection's CID-to-Unicode table
+	t0.recoverUnicode()
+	t0.useOrderingCMap()
+
 	return t0, nil
 }
 
@@ -174,6 +204,8 @@ func (t0 *Type0Font) parseDescendantFont(fontDict core.Dict, resolver func(core.
 	}
 
 	t0.DescendantFont = cidFont
+	t0.Font.descriptor = cidFont.FontDescriptor
+	t0.Font.cidFont = cidFont
 
 	return nil
 }
@@ -188,6 +220,17 @@ func (t0 *Type0Font) GetWidth(r rune) float64 {
 	return t0.DescendantFont.GetWidthForCID(int(r))
 }
 
+// useOrderingCMap maps CIDs to Unicode through the table for the
+// descendant font's character collection (e.g. Adobe-Japan1-UCS2), for fonts
+// without a ToUnicode CMap
+func (t0 *Type0Font) useOrderingCMap() {
+	cid := t0.DescendantFont
+	if t0.ToUnicodeCMap != nil || cid == nil {
+		return
+	}
+	t0.Font.cidToUnicode = orderingCMap(cid.CIDSystemInfo)
+}
+
 // NewCIDFont creates a CIDFont from a PDF font dictionary
 func NewCIDFont(fontDict core.Dict, resolver func(core.IndirectRef) (core.Object, error)) (*CIDFont, error) {
 	baseFont := extractName(fontDict.Get("BaseFont"))
@@ -344,6 +387,7 @@ func (cid *CIDFont) parseFontDescriptor(fontDict core.Dict, resolver func(core.I
 
 	// Font metrics
 	fd.ItalicAngle = getNumber(fdDict.Get("ItalicAngle"))
+	fd.FontWeight = getNumber(fdDict.Get("FontWeight"))
 	fd.Ascent = getNumber(fdDict.Get("Ascent"))
 	fd.Descent = getNumber(fdDict.Get("Descent"))
 	fd.CapHeight = getNumber(fdDict.Get("CapHeight"))
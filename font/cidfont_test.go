@@ -917,3 +917,93 @@ func TestCIDFont_NilCIDSystemInfo(t *testing.T) {
 		t.Errorf("Expected 'Unknown', got '%s'", collection)
 	}
 }
+
+func TestType0Font_PredefinedCMap(t *testing.T) {
+	fontDict := core.Dict{
+		"Type":     core.Name("Font"),
+		"Subtype":  core.Name("Type0"),
+		"BaseFont": core.Name("MS-Mincho"),
+		"Encoding": core.Name("90ms-RKSJ-V"),
+		"DescendantFonts": core.Array{core.Dict{
+			"Type":     core.Name("Font"),
+			"Subtype":  core.Name("CIDFontType2"),
+			"BaseFont": core.Name("MS-Mincho"),
+			"CIDSystemInfo": core.Dict{
+				"Registry":   core.String("Adobe"),
+				"Ordering":   core.String("Japan1"),
+				"Supplement": core.Int(2),
+			},
+		}},
+	}
+
+	font, err := NewType0Font(fontDict, mockResolver)
+	if err != nil {
+		t.Fatalf("NewType0Font failed: %v", err)
+	}
+
+	if font.CMap == nil {
+		t.Fatal("Expected predefined CMap")
+	}
+	if !font.IsVertical {
+		t.Error("90ms-RKSJ-V should be vertical")
+	}
+	if got := font.DecodeString([]byte{0x93, 0xfa, 0x96, 0x7b, 0x31}); got != "日本1" {
+		t.Errorf("Expected Shift-JIS decoding, got %q", got)
+	}
+}
+
+func TestType0Font_OrderingCMap(t *testing.T) {
+	// A stand-in for Adobe's Adobe-Japan1-UCS2 resource
+	ucs2 := `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+/CMapName /Test-Japan1-UCS2 def
+/CMapType 2 def
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+1 beginbfchar
+<0279> <3000>
+endbfchar
+1 beginbfrange
+<0001> <005F> <0020>
+endbfrange
+endcmap
+`
+	if err := RegisterCMap("", []byte(ucs2)); err != nil {
+		t.Fatalf("RegisterCMap failed: %v", err)
+	}
+
+	encoding := &core.Stream{
+		Dict: core.Dict{"CMapName": core.Name("Test-Embedded-H")},
+		Data: []byte("1 begincodespacerange\n<00> <FF>\nendcodespacerange\n2 begincidrange\n<20> <7e> 1\n<80> <80> 633\nendcidrange\n"),
+	}
+	fontDict := core.Dict{
+		"Type":     core.Name("Font"),
+		"Subtype":  core.Name("Type0"),
+		"BaseFont": core.Name("KozMinPro-Regular"),
+		"Encoding": encoding,
+		"DescendantFonts": core.Array{core.Dict{
+			"Type":     core.Name("Font"),
+			"Subtype":  core.Name("CIDFontType0"),
+			"BaseFont": core.Name("KozMinPro-Regular"),
+			"CIDSystemInfo": core.Dict{
+				"Registry":   core.String("Test"),
+				"Ordering":   core.String("Japan1"),
+				"Supplement": core.Int(6),
+			},
+		}},
+	}
+
+	font, err := NewType0Font(fontDict, mockResolver)
+	if err != nil {
+		t.Fatalf("NewType0Font failed: %v", err)
+	}
+
+	if font.Encoding != "Test-Embedded-H" {
+		t.Errorf("Expected encoding 'Test-Embedded-H', got '%s'", font.Encoding)
+	}
+	if got := font.DecodeString([]byte("Hi\x80!")); got != "Hi　!" {
+		t.Errorf("Expected ordering table decoding, got %q", got)
+	}
+}
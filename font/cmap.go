@@ -26,6 +26,40 @@ type CMap struct {
 	// Actual byte width observed in bfchar/bfrange source codes
 	// This may differ from byteWidth when CMaps use shorter codes than codespacerange allows
 	actualByteWidth int
+
+	// CMap name and writing mode, from /CMapName and /WMode
+	name     string
+	vertical bool
+
+	// All code space ranges, for CMaps that mix code lengths (e.g. Shift-JIS)
+	codespaces []codespaceRange
+
+	// Character code to CID mappings (begincidchar/begincidrange), used by
+	// the encoding CMaps of composite fonts
+	cidChars  map[uint32]uint32
+	cidRanges []cidRange
+
+	// CMap named by usecmap; mappings not found here are looked up there
+	useCMap string
+	parent  *CMap
+
+	// cidRanges is sorted by code and free of overlaps, so lookups can
+	// binary search it
+	cidRangesSorted bool
+}
+
+// codespaceRange is a code space range; codes match when each byte lies
+// between the corresponding bytes of low and high
+type codespaceRange struct {
+	low  []byte
+	high []byte
+}
+
+// cidRange maps a range of character codes to consecutive CIDs
+type cidRange struct {
+	startCode uint32
+	endCode   uint32
+	startCID  uint32
 }
 
 // CMapRange represents a range of character code to Unicode mappings
@@ -83,6 +117,15 @@ func parseCMapData(data []byte) (*CMap, error) {
 		_ = err
 	}
 
+	// Parse the name, writing mode, code space and CID mappings used by
+	// encoding CMaps, and link the parent named by usecmap
+	cmap.parseHeader(content)
+	cmap.parseCodeSpaces(content)
+	cmap.parseCIDMappings(content)
+	if cmap.useCMap != "" && cmap.useCMap != cmap.name {
+		cmap.parent, _ = PredefinedCMap(cmap.useCMap)
+	}
+
 	return cmap, nil
 }
 
@@ -537,6 +580,11 @@ func (cm *CMap) Lookup(charCode uint32) string {
 		}
 	}
 
+	// Fall back to the parent CMap named by usecmap
+	if cm.parent != nil {
+		return cm.parent.Lookup(charCode)
+	}
+
 	// No mapping found - return empty string
 	// Let the caller decide how to handle unmapped codes
 	return ""
@@ -1,4 +1,4 @@
-// Package font provides PDF font handling including Type1, TrueType, and CID fonts.
+// Package font provides PDF font handling including Type1, TrueType, Type3, and CID fonts.
 //
 // This package handles font parsing, character encoding, and text width calculation
 // for accurate text extraction from PDFs.
@@ -10,6 +10,7 @@
 //   - [Type1Font] - PostScript Type 1 fonts (including Standard 14)
 //   - [TrueTypeFont] - TrueType outline fonts
 //   - [Type0Font] - Composite fonts for CJK text
+//   - [Type3Font] - Fonts whose glyphs are drawn by content streams
 //
 // # Font Creation
 //
@@ -18,6 +19,7 @@
 //	font, err := font.NewType1Font(fontDict, resolver)
 //	font, err := font.NewTrueTypeFont(fontDict, resolver)
 //	font, err := font.NewType0Font(fontDict, resolver)
+//	font, err := font.NewType3Font(fontDict, resolver)
 //
 // # Text Decoding
 //
@@ -40,11 +42,37 @@
 //   - Custom encodings from /Encoding dictionary
 //   - ToUnicode CMaps for Unicode conversion
 //
+// When a font has no ToUnicode CMap, Unicode is recovered from the embedded
+// font program: TrueType cmap and 'post' tables, CFF charsets and built-in
+// encodings, and Type1 built-in encodings, with glyph names resolved through
+// the Adobe Glyph List.
+//
+// # Glyph Outlines
+//
+// A [GlyphSet] maps the character codes of a Type1, TrueType or Type0 font to
+// glyph outlines for rendering, read from the embedded TrueType, CFF or Type1
+// program. Fonts that are not embedded can be given a substitute TrueType
+// program:
+//
+//	glyphs, err := font.NewGlyphSet(fontDict, resolver)
+//	if !glyphs.Embedded() {
+//	    glyphs.SetSubstitute(program)
+//	}
+//	for _, g := range glyphs.Glyphs(rawBytes) {
+//	    // g.Outline is in text space, g.Width in 1000ths of an em
+//	}
+//
 // # CMap Support
 //
 // CMaps (Character Maps) handle character code to Unicode mapping:
 //
 //   - Embedded ToUnicode CMaps
-//   - Predefined CJK CMaps
-//   - CID-to-Unicode mapping
+//   - Predefined CJK CMaps (90ms-RKSJ-H, GBK-EUC-H, KSCms-UHC-H, ETen-B5-H,
+//     CNS-EUC-H, UniJIS-UCS2-H, ...), embedded from Adobe's CMap resources
+//   - Embedded encoding CMaps with usecmap chaining
+//   - CID-to-Unicode mapping through the character collections' ordering
+//     tables (Adobe-Japan1, GB1, CNS1, Korea1 and KR)
+//
+// Other CMap resources can be added, or the embedded ones overridden, with
+// [RegisterCMap].
 package font
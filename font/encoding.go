@@ -1,7 +1,9 @@
 package font
 
 import (
+	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"golang.org/x/text/unicode/norm"
@@ -89,12 +91,13 @@ func InferEncodingFromFontName(fontName string) Encoding {
 		return SymbolEncoding // Wingdings uses similar symbol encoding
 	}
 
-	// CJK fonts typically use Identity-H/V (handled elsewhere)
-	// These are just hints for when encoding is completely missing
+	// CJK fonts are composite fonts decoded through Identity-H/V or a
+	// predefined CMap (see Type0Font); these are just hints for when the
+	// encoding is completely missing
 	if strings.Contains(name, "mincho") || strings.Contains(name, "gothic") ||
 		strings.Contains(name, "msmincho") || strings.Contains(name, "msgothic") ||
 		strings.Contains(name, "heise") {
-		// Japanese fonts - would need Identity-H/V with CMap
+		// Japanese fonts
 		return WinAnsiEncoding // Safe fallback
 	}
 	if strings.Contains(name, "simsun") || strings.Contains(name, "simhei") ||
@@ -171,13 +174,43 @@ func NewCustomEncodingFromGlyphs(base Encoding, differences map[byte]string) *Cu
 	// Convert glyph names to runes
 	runeDiffs := make(map[byte]rune, len(differences))
 	for code, glyphName := range differences {
-		if r, ok := glyphNameToUnicode[glyphName]; ok {
+		if r, ok := GlyphNameToRune(glyphName); ok {
 			runeDiffs[code] = r
 		}
 	}
 	return NewCustomEncoding(base, runeDiffs)
 }
 
+// GlyphNameToRune maps a glyph name to its Unicode code point. Names are
+// looked up in the Adobe Glyph List subset, then parsed as "uniXXXX" or
+// "uXXXX" to "uXXXXXX" per the AGL naming conventions. A suffix after a
+// period (as in "a.sc") is ignored.
+func GlyphNameToRune(name string) (rune, bool) {
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		name = name[:i]
+	}
+
+	if r, ok := glyphNameToUnicode[name]; ok {
+		return r, true
+	}
+
+	var hex string
+	switch {
+	case strings.HasPrefix(name, "uni") && len(name) == 7:
+		hex = name[3:]
+	case strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7:
+		hex = name[1:]
+	default:
+		return 0, false
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || v > unicode.MaxRune || (v >= 0xD800 && v <= 0xDFFF) {
+		return 0, false
+	}
+	return rune(v), true
+}
+
 // Decode converts a byte to a rune, using the difference if present, otherwise the base encoding
 func (e *CustomEncoding) Decode(b byte) rune {
 	if r, ok := e.differences[b]; ok {
@@ -604,6 +637,67 @@ var glyphNameToUnicode = map[string]rune{
 	"infinity":     0x221E, // ∞
 	"radical":      0x221A, // √
 	"approxequal":  0x2248, // ≈
+	"Delta":        0x2206, // ∆
+	"lozenge":      0x25CA, // ◊
+	"logicalnot":   0x00AC, // ¬
+
+	// Ligatures
+	"ff":  0xFB00, // ﬀ
+	"fi":  0xFB01, // ﬁ
+	"fl":  0xFB02, // ﬂ
+	"ffi": 0xFB03, // ﬃ
+	"ffl": 0xFB04, // ﬄ
+
+	// Remaining StandardEncoding and Latin-1 glyphs
+	"quotesinglbase":   0x201A, // ‚
+	"quotedblbase":     0x201E, // „
+	"perthousand":      0x2030, // ‰
+	"periodcentered":   0x00B7, // ·
+	"currency":         0x00A4, // ¤
+	"brokenbar":        0x00A6, // ¦
+	"ordfeminine":      0x00AA, // ª
+	"ordmasculine":     0x00BA, // º
+	"onesuperior":      0x00B9, // ¹
+	"twosuperior":      0x00B2, // ²
+	"threesuperior":    0x00B3, // ³
+	"nonbreakingspace": 0x00A0,
+	"nbspace":          0x00A0,
+	"acute":            0x00B4, // ´
+	"dieresis":         0x00A8, // ¨
+	"cedilla":          0x00B8, // ¸
+	"macron":           0x00AF, // ¯
+	"circumflex":       0x02C6, // ˆ
+	"tilde":            0x02DC, // ˜
+	"breve":            0x02D8, // ˘
+	"dotaccent":        0x02D9, // ˙
+	"ring":             0x02DA, // ˚
+	"hungarumlaut":     0x02DD, // ˝
+	"ogonek":           0x02DB, // ˛
+	"caron":            0x02C7, // ˇ
+	"dotlessi":         0x0131, // ı
+	"Lslash":           0x0141, // Ł
+	"lslash":           0x0142, // ł
+	"OE":               0x0152, // Œ
+	"oe":               0x0153, // œ
+	"Scaron":           0x0160, // Š
+	"scaron":           0x0161, // š
+	"Zcaron":           0x017D, // Ž
+	"zcaron":           0x017E, // ž
+	"Ydieresis":        0x0178, // Ÿ
+	"Gbreve":           0x011E, // Ğ
+	"gbreve":           0x011F, // ğ
+	"Idotaccent":       0x0130, // İ
+	"Scedilla":         0x015E, // Ş
+	"scedilla":         0x015F, // ş
+	"Cacute":           0x0106, // Ć
+	"cacute":           0x0107, // ć
+	"Ccaron":           0x010C, // Č
+	"ccaron":           0x010D, // č
+	"dcroat":           0x0111, // đ
+	"franc":            0x20A3, // ₣
+	"figuredash":       0x2012, // ‒
+	"minute":           0x2032, // ′
+	"second":           0x2033, // ″
 }
 
 // symbolEncodingTable - Adobe Symbol font encoding
@@ -727,6 +821,20 @@ func NormalizeEmojiSequence(s string) string {
 	return s
 }
 
+// DecodeTextString decodes a PDF text string (PDF 32000-1:2008, 7.9.2.2),
+// such as a document title or a structure element's /ActualText: UTF-16BE
+// or UTF-8 when prefixed with a byte order mark, otherwise PDFDocEncoding
+func DecodeTextString(data []byte) string {
+	switch {
+	case len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF:
+		return DecodeUTF16BE(data[2:])
+	case len(data) >= 3 && data[0] == 0xEF && data[1] == 0xBB && data[2] == 0xBF:
+		return strings.ToValidUTF8(string(data[3:]), "")
+	default:
+		return PDFDocEncoding.DecodeString(data)
+	}
+}
+
 // DecodeUTF16BE decodes UTF-16 Big Endian encoded bytes to a string
 // Note: Input should NOT include the BOM (FEFF) - that should be stripped before calling
 func DecodeUTF16BE(data []byte) string {
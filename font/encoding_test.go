@@ -544,3 +544,24 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+func TestDecodeTextString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{"PDFDocEncoding", []byte("Caf\xe9 \x8dquoted\x8e"), "Café “quoted”"},
+		{"UTF-16BE with BOM", []byte{0xFE, 0xFF, 0x00, 0x48, 0x00, 0x69, 0x65, 0xE5}, "Hi日"},
+		{"UTF-8 with BOM", []byte("\xef\xbb\xbf日本"), "日本"},
+		{"Empty", []byte{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeTextString(tt.input); got != tt.expected {
+				t.Errorf("DecodeTextString() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package font
 
+import (
+	"strings"
+	"unicode"
+)
+
 // Font represents a PDF font
 type Font struct {
 	Name     string
@@ -12,6 +17,33 @@ type Font struct {
 
 	// ToUnicode CMap for character code to Unicode mapping
 	ToUnicodeCMap *CMap
+
+	// Custom encoding built from an /Encoding Differences array.
+	// When set, it is used instead of the named Encoding.
+	customEncoding Encoding
+
+	// Widths by single-byte character code (in 1000ths of em), for fonts
+	// whose widths cannot be looked up by decoded rune
+	codeWidths map[byte]float64
+
+	// Unicode by CID for composite fonts, recovered from the embedded font
+	// program when there is no ToUnicode CMap
+	cidUnicode map[uint16]rune
+
+	// Encoding CMap of a composite font with a non-Identity /Encoding,
+	// mapping codes to CIDs
+	encodingCMap *CMap
+
+	// CID-to-Unicode table for the composite font's character collection
+	// (e.g. Adobe-Japan1-UCS2)
+	cidToUnicode *CMap
+
+	// Descendant CIDFont of a composite font, for widths by CID
+	cidFont *CIDFont
+
+	// Font descriptor, or of the descendant font of a composite font, whose
+	// flags, weight and italic angle give the font's style
+	descriptor *FontDescriptor
 }
 
 // NewFont creates a new font
@@ -49,6 +81,35 @@ func (f *Font) GetStringWidth(s string) float64 {
 	return total
 }
 
+// GetCodesWidth calculates the total width of a string of character codes
+// (in 1000ths of em), from single-byte code widths or, for composite fonts,
+// the CIDFont's widths by CID. It reports false if the font has no per-code
+// widths, in which case callers should measure the decoded string with
+// GetStringWidth instead.
+func (f *Font) GetCodesWidth(data []byte) (float64, bool) {
+	if f.cidFont != nil {
+		total := 0.0
+		for len(data) > 0 {
+			cid, n, _ := f.nextCID(data)
+			if n == 0 {
+				break
+			}
+			data = data[n:]
+			total += f.cidFont.GetWidthForCID(int(cid))
+		}
+		return total, true
+	}
+	if f.codeWidths == nil {
+		return 0, false
+	}
+
+	total := 0.0
+	for _, b := range data {
+		total += f.codeWidths[b]
+	}
+	return total, true
+}
+
 // IsStandardFont returns true if this is one of the Standard 14 fonts
 func (f *Font) IsStandardFont() bool {
 	_, ok := standardFonts[f.BaseFont]
@@ -58,9 +119,10 @@ func (f *Font) IsStandardFont() bool {
 // DecodeString decodes a string of character codes to Unicode
 // Priority order:
 // 1. Use ToUnicode CMap if present (most accurate)
-// 2. Check for UTF-16 Byte Order Mark (BOM) - FEFF or FFFE
-// 3. Use font's Encoding property (standard encodings)
-// 4. Fall back to raw bytes as string
+// 2. Decode composite font codes (predefined CMaps, CIDs via font program or collection)
+// 3. Check for UTF-16 Byte Order Mark (BOM) - FEFF or FFFE
+// 4. Use font's Encoding property (custom Differences or standard encodings)
+// 5. Fall back to raw bytes as string
 // All decoded strings are normalized to NFC for consistent embeddings
 func (f *Font) DecodeString(data []byte) string {
 	var decoded string
@@ -71,7 +133,12 @@ func (f *Font) DecodeString(data []byte) string {
 		return NormalizeUnicode(decoded)
 	}
 
-	// Priority 2: Check for UTF-16 Byte Order Mark (BOM)
+	// Priority 2: Composite font codes
+	if f.encodingCMap != nil || f.cidUnicode != nil || f.cidToUnicode != nil {
+		return NormalizeUnicode(f.decodeCIDs(data))
+	}
+
+	// Priority 3: Check for UTF-16 Byte Order Mark (BOM)
 	// PDF hex strings starting with FEFF or FFFE are UTF-16 encoded
 	if len(data) >= 2 {
 		if data[0] == 0xFE && data[1] == 0xFF {
@@ -85,18 +152,76 @@ func (f *Font) DecodeString(data []byte) string {
 		}
 	}
 
-	// Priority 3: Use font's Encoding property
+	// Priority 4: Use font's Encoding property
+	if f.customEncoding != nil {
+		decoded = f.customEncoding.DecodeString(data)
+		return NormalizeUnicode(decoded)
+	}
 	if f.Encoding != "" {
 		enc := GetEncoding(f.Encoding)
 		decoded = enc.DecodeString(data)
 		return NormalizeUnicode(decoded)
 	}
 
-	// Priority 4: Fall back to raw bytes as string
+	// Priority 5: Fall back to raw bytes as string
 	decoded = string(data)
 	return NormalizeUnicode(decoded)
 }
 
+// decodeCIDs decodes the codes of a composite font: codes are mapped to
+// CIDs (2-byte Identity codes unless an encoding CMap is set) and then to
+// Unicode through the font program or the character collection. Codes
+// without a mapping decode to U+FFFD, so the text stays aligned with the
+// glyphs drawn and their widths.
+func (f *Font) decodeCIDs(data []byte) string {
+	var sb strings.Builder
+	for len(data) > 0 {
+		cid, n, ok := f.nextCID(data)
+		if n == 0 {
+			break
+		}
+		data = data[n:]
+		if !ok {
+			sb.WriteRune(unicode.ReplacementChar)
+			continue
+		}
+
+		if r, ok := f.cidUnicode[uint16(cid)]; ok && cid <= 0xFFFF {
+			sb.WriteRune(r)
+		} else if s := f.cidToUnicodeLookup(cid); s != "" {
+			sb.WriteString(s)
+		} else {
+			sb.WriteRune(unicode.ReplacementChar)
+		}
+	}
+	return sb.String()
+}
+
+// nextCID reads the character code at the start of data and maps it to a
+// CID through the encoding CMap, or as a 2-byte Identity code. It returns
+// the CID, the code's length in bytes (0 when data holds no whole code) and
+// whether the code is mapped; unmapped codes use CID 0.
+func (f *Font) nextCID(data []byte) (uint32, int, bool) {
+	if f.encodingCMap != nil {
+		code, n := f.encodingCMap.nextCode(data)
+		cid, ok := f.encodingCMap.LookupCID(code)
+		return cid, n, ok
+	}
+	if len(data) < 2 {
+		return 0, 0, false
+	}
+	return uint32(data[0])<<8 | uint32(data[1]), 2, true
+}
+
+// cidToUnicodeLookup maps a CID to Unicode through the character
+// collection, returning "" when there is no mapping
+func (f *Font) cidToUnicodeLookup(cid uint32) string {
+	if f.cidToUnicode == nil {
+		return ""
+	}
+	return f.cidToUnicode.Lookup(cid)
+}
+
 // IsVertical returns true if this font uses vertical writing mode
 // Vertical writing is indicated by the Identity-V encoding, commonly used for
 // East Asian languages (Chinese, Japanese, Korean) where text flows top-to-bottom
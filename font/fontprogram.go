@@ -0,0 +1,326 @@
+package font
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// This file recovers Unicode from embedded font programs for fonts that have
+// no ToUnicode CMap. Subset fonts often use custom glyph orderings, so the
+// character codes in the content stream mean nothing without the font
+// program: the codes are mapped to glyphs, the glyphs to names (or directly
+// to Unicode through the font's own cmap), and the names to Unicode through
+// the Adobe Glyph List.
+
+// parseDifferences parses an /Encoding Differences array into glyph names
+// by character code
+// Format: [code name1 name2 ... code name1 name2 ...]
+func parseDifferences(diffs core.Array) (map[byte]string, error) {
+	names := make(map[byte]string)
+	code := 0
+	for _, item := range diffs {
+		switch v := item.(type) {
+		case core.Int:
+			// This is a starting code
+			code = int(v)
+		case core.Name:
+			// This is a glyph name mapped to the current code
+			if code >= 0 && code <= 255 {
+				names[byte(code)] = string(v)
+			}
+			code++
+		default:
+			return nil, fmt.Errorf("invalid differences array item: %T", item)
+		}
+	}
+	return names, nil
+}
+
+// setCodeUnicode installs a custom encoding that maps the given codes to
+// Unicode, layered over the font's named Encoding
+func (f *Font) setCodeUnicode(runes map[byte]rune) {
+	if len(runes) == 0 {
+		return
+	}
+	f.customEncoding = NewCustomEncoding(GetEncoding(f.Encoding), runes)
+}
+
+// glyphNamesToRunes maps glyph names to Unicode, dropping names the Adobe
+// Glyph List does not cover
+func glyphNamesToRunes(names map[byte]string, runes map[byte]rune) {
+	for code, name := range names {
+		if r, ok := GlyphNameToRune(name); ok {
+			runes[code] = r
+		}
+	}
+}
+
+// builtinGlyphNames returns the glyph names of the embedded font program's
+// built-in encoding by character code, for Type1 (FontFile) and Type1C
+// (FontFile3) programs. It returns nil when the program uses
+// StandardEncoding or cannot be read.
+func (fd *FontDescriptor) builtinGlyphNames() map[byte]string {
+	if fd == nil {
+		return nil
+	}
+
+	if fd.FontFile != nil {
+		data, err := fd.FontFile.Decode()
+		if err != nil {
+			return nil
+		}
+		return parseType1BuiltinEncoding(data)
+	}
+
+	if fd.FontFile3 != nil {
+		cff, _, err := loadCFF(fd.FontFile3)
+		if err != nil || cff.encoding == nil {
+			return nil
+		}
+		names := make(map[byte]string, len(cff.encoding))
+		for code, gid := range cff.encoding {
+			if name := cff.glyphName(int(gid)); name != "" {
+				names[code] = name
+			}
+		}
+		return names
+	}
+
+	return nil
+}
+
+// parseType1BuiltinEncoding reads the /Encoding array from the cleartext part
+// of a Type1 font program, made of entries like "dup 65 /A put"
+func parseType1BuiltinEncoding(data []byte) map[byte]string {
+	// The encoding is in the cleartext portion, before eexec
+	if end := bytes.Index(data, []byte("eexec")); end >= 0 {
+		data = data[:end]
+	}
+
+	start := bytes.Index(data, []byte("/Encoding"))
+	if start < 0 {
+		return nil
+	}
+
+	tokens := bytes.Fields(data[start+len("/Encoding"):])
+	if len(tokens) > 0 && string(tokens[0]) == "StandardEncoding" {
+		return nil
+	}
+
+	names := make(map[byte]string)
+	for i := 0; i < len(tokens); i++ {
+		tok := string(tokens[i])
+		if tok == "def" {
+			break
+		}
+		if tok != "dup" || i+3 >= len(tokens) || string(tokens[i+3]) != "put" {
+			continue
+		}
+		code, err := strconv.Atoi(string(tokens[i+1]))
+		name := tokens[i+2]
+		if err == nil && code >= 0 && code <= 255 && len(name) > 1 && name[0] == '/' {
+			names[byte(code)] = string(name[1:])
+		}
+		i += 3
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+	return names
+}
+
+// loadCFF parses a FontFile3 stream. Bare CFF programs (Type1C,
+// CIDFontType0C) and CFF-flavoured OpenType programs are supported; for
+// OpenType, the cmap subtables are returned as well.
+func loadCFF(stream *core.Stream) (*cffFont, map[uint32]map[rune]uint16, error) {
+	data, err := stream.Decode()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode font program: %w", err)
+	}
+
+	if extractName(stream.Dict.Get("Subtype")) != "OpenType" && !bytes.HasPrefix(data, []byte("OTTO")) {
+		cff, err := parseCFF(data)
+		return cff, nil, err
+	}
+
+	tables, err := parseSfntTables(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	cffData, ok := tables["CFF "]
+	if !ok {
+		return nil, nil, fmt.Errorf("OpenType font has no CFF table")
+	}
+	cff, err := parseCFF(cffData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var subtables map[uint32]map[rune]uint16
+	if cmapData, ok := tables["cmap"]; ok {
+		subtables, _ = parseCmapSubtables(cmapData)
+	}
+	return cff, subtables, nil
+}
+
+// recoverUnicode maps single-byte codes to Unicode through the embedded
+// TrueType program. Codes are mapped to glyphs with the (3,0) symbol or
+// (1,0) Macintosh cmap subtable, and glyphs to Unicode with the font's
+// Unicode cmap or its 'post' glyph names. Fonts with only a Unicode cmap are
+// left to their named encoding, which already yields Unicode.
+func (tt *TrueTypeFont) recoverUnicode() map[byte]rune {
+	if tt.cmapSubtables == nil {
+		return nil
+	}
+
+	var codeToGID func(code byte) (uint16, bool)
+	if symbol, ok := tt.cmapSubtables[cmapWindowsSymbol]; ok {
+		// Symbol subtables usually place single-byte codes at U+F0xx
+		codeToGID = func(code byte) (uint16, bool) {
+			for _, base := range []rune{0, 0xF000, 0xF100, 0xF200} {
+				if gid, ok := symbol[base+rune(code)]; ok {
+					return gid, true
+				}
+			}
+			return 0, false
+		}
+	} else if mac, ok := tt.cmapSubtables[cmapMacRoman]; ok {
+		codeToGID = func(code byte) (uint16, bool) {
+			gid, ok := mac[rune(code)]
+			return gid, ok
+		}
+	} else {
+		return nil
+	}
+
+	var gidToRune map[uint16]rune
+	if unicode := unicodeCmap(tt.cmapSubtables); unicode != nil {
+		gidToRune = invertCmap(unicode)
+	}
+
+	runes := make(map[byte]rune)
+	for c := 0; c <= 255; c++ {
+		gid, ok := codeToGID(byte(c))
+		if !ok {
+			continue
+		}
+		if r, ok := gidToRune[gid]; ok {
+			runes[byte(c)] = r
+		} else if int(gid) < len(tt.glyphNames) {
+			if r, ok := GlyphNameToRune(tt.glyphNames[gid]); ok {
+				runes[byte(c)] = r
+			}
+		}
+	}
+	return runes
+}
+
+// recoverUnicode maps CIDs to Unicode through the descendant font's embedded
+// program, for composite fonts without a ToUnicode CMap. CIDs are mapped to
+// glyph IDs (through CIDToGIDMap for TrueType, or the charset for CID-keyed
+// CFF), then glyphs to Unicode through the program's cmap or glyph names.
+// Widths are keyed by the recovered characters as well.
+func (t0 *Type0Font) recoverUnicode() {
+	cid := t0.DescendantFont
+	if t0.ToUnicodeCMap != nil || cid == nil || cid.FontDescriptor == nil {
+		return
+	}
+
+	var gidToRune map[uint16]rune
+	var cidToGID map[uint16]uint16 // nil means CID == GID
+
+	switch {
+	case cid.FontDescriptor.FontFile2 != nil:
+		data, err := cid.FontDescriptor.FontFile2.Decode()
+		if err != nil {
+			return
+		}
+		tables, err := parseSfntTables(data)
+		if err != nil {
+			return
+		}
+		gidToRune = sfntGlyphUnicode(tables)
+
+		if cid.CIDToGIDMap != nil {
+			if mapData, err := cid.CIDToGIDMap.Decode(); err == nil {
+				cidToGID = make(map[uint16]uint16, len(mapData)/2)
+				for i := 0; i+1 < len(mapData); i += 2 {
+					cidToGID[uint16(i/2)] = binary.BigEndian.Uint16(mapData[i:])
+				}
+			}
+		}
+
+	case cid.FontDescriptor.FontFile3 != nil:
+		cff, subtables, err := loadCFF(cid.FontDescriptor.FontFile3)
+		if err != nil {
+			return
+		}
+		if unicode := unicodeCmap(subtables); unicode != nil {
+			gidToRune = invertCmap(unicode)
+		} else {
+			gidToRune = make(map[uint16]rune)
+			for gid := range cff.charset {
+				if r, ok := GlyphNameToRune(cff.glyphName(gid)); ok {
+					gidToRune[uint16(gid)] = r
+				}
+			}
+		}
+		if cff.isCID {
+			cidToGID = cff.cidToGID()
+		}
+
+	default:
+		return
+	}
+
+	cidUnicode := make(map[uint16]rune)
+	if cidToGID == nil {
+		for gid, r := range gidToRune {
+			cidUnicode[gid] = r
+		}
+	} else {
+		for c, gid := range cidToGID {
+			if r, ok := gidToRune[gid]; ok {
+				cidUnicode[c] = r
+			}
+		}
+	}
+
+	if len(cidUnicode) == 0 {
+		return
+	}
+
+	t0.Font.cidUnicode = cidUnicode
+	for c, r := range cidUnicode {
+		t0.Font.widths[r] = cid.GetWidthForCID(int(c))
+	}
+}
+
+// sfntGlyphUnicode maps glyph IDs to Unicode using a TrueType program's
+// Unicode cmap, falling back to its 'post' glyph names
+func sfntGlyphUnicode(tables map[string][]byte) map[uint16]rune {
+	if cmapData, ok := tables["cmap"]; ok {
+		if subtables, err := parseCmapSubtables(cmapData); err == nil {
+			if unicode := unicodeCmap(subtables); unicode != nil {
+				return invertCmap(unicode)
+			}
+		}
+	}
+
+	gidToRune := make(map[uint16]rune)
+	if postData, ok := tables["post"]; ok {
+		if names, err := parsePostGlyphNames(postData); err == nil {
+			for gid, name := range names {
+				if r, ok := GlyphNameToRune(name); ok {
+					gidToRune[uint16(gid)] = r
+				}
+			}
+		}
+	}
+	return gidToRune
+}
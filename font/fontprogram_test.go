@@ -0,0 +1,359 @@
+package font
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// mapResolver resolves indirect references from a map of object numbers
+func mapResolver(objects map[int]core.Object) func(core.IndirectRef) (core.Object, error) {
+	return func(ref core.IndirectRef) (core.Object, error) {
+		if obj, ok := objects[ref.Number]; ok {
+			return obj, nil
+		}
+		return nil, fmt.Errorf("object %d not found", ref.Number)
+	}
+}
+
+// descriptorWith returns a font descriptor referencing a font program as
+// object 10
+func descriptorWith(key string) core.Dict {
+	return core.Dict{
+		"Type":     core.Name("FontDescriptor"),
+		"FontName": core.Name("ABCDEF+Test"),
+		"Flags":    core.Int(4),
+		key:        core.IndirectRef{Number: 10},
+	}
+}
+
+func TestType1Font_BuiltinEncoding(t *testing.T) {
+	program := []byte(`%!PS-AdobeFont-1.0: ABCDEF+CMR10
+/FontName /ABCDEF+CMR10 def
+/Encoding 256 array
+0 1 255 {1 index exch /.notdef put} for
+dup 1 /H put
+dup 2 /e put
+dup 3 /fi put
+dup 4 /uni2014 put
+readonly def
+currentdict end
+currentfile eexec
+`)
+
+	fontDict := core.Dict{
+		"Type":           core.Name("Font"),
+		"Subtype":        core.Name("Type1"),
+		"BaseFont":       core.Name("ABCDEF+CMR10"),
+		"FontDescriptor": descriptorWith("FontFile"),
+	}
+	resolver := mapResolver(map[int]core.Object{
+		10: &core.Stream{Dict: core.Dict{}, Data: program},
+	})
+
+	font, err := NewType1Font(fontDict, resolver)
+	if err != nil {
+		t.Fatalf("NewType1Font failed: %v", err)
+	}
+
+	if got := font.DecodeString([]byte{1, 2, 3, 4}); got != "Heﬁ—" {
+		t.Errorf("Expected built-in encoding to decode, got %q", got)
+	}
+}
+
+func TestType1Font_Differences(t *testing.T) {
+	fontDict := core.Dict{
+		"Type":     core.Name("Font"),
+		"Subtype":  core.Name("Type1"),
+		"BaseFont": core.Name("Helvetica"),
+		"Encoding": core.Dict{
+			"BaseEncoding": core.Name("WinAnsiEncoding"),
+			"Differences":  core.Array{core.Int(65), core.Name("eacute"), core.Name("Euro")},
+		},
+	}
+
+	font, err := NewType1Font(fontDict, mockResolver)
+	if err != nil {
+		t.Fatalf("NewType1Font failed: %v", err)
+	}
+
+	if got := font.DecodeString([]byte("ABC")); got != "é€C" {
+		t.Errorf("Expected Differences to apply, got %q", got)
+	}
+}
+
+func TestType1CFont_BuiltinEncoding(t *testing.T) {
+	// Codes 0x01-0x02 -> glyphs 1-2, named "T" and "h"
+	program := buildCFF(testCFF{
+		numGlyphs: 3,
+		charset:   charsetFormat0(53, 73),
+		encoding:  []byte{0, 2, 0x01, 0x02},
+	})
+
+	fontDict := core.Dict{
+		"Type":           core.Name("Font"),
+		"Subtype":        core.Name("Type1"),
+		"BaseFont":       core.Name("ABCDEF+Minion"),
+		"FontDescriptor": descriptorWith("FontFile3"),
+	}
+	resolver := mapResolver(map[int]core.Object{
+		10: &core.Stream{Dict: core.Dict{"Subtype": core.Name("Type1C")}, Data: program},
+	})
+
+	font, err := NewType1Font(fontDict, resolver)
+	if err != nil {
+		t.Fatalf("NewType1Font failed: %v", err)
+	}
+
+	if got := font.DecodeString([]byte{1, 2}); got != "Th" {
+		t.Errorf("Expected CFF encoding to decode, got %q", got)
+	}
+}
+
+func TestTrueTypeFont_RecoverFromPost(t *testing.T) {
+	// A symbolic subset: codes 1-3 map through the (3,0) subtable to glyphs
+	// whose names are only known from the 'post' table
+	program := buildSfnt(map[string][]byte{
+		"head": make([]byte, 54),
+		"cmap": buildCmap(map[uint32]map[rune]uint16{
+			cmapWindowsSymbol: {0xF001: 3, 0xF002: 1, 0xF003: 2},
+		}),
+		"post": buildPost([]string{".notdef", "o", "uni00DF", "G"}),
+	})
+
+	fontDict := core.Dict{
+		"Type":           core.Name("Font"),
+		"Subtype":        core.Name("TrueType"),
+		"BaseFont":       core.Name("ABCDEF+Arial"),
+		"FontDescriptor": descriptorWith("FontFile2"),
+	}
+	resolver := mapResolver(map[int]core.Object{
+		10: &core.Stream{Dict: core.Dict{}, Data: program},
+	})
+
+	font, err := NewTrueTypeFont(fontDict, resolver)
+	if err != nil {
+		t.Fatalf("NewTrueTypeFont failed: %v", err)
+	}
+
+	if got := font.DecodeString([]byte{1, 2, 3}); got != "Goß" {
+		t.Errorf("Expected post names to decode, got %q", got)
+	}
+}
+
+func TestTrueTypeFont_UnicodeCmapLeavesEncoding(t *testing.T) {
+	// Only a Unicode subtable: the named encoding already yields Unicode
+	program := buildSfnt(map[string][]byte{
+		"head": make([]byte, 54),
+		"cmap": buildCmap(map[uint32]map[rune]uint16{
+			cmapWindowsBMP: {'A': 1},
+		}),
+	})
+
+	fontDict := core.Dict{
+		"Type":           core.Name("Font"),
+		"Subtype":        core.Name("TrueType"),
+		"BaseFont":       core.Name("Arial"),
+		"FontDescriptor": descriptorWith("FontFile2"),
+	}
+	resolver := mapResolver(map[int]core.Object{
+		10: &core.Stream{Dict: core.Dict{}, Data: program},
+	})
+
+	font, err := NewTrueTypeFont(fontDict, resolver)
+	if err != nil {
+		t.Fatalf("NewTrueTypeFont failed: %v", err)
+	}
+
+	if got := font.DecodeString([]byte("AB")); got != "AB" {
+		t.Errorf("Expected WinAnsi decoding, got %q", got)
+	}
+	if font.GetGlyphID('A') != 1 {
+		t.Errorf("Expected glyph 1 for 'A', got %d", font.GetGlyphID('A'))
+	}
+}
+
+// type0Dict returns an Identity-H Type0 font whose descendant is object 11
+func type0Dict() core.Dict {
+	return core.Dict{
+		"Type":            core.Name("Font"),
+		"Subtype":         core.Name("Type0"),
+		"BaseFont":        core.Name("ABCDEF+Subset"),
+		"Encoding":        core.Name("Identity-H"),
+		"DescendantFonts": core.Array{core.IndirectRef{Number: 11}},
+	}
+}
+
+// cidFontDict returns a descendant CIDFont referencing descriptor object 12
+func cidFontDict(subtype string) core.Dict {
+	return core.Dict{
+		"Type":     core.Name("Font"),
+		"Subtype":  core.Name(subtype),
+		"BaseFont": core.Name("ABCDEF+Subset"),
+		"CIDSystemInfo": core.Dict{
+			"Registry":   core.String("Adobe"),
+			"Ordering":   core.String("Identity"),
+			"Supplement": core.Int(0),
+		},
+		"FontDescriptor": core.IndirectRef{Number: 12},
+		"W":              core.Array{core.Int(1), core.Array{core.Int(600), core.Int(250)}},
+	}
+}
+
+func TestType0Font_RecoverTrueType(t *testing.T) {
+	// Chrome-style subset: glyph IDs in custom order, CIDToGIDMap Identity
+	program := buildSfnt(map[string][]byte{
+		"head": make([]byte, 54),
+		"cmap": buildCmap(map[uint32]map[rune]uint16{
+			cmapWindowsBMP: {'W': 1, ' ': 2, 0x4E2D: 3},
+		}),
+	})
+
+	resolver := mapResolver(map[int]core.Object{
+		10: &core.Stream{Dict: core.Dict{}, Data: program},
+		11: cidFontDict("CIDFontType2"),
+		12: descriptorWith("FontFile2"),
+	})
+
+	font, err := NewType0Font(type0Dict(), resolver)
+	if err != nil {
+		t.Fatalf("NewType0Font failed: %v", err)
+	}
+
+	if got := font.DecodeString([]byte{0, 1, 0, 2, 0, 3}); got != "W 中" {
+		t.Errorf("Expected font cmap to decode, got %q", got)
+	}
+	if w := font.Font.GetWidth(' '); w != 250 {
+		t.Errorf("Expected space width 250 from W array, got %v", w)
+	}
+}
+
+func TestType0Font_RecoverTrueTypeCIDToGIDMap(t *testing.T) {
+	program := buildSfnt(map[string][]byte{
+		"head": make([]byte, 54),
+		"post": buildPost([]string{".notdef", "x", "y"}),
+	})
+
+	// CID 0 -> GID 0, CID 1 -> GID 2, CID 2 -> GID 1
+	cidToGID := make([]byte, 6)
+	binary.BigEndian.PutUint16(cidToGID[2:], 2)
+	binary.BigEndian.PutUint16(cidToGID[4:], 1)
+
+	cidFont := cidFontDict("CIDFontType2")
+	cidFont["CIDToGIDMap"] = core.IndirectRef{Number: 13}
+
+	resolver := mapResolver(map[int]core.Object{
+		10: &core.Stream{Dict: core.Dict{}, Data: program},
+		11: cidFont,
+		12: descriptorWith("FontFile2"),
+		13: &core.Stream{Dict: core.Dict{}, Data: cidToGID},
+	})
+
+	font, err := NewType0Font(type0Dict(), resolver)
+	if err != nil {
+		t.Fatalf("NewType0Font failed: %v", err)
+	}
+
+	if got := font.DecodeString([]byte{0, 1, 0, 2}); got != "yx" {
+		t.Errorf("Expected CIDToGIDMap and post names to decode, got %q", got)
+	}
+}
+
+func TestType0Font_RecoverCIDKeyedCFF(t *testing.T) {
+	// InDesign-style OpenType CFF: CIDs 7 and 9 are glyphs 1 and 2, whose
+	// Unicode values come from the OpenType cmap
+	cff := buildCFF(testCFF{
+		numGlyphs: 3,
+		strings:   [][]byte{[]byte("Adobe"), []byte("Identity")},
+		charset:   charsetFormat0(7, 9),
+		cid:       true,
+	})
+	program := buildSfnt(map[string][]byte{
+		"CFF ": cff,
+		"cmap": buildCmap(map[uint32]map[rune]uint16{
+			cmapWindowsBMP: {'o': 1, 'k': 2},
+		}),
+	})
+	copy(program, "OTTO")
+
+	resolver := mapResolver(map[int]core.Object{
+		10: &core.Stream{Dict: core.Dict{"Subtype": core.Name("OpenType")}, Data: program},
+		11: cidFontDict("CIDFontType0"),
+		12: descriptorWith("FontFile3"),
+	})
+
+	font, err := NewType0Font(type0Dict(), resolver)
+	if err != nil {
+		t.Fatalf("NewType0Font failed: %v", err)
+	}
+
+	// CID 8 has no glyph and decodes to U+FFFD, keeping the text aligned
+	if got := font.DecodeString([]byte{0, 7, 0, 9, 0, 8}); got != "ok\uFFFD" {
+		t.Errorf("Expected CID charset and cmap to decode, got %q", got)
+	}
+}
+
+func TestType0Font_RecoverNameKeyedCFF(t *testing.T) {
+	// A bare name-keyed CFF as a CIDFontType0: CID == GID, names from charset
+	program := buildCFF(testCFF{
+		numGlyphs: 3,
+		charset:   charsetFormat0(71, 72), // "f", "g"
+	})
+
+	resolver := mapResolver(map[int]core.Object{
+		10: &core.Stream{Dict: core.Dict{"Subtype": core.Name("CIDFontType0C")}, Data: program},
+		11: cidFontDict("CIDFontType0"),
+		12: descriptorWith("FontFile3"),
+	})
+
+	font, err := NewType0Font(type0Dict(), resolver)
+	if err != nil {
+		t.Fatalf("NewType0Font failed: %v", err)
+	}
+
+	if got := font.DecodeString([]byte{0, 2, 0, 1}); got != "gf" {
+		t.Errorf("Expected CFF glyph names to decode, got %q", got)
+	}
+}
+
+func TestType0Font_ToUnicodeTakesPriority(t *testing.T) {
+	program := buildSfnt(map[string][]byte{
+		"cmap": buildCmap(map[uint32]map[rune]uint16{
+			cmapWindowsBMP: {'W': 1},
+		}),
+	})
+	cmap := `begincmap
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+1 beginbfchar
+<0001> <0058>
+endbfchar
+endcmap`
+
+	dict := type0Dict()
+	dict["ToUnicode"] = &core.Stream{Dict: core.Dict{}, Data: []byte(cmap)}
+	resolver := mapResolver(map[int]core.Object{
+		10: &core.Stream{Dict: core.Dict{}, Data: program},
+		11: cidFontDict("CIDFontType2"),
+		12: descriptorWith("FontFile2"),
+	})
+
+	font, err := NewType0Font(dict, resolver)
+	if err != nil {
+		t.Fatalf("NewType0Font failed: %v", err)
+	}
+
+	if got := font.DecodeString([]byte{0, 1}); got != "X" {
+		t.Errorf("Expected ToUnicode to take priority, got %q", got)
+	}
+}
+
+func TestParseType1BuiltinEncoding_Standard(t *testing.T) {
+	data := []byte("/FontName /Times-Roman def\n/Encoding StandardEncoding def\ncurrentfile eexec")
+	if names := parseType1BuiltinEncoding(data); names != nil {
+		t.Errorf("Expected nil for StandardEncoding, got %v", names)
+	}
+}
@@ -0,0 +1,85 @@
+//go:build ignore
+
+// gencmaps compresses Adobe's predefined CMap resources for embedding in
+// the font package. It walks a checkout of
+// https://github.com/adobe-type-tools/cmap-resources, or any directory of
+// CMap files, and writes each CMap resource to cmaps/<CMapName> as raw
+// DEFLATE data.
+//
+// Usage:
+//
+//	go run gencmaps.go path/to/cmap-resources
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+var cmapNamePattern = regexp.MustCompile(`/CMapName\s+/(\S+)\s+def`)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: go run gencmaps.go path/to/cmap-resources")
+	}
+
+	found := make(map[string][]byte)
+	err := filepath.WalkDir(os.Args[1], func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(data, []byte("%!PS-Adobe-3.0 Resource-CMap")) {
+			return nil
+		}
+		m := cmapNamePattern.FindSubmatch(data)
+		if m == nil {
+			return fmt.Errorf("%s: no CMap name", path)
+		}
+		found[string(m[1])] = data
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(found) == 0 {
+		log.Fatal("no CMap resources found")
+	}
+
+	if err := os.MkdirAll("cmaps", 0o755); err != nil {
+		log.Fatal(err)
+	}
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var buf bytes.Buffer
+		w, err := flate.NewWriter(&buf, flate.BestCompression)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, err := w.Write(found[name]); err != nil {
+			log.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join("cmaps", name), buf.Bytes(), 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+	fmt.Printf("wrote %d CMaps\n", len(names))
+}
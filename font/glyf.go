@@ -0,0 +1,350 @@
+package font
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/tsawler/tabula/model"
+)
+
+// maxCompositeDepth limits the nesting of composite TrueType glyphs
+const maxCompositeDepth = 8
+
+// maxGlyfPoints is the largest number of points a simple glyph can have
+const maxGlyfPoints = 0xFFFF
+
+// Simple glyph flags
+const (
+	glyfOnCurve     = 0x01
+	glyfXShort      = 0x02
+	glyfYShort      = 0x04
+	glyfRepeat      = 0x08
+	glyfXSameOrPlus = 0x10
+	glyfYSameOrPlus = 0x20
+)
+
+// Composite glyph component flags
+const (
+	compArgsAreWords   = 0x0001
+	compArgsAreXY      = 0x0002
+	compHaveScale      = 0x0008
+	compMoreComponents = 0x0020
+	compHaveXYScale    = 0x0040
+	compHaveTwoByTwo   = 0x0080
+)
+
+// trueTypeGlyphs reads glyph outlines from the 'glyf' and 'loca' tables of
+// a TrueType program
+type trueTypeGlyphs struct {
+	glyf       []byte
+	loca       []byte
+	longLoca   bool // 'loca' holds 32-bit offsets
+	numGlyphs  int
+	unitsPerEm float64
+	advances   []uint16 // Advance widths from 'hmtx', in font units
+}
+
+// newTrueTypeGlyphs prepares the glyph tables of a parsed TrueType program
+func newTrueTypeGlyphs(tables map[string][]byte) (*trueTypeGlyphs, error) {
+	head, ok := tables["head"]
+	if !ok || len(head) < 54 {
+		return nil, fmt.Errorf("missing or short head table")
+	}
+	glyf, ok := tables["glyf"]
+	if !ok {
+		return nil, fmt.Errorf("missing glyf table")
+	}
+	loca, ok := tables["loca"]
+	if !ok {
+		return nil, fmt.Errorf("missing loca table")
+	}
+
+	t := &trueTypeGlyphs{
+		glyf:       glyf,
+		loca:       loca,
+		longLoca:   binary.BigEndian.Uint16(head[50:]) != 0,
+		unitsPerEm: float64(binary.BigEndian.Uint16(head[18:])),
+	}
+	if t.unitsPerEm == 0 {
+		t.unitsPerEm = 1000
+	}
+	if t.longLoca {
+		t.numGlyphs = len(loca)/4 - 1
+	} else {
+		t.numGlyphs = len(loca)/2 - 1
+	}
+	if maxp, ok := tables["maxp"]; ok && len(maxp) >= 6 {
+		if n := int(binary.BigEndian.Uint16(maxp[4:])); n < t.numGlyphs {
+			t.numGlyphs = n
+		}
+	}
+
+	if hhea, ok := tables["hhea"]; ok && len(hhea) >= 36 {
+		count := int(binary.BigEndian.Uint16(hhea[34:]))
+		hmtx := tables["hmtx"]
+		if count*4 > len(hmtx) {
+			count = len(hmtx) / 4
+		}
+		t.advances = make([]uint16, count)
+		for i := range t.advances {
+			t.advances[i] = binary.BigEndian.Uint16(hmtx[i*4:])
+		}
+	}
+	return t, nil
+}
+
+// advance returns the advance width of a glyph in 1000ths of an em
+func (t *trueTypeGlyphs) advance(gid int) float64 {
+	if len(t.advances) == 0 {
+		return 0
+	}
+	if gid >= len(t.advances) {
+		gid = len(t.advances) - 1 // Glyphs past the last metric share its advance
+	}
+	return float64(t.advances[gid]) * 1000 / t.unitsPerEm
+}
+
+// outline returns a glyph's outline, or nil for empty or unreadable glyphs
+func (t *trueTypeGlyphs) outline(gid int) Outline {
+	b := newOutlineBuilder(1 / t.unitsPerEm)
+	t.appendGlyph(b, gid, model.Identity(), 0)
+	return b.outline()
+}
+
+// glyphData returns the 'glyf' entry of a glyph
+func (t *trueTypeGlyphs) glyphData(gid int) []byte {
+	if gid < 0 || gid >= t.numGlyphs {
+		return nil
+	}
+	var start, end int
+	if t.longLoca {
+		start = int(binary.BigEndian.Uint32(t.loca[gid*4:]))
+		end = int(binary.BigEndian.Uint32(t.loca[gid*4+4:]))
+	} else {
+		start = int(binary.BigEndian.Uint16(t.loca[gid*2:])) * 2
+		end = int(binary.BigEndian.Uint16(t.loca[gid*2+2:])) * 2
+	}
+	if start >= end || end > len(t.glyf) || end-start < 10 {
+		return nil
+	}
+	return t.glyf[start:end]
+}
+
+// appendGlyph adds a glyph's contours, transformed by m, to the builder
+func (t *trueTypeGlyphs) appendGlyph(b *outlineBuilder, gid int, m model.Matrix, depth int) {
+	data := t.glyphData(gid)
+	if data == nil || depth > maxCompositeDepth {
+		return
+	}
+	numContours := int(int16(binary.BigEndian.Uint16(data)))
+	if numContours >= 0 {
+		t.appendSimpleGlyph(b, data, numContours, m)
+	} else {
+		t.appendCompositeGlyph(b, data, m, depth)
+	}
+}
+
+// glyfPoint is a point of a simple glyph's contour
+type glyfPoint struct {
+	x, y    float64
+	onCurve bool
+}
+
+// appendSimpleGlyph adds the quadratic contours of a simple glyph
+func (t *trueTypeGlyphs) appendSimpleGlyph(b *outlineBuilder, data []byte, numContours int, m model.Matrix) {
+	pos := 10
+	if pos+numContours*2+2 > len(data) {
+		return
+	}
+	endPts := make([]int, numContours)
+	for i := range endPts {
+		endPts[i] = int(binary.BigEndian.Uint16(data[pos:]))
+		pos += 2
+	}
+	if numContours == 0 {
+		return
+	}
+	numPoints := endPts[numContours-1] + 1
+	if numPoints > maxGlyfPoints {
+		return
+	}
+	pos += 2 + int(binary.BigEndian.Uint16(data[pos:])) // Skip the instructions
+
+	flags := make([]byte, 0, numPoints)
+	for len(flags) < numPoints {
+		if pos >= len(data) {
+			return
+		}
+		flag := data[pos]
+		pos++
+		flags = append(flags, flag)
+		if flag&glyfRepeat != 0 {
+			if pos >= len(data) {
+				return
+			}
+			for n := int(data[pos]); n > 0 && len(flags) < numPoints; n-- {
+				flags = append(flags, flag)
+			}
+			pos++
+		}
+	}
+
+	points := make([]glyfPoint, numPoints)
+	var ok bool
+	if pos, ok = readGlyfCoords(data, pos, flags, points, glyfXShort, glyfXSameOrPlus, true); !ok {
+		return
+	}
+	if _, ok = readGlyfCoords(data, pos, flags, points, glyfYShort, glyfYSameOrPlus, false); !ok {
+		return
+	}
+	for i := range points {
+		points[i].onCurve = flags[i]&glyfOnCurve != 0
+		p := m.Transform(model.Point{X: points[i].x, Y: points[i].y})
+		points[i].x, points[i].y = p.X, p.Y
+	}
+
+	start := 0
+	for _, end := range endPts {
+		if end < start || end >= numPoints {
+			return
+		}
+		appendQuadContour(b, points[start:end+1])
+		start = end + 1
+	}
+}
+
+// readGlyfCoords reads the x or y coordinates of a simple glyph's points,
+// which are stored as deltas
+func readGlyfCoords(data []byte, pos int, flags []byte, points []glyfPoint, short, sameOrPlus byte, isX bool) (int, bool) {
+	v := 0
+	for i, flag := range flags {
+		switch {
+		case flag&short != 0:
+			if pos >= len(data) {
+				return pos, false
+			}
+			d := int(data[pos])
+			pos++
+			if flag&sameOrPlus == 0 {
+				d = -d
+			}
+			v += d
+		case flag&sameOrPlus == 0:
+			if pos+2 > len(data) {
+				return pos, false
+			}
+			v += int(int16(binary.BigEndian.Uint16(data[pos:])))
+			pos += 2
+		}
+		if isX {
+			points[i].x = float64(v)
+		} else {
+			points[i].y = float64(v)
+		}
+	}
+	return pos, true
+}
+
+// appendQuadContour adds a contour of on- and off-curve points. Between two
+// consecutive off-curve points lies an implied on-curve point at their
+// midpoint.
+func appendQuadContour(b *outlineBuilder, pts []glyfPoint) {
+	if len(pts) == 0 {
+		return
+	}
+	mid := func(p, q glyfPoint) glyfPoint {
+		return glyfPoint{x: (p.x + q.x) / 2, y: (p.y + q.y) / 2, onCurve: true}
+	}
+
+	// Start at an on-curve point, or at an implied one if there is none
+	first := -1
+	for i, p := range pts {
+		if p.onCurve {
+			first = i
+			break
+		}
+	}
+	var start glyfPoint
+	if first >= 0 {
+		start = pts[first]
+		pts = append(append([]glyfPoint{}, pts[first+1:]...), pts[:first]...)
+	} else {
+		start = mid(pts[0], pts[len(pts)-1])
+	}
+	b.moveTo(start.x, start.y)
+
+	var control *glyfPoint
+	for i := range pts {
+		p := pts[i]
+		switch {
+		case p.onCurve && control == nil:
+			b.lineTo(p.x, p.y)
+		case p.onCurve:
+			b.quadTo(control.x, control.y, p.x, p.y)
+			control = nil
+		case control != nil:
+			m := mid(*control, p)
+			b.quadTo(control.x, control.y, m.x, m.y)
+			control = &pts[i]
+		default:
+			control = &pts[i]
+		}
+	}
+	if control != nil {
+		b.quadTo(control.x, control.y, start.x, start.y)
+	}
+	b.closePath()
+}
+
+// appendCompositeGlyph adds the components of a composite glyph, each
+// transformed by its offset and scale
+func (t *trueTypeGlyphs) appendCompositeGlyph(b *outlineBuilder, data []byte, m model.Matrix, depth int) {
+	pos := 10
+	for {
+		if pos+4 > len(data) {
+			return
+		}
+		flags := binary.BigEndian.Uint16(data[pos:])
+		component := int(binary.BigEndian.Uint16(data[pos+2:]))
+		pos += 4
+
+		var dx, dy float64
+		if flags&compArgsAreWords != 0 {
+			if pos+4 > len(data) {
+				return
+			}
+			dx = float64(int16(binary.BigEndian.Uint16(data[pos:])))
+			dy = float64(int16(binary.BigEndian.Uint16(data[pos+2:])))
+			pos += 4
+		} else {
+			if pos+2 > len(data) {
+				return
+			}
+			dx, dy = float64(int8(data[pos])), float64(int8(data[pos+1]))
+			pos += 2
+		}
+		if flags&compArgsAreXY == 0 {
+			dx, dy = 0, 0 // Point matching is not supported
+		}
+
+		a, bb, c, d := 1.0, 0.0, 0.0, 1.0
+		f2dot14 := func(i int) float64 { return float64(int16(binary.BigEndian.Uint16(data[pos+i:]))) / 16384 }
+		switch {
+		case flags&compHaveScale != 0 && pos+2 <= len(data):
+			a = f2dot14(0)
+			d = a
+			pos += 2
+		case flags&compHaveXYScale != 0 && pos+4 <= len(data):
+			a, d = f2dot14(0), f2dot14(2)
+			pos += 4
+		case flags&compHaveTwoByTwo != 0 && pos+8 <= len(data):
+			a, bb, c, d = f2dot14(0), f2dot14(2), f2dot14(4), f2dot14(6)
+			pos += 8
+		}
+
+		t.appendGlyph(b, component, model.Matrix{a, bb, c, d, dx, dy}.Multiply(m), depth+1)
+		if flags&compMoreComponents == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package font
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// appendUint16 appends a big-endian 16-bit value
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+// buildGlyfTables assembles head, loca (short offsets), glyf, maxp, hhea
+// and hmtx tables for the given glyph entries and advances
+func buildGlyfTables(glyphs [][]byte, advances []uint16) map[string][]byte {
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], 1000) // unitsPerEm
+
+	var glyf []byte
+	loca := make([]byte, 0, 2*(len(glyphs)+1))
+	for _, g := range glyphs {
+		loca = appendUint16(loca, uint16(len(glyf)/2))
+		glyf = append(glyf, g...)
+		if len(glyf)%2 == 1 {
+			glyf = append(glyf, 0)
+		}
+	}
+	loca = appendUint16(loca, uint16(len(glyf)/2))
+
+	maxp := make([]byte, 6)
+	binary.BigEndian.PutUint16(maxp[4:], uint16(len(glyphs)))
+	hhea := make([]byte, 36)
+	binary.BigEndian.PutUint16(hhea[34:], uint16(len(advances)))
+	var hmtx []byte
+	for _, a := range advances {
+		hmtx = appendUint16(hmtx, a)
+		hmtx = appendUint16(hmtx, 0)
+	}
+	return map[string][]byte{"head": head, "loca": loca, "glyf": glyf, "maxp": maxp, "hhea": hhea, "hmtx": hmtx}
+}
+
+// squareGlyph encodes a simple glyph with one contour of on-curve points
+// at (0,0), (0,size), (size,size) and (size,0)
+func squareGlyph(size int16) []byte {
+	g := make([]byte, 10)
+	binary.BigEndian.PutUint16(g, 1) // numberOfContours
+	g = appendUint16(g, 3)
+	g = appendUint16(g, 0) // No instructions
+	for i := 0; i < 4; i++ {
+		g = append(g, glyfOnCurve)
+	}
+	for _, dx := range []int16{0, 0, size, 0} {
+		g = appendUint16(g, uint16(dx))
+	}
+	for _, dy := range []int16{0, size, 0, -size} {
+		g = appendUint16(g, uint16(dy))
+	}
+	return g
+}
+
+func TestTrueTypeGlyphsSimple(t *testing.T) {
+	tables := buildGlyfTables([][]byte{nil, squareGlyph(500)}, []uint16{0, 600})
+	glyphs, err := newTrueTypeGlyphs(tables)
+	if err != nil {
+		t.Fatalf("newTrueTypeGlyphs failed: %v", err)
+	}
+
+	if o := glyphs.outline(0); o != nil {
+		t.Errorf("Expected no outline for the empty glyph, got %v", o)
+	}
+
+	o := glyphs.outline(1)
+	if len(o) != 5 {
+		t.Fatalf("Expected 5 segments, got %d: %v", len(o), o)
+	}
+	if o[0].Op != OutlineMoveTo || o[4].Op != OutlineClose {
+		t.Errorf("Expected a closed contour, got %v", o)
+	}
+	if p := o[2].Points[0]; p.X != 0.5 || p.Y != 0.5 {
+		t.Errorf("Expected (0.5, 0.5), got %v", p)
+	}
+
+	if w := glyphs.advance(1); w != 600 {
+		t.Errorf("Expected advance 600, got %v", w)
+	}
+	if w := glyphs.advance(5); w != 600 {
+		t.Errorf("Expected the last advance for glyphs past hmtx, got %v", w)
+	}
+}
+
+func TestTrueTypeGlyphsComposite(t *testing.T) {
+	comp := make([]byte, 10)
+	binary.BigEndian.PutUint16(comp, 0xFFFF) // numberOfContours = -1
+	comp = appendUint16(comp, compArgsAreWords|compArgsAreXY)
+	comp = appendUint16(comp, 1) // Component glyph
+	comp = appendUint16(comp, 100)
+	comp = appendUint16(comp, 0)
+
+	glyphs, err := newTrueTypeGlyphs(buildGlyfTables([][]byte{nil, squareGlyph(500), comp}, []uint16{0, 600, 600}))
+	if err != nil {
+		t.Fatalf("newTrueTypeGlyphs failed: %v", err)
+	}
+	o := glyphs.outline(2)
+	if len(o) != 5 {
+		t.Fatalf("Expected 5 segments, got %d", len(o))
+	}
+	if p := o[0].Points[0]; p.X != 0.1 || p.Y != 0 {
+		t.Errorf("Expected the component offset to (0.1, 0), got %v", p)
+	}
+}
+
+func TestAppendQuadContour(t *testing.T) {
+	b := newOutlineBuilder(1)
+	// Two consecutive off-curve points imply an on-curve point between them
+	appendQuadContour(b, []glyfPoint{
+		{x: 0, y: 0, onCurve: true},
+		{x: 0, y: 10},
+		{x: 10, y: 10},
+		{x: 10, y: 0, onCurve: true},
+	})
+	o := b.outline()
+
+	var quads int
+	for _, s := range o {
+		if s.Op == OutlineQuadTo {
+			quads++
+		}
+	}
+	if quads != 2 {
+		t.Fatalf("Expected 2 quadratic segments, got %d: %v", quads, o)
+	}
+	if p := o[1].Points[1]; p.X != 5 || p.Y != 10 {
+		t.Errorf("Expected the implied point (5, 10), got %v", p)
+	}
+}
@@ -0,0 +1,499 @@
+package font
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/model"
+)
+
+// Glyph is the glyph shown for one character code of a string
+type Glyph struct {
+	Code    uint32  // Character code
+	Width   float64 // Advance width in 1000ths of an em
+	Outline Outline // Nil if the font has no outline for the code
+	Space   bool    // Single-byte code 32, to which word spacing applies
+}
+
+// Bounds of the horizontal scale applied to substitute glyphs to match
+// the widths of the font they stand in for
+const (
+	minSubstituteScale = 0.6
+	maxSubstituteScale = 1.4
+)
+
+// GlyphSet maps the character codes of a font to glyph outlines, read from
+// the embedded font program (TrueType, CFF or Type 1), or from a
+// substitute TrueType program for fonts that are not embedded
+type GlyphSet struct {
+	font *Font
+
+	// Simple fonts
+	firstChar    int
+	widths       []float64
+	missingWidth float64
+	names        [256]string // Glyph name by code, from the encoding
+	runes        [256]rune   // Unicode by code, from the encoding
+	symbolic     bool
+
+	// Composite fonts
+	composite bool
+	cmap      *CMap // Encoding CMap, nil for Identity
+	cidFont   *CIDFont
+	cidToGID  []byte // CIDToGIDMap stream data, nil for Identity
+
+	// Font programs
+	trueType *trueTypeGlyphs
+	cmaps    map[uint32]map[rune]uint16 // cmap subtables of a TrueType program
+	postGIDs map[string]int             // Glyph IDs by 'post' name
+	cff      *cffFont
+	cffGIDs  map[uint16]uint16 // Glyph IDs by CID of a CID-keyed CFF program
+	type1    *type1Program
+
+	substitute     *trueTypeGlyphs
+	substituteCmap map[rune]uint16
+
+	outlines map[uint32]Outline
+}
+
+// NewGlyphSet reads the glyphs of a Type1, TrueType or Type0 font. Type3
+// fonts, whose glyphs are content streams, are not supported.
+func NewGlyphSet(fontDict core.Dict, resolver func(core.IndirectRef) (core.Object, error)) (*GlyphSet, error) {
+	g := &GlyphSet{outlines: make(map[uint32]Outline)}
+
+	var fd *FontDescriptor
+	switch subtype := extractName(fontDict.Get("Subtype")); subtype {
+	case "Type1":
+		t1, err := NewType1Font(fontDict, resolver)
+		if err != nil {
+			return nil, err
+		}
+		g.font, fd = t1.Font, t1.FontDescriptor
+		g.firstChar, g.widths = t1.FirstChar, t1.Widths
+		var builtin map[byte]string
+		if t1.usesBuiltinEncoding {
+			builtin = fd.builtinGlyphNames()
+		}
+		g.setNames(GetEncoding(t1.Encoding), builtin, t1.Differences)
+
+	case "TrueType":
+		tt, err := NewTrueTypeFont(fontDict, resolver)
+		if err != nil {
+			return nil, err
+		}
+		g.font, fd = tt.Font, tt.FontDescriptor
+		g.firstChar, g.widths = tt.FirstChar, tt.Widths
+		g.setNames(GetEncoding(tt.Encoding), nil, tt.Differences)
+
+	case "Type0":
+		t0, err := NewType0Font(fontDict, resolver)
+		if err != nil {
+			return nil, err
+		}
+		if t0.DescendantFont == nil {
+			return nil, fmt.Errorf("missing descendant font")
+		}
+		g.font, g.composite, g.cmap, g.cidFont = t0.Font, true, t0.CMap, t0.DescendantFont
+		fd = t0.DescendantFont.FontDescriptor
+		if m := t0.DescendantFont.CIDToGIDMap; m != nil {
+			g.cidToGID, _ = m.Decode()
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported font subtype: %s", subtype)
+	}
+
+	if fd != nil {
+		g.missingWidth = fd.MissingWidth
+		g.symbolic = fd.Flags&FlagSymbolic != 0
+		g.loadProgram(fd)
+	}
+	return g, nil
+}
+
+// setNames records the glyph name and Unicode of each single-byte code,
+// from the base encoding, the font program's built-in encoding and the
+// Differences array, in increasing order of precedence
+func (g *GlyphSet) setNames(base Encoding, builtin, differences map[byte]string) {
+	for c := 0; c < 256; c++ {
+		g.runes[c] = base.Decode(byte(c))
+		g.names[c] = glyphNameForRune(g.runes[c])
+	}
+	for _, names := range []map[byte]string{builtin, differences} {
+		for code, name := range names {
+			g.names[code] = name
+			g.runes[code], _ = GlyphNameToRune(name)
+		}
+	}
+}
+
+// loadProgram reads the embedded font program of a descriptor, if it can
+func (g *GlyphSet) loadProgram(fd *FontDescriptor) {
+	switch {
+	case fd.FontFile2 != nil:
+		data, err := fd.FontFile2.Decode()
+		if err != nil {
+			return
+		}
+		tables, err := parseSfntTables(data)
+		if err != nil {
+			return
+		}
+		if g.trueType, err = newTrueTypeGlyphs(tables); err != nil {
+			return
+		}
+		if cmapData, ok := tables["cmap"]; ok {
+			g.cmaps, _ = parseCmapSubtables(cmapData)
+		}
+		if postData, ok := tables["post"]; ok {
+			if names, err := parsePostGlyphNames(postData); err == nil {
+				g.postGIDs = make(map[string]int, len(names))
+				for gid, name := range names {
+					if _, dup := g.postGIDs[name]; !dup {
+						g.postGIDs[name] = gid
+					}
+				}
+			}
+		}
+
+	case fd.FontFile3 != nil:
+		cff, subtables, err := loadCFF(fd.FontFile3)
+		if err != nil {
+			return
+		}
+		g.cff, g.cmaps = cff, subtables
+		if cff.isCID {
+			g.cffGIDs = cff.cidToGID()
+		}
+
+	case fd.FontFile != nil:
+		data, err := fd.FontFile.Decode()
+		if err != nil {
+			return
+		}
+		if p, ok := parseType1Program(data); ok {
+			g.type1 = p
+		}
+	}
+}
+
+// Font returns the parsed font
+func (g *GlyphSet) Font() *Font {
+	return g.font
+}
+
+// Embedded reports whether glyph outlines come from an embedded font
+// program
+func (g *GlyphSet) Embedded() bool {
+	return g.trueType != nil || g.cff != nil || g.type1 != nil
+}
+
+// SetSubstitute sets a TrueType program whose glyphs stand in for those of
+// a font that is not embedded. Codes are mapped to its glyphs through
+// their Unicode values, and each glyph is stretched horizontally towards
+// the width the PDF gives it.
+func (g *GlyphSet) SetSubstitute(program []byte) error {
+	tables, err := parseSfntTables(program)
+	if err != nil {
+		return err
+	}
+	glyphs, err := newTrueTypeGlyphs(tables)
+	if err != nil {
+		return err
+	}
+	subtables, err := parseCmapSubtables(tables["cmap"])
+	if err != nil {
+		return err
+	}
+	cmap := unicodeCmap(subtables)
+	if cmap == nil {
+		return fmt.Errorf("substitute font has no Unicode cmap")
+	}
+	g.substitute, g.substituteCmap = glyphs, cmap
+	g.outlines = make(map[uint32]Outline)
+	return nil
+}
+
+// Glyphs splits a string into the glyphs it shows
+func (g *GlyphSet) Glyphs(data []byte) []Glyph {
+	if !g.composite {
+		glyphs := make([]Glyph, len(data))
+		for i, b := range data {
+			glyphs[i] = Glyph{
+				Code:    uint32(b),
+				Width:   g.simpleWidth(b),
+				Outline: g.outline(uint32(b), data[i:i+1]),
+				Space:   b == ' ',
+			}
+		}
+		return glyphs
+	}
+
+	var glyphs []Glyph
+	for len(data) > 0 {
+		var code uint32
+		n := 2
+		if g.cmap != nil {
+			code, n = g.cmap.nextCode(data)
+		} else if len(data) >= 2 {
+			code = uint32(binary.BigEndian.Uint16(data))
+		} else {
+			code, n = uint32(data[0]), 1
+		}
+		if n <= 0 {
+			break
+		}
+		glyphs = append(glyphs, Glyph{
+			Code:    code,
+			Width:   g.cidFont.GetWidthForCID(int(g.cid(code))),
+			Outline: g.outline(code, data[:n]),
+			Space:   n == 1 && code == ' ',
+		})
+		data = data[n:]
+	}
+	return glyphs
+}
+
+// cid maps a composite font's character code to its CID
+func (g *GlyphSet) cid(code uint32) uint32 {
+	if g.cmap == nil {
+		return code
+	}
+	cid, _ := g.cmap.LookupCID(code)
+	return cid
+}
+
+// simpleWidth returns the width of a simple font's code, from /Widths,
+// then the descriptor's /MissingWidth, then the font's metrics
+func (g *GlyphSet) simpleWidth(code byte) float64 {
+	if i := int(code) - g.firstChar; i >= 0 && i < len(g.widths) {
+		return g.widths[i]
+	}
+	if len(g.widths) > 0 || g.missingWidth != 0 {
+		return g.missingWidth
+	}
+	if g.trueType != nil {
+		if gid, ok := g.trueTypeGID(code); ok {
+			return g.trueType.advance(gid)
+		}
+	}
+	return g.font.GetWidth(g.runes[code])
+}
+
+// outline returns the outline of a code, whose bytes in the string are raw
+func (g *GlyphSet) outline(code uint32, raw []byte) Outline {
+	if o, ok := g.outlines[code]; ok {
+		return o
+	}
+	var o Outline
+	switch {
+	case g.Embedded():
+		o = g.programOutline(code)
+	case g.substitute != nil:
+		o = g.substituteOutline(code, raw)
+	}
+	g.outlines[code] = o
+	return o
+}
+
+// programOutline returns a code's outline from the embedded font program
+func (g *GlyphSet) programOutline(code uint32) Outline {
+	if g.composite {
+		cid := g.cid(code)
+		switch {
+		case g.trueType != nil:
+			gid := int(cid)
+			if g.cidToGID != nil {
+				if int(cid)*2+1 >= len(g.cidToGID) {
+					return nil
+				}
+				gid = int(binary.BigEndian.Uint16(g.cidToGID[cid*2:]))
+			}
+			return g.trueType.outline(gid)
+		case g.cff != nil:
+			gid := int(cid)
+			if g.cff.isCID {
+				mapped, ok := g.cffGIDs[uint16(cid)]
+				if !ok {
+					return nil
+				}
+				gid = int(mapped)
+			}
+			return g.cff.outline(gid)
+		}
+		return nil
+	}
+
+	b := byte(code)
+	switch {
+	case g.trueType != nil:
+		if gid, ok := g.trueTypeGID(b); ok {
+			return g.trueType.outline(gid)
+		}
+	case g.cff != nil:
+		if gid, ok := g.cffGID(b); ok {
+			return g.cff.outline(gid)
+		}
+	case g.type1 != nil:
+		for _, name := range g.candidateNames(b) {
+			if o := g.type1.outline(name); o != nil {
+				return o
+			}
+		}
+	}
+	return nil
+}
+
+// candidateNames returns the glyph names to look up for a code: its
+// encoding name, then the other names of its Unicode value
+func (g *GlyphSet) candidateNames(code byte) []string {
+	var names []string
+	if g.names[code] != "" {
+		names = append(names, g.names[code])
+	}
+	if r := g.runes[code]; r != 0 {
+		names = append(names, runeGlyphNames(r)...)
+	}
+	return names
+}
+
+// cffGID maps a simple font's code to a CFF glyph, by name through the
+// charset, then through the program's built-in encoding
+func (g *GlyphSet) cffGID(code byte) (int, bool) {
+	for _, name := range g.candidateNames(code) {
+		if gid, ok := g.cff.glyphIndex(name); ok {
+			return gid, true
+		}
+	}
+	if gid, ok := g.cff.encoding[code]; ok {
+		return int(gid), true
+	}
+	return 0, false
+}
+
+// trueTypeGID maps a simple font's code to a TrueType glyph
+// (PDF 32000-1:2008, 9.6.6.4). Non-symbolic fonts go through the Unicode
+// cmap; symbolic fonts through the (3,0) cmap, whose codes may be offset to
+// U+F000, or the (1,0) cmap. The 'post' glyph names come last, and a font
+// without a cmap uses the code as the glyph ID.
+func (g *GlyphSet) trueTypeGID(code byte) (int, bool) {
+	unicode := g.cmaps[cmapWindowsBMP]
+	if unicode != nil && !g.symbolic {
+		if gid, ok := unicode[g.runes[code]]; ok && g.runes[code] != 0 {
+			return int(gid), true
+		}
+	}
+	if symbol, ok := g.cmaps[cmapWindowsSymbol]; ok {
+		for _, base := range []rune{0, 0xF000, 0xF100, 0xF200} {
+			if gid, ok := symbol[base+rune(code)]; ok {
+				return int(gid), true
+			}
+		}
+	}
+	if mac, ok := g.cmaps[cmapMacRoman]; ok {
+		if gid, ok := mac[rune(code)]; ok {
+			return int(gid), true
+		}
+	}
+	if gid, ok := g.postGIDs[g.names[code]]; ok && g.names[code] != "" {
+		return gid, true
+	}
+	if unicode != nil {
+		if gid, ok := unicode[g.runes[code]]; ok && g.runes[code] != 0 {
+			return int(gid), true
+		}
+	}
+	if len(g.cmaps) == 0 {
+		return int(code), true
+	}
+	return 0, false
+}
+
+// substituteOutline returns the substitute font's glyph for the character
+// a code decodes to, scaled horizontally to the code's width
+func (g *GlyphSet) substituteOutline(code uint32, raw []byte) Outline {
+	var r rune
+	for _, c := range g.font.DecodeString(raw) {
+		r = c
+		break
+	}
+	if r == 0 && !g.composite {
+		r = g.runes[code]
+	}
+	gid, ok := g.substituteCmap[r]
+	if !ok || r == 0 {
+		return nil
+	}
+	o := g.substitute.outline(int(gid))
+
+	var width float64
+	if g.composite {
+		width = g.cidFont.GetWidthForCID(int(g.cid(code)))
+	} else {
+		width = g.simpleWidth(byte(code))
+	}
+	advance := g.substitute.advance(int(gid))
+	if width <= 0 || advance <= 0 {
+		return o
+	}
+	scale := width / advance
+	if scale < minSubstituteScale {
+		scale = minSubstituteScale
+	} else if scale > maxSubstituteScale {
+		scale = maxSubstituteScale
+	}
+	m := model.Scale(scale, 1)
+	for i := range o {
+		for j := range o[i].Points {
+			o[i].Points[j] = m.Transform(o[i].Points[j])
+		}
+	}
+	return o
+}
+
+var (
+	runeNamesOnce sync.Once
+	runeNames     map[rune][]string // Glyph names by Unicode, preferred first
+)
+
+// runeGlyphNames returns the glyph names of a Unicode value: its Adobe
+// Glyph List names, shortest first, then its "uniXXXX" name
+func runeGlyphNames(r rune) []string {
+	runeNamesOnce.Do(func() {
+		runeNames = make(map[rune][]string, len(glyphNameToUnicode))
+		for name, r := range glyphNameToUnicode {
+			runeNames[r] = append(runeNames[r], name)
+		}
+		for _, names := range runeNames {
+			sort.Slice(names, func(i, j int) bool {
+				if len(names[i]) != len(names[j]) {
+					return len(names[i]) < len(names[j])
+				}
+				return names[i] < names[j]
+			})
+		}
+	})
+	names := runeNames[r]
+	if r <= 0xFFFF {
+		names = append(names[:len(names):len(names)], fmt.Sprintf("uni%04X", r))
+	}
+	return names
+}
+
+// glyphNameForRune returns the preferred glyph name of a Unicode value, or
+// "" for 0
+func glyphNameForRune(r rune) string {
+	if names := runeGlyphNames(r); r != 0 && len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// standardGlyphName returns the name StandardEncoding gives a code, as
+// used by the seac accent operator
+func standardGlyphName(code byte) string {
+	return glyphNameForRune(StandardEncodingTable.Decode(code))
+}
@@ -0,0 +1,146 @@
+package font
+
+import (
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// trueTypeFontDict returns a simple TrueType font dictionary, embedding
+// program if it is not nil
+func trueTypeFontDict(program []byte) core.Dict {
+	fd := core.Dict{
+		"Type":     core.Name("FontDescriptor"),
+		"FontName": core.Name("Test"),
+		"Flags":    core.Int(FlagNonsymbolic),
+	}
+	if program != nil {
+		fd["FontFile2"] = &core.Stream{Dict: core.Dict{}, Data: program}
+	}
+	return core.Dict{
+		"Type":           core.Name("Font"),
+		"Subtype":        core.Name("TrueType"),
+		"BaseFont":       core.Name("Test"),
+		"FirstChar":      core.Int(65),
+		"LastChar":       core.Int(66),
+		"Widths":         core.Array{core.Int(700), core.Int(800)},
+		"Encoding":       core.Name("WinAnsiEncoding"),
+		"FontDescriptor": fd,
+	}
+}
+
+func TestGlyphSetEmbeddedTrueType(t *testing.T) {
+	tables := buildGlyfTables([][]byte{nil, squareGlyph(500)}, []uint16{0, 600})
+	tables["cmap"] = buildCmap(map[uint32]map[rune]uint16{cmapWindowsBMP: {'A': 1}})
+
+	g, err := NewGlyphSet(trueTypeFontDict(buildSfnt(tables)), nil)
+	if err != nil {
+		t.Fatalf("NewGlyphSet failed: %v", err)
+	}
+	if !g.Embedded() {
+		t.Fatal("Expected an embedded font program")
+	}
+
+	glyphs := g.Glyphs([]byte("AB "))
+	if len(glyphs) != 3 {
+		t.Fatalf("Expected 3 glyphs, got %d", len(glyphs))
+	}
+	if glyphs[0].Width != 700 || glyphs[1].Width != 800 {
+		t.Errorf("Expected widths 700 and 800, got %v and %v", glyphs[0].Width, glyphs[1].Width)
+	}
+	if len(glyphs[0].Outline) == 0 {
+		t.Error("Expected an outline for A")
+	}
+	if glyphs[1].Outline != nil {
+		t.Errorf("Expected no outline for B, which the cmap lacks, got %v", glyphs[1].Outline)
+	}
+	if !glyphs[2].Space || glyphs[0].Space {
+		t.Error("Expected only code 32 to be a space")
+	}
+}
+
+func TestGlyphSetSubstitute(t *testing.T) {
+	g, err := NewGlyphSet(trueTypeFontDict(nil), nil)
+	if err != nil {
+		t.Fatalf("NewGlyphSet failed: %v", err)
+	}
+	if g.Embedded() {
+		t.Fatal("Expected no embedded font program")
+	}
+	if o := g.Glyphs([]byte("A"))[0].Outline; o != nil {
+		t.Errorf("Expected no outline without a substitute, got %d segments", len(o))
+	}
+
+	if err := g.SetSubstitute(goregular.TTF); err != nil {
+		t.Fatalf("SetSubstitute failed: %v", err)
+	}
+	o := g.Glyphs([]byte("A"))[0].Outline
+	if len(o) == 0 {
+		t.Fatal("Expected an outline from the substitute font")
+	}
+	var maxX float64
+	for _, s := range o {
+		for _, p := range s.Points {
+			if p.X > maxX {
+				maxX = p.X
+			}
+		}
+	}
+	if maxX <= 0 || maxX > 0.7*maxSubstituteScale {
+		t.Errorf("Expected the glyph within its scaled width, got right edge %v", maxX)
+	}
+
+	if err := g.SetSubstitute([]byte("not a font")); err == nil {
+		t.Error("Expected an error for an invalid substitute program")
+	}
+}
+
+func TestGlyphSetComposite(t *testing.T) {
+	tables := buildGlyfTables([][]byte{nil, nil, squareGlyph(500)}, []uint16{0, 0, 600})
+	fontDict := core.Dict{
+		"Subtype":  core.Name("Type0"),
+		"BaseFont": core.Name("Test"),
+		"Encoding": core.Name("Identity-H"),
+		"DescendantFonts": core.Array{core.Dict{
+			"Subtype":  core.Name("CIDFontType2"),
+			"BaseFont": core.Name("Test"),
+			"CIDSystemInfo": core.Dict{
+				"Registry":   core.String("Adobe"),
+				"Ordering":   core.String("Identity"),
+				"Supplement": core.Int(0),
+			},
+			"DW": core.Int(1000),
+			"W":  core.Array{core.Int(2), core.Array{core.Int(600)}},
+			"FontDescriptor": core.Dict{
+				"FontName":  core.Name("Test"),
+				"Flags":     core.Int(FlagSymbolic),
+				"FontFile2": core.IndirectRef{Number: 10},
+			},
+		}},
+	}
+
+	program := &core.Stream{Dict: core.Dict{}, Data: buildSfnt(tables)}
+	resolver := func(core.IndirectRef) (core.Object, error) { return program, nil }
+
+	g, err := NewGlyphSet(fontDict, resolver)
+	if err != nil {
+		t.Fatalf("NewGlyphSet failed: %v", err)
+	}
+	glyphs := g.Glyphs([]byte{0, 2, 0, 1})
+	if len(glyphs) != 2 {
+		t.Fatalf("Expected 2 glyphs, got %d", len(glyphs))
+	}
+	if glyphs[0].Code != 2 || glyphs[0].Width != 600 || len(glyphs[0].Outline) == 0 {
+		t.Errorf("Expected CID 2 with width 600 and an outline, got %+v", glyphs[0])
+	}
+	if glyphs[1].Width != 1000 || glyphs[1].Outline != nil {
+		t.Errorf("Expected CID 1 with the default width and no outline, got %+v", glyphs[1])
+	}
+}
+
+func TestGlyphSetType3(t *testing.T) {
+	if _, err := NewGlyphSet(core.Dict{"Subtype": core.Name("Type3")}, nil); err == nil {
+		t.Error("Expected an error for a Type3 font")
+	}
+}
@@ -0,0 +1,89 @@
+package font
+
+import "github.com/tsawler/tabula/model"
+
+// OutlineOp is the kind of an outline segment
+type OutlineOp int
+
+const (
+	// OutlineMoveTo starts a contour at Points[0]
+	OutlineMoveTo OutlineOp = iota
+	// OutlineLineTo draws a line to Points[0]
+	OutlineLineTo
+	// OutlineQuadTo draws a quadratic Bézier curve with control point
+	// Points[0] to Points[1]
+	OutlineQuadTo
+	// OutlineCubeTo draws a cubic Bézier curve with control points
+	// Points[0] and Points[1] to Points[2]
+	OutlineCubeTo
+	// OutlineClose closes the contour
+	OutlineClose
+)
+
+// OutlineSegment is a segment of a glyph outline
+type OutlineSegment struct {
+	Op     OutlineOp
+	Points [3]model.Point
+}
+
+// Outline is a glyph outline in text space units: one unit is the font
+// size, the origin is the glyph origin, and y points up
+type Outline []OutlineSegment
+
+// outlineBuilder collects the segments of an outline, mapping glyph space
+// coordinates to text space through a transformation
+type outlineBuilder struct {
+	out    Outline
+	matrix model.Matrix
+	open   bool // Whether a contour is open
+}
+
+// newOutlineBuilder returns a builder that scales glyph coordinates by scale
+func newOutlineBuilder(scale float64) *outlineBuilder {
+	return &outlineBuilder{matrix: model.Scale(scale, scale)}
+}
+
+func (b *outlineBuilder) point(x, y float64) model.Point {
+	return b.matrix.Transform(model.Point{X: x, Y: y})
+}
+
+func (b *outlineBuilder) moveTo(x, y float64) {
+	b.closePath()
+	b.out = append(b.out, OutlineSegment{Op: OutlineMoveTo, Points: [3]model.Point{b.point(x, y)}})
+	b.open = true
+}
+
+func (b *outlineBuilder) lineTo(x, y float64) {
+	if !b.open {
+		b.moveTo(x, y)
+		return
+	}
+	b.out = append(b.out, OutlineSegment{Op: OutlineLineTo, Points: [3]model.Point{b.point(x, y)}})
+}
+
+func (b *outlineBuilder) quadTo(x1, y1, x2, y2 float64) {
+	if !b.open {
+		b.moveTo(x1, y1)
+	}
+	b.out = append(b.out, OutlineSegment{Op: OutlineQuadTo, Points: [3]model.Point{b.point(x1, y1), b.point(x2, y2)}})
+}
+
+func (b *outlineBuilder) cubeTo(x1, y1, x2, y2, x3, y3 float64) {
+	if !b.open {
+		b.moveTo(x1, y1)
+	}
+	b.out = append(b.out, OutlineSegment{Op: OutlineCubeTo, Points: [3]model.Point{b.point(x1, y1), b.point(x2, y2), b.point(x3, y3)}})
+}
+
+func (b *outlineBuilder) closePath() {
+	if b.open {
+		b.out = append(b.out, OutlineSegment{Op: OutlineClose})
+		b.open = false
+	}
+}
+
+// outline returns the collected outline, closing the last contour
+func (b *outlineBuilder) outline() Outline {
+	b.closePath()
+	return b.out
+}
@@ -0,0 +1,261 @@
+package font
+
+import (
+	"bytes"
+	"compress/flate"
+	"embed"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Predefined CMaps (PDF 32000-1:2008, 9.7.5.2) name the encodings of CJK
+// composite fonts, typically non-embedded fonts such as MS-Mincho with
+// 90ms-RKSJ-H. Adobe's CMap resources
+// (https://github.com/adobe-type-tools/cmap-resources) are embedded in the
+// package, compressed, and parsed the first time they are used. Codes are
+// mapped to CIDs through the CMap, so the CIDFont's /W widths apply, and
+// CIDs to Unicode through the character collection's ordering table.
+//
+// The ordering tables (Adobe-Japan1-UCS2 and the like) are built by
+// inverting the collection's Uni* CMaps. CMaps added with
+// RegisterCMap take precedence over the embedded ones.
+//
+// The embedded resources are regenerated with gencmaps.go; cmaps/LICENSE.md
+// holds Adobe's license for them.
+
+//go:embed cmaps
+var cmapResources embed.FS
+
+var (
+	registeredCMapsMu sync.RWMutex
+	registeredCMaps   = make(map[string]*CMap)
+
+	// Embedded CMaps and ordering tables, by name, once parsed
+	predefinedCMapsMu sync.RWMutex
+	predefinedCMaps   = make(map[string]*CMap)
+)
+
+// orderingSources lists, for each character collection, the Unicode CMaps
+// whose inverse is its CID-to-Unicode table: horizontal forms first, then
+// vertical ones and, for Japan1, the half-width Latin used by the RKSJ
+// CMaps
+var orderingSources = map[string][]string{
+	"Adobe-Japan1": {"UniJIS-UTF32-H", "UniJIS-UTF32-V", "UniJIS-UCS2-HW-H"},
+	"Adobe-GB1":    {"UniGB-UTF32-H", "UniGB-UTF32-V"},
+	"Adobe-CNS1":   {"UniCNS-UTF32-H", "UniCNS-UTF32-V"},
+	"Adobe-Korea1": {"UniKS-UTF32-H", "UniKS-UTF32-V"},
+	"Adobe-KR":     {"UniAKR-UTF32-H"},
+}
+
+// RegisterCMap parses a CMap resource and makes it available by name, for
+// predefined /Encoding names, usecmap references and CID-to-Unicode
+// ordering tables (e.g. Adobe-Japan1-UCS2), overriding the embedded
+// resource of the same name. If name is empty, the CMap's /CMapName is
+// used.
+func RegisterCMap(name string, data []byte) error {
+	cmap, err := parseCMapData(data)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = cmap.name
+	}
+	if name == "" {
+		return fmt.Errorf("CMap has no name")
+	}
+	if cmap.name == "" {
+		cmap.name = name
+	}
+
+	registeredCMapsMu.Lock()
+	registeredCMaps[name] = cmap
+	registeredCMapsMu.Unlock()
+	return nil
+}
+
+// PredefinedCMap returns a registered or embedded predefined CMap by name
+func PredefinedCMap(name string) (*CMap, bool) {
+	registeredCMapsMu.RLock()
+	cmap, ok := registeredCMaps[name]
+	registeredCMapsMu.RUnlock()
+	if ok {
+		return cmap, true
+	}
+
+	// Identity-H/V map 2-byte codes to the same CIDs
+	if name == "Identity-H" || name == "Identity-V" {
+		cmap = NewCMap()
+		cmap.name = name
+		cmap.vertical = name == "Identity-V"
+		cmap.codespaces = []codespaceRange{{low: []byte{0x00, 0x00}, high: []byte{0xFF, 0xFF}}}
+		cmap.cidRanges = []cidRange{{startCode: 0, endCode: 0xFFFF, startCID: 0}}
+		return cmap, true
+	}
+
+	if cmap := cachedCMap(name); cmap != nil {
+		return cmap, true
+	}
+	cmap, err := loadEmbeddedCMap(name)
+	if err != nil {
+		return nil, false
+	}
+	return cacheCMap(name, cmap), true
+}
+
+// loadEmbeddedCMap decompresses and parses one of Adobe's CMap resources.
+// CMaps it uses are loaded in turn through PredefinedCMap.
+func loadEmbeddedCMap(name string) (*CMap, error) {
+	// Adobe's CMap names never contain a dot or a slash, so this also keeps
+	// the license and other paths out
+	if name == "" || strings.ContainsAny(name, "./\\") {
+		return nil, fmt.Errorf("invalid CMap name: %q", name)
+	}
+	compressed, err := cmapResources.ReadFile("cmaps/" + name)
+	if err != nil {
+		return nil, err
+	}
+	r := flate.NewReader(bytes.NewReader(compressed))
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress CMap %s: %w", name, err)
+	}
+
+	cmap, err := parseCMapData(data)
+	if err != nil {
+		return nil, err
+	}
+	if cmap.name == "" {
+		cmap.name = name
+	}
+	return cmap, nil
+}
+
+// cachedCMap returns an embedded CMap or ordering table parsed earlier
+func cachedCMap(name string) *CMap {
+	predefinedCMapsMu.RLock()
+	defer predefinedCMapsMu.RUnlock()
+	return predefinedCMaps[name]
+}
+
+// cacheCMap stores a parsed CMap, returning the one stored first when
+// another goroutine got there before
+func cacheCMap(name string, cmap *CMap) *CMap {
+	predefinedCMapsMu.Lock()
+	defer predefinedCMapsMu.Unlock()
+	if existing, ok := predefinedCMaps[name]; ok {
+		return existing
+	}
+	predefinedCMaps[name] = cmap
+	return cmap
+}
+
+// orderingCMap returns the CID-to-Unicode table for a character
+// collection, named <Registry>-<Ordering>-UCS2 as in Adobe's resources: a
+// registered one, or else one built from the embedded Unicode CMaps
+func orderingCMap(info *CIDSystemInfo) *CMap {
+	if info == nil || info.Registry == "" || info.Ordering == "" || info.Ordering == "Identity" {
+		return nil
+	}
+	collection := info.Registry + "-" + info.Ordering
+	name := collection + "-UCS2"
+
+	registeredCMapsMu.RLock()
+	cmap, ok := registeredCMaps[name]
+	registeredCMapsMu.RUnlock()
+	if ok {
+		return cmap
+	}
+
+	if cmap := cachedCMap(name); cmap != nil {
+		return cmap
+	}
+	sources, ok := orderingSources[collection]
+	if !ok {
+		return nil
+	}
+	cmap = buildOrderingCMap(name, sources)
+	if cmap == nil {
+		return nil
+	}
+	return cacheCMap(name, cmap)
+}
+
+// buildOrderingCMap maps CIDs to Unicode by inverting Unicode CMaps. Where
+// several characters share a CID, ordinary characters win over
+// compatibility and private-use ones, then the lowest code point. CIDs of
+// later sources are only added when earlier ones don't map them, so
+// vertical forms don't displace the horizontal characters they share a
+// glyph with.
+func buildOrderingCMap(name string, sources []string) *CMap {
+	best := make(map[uint32]rune)
+	for _, source := range sources {
+		cmap, ok := PredefinedCMap(source)
+		if !ok {
+			continue
+		}
+
+		found := make(map[uint32]rune)
+		add := func(code, cid uint32) {
+			r := rune(code)
+			if !utf8.ValidRune(r) {
+				return
+			}
+			if _, ok := best[cid]; ok {
+				return
+			}
+			if prev, ok := found[cid]; !ok || preferRune(r, prev) {
+				found[cid] = r
+			}
+		}
+		for code, cid := range cmap.cidChars {
+			add(code, cid)
+		}
+		for _, rng := range cmap.cidRanges {
+			for code := rng.startCode; code <= rng.endCode && code <= unicode.MaxRune; code++ {
+				add(code, rng.startCID+code-rng.startCode)
+			}
+		}
+		for cid, r := range found {
+			best[cid] = r
+		}
+	}
+	if len(best) == 0 {
+		return nil
+	}
+
+	cmap := NewCMap()
+	cmap.name = name
+	for cid, r := range best {
+		cmap.charMappings[cid] = string(r)
+	}
+	return cmap
+}
+
+// preferRune reports whether r is a better Unicode value for a CID than
+// prev
+func preferRune(r, prev rune) bool {
+	if a, b := runeRank(r), runeRank(prev); a != b {
+		return a < b
+	}
+	return r < prev
+}
+
+// runeRank ranks Unicode values for a CID: ordinary characters, then
+// radicals, compatibility ideographs and presentation forms, then private
+// use
+func runeRank(r rune) int {
+	switch {
+	case unicode.Is(unicode.Co, r):
+		return 2
+	case r >= 0x2E80 && r <= 0x2FDF, r >= 0xF900 && r <= 0xFAFF, r >= 0x2F800 && r <= 0x2FA1F,
+		r >= 0xFE10 && r <= 0xFE1F, r >= 0xFE30 && r <= 0xFE4F:
+		return 1
+	default:
+		return 0
+	}
+}
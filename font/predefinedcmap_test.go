@@ -0,0 +1,199 @@
+package font
+
+import (
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// predefinedFont returns a non-embedded composite font using a predefined
+// CMap, with a descendant font in the Adobe collection for ordering
+func predefinedFont(t *testing.T, encoding, ordering string, w core.Array) *Type0Font {
+	t.Helper()
+	descendant := core.Dict{
+		"Type":     core.Name("Font"),
+		"Subtype":  core.Name("CIDFontType0"),
+		"BaseFont": core.Name("Test-CJK"),
+		"CIDSystemInfo": core.Dict{
+			"Registry":   core.String("Adobe"),
+			"Ordering":   core.String(ordering),
+			"Supplement": core.Int(0),
+		},
+	}
+	if w != nil {
+		descendant["W"] = w
+	}
+	font, err := NewType0Font(core.Dict{
+		"Type":            core.Name("Font"),
+		"Subtype":         core.Name("Type0"),
+		"BaseFont":        core.Name("Test-CJK"),
+		"Encoding":        core.Name(encoding),
+		"DescendantFonts": core.Array{descendant},
+	}, mockResolver)
+	if err != nil {
+		t.Fatalf("NewType0Font failed: %v", err)
+	}
+	return font
+}
+
+func TestPredefinedCMap_Decode(t *testing.T) {
+	tests := []struct {
+		name     string
+		ordering string
+		data     []byte
+		expected string
+	}{
+		{"90ms-RKSJ-H", "Japan1", []byte{0x93, 0xfa, 0x96, 0x7b, 0x8c, 0xea, 0x41, 0xb1}, "日本語Aｱ"},
+		{"90msp-RKSJ-V", "Japan1", []byte{0x93, 0xfa, 0x96, 0x7b}, "日本"},
+		{"EUC-H", "Japan1", []byte{0xc6, 0xfc, 0xcb, 0xdc}, "日本"},
+		{"H", "Japan1", []byte{0x46, 0x7c, 0x4b, 0x5c}, "日本"},
+		{"GBK-EUC-H", "GB1", []byte{0xd6, 0xd0, 0xce, 0xc4}, "中文"},
+		{"GB-EUC-H", "GB1", []byte{0xd6, 0xd0, 0xce, 0xc4}, "中文"},
+		{"KSCms-UHC-H", "Korea1", []byte{0xc7, 0xd1, 0xb1, 0xb9, 0xbe, 0xee}, "한국어"},
+		{"ETen-B5-H", "CNS1", []byte{0xa4, 0xa4, 0xa4, 0xe5}, "中文"},
+		{"CNS-EUC-H", "CNS1", []byte{0xc4, 0xe3, 0xc5, 0xc6}, "中文"},
+		{"UniJIS-UCS2-H", "Japan1", []byte{0x65, 0xe5, 0x00, 0x41}, "日A"},
+		{"UniJIS-UCS2-HW-V", "Japan1", []byte{0x65, 0xe5}, "日"},
+		{"UniJIS-UTF16-H", "Japan1", []byte{0xd8, 0x40, 0xdc, 0x0b, 0x4e, 0x2d}, "𠀋中"},
+		{"UniKS-UTF8-H", "Korea1", []byte("한"), "한"},
+		{"UniCNS-UTF32-H", "CNS1", []byte{0x00, 0x00, 0x4e, 0x2d}, "中"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			font := predefinedFont(t, tt.name, tt.ordering, nil)
+			if font.CMap == nil {
+				t.Fatalf("Expected predefined CMap %s", tt.name)
+			}
+			if got := font.DecodeString(tt.data); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPredefinedCMap_CIDs(t *testing.T) {
+	cmap, ok := PredefinedCMap("90ms-RKSJ-H")
+	if !ok {
+		t.Fatal("Expected 90ms-RKSJ-H")
+	}
+	// Shift-JIS 日 is Adobe-Japan1 CID 3284; half-width A is 264
+	for _, tt := range []struct {
+		data []byte
+		cid  uint32
+	}{
+		{[]byte{0x93, 0xfa}, 3284},
+		{[]byte{0x41}, 264},
+	} {
+		code, n := cmap.nextCode(tt.data)
+		if n != len(tt.data) {
+			t.Errorf("Expected a %d-byte code, got %d bytes", len(tt.data), n)
+		}
+		if cid, ok := cmap.LookupCID(code); !ok || cid != tt.cid {
+			t.Errorf("Expected CID %d for %x, got %d", tt.cid, tt.data, cid)
+		}
+	}
+}
+
+func TestPredefinedCMap_Widths(t *testing.T) {
+	// Half-width A (CID 264) is 500 wide; 日 takes the default width
+	font := predefinedFont(t, "90ms-RKSJ-H", "Japan1", core.Array{core.Int(264), core.Array{core.Int(500)}})
+	width, ok := font.GetCodesWidth([]byte{0x41, 0x93, 0xfa})
+	if !ok {
+		t.Fatal("Expected widths by code")
+	}
+	if width != 1500 {
+		t.Errorf("Expected width 1500, got %v", width)
+	}
+}
+
+func TestPredefinedCMap_WritingMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		vertical bool
+	}{
+		{"90ms-RKSJ-H", false},
+		{"90ms-RKSJ-V", true},
+		{"H", false},
+		{"V", true},
+		{"Identity-V", true},
+	}
+
+	for _, tt := range tests {
+		cmap, ok := PredefinedCMap(tt.name)
+		if !ok {
+			t.Fatalf("Expected predefined CMap %s", tt.name)
+		}
+		if cmap.IsVertical() != tt.vertical {
+			t.Errorf("%s: expected vertical %v, got %v", tt.name, tt.vertical, cmap.IsVertical())
+		}
+	}
+}
+
+func TestPredefinedCMap_Identity(t *testing.T) {
+	cmap, ok := PredefinedCMap("Identity-H")
+	if !ok {
+		t.Fatal("Expected Identity-H")
+	}
+	if cid, ok := cmap.LookupCID(0x1234); !ok || cid != 0x1234 {
+		t.Errorf("Expected CID 0x1234, got %x", cid)
+	}
+}
+
+func TestPredefinedCMap_Unknown(t *testing.T) {
+	for _, name := range []string{"Foo-H", "UniFoo-UCS2-H", "WinAnsiEncoding", "LICENSE.md", "../cmaps/H", ""} {
+		if _, ok := PredefinedCMap(name); ok {
+			t.Errorf("Expected %s to be unknown", name)
+		}
+	}
+}
+
+func TestOrderingCMap(t *testing.T) {
+	// An Identity-H Adobe-Japan1 font without a ToUnicode CMap or embedded
+	// program: CID 34 is A, 842 is ぁ
+	font := predefinedFont(t, "Identity-H", "Japan1", nil)
+	if got := font.DecodeString([]byte{0x00, 0x22, 0x03, 0x4a}); got != "Aぁ" {
+		t.Errorf("Expected %q, got %q", "Aぁ", got)
+	}
+
+	for _, tt := range []struct {
+		registry, ordering string
+		cid                uint32
+		expected           string
+	}{
+		{"Adobe", "GB1", 4559, "中"},
+		{"Adobe", "CNS1", 661, "中"},
+		{"Adobe", "Korea1", 1, " "},
+	} {
+		cmap := orderingCMap(&CIDSystemInfo{Registry: tt.registry, Ordering: tt.ordering})
+		if cmap == nil {
+			t.Fatalf("Expected an ordering table for %s-%s", tt.registry, tt.ordering)
+		}
+		if got := cmap.Lookup(tt.cid); got != tt.expected {
+			t.Errorf("%s-%s: expected CID %d to be %q, got %q", tt.registry, tt.ordering, tt.cid, tt.expected, got)
+		}
+	}
+
+	if orderingCMap(&CIDSystemInfo{Registry: "Adobe", Ordering: "Identity"}) != nil {
+		t.Error("Expected no ordering table for Adobe-Identity")
+	}
+}
+
+func TestRegisterCMap(t *testing.T) {
+	if err := RegisterCMap("", []byte("1 begincidrange\n<00> <ff> 1\nendcidrange")); err == nil {
+		t.Error("Expected error for a CMap without a name")
+	}
+
+	data := []byte("/CMapName /Test-Registered-H def\n1 begincidrange\n<00> <ff> 1\nendcidrange")
+	if err := RegisterCMap("", data); err != nil {
+		t.Fatalf("RegisterCMap failed: %v", err)
+	}
+
+	cmap, ok := PredefinedCMap("Test-Registered-H")
+	if !ok {
+		t.Fatal("Expected registered CMap to be found")
+	}
+	if cid, _ := cmap.LookupCID(0x41); cid != 0x42 {
+		t.Errorf("Expected CID 0x42, got %x", cid)
+	}
+}
@@ -0,0 +1,319 @@
+package font
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"unicode"
+)
+
+// Platform/encoding pairs of the cmap subtables used for Unicode recovery
+const (
+	cmapMacRoman      = 1<<16 | 0  // (1,0) Macintosh Roman
+	cmapWindowsSymbol = 3<<16 | 0  // (3,0) Windows Symbol
+	cmapWindowsBMP    = 3<<16 | 1  // (3,1) Windows Unicode BMP
+	cmapWindowsFull   = 3<<16 | 10 // (3,10) Windows Unicode full repertoire
+	cmapUnicodeBMP    = 0<<16 | 3  // (0,3) Unicode 2.0 BMP
+	cmapUnicodeFull   = 0<<16 | 4  // (0,4) Unicode 2.0 full repertoire
+)
+
+// parseSfntTables reads the table directory of a TrueType or OpenType font
+// program and returns each table's bytes by tag
+func parseSfntTables(data []byte) (map[string][]byte, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("font program too short")
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if 12+numTables*16 > len(data) {
+		return nil, fmt.Errorf("table directory truncated")
+	}
+
+	tables := make(map[string][]byte, numTables)
+	for i := 0; i < numTables; i++ {
+		entry := data[12+i*16:]
+		tag := string(entry[0:4])
+		offset := int(binary.BigEndian.Uint32(entry[8:]))
+		length := int(binary.BigEndian.Uint32(entry[12:]))
+
+		// Extract table data
+		if offset >= 0 && length >= 0 && offset+length <= len(data) {
+			tables[tag] = data[offset : offset+length]
+		}
+	}
+
+	return tables, nil
+}
+
+// parseCmapSubtables parses every subtable of a 'cmap' table that uses a
+// supported format (0, 4, 6 or 12). Subtables are keyed by
+// platformID<<16 | encodingID.
+func parseCmapSubtables(data []byte) (map[uint32]map[rune]uint16, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("cmap table too short")
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[2:]))
+	if 4+numTables*8 > len(data) {
+		return nil, fmt.Errorf("cmap encoding records truncated")
+	}
+
+	subtables := make(map[uint32]map[rune]uint16)
+	for i := 0; i < numTables; i++ {
+		record := data[4+i*8:]
+		platformID := uint32(binary.BigEndian.Uint16(record[0:]))
+		encodingID := uint32(binary.BigEndian.Uint16(record[2:]))
+		offset := int(binary.BigEndian.Uint32(record[4:]))
+
+		mapping, err := parseCmapSubtable(data, offset)
+		if err != nil {
+			continue // Skip unsupported or malformed subtables
+		}
+		subtables[platformID<<16|encodingID] = mapping
+	}
+
+	if len(subtables) == 0 {
+		return nil, fmt.Errorf("no supported cmap subtable found")
+	}
+	return subtables, nil
+}
+
+// parseCmapSubtable parses the cmap subtable at offset, returning a mapping
+// from character code to glyph ID
+func parseCmapSubtable(data []byte, offset int) (map[rune]uint16, error) {
+	if offset < 0 || offset+2 > len(data) {
+		return nil, fmt.Errorf("cmap subtable offset out of range")
+	}
+
+	sub := data[offset:]
+	format := binary.BigEndian.Uint16(sub)
+	switch format {
+	case 0:
+		return parseCmapFormat0(sub)
+	case 4:
+		return parseCmapFormat4(sub)
+	case 6:
+		return parseCmapFormat6(sub)
+	case 12:
+		return parseCmapFormat12(sub)
+	default:
+		return nil, fmt.Errorf("cmap format %d not supported", format)
+	}
+}
+
+// maxCmapCodes caps the code points a cmap subtable may map. A font has at
+// most 65535 glyphs and real cmaps map well under this many codes; a
+// hostile font with huge or overlapping ranges is cut off here instead of
+// looping billions of times.
+const maxCmapCodes = 1 << 18
+
+// errCmapTooLarge is returned for a cmap subtable mapping more than
+// maxCmapCodes code points
+var errCmapTooLarge = fmt.Errorf("cmap maps more than %d code points", maxCmapCodes)
+
+// parseCmapFormat0 parses a byte encoding table: 256 single-byte glyph IDs
+func parseCmapFormat0(sub []byte) (map[rune]uint16, error) {
+	if len(sub) < 6+256 {
+		return nil, fmt.Errorf("cmap format 0 truncated")
+	}
+
+	mapping := make(map[rune]uint16)
+	for code, gid := range sub[6 : 6+256] {
+		if gid != 0 {
+			mapping[rune(code)] = uint16(gid)
+		}
+	}
+	return mapping, nil
+}
+
+// parseCmapFormat4 parses a segment mapping to delta values table, the
+// common format for the Unicode BMP
+func parseCmapFormat4(sub []byte) (map[rune]uint16, error) {
+	if len(sub) < 14 {
+		return nil, fmt.Errorf("cmap format 4 truncated")
+	}
+
+	segCount := int(binary.BigEndian.Uint16(sub[6:])) / 2
+	endCodes := 14
+	startCodes := endCodes + segCount*2 + 2 // skip reservedPad
+	idDeltas := startCodes + segCount*2
+	idRangeOffsets := idDeltas + segCount*2
+	if idRangeOffsets+segCount*2 > len(sub) {
+		return nil, fmt.Errorf("cmap format 4 segments truncated")
+	}
+
+	mapping := make(map[rune]uint16)
+	codes := 0
+	for i := 0; i < segCount; i++ {
+		end := uint32(binary.BigEndian.Uint16(sub[endCodes+i*2:]))
+		start := uint32(binary.BigEndian.Uint16(sub[startCodes+i*2:]))
+		delta := binary.BigEndian.Uint16(sub[idDeltas+i*2:])
+		rangeOffsetPos := idRangeOffsets + i*2
+		rangeOffset := int(binary.BigEndian.Uint16(sub[rangeOffsetPos:]))
+
+		if end >= start {
+			if codes += int(end-start) + 1; codes > maxCmapCodes {
+				return nil, errCmapTooLarge
+			}
+		}
+
+		// Use uint32 for the loop counter to avoid infinite loop when endCode is 0xFFFF
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			var gid uint16
+			if rangeOffset == 0 {
+				gid = uint16(c) + delta
+			} else {
+				// idRangeOffset is relative to its own position in the table
+				pos := rangeOffsetPos + rangeOffset + int(c-start)*2
+				if pos+2 > len(sub) {
+					break
+				}
+				gid = binary.BigEndian.Uint16(sub[pos:])
+				if gid != 0 {
+					gid += delta
+				}
+			}
+			if gid != 0 {
+				mapping[rune(c)] = gid
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// parseCmapFormat6 parses a trimmed table mapping: a dense range of codes
+func parseCmapFormat6(sub []byte) (map[rune]uint16, error) {
+	if len(sub) < 10 {
+		return nil, fmt.Errorf("cmap format 6 truncated")
+	}
+
+	firstCode := rune(binary.BigEndian.Uint16(sub[6:]))
+	entryCount := int(binary.BigEndian.Uint16(sub[8:]))
+	if 10+entryCount*2 > len(sub) {
+		return nil, fmt.Errorf("cmap format 6 glyph array truncated")
+	}
+
+	mapping := make(map[rune]uint16, entryCount)
+	for i := 0; i < entryCount; i++ {
+		if gid := binary.BigEndian.Uint16(sub[10+i*2:]); gid != 0 {
+			mapping[firstCode+rune(i)] = gid
+		}
+	}
+	return mapping, nil
+}
+
+// parseCmapFormat12 parses a segmented coverage table, used for code points
+// beyond the BMP
+func parseCmapFormat12(sub []byte) (map[rune]uint16, error) {
+	if len(sub) < 16 {
+		return nil, fmt.Errorf("cmap format 12 truncated")
+	}
+
+	numGroups := int(binary.BigEndian.Uint32(sub[12:]))
+	if numGroups < 0 || 16+numGroups*12 > len(sub) {
+		return nil, fmt.Errorf("cmap format 12 groups truncated")
+	}
+
+	mapping := make(map[rune]uint16)
+	codes := 0
+	for i := 0; i < numGroups; i++ {
+		group := sub[16+i*12:]
+		start := binary.BigEndian.Uint32(group[0:])
+		end := binary.BigEndian.Uint32(group[4:])
+		startGID := binary.BigEndian.Uint32(group[8:])
+		if end < start || start > unicode.MaxRune {
+			continue // Malformed group
+		}
+		if end > unicode.MaxRune {
+			end = unicode.MaxRune
+		}
+		if codes += int(end-start) + 1; codes > maxCmapCodes {
+			return nil, errCmapTooLarge
+		}
+		for c := start; c <= end; c++ {
+			mapping[rune(c)] = uint16(startGID + (c - start))
+		}
+	}
+	return mapping, nil
+}
+
+// invertCmap builds a glyph ID to Unicode mapping from a Unicode cmap
+// subtable. When several code points share a glyph the lowest one wins, so
+// the result is deterministic.
+func invertCmap(mapping map[rune]uint16) map[uint16]rune {
+	codes := make([]rune, 0, len(mapping))
+	for r := range mapping {
+		codes = append(codes, r)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	inverse := make(map[uint16]rune, len(codes))
+	for _, r := range codes {
+		gid := mapping[r]
+		if _, exists := inverse[gid]; !exists {
+			inverse[gid] = r
+		}
+	}
+	return inverse
+}
+
+// unicodeCmap returns the preferred Unicode subtable, or nil if the font has
+// none
+func unicodeCmap(subtables map[uint32]map[rune]uint16) map[rune]uint16 {
+	for _, key := range []uint32{cmapWindowsFull, cmapWindowsBMP, cmapUnicodeFull, cmapUnicodeBMP} {
+		if mapping, ok := subtables[key]; ok {
+			return mapping
+		}
+	}
+	return nil
+}
+
+// parsePostGlyphNames returns the glyph names recorded in a 'post' table,
+// indexed by glyph ID. Formats 1 and 2 carry names; format 3 does not.
+func parsePostGlyphNames(data []byte) ([]string, error) {
+	if len(data) < 32 {
+		return nil, fmt.Errorf("post table too short")
+	}
+
+	version := binary.BigEndian.Uint32(data)
+	switch version {
+	case 0x00010000:
+		return macGlyphNames[:], nil
+	case 0x00020000:
+		// handled below
+	default:
+		return nil, fmt.Errorf("post table version %#x has no glyph names", version)
+	}
+
+	if len(data) < 34 {
+		return nil, fmt.Errorf("post table format 2 truncated")
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(data[32:]))
+	indexEnd := 34 + numGlyphs*2
+	if indexEnd > len(data) {
+		return nil, fmt.Errorf("post glyph name index truncated")
+	}
+
+	// Pascal strings for the names that are not in the Macintosh set
+	var custom []string
+	for pos := indexEnd; pos < len(data); {
+		n := int(data[pos])
+		if pos+1+n > len(data) {
+			break
+		}
+		custom = append(custom, string(data[pos+1:pos+1+n]))
+		pos += 1 + n
+	}
+
+	names := make([]string, numGlyphs)
+	for gid := range names {
+		idx := int(binary.BigEndian.Uint16(data[34+gid*2:]))
+		switch {
+		case idx < len(macGlyphNames):
+			names[gid] = macGlyphNames[idx]
+		case idx-len(macGlyphNames) < len(custom):
+			names[gid] = custom[idx-len(macGlyphNames)]
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,268 @@
+package font
+
+import (
+	"encoding/binary"
+	"sort"
+	"testing"
+)
+
+// buildSfnt assembles a TrueType font program from raw tables
+func buildSfnt(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	data := make([]byte, 12+16*len(tags))
+	binary.BigEndian.PutUint32(data[0:], 0x00010000)
+	binary.BigEndian.PutUint16(data[4:], uint16(len(tags)))
+
+	for i, tag := range tags {
+		offset := len(data)
+		data = append(data, tables[tag]...)
+
+		entry := data[12+i*16:]
+		copy(entry[0:4], tag)
+		binary.BigEndian.PutUint32(entry[8:], uint32(offset))
+		binary.BigEndian.PutUint32(entry[12:], uint32(len(tables[tag])))
+	}
+	return data
+}
+
+// buildCmap assembles a cmap table with one format 4 subtable per
+// platform/encoding key. Each code becomes its own delta segment.
+func buildCmap(subtables map[uint32]map[rune]uint16) []byte {
+	keys := make([]uint32, 0, len(subtables))
+	for key := range subtables {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	data := make([]byte, 4+8*len(keys))
+	binary.BigEndian.PutUint16(data[2:], uint16(len(keys)))
+	for i, key := range keys {
+		record := data[4+i*8:]
+		binary.BigEndian.PutUint16(record[0:], uint16(key>>16))
+		binary.BigEndian.PutUint16(record[2:], uint16(key))
+		binary.BigEndian.PutUint32(record[4:], uint32(len(data)))
+		data = append(data, buildCmapFormat4(subtables[key])...)
+	}
+	return data
+}
+
+// buildCmapFormat4 builds a format 4 subtable with one segment per code
+func buildCmapFormat4(mapping map[rune]uint16) []byte {
+	codes := make([]rune, 0, len(mapping))
+	for r := range mapping {
+		codes = append(codes, r)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	segCount := len(codes) + 1
+	sub := make([]byte, 16+segCount*8)
+	binary.BigEndian.PutUint16(sub[0:], 4)
+	binary.BigEndian.PutUint16(sub[2:], uint16(len(sub)))
+	binary.BigEndian.PutUint16(sub[6:], uint16(segCount*2))
+
+	ends := sub[14:]
+	starts := sub[16+segCount*2:]
+	deltas := sub[16+segCount*4:]
+	for i, c := range codes {
+		binary.BigEndian.PutUint16(ends[i*2:], uint16(c))
+		binary.BigEndian.PutUint16(starts[i*2:], uint16(c))
+		binary.BigEndian.PutUint16(deltas[i*2:], mapping[c]-uint16(c))
+	}
+	last := segCount - 1
+	binary.BigEndian.PutUint16(ends[last*2:], 0xFFFF)
+	binary.BigEndian.PutUint16(starts[last*2:], 0xFFFF)
+	binary.BigEndian.PutUint16(deltas[last*2:], 1)
+	return sub
+}
+
+// buildPost assembles a format 2 'post' table from glyph names
+func buildPost(names []string) []byte {
+	data := make([]byte, 34+2*len(names))
+	binary.BigEndian.PutUint32(data[0:], 0x00020000)
+	binary.BigEndian.PutUint16(data[32:], uint16(len(names)))
+
+	macIndex := make(map[string]int, len(macGlyphNames))
+	for i, name := range macGlyphNames {
+		macIndex[name] = i
+	}
+
+	custom := 0
+	for gid, name := range names {
+		idx, ok := macIndex[name]
+		if !ok {
+			idx = len(macGlyphNames) + custom
+			custom++
+			data = append(data, byte(len(name)))
+			data = append(data, name...)
+		}
+		binary.BigEndian.PutUint16(data[34+gid*2:], uint16(idx))
+	}
+	return data
+}
+
+func TestParseCmapFormat4(t *testing.T) {
+	sub := buildCmapFormat4(map[rune]uint16{'A': 3, 'B': 4, 0x20AC: 9})
+
+	mapping, err := parseCmapFormat4(sub)
+	if err != nil {
+		t.Fatalf("parseCmapFormat4 failed: %v", err)
+	}
+	if len(mapping) != 3 {
+		t.Errorf("Expected 3 mappings, got %d", len(mapping))
+	}
+	if mapping['B'] != 4 || mapping[0x20AC] != 9 {
+		t.Errorf("Unexpected mapping: %v", mapping)
+	}
+}
+
+func TestParseCmapFormat4_RangeOffset(t *testing.T) {
+	// Two segments: 'a'-'c' through glyphIdArray, then the 0xFFFF terminator
+	sub := make([]byte, 16+2*8+3*2)
+	binary.BigEndian.PutUint16(sub[0:], 4)
+	binary.BigEndian.PutUint16(sub[6:], 4)       // segCountX2
+	binary.BigEndian.PutUint16(sub[14:], 'c')    // endCode[0]
+	binary.BigEndian.PutUint16(sub[16:], 0xFFFF) // endCode[1]
+	binary.BigEndian.PutUint16(sub[20:], 'a')    // startCode[0]
+	binary.BigEndian.PutUint16(sub[22:], 0xFFFF) // startCode[1]
+	binary.BigEndian.PutUint16(sub[24:], 0)      // idDelta[0]
+	binary.BigEndian.PutUint16(sub[26:], 1)      // idDelta[1]
+	binary.BigEndian.PutUint16(sub[28:], 4)      // idRangeOffset[0]: skip 2 entries to the glyph array
+	binary.BigEndian.PutUint16(sub[30:], 0)      // idRangeOffset[1]
+	for i, gid := range []uint16{7, 0, 5} {      // glyphIdArray
+		binary.BigEndian.PutUint16(sub[32+i*2:], gid)
+	}
+
+	mapping, err := parseCmapFormat4(sub)
+	if err != nil {
+		t.Fatalf("parseCmapFormat4 failed: %v", err)
+	}
+	if mapping['a'] != 7 || mapping['c'] != 5 {
+		t.Errorf("Unexpected mapping: %v", mapping)
+	}
+	if _, ok := mapping['b']; ok {
+		t.Error("Expected 'b' to be unmapped")
+	}
+}
+
+func TestParseCmapFormat12(t *testing.T) {
+	sub := make([]byte, 16+12)
+	binary.BigEndian.PutUint16(sub[0:], 12)
+	binary.BigEndian.PutUint32(sub[12:], 1)
+	binary.BigEndian.PutUint32(sub[16:], 0x1F600)
+	binary.BigEndian.PutUint32(sub[20:], 0x1F602)
+	binary.BigEndian.PutUint32(sub[24:], 40)
+
+	mapping, err := parseCmapSubtable(sub, 0)
+	if err != nil {
+		t.Fatalf("parseCmapSubtable failed: %v", err)
+	}
+	if mapping[0x1F601] != 41 {
+		t.Errorf("Expected U+1F601 -> 41, got %d", mapping[0x1F601])
+	}
+}
+
+func TestParseCmapFormat12_HostileRanges(t *testing.T) {
+	// A group spanning 0..0x7FFFFFFF is clamped to Unicode, and too many
+	// code points in all fail rather than filling a map
+	group := func(start, end uint32) []byte {
+		sub := make([]byte, 16+12)
+		binary.BigEndian.PutUint16(sub[0:], 12)
+		binary.BigEndian.PutUint32(sub[12:], 1)
+		binary.BigEndian.PutUint32(sub[16:], start)
+		binary.BigEndian.PutUint32(sub[20:], end)
+		binary.BigEndian.PutUint32(sub[24:], 1)
+		return sub
+	}
+
+	if _, err := parseCmapSubtable(group(0, 0x7FFFFFFF), 0); err == nil {
+		t.Error("Expected an error for a group covering all of Unicode")
+	}
+
+	mapping, err := parseCmapSubtable(group(0x10FFF0, 0x7FFFFFFF), 0)
+	if err != nil {
+		t.Fatalf("parseCmapSubtable failed: %v", err)
+	}
+	if len(mapping) != 16 {
+		t.Errorf("Expected the group clamped to 16 code points, got %d", len(mapping))
+	}
+}
+
+func TestParseCmapFormat4_OverlappingSegments(t *testing.T) {
+	// 32767 overlapping segments each covering the whole BMP
+	segCount := 0x7FFF
+	sub := make([]byte, 16+segCount*8)
+	binary.BigEndian.PutUint16(sub[0:], 4)
+	binary.BigEndian.PutUint16(sub[6:], uint16(segCount*2))
+	for i := 0; i < segCount; i++ {
+		binary.BigEndian.PutUint16(sub[14+i*2:], 0xFFFE)       // endCode
+		binary.BigEndian.PutUint16(sub[16+segCount*2+i*2:], 0) // startCode
+		binary.BigEndian.PutUint16(sub[16+segCount*4+i*2:], 1) // idDelta
+		binary.BigEndian.PutUint16(sub[16+segCount*6+i*2:], 0) // idRangeOffset
+	}
+
+	if _, err := parseCmapFormat4(sub); err == nil {
+		t.Error("Expected an error for overlapping segments mapping too many codes")
+	}
+}
+
+func TestParseCmapSubtables(t *testing.T) {
+	cmap := buildCmap(map[uint32]map[rune]uint16{
+		cmapWindowsBMP:    {'A': 1},
+		cmapWindowsSymbol: {0xF041: 1},
+	})
+
+	subtables, err := parseCmapSubtables(cmap)
+	if err != nil {
+		t.Fatalf("parseCmapSubtables failed: %v", err)
+	}
+	if len(subtables) != 2 {
+		t.Fatalf("Expected 2 subtables, got %d", len(subtables))
+	}
+	if subtables[cmapWindowsSymbol][0xF041] != 1 {
+		t.Errorf("Expected symbol subtable to map U+F041 to glyph 1")
+	}
+	if unicodeCmap(subtables)['A'] != 1 {
+		t.Errorf("Expected Unicode subtable to be preferred")
+	}
+}
+
+func TestInvertCmap(t *testing.T) {
+	inverse := invertCmap(map[rune]uint16{'B': 2, 'A': 2, 'C': 3})
+	if inverse[2] != 'A' {
+		t.Errorf("Expected lowest code point for shared glyph, got %q", inverse[2])
+	}
+	if inverse[3] != 'C' {
+		t.Errorf("Expected glyph 3 -> 'C', got %q", inverse[3])
+	}
+}
+
+func TestParsePostGlyphNames(t *testing.T) {
+	names, err := parsePostGlyphNames(buildPost([]string{".notdef", "H", "uni0416", "f_i"}))
+	if err != nil {
+		t.Fatalf("parsePostGlyphNames failed: %v", err)
+	}
+
+	expected := []string{".notdef", "H", "uni0416", "f_i"}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected %d names, got %d", len(expected), len(names))
+	}
+	for i, name := range expected {
+		if names[i] != name {
+			t.Errorf("Expected glyph %d named %q, got %q", i, name, names[i])
+		}
+	}
+}
+
+func TestParsePostGlyphNames_Format3(t *testing.T) {
+	data := make([]byte, 32)
+	binary.BigEndian.PutUint32(data, 0x00030000)
+
+	if _, err := parsePostGlyphNames(data); err == nil {
+		t.Error("Expected error for post format 3")
+	}
+}
@@ -0,0 +1,139 @@
+package font
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Font descriptor flags (PDF 32000-1:2008, Table 123)
+const (
+	FlagFixedPitch  = 1 << 0
+	FlagSerif       = 1 << 1
+	FlagSymbolic    = 1 << 2
+	FlagScript      = 1 << 3
+	FlagNonsymbolic = 1 << 5
+	FlagItalic      = 1 << 6
+	FlagAllCap      = 1 << 16
+	FlagSmallCap    = 1 << 17
+	FlagForceBold   = 1 << 18
+)
+
+// boldWeight is the /FontWeight from which a font counts as bold
+const boldWeight = 600
+
+// Name fragments that mark a style when the font descriptor does not.
+// Monospace families are matched against whole words of the name, so
+// "mono" matches DejaVuSansMono but not MonotypeCorsiva.
+var (
+	boldNames      = []string{"bold", "black", "heavy", "semibold", "demi"}
+	italicNames    = []string{"italic", "oblique", "slanted"}
+	monospaceNames = []string{"courier", "mono", "consolas", "menlo", "typewriter", "fixed"}
+)
+
+// IsBold reports whether the font is bold: its descriptor sets the
+// ForceBold flag or a /FontWeight of 600 or more, or its name says so
+func (f *Font) IsBold() bool {
+	if fd := f.descriptor; fd != nil && (fd.Flags&FlagForceBold != 0 || fd.FontWeight >= boldWeight) {
+		return true
+	}
+	return IsBoldName(f.BaseFont)
+}
+
+// IsItalic reports whether the font is italic or oblique: its descriptor
+// sets the Italic flag or a non-zero /ItalicAngle, or its name says so
+func (f *Font) IsItalic() bool {
+	if fd := f.descriptor; fd != nil && (fd.Flags&FlagItalic != 0 || fd.ItalicAngle != 0) {
+		return true
+	}
+	return IsItalicName(f.BaseFont)
+}
+
+// IsMonospace reports whether all of the font's glyphs have the same
+// width: its descriptor sets the FixedPitch flag, or its name is that of a
+// common monospaced family
+func (f *Font) IsMonospace() bool {
+	if fd := f.descriptor; fd != nil && fd.Flags&FlagFixedPitch != 0 {
+		return true
+	}
+	return IsMonospaceName(f.BaseFont)
+}
+
+// IsBoldName reports whether a font name marks a bold weight, e.g.
+// Helvetica-Bold or ABCDEF+Arial-Black
+func IsBoldName(name string) bool {
+	return nameContains(name, boldNames)
+}
+
+// IsItalicName reports whether a font name marks an italic or oblique
+// style, e.g. Times-Italic
+func IsItalicName(name string) bool {
+	return nameContains(name, italicNames)
+}
+
+// IsMonospaceName reports whether a font name is that of a common
+// monospaced family, e.g. Courier-Bold or DejaVuSansMono
+func IsMonospaceName(name string) bool {
+	for _, word := range nameWords(name) {
+		for _, s := range monospaceNames {
+			if word == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nameContains reports whether a font name, without any subset tag,
+// contains one of the given lowercase fragments
+func nameContains(name string, fragments []string) bool {
+	name = strings.ToLower(stripSubsetTag(name))
+	for _, s := range fragments {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// nameWords splits a font name, without any subset tag, into lowercase
+// words at punctuation, digits and case changes: SFMono-Regular gives sf,
+// mono and regular
+func nameWords(name string) []string {
+	runes := []rune(stripSubsetTag(name))
+	var words []string
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) {
+			if start >= 0 {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			// A new word starts at an upper-case letter after a lower-case
+			// one, or at the last capital of an acronym (the M of SFMono)
+			if unicode.IsLower(prev) || (i+1 < len(runes) && unicode.IsUpper(prev) && unicode.IsLower(runes[i+1])) {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, strings.ToLower(string(runes[start:])))
+	}
+	return words
+}
+
+// stripSubsetTag removes the subset tag from a font name, e.g.
+// ABCDEF+Helvetica
+func stripSubsetTag(name string) string {
+	if i := strings.IndexByte(name, '+'); i == 6 {
+		return name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,86 @@
+package font
+
+import (
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+func TestFontStyleFromDescriptor(t *testing.T) {
+	tests := []struct {
+		name                    string
+		descriptor              core.Dict
+		bold, italic, monospace bool
+	}{
+		{"plain", core.Dict{"Flags": core.Int(FlagNonsymbolic)}, false, false, false},
+		{"force bold", core.Dict{"Flags": core.Int(FlagForceBold)}, true, false, false},
+		{"font weight", core.Dict{"FontWeight": core.Int(700)}, true, false, false},
+		{"italic flag", core.Dict{"Flags": core.Int(FlagItalic)}, false, true, false},
+		{"italic angle", core.Dict{"ItalicAngle": core.Real(-12)}, false, true, false},
+		{"fixed pitch", core.Dict{"Flags": core.Int(FlagFixedPitch | FlagSerif)}, false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fontDict := core.Dict{
+				"Subtype":        core.Name("TrueType"),
+				"BaseFont":       core.Name("ABCDEF+Custom"),
+				"FontDescriptor": tt.descriptor,
+			}
+			f, err := NewTrueTypeFont(fontDict, mockResolver)
+			if err != nil {
+				t.Fatalf("NewTrueTypeFont failed: %v", err)
+			}
+			if f.IsBold() != tt.bold || f.IsItalic() != tt.italic || f.IsMonospace() != tt.monospace {
+				t.Errorf("Expected bold=%v italic=%v monospace=%v, got %v %v %v",
+					tt.bold, tt.italic, tt.monospace, f.IsBold(), f.IsItalic(), f.IsMonospace())
+			}
+		})
+	}
+}
+
+func TestFontStyleFromName(t *testing.T) {
+	tests := []struct {
+		baseFont                string
+		bold, italic, monospace bool
+	}{
+		{"Helvetica", false, false, false},
+		{"Helvetica-BoldOblique", true, true, false},
+		{"Times-Italic", false, true, false},
+		{"Courier", false, false, true},
+		{"QWERTY+SourceCodePro-Semibold", true, false, false},
+		{"XYZABC+DejaVuSansMono", false, false, true},
+		{"SFMono-Regular", false, false, true},
+		{"CourierNewPS-BoldMT", true, false, true},
+		{"MonotypeCorsiva", false, false, false},
+	}
+
+	for _, tt := range tests {
+		f := NewFont("F1", tt.baseFont, "Type1")
+		if f.IsBold() != tt.bold || f.IsItalic() != tt.italic || f.IsMonospace() != tt.monospace {
+			t.Errorf("%s: expected bold=%v italic=%v monospace=%v, got %v %v %v",
+				tt.baseFont, tt.bold, tt.italic, tt.monospace, f.IsBold(), f.IsItalic(), f.IsMonospace())
+		}
+	}
+}
+
+func TestType0FontStyleFromDescendant(t *testing.T) {
+	fontDict := core.Dict{
+		"Subtype":  core.Name("Type0"),
+		"BaseFont": core.Name("NotoSansCJK"),
+		"Encoding": core.Name("Identity-H"),
+		"DescendantFonts": core.Array{core.Dict{
+			"Subtype":        core.Name("CIDFontType2"),
+			"BaseFont":       core.Name("NotoSansCJK"),
+			"CIDSystemInfo":  core.Dict{"Registry": core.String("Adobe"), "Ordering": core.String("Identity"), "Supplement": core.Int(0)},
+			"FontDescriptor": core.Dict{"FontWeight": core.Int(900)},
+		}},
+	}
+	f, err := NewType0Font(fontDict, mockResolver)
+	if err != nil {
+		t.Fatalf("NewType0Font failed: %v", err)
+	}
+	if !f.IsBold() {
+		t.Error("Expected the descendant's /FontWeight 900 to make the font bold")
+	}
+}
@@ -18,23 +18,27 @@ type TrueTypeFont struct {
 	LastChar       int
 	Widths         []float64
 	FontDescriptor *FontDescriptor
-	ToUnicode      *core.Stream // CMap for character code to Unicode mapping
+	ToUnicode      *core.Stream    // CMap for character code to Unicode mapping
+	Differences    map[byte]string // Character code -> glyph name from /Encoding
 
 	// TrueType font program data
 	FontProgram []byte            // Raw font program from FontFile2
 	Tables      map[string][]byte // Parsed TrueType tables
 
 	// Parsed table data
-	unitsPerEm  uint16
-	glyphWidths map[uint16]uint16 // Glyph ID -> width
-	cmapTable   *CMapTable        // Character to glyph mapping
-	isSubset    bool              // Whether this is a subset font
+	unitsPerEm    uint16
+	glyphWidths   map[uint16]uint16          // Glyph ID -> width
+	cmapTable     *CMapTable                 // Character to glyph mapping
+	cmapSubtables map[uint32]map[rune]uint16 // All supported cmap subtables by platform/encoding
+	glyphNames    []string                   // Glyph ID -> name, from the 'post' table
+	isSubset      bool                       // Whether this is a subset font
 }
 
 // CMapTable represents a TrueType cmap table
 type CMapTable struct {
-	format   uint16
-	encoding map[rune]uint16 // Character code -> Glyph ID
+	platformID uint16
+	encodingID uint16
+	encoding   map[rune]uint16 // Character code -> Glyph ID
 }
 
 // NewTrueTypeFont creates a TrueType font from a PDF font dictionary
@@ -112,6 +116,17 @@ func NewTrueTypeFont(fontDict core.Dict, resolver func(core.IndirectRef) (core.O
 		}
 	}
 
+	// Without a ToUnicode CMap, map codes through the font program's cmap
+	// and glyph names, then the Differences glyph names
+	if tt.ToUnicodeCMap == nil {
+		runes := tt.recoverUnicode()
+		if runes == nil {
+			runes = make(map[byte]rune)
+		}
+		glyphNamesToRunes(tt.Differences, runes)
+		tt.setCodeUnicode(runes)
+	}
+
 	return tt, nil
 }
 
@@ -166,6 +181,26 @@ func (tt *TrueTypeFont) parseEncoding(fontDict core.Dict, resolver func(core.Ind
 		} else {
 			tt.Encoding = "WinAnsiEncoding"
 		}
+
+		// Record differences
+		if diffsObj := dict.Get("Differences"); diffsObj != nil {
+			// Resolve if indirect
+			if ref, ok := diffsObj.(core.IndirectRef); ok {
+				obj, err := resolver(ref)
+				if err != nil {
+					return err
+				}
+				diffsObj = obj
+			}
+
+			if diffs, ok := diffsObj.(core.Array); ok {
+				names, err := parseDifferences(diffs)
+				if err != nil {
+					return err
+				}
+				tt.Differences = names
+			}
+		}
 		return nil
 	}
 
@@ -289,6 +324,7 @@ func (tt *TrueTypeFont) parseFontDescriptor(fontDict core.Dict, resolver func(co
 
 	// Font metrics
 	fd.ItalicAngle = getNumber(fdDict.Get("ItalicAngle"))
+	fd.FontWeight = getNumber(fdDict.Get("FontWeight"))
 	fd.Ascent = getNumber(fdDict.Get("Ascent"))
 	fd.Descent = getNumber(fdDict.Get("Descent"))
 	fd.CapHeight = getNumber(fdDict.Get("CapHeight"))
@@ -313,6 +349,7 @@ func (tt *TrueTypeFont) parseFontDescriptor(fontDict core.Dict, resolver func(co
 	}
 
 	tt.FontDescriptor = fd
+	tt.Font.descriptor = fd
 
 	return nil
 }
@@ -341,46 +378,11 @@ func (tt *TrueTypeFont) parseFontProgram() error {
 
 // parseTrueTypeTables parses the binary TrueType font tables
 func (tt *TrueTypeFont) parseTrueTypeTables() error {
-	if len(tt.FontProgram) < 12 {
-		return fmt.Errorf("font program too short")
-	}
-
-	r := bytes.NewReader(tt.FontProgram)
-
-	// Read offset table (font directory)
-	var offsetTable struct {
-		SfntVersion   uint32
-		NumTables     uint16
-		SearchRange   uint16
-		EntrySelector uint16
-		RangeShift    uint16
-	}
-
-	if err := binary.Read(r, binary.BigEndian, &offsetTable); err != nil {
-		return fmt.Errorf("failed to read offset table: %w", err)
-	}
-
-	// Read table directory
-	for i := 0; i < int(offsetTable.NumTables); i++ {
-		var entry struct {
-			Tag      [4]byte
-			Checksum uint32
-			Offset   uint32
-			Length   uint32
-		}
-
-		if err := binary.Read(r, binary.BigEndian, &entry); err != nil {
-			return fmt.Errorf("failed to read table entry %d: %w", i, err)
-		}
-
-		tag := string(entry.Tag[:])
-
-		// Extract table data
-		if int(entry.Offset)+int(entry.Length) <= len(tt.FontProgram) {
-			tableData := tt.FontProgram[entry.Offset : entry.Offset+entry.Length]
-			tt.Tables[tag] = tableData
-		}
+	tables, err := parseSfntTables(tt.FontProgram)
+	if err != nil {
+		return err
 	}
+	tt.Tables = tables
 
 	// Parse key tables
 	if err := tt.parseHeadTable(); err != nil {
@@ -397,6 +399,12 @@ func (tt *TrueTypeFont) parseTrueTypeTables() error {
 		_ = err
 	}
 
+	if postData, ok := tt.Tables["post"]; ok {
+		if names, err := parsePostGlyphNames(postData); err == nil {
+			tt.glyphNames = names
+		}
+	}
+
 	return nil
 }
 
@@ -477,109 +485,26 @@ func (tt *TrueTypeFont) parseCmapTable() error {
 		return fmt.Errorf("cmap table not found")
 	}
 
-	if len(cmapData) < 4 {
-		return fmt.Errorf("cmap table too short")
-	}
-
-	r := bytes.NewReader(cmapData)
-
-	// Read cmap header
-	var version, numTables uint16
-	binary.Read(r, binary.BigEndian, &version)
-	binary.Read(r, binary.BigEndian, &numTables)
-
-	// Find a suitable subtable (prefer Unicode)
-	var bestOffset uint32
-	var bestFormat uint16
-
-	for i := uint16(0); i < numTables; i++ {
-		var platformID, encodingID uint16
-		var offset uint32
-
-		binary.Read(r, binary.BigEndian, &platformID)
-		binary.Read(r, binary.BigEndian, &encodingID)
-		binary.Read(r, binary.BigEndian, &offset)
-
-		// Prefer Unicode platform (3) with Unicode BMP encoding (1)
-		if platformID == 3 && encodingID == 1 {
-			bestOffset = offset
-			break
-		}
-
-		// Fallback to first found table
-		if bestOffset == 0 {
-			bestOffset = offset
-		}
-	}
-
-	if bestOffset == 0 {
-		return fmt.Errorf("no suitable cmap subtable found")
-	}
-
-	// Read the subtable format
-	r.Seek(int64(bestOffset), 0)
-	binary.Read(r, binary.BigEndian, &bestFormat)
-
-	tt.cmapTable = &CMapTable{
-		format:   bestFormat,
-		encoding: make(map[rune]uint16),
-	}
-
-	// Parse based on format
-	// Format 4 is most common for Unicode BMP
-	if bestFormat == 4 {
-		return tt.parseCmapFormat4(r)
-	}
-
-	// Other formats would be implemented here
-	return fmt.Errorf("cmap format %d not yet supported", bestFormat)
-}
-
-// parseCmapFormat4 parses a format 4 cmap subtable
-func (tt *TrueTypeFont) parseCmapFormat4(r *bytes.Reader) error {
-	// Format 4 structure:
-	// uint16 format
-	// uint16 length
-	// uint16 language
-	// uint16 segCountX2
-	// ... (complex structure for segment mapping)
-
-	var length, language, segCountX2 uint16
-	binary.Read(r, binary.BigEndian, &length)
-	binary.Read(r, binary.BigEndian, &language)
-	binary.Read(r, binary.BigEndian, &segCountX2)
-
-	segCount := segCountX2 / 2
-
-	// Skip searchRange, entrySelector, rangeShift
-	r.Seek(6, 1)
-
-	// Read endCode array
-	endCode := make([]uint16, segCount)
-	for i := range endCode {
-		binary.Read(r, binary.BigEndian, &endCode[i])
-	}
-
-	// Skip reservedPad
-	r.Seek(2, 1)
-
-	// Read startCode array
-	startCode := make([]uint16, segCount)
-	for i := range startCode {
-		binary.Read(r, binary.BigEndian, &startCode[i])
+	subtables, err := parseCmapSubtables(cmapData)
+	if err != nil {
+		return err
 	}
+	tt.cmapSubtables = subtables
 
-	// For simplicity, we'll just store a basic mapping
-	// Full implementation would handle idDelta and idRangeOffset
-	for i := range startCode {
-		// Use uint32 for the loop counter to avoid infinite loop when endCode is 0xFFFF
-		// (uint16 would wrap from 0xFFFF to 0 on increment)
-		for c := uint32(startCode[i]); c <= uint32(endCode[i]); c++ {
-			tt.cmapTable.encoding[rune(c)] = uint16(c)
+	// Prefer a Unicode subtable for rune lookups, then the symbol and
+	// Macintosh subtables that simple fonts use for single-byte codes
+	for _, key := range []uint32{cmapWindowsFull, cmapWindowsBMP, cmapUnicodeFull, cmapUnicodeBMP, cmapWindowsSymbol, cmapMacRoman} {
+		if mapping, ok := subtables[key]; ok {
+			tt.cmapTable = &CMapTable{
+				platformID: uint16(key >> 16),
+				encodingID: uint16(key),
+				encoding:   mapping,
+			}
+			return nil
 		}
 	}
 
-	return nil
+	return fmt.Errorf("no suitable cmap subtable found")
 }
 
 // GetGlyphID returns the glyph ID for a character
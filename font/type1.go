@@ -16,13 +16,19 @@ type Type1Font struct {
 	LastChar       int
 	Widths         []float64
 	FontDescriptor *FontDescriptor
-	ToUnicode      *core.Stream // CMap for character code to Unicode mapping
+	ToUnicode      *core.Stream    // CMap for character code to Unicode mapping
+	Differences    map[byte]string // Character code -> glyph name from /Encoding
+
+	// usesBuiltinEncoding is true when /Encoding does not name a base
+	// encoding, so codes go through the font program's own encoding
+	usesBuiltinEncoding bool
 }
 
 // FontDescriptor contains font metrics and properties
 type FontDescriptor struct {
 	FontName     string
 	Flags        int
+	FontWeight   float64    // 100-900; 400 is normal and 700 bold
 	FontBBox     [4]float64 // [llx lly urx ury]
 	ItalicAngle  float64
 	Ascent       float64
@@ -102,6 +108,24 @@ func NewType1Font(fontDict core.Dict, resolver func(core.IndirectRef) (core.Obje
 		}
 	}
 
+	// Without a ToUnicode CMap, map codes through the font program's
+	// built-in encoding and the Differences glyph names
+	if t1.ToUnicodeCMap == nil {
+		names := make(map[byte]string)
+		if t1.usesBuiltinEncoding {
+			for code, name := range t1.FontDescriptor.builtinGlyphNames() {
+				names[code] = name
+			}
+		}
+		for code, name := range t1.Differences {
+			names[code] = name
+		}
+
+		runes := make(map[byte]rune)
+		glyphNamesToRunes(names, runes)
+		t1.setCodeUnicode(runes)
+	}
+
 	return t1, nil
 }
 
@@ -111,6 +135,7 @@ func (t1 *Type1Font) parseEncoding(fontDict core.Dict, resolver func(core.Indire
 	if encodingObj == nil {
 		// Use default encoding
 		t1.Encoding = "StandardEncoding"
+		t1.usesBuiltinEncoding = true
 		return nil
 	}
 
@@ -138,6 +163,7 @@ func (t1 *Type1Font) parseEncoding(fontDict core.Dict, resolver func(core.Indire
 			}
 		} else {
 			t1.Encoding = "StandardEncoding"
+			t1.usesBuiltinEncoding = true
 		}
 
 		// Apply differences
@@ -164,24 +190,18 @@ func (t1 *Type1Font) parseEncoding(fontDict core.Dict, resolver func(core.Indire
 	return fmt.Errorf("invalid encoding type: %T", encodingObj)
 }
 
-// applyEncodingDifferences applies the Differences array to customize encoding
+// applyEncodingDifferences records the glyph names of the Differences array
 // Format: [code name1 name2 ... code name1 name2 ...]
 func (t1 *Type1Font) applyEncodingDifferences(diffs core.Array) error {
-	code := 0
-	for _, item := range diffs {
-		switch v := item.(type) {
-		case core.Int:
-			// This is a starting code
-			code = int(v)
-		case core.Name:
-			// This is a glyph name mapped to current code
-			// We would need a glyph name to Unicode mapping table here
-			// For now, just increment the code
-			// TODO: Implement proper glyph name to Unicode mapping
-			code++
-		default:
-			return fmt.Errorf("invalid differences array item: %T", item)
-		}
+	names, err := parseDifferences(diffs)
+	if err != nil {
+		return err
+	}
+	if t1.Differences == nil {
+		t1.Differences = make(map[byte]string)
+	}
+	for code, name := range names {
+		t1.Differences[code] = name
 	}
 	return nil
 }
@@ -303,6 +323,7 @@ func (t1 *Type1Font) parseFontDescriptor(fontDict core.Dict, resolver func(core.
 
 	// Font metrics
 	fd.ItalicAngle = getNumber(fdDict.Get("ItalicAngle"))
+	fd.FontWeight = getNumber(fdDict.Get("FontWeight"))
 	fd.Ascent = getNumber(fdDict.Get("Ascent"))
 	fd.Descent = getNumber(fdDict.Get("Descent"))
 	fd.CapHeight = getNumber(fdDict.Get("CapHeight"))
@@ -347,6 +368,7 @@ func (t1 *Type1Font) parseFontDescriptor(fontDict core.Dict, resolver func(core.
 	}
 
 	t1.FontDescriptor = fd
+	t1.Font.descriptor = fd
 
 	// Use MissingWidth from font descriptor if available
 	if fd.MissingWidth > 0 {
@@ -387,3 +409,15 @@ func getNumber(obj core.Object) float64 {
 		return 0
 	}
 }
+
+// resolveIfRef resolves an indirect reference, or returns the object unchanged
+// if it is not a reference
+func resolveIfRef(obj core.Object, resolver func(core.IndirectRef) (core.Object, error)) (core.Object, error) {
+	if ref, ok := obj.(core.IndirectRef); ok {
+		if resolver == nil {
+			return nil, fmt.Errorf("cannot resolve %v without a resolver", ref)
+		}
+		return resolver(ref)
+	}
+	return obj, nil
+}
@@ -0,0 +1,532 @@
+package font
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"strconv"
+)
+
+// Type 1 encryption keys (Adobe Type 1 Font Format, chapter 7)
+const (
+	eexecKey      = 55665
+	charStringKey = 4330
+)
+
+// type1Program holds the glyph programs of a Type 1 font program (FontFile)
+type type1Program struct {
+	charStrings map[string][]byte // Decrypted charstrings by glyph name
+	subrs       [][]byte          // Decrypted subroutines
+	scale       float64           // FontMatrix scale from glyph space to text space
+}
+
+// parseType1Program reads the charstrings and subroutines from the
+// eexec-encrypted portion of a Type 1 font program
+func parseType1Program(data []byte) (*type1Program, bool) {
+	start := bytes.Index(data, []byte("eexec"))
+	if start < 0 {
+		return nil, false
+	}
+	clear, encrypted := data[:start], data[start+len("eexec"):]
+	for len(encrypted) > 0 && isPSWhitespace(encrypted[0]) {
+		encrypted = encrypted[1:]
+	}
+	if isHexPrefix(encrypted) {
+		encrypted = decodeHexPortion(encrypted)
+	}
+	private := decryptType1(encrypted, eexecKey, 4)
+
+	p := &type1Program{charStrings: make(map[string][]byte), scale: 0.001}
+	if m := fontMatrixScale(clear); m > 0 {
+		p.scale = m
+	}
+
+	lenIV := 4
+	if i := bytes.Index(private, []byte("/lenIV")); i >= 0 {
+		if v, ok := nextPSInt(private[i+len("/lenIV"):]); ok {
+			lenIV = v
+		}
+	}
+	decrypt := func(b []byte) []byte {
+		if lenIV < 0 {
+			return b
+		}
+		return decryptType1(b, charStringKey, lenIV)
+	}
+
+	if i := bytes.Index(private, []byte("/Subrs")); i >= 0 {
+		p.subrs = parseType1Subrs(private[i+len("/Subrs"):], decrypt)
+	}
+	i := bytes.Index(private, []byte("/CharStrings"))
+	if i < 0 {
+		return nil, false
+	}
+	parseType1CharStrings(private[i+len("/CharStrings"):], decrypt, p.charStrings)
+	return p, len(p.charStrings) > 0
+}
+
+// decryptType1 decrypts eexec or charstring data, dropping the first skip
+// random bytes
+func decryptType1(data []byte, key uint16, skip int) []byte {
+	out := make([]byte, len(data))
+	r := key
+	for i, c := range data {
+		out[i] = c ^ byte(r>>8)
+		r = (uint16(c)+r)*52845 + 22719
+	}
+	if skip > len(out) {
+		return nil
+	}
+	return out[skip:]
+}
+
+// isHexPrefix reports whether encrypted data is stored as hexadecimal
+// text, as in PFA files, rather than binary
+func isHexPrefix(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+	for _, c := range data[:4] {
+		if !isHexDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// decodeHexPortion decodes hexadecimal text, ignoring whitespace and
+// stopping at the first other character
+func decodeHexPortion(data []byte) []byte {
+	digits := make([]byte, 0, len(data))
+	for _, c := range data {
+		if isHexDigit(c) {
+			digits = append(digits, c)
+		} else if !isPSWhitespace(c) {
+			break
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = digits[:len(digits)-1]
+	}
+	out := make([]byte, len(digits)/2)
+	if _, err := hex.Decode(out, digits); err != nil {
+		return nil
+	}
+	return out
+}
+
+func isPSWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+// nextPSToken returns the next whitespace-delimited token and the data
+// after it
+func nextPSToken(data []byte) ([]byte, []byte) {
+	for len(data) > 0 && isPSWhitespace(data[0]) {
+		data = data[1:]
+	}
+	end := 0
+	for end < len(data) && !isPSWhitespace(data[end]) {
+		end++
+	}
+	return data[:end], data[end:]
+}
+
+// nextPSInt parses the next token as an integer
+func nextPSInt(data []byte) (int, bool) {
+	tok, _ := nextPSToken(data)
+	v, err := strconv.Atoi(string(tok))
+	return v, err == nil
+}
+
+// fontMatrixScale returns the horizontal scale of the /FontMatrix in a
+// Type 1 font's cleartext portion, or 0 if there is none
+func fontMatrixScale(clear []byte) float64 {
+	i := bytes.Index(clear, []byte("/FontMatrix"))
+	if i < 0 {
+		return 0
+	}
+	rest := clear[i+len("/FontMatrix"):]
+	open := bytes.IndexAny(rest, "[{")
+	if open < 0 || open > 8 {
+		return 0
+	}
+	tok, _ := nextPSToken(rest[open+1:])
+	v, err := strconv.ParseFloat(string(tok), 64)
+	if err != nil || v <= 0 || math.IsInf(v, 0) {
+		return 0
+	}
+	return v
+}
+
+// readType1Binary reads the "<length> RD <binary>" form used by the Subrs
+// and CharStrings entries, returning the binary and the data after it.
+// RD stands for any token, often -| or RD.
+func readType1Binary(data []byte) ([]byte, []byte, bool) {
+	n, ok := nextPSInt(data)
+	if !ok || n < 0 {
+		return nil, nil, false
+	}
+	_, data = nextPSToken(data) // Length
+	_, data = nextPSToken(data) // RD
+	if len(data) < n+1 {
+		return nil, nil, false
+	}
+	// A single space separates RD from the binary
+	return data[1 : n+1], data[n+1:], true
+}
+
+// parseType1Subrs reads the "dup <index> <length> RD <binary> NP" entries of
+// the Subrs array
+func parseType1Subrs(data []byte, decrypt func([]byte) []byte) [][]byte {
+	count, ok := nextPSInt(data)
+	if !ok || count <= 0 || count > 1<<16 {
+		return nil
+	}
+	subrs := make([][]byte, count)
+	for {
+		tok, rest := nextPSToken(data)
+		if len(tok) == 0 {
+			break
+		}
+		if string(tok) != "dup" {
+			if bytes.Equal(tok, []byte("/CharStrings")) || bytes.HasSuffix(tok, []byte("def")) && !bytes.Equal(tok, []byte("def")) {
+				break
+			}
+			data = rest
+			continue
+		}
+		index, ok := nextPSInt(rest)
+		if !ok {
+			break
+		}
+		_, rest = nextPSToken(rest)
+		bin, rest, ok := readType1Binary(rest)
+		if !ok {
+			break
+		}
+		if index >= 0 && index < count {
+			subrs[index] = decrypt(bin)
+		}
+		data = rest
+		if index == count-1 {
+			break
+		}
+	}
+	return subrs
+}
+
+// parseType1CharStrings reads the "/<name> <length> RD <binary> ND" entries
+// of the CharStrings dictionary
+func parseType1CharStrings(data []byte, decrypt func([]byte) []byte, out map[string][]byte) {
+	for {
+		tok, rest := nextPSToken(data)
+		if len(tok) == 0 || string(tok) == "end" {
+			return
+		}
+		if tok[0] != '/' {
+			data = rest
+			continue
+		}
+		bin, after, ok := readType1Binary(rest)
+		if !ok {
+			// A name that is not a charstring, such as a dictionary key
+			data = rest
+			continue
+		}
+		out[string(tok[1:])] = decrypt(bin)
+		data = after
+	}
+}
+
+// outline returns the outline of a named glyph, or nil if the font has no
+// such glyph
+func (p *type1Program) outline(name string) Outline {
+	code, ok := p.charStrings[name]
+	if !ok {
+		return nil
+	}
+	b := newOutlineBuilder(p.scale)
+	in := &type1Interp{prog: p, b: b}
+	in.run(code, 0)
+	return b.outline()
+}
+
+// type1Interp runs Type 1 charstrings (Adobe Type 1 Font Format, chapter
+// 6), with the flex and hint replacement mechanisms of the standard
+// OtherSubrs
+type type1Interp struct {
+	prog   *type1Program
+	b      *outlineBuilder
+	stack  []float64
+	ps     []float64 // PostScript stack of callothersubr and pop
+	x, y   float64
+	sbx    float64 // Left side bearing of the glyph
+	dx, dy float64 // Offset of an accent glyph
+	depth  int     // Accent nesting
+
+	flex    bool
+	flexPts [][2]float64
+	ops     int
+	done    bool
+}
+
+func (in *type1Interp) push(v float64) {
+	if len(in.stack) >= maxCharStack {
+		in.done = true
+		return
+	}
+	in.stack = append(in.stack, v)
+}
+
+func (in *type1Interp) moveTo(dx, dy float64) {
+	in.x += dx
+	in.y += dy
+	if in.flex {
+		in.flexPts = append(in.flexPts, [2]float64{in.x, in.y})
+		return
+	}
+	in.b.moveTo(in.x+in.dx, in.y+in.dy)
+}
+
+func (in *type1Interp) lineTo(dx, dy float64) {
+	in.x += dx
+	in.y += dy
+	in.b.lineTo(in.x+in.dx, in.y+in.dy)
+}
+
+func (in *type1Interp) curveTo(dxa, dya, dxb, dyb, dxc, dyc float64) {
+	x1, y1 := in.x+dxa, in.y+dya
+	x2, y2 := x1+dxb, y1+dyb
+	in.x, in.y = x2+dxc, y2+dyc
+	in.b.cubeTo(x1+in.dx, y1+in.dy, x2+in.dx, y2+in.dy, in.x+in.dx, in.y+in.dy)
+}
+
+// run interprets a charstring, calling subroutines recursively
+func (in *type1Interp) run(code []byte, level int) {
+	if level > maxSubrDepth {
+		in.done = true
+		return
+	}
+	for pos := 0; pos < len(code) && !in.done; {
+		in.ops++
+		if in.ops > maxCharStringOps {
+			in.done = true
+			return
+		}
+		v := code[pos]
+		switch {
+		case v >= 32 && v <= 246:
+			in.push(float64(int(v) - 139))
+			pos++
+			continue
+		case v >= 247 && v <= 250:
+			if pos+2 > len(code) {
+				in.done = true
+				return
+			}
+			in.push(float64((int(v)-247)*256 + int(code[pos+1]) + 108))
+			pos += 2
+			continue
+		case v >= 251 && v <= 254:
+			if pos+2 > len(code) {
+				in.done = true
+				return
+			}
+			in.push(float64(-(int(v)-251)*256 - int(code[pos+1]) - 108))
+			pos += 2
+			continue
+		case v == 255:
+			if pos+5 > len(code) {
+				in.done = true
+				return
+			}
+			in.push(float64(int32(uint32(code[pos+1])<<24 | uint32(code[pos+2])<<16 | uint32(code[pos+3])<<8 | uint32(code[pos+4]))))
+			pos += 5
+			continue
+		}
+
+		pos++
+		op := int(v)
+		if v == 12 {
+			if pos >= len(code) {
+				in.done = true
+				return
+			}
+			op = 1200 + int(code[pos])
+			pos++
+		}
+
+		s := in.stack
+		switch op {
+		case 13: // hsbw
+			if len(s) >= 2 {
+				in.sbx = s[0]
+				in.x, in.y = s[0], 0
+			}
+		case 1207: // sbw
+			if len(s) >= 4 {
+				in.sbx = s[0]
+				in.x, in.y = s[0], s[1]
+			}
+		case 21: // rmoveto
+			if len(s) >= 2 {
+				in.moveTo(s[0], s[1])
+			}
+		case 22: // hmoveto
+			if len(s) >= 1 {
+				in.moveTo(s[0], 0)
+			}
+		case 4: // vmoveto
+			if len(s) >= 1 {
+				in.moveTo(0, s[0])
+			}
+		case 5: // rlineto
+			if len(s) >= 2 {
+				in.lineTo(s[0], s[1])
+			}
+		case 6: // hlineto
+			if len(s) >= 1 {
+				in.lineTo(s[0], 0)
+			}
+		case 7: // vlineto
+			if len(s) >= 1 {
+				in.lineTo(0, s[0])
+			}
+		case 8: // rrcurveto
+			if len(s) >= 6 {
+				in.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			}
+		case 30: // vhcurveto
+			if len(s) >= 4 {
+				in.curveTo(0, s[0], s[1], s[2], s[3], 0)
+			}
+		case 31: // hvcurveto
+			if len(s) >= 4 {
+				in.curveTo(s[0], 0, s[1], s[2], 0, s[3])
+			}
+		case 9: // closepath
+			in.b.closePath()
+		case 10: // callsubr
+			if len(s) == 0 {
+				in.done = true
+				return
+			}
+			n := int(s[len(s)-1])
+			in.stack = s[:len(s)-1]
+			if n < 0 || n >= len(in.prog.subrs) || in.prog.subrs[n] == nil {
+				in.done = true
+				return
+			}
+			in.run(in.prog.subrs[n], level+1)
+			continue // The subroutine leaves its results on the stack
+		case 11: // return
+			return
+		case 14: // endchar
+			in.b.closePath()
+			in.done = true
+			return
+		case 1206: // seac
+			if len(s) >= 5 {
+				in.seac(s[0], s[1], s[2], int(s[3]), int(s[4]))
+			}
+			in.done = true
+			return
+		case 1212: // div
+			if len(s) >= 2 {
+				r := 0.0
+				if s[len(s)-1] != 0 {
+					r = s[len(s)-2] / s[len(s)-1]
+				}
+				in.stack = append(s[:len(s)-2], r)
+			}
+			continue
+		case 1216: // callothersubr
+			in.callOtherSubr()
+			continue
+		case 1217: // pop
+			if n := len(in.ps); n > 0 {
+				in.push(in.ps[n-1])
+				in.ps = in.ps[:n-1]
+			}
+			continue
+		case 1233: // setcurrentpoint
+			if len(s) >= 2 {
+				in.x, in.y = s[0], s[1]
+			}
+		}
+		// Hints (hstem, vstem, dotsection, hstem3, vstem3) and all
+		// painting operators clear the stack
+		in.stack = in.stack[:0]
+	}
+}
+
+// callOtherSubr runs the standard OtherSubrs: 0 to 2 implement flex, 3
+// hint replacement. Arguments of other OtherSubrs are passed through to
+// pop.
+func (in *type1Interp) callOtherSubr() {
+	s := in.stack
+	if len(s) < 2 {
+		in.stack = s[:0]
+		return
+	}
+	n, count := int(s[len(s)-1]), int(s[len(s)-2])
+	s = s[:len(s)-2]
+	if count < 0 || count > len(s) {
+		count = len(s)
+	}
+	args := append([]float64(nil), s[len(s)-count:]...)
+	in.stack = s[:len(s)-count]
+
+	switch n {
+	case 1: // Start flex
+		in.flex = true
+		in.flexPts = in.flexPts[:0]
+	case 2: // Add a flex point; the points are collected by rmoveto
+	case 0: // End flex
+		in.flex = false
+		if pts := in.flexPts; len(pts) >= 7 {
+			// The first point is the reference point; the others are
+			// the control and end points of two curves
+			for i := 1; i+2 < 7; i += 3 {
+				in.b.cubeTo(pts[i][0]+in.dx, pts[i][1]+in.dy, pts[i+1][0]+in.dx, pts[i+1][1]+in.dy, pts[i+2][0]+in.dx, pts[i+2][1]+in.dy)
+			}
+			in.x, in.y = pts[6][0], pts[6][1]
+		}
+		in.ps = in.ps[:0]
+		in.ps = append(in.ps, in.y, in.x) // Popped as x, then y
+		return
+	case 3: // Hint replacement; the subroutine number is popped
+		in.ps = append(in.ps[:0], 3)
+		return
+	}
+	in.ps = in.ps[:0]
+	for i := len(args) - 1; i >= 0; i-- {
+		in.ps = append(in.ps, args[i])
+	}
+}
+
+// seac draws an accented character from two glyphs named by their
+// StandardEncoding codes. The accent's origin is offset by (adx, ady) from
+// the base character's, less the accent's side bearing asb.
+func (in *type1Interp) seac(asb, adx, ady float64, base, accent int) {
+	if in.depth > 0 || base < 0 || base > 255 || accent < 0 || accent > 255 {
+		return
+	}
+	baseCode, ok1 := in.prog.charStrings[standardGlyphName(byte(base))]
+	accentCode, ok2 := in.prog.charStrings[standardGlyphName(byte(accent))]
+	if !ok1 || !ok2 {
+		return
+	}
+	in.b.closePath()
+	baseIn := &type1Interp{prog: in.prog, b: in.b, depth: 1}
+	baseIn.run(baseCode, 0)
+	in.b.closePath()
+	accentIn := &type1Interp{prog: in.prog, b: in.b, depth: 1, dx: in.sbx + adx - asb, dy: ady}
+	accentIn.run(accentCode, 0)
+	in.b.closePath()
+}
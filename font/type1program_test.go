@@ -0,0 +1,117 @@
+package font
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// encryptType1 is the inverse of decryptType1, with four zero bytes of
+// leading padding
+func encryptType1(plain []byte, key uint16) []byte {
+	out := make([]byte, 0, len(plain)+4)
+	r := key
+	for _, p := range append([]byte{0, 0, 0, 0}, plain...) {
+		c := p ^ byte(r>>8)
+		r = (uint16(c)+r)*52845 + 22719
+		out = append(out, c)
+	}
+	return out
+}
+
+// buildType1Program assembles a Type 1 font program with the given
+// charstrings and subroutines
+func buildType1Program(charStrings map[string][]byte, subrs [][]byte) []byte {
+	var private bytes.Buffer
+	private.WriteString("dup /Private 8 dict dup begin /lenIV 4 def\n")
+	fmt.Fprintf(&private, "/Subrs %d array\n", len(subrs))
+	for i, s := range subrs {
+		enc := encryptType1(s, charStringKey)
+		fmt.Fprintf(&private, "dup %d %d RD ", i, len(enc))
+		private.Write(enc)
+		private.WriteString(" NP\n")
+	}
+	private.WriteString("ND\n")
+	fmt.Fprintf(&private, "2 index /CharStrings %d dict dup begin\n", len(charStrings))
+	for name, cs := range charStrings {
+		enc := encryptType1(cs, charStringKey)
+		fmt.Fprintf(&private, "/%s %d RD ", name, len(enc))
+		private.Write(enc)
+		private.WriteString(" ND\n")
+	}
+	private.WriteString("end\nend\n")
+
+	var program bytes.Buffer
+	program.WriteString("%!PS-AdobeFont-1.0: Test\n/FontMatrix [0.001 0 0 0.001 0 0] readonly def\ncurrentfile eexec\n")
+	program.Write(encryptType1(private.Bytes(), eexecKey))
+	return program.Bytes()
+}
+
+func TestType1ProgramOutline(t *testing.T) {
+	// 0 50 hsbw 10 0 rmoveto 100 0 rlineto 0 subr closepath endchar
+	square := append(csNums(0, 50), 13)
+	square = append(square, csNums(10, 0)...)
+	square = append(square, 21)
+	square = append(square, csNums(100, 0)...)
+	square = append(square, 5)
+	square = append(square, csNums(0)...)
+	square = append(square, 10, 9, 14)
+	subr := append(csNums(0, 100), 5, 11) // 0 100 rlineto return
+
+	p, ok := parseType1Program(buildType1Program(map[string][]byte{"A": square}, [][]byte{subr}))
+	if !ok {
+		t.Fatal("parseType1Program failed")
+	}
+	if p.scale != 0.001 {
+		t.Errorf("Expected scale 0.001, got %v", p.scale)
+	}
+	if len(p.subrs) != 1 {
+		t.Fatalf("Expected 1 subroutine, got %d", len(p.subrs))
+	}
+
+	o := p.outline("A")
+	if len(o) != 4 {
+		t.Fatalf("Expected 4 segments, got %d: %v", len(o), o)
+	}
+	if pt := o[0].Points[0]; pt.X != 0.01 || pt.Y != 0 {
+		t.Errorf("Expected a move to (0.01, 0), got %v", pt)
+	}
+	if pt := o[2].Points[0]; pt.X != 0.11 || pt.Y != 0.1 {
+		t.Errorf("Expected the subroutine's line to (0.11, 0.1), got %v", pt)
+	}
+	if o := p.outline("B"); o != nil {
+		t.Errorf("Expected no outline for a missing glyph, got %v", o)
+	}
+}
+
+func TestType1ProgramHex(t *testing.T) {
+	program := buildType1Program(map[string][]byte{"A": append(csNums(0, 50), 13, 14)}, nil)
+	i := bytes.Index(program, []byte("eexec")) + len("eexec\n")
+	hexProgram := append([]byte{}, program[:i]...)
+	hexProgram = append(hexProgram, fmt.Sprintf("%X", program[i:])...)
+
+	p, ok := parseType1Program(hexProgram)
+	if !ok {
+		t.Fatal("parseType1Program failed on a hexadecimal program")
+	}
+	if _, ok := p.charStrings["A"]; !ok {
+		t.Error("Expected charstring A")
+	}
+}
+
+func TestType1ProgramInvalid(t *testing.T) {
+	for _, data := range [][]byte{nil, []byte("%!PS no eexec"), []byte("eexec")} {
+		if _, ok := parseType1Program(data); ok {
+			t.Errorf("Expected %q to be rejected", data)
+		}
+	}
+}
+
+func TestStandardGlyphName(t *testing.T) {
+	tests := map[byte]string{'A': "A", 0xC1: "grave", 0: ""}
+	for code, want := range tests {
+		if got := standardGlyphName(code); got != want {
+			t.Errorf("standardGlyphName(%#x): Expected %q, got %q", code, want, got)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package font
+
+import (
+	"fmt"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// Type3Font represents a Type3 font in a PDF
+// Type3 glyphs are defined by content streams (CharProcs) rather than a font
+// program. They are common in TeX output that uses bitmap fonts and in some
+// report generators.
+type Type3Font struct {
+	*Font // Embed basic font
+
+	// Type3-specific fields
+	FirstChar   int
+	LastChar    int
+	Widths      []float64       // Glyph widths in glyph space
+	FontMatrix  [6]float64      // Glyph space to text space transform
+	FontBBox    [4]float64      // [llx lly urx ury] in glyph space
+	CharProcs   core.Dict       // Glyph name -> glyph content stream
+	Resources   core.Dict       // Resources used by the glyph procedures
+	Differences map[byte]string // Character code -> glyph name
+	ToUnicode   *core.Stream    // CMap for character code to Unicode mapping
+}
+
+// defaultFontMatrix is the glyph space of most fonts: 1000 units per em.
+var defaultFontMatrix = [6]float64{0.001, 0, 0, 0.001, 0, 0}
+
+// NewType3Font creates a Type3 font from a PDF font dictionary
+func NewType3Font(fontDict core.Dict, resolver func(core.IndirectRef) (core.Object, error)) (*Type3Font, error) {
+	// Extract basic font properties
+	name := extractName(fontDict.Get("Name"))
+	baseFont := extractName(fontDict.Get("BaseFont"))
+	subtype := extractName(fontDict.Get("Subtype"))
+
+	if subtype != "Type3" {
+		return nil, fmt.Errorf("not a Type3 font: %s", subtype)
+	}
+
+	// Type3 fonts usually have no BaseFont; fall back to the resource name
+	if baseFont == "" {
+		baseFont = name
+	}
+
+	t3 := &Type3Font{
+		Font:        NewFont(name, baseFont, subtype),
+		FirstChar:   0,
+		LastChar:    255,
+		FontMatrix:  defaultFontMatrix,
+		Differences: make(map[byte]string),
+	}
+
+	// Parse font matrix
+	if err := t3.parseFontMatrix(fontDict, resolver); err != nil {
+		return nil, fmt.Errorf("failed to parse font matrix: %w", err)
+	}
+
+	// Parse font bounding box (optional for text extraction)
+	if bboxObj, err := resolveIfRef(fontDict.Get("FontBBox"), resolver); err == nil {
+		if bbox, ok := bboxObj.(core.Array); ok && len(bbox) >= 4 {
+			for i := 0; i < 4; i++ {
+				t3.FontBBox[i] = getNumber(bbox[i])
+			}
+		}
+	}
+
+	// Glyph procedures and their resources are kept for rendering
+	if obj, err := resolveIfRef(fontDict.Get("CharProcs"), resolver); err == nil {
+		if dict, ok := obj.(core.Dict); ok {
+			t3.CharProcs = dict
+		}
+	}
+	if obj, err := resolveIfRef(fontDict.Get("Resources"), resolver); err == nil {
+		if dict, ok := obj.(core.Dict); ok {
+			t3.Resources = dict
+		}
+	}
+
+	// Parse encoding
+	if err := t3.parseEncoding(fontDict, resolver); err != nil {
+		return nil, fmt.Errorf("failed to parse encoding: %w", err)
+	}
+
+	// Parse ToUnicode CMap if present
+	if obj, err := resolveIfRef(fontDict.Get("ToUnicode"), resolver); err == nil {
+		if stream, ok := obj.(*core.Stream); ok {
+			t3.ToUnicode = stream
+
+			// Parse the ToUnicode CMap
+			if cmap, err := ParseToUnicodeCMap(stream); err == nil {
+				t3.Font.ToUnicodeCMap = cmap
+			}
+		}
+	}
+
+	// Parse widths last so they can be keyed by decoded character
+	if err := t3.parseWidths(fontDict, resolver); err != nil {
+		return nil, fmt.Errorf("failed to parse widths: %w", err)
+	}
+
+	return t3, nil
+}
+
+// parseFontMatrix extracts the FontMatrix, which maps glyph space to text space
+func (t3 *Type3Font) parseFontMatrix(fontDict core.Dict, resolver func(core.IndirectRef) (core.Object, error)) error {
+	obj, err := resolveIfRef(fontDict.Get("FontMatrix"), resolver)
+	if err != nil {
+		return err
+	}
+	if obj == nil {
+		// FontMatrix is required, but 1000 units per em is the usual value
+		return nil
+	}
+
+	arr, ok := obj.(core.Array)
+	if !ok || len(arr) != 6 {
+		return fmt.Errorf("font matrix is not a 6-element array: %v", obj)
+	}
+
+	for i, v := range arr {
+		t3.FontMatrix[i] = getNumber(v)
+	}
+
+	if t3.FontMatrix[0] == 0 {
+		return fmt.Errorf("font matrix has zero horizontal scale")
+	}
+
+	return nil
+}
+
+// parseEncoding extracts the encoding from the font dictionary.
+// Glyph names from the Differences array are mapped to Unicode through the
+// Adobe Glyph List; codes with unrecognised glyph names fall back to the base
+// encoding.
+func (t3 *Type3Font) parseEncoding(fontDict core.Dict, resolver func(core.IndirectRef) (core.Object, error)) error {
+	// Type3 fonts have no built-in encoding; StandardEncoding is the closest
+	// guess for codes the Differences array does not name
+	t3.Encoding = "StandardEncoding"
+
+	encodingObj, err := resolveIfRef(fontDict.Get("Encoding"), resolver)
+	if err != nil {
+		return err
+	}
+
+	switch enc := encodingObj.(type) {
+	case nil:
+		return nil
+	case core.Name:
+		t3.Encoding = string(enc)
+		return nil
+	case core.Dict:
+		if baseEnc, ok := enc.Get("BaseEncoding").(core.Name); ok {
+			t3.Encoding = string(baseEnc)
+		}
+
+		diffsObj, err := resolveIfRef(enc.Get("Differences"), resolver)
+		if err != nil {
+			return err
+		}
+		if diffs, ok := diffsObj.(core.Array); ok {
+			names, err := parseDifferences(diffs)
+			if err != nil {
+				return err
+			}
+			t3.Differences = names
+		}
+
+		runes := make(map[byte]rune)
+		glyphNamesToRunes(t3.Differences, runes)
+		t3.setCodeUnicode(runes)
+		return nil
+	default:
+		return fmt.Errorf("invalid encoding type: %T", encodingObj)
+	}
+}
+
+// parseWidths extracts glyph widths and converts them from glyph space to
+// 1000ths of em using the FontMatrix
+func (t3 *Type3Font) parseWidths(fontDict core.Dict, resolver func(core.IndirectRef) (core.Object, error)) error {
+	// Get FirstChar
+	if i, ok := fontDict.Get("FirstChar").(core.Int); ok {
+		t3.FirstChar = int(i)
+	}
+
+	// Get LastChar
+	if i, ok := fontDict.Get("LastChar").(core.Int); ok {
+		t3.LastChar = int(i)
+	}
+
+	widthsObj, err := resolveIfRef(fontDict.Get("Widths"), resolver)
+	if err != nil {
+		return err
+	}
+	if widthsObj == nil {
+		// No widths array - use defaults
+		return nil
+	}
+
+	widthsArray, ok := widthsObj.(core.Array)
+	if !ok {
+		return fmt.Errorf("widths is not an array: %T", widthsObj)
+	}
+
+	// Extract width values
+	t3.Widths = make([]float64, len(widthsArray))
+	for i, w := range widthsArray {
+		w, err := resolveIfRef(w, resolver)
+		if err != nil {
+			return err
+		}
+		switch v := w.(type) {
+		case core.Int:
+			t3.Widths[i] = float64(v)
+		case core.Real:
+			t3.Widths[i] = float64(v)
+		default:
+			return fmt.Errorf("invalid width type at index %d: %T", i, w)
+		}
+	}
+
+	// Widths are in glyph space; the FontMatrix scales them to text space,
+	// where 1 unit is 1 em
+	scale := t3.FontMatrix[0] * 1000
+	t3.codeWidths = make(map[byte]float64, len(t3.Widths))
+	for i, width := range t3.Widths {
+		code := t3.FirstChar + i
+		if code < 0 || code > t3.LastChar || code > 255 {
+			continue
+		}
+		scaled := width * scale
+		t3.codeWidths[byte(code)] = scaled
+
+		// Also key by decoded character so rune lookups such as the
+		// space width see the font's own metrics
+		runes := []rune(t3.DecodeString([]byte{byte(code)}))
+		if len(runes) == 1 {
+			t3.widths[runes[0]] = scaled
+		}
+	}
+
+	return nil
+}
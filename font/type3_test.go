@@ -0,0 +1,145 @@
+package font
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// type3FontDict returns a bitmap-style Type3 font whose glyph space is 100
+// units per em and whose glyph names are not plain character names.
+func type3FontDict() core.Dict {
+	return core.Dict{
+		"Type":       core.Name("Font"),
+		"Subtype":    core.Name("Type3"),
+		"FontMatrix": core.Array{core.Real(0.01), core.Int(0), core.Int(0), core.Real(0.01), core.Int(0), core.Int(0)},
+		"FontBBox":   core.Array{core.Int(0), core.Int(-20), core.Int(100), core.Int(80)},
+		"FirstChar":  core.Int(1),
+		"LastChar":   core.Int(4),
+		"Widths":     core.Array{core.Int(50), core.Int(30), core.Int(60), core.Int(25)},
+		"Encoding": core.Dict{
+			"Type":        core.Name("Encoding"),
+			"Differences": core.Array{core.Int(1), core.Name("H"), core.Name("i"), core.Name("uni00E9"), core.Name("space")},
+		},
+		"CharProcs": core.Dict{},
+	}
+}
+
+func TestNewType3Font_Basic(t *testing.T) {
+	font, err := NewType3Font(type3FontDict(), mockResolver)
+	if err != nil {
+		t.Fatalf("NewType3Font failed: %v", err)
+	}
+
+	if font.Subtype != "Type3" {
+		t.Errorf("Expected Subtype 'Type3', got '%s'", font.Subtype)
+	}
+	if font.FontMatrix[0] != 0.01 {
+		t.Errorf("Expected FontMatrix[0] 0.01, got %v", font.FontMatrix[0])
+	}
+	if font.FontBBox[3] != 80 {
+		t.Errorf("Expected FontBBox[3] 80, got %v", font.FontBBox[3])
+	}
+	if font.Differences[3] != "uni00E9" {
+		t.Errorf("Expected Differences[3] 'uni00E9', got '%s'", font.Differences[3])
+	}
+	if font.CharProcs == nil {
+		t.Error("Expected CharProcs to be set")
+	}
+}
+
+func TestNewType3Font_Differences(t *testing.T) {
+	font, err := NewType3Font(type3FontDict(), mockResolver)
+	if err != nil {
+		t.Fatalf("NewType3Font failed: %v", err)
+	}
+
+	got := font.DecodeString([]byte{1, 2, 4, 3})
+	if got != "Hi é" {
+		t.Errorf("Expected 'Hi é', got %q", got)
+	}
+}
+
+func TestNewType3Font_FontMatrixWidths(t *testing.T) {
+	font, err := NewType3Font(type3FontDict(), mockResolver)
+	if err != nil {
+		t.Fatalf("NewType3Font failed: %v", err)
+	}
+
+	// 50 glyph units at 0.01 per unit is half an em
+	width, ok := font.GetCodesWidth([]byte{1, 2})
+	if !ok {
+		t.Fatal("Expected per-code widths")
+	}
+	if math.Abs(width-800) > 0.001 {
+		t.Errorf("Expected width 800, got %v", width)
+	}
+
+	// Rune lookups see the same metrics
+	if w := font.GetWidth(' '); math.Abs(w-250) > 0.001 {
+		t.Errorf("Expected space width 250, got %v", w)
+	}
+}
+
+func TestNewType3Font_ToUnicode(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin
+begincmap
+1 begincodespacerange
+<00> <FF>
+endcodespacerange
+2 beginbfchar
+<01> <0058>
+<02> <0059>
+endbfchar
+endcmap`
+
+	dict := type3FontDict()
+	dict["ToUnicode"] = &core.Stream{Dict: core.Dict{}, Data: []byte(cmap)}
+
+	font, err := NewType3Font(dict, mockResolver)
+	if err != nil {
+		t.Fatalf("NewType3Font failed: %v", err)
+	}
+
+	if got := font.DecodeString([]byte{1, 2}); got != "XY" {
+		t.Errorf("Expected ToUnicode to take priority, got %q", got)
+	}
+}
+
+func TestNewType3Font_Errors(t *testing.T) {
+	dict := type3FontDict()
+	dict["Subtype"] = core.Name("Type1")
+	if _, err := NewType3Font(dict, mockResolver); err == nil {
+		t.Error("Expected error for non-Type3 font")
+	}
+
+	dict = type3FontDict()
+	dict["FontMatrix"] = core.Array{core.Int(1), core.Int(0)}
+	if _, err := NewType3Font(dict, mockResolver); err == nil {
+		t.Error("Expected error for malformed FontMatrix")
+	}
+}
+
+func TestGlyphNameToRune(t *testing.T) {
+	tests := []struct {
+		name string
+		want rune
+		ok   bool
+	}{
+		{"A", 'A', true},
+		{"space", ' ', true},
+		{"uni20AC", 0x20AC, true},
+		{"u1F600", 0x1F600, true},
+		{"a.sc", 'a', true},
+		{"uniD800", 0, false},
+		{"g42", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := GlyphNameToRune(tt.name)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("GlyphNameToRune(%q) = %q, %v; expected %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package tabula
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tsawler/tabula/format"
+	"github.com/tsawler/tabula/forms"
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/text"
+)
+
+// FormFields returns the fields of the document's interactive form, in the
+// order of the form's field tree, with their fully qualified names, types,
+// values and the page and rectangle of each widget. Page selection does not
+// apply, since a form belongs to the whole document.
+//
+// The values held in the datasets of an XFA (Adobe LiveCycle) form are
+// returned as text fields without widgets when the AcroForm fields hold no
+// values themselves, as is the case for dynamic XFA forms. Formats other
+// than PDF have no form fields.
+// This is a terminal operation that closes the underlying reader.
+//
+// Example:
+//
+//	fields, _, err := tabula.Open("application.pdf").FormFields()
+//	for _, f := range fields {
+//	    fmt.Printf("%s (%s) = %q\n", f.Name, f.Type, f.Value)
+//	}
+func (e *Extractor) FormFields() ([]*model.FormField, []Warning, error) {
+	if e.err != nil {
+		return nil, nil, e.err
+	}
+
+	if err := e.ensureReader(); err != nil {
+		return nil, nil, err
+	}
+	defer e.Close()
+
+	if e.format != format.PDF {
+		return nil, e.warnings, nil
+	}
+
+	fields, err := e.reader.FormFields()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !hasFormValues(fields) {
+		// XFA data is read on a best-effort basis
+		if xfaFields, err := e.reader.XFAFields(); err == nil {
+			fields = append(fields, xfaFields...)
+		}
+	}
+
+	result := make([]*model.FormField, 0, len(fields))
+	for _, f := range fields {
+		result = append(result, modelFormField(f))
+	}
+	return result, e.warnings, nil
+}
+
+// hasFormValues reports whether any of the fields holds a value
+func hasFormValues(fields []*forms.Field) bool {
+	for _, f := range fields {
+		if f.Value != "" || f.Checked || f.Signed {
+			return true
+		}
+	}
+	return false
+}
+
+// modelFormField converts a parsed form field
+func modelFormField(f *forms.Field) *model.FormField {
+	mf := &model.FormField{
+		Name:     f.Name,
+		Type:     model.FormFieldType(f.Type), // Declared in the same order
+		Value:    f.Value,
+		Values:   f.Values,
+		Checked:  f.Checked,
+		Signed:   f.Signed,
+		Options:  f.Options,
+		ReadOnly: f.ReadOnly(),
+		Required: f.Required(),
+		XFA:      f.XFA,
+	}
+	for _, w := range f.Widgets {
+		mf.Widgets = append(mf.Widgets, model.FormWidget{
+			Page: w.Page + 1,
+			BBox: model.BBox{X: w.Rect[0], Y: w.Rect[1], Width: w.Rect[2] - w.Rect[0], Height: w.Rect[3] - w.Rect[1]},
+		})
+	}
+	return mf
+}
+
+// pageFormFragments returns the fragments showing the values of the form
+// fields on a page, loading the form on first use. A form that cannot be
+// read adds nothing.
+func (e *Extractor) pageFormFragments(pageNum int) []text.TextFragment {
+	if e.formFragments == nil {
+		e.formFragments = make(map[int][]text.TextFragment)
+		if fields, err := e.reader.FormFields(); err == nil {
+			for _, f := range fields {
+				for _, w := range f.Widgets {
+					if frag, ok := widgetFragment(f, w); ok && w.Page >= 0 {
+						e.formFragments[w.Page] = append(e.formFragments[w.Page], frag)
+					}
+				}
+			}
+		}
+	}
+	return e.formFragments[pageNum]
+}
+
+// widgetFragment returns a text fragment showing a field's value inside one
+// of its widgets: the text of a text or choice field, and "[X]" or "[ ]" for
+// check boxes and radio buttons. Fields without a value, push buttons and
+// signatures have none.
+func widgetFragment(f *forms.Field, w forms.Widget) (text.TextFragment, bool) {
+	var value string
+	switch f.Type {
+	case forms.FieldText, forms.FieldChoice:
+		value = strings.TrimSpace(f.Value)
+	case forms.FieldCheckbox, forms.FieldRadio:
+		on := f.Checked
+		if w.State != "" {
+			on = w.State != "Off"
+		}
+		value = "[ ]"
+		if on {
+			value = "[X]"
+		}
+	}
+	if value == "" {
+		return text.TextFragment{}, false
+	}
+	// Multi-line text fields are read as a single line
+	value = strings.Join(strings.Fields(value), " ")
+
+	width, height := w.Rect[2]-w.Rect[0], w.Rect[3]-w.Rect[1]
+	size := f.FontSize
+	if size <= 0 {
+		// Auto-sized text fills the widget's height, within reason
+		size = height * 0.7
+		if size < 6 {
+			size = 6
+		} else if size > 12 {
+			size = 12
+		}
+	}
+
+	textWidth := float64(utf8.RuneCountInString(value)) * size * 0.5
+	if textWidth > width && width > 0 {
+		textWidth = width
+	}
+
+	return text.TextFragment{
+		Text:     value,
+		X:        w.Rect[0] + 2,
+		Y:        w.Rect[1] + (height-size)/2,
+		Width:    textWidth,
+		Height:   size,
+		FontName: "Helvetica",
+		FontSize: size,
+		Visible:  true,
+	}, true
+}
@@ -0,0 +1,85 @@
+package tabula
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+)
+
+// buildFormPDF assembles a one-page PDF with a filled-in text field, a
+// checked check box and an empty text field. The widgets do not name their
+// page, which is found from the page's /Annots.
+func buildFormPDF() []byte {
+	content := `BT /F1 12 Tf 72 700 Td (Name:) Tj ET
+BT /F1 12 Tf 72 650 Td (Subscribe:) Tj ET`
+	return buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R /AcroForm << /Fields [6 0 R 7 0 R 8 0 R] /DA (/Helv 0 Tf 0 g) >> >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 3 0 R >> >> /Annots [6 0 R 7 0 R 8 0 R] >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Annot /Subtype /Widget /FT /Tx /T (name) /V (Jane Doe) /DA (/Helv 12 Tf 0 g) /Rect [120 695 300 715] >>",
+		"<< /Type /Annot /Subtype /Widget /FT /Btn /T (subscribe) /V /Yes /AS /Yes /Ff 2 /Rect [140 646 152 658] /AP << /N << /Yes 9 0 R /Off 9 0 R >> >> >>",
+		"<< /Type /Annot /Subtype /Widget /FT /Tx /T (comments) /Rect [72 500 300 600] >>",
+		"<< /Length 0 >>\nstream\n\nendstream",
+	)
+}
+
+func TestFormFields(t *testing.T) {
+	fields, _, err := FromBytes(buildFormPDF(), "").FormFields()
+	if err != nil {
+		t.Fatalf("FormFields() error: %v", err)
+	}
+	if len(fields) != 3 {
+		t.Fatalf("got %d fields, want 3", len(fields))
+	}
+
+	name := fields[0]
+	if name.Name != "name" || name.Type != model.FormFieldText || name.Value != "Jane Doe" {
+		t.Errorf("field 0 = %+v, want text field name = Jane Doe", name)
+	}
+	if len(name.Widgets) != 1 || name.Widgets[0].Page != 1 || name.Widgets[0].BBox.X != 120 {
+		t.Errorf("field 0 widgets = %+v, want one widget on page 1", name.Widgets)
+	}
+
+	subscribe := fields[1]
+	if subscribe.Type != model.FormFieldCheckbox || !subscribe.Checked || !subscribe.Required {
+		t.Errorf("field 1 = %+v, want checked, required check box", subscribe)
+	}
+	if got := subscribe.Type.String(); got != "checkbox" {
+		t.Errorf("type string = %q, want checkbox", got)
+	}
+
+	if comments := fields[2]; comments.Value != "" {
+		t.Errorf("field 2 value = %q, want empty", comments.Value)
+	}
+}
+
+func TestFormFieldsNoForm(t *testing.T) {
+	fields, _, err := FromBytes(buildAnnotatedPDF(), "").FormFields()
+	if err != nil || len(fields) != 0 {
+		t.Errorf("FormFields() = %v, %v, want no fields", fields, err)
+	}
+}
+
+func TestInlineFormFields(t *testing.T) {
+	plain, _, err := FromBytes(buildFormPDF(), "").Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if strings.Contains(plain, "Jane Doe") {
+		t.Errorf("got field values without InlineFormFields():\n%s", plain)
+	}
+
+	inlined, _, err := FromBytes(buildFormPDF(), "").InlineFormFields().Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	for _, want := range []string{"Name: Jane Doe", "Subscribe: [X]"} {
+		if !strings.Contains(inlined, want) {
+			t.Errorf("inlined text is missing %q:\n%s", want, inlined)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Package forms reads the fields of interactive PDF forms.
+//
+// AcroForm fields (PDF 32000-1:2008, 12.7) form a tree rooted at the
+// catalog's /AcroForm /Fields array. Intermediate fields group their kids
+// under a partial name, and attributes such as the field type, value, flags
+// and default appearance are inherited down the tree. Terminal fields hold
+// a value and are shown on the page by one or more widget annotations.
+//
+// # Parsing
+//
+// [Parse] reads the terminal fields from the document catalog:
+//
+//	fields, err := forms.Parse(catalog, resolver, pageIndex, annotPage)
+//	for _, f := range fields {
+//	    fmt.Printf("%s (%s) = %q\n", f.Name, f.Type, f.Value)
+//	}
+//
+// Each [Field] carries its fully qualified name, e.g. "applicant.address.city",
+// its [FieldType], its value and options, and a [Widget] with the page and
+// rectangle of each widget annotation. Check boxes and radio groups report
+// their on state; choice fields their selected options; signature fields
+// whether they are signed.
+//
+// # XFA
+//
+// Forms designed with Adobe LiveCycle (XFA) keep their data in XML packets
+// under /AcroForm /XFA, often with only a placeholder AcroForm. [ParseXFA]
+// reads the values of the datasets packet as text fields named by their
+// path in the data tree. Layout is defined by the template packet, which is
+// not read, so XFA fields have no widgets.
+package forms
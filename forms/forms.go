@@ -0,0 +1,459 @@
+package forms
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/font"
+)
+
+// maxDepth limits how deep the field tree is followed
+const maxDepth = 64
+
+// Field flags (PDF 32000-1:2008, Tables 221, 226 and 228)
+const (
+	FlagReadOnly    = 1 << 0
+	FlagRequired    = 1 << 1
+	FlagNoExport    = 1 << 2
+	FlagRadio       = 1 << 15
+	FlagPushbutton  = 1 << 16
+	FlagCombo       = 1 << 17
+	FlagMultiSelect = 1 << 21
+)
+
+// Resolver resolves indirect references
+type Resolver func(core.IndirectRef) (core.Object, error)
+
+// PageIndex returns the 0-based index of the page with the given reference
+type PageIndex func(core.IndirectRef) (int, bool)
+
+// FieldType is the type of a form field
+type FieldType int
+
+const (
+	FieldUnknown FieldType = iota
+	FieldText
+	FieldCheckbox
+	FieldRadio
+	FieldPushbutton
+	FieldChoice
+	FieldSignature
+)
+
+// String returns the name of the field type
+func (t FieldType) String() string {
+	switch t {
+	case FieldText:
+		return "text"
+	case FieldCheckbox:
+		return "checkbox"
+	case FieldRadio:
+		return "radio"
+	case FieldPushbutton:
+		return "pushbutton"
+	case FieldChoice:
+		return "choice"
+	case FieldSignature:
+		return "signature"
+	default:
+		return "unknown"
+	}
+}
+
+// Field is a terminal form field: one with a value, shown by one or more
+// widget annotations
+type Field struct {
+	Name     string // Fully qualified name, e.g. "applicant.address.city"
+	Type     FieldType
+	Value    string   // Text, selected choice(s) joined by ", ", the on state of a check box or radio group, or the signer's name
+	Values   []string // Selected options of a choice field
+	Checked  bool     // Whether a check box or radio group is on
+	Signed   bool     // Whether a signature field holds a signature
+	Options  []string // Choice options, or the on states of a check box or radio group
+	Flags    int      // Field flags (/Ff)
+	FontSize float64  // Font size from the default appearance string, or 0 if auto
+	Widgets  []Widget
+	XFA      bool // Whether the field comes from XFA datasets rather than AcroForm
+}
+
+// ReadOnly reports whether the field is read-only
+func (f *Field) ReadOnly() bool { return f.Flags&FlagReadOnly != 0 }
+
+// Required reports whether the field must have a value
+func (f *Field) Required() bool { return f.Flags&FlagRequired != 0 }
+
+// Widget is a field's widget annotation: where the field appears
+type Widget struct {
+	Page  int        // 0-based page index, or -1 if unknown
+	Rect  [4]float64 // llx, lly, urx, ury
+	State string     // Appearance state (/AS) of a check box or radio button widget
+}
+
+// Parse reads the AcroForm fields of a document catalog. It returns nil and
+// no error if the document has no interactive form. Widget pages come from
+// the widgets' /P entries through pageIndex, or else from annotPage, which
+// maps a widget annotation reference to the page whose /Annots hold it.
+func Parse(catalog core.Dict, resolver Resolver, pageIndex, annotPage PageIndex) ([]*Field, error) {
+	p := &parser{resolver: resolver, pageIndex: pageIndex, annotPage: annotPage, visited: make(map[int]bool)}
+
+	acroForm, err := p.acroForm(catalog)
+	if acroForm == nil {
+		return nil, err
+	}
+
+	fields, ok := p.resolve(acroForm.Get("Fields")).(core.Array)
+	if !ok {
+		return nil, nil
+	}
+
+	var result []*Field
+	for _, fieldObj := range fields {
+		result = p.field(result, fieldObj, "", inherited{}, 0)
+	}
+	return result, nil
+}
+
+// parser holds the state for a single Parse call
+type parser struct {
+	resolver  Resolver
+	pageIndex PageIndex
+	annotPage PageIndex
+	visited   map[int]bool // Object numbers already parsed, to break cycles
+}
+
+// inherited holds the inheritable field attributes (PDF 32000-1:2008, 12.7.3.1)
+type inherited struct {
+	ft core.Name
+	v  core.Object
+	ff int
+	da string
+	op core.Object
+}
+
+// with returns the attributes inherited by the kids of dict
+func (in inherited) with(p *parser, dict core.Dict) inherited {
+	if ft, ok := p.resolve(dict.Get("FT")).(core.Name); ok {
+		in.ft = ft
+	}
+	if v := dict.Get("V"); v != nil {
+		in.v = v
+	}
+	if ff, ok := p.resolve(dict.Get("Ff")).(core.Int); ok {
+		in.ff = int(ff)
+	}
+	if da, ok := p.resolve(dict.Get("DA")).(core.String); ok {
+		in.da = string(da)
+	}
+	if opt := dict.Get("Opt"); opt != nil {
+		in.op = opt
+	}
+	return in
+}
+
+// acroForm returns the catalog's interactive form dictionary
+func (p *parser) acroForm(catalog core.Dict) (core.Dict, error) {
+	obj := catalog.Get("AcroForm")
+	if obj == nil {
+		return nil, nil
+	}
+	acroForm, ok := p.resolve(obj).(core.Dict)
+	if !ok {
+		return nil, fmt.Errorf("AcroForm is not a dictionary")
+	}
+	return acroForm, nil
+}
+
+// resolve follows an indirect reference, returning nil if it cannot be resolved
+func (p *parser) resolve(obj core.Object) core.Object {
+	if ref, ok := obj.(core.IndirectRef); ok && p.resolver != nil {
+		resolved, err := p.resolver(ref)
+		if err != nil {
+			return nil
+		}
+		return resolved
+	}
+	return obj
+}
+
+// text resolves and decodes a text string
+func (p *parser) text(obj core.Object) string {
+	if s, ok := p.resolve(obj).(core.String); ok {
+		return font.DecodeTextString([]byte(s))
+	}
+	return ""
+}
+
+// field parses a field dictionary and its descendants, appending the
+// terminal fields to result
+func (p *parser) field(result []*Field, obj core.Object, parentName string, in inherited, depth int) []*Field {
+	if depth > maxDepth {
+		return result
+	}
+	if ref, ok := obj.(core.IndirectRef); ok {
+		if p.visited[ref.Number] {
+			return result
+		}
+		p.visited[ref.Number] = true
+	}
+
+	dict, ok := p.resolve(obj).(core.Dict)
+	if !ok {
+		return result
+	}
+
+	name := parentName
+	if partial := p.text(dict.Get("T")); partial != "" {
+		if name != "" {
+			name += "."
+		}
+		name += partial
+	}
+	in = in.with(p, dict)
+
+	// Kids with a partial name are fields; the others are widgets
+	kids, _ := p.resolve(dict.Get("Kids")).(core.Array)
+	var widgets []core.Object
+	hasFieldKids := false
+	for _, kidObj := range kids {
+		kid, ok := p.resolve(kidObj).(core.Dict)
+		if !ok {
+			continue
+		}
+		if kid.Get("T") != nil {
+			hasFieldKids = true
+			result = p.field(result, kidObj, name, in, depth+1)
+		} else {
+			widgets = append(widgets, kidObj)
+		}
+	}
+	if hasFieldKids {
+		return result
+	}
+
+	// A terminal field with no widget kids is merged with its only widget
+	if len(widgets) == 0 {
+		widgets = []core.Object{obj}
+	}
+	return append(result, p.terminal(name, in, widgets))
+}
+
+// terminal builds a terminal field from its inherited attributes and widgets
+func (p *parser) terminal(name string, in inherited, widgetObjs []core.Object) *Field {
+	f := &Field{
+		Name:     name,
+		Type:     fieldType(in.ft, in.ff),
+		Flags:    in.ff,
+		FontSize: daFontSize(in.da),
+	}
+
+	for _, widgetObj := range widgetObjs {
+		if w, ok := p.widget(widgetObj); ok {
+			f.Widgets = append(f.Widgets, w)
+		}
+	}
+
+	switch f.Type {
+	case FieldText:
+		f.Value = p.text(in.v)
+	case FieldChoice:
+		f.Options = p.options(in.op)
+		switch v := p.resolve(in.v).(type) {
+		case core.String:
+			f.Values = []string{font.DecodeTextString([]byte(v))}
+		case core.Array:
+			for _, elem := range v {
+				if s := p.text(elem); s != "" {
+					f.Values = append(f.Values, s)
+				}
+			}
+		}
+		f.Value = strings.Join(f.Values, ", ")
+	case FieldCheckbox, FieldRadio:
+		p.buttonValue(f, in, widgetObjs)
+	case FieldSignature:
+		if sig, ok := p.resolve(in.v).(core.Dict); ok {
+			f.Signed = true
+			f.Value = p.text(sig.Get("Name"))
+		}
+	}
+
+	return f
+}
+
+// buttonValue sets the on state of a check box or radio group. The value
+// is the field's /V, or for fields without one, the first widget's /AS. An
+// /Opt array replaces the state names, which are then indexes, with the
+// export values.
+func (p *parser) buttonValue(f *Field, in inherited, widgetObjs []core.Object) {
+	state := ""
+	if v, ok := p.resolve(in.v).(core.Name); ok {
+		state = string(v)
+	} else {
+		for _, w := range f.Widgets {
+			if w.State != "" && w.State != "Off" {
+				state = w.State
+				break
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, widgetObj := range widgetObjs {
+		for _, onState := range p.onStates(widgetObj) {
+			if !seen[onState] {
+				seen[onState] = true
+				f.Options = append(f.Options, onState)
+			}
+		}
+	}
+
+	f.Checked = state != "" && state != "Off"
+	if !f.Checked {
+		return
+	}
+	f.Value = state
+
+	opts := p.options(in.op)
+	if i, err := strconv.Atoi(state); err == nil && i >= 0 && i < len(opts) {
+		f.Value = opts[i]
+	}
+}
+
+// onStates returns the names of a button widget's appearance states other
+// than Off
+func (p *parser) onStates(widgetObj core.Object) []string {
+	widget, ok := p.resolve(widgetObj).(core.Dict)
+	if !ok {
+		return nil
+	}
+	ap, ok := p.resolve(widget.Get("AP")).(core.Dict)
+	if !ok {
+		return nil
+	}
+	normal, ok := p.resolve(ap.Get("N")).(core.Dict)
+	if !ok {
+		return nil
+	}
+
+	var states []string
+	for name := range normal {
+		if name != "Off" {
+			states = append(states, name)
+		}
+	}
+	sort.Strings(states)
+	return states
+}
+
+// options reads an /Opt array, whose elements are either display strings
+// or [export display] pairs; the display string is used
+func (p *parser) options(obj core.Object) []string {
+	arr, ok := p.resolve(obj).(core.Array)
+	if !ok {
+		return nil
+	}
+
+	var opts []string
+	for _, elem := range arr {
+		switch v := p.resolve(elem).(type) {
+		case core.String:
+			opts = append(opts, font.DecodeTextString([]byte(v)))
+		case core.Array:
+			if len(v) == 2 {
+				opts = append(opts, p.text(v[1]))
+			}
+		}
+	}
+	return opts
+}
+
+// widget reads a widget annotation's page, rectangle and appearance state
+func (p *parser) widget(obj core.Object) (Widget, bool) {
+	dict, ok := p.resolve(obj).(core.Dict)
+	if !ok {
+		return Widget{}, false
+	}
+
+	rect, ok := p.resolve(dict.Get("Rect")).(core.Array)
+	if !ok || len(rect) != 4 {
+		return Widget{}, false
+	}
+	w := Widget{Page: -1}
+	for i, elem := range rect {
+		w.Rect[i] = number(p.resolve(elem))
+	}
+	if w.Rect[0] > w.Rect[2] {
+		w.Rect[0], w.Rect[2] = w.Rect[2], w.Rect[0]
+	}
+	if w.Rect[1] > w.Rect[3] {
+		w.Rect[1], w.Rect[3] = w.Rect[3], w.Rect[1]
+	}
+
+	if state, ok := p.resolve(dict.Get("AS")).(core.Name); ok {
+		w.State = string(state)
+	}
+
+	if pg, ok := dict.Get("P").(core.IndirectRef); ok && p.pageIndex != nil {
+		if idx, ok := p.pageIndex(pg); ok {
+			w.Page = idx
+		}
+	}
+	if ref, ok := obj.(core.IndirectRef); ok && w.Page < 0 && p.annotPage != nil {
+		if idx, ok := p.annotPage(ref); ok {
+			w.Page = idx
+		}
+	}
+
+	return w, true
+}
+
+// fieldType determines a field's type from /FT and its flags
+func fieldType(ft core.Name, flags int) FieldType {
+	switch ft {
+	case "Tx":
+		return FieldText
+	case "Ch":
+		return FieldChoice
+	case "Sig":
+		return FieldSignature
+	case "Btn":
+		switch {
+		case flags&FlagPushbutton != 0:
+			return FieldPushbutton
+		case flags&FlagRadio != 0:
+			return FieldRadio
+		default:
+			return FieldCheckbox
+		}
+	}
+	return FieldUnknown
+}
+
+// daFontSize returns the font size set by the Tf operator in a default
+// appearance string such as "/Helv 10 Tf 0 g", or 0 if there is none
+func daFontSize(da string) float64 {
+	tokens := strings.Fields(da)
+	for i := 2; i < len(tokens); i++ {
+		if tokens[i] == "Tf" {
+			size, err := strconv.ParseFloat(tokens[i-1], 64)
+			if err == nil && size > 0 {
+				return size
+			}
+		}
+	}
+	return 0
+}
+
+// number converts a numeric object to a float
+func number(obj core.Object) float64 {
+	switch v := obj.(type) {
+	case core.Int:
+		return float64(v)
+	case core.Real:
+		return float64(v)
+	}
+	return 0
+}
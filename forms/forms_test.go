@@ -0,0 +1,216 @@
+package forms
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// testDoc builds a resolver and page index over numbered objects; objects
+// 100 and 101 are pages 0 and 1
+func testDoc(objects map[int]core.Object) (Resolver, PageIndex) {
+	resolver := func(ref core.IndirectRef) (core.Object, error) {
+		if obj, ok := objects[ref.Number]; ok {
+			return obj, nil
+		}
+		return nil, fmt.Errorf("object %d not found", ref.Number)
+	}
+	pageIndex := func(ref core.IndirectRef) (int, bool) {
+		switch ref.Number {
+		case 100:
+			return 0, true
+		case 101:
+			return 1, true
+		}
+		return 0, false
+	}
+	return resolver, pageIndex
+}
+
+func rect(llx, lly, urx, ury int) core.Array {
+	return core.Array{core.Int(llx), core.Int(lly), core.Int(urx), core.Int(ury)}
+}
+
+func TestParseNoForm(t *testing.T) {
+	fields, err := Parse(core.Dict{"Type": core.Name("Catalog")}, nil, nil, nil)
+	if err != nil || fields != nil {
+		t.Errorf("Expected no fields and no error, got %v, %v", fields, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	page0 := core.IndirectRef{Number: 100}
+	page1 := core.IndirectRef{Number: 101}
+	onOff := func(on string) core.Dict {
+		return core.Dict{"N": core.Dict{on: core.Dict{}, "Off": core.Dict{}}}
+	}
+
+	objects := map[int]core.Object{
+		1: core.Dict{
+			"Fields": core.Array{core.IndirectRef{Number: 2}, core.IndirectRef{Number: 5}, core.IndirectRef{Number: 6}, core.IndirectRef{Number: 9}, core.IndirectRef{Number: 10}},
+			"DA":     core.String("/Helv 0 Tf 0 g"),
+		},
+		// "applicant" groups a text field, inherited type and flags
+		2: core.Dict{
+			"T":    core.String("applicant"),
+			"FT":   core.Name("Tx"),
+			"Ff":   core.Int(FlagRequired),
+			"Kids": core.Array{core.IndirectRef{Number: 3}, core.IndirectRef{Number: 4}},
+		},
+		3: core.Dict{"T": core.String("name"), "V": core.String("\xfe\xff\x00J\x00o"), "DA": core.String("/Helv 10 Tf 0 g"), "Subtype": core.Name("Widget"), "Rect": rect(300, 700, 100, 720), "P": page0},
+		4: core.Dict{"T": core.String("city"), "Subtype": core.Name("Widget"), "Rect": rect(100, 650, 300, 670)},
+		// A check box merged with its widget
+		5: core.Dict{"T": core.String("agree"), "FT": core.Name("Btn"), "V": core.Name("Yes"), "AS": core.Name("Yes"), "AP": onOff("Yes"), "Rect": rect(100, 600, 112, 612), "P": page0},
+		// A radio group with two widgets on page 1
+		6: core.Dict{"T": core.String("size"), "FT": core.Name("Btn"), "Ff": core.Int(FlagRadio), "V": core.Name("L"), "Kids": core.Array{core.IndirectRef{Number: 7}, core.IndirectRef{Number: 8}}},
+		7: core.Dict{"Subtype": core.Name("Widget"), "AS": core.Name("Off"), "AP": onOff("M"), "Rect": rect(100, 500, 112, 512), "P": page1},
+		8: core.Dict{"Subtype": core.Name("Widget"), "AS": core.Name("L"), "AP": onOff("L"), "Rect": rect(150, 500, 162, 512), "P": page1},
+		// A multiple-selection list box with [export display] options
+		9: core.Dict{
+			"T":   core.String("colors"),
+			"FT":  core.Name("Ch"),
+			"Ff":  core.Int(FlagMultiSelect | FlagReadOnly),
+			"Opt": core.Array{core.Array{core.String("r"), core.String("Red")}, core.String("Green"), core.String("Blue")},
+			"V":   core.Array{core.String("Red"), core.String("Blue")},
+		},
+		10: core.Dict{"T": core.String("sig"), "FT": core.Name("Sig"), "V": core.Dict{"Name": core.String("J. Smith")}},
+	}
+	resolver, pageIndex := testDoc(objects)
+	annotPage := func(ref core.IndirectRef) (int, bool) {
+		return 1, ref.Number == 4
+	}
+
+	fields, err := Parse(core.Dict{"AcroForm": core.IndirectRef{Number: 1}}, resolver, pageIndex, annotPage)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(fields) != 6 {
+		t.Fatalf("Expected 6 fields, got %d", len(fields))
+	}
+
+	name := fields[0]
+	if name.Name != "applicant.name" || name.Type != FieldText || name.Value != "Jo" || !name.Required() {
+		t.Errorf("Expected required text field applicant.name = Jo, got %+v", name)
+	}
+	if name.FontSize != 10 {
+		t.Errorf("Expected font size 10, got %v", name.FontSize)
+	}
+	if len(name.Widgets) != 1 || name.Widgets[0].Page != 0 || name.Widgets[0].Rect != [4]float64{100, 700, 300, 720} {
+		t.Errorf("Expected normalized widget on page 0, got %+v", name.Widgets)
+	}
+
+	if city := fields[1]; city.Name != "applicant.city" || city.Value != "" || city.Widgets[0].Page != 1 {
+		t.Errorf("Expected empty applicant.city on page 1 via annotPage, got %+v", city)
+	}
+
+	if agree := fields[2]; agree.Type != FieldCheckbox || !agree.Checked || agree.Value != "Yes" {
+		t.Errorf("Expected checked check box, got %+v", agree)
+	}
+
+	size := fields[3]
+	if size.Type != FieldRadio || size.Value != "L" || len(size.Widgets) != 2 {
+		t.Errorf("Expected radio group set to L with 2 widgets, got %+v", size)
+	}
+	if len(size.Options) != 2 || size.Options[0] != "M" || size.Options[1] != "L" {
+		t.Errorf("Expected on states [M L], got %v", size.Options)
+	}
+
+	colors := fields[4]
+	if colors.Type != FieldChoice || colors.Value != "Red, Blue" || !colors.ReadOnly() {
+		t.Errorf("Expected read-only choice Red, Blue, got %+v", colors)
+	}
+	if len(colors.Options) != 3 || colors.Options[0] != "Red" {
+		t.Errorf("Expected display options, got %v", colors.Options)
+	}
+
+	if sig := fields[5]; sig.Type != FieldSignature || !sig.Signed || sig.Value != "J. Smith" {
+		t.Errorf("Expected signature by J. Smith, got %+v", sig)
+	}
+}
+
+func TestParseCycle(t *testing.T) {
+	objects := map[int]core.Object{
+		1: core.Dict{"T": core.String("a"), "FT": core.Name("Tx"), "Kids": core.Array{core.IndirectRef{Number: 2}}},
+		2: core.Dict{"T": core.String("b"), "Kids": core.Array{core.IndirectRef{Number: 1}, core.IndirectRef{Number: 3}}},
+		3: core.Dict{"T": core.String("c"), "V": core.String("x")},
+	}
+	resolver, pageIndex := testDoc(objects)
+
+	catalog := core.Dict{"AcroForm": core.Dict{"Fields": core.Array{core.IndirectRef{Number: 1}}}}
+	fields, err := Parse(catalog, resolver, pageIndex, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(fields) != 1 || fields[0].Name != "a.b.c" || fields[0].Value != "x" {
+		t.Errorf("Expected only a.b.c = x, got %v", fields)
+	}
+}
+
+func TestDAFontSize(t *testing.T) {
+	tests := []struct {
+		da   string
+		want float64
+	}{
+		{"/Helv 10 Tf 0 g", 10},
+		{"0 g /TiRo 8.5 Tf", 8.5},
+		{"/Helv 0 Tf 0 g", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := daFontSize(tt.da); got != tt.want {
+			t.Errorf("daFontSize(%q): Expected %v, got %v", tt.da, tt.want, got)
+		}
+	}
+}
+
+func TestParseXFA(t *testing.T) {
+	datasets := `<xfa:datasets xmlns:xfa="http://www.xfa.org/schema/xfa-data/1.0/">
+<xfa:data>
+<form1>
+  <applicant><name>Jane Doe</name><email>jane@example.com</email></applicant>
+  <item><qty>2</qty></item>
+  <item><qty>5</qty></item>
+  <notes/>
+</form1>
+</xfa:data>
+</xfa:datasets>`
+	objects := map[int]core.Object{
+		2: &core.Stream{Dict: core.Dict{}, Data: []byte(`<template/>`)},
+		3: &core.Stream{Dict: core.Dict{}, Data: []byte(datasets)},
+	}
+	resolver, _ := testDoc(objects)
+
+	catalog := core.Dict{"AcroForm": core.Dict{
+		"Fields": core.Array{},
+		"XFA":    core.Array{core.String("template"), core.IndirectRef{Number: 2}, core.String("datasets"), core.IndirectRef{Number: 3}},
+	}}
+	fields, err := ParseXFA(catalog, resolver)
+	if err != nil {
+		t.Fatalf("ParseXFA failed: %v", err)
+	}
+
+	want := []struct{ name, value string }{
+		{"form1.applicant.name", "Jane Doe"},
+		{"form1.applicant.email", "jane@example.com"},
+		{"form1.item[0].qty", "2"},
+		{"form1.item[1].qty", "5"},
+		{"form1.notes", ""},
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("Expected %d fields, got %d", len(want), len(fields))
+	}
+	for i, w := range want {
+		if fields[i].Name != w.name || fields[i].Value != w.value || !fields[i].XFA {
+			t.Errorf("Field %d: expected %s = %q, got %+v", i, w.name, w.value, fields[i])
+		}
+	}
+}
+
+func TestParseXFANone(t *testing.T) {
+	catalog := core.Dict{"AcroForm": core.Dict{"Fields": core.Array{}}}
+	fields, err := ParseXFA(catalog, nil)
+	if err != nil || fields != nil {
+		t.Errorf("Expected no fields and no error, got %v, %v", fields, err)
+	}
+}
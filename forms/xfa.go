@@ -0,0 +1,170 @@
+package forms
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// maxXFAFields limits how many fields are read from XFA datasets
+const maxXFAFields = 10000
+
+// ParseXFA reads the field values of an XFA (Adobe LiveCycle) form from the
+// datasets packet of the catalog's /AcroForm /XFA entry. It returns nil and
+// no error if the document has no XFA form.
+//
+// Each leaf element under xfa:data becomes a text field named by its path,
+// e.g. "form1.applicant.name"; repeated siblings are numbered as in XFA
+// scripting, e.g. "form1.item[1].qty". The template packet is not read, so
+// the fields have no type beyond text and no widgets.
+func ParseXFA(catalog core.Dict, resolver Resolver) ([]*Field, error) {
+	p := &parser{resolver: resolver}
+
+	acroForm, err := p.acroForm(catalog)
+	if acroForm == nil {
+		return nil, err
+	}
+
+	data, err := p.xfaDatasets(acroForm.Get("XFA"))
+	if data == nil {
+		return nil, err
+	}
+
+	root, err := parseDatasets(data)
+	if err != nil {
+		return nil, fmt.Errorf("XFA datasets: %w", err)
+	}
+	if root == nil {
+		return nil, nil
+	}
+
+	var result []*Field
+	root.collect(&result, "")
+	return result, nil
+}
+
+// xfaDatasets returns the decoded XML holding the datasets packet. The /XFA
+// entry is either a single stream with the whole XDP document or an array
+// of packet names and streams.
+func (p *parser) xfaDatasets(obj core.Object) ([]byte, error) {
+	switch v := p.resolve(obj).(type) {
+	case *core.Stream:
+		return v.Decode()
+	case core.Array:
+		for i := 0; i+1 < len(v); i += 2 {
+			if name, ok := p.resolve(v[i]).(core.String); !ok || string(name) != "datasets" {
+				continue
+			}
+			stream, ok := p.resolve(v[i+1]).(*core.Stream)
+			if !ok {
+				return nil, fmt.Errorf("XFA datasets packet is not a stream")
+			}
+			return stream.Decode()
+		}
+	}
+	return nil, nil
+}
+
+// xfaNode is an element of the XFA data tree
+type xfaNode struct {
+	name     string
+	text     strings.Builder
+	children []*xfaNode
+}
+
+// parseDatasets parses the xfa:data element of a datasets packet into a
+// tree, returning nil if there is none
+func parseDatasets(data []byte) (*xfaNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	var (
+		root  *xfaNode
+		stack []*xfaNode
+		names []string // Local names of the open elements
+		count int
+	)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := ""
+			if len(names) > 0 {
+				parent = names[len(names)-1]
+			}
+			names = append(names, t.Name.Local)
+
+			switch {
+			case len(stack) > 0:
+				count++
+				if count > maxXFAFields {
+					return root, nil
+				}
+				node := &xfaNode{name: t.Name.Local}
+				top := stack[len(stack)-1]
+				top.children = append(top.children, node)
+				stack = append(stack, node)
+			case root == nil && t.Name.Local == "data" && parent == "datasets":
+				root = &xfaNode{}
+				stack = append(stack, root)
+			}
+		case xml.EndElement:
+			if len(names) > 0 {
+				names = names[:len(names)-1]
+			}
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+				if len(stack) == 0 {
+					return root, nil
+				}
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+	return root, nil
+}
+
+// collect appends a field for each leaf below n
+func (n *xfaNode) collect(result *[]*Field, path string) {
+	counts := make(map[string]int)
+	for _, child := range n.children {
+		counts[child.name]++
+	}
+
+	seen := make(map[string]int)
+	for _, child := range n.children {
+		name := child.name
+		if counts[name] > 1 {
+			name = fmt.Sprintf("%s[%d]", name, seen[child.name])
+			seen[child.name]++
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		if len(child.children) > 0 {
+			child.collect(result, name)
+			continue
+		}
+		*result = append(*result, &Field{
+			Name:  name,
+			Type:  FieldText,
+			Value: strings.TrimSpace(child.text.String()),
+			XFA:   true,
+		})
+	}
+}
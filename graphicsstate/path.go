@@ -291,6 +291,12 @@ func (pe *PathExtractor) EndPath() {
 	pe.currentPath.Clear()
 }
 
+// CurrentPath returns the path under construction, in user space. Painting
+// operators clear it, so it must be read before they are called.
+func (pe *PathExtractor) CurrentPath() *Path {
+	return pe.currentPath
+}
+
 // extractLinesFromPath extracts lines and rectangles from the current path
 func (pe *PathExtractor) extractLinesFromPath(stroked, filled bool) {
 	if pe.currentPath.IsEmpty() {
@@ -412,6 +412,22 @@ func TestPathExtractor_EndPath(t *testing.T) {
 	}
 }
 
+func TestPathExtractor_CurrentPath(t *testing.T) {
+	gs := NewGraphicsState()
+	pe := NewPathExtractor(gs)
+
+	pe.MoveTo(0, 0)
+	pe.LineTo(100, 0)
+	if n := len(pe.CurrentPath().Segments); n != 2 {
+		t.Errorf("Expected 2 segments, got %d", n)
+	}
+
+	pe.Fill()
+	if !pe.CurrentPath().IsEmpty() {
+		t.Error("Expected an empty path after Fill")
+	}
+}
+
 func TestPathExtractor_GetHorizontalLines(t *testing.T) {
 	gs := NewGraphicsState()
 	pe := NewPathExtractor(gs)
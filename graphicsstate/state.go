@@ -2,6 +2,7 @@ package graphicsstate
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/tsawler/tabula/model"
 )
@@ -23,6 +24,15 @@ type GraphicsState struct {
 	// Color (simplified - just RGB for now)
 	StrokeColor [3]float64
 	FillColor   [3]float64
+
+	// Clipping region, as the device-space bounding box of the clipping
+	// paths set with W and W*; HasClip is false while nothing is clipped
+	ClipBox model.BBox
+	HasClip bool
+
+	// Current path, for clipping; not part of the saved state
+	pathPoints  []model.Point
+	clipPending bool
 }
 
 // TextState represents text-specific state
@@ -75,6 +85,8 @@ func (gs *GraphicsState) Clone() *GraphicsState {
 		LineWidth:   gs.LineWidth,
 		StrokeColor: gs.StrokeColor,
 		FillColor:   gs.FillColor,
+		ClipBox:     gs.ClipBox,
+		HasClip:     gs.HasClip,
 		Text:        gs.Text,
 	}
 	return clone
@@ -100,6 +112,8 @@ func (gs *GraphicsState) Restore() error {
 	gs.LineWidth = saved.LineWidth
 	gs.StrokeColor = saved.StrokeColor
 	gs.FillColor = saved.FillColor
+	gs.ClipBox = saved.ClipBox
+	gs.HasClip = saved.HasClip
 	gs.Text = saved.Text
 
 	return nil
@@ -125,6 +139,93 @@ func (gs *GraphicsState) SetFillColorRGB(r, g, b float64) {
 	gs.FillColor = [3]float64{r, g, b}
 }
 
+// SetStrokeColor sets the stroke color from gray, RGB or CMYK components
+// (G, RG, K, SC and SCN operators); other component counts are ignored
+func (gs *GraphicsState) SetStrokeColor(components ...float64) {
+	if c, ok := colorToRGB(components); ok {
+		gs.StrokeColor = c
+	}
+}
+
+// SetFillColor sets the fill color from gray, RGB or CMYK components
+// (g, rg, k, sc and scn operators); other component counts are ignored
+func (gs *GraphicsState) SetFillColor(components ...float64) {
+	if c, ok := colorToRGB(components); ok {
+		gs.FillColor = c
+	}
+}
+
+// colorToRGB converts gray, RGB or CMYK components to RGB
+func colorToRGB(components []float64) ([3]float64, bool) {
+	switch len(components) {
+	case 1:
+		return [3]float64{components[0], components[0], components[0]}, true
+	case 3:
+		return [3]float64{components[0], components[1], components[2]}, true
+	case 4:
+		r, g, b := cmykToRGB(components[0], components[1], components[2], components[3])
+		return [3]float64{r, g, b}, true
+	}
+	return [3]float64{}, false
+}
+
+// AddPathPoint adds a point of the path under construction, in user space
+// (m, l, c, v, y and re operators). Only the path's bounds are kept, so
+// curve control points may be added as well.
+func (gs *GraphicsState) AddPathPoint(x, y float64) {
+	gs.pathPoints = append(gs.pathPoints, gs.CTM.Transform(model.Point{X: x, Y: y}))
+}
+
+// PathBounds returns the device-space bounding box of the current path
+func (gs *GraphicsState) PathBounds() (model.BBox, bool) {
+	if len(gs.pathPoints) == 0 {
+		return model.BBox{}, false
+	}
+	return boundingBoxFromPoints(gs.pathPoints), true
+}
+
+// ClipPath marks the current path as a clipping path, which takes effect
+// when the path is painted or ended (W and W* operators)
+func (gs *GraphicsState) ClipPath() {
+	gs.clipPending = true
+}
+
+// EndPath ends the current path after it is painted (S, f, B, n and related
+// operators), intersecting the clipping region with it if it was marked as
+// a clipping path
+func (gs *GraphicsState) EndPath() {
+	if gs.clipPending {
+		bounds, _ := gs.PathBounds()
+		gs.Clip(bounds)
+	}
+	gs.pathPoints = gs.pathPoints[:0]
+	gs.clipPending = false
+}
+
+// Clip intersects the clipping region with a device-space box
+func (gs *GraphicsState) Clip(box model.BBox) {
+	if !gs.HasClip {
+		gs.ClipBox, gs.HasClip = box, true
+		return
+	}
+
+	x0 := math.Max(gs.ClipBox.X, box.X)
+	y0 := math.Max(gs.ClipBox.Y, box.Y)
+	x1 := math.Min(gs.ClipBox.X+gs.ClipBox.Width, box.X+box.Width)
+	y1 := math.Min(gs.ClipBox.Y+gs.ClipBox.Height, box.Y+box.Height)
+	gs.ClipBox = model.BBox{X: x0, Y: y0, Width: math.Max(x1-x0, 0), Height: math.Max(y1-y0, 0)}
+}
+
+// InClip reports whether a device-space point lies inside the clipping
+// region, allowing tolerance points of slack
+func (gs *GraphicsState) InClip(x, y, tolerance float64) bool {
+	if !gs.HasClip {
+		return true
+	}
+	return x >= gs.ClipBox.X-tolerance && x <= gs.ClipBox.X+gs.ClipBox.Width+tolerance &&
+		y >= gs.ClipBox.Y-tolerance && y <= gs.ClipBox.Y+gs.ClipBox.Height+tolerance
+}
+
 // SetFont sets the current font (Tf operator)
 func (gs *GraphicsState) SetFont(name string, size float64) {
 	gs.Text.FontName = name
@@ -469,3 +469,65 @@ func TestComplexTextFlow(t *testing.T) {
 		t.Errorf("expected Y position 706, got %f", gs.Text.TextMatrix[5])
 	}
 }
+
+// TestSetColorComponents tests gray, RGB and CMYK color components
+func TestSetColorComponents(t *testing.T) {
+	gs := NewGraphicsState()
+
+	gs.SetFillColor(0.5)
+	if gs.FillColor != [3]float64{0.5, 0.5, 0.5} {
+		t.Errorf("expected gray fill, got %v", gs.FillColor)
+	}
+
+	gs.SetFillColor(0, 0, 0, 0)
+	if gs.FillColor != [3]float64{1, 1, 1} {
+		t.Errorf("expected white from CMYK, got %v", gs.FillColor)
+	}
+
+	gs.SetFillColor(1, 2)
+	if gs.FillColor != [3]float64{1, 1, 1} {
+		t.Errorf("expected two components to be ignored, got %v", gs.FillColor)
+	}
+
+	gs.SetStrokeColor(1, 0, 0)
+	if gs.StrokeColor != [3]float64{1, 0, 0} {
+		t.Errorf("expected red stroke, got %v", gs.StrokeColor)
+	}
+}
+
+// TestClipPath tests W n clipping, intersection and q/Q
+func TestClipPath(t *testing.T) {
+	gs := NewGraphicsState()
+	if !gs.InClip(-1000, -1000, 0) {
+		t.Error("expected everything inside before clipping")
+	}
+
+	gs.Save()
+	gs.Transform(model.Translate(100, 100))
+	gs.AddPathPoint(0, 0)
+	gs.AddPathPoint(200, 100)
+	gs.EndPath() // Painted, not clipped
+	if gs.HasClip {
+		t.Fatal("expected no clip without W")
+	}
+
+	gs.AddPathPoint(0, 0)
+	gs.AddPathPoint(200, 100)
+	gs.ClipPath()
+	gs.EndPath()
+	if !gs.InClip(150, 150, 0) || gs.InClip(50, 150, 0) || !gs.InClip(99.5, 150, 1) {
+		t.Errorf("unexpected clip region %+v", gs.ClipBox)
+	}
+
+	gs.Clip(model.BBox{X: 250, Y: 0, Width: 100, Height: 500})
+	if gs.InClip(150, 150, 0) || !gs.InClip(275, 150, 0) {
+		t.Errorf("expected intersected clip region, got %+v", gs.ClipBox)
+	}
+
+	if err := gs.Restore(); err != nil {
+		t.Fatal(err)
+	}
+	if gs.HasClip {
+		t.Error("expected Q to restore the unclipped state")
+	}
+}
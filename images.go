@@ -11,7 +11,8 @@ import (
 // placement in PDF user-space points (origin bottom-left).
 type PlacedImage struct {
 	Page                  int     // 1-based page number
-	Name                  string  // XObject name (e.g. "Im1")
+	Name                  string  // XObject name (e.g. "Im1"); empty for inline images
+	Inline                bool    // drawn inline in the content stream (BI/ID/EI)
 	PixelWidth            int     // intrinsic image width in pixels
 	PixelHeight           int     // intrinsic image height in pixels
 	ColorSpace            string  // base color-space name (DeviceRGB, DeviceGray, ...)
@@ -39,14 +40,15 @@ func (p PlacedImage) Coverage() float64 {
 	return c
 }
 
-// Images reports every raster image XObject drawn on the configured pages,
+// Images reports every raster image drawn on the configured pages,
 // together with the bounding box it occupies on the page (in points). This lets
 // callers compute how much of a page each image covers (via Coverage) to tell a
 // full-page scan from a discrete in-page figure.
 //
 // Placement is recovered by walking each page's content stream and tracking the
 // current transformation matrix; images nested inside Form XObjects are included
-// with the form's matrix composed in. Inline images (BI/ID/EI) are not reported.
+// with the form's matrix composed in. Inline images (BI/ID/EI), which scanners
+// and fax software often use for page scans, are included with Inline set.
 //
 // Results are ordered by page, then by draw order within a page. For non-PDF
 // formats this returns (nil, nil). This is a terminal operation that closes the
@@ -80,6 +82,9 @@ func (e *Extractor) Images() ([]PlacedImage, error) {
 
 	var result []PlacedImage
 	for _, pageNum := range pageIndices {
+		if err := e.ctxErr(); err != nil {
+			return nil, err
+		}
 		page, err := e.reader.GetPage(pageNum)
 		if err != nil {
 			return nil, fmt.Errorf("page %d: %w", pageNum+1, err)
@@ -97,6 +102,7 @@ func (e *Extractor) Images() ([]PlacedImage, error) {
 			result = append(result, PlacedImage{
 				Page:        pageNum + 1,
 				Name:        p.Name,
+				Inline:      p.Inline,
 				PixelWidth:  p.PixelWidth,
 				PixelHeight: p.PixelHeight,
 				ColorSpace:  p.ColorSpace,
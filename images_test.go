@@ -1,6 +1,7 @@
 package tabula
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -143,3 +144,48 @@ func assertPlausibleBBox(t *testing.T, file string, img PlacedImage) {
 			file, img.Page, img.Name, img.X, img.Y, img.Width, img.Height, img.PageWidth, img.PageHeight)
 	}
 }
+
+// buildInlineImagePDF assembles a one-page PDF whose only content is a
+// 4x2 grayscale inline image scaled to cover the page, as fax-to-PDF tools
+// emit page scans.
+func buildInlineImagePDF() []byte {
+	content := "q 612 0 0 792 0 0 cm BI /W 4 /H 2 /BPC 8 /CS /G /F /AHx ID 00FF00FF FF00FF00> EI Q"
+	return buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	)
+}
+
+func TestImagesInline(t *testing.T) {
+	images, err := FromBytes(buildInlineImagePDF(), "").Images()
+	if err != nil {
+		t.Fatalf("Images() error: %v", err)
+	}
+	if len(images) != 1 {
+		t.Fatalf("got %d images, want 1", len(images))
+	}
+	img := images[0]
+	if !img.Inline || img.PixelWidth != 4 || img.PixelHeight != 2 || img.ColorSpace != "DeviceGray" {
+		t.Errorf("image = %+v, want 4x2 DeviceGray inline image", img)
+	}
+	if cov := img.Coverage(); cov < 0.99 {
+		t.Errorf("coverage = %.3f, want full page", cov)
+	}
+}
+
+func TestPrepareOCRImagesInline(t *testing.T) {
+	ext := FromBytes(buildInlineImagePDF(), "")
+	defer ext.Close()
+	if err := ext.ensureReader(); err != nil {
+		t.Fatalf("ensureReader() error: %v", err)
+	}
+	page, err := ext.reader.GetPage(0)
+	if err != nil {
+		t.Fatalf("GetPage() error: %v", err)
+	}
+	if prepared := ext.prepareOCRImages(page, 1); len(prepared) != 1 || len(prepared[0].png) == 0 {
+		t.Errorf("got %d prepared images, want the inline image", len(prepared))
+	}
+}
@@ -15,6 +15,20 @@
 //   - 2: TIFF Predictor 2
 //   - 10-15: PNG predictors (None, Sub, Up, Average, Paeth)
 //
+// LZWDecode:
+//
+//	decoded, err := filters.LZWDecode(data, params)
+//
+// Decodes LZW-compressed data as written by early PDF producers. The
+// EarlyChange parameter (default 1) controls when the code width grows, and
+// the same predictors as FlateDecode are supported.
+//
+// RunLengthDecode:
+//
+//	decoded, err := filters.RunLengthDecode(data)
+//
+// Decodes byte-oriented run-length encoded data.
+//
 // ASCIIHexDecode:
 //
 //	decoded, err := filters.ASCIIHexDecode(data)
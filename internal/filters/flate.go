@@ -21,20 +21,29 @@ func FlateDecode(data []byte, params Params) ([]byte, error) {
 		return nil, fmt.Errorf("zlib decompression failed: %w", err)
 	}
 
-	// Apply predictor if specified
-	if params != nil {
-		if predictorObj, ok := params["Predictor"]; ok && predictorObj != nil {
-			predictor := getIntParam(params, "Predictor", 1)
-			if predictor != 1 {
-				decompressed, err = applyPredictor(decompressed, predictor, params)
-				if err != nil {
-					return nil, fmt.Errorf("predictor failed: %w", err)
-				}
+	return applyPredictorParams(decompressed, params)
+}
+
+// applyPredictorParams applies the predictor named by the Predictor entry in
+// params, if any. It is shared by the filters that accept predictor
+// parameters (FlateDecode and LZWDecode).
+func applyPredictorParams(data []byte, params Params) ([]byte, error) {
+	if params == nil {
+		return data, nil
+	}
+
+	if predictorObj, ok := params["Predictor"]; ok && predictorObj != nil {
+		predictor := getIntParam(params, "Predictor", 1)
+		if predictor != 1 {
+			decoded, err := applyPredictor(data, predictor, params)
+			if err != nil {
+				return nil, fmt.Errorf("predictor failed: %w", err)
 			}
+			return decoded, nil
 		}
 	}
 
-	return decompressed, nil
+	return data, nil
 }
 
 // zlibDecompress decompresses zlib-compressed data using the standard library.
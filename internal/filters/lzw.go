@@ -0,0 +1,137 @@
+package filters
+
+import (
+	"fmt"
+)
+
+// LZW code values reserved by the PDF specification (ISO 32000-1, 7.4.4).
+const (
+	lzwClearTable = 256
+	lzwEOD        = 257
+	lzwFirstCode  = 258
+	lzwMaxCodeLen = 12
+)
+
+// LZWDecode decompresses LZW (Lempel-Ziv-Welch) compressed data.
+// This filter is found mostly in PDFs written by early Acrobat versions,
+// before FlateDecode became the norm. Codes are read MSB-first with a
+// width that grows from 9 to 12 bits.
+//
+// Parameters from the PDF decode parameters dictionary:
+//   - EarlyChange: 1 (default) widens the code one entry early, 0 widens
+//     it exactly when the table fills, as some TIFF-derived encoders do
+//   - Predictor, Columns, Colors, BitsPerComponent: as for FlateDecode
+func LZWDecode(data []byte, params Params) ([]byte, error) {
+	earlyChange := getIntParam(params, "EarlyChange", 1)
+	if earlyChange != 0 && earlyChange != 1 {
+		return nil, fmt.Errorf("invalid EarlyChange value: %d", earlyChange)
+	}
+
+	decompressed, err := lzwDecompress(data, earlyChange)
+	if err != nil {
+		return nil, fmt.Errorf("lzw decompression failed: %w", err)
+	}
+
+	return applyPredictorParams(decompressed, params)
+}
+
+// lzwDecompress expands an LZW code stream. Decoding stops at the EOD code
+// or when the input runs out, matching how readers tolerate truncated
+// streams from older producers.
+func lzwDecompress(data []byte, earlyChange int) ([]byte, error) {
+	// Each table entry is stored as a prefix code plus a final byte, so the
+	// table never holds more than 4096 small records.
+	var (
+		prefix [1 << lzwMaxCodeLen]int
+		suffix [1 << lzwMaxCodeLen]byte
+		first  [1 << lzwMaxCodeLen]byte
+		length [1 << lzwMaxCodeLen]int
+	)
+	for i := 0; i < 256; i++ {
+		prefix[i] = -1
+		suffix[i] = byte(i)
+		first[i] = byte(i)
+		length[i] = 1
+	}
+
+	out := make([]byte, 0, len(data)*3)
+	next := lzwFirstCode
+	codeLen := 9
+	prev := -1
+
+	var bitBuf uint32
+	var bitCount uint
+	pos := 0
+
+	for {
+		for bitCount < uint(codeLen) && pos < len(data) {
+			bitBuf = bitBuf<<8 | uint32(data[pos])
+			bitCount += 8
+			pos++
+		}
+		if bitCount < uint(codeLen) {
+			break
+		}
+		code := int(bitBuf>>(bitCount-uint(codeLen))) & (1<<uint(codeLen) - 1)
+		bitCount -= uint(codeLen)
+
+		if code == lzwEOD {
+			break
+		}
+		if code == lzwClearTable {
+			next = lzwFirstCode
+			codeLen = 9
+			prev = -1
+			continue
+		}
+
+		switch {
+		case code < next:
+			// Known code: emit its string and, if there was a previous
+			// code, add previous string + first byte of this one.
+			out = appendLZWString(out, code, prefix[:], suffix[:], length[:])
+			if prev >= 0 && next < 1<<lzwMaxCodeLen {
+				prefix[next] = prev
+				suffix[next] = first[code]
+				first[next] = first[prev]
+				length[next] = length[prev] + 1
+				next++
+			}
+		case code == next && prev >= 0:
+			// KwKwK case: the code being defined is the one just read.
+			if next >= 1<<lzwMaxCodeLen {
+				return nil, fmt.Errorf("code table overflow")
+			}
+			prefix[next] = prev
+			suffix[next] = first[prev]
+			first[next] = first[prev]
+			length[next] = length[prev] + 1
+			next++
+			out = appendLZWString(out, code, prefix[:], suffix[:], length[:])
+		default:
+			return nil, fmt.Errorf("invalid code %d (next %d)", code, next)
+		}
+		prev = code
+
+		if next+earlyChange >= 1<<uint(codeLen) && codeLen < lzwMaxCodeLen {
+			codeLen++
+		}
+	}
+
+	return out, nil
+}
+
+// appendLZWString appends the byte string for code to out by walking the
+// prefix chain backwards into a pre-sized tail.
+func appendLZWString(out []byte, code int, prefix []int, suffix []byte, length []int) []byte {
+	n := length[code]
+	start := len(out)
+	for i := 0; i < n; i++ {
+		out = append(out, 0)
+	}
+	for i := start + n - 1; i >= start; i-- {
+		out[i] = suffix[code]
+		code = prefix[code]
+	}
+	return out
+}
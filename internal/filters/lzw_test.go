@@ -0,0 +1,168 @@
+package filters
+
+import (
+	"bytes"
+	"compress/lzw"
+	"testing"
+)
+
+// lzwCompress is a minimal LZW encoder for testing. It emits a ClearTable
+// code first and EOD last, growing the code width according to earlyChange.
+func lzwCompress(data []byte, earlyChange int) []byte {
+	var out []byte
+	var bitBuf uint32
+	var bitCount uint
+	codeLen := 9
+
+	write := func(code int) {
+		bitBuf = bitBuf<<uint(codeLen) | uint32(code)
+		bitCount += uint(codeLen)
+		for bitCount >= 8 {
+			out = append(out, byte(bitBuf>>(bitCount-8)))
+			bitCount -= 8
+		}
+	}
+
+	table := make(map[string]int)
+	next := lzwFirstCode
+	write(lzwClearTable)
+
+	var cur []byte
+	for _, b := range data {
+		candidate := append(append([]byte{}, cur...), b)
+		if len(candidate) == 1 {
+			cur = candidate
+			continue
+		}
+		if _, ok := table[string(candidate)]; ok {
+			cur = candidate
+			continue
+		}
+		if len(cur) == 1 {
+			write(int(cur[0]))
+		} else {
+			write(table[string(cur)])
+		}
+		if next < 1<<lzwMaxCodeLen {
+			table[string(candidate)] = next
+			next++
+		}
+		// The decoder adds each entry one code later than the encoder,
+		// so the encoder widens when the table is one entry larger.
+		if next-1+earlyChange >= 1<<uint(codeLen) && codeLen < lzwMaxCodeLen {
+			codeLen++
+		}
+		cur = []byte{b}
+	}
+	if len(cur) == 1 {
+		write(int(cur[0]))
+	} else if len(cur) > 1 {
+		write(table[string(cur)])
+	}
+	write(lzwEOD)
+	if bitCount > 0 {
+		out = append(out, byte(bitBuf<<(8-bitCount)))
+	}
+	return out
+}
+
+// TestLZWDecodeSpecExample tests the example from the PDF specification
+func TestLZWDecodeSpecExample(t *testing.T) {
+	// ISO 32000-1, 7.4.4.2: "-----A---B" encodes to these bytes
+	encoded := []byte{0x80, 0x0B, 0x60, 0x50, 0x22, 0x0C, 0x0C, 0x85, 0x01}
+
+	decoded, err := LZWDecode(encoded, nil)
+	if err != nil {
+		t.Fatalf("LZWDecode failed: %v", err)
+	}
+
+	expected := []byte("-----A---B")
+	if !bytes.Equal(decoded, expected) {
+		t.Errorf("decoded data doesn't match\ngot:  %q\nwant: %q", decoded, expected)
+	}
+}
+
+// TestLZWDecodeRoundTrip tests decoding of long input that exercises every
+// code width, for both EarlyChange settings
+func TestLZWDecodeRoundTrip(t *testing.T) {
+	var original []byte
+	for i := 0; i < 20000; i++ {
+		original = append(original, byte((i*7)%251), byte(i%13))
+	}
+
+	for _, earlyChange := range []int{0, 1} {
+		encoded := lzwCompress(original, earlyChange)
+
+		decoded, err := LZWDecode(encoded, Params{"EarlyChange": earlyChange})
+		if err != nil {
+			t.Fatalf("EarlyChange=%d: LZWDecode failed: %v", earlyChange, err)
+		}
+
+		if !bytes.Equal(decoded, original) {
+			t.Errorf("EarlyChange=%d: decoded data doesn't match original (got %d bytes, want %d)",
+				earlyChange, len(decoded), len(original))
+		}
+	}
+}
+
+// TestLZWDecodeNoEarlyChange tests EarlyChange=0 against the standard
+// library encoder, which widens codes without the early change
+func TestLZWDecodeNoEarlyChange(t *testing.T) {
+	original := bytes.Repeat([]byte("The quick brown fox jumps over the lazy dog. 0123456789\n"), 400)
+
+	var buf bytes.Buffer
+	w := lzw.NewWriter(&buf, lzw.MSB, 8)
+	w.Write(original)
+	w.Close()
+
+	decoded, err := LZWDecode(buf.Bytes(), Params{"EarlyChange": 0})
+	if err != nil {
+		t.Fatalf("LZWDecode failed: %v", err)
+	}
+
+	if !bytes.Equal(decoded, original) {
+		t.Errorf("decoded data doesn't match original (got %d bytes, want %d)", len(decoded), len(original))
+	}
+}
+
+// TestLZWDecodeWithPNGPredictor tests LZW combined with a PNG predictor
+func TestLZWDecodeWithPNGPredictor(t *testing.T) {
+	// 2 rows, 3 columns, Sub predictor on each row
+	data := []byte{
+		1, 10, 5, 5,
+		1, 20, 1, 1,
+	}
+
+	params := Params{
+		"Predictor": 11,
+		"Columns":   3,
+	}
+
+	decoded, err := LZWDecode(lzwCompress(data, 1), params)
+	if err != nil {
+		t.Fatalf("LZWDecode failed: %v", err)
+	}
+
+	expected := []byte{10, 15, 20, 20, 21, 22}
+	if !bytes.Equal(decoded, expected) {
+		t.Errorf("decoded data doesn't match\ngot:  %v\nwant: %v", decoded, expected)
+	}
+}
+
+// TestLZWDecodeInvalidCode tests that an out-of-range code is rejected
+func TestLZWDecodeInvalidCode(t *testing.T) {
+	// 9-bit code 300 straight after ClearTable is not yet defined
+	var bits uint32 = lzwClearTable<<9 | 300
+	encoded := []byte{byte(bits >> 10), byte(bits >> 2), byte(bits << 6)}
+
+	if _, err := LZWDecode(encoded, nil); err == nil {
+		t.Error("expected error for undefined code")
+	}
+}
+
+// TestLZWDecodeInvalidEarlyChange tests rejection of bad EarlyChange values
+func TestLZWDecodeInvalidEarlyChange(t *testing.T) {
+	if _, err := LZWDecode([]byte{0x80}, Params{"EarlyChange": 2}); err == nil {
+		t.Error("expected error for EarlyChange=2")
+	}
+}
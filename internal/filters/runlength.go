@@ -0,0 +1,46 @@
+package filters
+
+import "fmt"
+
+// RunLengthDecode decompresses data encoded with the PDF RunLengthDecode
+// filter, a byte-oriented scheme similar to PackBits.
+//
+// Each run starts with a length byte: 0-127 means copy the next length+1
+// bytes literally, 129-255 means repeat the next byte 257-length times,
+// and 128 marks end of data.
+func RunLengthDecode(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data)*2)
+
+	for i := 0; i < len(data); {
+		n := int(data[i])
+		i++
+
+		switch {
+		case n == 128:
+			// End of data
+			return out, nil
+
+		case n < 128:
+			count := n + 1
+			if i+count > len(data) {
+				return nil, fmt.Errorf("literal run of %d bytes at offset %d exceeds data length", count, i-1)
+			}
+			out = append(out, data[i:i+count]...)
+			i += count
+
+		default:
+			if i >= len(data) {
+				return nil, fmt.Errorf("repeat run at offset %d is missing its byte", i-1)
+			}
+			count := 257 - n
+			b := data[i]
+			for j := 0; j < count; j++ {
+				out = append(out, b)
+			}
+			i++
+		}
+	}
+
+	// Missing EOD marker - accept what was decoded
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package filters
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestRunLengthDecodeBasic tests literal and repeat runs
+func TestRunLengthDecodeBasic(t *testing.T) {
+	data := []byte{
+		2, 'a', 'b', 'c', // literal run of 3
+		253, 'x', // repeat 'x' 4 times
+		128, // EOD
+	}
+
+	decoded, err := RunLengthDecode(data)
+	if err != nil {
+		t.Fatalf("RunLengthDecode failed: %v", err)
+	}
+
+	expected := []byte("abcxxxx")
+	if !bytes.Equal(decoded, expected) {
+		t.Errorf("decoded data doesn't match\ngot:  %q\nwant: %q", decoded, expected)
+	}
+}
+
+// TestRunLengthDecodeStopsAtEOD tests that data after EOD is ignored
+func TestRunLengthDecodeStopsAtEOD(t *testing.T) {
+	data := []byte{0, 'a', 128, 0, 'b'}
+
+	decoded, err := RunLengthDecode(data)
+	if err != nil {
+		t.Fatalf("RunLengthDecode failed: %v", err)
+	}
+
+	if !bytes.Equal(decoded, []byte("a")) {
+		t.Errorf("got %q, want %q", decoded, "a")
+	}
+}
+
+// TestRunLengthDecodeNoEOD tests input without an EOD marker
+func TestRunLengthDecodeNoEOD(t *testing.T) {
+	decoded, err := RunLengthDecode([]byte{129, 'z'})
+	if err != nil {
+		t.Fatalf("RunLengthDecode failed: %v", err)
+	}
+
+	expected := bytes.Repeat([]byte("z"), 128)
+	if !bytes.Equal(decoded, expected) {
+		t.Errorf("got %d bytes, want %d", len(decoded), len(expected))
+	}
+}
+
+// TestRunLengthDecodeTruncated tests truncated literal and repeat runs
+func TestRunLengthDecodeTruncated(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"truncated literal", []byte{5, 'a', 'b'}},
+		{"missing repeat byte", []byte{200}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RunLengthDecode(tt.data); err == nil {
+				t.Error("expected error for truncated input")
+			}
+		})
+	}
+}
@@ -112,6 +112,7 @@ func (le *LayoutElement) ToModelElement() model.Element {
 			Level:    level,
 			BBox:     le.BBox,
 			FontSize: le.fontSize(),
+			Style:    DominantStyle(le.fragments()),
 			ZOrder:   le.ZOrder,
 		}
 
@@ -149,6 +150,7 @@ func (le *LayoutElement) ToModelElement() model.Element {
 			Text:      le.Text,
 			BBox:      le.BBox,
 			FontSize:  le.fontSize(),
+			Style:     DominantStyle(le.fragments()),
 			Alignment: alignment,
 			ZOrder:    le.ZOrder,
 		}
@@ -170,6 +172,22 @@ func (le *LayoutElement) fontSize() float64 {
 	return 12.0 // default
 }
 
+// fragments returns the text fragments of the element, from its heading,
+// paragraph or lines
+func (le *LayoutElement) fragments() []text.TextFragment {
+	if le.Heading != nil {
+		return le.Heading.Fragments
+	}
+	if le.Paragraph != nil {
+		return le.Paragraph.GetFragments()
+	}
+	var fragments []text.TextFragment
+	for _, line := range le.Lines {
+		fragments = append(fragments, line.Fragments...)
+	}
+	return fragments
+}
+
 // toModelAlignment converts a layout LineAlignment to a model.TextAlignment.
 func toModelAlignment(align LineAlignment) model.TextAlignment {
 	switch align {
@@ -650,6 +650,20 @@ func parsePageNumber(s string, result *int) (bool, error) {
 
 // FilterFragments removes header/footer fragments from a page
 func (r *HeaderFooterResult) FilterFragments(pageIndex int, fragments []text.TextFragment, pageHeight float64) []text.TextFragment {
+	return r.filterFragments(pageIndex, false, fragments, pageHeight)
+}
+
+// FilterFragmentsAnyPage removes header/footer fragments from a page that was
+// not part of the detection input, such as when detection ran over a sample of
+// a long document. Every detected region is applied regardless of the pages it
+// was found on; non-page-number regions still require matching text.
+func (r *HeaderFooterResult) FilterFragmentsAnyPage(fragments []text.TextFragment, pageHeight float64) []text.TextFragment {
+	return r.filterFragments(-1, true, fragments, pageHeight)
+}
+
+// filterFragments implements FilterFragments and FilterFragmentsAnyPage. When
+// anyPage is set, regions apply to every page and pageIndex is ignored.
+func (r *HeaderFooterResult) filterFragments(pageIndex int, anyPage bool, fragments []text.TextFragment, pageHeight float64) []text.TextFragment {
 	if r == nil || len(fragments) == 0 {
 		return fragments
 	}
@@ -687,7 +701,7 @@ func (r *HeaderFooterResult) FilterFragments(pageIndex int, fragments []text.Tex
 	var filtered []text.TextFragment
 
 	for _, frag := range fragments {
-		if r.isInHeaderFooter(pageIndex, frag, minY, maxY, headerRegion, footerRegion, invertedCoords, charLevel) {
+		if r.isInHeaderFooter(pageIndex, anyPage, frag, minY, maxY, headerRegion, footerRegion, invertedCoords, charLevel) {
 			continue
 		}
 		filtered = append(filtered, frag)
@@ -697,10 +711,10 @@ func (r *HeaderFooterResult) FilterFragments(pageIndex int, fragments []text.Tex
 }
 
 // isInHeaderFooter checks if a fragment is in a detected header/footer region
-func (r *HeaderFooterResult) isInHeaderFooter(pageIndex int, frag text.TextFragment, minY, maxY, headerRegion, footerRegion float64, invertedCoords, charLevel bool) bool {
+func (r *HeaderFooterResult) isInHeaderFooter(pageIndex int, anyPage bool, frag text.TextFragment, minY, maxY, headerRegion, footerRegion float64, invertedCoords, charLevel bool) bool {
 	// Check headers
 	for _, header := range r.Headers {
-		if !containsPage(header.PageIndices, pageIndex) {
+		if !anyPage && !containsPage(header.PageIndices, pageIndex) {
 			continue
 		}
 
@@ -724,7 +738,7 @@ func (r *HeaderFooterResult) isInHeaderFooter(pageIndex int, frag text.TextFragm
 
 	// Check footers
 	for _, footer := range r.Footers {
-		if !containsPage(footer.PageIndices, pageIndex) {
+		if !anyPage && !containsPage(footer.PageIndices, pageIndex) {
 			continue
 		}
 
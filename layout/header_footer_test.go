@@ -304,6 +304,47 @@ func TestHeaderFooterDetector_FilterFragments(t *testing.T) {
 	}
 }
 
+func TestHeaderFooterDetector_FilterFragmentsAnyPage(t *testing.T) {
+	detector := NewHeaderFooterDetector()
+
+	pages := []PageFragments{
+		makePageFragments(0, 792, 612, []text.TextFragment{
+			makeFragment(72, 760, 200, 12, "Header Text"),
+			makeFragment(72, 400, 468, 10, "Body content 1"),
+		}),
+		makePageFragments(1, 792, 612, []text.TextFragment{
+			makeFragment(72, 760, 200, 12, "Header Text"),
+			makeFragment(72, 400, 468, 10, "Body content 2"),
+		}),
+	}
+
+	result := detector.Detect(pages)
+	if !result.HasHeaders() {
+		t.Fatal("expected a header to be detected")
+	}
+
+	// Page 7 was not part of the detection input.
+	unsampled := []text.TextFragment{
+		makeFragment(72, 760, 200, 12, "Header Text"),
+		makeFragment(72, 740, 200, 12, "Chapter Heading"),
+		makeFragment(72, 400, 468, 10, "Body content 7"),
+	}
+
+	if got := result.FilterFragments(7, unsampled, 792); len(got) != len(unsampled) {
+		t.Errorf("FilterFragments on an undetected page removed %d fragments, want 0", len(unsampled)-len(got))
+	}
+
+	got := result.FilterFragmentsAnyPage(unsampled, 792)
+	if len(got) != 2 {
+		t.Fatalf("FilterFragmentsAnyPage returned %d fragments, want 2", len(got))
+	}
+	for _, f := range got {
+		if f.Text == "Header Text" {
+			t.Error("header should be removed from an unsampled page")
+		}
+	}
+}
+
 func TestHeaderFooterDetector_CustomConfig(t *testing.T) {
 	config := HeaderFooterConfig{
 		HeaderRegionHeight: 100.0, // Larger header region
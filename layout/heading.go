@@ -6,7 +6,9 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/tsawler/tabula/font"
 	"github.com/tsawler/tabula/model"
 	"github.com/tsawler/tabula/text"
 )
@@ -336,36 +338,38 @@ func (d *HeadingDetector) createHeading(para Paragraph, index int, bodyFontSize
 	return heading
 }
 
-// detectBold checks if the paragraph appears to be bold
+// detectBold checks if most of the paragraph's text is bold, going by the
+// fragments' font flags or, for fragments without them, their font names
 func (d *HeadingDetector) detectBold(para Paragraph) bool {
-	// Check font name for bold indicators
-	for _, line := range para.Lines {
-		for _, frag := range line.Fragments {
-			fontLower := strings.ToLower(frag.FontName)
-			if strings.Contains(fontLower, "bold") ||
-				strings.Contains(fontLower, "black") ||
-				strings.Contains(fontLower, "heavy") ||
-				strings.Contains(fontLower, "semibold") ||
-				strings.Contains(fontLower, "demibold") {
-				return true
-			}
-		}
-	}
-	return false
+	return mostlyStyled(para, func(f text.TextFragment) bool {
+		return f.IsBold || font.IsBoldName(f.FontName)
+	})
 }
 
-// detectItalic checks if the paragraph appears to be italic
+// detectItalic checks if most of the paragraph's text is italic
 func (d *HeadingDetector) detectItalic(para Paragraph) bool {
+	return mostlyStyled(para, func(f text.TextFragment) bool {
+		return f.IsItalic || font.IsItalicName(f.FontName)
+	})
+}
+
+// mostlyStyled reports whether more than half the characters of the
+// paragraph are in fragments for which styled is true
+func mostlyStyled(para Paragraph, styled func(text.TextFragment) bool) bool {
+	total, count := 0, 0
 	for _, line := range para.Lines {
 		for _, frag := range line.Fragments {
-			fontLower := strings.ToLower(frag.FontName)
-			if strings.Contains(fontLower, "italic") ||
-				strings.Contains(fontLower, "oblique") {
-				return true
+			n := utf8.RuneCountInString(strings.TrimSpace(frag.Text))
+			if n == 0 {
+				n = 1
+			}
+			total += n
+			if styled(frag) {
+				count += n
 			}
 		}
 	}
-	return false
+	return count*2 > total
 }
 
 // detectAllCaps checks if text is in all capital letters
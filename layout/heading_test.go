@@ -295,6 +295,26 @@ func TestDetectBold(t *testing.T) {
 	}
 }
 
+func TestDetectBoldFromFlags(t *testing.T) {
+	detector := NewHeadingDetector()
+
+	bold := Paragraph{Lines: []Line{{Fragments: []text.TextFragment{
+		{Text: "Results", FontName: "/F2", IsBold: true},
+		{Text: "and", FontName: "/F1"},
+	}}}}
+	if !detector.detectBold(bold) {
+		t.Error("detectBold() = false for mostly bold text, want true")
+	}
+
+	mixed := Paragraph{Lines: []Line{{Fragments: []text.TextFragment{
+		{Text: "Note:", FontName: "/F2", IsBold: true},
+		{Text: "the results below are preliminary", FontName: "/F1"},
+	}}}}
+	if detector.detectBold(mixed) {
+		t.Error("detectBold() = true for a single bold word, want false")
+	}
+}
+
 func TestDetectItalic(t *testing.T) {
 	detector := NewHeadingDetector()
 
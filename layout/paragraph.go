@@ -784,3 +784,15 @@ func (p *Paragraph) GetLastLine() *Line {
 	}
 	return &p.Lines[len(p.Lines)-1]
 }
+
+// GetFragments returns the text fragments of all the paragraph's lines
+func (p *Paragraph) GetFragments() []text.TextFragment {
+	if p == nil {
+		return nil
+	}
+	var fragments []text.TextFragment
+	for _, line := range p.Lines {
+		fragments = append(fragments, line.Fragments...)
+	}
+	return fragments
+}
@@ -0,0 +1,79 @@
+package layout
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/text"
+)
+
+// FragmentStyle returns the style of a text fragment
+func FragmentStyle(f text.TextFragment) model.TextStyle {
+	return model.TextStyle{
+		Bold:      f.IsBold,
+		Italic:    f.IsItalic,
+		Monospace: f.IsMonospace,
+		Color:     toModelColor(f.Color),
+	}
+}
+
+// DominantStyle returns the style of most of the text in the fragments,
+// weighted by character count: each of bold, italic and monospace is set
+// when more than half the characters have it, and the color is the most
+// common one.
+func DominantStyle(fragments []text.TextFragment) model.TextStyle {
+	var total, bold, italic, mono int
+	colors := make(map[model.Color]int)
+	for _, f := range fragments {
+		n := utf8.RuneCountInString(strings.TrimSpace(f.Text))
+		if n == 0 {
+			n = 1
+		}
+		total += n
+		if f.IsBold {
+			bold += n
+		}
+		if f.IsItalic {
+			italic += n
+		}
+		if f.IsMonospace {
+			mono += n
+		}
+		colors[toModelColor(f.Color)] += n
+	}
+
+	style := model.TextStyle{
+		Bold:      bold*2 > total,
+		Italic:    italic*2 > total,
+		Monospace: mono*2 > total,
+	}
+	best := 0
+	for c, n := range colors {
+		if n > best || (n == best && colorLess(c, style.Color)) {
+			style.Color, best = c, n
+		}
+	}
+	return style
+}
+
+// toModelColor converts an RGB color with components in [0, 1]
+func toModelColor(c [3]float64) model.Color {
+	component := func(v float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+	return model.Color{R: component(c[0]), G: component(c[1]), B: component(c[2])}
+}
+
+// colorLess orders colors so that ties between equally common colors are
+// broken the same way every time
+func colorLess(a, b model.Color) bool {
+	if a.R != b.R {
+		return a.R < b.R
+	}
+	if a.G != b.G {
+		return a.G < b.G
+	}
+	return a.B < b.B
+}
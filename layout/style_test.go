@@ -0,0 +1,34 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/text"
+)
+
+func TestDominantStyle(t *testing.T) {
+	fragments := []text.TextFragment{
+		{Text: "Mostly bold", IsBold: true, Color: [3]float64{1, 0, 0}},
+		{Text: "code", IsMonospace: true},
+		{Text: "and more", IsBold: true, IsItalic: true, Color: [3]float64{1, 0, 0}},
+	}
+
+	got := DominantStyle(fragments)
+	want := model.TextStyle{Bold: true, Color: model.Color{R: 255}}
+	if got != want {
+		t.Errorf("DominantStyle() = %+v, want %+v", got, want)
+	}
+
+	if got := DominantStyle(nil); got != (model.TextStyle{}) {
+		t.Errorf("DominantStyle(nil) = %+v, want zero style", got)
+	}
+}
+
+func TestFragmentStyle(t *testing.T) {
+	f := text.TextFragment{IsItalic: true, IsMonospace: true, Color: [3]float64{0, 0.5, 1}}
+	want := model.TextStyle{Italic: true, Monospace: true, Color: model.Color{G: 128, B: 255}}
+	if got := FragmentStyle(f); got != want {
+		t.Errorf("FragmentStyle() = %+v, want %+v", got, want)
+	}
+}
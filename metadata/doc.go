@@ -0,0 +1,40 @@
+// Package metadata reads PDF document metadata.
+//
+// A PDF may describe itself in two places: the document information
+// dictionary referenced by the trailer's /Info entry (PDF 32000-1:2008,
+// 14.3.3), and an XMP metadata stream referenced by the catalog's /Metadata
+// entry (14.3.2). PDF 2.0 deprecates /Info, and PDF/A files and many modern
+// producers record the language, identifiers such as a DOI, the PDF/A
+// conformance level and custom properties only in XMP.
+//
+// # Information Dictionary
+//
+// [FromInfo] decodes the information dictionary's text strings, which may be
+// PDFDocEncoding or UTF-16BE, and its dates. Entries other than the standard
+// ones are returned in Metadata.Custom.
+//
+// [ParseDate] parses PDF date strings of the form D:YYYYMMDDHHmmSSOHH'mm',
+// where every part after the year is optional.
+//
+// # XMP
+//
+// [ParseXMP] reads an XMP packet's RDF properties, in both attribute and
+// element form, including rdf:Alt (the x-default or first language
+// alternative is used), rdf:Seq and rdf:Bag arrays:
+//
+//   - dc:title, dc:creator, dc:description, dc:subject, dc:language and
+//     dc:identifier
+//   - xmp:CreatorTool, xmp:CreateDate and xmp:ModifyDate
+//   - pdf:Producer and pdf:Keywords
+//   - pdfaid:part and pdfaid:conformance, as e.g. "PDF/A-2b"
+//   - prism:doi, as a "doi:" identifier
+//
+// Properties in other namespaces, such as the pdfx namespace where
+// custom /Info entries are mirrored, are returned in Metadata.Custom under
+// their prefixed name, e.g. "pdfx:Department".
+//
+// # Combining Sources
+//
+// [Merge] combines the two, preferring XMP values unless the information
+// dictionary was modified more recently, as PDF 2.0 recommends.
+package metadata
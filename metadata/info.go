@@ -0,0 +1,127 @@
+package metadata
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/font"
+	"github.com/tsawler/tabula/model"
+)
+
+// Resolver resolves indirect references
+type Resolver func(core.IndirectRef) (core.Object, error)
+
+// FromInfo reads a document information dictionary. Text strings are
+// decoded and dates parsed; entries other than the standard ones with a
+// string or name value are returned in Custom.
+func FromInfo(info core.Dict, resolver Resolver) model.Metadata {
+	meta := model.Metadata{Custom: make(map[string]string)}
+	for key, obj := range info {
+		value, ok := textValue(obj, resolver)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Title":
+			meta.Title = value
+		case "Author":
+			meta.Author = value
+		case "Subject":
+			meta.Subject = value
+		case "Keywords":
+			meta.Keywords = SplitKeywords(value)
+		case "Creator":
+			meta.Creator = value
+		case "Producer":
+			meta.Producer = value
+		case "CreationDate":
+			meta.CreationDate, _ = ParseDate(value)
+		case "ModDate":
+			meta.ModDate, _ = ParseDate(value)
+		case "Trapped":
+		default:
+			if value != "" {
+				meta.Custom[key] = value
+			}
+		}
+	}
+	return meta
+}
+
+// textValue returns the decoded, trimmed text of a string or name
+func textValue(obj core.Object, resolver Resolver) (string, bool) {
+	if ref, ok := obj.(core.IndirectRef); ok && resolver != nil {
+		resolved, err := resolver(ref)
+		if err != nil {
+			return "", false
+		}
+		obj = resolved
+	}
+	switch v := obj.(type) {
+	case core.String:
+		return strings.TrimSpace(font.DecodeTextString([]byte(v))), true
+	case core.Name:
+		return string(v), true
+	}
+	return "", false
+}
+
+// SplitKeywords splits a keywords string on commas or semicolons, dropping
+// empty keywords
+func SplitKeywords(s string) []string {
+	var keywords []string
+	for _, kw := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			keywords = append(keywords, kw)
+		}
+	}
+	return keywords
+}
+
+// ParseDate parses a PDF date string, D:YYYYMMDDHHmmSSOHH'mm' (PDF
+// 32000-1:2008, 7.9.4). The D: prefix and every part after the year are
+// optional; O is +, - or Z, and a missing offset is taken as UTC.
+func ParseDate(s string) (time.Time, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "D:")
+
+	// Digits of the date and time, then the offset
+	end := 0
+	for end < len(s) && end < 14 && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	digits, rest := s[:end], s[end:]
+	if len(digits) < 4 || len(digits)%2 != 0 {
+		return time.Time{}, false
+	}
+
+	// Year, then month, day, hour, minute and second, with their defaults
+	parts := [6]int{0, 1, 1, 0, 0, 0}
+	parts[0], _ = strconv.Atoi(digits[:4])
+	for i := 1; 2*i+4 <= len(digits); i++ {
+		parts[i], _ = strconv.Atoi(digits[2*i+2 : 2*i+4])
+	}
+	if parts[1] < 1 || parts[1] > 12 || parts[2] < 1 || parts[2] > 31 || parts[3] > 23 || parts[4] > 59 || parts[5] > 59 {
+		return time.Time{}, false
+	}
+
+	loc := time.UTC
+	if len(rest) > 0 && (rest[0] == '+' || rest[0] == '-') {
+		offset := strings.NewReplacer("'", "", ":", "").Replace(rest[1:])
+		hours, minutes := 0, 0
+		if len(offset) >= 2 {
+			hours, _ = strconv.Atoi(offset[:2])
+		}
+		if len(offset) >= 4 {
+			minutes, _ = strconv.Atoi(offset[2:4])
+		}
+		seconds := hours*3600 + minutes*60
+		if rest[0] == '-' {
+			seconds = -seconds
+		}
+		loc = time.FixedZone("", seconds)
+	}
+
+	return time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], parts[5], 0, loc), true
+}
@@ -0,0 +1,68 @@
+package metadata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tsawler/tabula/core"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+		ok   bool
+	}{
+		{"D:20230415103000+02'00'", time.Date(2023, 4, 15, 8, 30, 0, 0, time.UTC), true},
+		{"D:20230415103000-05'30", time.Date(2023, 4, 15, 16, 0, 0, 0, time.UTC), true},
+		{"D:20230415103000Z", time.Date(2023, 4, 15, 10, 30, 0, 0, time.UTC), true},
+		{"D:2023", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"20230415", time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC), true},
+		{"yesterday", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseDate(tt.in)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%q): Expected %v, %v, got %v, %v", tt.in, tt.want, tt.ok, got, ok)
+		}
+	}
+}
+
+func TestFromInfo(t *testing.T) {
+	objects := map[int]core.Object{
+		1: core.String("Jane Doe"),
+	}
+	resolver := func(ref core.IndirectRef) (core.Object, error) {
+		return objects[ref.Number], nil
+	}
+
+	info := core.Dict{
+		"Title":        core.String("\xfe\xff\x00R\x00\xe9\x00s\x00u\x00m\x00\xe9"),
+		"Author":       core.IndirectRef{Number: 1},
+		"Keywords":     core.String("pdf, metadata; xmp"),
+		"CreationDate": core.String("D:20230415103000Z"),
+		"Trapped":      core.Name("False"),
+		"Department":   core.String("Research"),
+	}
+	meta := FromInfo(info, resolver)
+
+	if meta.Title != "Résumé" {
+		t.Errorf("Expected title Résumé, got %q", meta.Title)
+	}
+	if meta.Author != "Jane Doe" {
+		t.Errorf("Expected author resolved to Jane Doe, got %q", meta.Author)
+	}
+	if len(meta.Keywords) != 3 || meta.Keywords[2] != "xmp" {
+		t.Errorf("Expected keywords [pdf metadata xmp], got %v", meta.Keywords)
+	}
+	if meta.CreationDate.Year() != 2023 {
+		t.Errorf("Expected creation date in 2023, got %v", meta.CreationDate)
+	}
+	if meta.Custom["Department"] != "Research" {
+		t.Errorf("Expected custom Department, got %v", meta.Custom)
+	}
+	if _, ok := meta.Custom["Trapped"]; ok {
+		t.Errorf("Expected Trapped to be ignored, got %v", meta.Custom)
+	}
+}
@@ -0,0 +1,394 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tsawler/tabula/model"
+)
+
+// XMP namespaces
+const (
+	nsRDF    = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	nsXML    = "http://www.w3.org/XML/1998/namespace"
+	nsDC     = "http://purl.org/dc/elements/1.1/"
+	nsXMP    = "http://ns.adobe.com/xap/1.0/"
+	nsPDF    = "http://ns.adobe.com/pdf/1.3/"
+	nsPDFAID = "http://www.aiim.org/pdfa/ns/id/"
+	nsPRISM  = "http://prismstandard.org/namespaces/basic/" // Followed by the version, e.g. 2.0/
+)
+
+// ignoredNamespaces hold bookkeeping rather than descriptive metadata, and
+// are not reported as custom properties
+var ignoredNamespaces = []string{
+	"adobe:ns:meta/",
+	"http://ns.adobe.com/xap/1.0/mm/",
+	"http://ns.adobe.com/xap/1.0/t/pg/",
+	"http://ns.adobe.com/xap/1.0/g/img/",
+	"http://ns.adobe.com/xap/1.0/sType/",
+	"http://www.aiim.org/pdfa/ns/", // PDF/A extension schema descriptions
+}
+
+// xmpProperty is a property of an rdf:Description with a simple or array
+// value. For language alternatives, the x-default value comes first.
+type xmpProperty struct {
+	ns, name string
+	values   []string
+}
+
+// ParseXMP reads the descriptive properties of an XMP packet
+func ParseXMP(data []byte) (model.Metadata, error) {
+	props, prefixes, err := parseRDF(data)
+	if err != nil {
+		return model.Metadata{}, err
+	}
+
+	meta := model.Metadata{Custom: make(map[string]string)}
+	var pdfaPart, pdfaConformance string
+	for _, p := range props {
+		if len(p.values) == 0 {
+			continue
+		}
+		first := p.values[0]
+		switch {
+		case p.ns == nsDC:
+			switch p.name {
+			case "title":
+				meta.Title = first
+			case "creator":
+				meta.Author = strings.Join(p.values, ", ")
+			case "description":
+				meta.Subject = first
+			case "subject":
+				for _, v := range p.values {
+					meta.Keywords = append(meta.Keywords, SplitKeywords(v)...)
+				}
+			case "language":
+				meta.Language = first
+			case "identifier":
+				meta.Identifiers = appendIdentifiers(meta.Identifiers, p.values...)
+			}
+		case p.ns == nsXMP:
+			switch p.name {
+			case "CreatorTool":
+				meta.Creator = first
+			case "CreateDate":
+				meta.CreationDate, _ = parseXMPDate(first)
+			case "ModifyDate":
+				meta.ModDate, _ = parseXMPDate(first)
+			}
+		case p.ns == nsPDF:
+			switch p.name {
+			case "Producer":
+				meta.Producer = first
+			case "Keywords":
+				if len(meta.Keywords) == 0 {
+					meta.Keywords = SplitKeywords(first)
+				}
+			}
+		case p.ns == nsPDFAID:
+			switch p.name {
+			case "part":
+				pdfaPart = first
+			case "conformance":
+				pdfaConformance = first
+			}
+		case strings.HasPrefix(p.ns, nsPRISM):
+			if p.name == "doi" {
+				doi := first
+				if !strings.HasPrefix(strings.ToLower(doi), "doi:") {
+					doi = "doi:" + doi
+				}
+				meta.Identifiers = appendIdentifiers(meta.Identifiers, doi)
+			}
+		case !ignoredNamespace(p.ns):
+			prefix := prefixes[p.ns]
+			if prefix == "" {
+				prefix = p.ns
+			}
+			meta.Custom[prefix+":"+p.name] = strings.Join(p.values, ", ")
+		}
+	}
+
+	if pdfaPart != "" {
+		meta.PDFAConformance = "PDF/A-" + pdfaPart + strings.ToLower(pdfaConformance)
+	}
+	return meta, nil
+}
+
+// ignoredNamespace reports whether properties in a namespace are skipped
+func ignoredNamespace(ns string) bool {
+	for _, ignored := range ignoredNamespaces {
+		if strings.HasPrefix(ns, ignored) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendIdentifiers appends identifiers that are not already present
+func appendIdentifiers(ids []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, id := range ids {
+			if strings.EqualFold(id, v) {
+				found = true
+				break
+			}
+		}
+		if !found && v != "" {
+			ids = append(ids, v)
+		}
+	}
+	return ids
+}
+
+// parseRDF returns the properties of every rdf:Description in an XMP
+// packet, and the prefix declared for each namespace
+func parseRDF(data []byte) ([]xmpProperty, map[string]string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+
+	var props []xmpProperty
+	prefixes := make(map[string]string)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse XMP: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		recordPrefixes(start, prefixes)
+		if start.Name.Space != nsRDF || start.Name.Local != "Description" {
+			continue
+		}
+
+		// Simple properties may be given as attributes
+		for _, attr := range start.Attr {
+			if isPropertyName(attr.Name) {
+				props = append(props, xmpProperty{ns: attr.Name.Space, name: attr.Name.Local, values: []string{strings.TrimSpace(attr.Value)}})
+			}
+		}
+		children, err := parseProperties(dec, prefixes)
+		if err != nil {
+			return nil, nil, err
+		}
+		props = append(props, children...)
+	}
+	return props, prefixes, nil
+}
+
+// recordPrefixes records the namespace prefixes an element declares
+func recordPrefixes(start xml.StartElement, prefixes map[string]string) {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" {
+			if _, ok := prefixes[attr.Value]; !ok {
+				prefixes[attr.Value] = attr.Name.Local
+			}
+		}
+	}
+}
+
+// isPropertyName reports whether an attribute of rdf:Description is a
+// property rather than RDF syntax or a namespace declaration
+func isPropertyName(name xml.Name) bool {
+	switch name.Space {
+	case "", "xmlns", nsRDF, nsXML:
+		return false
+	}
+	return true
+}
+
+// parseProperties reads the property elements of an rdf:Description up to
+// its end tag
+func parseProperties(dec *xml.Decoder, prefixes map[string]string) ([]xmpProperty, error) {
+	var props []xmpProperty
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("parse XMP: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			recordPrefixes(t, prefixes)
+			values, err := propertyValues(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			props = append(props, xmpProperty{ns: t.Name.Space, name: t.Name.Local, values: values})
+		case xml.EndElement:
+			return props, nil
+		}
+	}
+}
+
+// propertyValues reads the value of a property element: its text, an
+// rdf:resource, or the items of an rdf:Alt, rdf:Seq or rdf:Bag. Structured
+// values have none.
+func propertyValues(dec *xml.Decoder, start xml.StartElement) ([]string, error) {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == nsRDF && attr.Name.Local == "resource" {
+			return []string{attr.Value}, dec.Skip()
+		}
+		if attr.Name.Space == nsRDF && attr.Name.Local == "parseType" {
+			return nil, dec.Skip()
+		}
+	}
+
+	var text strings.Builder
+	var values []string
+	structured := false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("parse XMP: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			if t.Name.Space == nsRDF && (t.Name.Local == "Alt" || t.Name.Local == "Seq" || t.Name.Local == "Bag") {
+				items, err := arrayItems(dec)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, items...)
+				continue
+			}
+			structured = true
+			if err := dec.Skip(); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if structured {
+				return nil, nil
+			}
+			if values == nil {
+				if s := strings.TrimSpace(text.String()); s != "" {
+					values = []string{s}
+				}
+			}
+			return values, nil
+		}
+	}
+}
+
+// arrayItems reads the rdf:li items of an array up to its end tag. The
+// x-default item of a language alternative is moved to the front.
+func arrayItems(dec *xml.Decoder) ([]string, error) {
+	var items []string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("parse XMP: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			values, err := propertyValues(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			if len(values) == 0 {
+				continue
+			}
+			isDefault := false
+			for _, attr := range t.Attr {
+				if attr.Name.Space == nsXML && attr.Name.Local == "lang" && strings.EqualFold(attr.Value, "x-default") {
+					isDefault = true
+				}
+			}
+			if isDefault {
+				items = append(values[:1:1], items...)
+			} else {
+				items = append(items, values[0])
+			}
+		case xml.EndElement:
+			return items, nil
+		}
+	}
+}
+
+// xmpDateLayouts are the forms of XMP dates (ISO 8601 as profiled by XMP)
+var xmpDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// parseXMPDate parses an XMP date; one without a time zone is taken as UTC
+func parseXMPDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range xmpDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Merge combines metadata read from XMP and from the information
+// dictionary. XMP values are preferred, unless the information dictionary's
+// ModDate is later than XMP's, in which case it was updated by a tool that
+// did not update the XMP. Either source fills the other's gaps, and custom
+// properties from both are kept.
+func Merge(xmp, info model.Metadata) model.Metadata {
+	primary, secondary := xmp, info
+	if !info.ModDate.IsZero() && info.ModDate.After(xmp.ModDate) && !xmp.ModDate.IsZero() {
+		primary, secondary = info, xmp
+	}
+
+	merged := primary
+	if merged.Title == "" {
+		merged.Title = secondary.Title
+	}
+	if merged.Author == "" {
+		merged.Author = secondary.Author
+	}
+	if merged.Subject == "" {
+		merged.Subject = secondary.Subject
+	}
+	if len(merged.Keywords) == 0 {
+		merged.Keywords = secondary.Keywords
+	}
+	if merged.Creator == "" {
+		merged.Creator = secondary.Creator
+	}
+	if merged.Producer == "" {
+		merged.Producer = secondary.Producer
+	}
+	if merged.CreationDate.IsZero() {
+		merged.CreationDate = secondary.CreationDate
+	}
+	if merged.ModDate.IsZero() {
+		merged.ModDate = secondary.ModDate
+	}
+	if merged.Language == "" {
+		merged.Language = secondary.Language
+	}
+	if merged.PDFAConformance == "" {
+		merged.PDFAConformance = secondary.PDFAConformance
+	}
+	merged.Identifiers = appendIdentifiers(append([]string(nil), primary.Identifiers...), secondary.Identifiers...)
+
+	merged.Custom = make(map[string]string, len(primary.Custom)+len(secondary.Custom))
+	for k, v := range secondary.Custom {
+		merged.Custom[k] = v
+	}
+	for k, v := range primary.Custom {
+		merged.Custom[k] = v
+	}
+	return merged
+}
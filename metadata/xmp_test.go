@@ -0,0 +1,118 @@
+package metadata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tsawler/tabula/model"
+)
+
+const testXMP = `<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+    xmlns:pdf="http://ns.adobe.com/pdf/1.3/"
+    xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/"
+    xmlns:prism="http://prismstandard.org/namespaces/basic/2.0/"
+    xmlns:xmpMM="http://ns.adobe.com/xap/1.0/mm/"
+    xmlns:acme="http://example.com/acme/1.0/"
+    xmp:CreatorTool="Writer"
+    pdfaid:part="2"
+    pdfaid:conformance="B"
+    acme:project="Apollo">
+   <dc:title>
+    <rdf:Alt>
+     <rdf:li xml:lang="de">Bericht</rdf:li>
+     <rdf:li xml:lang="x-default">Report</rdf:li>
+    </rdf:Alt>
+   </dc:title>
+   <dc:creator><rdf:Seq><rdf:li>Ann</rdf:li><rdf:li>Bob</rdf:li></rdf:Seq></dc:creator>
+   <dc:subject><rdf:Bag><rdf:li>alpha</rdf:li><rdf:li>beta</rdf:li></rdf:Bag></dc:subject>
+   <dc:language><rdf:Bag><rdf:li>en-GB</rdf:li></rdf:Bag></dc:language>
+   <pdf:Producer>Tabula</pdf:Producer>
+   <pdf:Keywords>ignored</pdf:Keywords>
+   <xmp:ModifyDate>2024-02-01T12:00:00Z</xmp:ModifyDate>
+   <prism:doi>10.1000/xyz123</prism:doi>
+   <xmpMM:DocumentID>uuid:1234</xmpMM:DocumentID>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`
+
+func TestParseXMP(t *testing.T) {
+	meta, err := ParseXMP([]byte(testXMP))
+	if err != nil {
+		t.Fatalf("ParseXMP failed: %v", err)
+	}
+
+	if meta.Title != "Report" {
+		t.Errorf("Expected x-default title Report, got %q", meta.Title)
+	}
+	if meta.Author != "Ann, Bob" {
+		t.Errorf("Expected author Ann, Bob, got %q", meta.Author)
+	}
+	if len(meta.Keywords) != 2 || meta.Keywords[0] != "alpha" {
+		t.Errorf("Expected keywords [alpha beta], got %v", meta.Keywords)
+	}
+	if meta.Language != "en-GB" {
+		t.Errorf("Expected language en-GB, got %q", meta.Language)
+	}
+	if meta.Creator != "Writer" || meta.Producer != "Tabula" {
+		t.Errorf("Expected creator Writer and producer Tabula, got %q, %q", meta.Creator, meta.Producer)
+	}
+	if !meta.ModDate.Equal(time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected modification date 2024-02-01, got %v", meta.ModDate)
+	}
+	if meta.PDFAConformance != "PDF/A-2b" {
+		t.Errorf("Expected PDF/A-2b, got %q", meta.PDFAConformance)
+	}
+	if len(meta.Identifiers) != 1 || meta.Identifiers[0] != "doi:10.1000/xyz123" {
+		t.Errorf("Expected DOI identifier, got %v", meta.Identifiers)
+	}
+	if meta.Custom["acme:project"] != "Apollo" {
+		t.Errorf("Expected custom acme:project, got %v", meta.Custom)
+	}
+	if len(meta.Custom) != 1 {
+		t.Errorf("Expected only one custom property, got %v", meta.Custom)
+	}
+}
+
+func TestParseXMPInvalid(t *testing.T) {
+	if _, err := ParseXMP([]byte("not xml at all <")); err == nil {
+		t.Error("Expected an error for a packet without rdf:RDF")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	xmp := model.Metadata{
+		Title:       "XMP Title",
+		ModDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Identifiers: []string{"doi:10.1/a"},
+		Custom:      map[string]string{"a": "xmp"},
+	}
+	info := model.Metadata{
+		Title:    "Info Title",
+		Producer: "Info Producer",
+		Custom:   map[string]string{"a": "info", "b": "info"},
+	}
+
+	merged := Merge(xmp, info)
+	if merged.Title != "XMP Title" || merged.Producer != "Info Producer" {
+		t.Errorf("Expected XMP title and Info producer, got %q, %q", merged.Title, merged.Producer)
+	}
+	if merged.Custom["a"] != "xmp" || merged.Custom["b"] != "info" {
+		t.Errorf("Expected custom properties from both, XMP first, got %v", merged.Custom)
+	}
+
+	// An Info dictionary modified after the XMP packet takes precedence
+	info.ModDate = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	merged = Merge(xmp, info)
+	if merged.Title != "Info Title" {
+		t.Errorf("Expected newer Info title, got %q", merged.Title)
+	}
+	if len(merged.Identifiers) != 1 {
+		t.Errorf("Expected identifiers kept, got %v", merged.Identifiers)
+	}
+}
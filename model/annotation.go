@@ -0,0 +1,139 @@
+package model
+
+// AnnotationType identifies the kind of a PDF annotation.
+type AnnotationType int
+
+const (
+	AnnotationOther     AnnotationType = iota // Any other subtype (see Annotation.Subtype)
+	AnnotationLink                            // Hyperlink or internal link
+	AnnotationNote                            // Sticky note (Text annotation)
+	AnnotationFreeText                        // Text displayed directly on the page
+	AnnotationHighlight                       // Highlighted text
+	AnnotationUnderline                       // Underlined text
+	AnnotationStrikeOut                       // Struck-out text
+	AnnotationSquiggly                        // Text with a squiggly underline
+	AnnotationStamp                           // Rubber stamp, e.g. "Approved"
+)
+
+// String returns the name of the annotation type.
+func (at AnnotationType) String() string {
+	switch at {
+	case AnnotationLink:
+		return "Link"
+	case AnnotationNote:
+		return "Note"
+	case AnnotationFreeText:
+		return "FreeText"
+	case AnnotationHighlight:
+		return "Highlight"
+	case AnnotationUnderline:
+		return "Underline"
+	case AnnotationStrikeOut:
+		return "StrikeOut"
+	case AnnotationSquiggly:
+		return "Squiggly"
+	case AnnotationStamp:
+		return "Stamp"
+	default:
+		return "Other"
+	}
+}
+
+// IsTextMarkup reports whether the annotation marks up text on the page
+// (highlight, underline, strike-out or squiggly).
+func (at AnnotationType) IsTextMarkup() bool {
+	switch at {
+	case AnnotationHighlight, AnnotationUnderline, AnnotationStrikeOut, AnnotationSquiggly:
+		return true
+	}
+	return false
+}
+
+// AnnotationTypeFromSubtype maps a PDF annotation subtype name to its type.
+func AnnotationTypeFromSubtype(subtype string) AnnotationType {
+	switch subtype {
+	case "Link":
+		return AnnotationLink
+	case "Text":
+		return AnnotationNote
+	case "FreeText":
+		return AnnotationFreeText
+	case "Highlight":
+		return AnnotationHighlight
+	case "Underline":
+		return AnnotationUnderline
+	case "StrikeOut":
+		return AnnotationStrikeOut
+	case "Squiggly":
+		return AnnotationSquiggly
+	case "Stamp":
+		return AnnotationStamp
+	default:
+		return AnnotationOther
+	}
+}
+
+// Annotation represents an annotation on a PDF page: a link, a comment,
+// a text markup or a stamp.
+type Annotation struct {
+	Type     AnnotationType
+	Subtype  string // PDF annotation subtype, e.g. "Highlight" or "Ink"
+	Page     int    // Page number (1-indexed)
+	BBox     BBox   // Annotation rectangle
+	Author   string
+	Subject  string
+	Contents string // Comment or note text
+	Name     string // Icon name of a note or name of a stamp, e.g. "Approved"
+
+	// Link targets
+	URI        string  // External target of a URI link
+	TargetPage int     // Internal link target page (1-indexed, 0 if none)
+	TargetY    float64 // Vertical position on the target page (if HasTargetY)
+	HasTargetY bool
+
+	// MarkedText is the page text covered by a text markup annotation
+	MarkedText string
+}
+
+// Link is a run of page text covered by a URI link annotation.
+type Link struct {
+	Text string
+	URI  string
+	BBox BBox
+}
+
+// Comment is a page element holding a comment annotation (a note, free
+// text, stamp or text markup) so that reviewers' comments can flow into
+// text output and chunks alongside the page content.
+type Comment struct {
+	Annotation Annotation
+	ZOrder     int
+}
+
+// Type returns ElementTypeHighlight for text markup annotations and
+// ElementTypeComment otherwise.
+func (c *Comment) Type() ElementType {
+	if c.Annotation.Type.IsTextMarkup() {
+		return ElementTypeHighlight
+	}
+	return ElementTypeComment
+}
+func (c *Comment) BoundingBox() BBox { return c.Annotation.BBox }
+func (c *Comment) ZIndex() int       { return c.ZOrder }
+
+// GetText returns the comment text; for text markup it is the marked text
+// followed by any comment on it.
+func (c *Comment) GetText() string {
+	a := c.Annotation
+	text := a.Contents
+	if text == "" && a.Type == AnnotationStamp {
+		text = a.Name
+	}
+	if a.Type.IsTextMarkup() && a.MarkedText != "" {
+		if text == "" {
+			return a.MarkedText
+		}
+		return a.MarkedText + " (" + text + ")"
+	}
+	return text
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Attachment is a file embedded in a document, such as the XML invoice
+// data of a ZUGFeRD or Factur-X PDF or one of the files of a PDF portfolio.
+type Attachment struct {
+	Name         string // File name
+	Description  string // Description given by the document, if any
+	MIMEType     string // e.g. "application/xml"; "application/octet-stream" if unknown
+	Data         []byte // File contents
+	CreationDate time.Time
+	ModDate      time.Time
+	Relationship string // Relationship of a PDF/A-3 associated file to the document, e.g. "Alternative" or "Data"
+	Page         int    // 1-indexed page of a file attachment annotation; 0 for files attached to the document
+
+	// Document is the extracted content of the file, set when attachments
+	// are included in the extraction and the file's format is supported
+	Document *Document
+}
@@ -3,14 +3,19 @@ package model
 import "time"
 
 // Document represents a complete PDF document with extracted semantic structure.
-// It contains document-level metadata and an ordered list of pages.
+// It contains document-level metadata, an ordered list of pages and, for PDFs
+// with bookmarks, the document outline. When attachments are included in the
+// extraction, it also holds the PDF's embedded files and their content.
 type Document struct {
-	Metadata Metadata
-	Pages    []*Page
+	Metadata    Metadata
+	Pages       []*Page
+	Outline     []*OutlineEntry
+	Attachments []*Attachment
 }
 
 // Metadata contains document-level metadata extracted from the PDF's document
-// information dictionary and XMP metadata streams.
+// information dictionary and XMP metadata streams, or from the core, custom
+// and package properties of other formats.
 type Metadata struct {
 	Title        string
 	Author       string
@@ -20,7 +25,12 @@ type Metadata struct {
 	Producer     string
 	CreationDate time.Time
 	ModDate      time.Time
-	// Custom metadata
+	Language     string   // BCP 47 language tag, e.g. "en-US"
+	Identifiers  []string // e.g. "doi:10.1000/182", ISBNs or UUIDs
+	// PDF/A part and conformance level, e.g. "PDF/A-2b", for PDF/A files
+	PDFAConformance string
+	// Custom metadata: non-standard /Info entries, properties in other XMP
+	// namespaces (by prefixed name) and custom document properties
 	Custom map[string]string
 }
 
@@ -162,3 +172,12 @@ type TOCEntry struct {
 	BBox     BBox    // Position on page
 	FontSize float64 // Font size of heading
 }
+
+// OutlineEntry represents an entry in the document outline (PDF bookmarks).
+type OutlineEntry struct {
+	Title    string          // Entry title
+	Page     int             // Destination page number (1-indexed, 0 if unresolved)
+	Y        float64         // Vertical position on the page (if HasY)
+	HasY     bool            // Whether the destination specifies a vertical position
+	Children []*OutlineEntry // Nested entries
+}
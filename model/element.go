@@ -12,6 +12,8 @@ const (
 	ElementTypeImage
 	ElementTypeFigure
 	ElementTypeCaption
+	ElementTypeComment
+	ElementTypeHighlight
 )
 
 // String returns the name of the element type.
@@ -31,6 +33,10 @@ func (et ElementType) String() string {
 		return "Figure"
 	case ElementTypeCaption:
 		return "Caption"
+	case ElementTypeComment:
+		return "Comment"
+	case ElementTypeHighlight:
+		return "Highlight"
 	default:
 		return "Unknown"
 	}
@@ -145,9 +151,18 @@ type TextStyle struct {
 	Bold      bool
 	Italic    bool
 	Underline bool
+	Monospace bool
 	Color     Color
 }
 
+// StyledText is a run of page text set in a bold, italic or monospaced font
+// that stands out from the page's body text.
+type StyledText struct {
+	Text  string
+	Style TextStyle
+	BBox  BBox
+}
+
 // TextAlignment represents horizontal text alignment.
 type TextAlignment int
 
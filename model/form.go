@@ -0,0 +1,55 @@
+package model
+
+// FormFieldType identifies the kind of an interactive form field.
+type FormFieldType int
+
+const (
+	FormFieldUnknown    FormFieldType = iota
+	FormFieldText                     // Single or multi-line text
+	FormFieldCheckbox                 // Check box
+	FormFieldRadio                    // Radio button group
+	FormFieldPushbutton               // Push button (holds no value)
+	FormFieldChoice                   // List box or combo box
+	FormFieldSignature                // Digital signature
+)
+
+// String returns the name of the form field type.
+func (ft FormFieldType) String() string {
+	switch ft {
+	case FormFieldText:
+		return "text"
+	case FormFieldCheckbox:
+		return "checkbox"
+	case FormFieldRadio:
+		return "radio"
+	case FormFieldPushbutton:
+		return "pushbutton"
+	case FormFieldChoice:
+		return "choice"
+	case FormFieldSignature:
+		return "signature"
+	default:
+		return "unknown"
+	}
+}
+
+// FormField is a field of an interactive (AcroForm or XFA) form.
+type FormField struct {
+	Name     string // Fully qualified name, e.g. "applicant.address.city"
+	Type     FormFieldType
+	Value    string   // Text, selected choice(s), on state of a check box or radio group, or signer name
+	Values   []string // Selected options of a choice field
+	Checked  bool     // Whether a check box or radio group is on
+	Signed   bool     // Whether a signature field holds a signature
+	Options  []string // Choice options, or the on states of a check box or radio group
+	ReadOnly bool
+	Required bool
+	Widgets  []FormWidget // Where the field appears; empty for XFA fields
+	XFA      bool         // Whether the value comes from XFA datasets
+}
+
+// FormWidget is a place where a form field appears on a page.
+type FormWidget struct {
+	Page int  // 1-based page number, or 0 if unknown
+	BBox BBox // Widget rectangle in PDF coordinates
+}
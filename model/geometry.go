@@ -202,6 +202,24 @@ func Rotate(angle float64) Matrix {
 	return Matrix{cos, sin, -sin, cos, 0, 0}
 }
 
+// Inverse returns the inverse of m, which undoes its transformation. It
+// reports false when m is singular (it collapses the plane onto a line or
+// point) and has no inverse.
+func (m Matrix) Inverse() (Matrix, bool) {
+	det := m[0]*m[3] - m[1]*m[2]
+	if det == 0 {
+		return Matrix{}, false
+	}
+	return Matrix{
+		m[3] / det,
+		-m[1] / det,
+		-m[2] / det,
+		m[0] / det,
+		(m[2]*m[5] - m[3]*m[4]) / det,
+		(m[1]*m[4] - m[0]*m[5]) / det,
+	}, true
+}
+
 // IsIdentity reports whether m is the identity matrix.
 func (m Matrix) IsIdentity() bool {
 	return m[0] == 1 && m[1] == 0 && m[2] == 0 && m[3] == 1 && m[4] == 0 && m[5] == 0
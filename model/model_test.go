@@ -364,6 +364,23 @@ func TestRotate(t *testing.T) {
 	}
 }
 
+func TestMatrixInverse(t *testing.T) {
+	m := Matrix{0, 2, -3, 0, 10, 20} // Rotate, scale and translate
+	inv, ok := m.Inverse()
+	if !ok {
+		t.Fatal("Inverse() reported a singular matrix")
+	}
+	p := Point{5, 7}
+	result := m.Multiply(inv).Transform(p)
+	if math.Abs(result.X-p.X) > 0.0001 || math.Abs(result.Y-p.Y) > 0.0001 {
+		t.Errorf("m.Multiply(inverse).Transform(%v) = %v, want %v", p, result, p)
+	}
+
+	if _, ok := (Matrix{1, 2, 2, 4, 0, 0}).Inverse(); ok {
+		t.Error("Inverse() of a singular matrix reported ok")
+	}
+}
+
 func TestMatrixIsIdentity(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -838,6 +855,8 @@ func TestElementTypeString(t *testing.T) {
 		{ElementTypeImage, "Image"},
 		{ElementTypeFigure, "Figure"},
 		{ElementTypeCaption, "Caption"},
+		{ElementTypeComment, "Comment"},
+		{ElementTypeHighlight, "Highlight"},
 	}
 
 	for _, tt := range tests {
@@ -870,6 +889,38 @@ func TestParagraphInterface(t *testing.T) {
 	}
 }
 
+func TestCommentInterface(t *testing.T) {
+	note := &Comment{Annotation: Annotation{Type: AnnotationNote, Contents: "Fix this", BBox: NewBBox(0, 0, 20, 20)}}
+	if note.Type() != ElementTypeComment {
+		t.Error("Type() should return ElementTypeComment for a note")
+	}
+	if note.GetText() != "Fix this" {
+		t.Errorf("GetText() = %q, want %q", note.GetText(), "Fix this")
+	}
+
+	highlight := &Comment{Annotation: Annotation{Type: AnnotationHighlight, MarkedText: "key result", Contents: "cite"}}
+	if highlight.Type() != ElementTypeHighlight {
+		t.Error("Type() should return ElementTypeHighlight for a highlight")
+	}
+	if got := highlight.GetText(); got != "key result (cite)" {
+		t.Errorf("GetText() = %q, want %q", got, "key result (cite)")
+	}
+
+	stamp := &Comment{Annotation: Annotation{Type: AnnotationStamp, Name: "Approved"}}
+	if stamp.GetText() != "Approved" {
+		t.Errorf("GetText() = %q, want stamp name", stamp.GetText())
+	}
+}
+
+func TestAnnotationTypeFromSubtype(t *testing.T) {
+	if AnnotationTypeFromSubtype("Text") != AnnotationNote || AnnotationTypeFromSubtype("Ink") != AnnotationOther {
+		t.Error("AnnotationTypeFromSubtype() mapped subtypes incorrectly")
+	}
+	if !AnnotationSquiggly.IsTextMarkup() || AnnotationFreeText.IsTextMarkup() {
+		t.Error("IsTextMarkup() classified types incorrectly")
+	}
+}
+
 func TestHeadingInterface(t *testing.T) {
 	h := &Heading{
 		Text:   "Test heading",
@@ -2,7 +2,14 @@ package model
 
 // Page represents a single page in a PDF document
 type Page struct {
-	Number   int       // 1-indexed page number
+	Number    int    // 1-indexed page number, by position in the document
+	PageLabel string // Printed page label, e.g. "iv" or "A-3"; empty if the document defines none
+
+	// SourceNumber is the 1-indexed number of the page in the source file,
+	// which differs from Number when only some pages were extracted; 0 if
+	// unknown
+	SourceNumber int
+
 	Width    float64   // Page width in points
 	Height   float64   // Page height in points
 	Rotation int       // Rotation angle (0, 90, 180, 270)
@@ -12,6 +19,12 @@ type Page struct {
 	RawText  []TextFragment // All text fragments with positions
 	RawLines []Line         // All detected lines/rectangles
 
+	// Hyperlinks: runs of text covered by URI link annotations
+	Links []Link
+
+	// Emphasis: runs of bold, italic or monospaced text
+	Styled []StyledText
+
 	// Layout analysis results (populated by AnalyzeLayout)
 	Layout *PageLayout // Layout analysis results, nil if not analyzed
 }
@@ -220,6 +233,15 @@ func (p *Page) GetElementsInRegion(bbox BBox) []Element {
 	return elements
 }
 
+// SourcePage returns the page's number in the source file: SourceNumber
+// when set, else Number
+func (p *Page) SourcePage() int {
+	if p.SourceNumber > 0 {
+		return p.SourceNumber
+	}
+	return p.Number
+}
+
 // HasLayout returns true if layout analysis has been performed on this page
 func (p *Page) HasLayout() bool {
 	return p.Layout != nil
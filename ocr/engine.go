@@ -0,0 +1,93 @@
+package ocr
+
+import (
+	"context"
+	"image"
+	"strings"
+)
+
+// Word is a word recognized in an image, with its position.
+type Word struct {
+	// Text is the recognized word
+	Text string
+
+	// BBox is the word's bounding box in image pixels, origin top-left
+	BBox image.Rectangle
+
+	// Confidence is the engine's confidence in the word, from 0 to 100
+	Confidence float64
+
+	// Block, Paragraph and Line identify the text block, the paragraph
+	// within it and the line within that paragraph the word belongs to,
+	// numbered from 1 as in Tesseract's TSV output. Engines that don't
+	// report paragraphs leave Paragraph at 0.
+	Block, Paragraph, Line int
+}
+
+// Options configures a recognition.
+type Options struct {
+	// Language is the language(s) to recognize, e.g. "eng" or "eng+fra";
+	// empty means the engine's default
+	Language string
+
+	// PageSegMode is the page segmentation mode, used when PageSegModeSet
+	// is true; otherwise the engine's default applies
+	PageSegMode    PageSegMode
+	PageSegModeSet bool
+
+	// DPI is the image's resolution, a hint for engines that estimate
+	// text size; 0 means unknown
+	DPI int
+}
+
+// Engine recognizes the words in an image. The image is encoded (PNG,
+// JPEG, TIFF and so on). Implementations must be safe for concurrent use,
+// since pages are recognized in parallel, and should stop when ctx is
+// canceled. Words are returned in reading order.
+type Engine interface {
+	Recognize(ctx context.Context, img []byte, opts Options) ([]Word, error)
+}
+
+// OrientationDetector is implemented by engines that can tell which way
+// up an image's text is, such as Tesseract's orientation and script
+// detection (OSD). Image preprocessing uses it, when the engine offers
+// it, to turn pages upright before recognition.
+type OrientationDetector interface {
+	// DetectOrientation returns the clockwise rotation, 0, 90, 180 or
+	// 270 degrees, that makes the image's text upright. The image is
+	// encoded as for Recognize.
+	DetectOrientation(ctx context.Context, img []byte) (int, error)
+}
+
+// EngineFunc adapts a function to the Engine interface.
+type EngineFunc func(ctx context.Context, img []byte, opts Options) ([]Word, error)
+
+// Recognize calls f(ctx, img, opts).
+func (f EngineFunc) Recognize(ctx context.Context, img []byte, opts Options) ([]Word, error) {
+	return f(ctx, img, opts)
+}
+
+// Text assembles words into text: words on a line are separated by spaces,
+// lines by newlines and paragraphs and blocks by blank lines.
+func Text(words []Word) string {
+	var sb strings.Builder
+	for i, w := range words {
+		t := strings.TrimSpace(w.Text)
+		if t == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			prev := words[i-1]
+			switch {
+			case prev.Block != w.Block || prev.Paragraph != w.Paragraph:
+				sb.WriteString("\n\n")
+			case prev.Line != w.Line:
+				sb.WriteString("\n")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString(t)
+	}
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package ocr
+
+import (
+	"context"
+	"image"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	words := []Word{
+		{Text: "Chapter", Block: 1, Paragraph: 1, Line: 1},
+		{Text: "One", Block: 1, Paragraph: 1, Line: 1},
+		{Text: "It", Block: 2, Paragraph: 1, Line: 1},
+		{Text: "was", Block: 2, Paragraph: 1, Line: 1},
+		{Text: "dark.", Block: 2, Paragraph: 1, Line: 2},
+		{Text: " ", Block: 2, Paragraph: 1, Line: 2},
+		{Text: "Later", Block: 2, Paragraph: 2, Line: 1},
+	}
+	want := "Chapter One\n\nIt was\ndark.\n\nLater"
+	if got := Text(words); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := Text(nil); got != "" {
+		t.Errorf("Expected empty text, got %q", got)
+	}
+}
+
+func TestEngineFunc(t *testing.T) {
+	var got Options
+	var engine Engine = EngineFunc(func(ctx context.Context, img []byte, opts Options) ([]Word, error) {
+		got = opts
+		return []Word{{Text: string(img), BBox: image.Rect(0, 0, 10, 10)}}, nil
+	})
+
+	words, err := engine.Recognize(context.Background(), []byte("hello"), Options{Language: "deu", DPI: 300})
+	if err != nil {
+		t.Fatalf("Recognize failed: %v", err)
+	}
+	if len(words) != 1 || words[0].Text != "hello" {
+		t.Errorf("Expected the function's words, got %+v", words)
+	}
+	if got.Language != "deu" || got.DPI != 300 {
+		t.Errorf("Expected the options to be passed through, got %+v", got)
+	}
+}
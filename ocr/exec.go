@@ -0,0 +1,109 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ExecEngine is an Engine that runs the tesseract command-line program,
+// so OCR works without CGO or the "ocr" build tag. The image is piped to
+// tesseract on stdin and its TSV output read from stdout. It is safe for
+// concurrent use; each recognition runs its own process.
+type ExecEngine struct {
+	// Path is the tesseract executable; empty means "tesseract" looked
+	// up in PATH
+	Path string
+
+	// Args are extra arguments passed before the output config, e.g.
+	// []string{"--tessdata-dir", "/opt/tessdata"} or []string{"--oem", "1"}
+	Args []string
+}
+
+// NewExecEngine creates an engine that runs the tesseract executable at
+// path, or the one in PATH when path is empty.
+func NewExecEngine(path string) *ExecEngine {
+	return &ExecEngine{Path: path}
+}
+
+// minOSDConfidence is the least confidence tesseract's orientation
+// detection must report for DetectOrientation to trust it
+const minOSDConfidence = 2.0
+
+// Recognize runs tesseract over an image and returns the words it found.
+// Canceling ctx kills the process.
+func (e *ExecEngine) Recognize(ctx context.Context, img []byte, opts Options) ([]Word, error) {
+	out, err := e.run(ctx, img, e.args(opts))
+	if err != nil {
+		return nil, err
+	}
+	return ParseTSV(out)
+}
+
+// DetectOrientation runs tesseract's orientation and script detection
+// (--psm 0) over an image and returns the clockwise rotation that makes
+// its text upright. It needs the osd.traineddata language data, and fails
+// when tesseract finds too little text or isn't confident of the answer.
+func (e *ExecEngine) DetectOrientation(ctx context.Context, img []byte) (int, error) {
+	args := append([]string{"stdin", "stdout", "--psm", strconv.Itoa(int(PSM_OSD_ONLY))}, e.Args...)
+	out, err := e.run(ctx, img, args)
+	if err != nil {
+		return 0, err
+	}
+	rotate, confidence, err := ParseOSD(out)
+	if err != nil {
+		return 0, err
+	}
+	if confidence < minOSDConfidence {
+		return 0, fmt.Errorf("orientation confidence %.2f is too low", confidence)
+	}
+	return rotate, nil
+}
+
+// run runs tesseract with the given arguments and an image on stdin, and
+// returns its output
+func (e *ExecEngine) run(ctx context.Context, img []byte, args []string) ([]byte, error) {
+	bin := e.Path
+	if bin == "" {
+		bin = "tesseract"
+	}
+	path, err := exec.LookPath(bin)
+	if err != nil {
+		return nil, fmt.Errorf("tesseract not found: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = bytes.NewReader(img)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("tesseract failed: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("tesseract failed: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// args builds the tesseract command line for a recognition
+func (e *ExecEngine) args(opts Options) []string {
+	args := []string{"stdin", "stdout"}
+	if opts.Language != "" {
+		args = append(args, "-l", opts.Language)
+	}
+	if opts.PageSegModeSet {
+		args = append(args, "--psm", strconv.Itoa(int(opts.PageSegMode)))
+	}
+	if opts.DPI > 0 {
+		args = append(args, "--dpi", strconv.Itoa(opts.DPI))
+	}
+	args = append(args, e.Args...)
+	return append(args, "tsv")
+}
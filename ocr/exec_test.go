@@ -0,0 +1,95 @@
+package ocr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestExecEngineArgs(t *testing.T) {
+	e := &ExecEngine{Args: []string{"--oem", "1"}}
+
+	got := e.args(Options{Language: "eng+fra", PageSegMode: PSM_SINGLE_COLUMN, PageSegModeSet: true, DPI: 300})
+	want := []string{"stdin", "stdout", "-l", "eng+fra", "--psm", "4", "--dpi", "300", "--oem", "1", "tsv"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	got = e.args(Options{})
+	want = []string{"stdin", "stdout", "--oem", "1", "tsv"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// The zero mode, PSM_OSD_ONLY, is passed on when set explicitly
+	got = e.args(Options{PageSegModeSet: true})
+	want = []string{"stdin", "stdout", "--psm", "0", "--oem", "1", "tsv"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// fakeTesseract writes a script that checks it was given an image on stdin
+// and prints TSV output, standing in for the tesseract program
+func fakeTesseract(t *testing.T, output string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("Shell scripts are not executable on Windows")
+	}
+	tsv := filepath.Join(t.TempDir(), "out.tsv")
+	if err := os.WriteFile(tsv, []byte(output), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(t.TempDir(), "tesseract")
+	body := "#!/bin/sh\n" +
+		"[ \"$(cat)\" = \"image\" ] || { echo 'no image' >&2; exit 1; }\n" +
+		"cat '" + tsv + "'\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestExecEngineRecognize(t *testing.T) {
+	e := NewExecEngine(fakeTesseract(t, sampleTSV))
+
+	words, err := e.Recognize(context.Background(), []byte("image"), Options{Language: "eng"})
+	if err != nil {
+		t.Fatalf("Recognize failed: %v", err)
+	}
+	if got := Text(words); got != "Hello world\n\nAgain" {
+		t.Errorf("Expected the TSV's words, got %q", got)
+	}
+
+	_, err = e.Recognize(context.Background(), []byte("other"), Options{})
+	if err == nil || !strings.Contains(err.Error(), "no image") {
+		t.Errorf("Expected the program's error output, got %v", err)
+	}
+}
+
+func TestExecEngineDetectOrientation(t *testing.T) {
+	e := NewExecEngine(fakeTesseract(t, sampleOSD))
+	rotate, err := e.DetectOrientation(context.Background(), []byte("image"))
+	if err != nil {
+		t.Fatalf("DetectOrientation failed: %v", err)
+	}
+	if rotate != 90 {
+		t.Errorf("Expected rotation 90, got %d", rotate)
+	}
+
+	e = NewExecEngine(fakeTesseract(t, "Rotate: 180\nOrientation confidence: 0.40\n"))
+	if _, err := e.DetectOrientation(context.Background(), []byte("image")); err == nil {
+		t.Error("Expected an error for a low confidence")
+	}
+}
+
+func TestExecEngineNotFound(t *testing.T) {
+	e := NewExecEngine(filepath.Join(t.TempDir(), "missing-tesseract"))
+	if _, err := e.Recognize(context.Background(), []byte("image"), Options{}); err == nil {
+		t.Error("Expected an error for a missing program")
+	}
+}
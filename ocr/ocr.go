@@ -17,6 +17,14 @@
 //	go build -tags ocr
 //
 // Without the build tag, OCR functions return ErrOCRNotEnabled.
+//
+// Engine is the interface between tabula and an OCR engine: it recognizes
+// the words of an image, with their bounding boxes, confidences and block,
+// paragraph and line numbers. TesseractEngine uses the Tesseract library and
+// needs the build tag; ExecEngine runs the tesseract program, so it needs
+// neither CGO nor the tag; other engines can implement the interface, or
+// wrap a function with EngineFunc. ParseTSV and ParseHOCR read Tesseract's
+// TSV and hOCR output.
 package ocr
 
 import (
@@ -102,3 +110,34 @@ func (c *Client) SetPageSegMode(mode PageSegMode) error {
 func (c *Client) SetVariable(name, value string) error {
 	return c.client.SetVariable(gosseract.SettableVariable(name), value)
 }
+
+// RecognizeWords performs OCR on image data and returns the recognized
+// words with their bounding boxes, confidences and block, paragraph and
+// line numbers.
+func (c *Client) RecognizeWords(imageData []byte) ([]Word, error) {
+	if err := c.client.SetImageFromBytes(imageData); err != nil {
+		return nil, fmt.Errorf("failed to set image: %w", err)
+	}
+
+	boxes, err := c.client.GetBoundingBoxesVerbose()
+	if err != nil {
+		return nil, fmt.Errorf("OCR failed: %w", err)
+	}
+
+	words := make([]Word, 0, len(boxes))
+	for _, b := range boxes {
+		text := strings.TrimSpace(b.Word)
+		if text == "" {
+			continue
+		}
+		words = append(words, Word{
+			Text:       text,
+			BBox:       b.Box,
+			Confidence: b.Confidence,
+			Block:      b.BlockNum,
+			Paragraph:  b.ParNum,
+			Line:       b.LineNum,
+		})
+	}
+	return words, nil
+}
@@ -4,7 +4,9 @@
 // for extracting text from images in scanned PDFs.
 //
 // This is the stub implementation used when the "ocr" build tag is not set.
-// All functions return ErrOCRNotEnabled.
+// The Tesseract library client and engine return ErrOCRNotEnabled; the
+// Engine interface, ExecEngine (which runs the tesseract program) and the
+// TSV and hOCR parsers work in either build.
 //
 // To enable OCR, rebuild with the "ocr" build tag:
 //
@@ -19,7 +21,10 @@
 //	apt-get install tesseract-ocr
 package ocr
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 // ErrOCRNotEnabled is returned when OCR functions are called but OCR support
 // was not compiled in. Rebuild with -tags ocr to enable OCR support.
@@ -81,3 +86,28 @@ func (c *Client) SetPageSegMode(mode PageSegMode) error {
 func (c *Client) SetVariable(name, value string) error {
 	return ErrOCRNotEnabled
 }
+
+// RecognizeWords returns an error indicating OCR support is not enabled.
+func (c *Client) RecognizeWords(imageData []byte) ([]Word, error) {
+	return nil, ErrOCRNotEnabled
+}
+
+// TesseractEngine is a stub engine; OCR support is not enabled.
+type TesseractEngine struct{}
+
+// NewTesseractEngine returns an error indicating OCR support is not enabled.
+// To enable OCR, rebuild with: go build -tags ocr
+func NewTesseractEngine() (*TesseractEngine, error) {
+	return nil, ErrOCRNotEnabled
+}
+
+// Recognize returns an error indicating OCR support is not enabled.
+func (t *TesseractEngine) Recognize(ctx context.Context, img []byte, opts Options) ([]Word, error) {
+	return nil, ErrOCRNotEnabled
+}
+
+// Close is a no-op for the stub engine.
+// It is safe to call on a nil engine.
+func (t *TesseractEngine) Close() error {
+	return nil
+}
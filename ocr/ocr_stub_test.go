@@ -27,3 +27,16 @@ func TestCloseOnNilClient(t *testing.T) {
 		t.Errorf("Close on nil client should not error: %v", err)
 	}
 }
+
+func TestNewTesseractEngineReturnsError(t *testing.T) {
+	engine, err := NewTesseractEngine()
+	if !errors.Is(err, ErrOCRNotEnabled) {
+		t.Errorf("Expected ErrOCRNotEnabled, got: %v", err)
+	}
+	if engine != nil {
+		t.Error("Expected nil engine when OCR is disabled")
+	}
+	if err := engine.Close(); err != nil {
+		t.Errorf("Close on nil engine should not error: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package ocr
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// tsvWordLevel is the level of word rows in Tesseract's TSV output
+const tsvWordLevel = 5
+
+// ParseTSV reads the words from Tesseract's TSV output (the "tsv" config,
+// or TessBaseAPI::GetTSVText). Rows other than words, and words with no
+// text, are skipped.
+func ParseTSV(data []byte) ([]Word, error) {
+	var words []Word
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		row := scanner.Text()
+		if line == 1 && strings.HasPrefix(row, "level") {
+			continue // Header
+		}
+		cols := strings.SplitN(row, "\t", 12)
+		if len(cols) < 11 {
+			continue
+		}
+		var n [10]int
+		for i := range n {
+			v, err := strconv.Atoi(strings.TrimSpace(cols[i]))
+			if err != nil {
+				return nil, fmt.Errorf("TSV line %d: column %d: %w", line, i+1, err)
+			}
+			n[i] = v
+		}
+		if n[0] != tsvWordLevel || len(cols) < 12 || strings.TrimSpace(cols[11]) == "" {
+			continue
+		}
+		conf, err := strconv.ParseFloat(strings.TrimSpace(cols[10]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("TSV line %d: confidence: %w", line, err)
+		}
+		words = append(words, Word{
+			Text:       strings.TrimSpace(cols[11]),
+			BBox:       image.Rect(n[6], n[7], n[6]+n[8], n[7]+n[9]),
+			Confidence: conf,
+			Block:      n[2],
+			Paragraph:  n[3],
+			Line:       n[4],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// ParseOSD reads the result of Tesseract's orientation and script
+// detection (--psm 0): the clockwise rotation, in degrees, that makes the
+// image's text upright (its "Rotate" line) and the confidence in it.
+func ParseOSD(data []byte) (rotate int, confidence float64, err error) {
+	found := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, val, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		switch strings.TrimSpace(key) {
+		case "Rotate":
+			if rotate, err = strconv.Atoi(val); err != nil {
+				return 0, 0, fmt.Errorf("OSD rotation: %w", err)
+			}
+			found = true
+		case "Orientation confidence":
+			if confidence, err = strconv.ParseFloat(val, 64); err != nil {
+				return 0, 0, fmt.Errorf("OSD confidence: %w", err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return 0, 0, fmt.Errorf("no rotation in OSD output")
+	}
+	return rotate, confidence, nil
+}
+
+// hOCR element classes that start a line
+var hocrLineClasses = map[string]bool{
+	"ocr_line":      true,
+	"ocr_textfloat": true,
+	"ocr_header":    true,
+	"ocr_caption":   true,
+}
+
+// ParseHOCR reads the words (ocrx_word elements) from hOCR output, as
+// written by Tesseract's "hocr" config and other OCR engines. Blocks
+// (ocr_carea), paragraphs (ocr_par) and lines are numbered in document
+// order like Tesseract's TSV output: paragraphs within their block and
+// lines within their paragraph.
+func ParseHOCR(data []byte) ([]Word, error) {
+	var words []Word
+	var block, par, line int
+
+	// The word being read, and the depth of its element
+	var word *Word
+	var text strings.Builder
+	depth, wordDepth := 0, 0
+
+	z := html.NewTokenizer(bytes.NewReader(data))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return nil, err
+			}
+			return words, nil
+
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			if !isVoidElement(string(name)) {
+				depth++
+			}
+			class, title := hocrAttrs(z)
+			switch {
+			case class == "ocr_carea":
+				block++
+				par, line = 0, 0
+			case class == "ocr_par":
+				par++
+				line = 0
+			case hocrLineClasses[class]:
+				line++
+			case class == "ocrx_word" && word == nil:
+				word = &Word{Block: block, Paragraph: par, Line: line}
+				word.BBox, word.Confidence = hocrTitle(title)
+				text.Reset()
+				wordDepth = depth
+			}
+
+		case html.EndTagToken:
+			if word != nil && depth == wordDepth {
+				word.Text = strings.TrimSpace(text.String())
+				if word.Text != "" {
+					words = append(words, *word)
+				}
+				word = nil
+			}
+			depth--
+
+		case html.TextToken:
+			if word != nil {
+				text.Write(z.Text())
+			}
+		}
+	}
+}
+
+// hocrAttrs returns the class and title attributes of the current tag
+func hocrAttrs(z *html.Tokenizer) (class, title string) {
+	for {
+		key, val, more := z.TagAttr()
+		switch string(key) {
+		case "class":
+			class = string(val)
+		case "title":
+			title = string(val)
+		}
+		if !more {
+			return class, title
+		}
+	}
+}
+
+// hocrTitle reads the bbox and x_wconf properties of an hOCR title
+// attribute, e.g. "bbox 36 92 96 116; x_wconf 93"
+func hocrTitle(title string) (image.Rectangle, float64) {
+	var box image.Rectangle
+	conf := 0.0
+	for _, prop := range strings.Split(title, ";") {
+		fields := strings.Fields(prop)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "bbox":
+			if len(fields) == 5 {
+				var n [4]int
+				ok := true
+				for i := range n {
+					v, err := strconv.Atoi(fields[i+1])
+					if err != nil {
+						ok = false
+						break
+					}
+					n[i] = v
+				}
+				if ok {
+					box = image.Rect(n[0], n[1], n[2], n[3])
+				}
+			}
+		case "x_wconf":
+			if len(fields) == 2 {
+				if v, err := strconv.ParseFloat(fields[1], 64); err == nil {
+					conf = v
+				}
+			}
+		}
+	}
+	return box, conf
+}
+
+// isVoidElement reports whether an HTML element has no end tag
+func isVoidElement(name string) bool {
+	switch name {
+	case "area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr":
+		return true
+	}
+	return false
+}
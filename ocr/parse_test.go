@@ -0,0 +1,117 @@
+package ocr
+
+import (
+	"image"
+	"testing"
+)
+
+const sampleTSV = "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+	"1\t1\t0\t0\t0\t0\t0\t0\t2550\t3300\t-1\t\n" +
+	"2\t1\t1\t0\t0\t0\t300\t290\t900\t60\t-1\t\n" +
+	"3\t1\t1\t1\t0\t0\t300\t290\t900\t60\t-1\t\n" +
+	"4\t1\t1\t1\t1\t0\t300\t290\t900\t60\t-1\t\n" +
+	"5\t1\t1\t1\t1\t1\t300\t290\t400\t60\t96.5\tHello\n" +
+	"5\t1\t1\t1\t1\t2\t720\t292\t480\t58\t91\tworld\n" +
+	"5\t1\t1\t1\t1\t3\t1210\t292\t10\t58\t95\t \n" +
+	"5\t1\t2\t1\t1\t1\t300\t500\t200\t50\t88\tAgain\n"
+
+func TestParseTSV(t *testing.T) {
+	words, err := ParseTSV([]byte(sampleTSV))
+	if err != nil {
+		t.Fatalf("ParseTSV failed: %v", err)
+	}
+	if len(words) != 3 {
+		t.Fatalf("Expected 3 words, got %d: %+v", len(words), words)
+	}
+	want := Word{Text: "Hello", BBox: image.Rect(300, 290, 700, 350), Confidence: 96.5, Block: 1, Paragraph: 1, Line: 1}
+	if words[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, words[0])
+	}
+	if words[2].Text != "Again" || words[2].Block != 2 {
+		t.Errorf("Expected Again in block 2, got %+v", words[2])
+	}
+	if got := Text(words); got != "Hello world\n\nAgain" {
+		t.Errorf("Expected assembled text, got %q", got)
+	}
+}
+
+func TestParseTSVInvalid(t *testing.T) {
+	if _, err := ParseTSV([]byte("5\t1\tx\t1\t1\t1\t0\t0\t1\t1\t90\tword\n")); err == nil {
+		t.Error("Expected an error for a non-numeric column")
+	}
+	words, err := ParseTSV(nil)
+	if err != nil || len(words) != 0 {
+		t.Errorf("Expected no words for empty output, got %v, %v", words, err)
+	}
+}
+
+const sampleHOCR = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml">
+ <head><meta name="ocr-system" content="tesseract" /></head>
+ <body>
+  <div class='ocr_page' id='page_1' title='image "scan.png"; bbox 0 0 2550 3300'>
+   <div class='ocr_carea' id='block_1_1' title="bbox 300 290 1200 350">
+    <p class='ocr_par' id='par_1_1'>
+     <span class='ocr_line' id='line_1_1' title="bbox 300 290 1200 350; baseline 0 -10">
+      <span class='ocrx_word' id='word_1_1' title='bbox 300 290 700 350; x_wconf 96'><strong>Hello</strong></span>
+      <span class='ocrx_word' id='word_1_2' title='bbox 720 292 1200 350; x_wconf 91'>world&amp;co</span>
+     </span>
+     <span class='ocr_line' id='line_1_2' title="bbox 300 360 500 410">
+      <span class='ocrx_word' id='word_1_3' title='bbox 300 360 500 410; x_wconf 88'>Again</span>
+     </span>
+    </p>
+   </div>
+   <div class='ocr_carea' id='block_1_2'>
+    <p class='ocr_par'><span class='ocr_line'><span class='ocrx_word' title='bbox 1 2 3 4'>Next</span></span></p>
+   </div>
+  </div>
+ </body>
+</html>`
+
+const sampleOSD = "Page number: 0\n" +
+	"Orientation in degrees: 270\n" +
+	"Rotate: 90\n" +
+	"Orientation confidence: 7.12\n" +
+	"Script: Latin\n" +
+	"Script confidence: 2.54\n"
+
+func TestParseOSD(t *testing.T) {
+	rotate, confidence, err := ParseOSD([]byte(sampleOSD))
+	if err != nil {
+		t.Fatalf("ParseOSD failed: %v", err)
+	}
+	if rotate != 90 || confidence != 7.12 {
+		t.Errorf("Expected rotation 90 with confidence 7.12, got %d with %.2f", rotate, confidence)
+	}
+
+	if _, _, err := ParseOSD([]byte("Too few characters. Skipping this page\n")); err == nil {
+		t.Error("Expected an error for output without a rotation")
+	}
+	if _, _, err := ParseOSD([]byte("Rotate: left\n")); err == nil {
+		t.Error("Expected an error for a non-numeric rotation")
+	}
+}
+
+func TestParseHOCR(t *testing.T) {
+	words, err := ParseHOCR([]byte(sampleHOCR))
+	if err != nil {
+		t.Fatalf("ParseHOCR failed: %v", err)
+	}
+	if len(words) != 4 {
+		t.Fatalf("Expected 4 words, got %d: %+v", len(words), words)
+	}
+	want := Word{Text: "Hello", BBox: image.Rect(300, 290, 700, 350), Confidence: 96, Block: 1, Paragraph: 1, Line: 1}
+	if words[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, words[0])
+	}
+	if words[1].Text != "world&co" {
+		t.Errorf("Expected entities to be decoded, got %q", words[1].Text)
+	}
+	if words[2].Line != 2 || words[3].Block != 2 || words[3].Line != 1 {
+		t.Errorf("Expected line and block numbering, got %+v and %+v", words[2], words[3])
+	}
+	if got := Text(words); got != "Hello world&co\nAgain\n\nNext" {
+		t.Errorf("Expected assembled text, got %q", got)
+	}
+}
@@ -0,0 +1,172 @@
+package preprocess
+
+import (
+	"image"
+	"math"
+)
+
+// Sauvola's parameters: the sensitivity to the neighborhood's deviation,
+// and the deviation's range for 8-bit images
+const (
+	sauvolaK = 0.34
+	sauvolaR = 128.0
+)
+
+// mask marks an image's dark (ink) pixels
+type mask struct {
+	w, h int
+	dark []bool // Row by row
+}
+
+// Otsu returns the threshold that best separates an image's dark pixels
+// from its light ones by Otsu's method: the gray level maximizing the
+// variance between the two classes. Pixels at or below it are dark.
+func Otsu(g *image.Gray) uint8 {
+	var hist [256]int
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	for y := 0; y < h; y++ {
+		for _, v := range g.Pix[y*g.Stride : y*g.Stride+w] {
+			hist[v]++
+		}
+	}
+
+	total := w * h
+	var sum float64
+	for i, n := range hist {
+		sum += float64(i * n)
+	}
+	var sumDark float64
+	var nDark int
+	best, bestVar := 0, -1.0
+	for t := 0; t < 256; t++ {
+		nDark += hist[t]
+		if nDark == 0 {
+			continue
+		}
+		nLight := total - nDark
+		if nLight == 0 {
+			break
+		}
+		sumDark += float64(t * hist[t])
+		meanDark := sumDark / float64(nDark)
+		meanLight := (sum - sumDark) / float64(nLight)
+		v := float64(nDark) * float64(nLight) * (meanDark - meanLight) * (meanDark - meanLight)
+		if v > bestVar {
+			best, bestVar = t, v
+		}
+	}
+	return uint8(best)
+}
+
+// otsuMask marks the pixels at or below g's Otsu threshold. An image of a
+// single gray level has no ink.
+func otsuMask(g *image.Gray) mask {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	m := mask{w: w, h: h, dark: make([]bool, w*h)}
+	lo, hi := uint8(255), uint8(0)
+	for y := 0; y < h; y++ {
+		for _, v := range g.Pix[y*g.Stride : y*g.Stride+w] {
+			if v < lo {
+				lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+	}
+	if lo == hi {
+		return m
+	}
+	t := Otsu(g)
+	for y := 0; y < h; y++ {
+		for x, v := range g.Pix[y*g.Stride : y*g.Stride+w] {
+			m.dark[y*w+x] = v <= t
+		}
+	}
+	return m
+}
+
+// sauvolaMask marks the pixels darker than their Sauvola threshold, the
+// mean of their neighborhood lowered where it varies little:
+// T = mean × (1 + k × (deviation / R − 1)). The neighborhood is a square
+// about a hundredth of the image's smaller side across, at least 15
+// pixels; sums over it come from integral images.
+func sauvolaMask(g *image.Gray) mask {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	m := mask{w: w, h: h, dark: make([]bool, w*h)}
+
+	r := w
+	if h < r {
+		r = h
+	}
+	r /= 200
+	if r < 7 {
+		r = 7
+	}
+
+	// Integral images of the values and their squares, with a zero row
+	// and column in front
+	sums := make([]float64, (w+1)*(h+1))
+	squares := make([]float64, (w+1)*(h+1))
+	for y := 0; y < h; y++ {
+		var rowSum, rowSquares float64
+		for x := 0; x < w; x++ {
+			v := float64(g.Pix[y*g.Stride+x])
+			rowSum += v
+			rowSquares += v * v
+			i := (y+1)*(w+1) + x + 1
+			sums[i] = sums[i-(w+1)] + rowSum
+			squares[i] = squares[i-(w+1)] + rowSquares
+		}
+	}
+	area := func(t []float64, x0, y0, x1, y1 int) float64 {
+		return t[y1*(w+1)+x1] - t[y0*(w+1)+x1] - t[y1*(w+1)+x0] + t[y0*(w+1)+x0]
+	}
+
+	for y := 0; y < h; y++ {
+		y0, y1 := clampInt(y-r, 0, h), clampInt(y+r+1, 0, h)
+		for x := 0; x < w; x++ {
+			x0, x1 := clampInt(x-r, 0, w), clampInt(x+r+1, 0, w)
+			n := float64((x1 - x0) * (y1 - y0))
+			mean := area(sums, x0, y0, x1, y1) / n
+			variance := area(squares, x0, y0, x1, y1)/n - mean*mean
+			dev := math.Sqrt(math.Max(variance, 0))
+			t := mean * (1 + sauvolaK*(dev/sauvolaR-1))
+			m.dark[y*w+x] = float64(g.Pix[y*g.Stride+x]) <= t
+		}
+	}
+	return m
+}
+
+// binarize paints g's dark pixels black and the rest white
+func binarize(g *image.Gray, m mask) {
+	for y := 0; y < m.h; y++ {
+		for x := 0; x < m.w; x++ {
+			if m.dark[y*m.w+x] {
+				g.Pix[y*g.Stride+x] = 0
+			} else {
+				g.Pix[y*g.Stride+x] = 255
+			}
+		}
+	}
+}
+
+// whiten paints the pixels at the given mask indexes white and returns
+// how many there were
+func whiten(g *image.Gray, pixels []int) int {
+	w := g.Rect.Dx()
+	for _, i := range pixels {
+		g.Pix[(i/w)*g.Stride+i%w] = 255
+	}
+	return len(pixels)
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
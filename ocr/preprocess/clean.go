@@ -0,0 +1,69 @@
+package preprocess
+
+// borderPixels returns the indexes of the dark pixels connected to the
+// mask's edges: the dark margins left by a scanner lid, the edge of the
+// paper or a photocopy's shadow
+func borderPixels(m mask) []int {
+	seen := make([]bool, len(m.dark))
+	var border []int
+	fill := func(x, y int) {
+		if i := y*m.w + x; m.dark[i] && !seen[i] {
+			border = append(border, m.component(i, seen, -1)...)
+		}
+	}
+	for x := 0; x < m.w; x++ {
+		fill(x, 0)
+		fill(x, m.h-1)
+	}
+	for y := 0; y < m.h; y++ {
+		fill(0, y)
+		fill(m.w-1, y)
+	}
+	return border
+}
+
+// specks returns the indexes of the pixels of dark components of at most
+// size pixels
+func specks(m mask, size int) []int {
+	seen := make([]bool, len(m.dark))
+	var out []int
+	for i, d := range m.dark {
+		if d && !seen[i] {
+			if c := m.component(i, seen, size); len(c) <= size {
+				out = append(out, c...)
+			}
+		}
+	}
+	return out
+}
+
+// component marks the 8-connected dark component containing pixel i as
+// seen and returns its pixels. With limit >= 0, it stops collecting (but
+// not marking) pixels past limit+1, which is enough to tell a component is
+// larger than limit.
+func (m mask) component(i int, seen []bool, limit int) []int {
+	var pixels []int
+	stack := []int{i}
+	seen[i] = true
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if limit < 0 || len(pixels) <= limit {
+			pixels = append(pixels, p)
+		}
+		x, y := p%m.w, p/m.w
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				nx, ny := x+dx, y+dy
+				if nx < 0 || ny < 0 || nx >= m.w || ny >= m.h {
+					continue
+				}
+				if n := ny*m.w + nx; m.dark[n] && !seen[n] {
+					seen[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+	}
+	return pixels
+}
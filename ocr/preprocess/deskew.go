@@ -0,0 +1,104 @@
+package preprocess
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+const (
+	// maxSkewPoints bounds the ink pixels DetectSkew projects; larger
+	// images are sampled
+	maxSkewPoints = 200000
+
+	// minSkewPoints is the least ink DetectSkew needs to find lines
+	minSkewPoints = 100
+
+	// Angle steps, in degrees, of DetectSkew's coarse and fine searches
+	skewCoarseStep = 0.5
+	skewFineStep   = 0.05
+)
+
+// DetectSkew returns the angle, in degrees clockwise, by which an image's
+// text lines are tilted, searching up to maxSkew degrees either way. It
+// projects the image's ink along each candidate angle and picks the angle
+// whose profile is most sharply peaked, as it is when the projection runs
+// along the lines. It returns 0 for an image with too little ink.
+func DetectSkew(img image.Image, maxSkew float64) float64 {
+	m := otsuMask(toGray(img))
+
+	var n int
+	for _, d := range m.dark {
+		if d {
+			n++
+		}
+	}
+	if n < minSkewPoints || maxSkew <= 0 {
+		return 0
+	}
+	stride := (n + maxSkewPoints - 1) / maxSkewPoints
+	xs := make([]float64, 0, n/stride+1)
+	ys := make([]float64, 0, n/stride+1)
+	k := 0
+	for i, d := range m.dark {
+		if !d {
+			continue
+		}
+		if k%stride == 0 {
+			xs = append(xs, float64(i%m.w))
+			ys = append(ys, float64(i/m.w))
+		}
+		k++
+	}
+
+	// The projection of each point along angle a is y - x tan(a), which
+	// is offset to index the histogram
+	offset := float64(m.w)*math.Tan(maxSkew*math.Pi/180) + 1
+	hist := make([]float64, m.h+2*int(offset)+2)
+	score := func(deg float64) float64 {
+		for i := range hist {
+			hist[i] = 0
+		}
+		t := math.Tan(deg * math.Pi / 180)
+		for i := range xs {
+			b := int(ys[i] - xs[i]*t + offset)
+			if b >= 0 && b < len(hist) {
+				hist[b]++
+			}
+		}
+		var s float64
+		for _, v := range hist {
+			s += v * v
+		}
+		return s
+	}
+
+	// A coarse search, then a finer one around its best angle; ties go to
+	// the angle nearest 0
+	best, bestScore := 0.0, score(0)
+	search := func(center, span, step float64) {
+		for i := 1; float64(i)*step <= span+1e-9; i++ {
+			for _, deg := range []float64{center + float64(i)*step, center - float64(i)*step} {
+				if math.Abs(deg) > maxSkew+1e-9 {
+					continue
+				}
+				if s := score(deg); s > bestScore {
+					best, bestScore = deg, s
+				}
+			}
+		}
+	}
+	search(0, maxSkew, skewCoarseStep)
+	search(best, skewCoarseStep, skewFineStep)
+	return math.Round(best/skewFineStep) * skewFineStep
+}
+
+// toGray returns img as a grayscale image with its origin at (0, 0)
+func toGray(img image.Image) *image.Gray {
+	if g, ok := img.(*image.Gray); ok && g.Rect.Min == (image.Point{}) {
+		return g
+	}
+	g := image.NewGray(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(g, g.Rect, img, img.Bounds().Min, draw.Src)
+	return g
+}
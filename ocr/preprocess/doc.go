@@ -0,0 +1,29 @@
+// Package preprocess cleans up scanned page images before OCR.
+//
+// Phone-camera scans and faxes are often skewed by a few degrees, noisy,
+// unevenly lit or upside down, and OCR accuracy collapses on them.
+// [Process] runs an image through a pipeline of corrections, each enabled
+// in [Options]:
+//
+//   - Orientation turns the image by 0, 90, 180 or 270 degrees so its text
+//     is upright, as found by an [OrientationDetector] (such as Tesseract's
+//     orientation and script detection) or by [DetectOrientation], which
+//     compares the ink above and below the text lines.
+//   - Deskew straightens text lines tilted by up to a few degrees, at the
+//     angle [DetectSkew] finds from projection profiles.
+//   - Binarize turns the image black and white, with a single threshold
+//     (Otsu's method) or a threshold per pixel (Sauvola's method), which
+//     copes with shadows and uneven lighting.
+//   - RemoveBorders whitens dark regions connected to the image's edges,
+//     such as the shadow of a scanner lid or the edge of the paper.
+//   - Despeckle whitens specks of noise too small to be part of a letter.
+//
+// The result is a grayscale image, with a matrix mapping its pixels back
+// to the input image, so positions found in it (the boxes of recognized
+// words) can be placed on the original, and a description of the steps
+// that changed the image:
+//
+//	res := preprocess.Process(img, preprocess.DefaultOptions())
+//	fmt.Println(strings.Join(res.Applied, ", ")) // e.g. "deskewed 2.1°, binarized (Sauvola)"
+//	png.Encode(w, res.Image)
+package preprocess
@@ -0,0 +1,155 @@
+package preprocess
+
+import (
+	"image"
+	"math"
+)
+
+const (
+	// verticalTextRatio is how much more the column profile must vary than
+	// the row profile for text to be taken as running vertically
+	verticalTextRatio = 1.3
+
+	// upsideDownRatio is how much more ink must lie on one side of the
+	// text lines' x-height band than the other to decide which way is up
+	upsideDownRatio = 1.2
+
+	// minOrientationLines is the fewest text lines orientation is decided
+	// from
+	minOrientationLines = 2
+)
+
+// DetectOrientation returns the clockwise rotation, 0, 90, 180 or 270
+// degrees, that makes an image's text upright, judged from its text lines.
+// Lines of text make the ink's row profile alternate between lines and
+// gaps; when the column profile alternates more, the text runs vertically.
+// Latin text has more ink above its lines' x-height band (capitals and
+// ascenders) than below it (descenders), so more ink below means the text
+// is upside down. Slightly skewed lines are straightened first. When the
+// evidence is weak it returns 0.
+func DetectOrientation(img image.Image) int {
+	g := toGray(img)
+	rows, cols := otsuMask(straighten(g)).profiles()
+	if variation(cols) > variation(rows)*verticalTextRatio {
+		// Sideways: turned clockwise by 90 the text is upright or upside down
+		rows, _ = otsuMask(straighten(turn(g, 90))).profiles()
+		switch upsideDown(rows) {
+		case 1:
+			return 270
+		case -1:
+			return 90
+		}
+		return 0
+	}
+	if upsideDown(rows) == 1 {
+		return 180
+	}
+	return 0
+}
+
+// straighten returns g with any skew up to DefaultMaxSkew removed
+func straighten(g *image.Gray) *image.Gray {
+	if skew := DetectSkew(g, DefaultMaxSkew); math.Abs(skew) >= minSkew {
+		g, _ = rotate(g, skew)
+	}
+	return g
+}
+
+// profiles returns the number of dark pixels in each row and column
+func (m mask) profiles() (rows, cols []int) {
+	rows, cols = make([]int, m.h), make([]int, m.w)
+	for i, d := range m.dark {
+		if d {
+			rows[i/m.w]++
+			cols[i%m.w]++
+		}
+	}
+	return rows, cols
+}
+
+// variation returns the coefficient of variation (deviation over mean) of
+// a profile between its first and last inked entries
+func variation(p []int) float64 {
+	first, last := -1, -1
+	for i, v := range p {
+		if v > 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return 0
+	}
+	p = p[first : last+1]
+	var sum, squares float64
+	for _, v := range p {
+		sum += float64(v)
+		squares += float64(v) * float64(v)
+	}
+	n := float64(len(p))
+	mean := sum / n
+	return math.Sqrt(math.Max(squares/n-mean*mean, 0)) / mean
+}
+
+// upsideDown judges from a row profile whether text lines are upright
+// (-1), upside down (1) or can't be told (0). Each line is a run of inked
+// rows; its x-height band is the rows with at least half the line's peak
+// ink, and the ink above and below the band is summed over all lines.
+func upsideDown(rows []int) int {
+	peak := 0
+	for _, v := range rows {
+		if v > peak {
+			peak = v
+		}
+	}
+	if peak == 0 {
+		return 0
+	}
+	gap := peak / 50 // Rows with less ink are taken as blank
+
+	var above, below, lines int
+	for i := 0; i < len(rows); {
+		if rows[i] <= gap {
+			i++
+			continue
+		}
+		start := i
+		linePeak := 0
+		for ; i < len(rows) && rows[i] > gap; i++ {
+			if rows[i] > linePeak {
+				linePeak = rows[i]
+			}
+		}
+		if i-start < 4 {
+			continue // Too thin to be a line of text
+		}
+		bandTop, bandBottom := -1, -1
+		for j := start; j < i; j++ {
+			if rows[j]*2 >= linePeak {
+				if bandTop < 0 {
+					bandTop = j
+				}
+				bandBottom = j
+			}
+		}
+		for j := start; j < bandTop; j++ {
+			above += rows[j]
+		}
+		for j := bandBottom + 1; j < i; j++ {
+			below += rows[j]
+		}
+		lines++
+	}
+
+	switch {
+	case lines < minOrientationLines:
+		return 0
+	case float64(below) > float64(above)*upsideDownRatio:
+		return 1
+	case float64(above) > float64(below)*upsideDownRatio:
+		return -1
+	}
+	return 0
+}
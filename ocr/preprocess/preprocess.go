@@ -0,0 +1,290 @@
+package preprocess
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/tsawler/tabula/model"
+)
+
+// Binarization selects how an image is turned black and white.
+type Binarization int
+
+const (
+	// BinarizeNone keeps the image's shades of gray
+	BinarizeNone Binarization = iota
+
+	// BinarizeOtsu uses one threshold for the whole image, chosen from its
+	// histogram by Otsu's method. Fast, and right for evenly lit scans.
+	BinarizeOtsu
+
+	// BinarizeSauvola uses a threshold for each pixel from the mean and
+	// deviation of its neighborhood (Sauvola's method), so shadows, stains
+	// and uneven lighting don't swallow the text.
+	BinarizeSauvola
+)
+
+// String returns the method's name.
+func (b Binarization) String() string {
+	switch b {
+	case BinarizeOtsu:
+		return "Otsu"
+	case BinarizeSauvola:
+		return "Sauvola"
+	default:
+		return "none"
+	}
+}
+
+const (
+	// DefaultMaxSkew is the largest skew, in degrees, Deskew looks for when
+	// Options.MaxSkew is not set
+	DefaultMaxSkew = 5.0
+
+	// DefaultSpeckleSize is the size, in pixels, of the largest speck
+	// Despeckle removes when Options.SpeckleSize is not set. A period or
+	// the dot of an i is several times larger at 300 DPI.
+	DefaultSpeckleSize = 6
+
+	// minSkew is the smallest skew, in degrees, worth correcting
+	minSkew = 0.1
+)
+
+// OrientationDetector finds the orientation of an image's text.
+type OrientationDetector interface {
+	// DetectOrientation returns the clockwise rotation, 0, 90, 180 or 270
+	// degrees, that makes the image's text upright.
+	DetectOrientation(img image.Image) (int, error)
+}
+
+// Options selects the steps of the pipeline. The zero value changes
+// nothing but converting the image to grayscale.
+type Options struct {
+	// Orientation turns the image so its text is upright
+	Orientation bool
+
+	// OrientationDetector finds the orientation; nil, or a detector that
+	// fails, means DetectOrientation
+	OrientationDetector OrientationDetector
+
+	// Deskew straightens text lines tilted by up to MaxSkew degrees
+	// (DefaultMaxSkew if 0)
+	Deskew  bool
+	MaxSkew float64
+
+	// Binarize turns the image black and white
+	Binarize Binarization
+
+	// RemoveBorders whitens dark regions connected to the image's edges
+	RemoveBorders bool
+
+	// Despeckle whitens specks of at most SpeckleSize pixels
+	// (DefaultSpeckleSize if 0)
+	Despeckle   bool
+	SpeckleSize int
+}
+
+// DefaultOptions returns options with every step enabled and Sauvola
+// binarization.
+func DefaultOptions() Options {
+	return Options{
+		Orientation:   true,
+		Deskew:        true,
+		Binarize:      BinarizeSauvola,
+		RemoveBorders: true,
+		Despeckle:     true,
+	}
+}
+
+// Result is an image after preprocessing.
+type Result struct {
+	// Image is the processed image, with its origin at (0, 0)
+	Image *image.Gray
+
+	// ToSource maps the pixels of Image to those of the input image
+	ToSource model.Matrix
+
+	// Rotation is the clockwise rotation, in degrees, applied to make the
+	// text upright
+	Rotation int
+
+	// Skew is the angle, in degrees clockwise, the text lines were tilted
+	// by before Deskew straightened them
+	Skew float64
+
+	// Applied describes the steps that changed the image, in order, such as
+	// "rotated 180°" or "binarized (Otsu)"
+	Applied []string
+}
+
+// Process runs an image through the steps opts enables, in the order
+// orientation, deskew, binarization, border removal and despeckling.
+func Process(img image.Image, opts Options) Result {
+	g := toGray(img)
+	if src, ok := img.(*image.Gray); ok && src == g {
+		g = cloneGray(g) // Leave the caller's image alone
+	}
+	origin := img.Bounds().Min
+	res := Result{Image: g, ToSource: model.Translate(float64(origin.X), float64(origin.Y))}
+	if g.Rect.Empty() {
+		return res
+	}
+
+	if opts.Orientation {
+		deg := -1
+		if opts.OrientationDetector != nil {
+			if d, err := opts.OrientationDetector.DetectOrientation(res.Image); err == nil {
+				deg = normalizeRotation(d)
+			}
+		}
+		if deg < 0 {
+			deg = DetectOrientation(res.Image)
+		}
+		if deg != 0 {
+			w, h := res.Image.Rect.Dx(), res.Image.Rect.Dy()
+			res.Image = turn(res.Image, deg)
+			res.ToSource = unturnMatrix(deg, w, h).Multiply(res.ToSource)
+			res.Rotation = deg
+			res.Applied = append(res.Applied, fmt.Sprintf("rotated %d°", deg))
+		}
+	}
+
+	if opts.Deskew {
+		maxSkew := opts.MaxSkew
+		if maxSkew <= 0 {
+			maxSkew = DefaultMaxSkew
+		}
+		if skew := DetectSkew(res.Image, maxSkew); math.Abs(skew) >= minSkew {
+			var m model.Matrix
+			res.Image, m = rotate(res.Image, skew)
+			res.ToSource = m.Multiply(res.ToSource)
+			res.Skew = skew
+			res.Applied = append(res.Applied, fmt.Sprintf("deskewed %.1f°", skew))
+		}
+	}
+
+	switch opts.Binarize {
+	case BinarizeOtsu:
+		binarize(res.Image, otsuMask(res.Image))
+		res.Applied = append(res.Applied, "binarized (Otsu)")
+	case BinarizeSauvola:
+		binarize(res.Image, sauvolaMask(res.Image))
+		res.Applied = append(res.Applied, "binarized (Sauvola)")
+	}
+
+	if opts.RemoveBorders || opts.Despeckle {
+		// Dark pixels: the image itself once binarized, or else by Otsu's
+		// threshold
+		dark := otsuMask(res.Image)
+		if opts.RemoveBorders && whiten(res.Image, borderPixels(dark)) > 0 {
+			res.Applied = append(res.Applied, "removed borders")
+		}
+		if opts.Despeckle {
+			size := opts.SpeckleSize
+			if size <= 0 {
+				size = DefaultSpeckleSize
+			}
+			if n := whiten(res.Image, specks(dark, size)); n > 0 {
+				res.Applied = append(res.Applied, "despeckled")
+			}
+		}
+	}
+
+	return res
+}
+
+// cloneGray returns a copy of g
+func cloneGray(g *image.Gray) *image.Gray {
+	c := image.NewGray(g.Rect)
+	for y := g.Rect.Min.Y; y < g.Rect.Max.Y; y++ {
+		copy(c.Pix[(y-c.Rect.Min.Y)*c.Stride:], g.Pix[g.PixOffset(g.Rect.Min.X, y):g.PixOffset(g.Rect.Max.X, y)])
+	}
+	return c
+}
+
+// normalizeRotation returns deg as 0, 90, 180 or 270, or -1 if it isn't a
+// multiple of 90
+func normalizeRotation(deg int) int {
+	deg = ((deg % 360) + 360) % 360
+	if deg%90 != 0 {
+		return -1
+	}
+	return deg
+}
+
+// turn returns g turned clockwise by 90, 180 or 270 degrees
+func turn(g *image.Gray, deg int) *image.Gray {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	var dst *image.Gray
+	if deg == 180 {
+		dst = image.NewGray(image.Rect(0, 0, w, h))
+	} else {
+		dst = image.NewGray(image.Rect(0, 0, h, w))
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := g.Pix[y*g.Stride+x]
+			switch deg {
+			case 90:
+				dst.Pix[x*dst.Stride+h-1-y] = v
+			case 180:
+				dst.Pix[(h-1-y)*dst.Stride+w-1-x] = v
+			default: // 270
+				dst.Pix[(w-1-x)*dst.Stride+y] = v
+			}
+		}
+	}
+	return dst
+}
+
+// unturnMatrix maps the pixels of a w×h image turned clockwise by deg back
+// to the image's
+func unturnMatrix(deg, w, h int) model.Matrix {
+	fw, fh := float64(w), float64(h)
+	switch deg {
+	case 90:
+		return model.Matrix{0, -1, 1, 0, 0, fh}
+	case 180:
+		return model.Matrix{-1, 0, 0, -1, fw, fh}
+	case 270:
+		return model.Matrix{0, 1, -1, 0, fw, 0}
+	default:
+		return model.Identity()
+	}
+}
+
+// rotate straightens g, whose content is tilted clockwise by deg degrees,
+// about its center, keeping its size and filling the uncovered corners
+// with white. It returns the image and the matrix mapping its pixels to
+// g's.
+func rotate(g *image.Gray, deg float64) (*image.Gray, model.Matrix) {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	cx, cy := float64(w)/2, float64(h)/2
+	m := model.Translate(-cx, -cy).Multiply(model.Rotate(deg * math.Pi / 180)).Multiply(model.Translate(cx, cy))
+
+	dst := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := m.Transform(model.Point{X: float64(x) + 0.5, Y: float64(y) + 0.5})
+			dst.Pix[y*dst.Stride+x] = sample(g, p.X-0.5, p.Y-0.5)
+		}
+	}
+	return dst, m
+}
+
+// sample returns g's value at (x, y), interpolating between pixel centers
+// bilinearly; outside the image is white
+func sample(g *image.Gray, x, y float64) uint8 {
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+	at := func(x, y int) float64 {
+		if x < 0 || y < 0 || x >= g.Rect.Dx() || y >= g.Rect.Dy() {
+			return 255
+		}
+		return float64(g.Pix[y*g.Stride+x])
+	}
+	top := at(x0, y0)*(1-fx) + at(x0+1, y0)*fx
+	bottom := at(x0, y0+1)*(1-fx) + at(x0+1, y0+1)*fx
+	return uint8(top*(1-fy) + bottom*fy + 0.5)
+}
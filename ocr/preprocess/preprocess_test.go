@@ -0,0 +1,276 @@
+package preprocess
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/tsawler/tabula/model"
+)
+
+var sampleLines = []string{
+	"The quick brown fox jumps over the lazy dog",
+	"Pack my box with five dozen liquor jugs today",
+	"Sphinx of black quartz, judge my solemn vow",
+	"How vexingly quick daft zebras jump at night",
+	"Bright vixens jump; dozy fowl quack loudly",
+	"The five boxing wizards jump quickly home",
+}
+
+// textPage draws the sample lines in black on a white page
+func textPage(t *testing.T) *image.Gray {
+	t.Helper()
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("Failed to parse font: %v", err)
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: 28, DPI: 72})
+	if err != nil {
+		t.Fatalf("Failed to create face: %v", err)
+	}
+	defer face.Close()
+
+	img := image.NewGray(image.Rect(0, 0, 800, 600))
+	draw.Draw(img, img.Rect, image.White, image.Point{}, draw.Src)
+	d := &font.Drawer{Dst: img, Src: image.Black, Face: face}
+	for i, line := range sampleLines {
+		d.Dot = fixed.P(60, 100+i*60)
+		d.DrawString(line)
+	}
+	return img
+}
+
+// tilt returns img with its content turned clockwise by deg degrees
+func tilt(img *image.Gray, deg float64) *image.Gray {
+	out, _ := rotate(img, -deg)
+	return out
+}
+
+// inkCenter returns the mean position of the centers of img's dark pixels
+func inkCenter(img *image.Gray) model.Point {
+	var sx, sy, n float64
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			if img.Pix[y*img.Stride+x] < 128 {
+				sx, sy, n = sx+float64(x)+0.5, sy+float64(y)+0.5, n+1
+			}
+		}
+	}
+	return model.Point{X: sx / n, Y: sy / n}
+}
+
+func TestOtsu(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+		if i%3 == 0 {
+			img.Pix[i] = 40
+		}
+	}
+	if th := Otsu(img); th < 40 || th >= 200 {
+		t.Errorf("Expected a threshold between 40 and 200, got %d", th)
+	}
+}
+
+func TestBinarizeSauvolaUnevenLighting(t *testing.T) {
+	// Text on a background darkening from white to mid-gray, which a
+	// single threshold can't separate
+	img := textPage(t)
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			shade := uint8(110 * x / img.Rect.Dx())
+			if v := img.Pix[y*img.Stride+x]; v > shade {
+				img.Pix[y*img.Stride+x] = v - shade
+			}
+		}
+	}
+
+	res := Process(img, Options{Binarize: BinarizeSauvola})
+	// The dim right side stays white apart from its text
+	var dark, total int
+	for y := 0; y < 40; y++ {
+		for x := 600; x < 800; x++ {
+			total++
+			if res.Image.Pix[y*res.Image.Stride+x] == 0 {
+				dark++
+			}
+		}
+	}
+	if dark*10 > total {
+		t.Errorf("Expected the blank shaded area to stay white, got %d of %d pixels black", dark, total)
+	}
+	for _, v := range res.Image.Pix {
+		if v != 0 && v != 255 {
+			t.Fatalf("Expected a black and white image, got gray %d", v)
+		}
+	}
+	if len(res.Applied) != 1 || res.Applied[0] != "binarized (Sauvola)" {
+		t.Errorf("Expected binarized (Sauvola), got %v", res.Applied)
+	}
+}
+
+func TestDetectSkew(t *testing.T) {
+	img := textPage(t)
+	for _, deg := range []float64{-3, -1.5, 0, 2} {
+		if got := DetectSkew(tilt(img, deg), DefaultMaxSkew); math.Abs(got-deg) > 0.2 {
+			t.Errorf("Expected a skew of %.1f°, got %.2f°", deg, got)
+		}
+	}
+	if got := DetectSkew(image.NewGray(image.Rect(0, 0, 50, 50)), DefaultMaxSkew); got != 0 {
+		t.Errorf("Expected no skew for a blank image, got %.2f°", got)
+	}
+}
+
+func TestProcessDeskew(t *testing.T) {
+	img := textPage(t)
+	skewed := tilt(img, 2)
+
+	res := Process(skewed, Options{Deskew: true})
+	if math.Abs(res.Skew-2) > 0.2 {
+		t.Fatalf("Expected a skew of 2°, got %.2f°", res.Skew)
+	}
+	if len(res.Applied) != 1 || !strings.HasPrefix(res.Applied[0], "deskewed") {
+		t.Errorf("Expected deskewed, got %v", res.Applied)
+	}
+	if got := DetectSkew(res.Image, DefaultMaxSkew); math.Abs(got) > 0.2 {
+		t.Errorf("Expected straight lines after deskewing, got a skew of %.2f°", got)
+	}
+
+	// ToSource maps the ink back to where it was
+	want := inkCenter(skewed)
+	if got := res.ToSource.Transform(inkCenter(res.Image)); got.Distance(want) > 3 {
+		t.Errorf("Expected the ink center to map back to %v, got %v", want, got)
+	}
+}
+
+func TestDetectOrientation(t *testing.T) {
+	img := textPage(t)
+	for _, deg := range []int{0, 90, 180, 270} {
+		turned := img
+		if deg != 0 {
+			// Turned counter-clockwise by deg, it takes deg clockwise to fix
+			turned = turn(img, 360-deg)
+		}
+		if got := DetectOrientation(turned); got != deg {
+			t.Errorf("Expected orientation %d, got %d", deg, got)
+		}
+	}
+	if got := DetectOrientation(image.NewGray(image.Rect(0, 0, 50, 50))); got != 0 {
+		t.Errorf("Expected orientation 0 for a blank image, got %d", got)
+	}
+}
+
+type fixedOrientation struct {
+	deg int
+	err error
+}
+
+func (f fixedOrientation) DetectOrientation(img image.Image) (int, error) {
+	return f.deg, f.err
+}
+
+func TestProcessOrientation(t *testing.T) {
+	img := textPage(t)
+	upsideDown := turn(img, 180)
+
+	res := Process(upsideDown, Options{Orientation: true})
+	if res.Rotation != 180 || len(res.Applied) != 1 || res.Applied[0] != "rotated 180°" {
+		t.Fatalf("Expected a 180° rotation, got %d (%v)", res.Rotation, res.Applied)
+	}
+	want := inkCenter(upsideDown)
+	if got := res.ToSource.Transform(inkCenter(res.Image)); got.Distance(want) > 1 {
+		t.Errorf("Expected the ink center to map back to %v, got %v", want, got)
+	}
+
+	// A detector's answer is taken, and a failing detector falls back to
+	// the text-line heuristic
+	res = Process(img, Options{Orientation: true, OrientationDetector: fixedOrientation{deg: 90}})
+	if res.Rotation != 90 || res.Image.Rect.Dx() != img.Rect.Dy() {
+		t.Errorf("Expected the detector's 90° rotation, got %d", res.Rotation)
+	}
+	res = Process(upsideDown, Options{Orientation: true, OrientationDetector: fixedOrientation{err: errors.New("no OSD data")}})
+	if res.Rotation != 180 {
+		t.Errorf("Expected the heuristic's 180° rotation, got %d", res.Rotation)
+	}
+}
+
+func TestProcessRemoveBorders(t *testing.T) {
+	img := textPage(t)
+	// A dark band down the left edge, as from a scanner lid
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < 30; x++ {
+			img.Pix[y*img.Stride+x] = 20
+		}
+	}
+
+	res := Process(img, Options{RemoveBorders: true})
+	if v := res.Image.GrayAt(10, 300).Y; v != 255 {
+		t.Errorf("Expected the border to be whitened, got gray %d", v)
+	}
+	if ink := inkCenter(res.Image); ink.X < 100 {
+		t.Errorf("Expected the text to remain, got ink centered at %v", ink)
+	}
+	if len(res.Applied) != 1 || res.Applied[0] != "removed borders" {
+		t.Errorf("Expected removed borders, got %v", res.Applied)
+	}
+}
+
+func TestProcessDespeckle(t *testing.T) {
+	img := textPage(t)
+	before := inkCenter(img)
+	// Isolated specks in the margin
+	for _, p := range []image.Point{{700, 560}, {720, 570}, {740, 580}} {
+		img.SetGray(p.X, p.Y, color.Gray{})
+		img.SetGray(p.X+1, p.Y, color.Gray{})
+	}
+
+	res := Process(img, Options{Despeckle: true})
+	for _, p := range []image.Point{{700, 560}, {720, 570}, {740, 580}} {
+		if v := res.Image.GrayAt(p.X, p.Y).Y; v != 255 {
+			t.Errorf("Expected the speck at %v to be removed, got gray %d", p, v)
+		}
+	}
+	if after := inkCenter(res.Image); after.Distance(before) > 1 {
+		t.Errorf("Expected the text to remain, got ink center %v, want %v", after, before)
+	}
+	if len(res.Applied) != 1 || res.Applied[0] != "despeckled" {
+		t.Errorf("Expected despeckled, got %v", res.Applied)
+	}
+}
+
+func TestProcessNothing(t *testing.T) {
+	img := textPage(t)
+	res := Process(img, Options{})
+	if len(res.Applied) != 0 || res.ToSource != model.Identity() {
+		t.Errorf("Expected no changes, got %v and %v", res.Applied, res.ToSource)
+	}
+	if &res.Image.Pix[0] == &img.Pix[0] {
+		t.Error("Expected a copy of the image")
+	}
+}
+
+func TestDefaultOptions(t *testing.T) {
+	// A skewed, upside-down page comes out upright, straight and black and white
+	img := turn(tilt(textPage(t), 1.5), 180)
+
+	res := Process(img, DefaultOptions())
+	if res.Rotation != 180 {
+		t.Errorf("Expected a 180° rotation, got %d", res.Rotation)
+	}
+	if math.Abs(res.Skew-1.5) > 0.2 {
+		t.Errorf("Expected a skew of 1.5°, got %.2f°", res.Skew)
+	}
+	want := []string{"rotated 180°", "deskewed 1.5°", "binarized (Sauvola)"}
+	if len(res.Applied) < len(want) || strings.Join(res.Applied[:len(want)], ", ") != strings.Join(want, ", ") {
+		t.Errorf("Expected %v first, got %v", want, res.Applied)
+	}
+}
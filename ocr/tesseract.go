@@ -0,0 +1,119 @@
+//go:build ocr
+
+package ocr
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// TesseractEngine is an Engine backed by the Tesseract library. A Tesseract
+// client can't be shared between goroutines, so the engine keeps a pool of
+// clients and each concurrent recognition uses its own.
+type TesseractEngine struct {
+	mu     sync.Mutex
+	idle   []*tesseractClient
+	closed bool
+}
+
+// tesseractClient is a pooled client with the settings it was last used
+// with, so unchanged settings don't make Tesseract reinitialize
+type tesseractClient struct {
+	client *Client
+	lang   string
+	psm    PageSegMode
+	psmSet bool // whether psm has been applied to the client
+	dpi    int
+}
+
+// NewTesseractEngine creates an engine using the Tesseract library. The
+// engine should be closed when no longer needed to release its clients.
+func NewTesseractEngine() (*TesseractEngine, error) {
+	c, err := New()
+	if err != nil {
+		return nil, err
+	}
+	return &TesseractEngine{idle: []*tesseractClient{{client: c}}}, nil
+}
+
+// Recognize performs OCR on an image and returns the words found.
+func (t *TesseractEngine) Recognize(ctx context.Context, img []byte, opts Options) ([]Word, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tc, err := t.get()
+	if err != nil {
+		return nil, err
+	}
+	defer t.put(tc)
+
+	if opts.Language != tc.lang {
+		lang := opts.Language
+		if lang == "" {
+			lang = "eng"
+		}
+		if err := tc.client.SetLanguage(lang); err != nil {
+			return nil, err
+		}
+		tc.lang = opts.Language
+	}
+	mode := PSM_AUTO
+	if opts.PageSegModeSet {
+		mode = opts.PageSegMode
+	}
+	if !tc.psmSet || mode != tc.psm {
+		if err := tc.client.SetPageSegMode(mode); err != nil {
+			return nil, err
+		}
+		tc.psm, tc.psmSet = mode, true
+	}
+	if opts.DPI > 0 && opts.DPI != tc.dpi {
+		if err := tc.client.SetVariable("user_defined_dpi", strconv.Itoa(opts.DPI)); err != nil {
+			return nil, err
+		}
+		tc.dpi = opts.DPI
+	}
+	return tc.client.RecognizeWords(img)
+}
+
+// Close releases the engine's Tesseract clients.
+func (t *TesseractEngine) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	for _, tc := range t.idle {
+		tc.client.Close()
+	}
+	t.idle = nil
+	return nil
+}
+
+// get takes an idle client from the pool, or creates one
+func (t *TesseractEngine) get() (*tesseractClient, error) {
+	t.mu.Lock()
+	if n := len(t.idle); n > 0 {
+		tc := t.idle[n-1]
+		t.idle = t.idle[:n-1]
+		t.mu.Unlock()
+		return tc, nil
+	}
+	t.mu.Unlock()
+
+	c, err := New()
+	if err != nil {
+		return nil, err
+	}
+	return &tesseractClient{client: c}, nil
+}
+
+// put returns a client to the pool, closing it if the engine was closed
+func (t *TesseractEngine) put(tc *tesseractClient) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		tc.client.Close()
+		return
+	}
+	t.idle = append(t.idle, tc)
+}
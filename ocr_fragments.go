@@ -0,0 +1,231 @@
+package tabula
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/ocr"
+	"github.com/tsawler/tabula/reader"
+	"github.com/tsawler/tabula/text"
+)
+
+// Proportions of a Latin font's em square, used to estimate the font size
+// of OCR'd words from the height of their boxes
+const (
+	ocrAscent  = 0.72 // Cap height and ascenders above the baseline
+	ocrXHeight = 0.5  // Lowercase letters without ascenders
+	ocrDescent = 0.21 // Descenders below the baseline
+)
+
+// ocrFragments turns the words OCR found in an image into text fragments
+// positioned on the page, so scanned pages go through the same layout
+// analysis as born-digital ones. toPage maps the image's pixels (origin
+// top-left) to the page's user space; a singular toPage, for an image
+// whose placement is unknown, yields no fragments.
+//
+// Each fragment is one word. The words of a line share a baseline and a
+// font size, estimated from the heights of their boxes and which letters
+// rise above the x-height or descend below the baseline, so a line of
+// "minimum" and a line of "Typography" of the same size get the same
+// estimate.
+func ocrFragments(words []ocr.Word, toPage model.Matrix) []text.TextFragment {
+	if _, ok := toPage.Inverse(); !ok {
+		return nil
+	}
+	// Pixels to points: the scale of toPage, as the text extractor scales
+	// font sizes by the CTM
+	scale := math.Sqrt(math.Abs(toPage[0]*toPage[3] - toPage[1]*toPage[2]))
+
+	var fragments []text.TextFragment
+	for _, line := range ocrLines(words) {
+		size, baseline := ocrLineMetrics(line)
+		if size <= 0 {
+			continue
+		}
+		for _, w := range line {
+			// The word's box from the baseline up one em, in points
+			box := transformRect(toPage,
+				model.Point{X: float64(w.BBox.Min.X), Y: baseline - size},
+				model.Point{X: float64(w.BBox.Max.X), Y: baseline})
+			fragments = append(fragments, text.TextFragment{
+				Text:      w.Text,
+				X:         box.X,
+				Y:         box.Y,
+				Width:     box.Width,
+				Height:    box.Height,
+				FontSize:  size * scale,
+				Direction: text.DetectDirection(w.Text),
+				Visible:   true,
+			})
+		}
+	}
+	return fragments
+}
+
+// ocrLines groups words with text and a box into lines: runs of words the
+// engine put on the same line that also overlap vertically, since engines
+// that don't number lines leave them all on line 0
+func ocrLines(words []ocr.Word) [][]ocr.Word {
+	var lines [][]ocr.Word
+	var prev ocr.Word
+	var top, bottom int
+	for _, w := range words {
+		w.Text = strings.TrimSpace(w.Text)
+		if w.Text == "" || w.BBox.Empty() {
+			continue
+		}
+		sameLine := len(lines) > 0 && w.Block == prev.Block && w.Paragraph == prev.Paragraph && w.Line == prev.Line
+		prev = w
+		if sameLine && w.BBox.Min.Y < bottom && w.BBox.Max.Y > top {
+			last := len(lines) - 1
+			lines[last] = append(lines[last], w)
+			if w.BBox.Min.Y < top {
+				top = w.BBox.Min.Y
+			}
+			if w.BBox.Max.Y > bottom {
+				bottom = w.BBox.Max.Y
+			}
+			continue
+		}
+		lines = append(lines, []ocr.Word{w})
+		top, bottom = w.BBox.Min.Y, w.BBox.Max.Y
+	}
+	return lines
+}
+
+// ocrLineMetrics estimates a line's font size and baseline in pixels: the
+// medians of the estimates from each of its words
+func ocrLineMetrics(line []ocr.Word) (size, baseline float64) {
+	var sizes, baselines []float64
+	for _, w := range line {
+		above, below, ok := ocrWordExtent(w.Text)
+		if !ok {
+			continue
+		}
+		s := float64(w.BBox.Dy()) / (above + below)
+		sizes = append(sizes, s)
+		baselines = append(baselines, float64(w.BBox.Max.Y)-below*s)
+	}
+	if len(sizes) > 0 {
+		return medianOf(sizes), medianOf(baselines)
+	}
+
+	// Only punctuation: assume the boxes span the whole em
+	for _, w := range line {
+		if h := float64(w.BBox.Dy()) / (ocrAscent + ocrDescent); h > size {
+			size = h
+		}
+		if b := float64(w.BBox.Max.Y) - ocrDescent*size; b > baseline {
+			baseline = b
+		}
+	}
+	return size, baseline
+}
+
+// ocrWordExtent returns how far a word's letters reach above and below the
+// baseline, in ems. It reports false for a word with no letters or digits,
+// whose box says little about the font size.
+func ocrWordExtent(word string) (above, below float64, ok bool) {
+	for _, r := range word {
+		switch {
+		case strings.ContainsRune("gpqy", r):
+			above, below = math.Max(above, ocrXHeight), ocrDescent
+		case r == 'j':
+			above, below = ocrAscent, ocrDescent
+		case unicode.IsUpper(r) || unicode.IsDigit(r) || strings.ContainsRune("bdfhiklt", r):
+			above = ocrAscent
+		case unicode.IsLower(r):
+			above = math.Max(above, ocrXHeight)
+		case unicode.IsLetter(r):
+			// Scripts without case, such as CJK, fill the em
+			above, below = ocrAscent, ocrDescent
+		default:
+			continue
+		}
+		ok = true
+	}
+	return above, below, ok
+}
+
+// medianOf returns the median of values, which it sorts
+func medianOf(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// transformRect maps the rectangle with corners a and b through m and
+// returns the axis-aligned box around the result
+func transformRect(m model.Matrix, a, b model.Point) model.BBox {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range []model.Point{a, b, {X: a.X, Y: b.Y}, {X: b.X, Y: a.Y}} {
+		q := m.Transform(p)
+		minX, minY = math.Min(minX, q.X), math.Min(minY, q.Y)
+		maxX, maxY = math.Max(maxX, q.X), math.Max(maxY, q.Y)
+	}
+	return model.BBox{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+}
+
+// embeddedImageToPage returns the matrix mapping the pixels of the OCR
+// image made from an embedded image (turned by the page's rotation, then
+// perhaps enlarged, and PNG-encoded) to the page's user space, given the
+// CTM the image is drawn with
+func embeddedImageToPage(img reader.PageImage, ctm model.Matrix, rotation int, png []byte) model.Matrix {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(png))
+	w, h := float64(img.Width), float64(img.Height)
+	if err != nil || w <= 0 || h <= 0 || cfg.Width <= 0 || cfg.Height <= 0 {
+		return model.Matrix{}
+	}
+
+	// Undo the rotation (clockwise by rotation) applied for OCR
+	turnedW, turnedH := w, h
+	var unturn model.Matrix
+	switch ((rotation % 360) + 360) % 360 {
+	case 90:
+		turnedW, turnedH = h, w
+		unturn = model.Matrix{0, -1, 1, 0, 0, h}
+	case 180:
+		unturn = model.Matrix{-1, 0, 0, -1, w, h}
+	case 270:
+		turnedW, turnedH = h, w
+		unturn = model.Matrix{0, 1, -1, 0, w, 0}
+	default:
+		unturn = model.Identity()
+	}
+
+	// OCR image pixels to turned pixels, to image pixels, to the image's
+	// unit square (whose first row is at the top), to the page
+	return model.Scale(turnedW/float64(cfg.Width), turnedH/float64(cfg.Height)).
+		Multiply(unturn).
+		Multiply(model.Matrix{1 / w, 0, 0, -1 / h, 0, 1}).
+		Multiply(ctm)
+}
+
+// imagePlacement finds the CTM an extracted image is drawn with among a
+// page's placed images, matching XObjects by name and inline images in
+// draw order, and marks the placement used. When the image isn't found it
+// is assumed to fill the page box, as estimateImageDPI does.
+func imagePlacement(img reader.PageImage, placed []reader.PlacedPageImage, used []bool, pageBox []float64) model.Matrix {
+	for i, p := range placed {
+		if used[i] || p.Inline != img.Inline || p.Name != img.Name ||
+			p.PixelWidth != img.Width || p.PixelHeight != img.Height {
+			continue
+		}
+		used[i] = true
+		return p.CTM
+	}
+	if len(pageBox) != 4 {
+		return model.Matrix{}
+	}
+	x0, y0 := math.Min(pageBox[0], pageBox[2]), math.Min(pageBox[1], pageBox[3])
+	return model.Matrix{math.Abs(pageBox[2] - pageBox[0]), 0, 0, math.Abs(pageBox[3] - pageBox[1]), x0, y0}
+}
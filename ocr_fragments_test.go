@@ -0,0 +1,164 @@
+package tabula
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/ocr"
+	"github.com/tsawler/tabula/reader"
+)
+
+// page300 maps the pixels of a 612x792 page rendered at 300 DPI to points
+var page300 = model.Matrix{0.24, 0, 0, -0.24, 0, 792}
+
+// near reports whether a and b are within a tenth of a point, the accuracy
+// of estimates from whole-pixel boxes
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.1
+}
+
+func TestOCRFragments(t *testing.T) {
+	// A 12pt line (50 pixels to the em at 300 DPI) with its baseline 1000
+	// pixels down: "Hello" reaches cap height, "gap" descends
+	words := []ocr.Word{
+		{Text: "Hello", BBox: image.Rect(300, 964, 420, 1000), Block: 1, Paragraph: 1, Line: 1},
+		{Text: "gap", BBox: image.Rect(440, 975, 510, 1011), Block: 1, Paragraph: 1, Line: 1},
+		{Text: "  ", BBox: image.Rect(520, 975, 530, 1000), Block: 1, Paragraph: 1, Line: 1},
+	}
+
+	fragments := ocrFragments(words, page300)
+	if len(fragments) != 2 {
+		t.Fatalf("got %d fragments, want 2", len(fragments))
+	}
+	for _, f := range fragments {
+		if !near(f.FontSize, 12) || !near(f.Height, 12) {
+			t.Errorf("%q: got font size %.2f and height %.2f, want 12", f.Text, f.FontSize, f.Height)
+		}
+		if !near(f.Y, 792-240) {
+			t.Errorf("%q: got baseline %.2f, want %.2f", f.Text, f.Y, 792-240.0)
+		}
+		if !f.Visible {
+			t.Errorf("%q: got an invisible fragment", f.Text)
+		}
+	}
+	if f := fragments[0]; f.Text != "Hello" || !near(f.X, 72) || !near(f.Width, 28.8) {
+		t.Errorf("got %q at x %.2f, width %.2f, want Hello at 72, width 28.8", f.Text, f.X, f.Width)
+	}
+}
+
+func TestOCRFragmentsUnnumberedLines(t *testing.T) {
+	// Words from an engine that doesn't number lines still form two lines
+	words := []ocr.Word{
+		{Text: "First", BBox: image.Rect(300, 964, 420, 1000)},
+		{Text: "Second", BBox: image.Rect(300, 1024, 460, 1060)},
+	}
+
+	fragments := ocrFragments(words, page300)
+	if len(fragments) != 2 {
+		t.Fatalf("got %d fragments, want 2", len(fragments))
+	}
+	if near(fragments[0].Y, fragments[1].Y) {
+		t.Errorf("got both words on baseline %.2f, want separate lines", fragments[0].Y)
+	}
+}
+
+func TestOCRFragmentsUnplaced(t *testing.T) {
+	words := []ocr.Word{{Text: "Hello", BBox: image.Rect(300, 964, 420, 1000)}}
+	if fragments := ocrFragments(words, model.Matrix{}); fragments != nil {
+		t.Errorf("got %d fragments for an image with no placement, want none", len(fragments))
+	}
+}
+
+func TestOCRWordExtent(t *testing.T) {
+	tests := []struct {
+		word         string
+		above, below float64
+		ok           bool
+	}{
+		{"Hello", ocrAscent, 0, true},
+		{"run", ocrXHeight, 0, true},
+		{"gap", ocrXHeight, ocrDescent, true},
+		{"Typography", ocrAscent, ocrDescent, true},
+		{"2024", ocrAscent, 0, true},
+		{"漢字", ocrAscent, ocrDescent, true},
+		{"—", 0, 0, false},
+	}
+	for _, tt := range tests {
+		above, below, ok := ocrWordExtent(tt.word)
+		if above != tt.above || below != tt.below || ok != tt.ok {
+			t.Errorf("ocrWordExtent(%q) = %v, %v, %v, want %v, %v, %v", tt.word, above, below, ok, tt.above, tt.below, tt.ok)
+		}
+	}
+}
+
+// encodedPNG returns a blank PNG of the given size
+func encodedPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestEmbeddedImageToPage(t *testing.T) {
+	// A 100x200 image drawn in a 50x100 point box at (10, 20)
+	img := reader.PageImage{Width: 100, Height: 200}
+	ctm := model.Matrix{50, 0, 0, 100, 10, 20}
+
+	tests := []struct {
+		name     string
+		rotation int
+		w, h     int         // OCR image size
+		pixel    model.Point // A pixel of the OCR image...
+		want     model.Point // ...and where it is on the page
+	}{
+		{"upright", 0, 100, 200, model.Point{X: 0, Y: 0}, model.Point{X: 10, Y: 120}},
+		{"upright far corner", 0, 100, 200, model.Point{X: 100, Y: 200}, model.Point{X: 60, Y: 20}},
+		{"enlarged", 0, 300, 600, model.Point{X: 300, Y: 0}, model.Point{X: 60, Y: 120}},
+		{"turned 90", 90, 200, 100, model.Point{X: 0, Y: 0}, model.Point{X: 10, Y: 20}},
+		{"turned 180", 180, 100, 200, model.Point{X: 0, Y: 0}, model.Point{X: 60, Y: 20}},
+		{"turned 270", 270, 200, 100, model.Point{X: 0, Y: 0}, model.Point{X: 60, Y: 120}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := embeddedImageToPage(img, ctm, tt.rotation, encodedPNG(t, tt.w, tt.h))
+			if got := m.Transform(tt.pixel); !near(got.X, tt.want.X) || !near(got.Y, tt.want.Y) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if m := embeddedImageToPage(img, ctm, 0, []byte("not a png")); m != (model.Matrix{}) {
+		t.Errorf("got %v for an undecodable image, want the zero matrix", m)
+	}
+}
+
+func TestImagePlacement(t *testing.T) {
+	placed := []reader.PlacedPageImage{
+		{Name: "Im1", PixelWidth: 10, PixelHeight: 10, CTM: model.Matrix{1, 0, 0, 1, 0, 0}},
+		{Inline: true, PixelWidth: 10, PixelHeight: 10, CTM: model.Matrix{2, 0, 0, 2, 0, 0}},
+		{Inline: true, PixelWidth: 10, PixelHeight: 10, CTM: model.Matrix{3, 0, 0, 3, 0, 0}},
+	}
+	used := make([]bool, len(placed))
+	box := []float64{0, 0, 612, 792}
+
+	inline := reader.PageImage{Inline: true, Width: 10, Height: 10}
+	if got := imagePlacement(inline, placed, used, box); got[0] != 2 {
+		t.Errorf("got %v for the first inline image, want the first inline placement", got)
+	}
+	if got := imagePlacement(inline, placed, used, box); got[0] != 3 {
+		t.Errorf("got %v for the second inline image, want the second inline placement", got)
+	}
+	if got := imagePlacement(reader.PageImage{Name: "Im1", Width: 10, Height: 10}, placed, used, box); got[0] != 1 {
+		t.Errorf("got %v for Im1, want its placement", got)
+	}
+	want := model.Matrix{612, 0, 0, 792, 0, 0}
+	if got := imagePlacement(reader.PageImage{Name: "Im2", Width: 10, Height: 10}, placed, used, box); got != want {
+		t.Errorf("got %v for an undrawn image, want the page box %v", got, want)
+	}
+}
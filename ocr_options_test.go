@@ -1,6 +1,20 @@
 package tabula
 
-import "testing"
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tsawler/tabula/layout"
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/ocr"
+	"github.com/tsawler/tabula/ocr/preprocess"
+)
 
 func TestEstimateImageDPI(t *testing.T) {
 	// 1700x2200 px on a US-Letter page (612x792 pt) = 200 DPI.
@@ -32,3 +46,332 @@ func TestOCRUpscale(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderPageBuiltin(t *testing.T) {
+	ext := FromBytes(buildTextPDF("BT /F1 24 Tf 72 700 Td (Scanned) Tj ET"), "")
+	defer ext.Close()
+	if err := ext.ensureReader(); err != nil {
+		t.Fatalf("ensureReader() error: %v", err)
+	}
+
+	rendered := ext.renderPageBuiltin(1)
+	if len(rendered) != 1 {
+		t.Fatalf("got %d rendered images, want 1", len(rendered))
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(rendered[0].png))
+	if err != nil {
+		t.Fatalf("decoding render: %v", err)
+	}
+	// US Letter at 300 DPI
+	if cfg.Width != 2550 || cfg.Height != 3300 || rendered[0].dpi != ocrRenderDPI {
+		t.Errorf("got %dx%d at %d DPI, want 2550x3300 at %d DPI", cfg.Width, cfg.Height, rendered[0].dpi, ocrRenderDPI)
+	}
+
+	if got := ext.renderPageBuiltin(2); got != nil {
+		t.Errorf("got %d images for a missing page, want none", len(got))
+	}
+}
+
+func TestOCRRendererOption(t *testing.T) {
+	ext := FromBytes(buildTextPDF(""), "")
+	if ext.options.ocrRenderer != RendererBuiltin {
+		t.Errorf("default renderer = %d, want RendererBuiltin", ext.options.ocrRenderer)
+	}
+	if got := ext.OCRRenderer(RendererPdftoppm).options.ocrRenderer; got != RendererPdftoppm {
+		t.Errorf("renderer = %d, want RendererPdftoppm", got)
+	}
+	if ext.options.ocrRenderer != RendererBuiltin {
+		t.Error("OCRRenderer modified the original extractor")
+	}
+
+	if !ext.OCRRenderer(RendererPdftoppm).usePdftoppm() {
+		t.Error("pdftoppm not used when selected")
+	}
+	// Passwords are not put on pdftoppm's command line
+	if ext.OCRRenderer(RendererPdftoppm).Password("secret").usePdftoppm() {
+		t.Error("pdftoppm used for a document opened with a password")
+	}
+}
+
+// stubEngine recognizes the same words on every image, counting calls
+type stubEngine struct {
+	mu    sync.Mutex
+	calls int
+	opts  ocr.Options
+}
+
+func (s *stubEngine) Recognize(ctx context.Context, img []byte, opts ocr.Options) ([]ocr.Word, error) {
+	s.mu.Lock()
+	s.calls++
+	s.opts = opts
+	s.mu.Unlock()
+	if _, err := png.DecodeConfig(bytes.NewReader(img)); err != nil {
+		return nil, err
+	}
+	return []ocr.Word{
+		{Text: "Scanned", BBox: image.Rect(300, 300, 700, 360), Confidence: 95, Block: 1, Paragraph: 1, Line: 1},
+		{Text: "words", BBox: image.Rect(720, 300, 1000, 360), Confidence: 93, Block: 1, Paragraph: 1, Line: 1},
+	}, nil
+}
+
+func TestOCREngine(t *testing.T) {
+	// A page with no native text, only artwork, falls back to OCR
+	data := buildPagesPDF("0 0 1 rg 72 72 100 100 re f", "0 0 1 rg 72 72 100 100 re f")
+	engine := &stubEngine{}
+
+	text, warnings, err := FromBytes(data, "").OCREngine(engine).OCRLanguage("deu").Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if text != "Scanned words\n\nScanned words" {
+		t.Errorf("got %q, want the engine's words for both pages", text)
+	}
+	if engine.calls != 2 {
+		t.Errorf("got %d engine calls, want 2", engine.calls)
+	}
+	if engine.opts.Language != "deu" || engine.opts.DPI != ocrRenderDPI {
+		t.Errorf("got options %+v, want language deu at %d DPI", engine.opts, ocrRenderDPI)
+	}
+	fallbacks := 0
+	for _, w := range warnings {
+		if w.Code == WarningOCRFallback {
+			fallbacks++
+		}
+	}
+	if fallbacks != 2 {
+		t.Errorf("got %d OCR fallback warnings, want 2", fallbacks)
+	}
+	if engine.opts.PageSegModeSet {
+		t.Errorf("got options %+v, want the engine's default page segmentation", engine.opts)
+	}
+
+	// The zero mode is passed on when set explicitly
+	if _, _, err := FromBytes(data, "").OCREngine(engine).OCRPageSegMode(ocr.PSM_OSD_ONLY).Text(); err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if !engine.opts.PageSegModeSet || engine.opts.PageSegMode != ocr.PSM_OSD_ONLY {
+		t.Errorf("got options %+v, want PSM_OSD_ONLY set", engine.opts)
+	}
+}
+
+func TestOCREngineError(t *testing.T) {
+	data := buildTextPDF("0 0 1 rg 72 72 100 100 re f")
+	engine := ocr.EngineFunc(func(ctx context.Context, img []byte, opts ocr.Options) ([]ocr.Word, error) {
+		return nil, errors.New("service unavailable")
+	})
+
+	text, warnings, err := FromBytes(data, "").OCREngine(engine).Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if text != "" {
+		t.Errorf("got %q, want no text", text)
+	}
+	failed := 0
+	for _, w := range warnings {
+		switch w.Code {
+		case WarningOCRFallback:
+			t.Errorf("got an OCR fallback warning for a failed engine: %v", w)
+		case WarningOCRFailed:
+			failed++
+			if !strings.Contains(w.Message, "Page 1") || !strings.Contains(w.Message, "service unavailable") {
+				t.Errorf("got warning %q, want the page and the engine's error", w.Message)
+			}
+		}
+	}
+	if failed != 1 {
+		t.Errorf("got %d OCR failure warnings, want 1", failed)
+	}
+}
+
+// layoutEngine recognizes a scanned page with a 24pt heading over a
+// paragraph of 12pt body text, as at 300 DPI: 100 and 50 pixels to the em
+var layoutEngine = ocr.EngineFunc(func(ctx context.Context, img []byte, opts ocr.Options) ([]ocr.Word, error) {
+	var words []ocr.Word
+	line := func(par, num int, baseline, em int, text ...string) {
+		x := 300
+		for _, t := range text {
+			w := len(t) * em / 2
+			words = append(words, ocr.Word{
+				Text:       t,
+				BBox:       image.Rect(x, baseline-em*72/100, x+w, baseline),
+				Confidence: 90,
+				Block:      1,
+				Paragraph:  par,
+				Line:       num,
+			})
+			x += w + em/4
+		}
+	}
+	line(1, 1, 400, 100, "Introduction")
+	line(2, 1, 520, 50, "The", "first", "line", "of", "the", "scanned", "body", "text", "holds", "the", "details")
+	line(2, 2, 580, 50, "The", "second", "line", "of", "the", "scanned", "body", "text", "holds", "the", "rest")
+	line(2, 3, 640, 50, "The", "third", "line", "of", "the", "scanned", "body", "text", "ends", "it", "all")
+	return words, nil
+})
+
+func TestOCRLayout(t *testing.T) {
+	data := buildTextPDF("0 0 1 rg 72 72 100 100 re f")
+
+	doc, _, err := FromBytes(data, "").OCREngine(layoutEngine).Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	page := doc.Pages[0]
+	var heading *model.Heading
+	var para *model.Paragraph
+	for _, el := range page.Elements {
+		switch el := el.(type) {
+		case *model.Heading:
+			heading = el
+		case *model.Paragraph:
+			para = el
+		}
+	}
+	if heading == nil || heading.Text != "Introduction" {
+		t.Fatalf("got elements %+v, want the heading Introduction", page.Elements)
+	}
+	if para == nil || !strings.HasPrefix(para.Text, "The first line of the scanned body text") {
+		t.Fatalf("got paragraph %+v, want the body text", para)
+	}
+	// Pixels are 0.24pt at 300 DPI: the text starts 72pt from the left, and
+	// the paragraph spans from its last baseline, 640 pixels down, to one em
+	// above its first, 520 pixels down
+	if b := para.BBox; !near(b.X, 72) || !near(b.Y, 792-640*0.24) || !near(b.Top(), 792-520*0.24+12) {
+		t.Errorf("got paragraph box %+v, want it at x 72 around the body's lines", b)
+	}
+
+	headings, err := FromBytes(data, "").OCREngine(layoutEngine).Headings()
+	if err != nil {
+		t.Fatalf("Headings() error: %v", err)
+	}
+	if len(headings) != 1 || headings[0].Text != "Introduction" {
+		t.Errorf("got headings %+v, want Introduction", headings)
+	}
+
+	ro, err := FromBytes(data, "").OCREngine(layoutEngine).ReadingOrder()
+	if err != nil {
+		t.Fatalf("ReadingOrder() error: %v", err)
+	}
+	if len(ro.Lines) != 4 {
+		t.Errorf("got %d reading-order lines, want 4", len(ro.Lines))
+	}
+
+	chunks, _, err := FromBytes(data, "").OCREngine(layoutEngine).Chunks()
+	if err != nil {
+		t.Fatalf("Chunks() error: %v", err)
+	}
+	if len(chunks.Chunks) == 0 || chunks.Chunks[0].Metadata.BBox == nil {
+		t.Fatalf("got chunks %+v, want a chunk with a bounding box", chunks.Chunks)
+	}
+	if box := chunks.Chunks[0].Metadata.BBox; !near(box.X, 72) {
+		t.Errorf("got chunk box %+v, want it at x 72", box)
+	}
+}
+
+func TestOCRLayoutExcludeHeaders(t *testing.T) {
+	// Every scanned page carries the same running header, 36pt from the top
+	engine := ocr.EngineFunc(func(ctx context.Context, img []byte, opts ocr.Options) ([]ocr.Word, error) {
+		words, err := layoutEngine(ctx, img, opts)
+		return append(words, ocr.Word{Text: "Quarterly", BBox: image.Rect(300, 114, 700, 150), Confidence: 90, Block: 2, Paragraph: 3, Line: 1}), err
+	})
+	scan := "0 0 1 rg 72 72 100 100 re f"
+	data := buildPagesPDF(scan, scan, scan)
+
+	hasHeader := func(lines []layout.Line) bool {
+		for _, line := range lines {
+			if strings.Contains(line.Text, "Quarterly") {
+				return true
+			}
+		}
+		return false
+	}
+
+	lines, err := FromBytes(data, "").OCREngine(engine).Lines()
+	if err != nil {
+		t.Fatalf("Lines() error: %v", err)
+	}
+	if !hasHeader(lines) {
+		t.Fatal("got no header line, want the OCR words of the running header")
+	}
+
+	lines, err = FromBytes(data, "").OCREngine(engine).ExcludeHeaders().Lines()
+	if err != nil {
+		t.Fatalf("Lines() error: %v", err)
+	}
+	if hasHeader(lines) {
+		t.Error("got the running header with ExcludeHeaders")
+	}
+	body := 0
+	for _, line := range lines {
+		if strings.Contains(line.Text, "scanned body") {
+			body++
+		}
+	}
+	if body != 3*3 {
+		t.Errorf("got %d body lines, want the 3 of each page", body)
+	}
+}
+
+// uprightEngine is layoutEngine, reporting through orientation detection
+// that pages must be turned by rotate degrees to be upright
+type uprightEngine struct {
+	rotate int
+}
+
+func (u uprightEngine) Recognize(ctx context.Context, img []byte, opts ocr.Options) ([]ocr.Word, error) {
+	return layoutEngine.Recognize(ctx, img, opts)
+}
+
+func (u uprightEngine) DetectOrientation(ctx context.Context, img []byte) (int, error) {
+	return u.rotate, nil
+}
+
+func TestOCRPreprocess(t *testing.T) {
+	data := buildTextPDF("0 0 1 rg 72 72 100 100 re f")
+	opts := preprocess.Options{Orientation: true, Binarize: preprocess.BinarizeOtsu}
+
+	ext := FromBytes(data, "").OCREngine(uprightEngine{rotate: 180})
+	preprocessed := ext.OCRPreprocess(opts)
+	if ext.options.ocrPreprocess != nil {
+		t.Error("OCRPreprocess modified the original extractor")
+	}
+	doc, warnings, err := preprocessed.Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	var got []string
+	for _, w := range warnings {
+		if w.Code == WarningOCRPreprocess {
+			got = append(got, w.Message)
+		}
+	}
+	want := "Page 1: Preprocessed for OCR: rotated 180°, binarized (Otsu)"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("got preprocessing warnings %q, want %q", got, want)
+	}
+
+	// The heading's words were found in the turned image, 300 to 400 pixels
+	// down; turned back they lie 2900 to 3000 pixels down the page as
+	// rendered, 72pt to 96pt above its bottom
+	var heading model.BBox
+	for _, el := range doc.Pages[0].Elements {
+		if te, ok := el.(model.TextElement); ok && strings.Contains(te.GetText(), "Introduction") {
+			heading = el.BoundingBox()
+		}
+	}
+	if !near(heading.Y, 72) || !near(heading.Top(), 96) {
+		t.Errorf("got heading box %+v, want it 72pt to 96pt up the page", heading)
+	}
+
+	// Preprocessing that changes nothing leaves no warning
+	_, warnings, err = FromBytes(data, "").OCREngine(layoutEngine).OCRPreprocess(preprocess.Options{}).Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	for _, w := range warnings {
+		if w.Code == WarningOCRPreprocess {
+			t.Errorf("got a preprocessing warning for no preprocessing: %v", w)
+		}
+	}
+}
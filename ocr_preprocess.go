@@ -0,0 +1,113 @@
+package tabula
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+
+	"github.com/tsawler/tabula/ocr"
+	"github.com/tsawler/tabula/ocr/preprocess"
+)
+
+// OCRPreprocess cleans up scanned pages before OCR: turning them upright,
+// straightening skewed text lines, binarizing, and removing dark borders
+// and specks, as opts enables. preprocess.DefaultOptions enables every
+// step. When the OCR engine can detect orientation itself (as
+// ocr.ExecEngine does with tesseract's OSD) it is used, falling back to a
+// text-line heuristic. Word positions are mapped back through the
+// corrections, so layout analysis is unaffected. A warning records the
+// steps applied to each page. Has effect only when OCR is available.
+//
+// Example:
+//
+//	text, warnings, err := tabula.Open("scan.pdf").
+//	    OCREngine(ocr.NewExecEngine("")).
+//	    OCRPreprocess(preprocess.DefaultOptions()).
+//	    Text()
+func (e *Extractor) OCRPreprocess(opts preprocess.Options) *Extractor {
+	newExt := e.clone()
+	newExt.options.ocrPreprocess = &opts
+	return newExt
+}
+
+// preprocessForOCR runs a prepared image through the configured
+// preprocessing and returns it re-encoded, with its placement on the page
+// adjusted, and the steps applied. When preprocessing is off, changes
+// nothing, or the image can't be decoded, the image is returned as is.
+func (e *Extractor) preprocessForOCR(ctx context.Context, engine ocr.Engine, pi preparedImage) (preparedImage, []string) {
+	if e.options.ocrPreprocess == nil {
+		return pi, nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(pi.png))
+	if err != nil {
+		return pi, nil
+	}
+
+	opts := *e.options.ocrPreprocess
+	if d, ok := engine.(ocr.OrientationDetector); ok && opts.Orientation && opts.OrientationDetector == nil {
+		opts.OrientationDetector = engineOrientation{ctx: ctx, detector: d}
+	}
+	res := preprocess.Process(img, opts)
+	if len(res.Applied) == 0 {
+		return pi, nil
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, res.Image); err != nil {
+		return pi, nil
+	}
+	return preparedImage{
+		png:    buf.Bytes(),
+		dpi:    pi.dpi,
+		toPage: res.ToSource.Multiply(pi.toPage),
+	}, res.Applied
+}
+
+// engineOrientation adapts an OCR engine's orientation detection to
+// preprocess.OrientationDetector
+type engineOrientation struct {
+	ctx      context.Context
+	detector ocr.OrientationDetector
+}
+
+// DetectOrientation encodes img as PNG and asks the engine.
+func (o engineOrientation) DetectOrientation(img image.Image) (int, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return 0, err
+	}
+	return o.detector.DetectOrientation(o.ctx, buf.Bytes())
+}
+
+// mergeSteps appends the preprocessing steps not already in steps
+func mergeSteps(steps, more []string) []string {
+	for _, m := range more {
+		found := false
+		for _, s := range steps {
+			if s == m {
+				found = true
+				break
+			}
+		}
+		if !found {
+			steps = append(steps, m)
+		}
+	}
+	return steps
+}
+
+// ocrWarnings builds the warnings for a page whose text came from OCR: the
+// OCR-fallback warning, and one listing the preprocessing applied, if any.
+func ocrWarnings(pageNum int, res ocrResult) []Warning {
+	warnings := []Warning{ocrWarning(pageNum)}
+	if len(res.preprocessed) > 0 {
+		warnings = append(warnings, Warning{
+			Code:    WarningOCRPreprocess,
+			Message: fmt.Sprintf("Page %d: Preprocessed for OCR: %s", pageNum, strings.Join(res.preprocessed, ", ")),
+		})
+	}
+	return warnings
+}
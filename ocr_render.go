@@ -1,7 +1,10 @@
 package tabula
 
 import (
+	"bytes"
 	"context"
+	"image/png"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,14 +14,32 @@ import (
 
 	"github.com/tsawler/tabula/format"
 	"github.com/tsawler/tabula/ocr"
+	"github.com/tsawler/tabula/pages"
+	"github.com/tsawler/tabula/render"
+)
+
+// OCRRenderer selects how whole pages are rasterized for OCR.
+type OCRRenderer int
+
+const (
+	// RendererBuiltin rasterizes pages with the pure-Go render package. It
+	// needs no external tools and is the default.
+	RendererBuiltin OCRRenderer = iota
+
+	// RendererPdftoppm rasterizes pages with pdftoppm (poppler-utils), which
+	// draws shadings, patterns and Type 3 fonts the built-in renderer skips.
+	// When pdftoppm isn't installed the built-in renderer is used instead, as
+	// it is for documents opened with a Password: pdftoppm would need the
+	// password on its command line, where other local users can see it.
+	RendererPdftoppm
 )
 
 // ocrRenderDPI is the rasterization resolution for a full-page OCR render. 300
 // DPI is the standard floor for reliable Tesseract accuracy on body text.
 const ocrRenderDPI = 300
 
-// ocrRenderTimeout bounds a single page render so a pathological PDF can't hang
-// ingestion on the pdftoppm subprocess.
+// ocrRenderTimeout bounds a single pdftoppm render so a pathological PDF can't
+// hang ingestion on the subprocess.
 const ocrRenderTimeout = 60 * time.Second
 
 var (
@@ -42,6 +63,7 @@ func pdftoppmBin() string {
 
 // ocrCompiledIn reports whether OCR support was built in (-tags ocr) with a
 // usable Tesseract engine. Cached so we don't rasterize pages we couldn't OCR.
+// An engine set with OCREngine makes OCR available regardless.
 func ocrCompiledIn() bool {
 	ocrAvailOnce.Do(func() {
 		c, err := ocr.New()
@@ -55,20 +77,80 @@ func ocrCompiledIn() bool {
 }
 
 // renderPageForOCR rasterizes one page (1-based) of the source PDF to a single
-// full-page image, via pdftoppm, ready for OCR. Unlike extracting a page's
-// embedded images, a full-page render captures vector-outlined text and vector
-// artwork — in many illustrated or design-heavy PDFs the body text is drawn as
-// path outlines, not fonts, so it's invisible to both native text extraction and
-// image extraction, and only a rasterized render exposes it to OCR.
+// full-page image ready for OCR. Unlike extracting a page's embedded images, a
+// full-page render captures vector-outlined text and vector artwork — in many
+// illustrated or design-heavy PDFs the body text is drawn as path outlines, not
+// fonts, so it's invisible to both native text extraction and image extraction,
+// and only a rasterized render exposes it to OCR.
+//
+// The page is rendered by the built-in renderer unless OCRRenderer selected
+// pdftoppm, it is installed and no password was given. Both render the page's CropBox upright
+// (honoring /Rotate) at ocrRenderDPI.
+//
+// The images carry the inverse of the page's mapping to pixels, which places
+// the words OCR finds back on the page.
 //
 // Returns nil (so the caller falls back to embedded-image OCR) when the source
-// isn't a file-backed PDF, OCR isn't compiled in, pdftoppm isn't installed, or
-// the render fails.
-func (e *Extractor) renderPageForOCR(pageNum int) []preparedImage {
-	if e.format != format.PDF || e.filename == "" || pageNum < 1 {
+// isn't a PDF, OCR is unavailable, or the render fails.
+func (e *Extractor) renderPageForOCR(page *pages.Page, pageNum int) []preparedImage {
+	if e.format != format.PDF || pageNum < 1 {
 		return nil
 	}
-	if !ocrCompiledIn() {
+	if e.options.ocrEngine == nil && !ocrCompiledIn() {
+		return nil
+	}
+	var rendered []preparedImage
+	if e.usePdftoppm() {
+		rendered = e.renderPagePdftoppm(pageNum)
+	}
+	if len(rendered) == 0 {
+		rendered = e.renderPageBuiltin(pageNum)
+	}
+	if m, err := render.PageMatrix(page, render.Options{DPI: ocrRenderDPI}); err == nil {
+		if toPage, ok := m.Inverse(); ok {
+			for i := range rendered {
+				rendered[i].toPage = toPage
+			}
+		}
+	}
+	return rendered
+}
+
+// usePdftoppm reports whether pages should be rendered with pdftoppm. A
+// password is never passed to it, since command lines are visible to other
+// processes.
+func (e *Extractor) usePdftoppm() bool {
+	return e.options.ocrRenderer == RendererPdftoppm && e.options.password == ""
+}
+
+// renderPageBuiltin rasterizes one page (1-based) with the render package. It
+// reads through the extractor's reader, so it must run on the reader
+// goroutine.
+func (e *Extractor) renderPageBuiltin(pageNum int) []preparedImage {
+	if e.reader == nil {
+		return nil
+	}
+	img, err := render.Page(e.reader, pageNum-1, render.Options{DPI: ocrRenderDPI})
+	if err != nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil
+	}
+	return []preparedImage{{png: buf.Bytes(), dpi: ocrRenderDPI}}
+}
+
+// renderPagePdftoppm rasterizes one page (1-based) of the source PDF via
+// pdftoppm. In-memory sources (FromBytes, FromReaderAt) are piped to pdftoppm
+// on stdin.
+//
+// The render is bounded by ocrRenderTimeout and by the extractor's context, so
+// canceling the extraction kills an in-flight pdftoppm.
+//
+// Returns nil when pdftoppm isn't installed or the render fails.
+func (e *Extractor) renderPagePdftoppm(pageNum int) []preparedImage {
+	if e.filename == "" && e.source == nil {
 		return nil
 	}
 	bin := pdftoppmBin()
@@ -83,21 +165,31 @@ func (e *Extractor) renderPageForOCR(pageNum int) []preparedImage {
 	defer os.RemoveAll(tmp)
 	prefix := filepath.Join(tmp, "page")
 
-	ctx, cancel := context.WithTimeout(context.Background(), ocrRenderTimeout)
+	ctx, cancel := context.WithTimeout(e.context(), ocrRenderTimeout)
 	defer cancel()
 	// -singlefile writes exactly <prefix>.png (no page-number suffix); -r sets the
-	// DPI; pdftoppm renders the page upright (honoring /Rotate) and flattens
-	// vector text, vector art, and images into one bitmap.
-	cmd := exec.CommandContext(ctx, bin, "-png", "-r", strconv.Itoa(ocrRenderDPI),
-		"-f", strconv.Itoa(pageNum), "-l", strconv.Itoa(pageNum),
-		"-singlefile", e.filename, prefix)
+	// DPI; -cropbox renders the CropBox like the built-in renderer, so words
+	// map back onto the page the same way; pdftoppm renders the page upright
+	// (honoring /Rotate) and flattens vector text, vector art, and images into
+	// one bitmap.
+	args := []string{"-png", "-r", strconv.Itoa(ocrRenderDPI), "-cropbox",
+		"-f", strconv.Itoa(pageNum), "-l", strconv.Itoa(pageNum), "-singlefile"}
+	input := e.filename
+	if e.source != nil {
+		input = "-" // read the PDF from stdin
+	}
+	args = append(args, input, prefix)
+	cmd := exec.CommandContext(ctx, bin, args...)
+	if e.source != nil {
+		cmd.Stdin = io.NewSectionReader(e.source, 0, e.sourceSize)
+	}
 	if err := cmd.Run(); err != nil {
 		return nil
 	}
 
-	png, err := os.ReadFile(prefix + ".png")
-	if err != nil || len(png) == 0 {
+	data, err := os.ReadFile(prefix + ".png")
+	if err != nil || len(data) == 0 {
 		return nil
 	}
-	return []preparedImage{{png: png, dpi: ocrRenderDPI}}
+	return []preparedImage{{png: data, dpi: ocrRenderDPI}}
 }
@@ -8,6 +8,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tsawler/tabula/model"
 	"github.com/tsawler/tabula/rag"
@@ -15,7 +16,8 @@ import (
 
 // Reader provides access to ODT document content.
 type Reader struct {
-	zipReader     *zip.ReadCloser
+	zipReader     *zip.Reader
+	closer        io.Closer // underlying archive when opened from a path
 	content       *documentXML
 	contentStyles *contentStylesXML
 	docStyles     *stylesXML
@@ -46,13 +48,36 @@ func Open(filename string) (*Reader, error) {
 		return nil, fmt.Errorf("opening ZIP archive: %w", err)
 	}
 
+	r, err := newReader(&zr.Reader)
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	r.closer = zr
+
+	return r, nil
+}
+
+// OpenReader opens an ODT document from an io.ReaderAt of the given size,
+// such as a bytes.Reader over a file held in memory. The caller keeps
+// ownership of ra; Close does not close it.
+func OpenReader(ra io.ReaderAt, size int64) (*Reader, error) {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, fmt.Errorf("opening ZIP archive: %w", err)
+	}
+
+	return newReader(zr)
+}
+
+// newReader parses the document parts of an opened archive.
+func newReader(zr *zip.Reader) (*Reader, error) {
 	r := &Reader{
 		zipReader: zr,
 	}
 
 	// Validate required files exist
 	if err := r.validate(); err != nil {
-		zr.Close()
 		return nil, err
 	}
 
@@ -71,7 +96,6 @@ func Open(filename string) (*Reader, error) {
 
 	// Parse content.xml (main document content)
 	if err := r.parseContent(); err != nil {
-		zr.Close()
 		return nil, fmt.Errorf("parsing content: %w", err)
 	}
 
@@ -86,9 +110,9 @@ func Open(filename string) (*Reader, error) {
 
 // Close releases resources associated with the Reader.
 func (r *Reader) Close() error {
-	if r.zipReader != nil {
-		err := r.zipReader.Close()
-		r.zipReader = nil
+	if r.closer != nil {
+		err := r.closer.Close()
+		r.closer = nil
 		return err
 	}
 	return nil
@@ -531,15 +555,7 @@ func (r *Reader) Document() (*model.Document, error) {
 	doc := model.NewDocument()
 
 	// Set metadata
-	if r.meta != nil && r.meta.Meta != nil {
-		doc.Metadata.Title = r.meta.Meta.Title
-		doc.Metadata.Author = r.meta.Meta.Creator
-		if doc.Metadata.Author == "" {
-			doc.Metadata.Author = r.meta.Meta.InitialCreator
-		}
-		doc.Metadata.Subject = r.meta.Meta.Subject
-		doc.Metadata.Creator = r.meta.Meta.Generator
-	}
+	doc.Metadata = r.Metadata()
 
 	// Create single page for entire document (ODT doesn't have fixed pages)
 	page := model.NewPage(612, 792) // Standard US Letter dimensions
@@ -668,21 +684,47 @@ func (r *Reader) Document() (*model.Document, error) {
 	return doc, nil
 }
 
-// Metadata returns document metadata.
+// Metadata returns document metadata, including user-defined properties.
 func (r *Reader) Metadata() model.Metadata {
-	meta := model.Metadata{}
+	meta := model.Metadata{Custom: make(map[string]string)}
 	if r.meta != nil && r.meta.Meta != nil {
-		meta.Title = r.meta.Meta.Title
-		meta.Author = r.meta.Meta.Creator
+		m := r.meta.Meta
+		meta.Title = m.Title
+		meta.Author = m.Creator
 		if meta.Author == "" {
-			meta.Author = r.meta.Meta.InitialCreator
+			meta.Author = m.InitialCreator
+		}
+		meta.Subject = m.Subject
+		meta.Creator = m.Generator
+		for _, kw := range m.Keywords {
+			if kw = strings.TrimSpace(kw); kw != "" {
+				meta.Keywords = append(meta.Keywords, kw)
+			}
+		}
+		meta.Language = m.Language
+		meta.CreationDate = parseMetaDate(m.CreationDate)
+		meta.ModDate = parseMetaDate(m.Date)
+		for _, p := range m.UserDefined {
+			if p.Name != "" {
+				meta.Custom[p.Name] = strings.TrimSpace(p.Value)
+			}
 		}
-		meta.Subject = r.meta.Meta.Subject
-		meta.Creator = r.meta.Meta.Generator
 	}
 	return meta
 }
 
+// parseMetaDate parses an ODF date-time, which may omit the time zone, in
+// which case it is taken as UTC
+func parseMetaDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 // Tables returns all parsed tables from the document.
 func (r *Reader) Tables() []ParsedTable {
 	return r.tables
@@ -2,6 +2,7 @@ package odt
 
 import (
 	"archive/zip"
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
@@ -63,6 +64,11 @@ func createTestODT(t *testing.T, content string) string {
     <dc:title>Test Document</dc:title>
     <dc:creator>Test Author</dc:creator>
     <meta:generator>Test Generator</meta:generator>
+    <meta:keyword>alpha</meta:keyword>
+    <meta:keyword>beta</meta:keyword>
+    <dc:language>en-US</dc:language>
+    <meta:creation-date>2023-04-15T10:30:00</meta:creation-date>
+    <meta:user-defined meta:name="Department">Research</meta:user-defined>
   </office:meta>
 </office:document-meta>`))
 
@@ -99,6 +105,37 @@ func TestOpenAndClose(t *testing.T) {
 	}
 }
 
+func TestOpenReader(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+                         xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+  <office:body>
+    <office:text>
+      <text:p>Hello from memory</text:p>
+    </office:text>
+  </office:body>
+</office:document-content>`
+
+	data, err := os.ReadFile(createTestODT(t, content))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	r, err := OpenReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer r.Close()
+
+	text, err := r.Text()
+	if err != nil {
+		t.Fatalf("Text failed: %v", err)
+	}
+	if !strings.Contains(text, "Hello from memory") {
+		t.Errorf("expected 'Hello from memory' in text, got: %s", text)
+	}
+}
+
 func TestText(t *testing.T) {
 	content := `<?xml version="1.0" encoding="UTF-8"?>
 <office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
@@ -246,6 +283,18 @@ func TestMetadata(t *testing.T) {
 	if meta.Author != "Test Author" {
 		t.Errorf("expected author 'Test Author', got '%s'", meta.Author)
 	}
+	if len(meta.Keywords) != 2 || meta.Keywords[1] != "beta" {
+		t.Errorf("expected keywords [alpha beta], got %v", meta.Keywords)
+	}
+	if meta.Language != "en-US" {
+		t.Errorf("expected language 'en-US', got '%s'", meta.Language)
+	}
+	if meta.CreationDate.Year() != 2023 {
+		t.Errorf("expected creation date in 2023, got %v", meta.CreationDate)
+	}
+	if meta.Custom["Department"] != "Research" {
+		t.Errorf("expected custom property Department, got %v", meta.Custom)
+	}
 }
 
 func TestDocument(t *testing.T) {
@@ -258,14 +258,21 @@ type metaXML struct {
 
 // metaInfoXML represents the office:meta element.
 type metaInfoXML struct {
-	Title          string `xml:"title"`
-	Description    string `xml:"description"`
-	Subject        string `xml:"subject"`
-	Keyword        string `xml:"keyword"`
-	InitialCreator string `xml:"initial-creator"`
-	Creator        string `xml:"creator"`
-	CreationDate   string `xml:"creation-date"`
-	Date           string `xml:"date"` // Last modified
-	Generator      string `xml:"generator"`
-	Language       string `xml:"language"`
+	Title          string           `xml:"title"`
+	Description    string           `xml:"description"`
+	Subject        string           `xml:"subject"`
+	Keywords       []string         `xml:"keyword"`
+	InitialCreator string           `xml:"initial-creator"`
+	Creator        string           `xml:"creator"`
+	CreationDate   string           `xml:"creation-date"`
+	Date           string           `xml:"date"` // Last modified
+	Generator      string           `xml:"generator"`
+	Language       string           `xml:"language"`
+	UserDefined    []userDefinedXML `xml:"user-defined"`
+}
+
+// userDefinedXML represents a meta:user-defined custom property.
+type userDefinedXML struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
 }
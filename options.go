@@ -1,11 +1,15 @@
 package tabula
 
-import "github.com/tsawler/tabula/ocr"
+import (
+	"github.com/tsawler/tabula/ocr"
+	"github.com/tsawler/tabula/ocr/preprocess"
+)
 
 // ExtractOptions holds configuration for text extraction.
 type ExtractOptions struct {
 	// Page selection (1-indexed in API, stored as-is)
-	pages []int
+	pages      []int
+	pageLabels []string // Printed page labels, resolved when pages are selected
 
 	// Layout filtering
 	excludeHeaders bool
@@ -15,11 +19,32 @@ type ExtractOptions struct {
 	byColumn       bool
 	preserveLayout bool
 	joinParagraphs bool // Join lines within paragraphs with spaces instead of newlines
+	keepHiddenText bool // Keep text that is not visible (VisibleTextOnly(false))
 
-	// OCR options (scanned-PDF fallback only; effective with -tags ocr)
+	// Annotations (PDF only)
+	includeComments bool // Add comment annotations to Document() pages as elements
+
+	// Forms (PDF only)
+	inlineFormFields bool // Add form field values to page text at their widgets
+
+	// Attachments (PDF only)
+	includeAttachments bool // Extract embedded files and append their content
+
+	// OCR options (scanned-PDF fallback only; effective with -tags ocr or
+	// an engine)
+	ocrEngine   ocr.Engine      // engine used instead of Tesseract, or nil
 	ocrLanguage string          // Tesseract language(s), e.g. "eng" or "eng+fra"
 	ocrPSM      ocr.PageSegMode // page segmentation mode
 	ocrPSMSet   bool            // whether ocrPSM was explicitly set
+	ocrRenderer OCRRenderer     // how whole pages are rasterized for OCR
+
+	ocrPreprocess *preprocess.Options // image cleanup before OCR, or nil for none
+
+	// Rendering (PDF only)
+	snapshotFormat ImageFormat // encoding of RenderPage and RenderRegion images
+
+	// Decryption (PDF only)
+	password string // user or owner password for encrypted PDFs
 }
 
 // defaultOptions returns the default extraction options.
@@ -37,14 +62,23 @@ func defaultOptions() ExtractOptions {
 // clone creates a deep copy of ExtractOptions.
 func (o ExtractOptions) clone() ExtractOptions {
 	newOpts := ExtractOptions{
-		excludeHeaders: o.excludeHeaders,
-		excludeFooters: o.excludeFooters,
-		byColumn:       o.byColumn,
-		preserveLayout: o.preserveLayout,
-		joinParagraphs: o.joinParagraphs,
-		ocrLanguage:    o.ocrLanguage,
-		ocrPSM:         o.ocrPSM,
-		ocrPSMSet:      o.ocrPSMSet,
+		excludeHeaders:     o.excludeHeaders,
+		excludeFooters:     o.excludeFooters,
+		byColumn:           o.byColumn,
+		preserveLayout:     o.preserveLayout,
+		joinParagraphs:     o.joinParagraphs,
+		keepHiddenText:     o.keepHiddenText,
+		includeComments:    o.includeComments,
+		inlineFormFields:   o.inlineFormFields,
+		includeAttachments: o.includeAttachments,
+		ocrEngine:          o.ocrEngine,
+		ocrLanguage:        o.ocrLanguage,
+		ocrPSM:             o.ocrPSM,
+		ocrPSMSet:          o.ocrPSMSet,
+		ocrRenderer:        o.ocrRenderer,
+		ocrPreprocess:      o.ocrPreprocess,
+		snapshotFormat:     o.snapshotFormat,
+		password:           o.password,
 	}
 
 	// Deep copy pages slice
@@ -52,6 +86,10 @@ func (o ExtractOptions) clone() ExtractOptions {
 		newOpts.pages = make([]int, len(o.pages))
 		copy(newOpts.pages, o.pages)
 	}
+	if o.pageLabels != nil {
+		newOpts.pageLabels = make([]string, len(o.pageLabels))
+		copy(newOpts.pageLabels, o.pageLabels)
+	}
 
 	return newOpts
 }
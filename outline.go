@@ -0,0 +1,109 @@
+package tabula
+
+import (
+	"github.com/tsawler/tabula/format"
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/pages"
+)
+
+// Outline returns the document outline as nested entries. For PDFs this is
+// the bookmark tree, with each destination resolved to a page number and,
+// where the destination specifies one, a vertical position. For other
+// formats, and PDFs without bookmarks, the outline is built from the
+// detected headings.
+// This is a terminal operation that closes the underlying reader.
+//
+// Example:
+//
+//	outline, _, err := tabula.Open("manual.pdf").Outline()
+//	for _, entry := range outline {
+//	    fmt.Printf("%s (page %d)\n", entry.Title, entry.Page)
+//	}
+func (e *Extractor) Outline() ([]*model.OutlineEntry, []Warning, error) {
+	if e.err != nil {
+		return nil, nil, e.err
+	}
+
+	if err := e.ensureReader(); err != nil {
+		return nil, nil, err
+	}
+	defer e.Close()
+
+	if e.format == format.PDF {
+		items, err := e.reader.Outline()
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(items) > 0 {
+			return outlineEntries(items), e.warnings, nil
+		}
+	}
+
+	doc, warnings, err := e.Document()
+	if err != nil {
+		return nil, warnings, err
+	}
+	return outlineFromHeadings(doc.TableOfContents()), warnings, nil
+}
+
+// loadOutline reads the PDF's bookmarks for Document(). The outline is
+// optional, so a malformed one is ignored.
+func (e *Extractor) loadOutline() []*model.OutlineEntry {
+	if e.reader == nil {
+		return nil
+	}
+	items, err := e.reader.Outline()
+	if err != nil {
+		return nil
+	}
+	return outlineEntries(items)
+}
+
+// outlineEntries converts outline items to model entries with 1-based pages
+func outlineEntries(items []*pages.OutlineItem) []*model.OutlineEntry {
+	if len(items) == 0 {
+		return nil
+	}
+	entries := make([]*model.OutlineEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, &model.OutlineEntry{
+			Title:    item.Title,
+			Page:     item.Dest.PageIndex + 1,
+			Y:        item.Dest.Y,
+			HasY:     item.Dest.HasY,
+			Children: outlineEntries(item.Children),
+		})
+	}
+	return entries
+}
+
+// outlineFromHeadings nests table of contents entries by heading level
+func outlineFromHeadings(toc []model.TOCEntry) []*model.OutlineEntry {
+	type open struct {
+		level int
+		entry *model.OutlineEntry
+	}
+
+	var roots []*model.OutlineEntry
+	var stack []open
+	for _, h := range toc {
+		entry := &model.OutlineEntry{
+			Title: h.Text,
+			Page:  h.Page,
+			Y:     h.BBox.Top(),
+			HasY:  h.BBox.Height > 0,
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].level >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, entry)
+		} else {
+			parent := stack[len(stack)-1].entry
+			parent.Children = append(parent.Children, entry)
+		}
+		stack = append(stack, open{level: h.Level, entry: entry})
+	}
+	return roots
+}
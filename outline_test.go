@@ -0,0 +1,108 @@
+package tabula
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/rag"
+)
+
+// buildOutlinePDF assembles a two-page PDF with a bookmark to page 1 that
+// has a nested bookmark, and a bookmark to page 2 by named destination.
+func buildOutlinePDF() []byte {
+	page1 := "BT /F1 12 Tf 72 700 Td (Getting started) Tj ET"
+	page2 := "BT /F1 12 Tf 72 700 Td (Reference material) Tj ET"
+	return buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R /Outlines 8 0 R /Names << /Dests << /Names [(ref) [6 0 R /FitH 650]] >> >> >>",
+		"<< /Type /Pages /Kids [4 0 R 6 0 R] /Count 2 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 3 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(page1), page1),
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 7 0 R /Resources << /Font << /F1 3 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(page2), page2),
+		"<< /Type /Outlines /First 9 0 R /Last 10 0 R /Count 2 >>",
+		"<< /Title (Start) /Parent 8 0 R /Next 10 0 R /First 11 0 R /Last 11 0 R /Dest [4 0 R /XYZ 0 720 null] >>",
+		"<< /Title (Reference) /Parent 8 0 R /Prev 9 0 R /A << /S /GoTo /D (ref) >> >>",
+		"<< /Title (Install) /Parent 9 0 R /Dest [4 0 R /Fit] >>",
+	)
+}
+
+func TestOutline(t *testing.T) {
+	outline, _, err := FromBytes(buildOutlinePDF(), "").Outline()
+	if err != nil {
+		t.Fatalf("Outline() error: %v", err)
+	}
+	if len(outline) != 2 {
+		t.Fatalf("got %d entries, want 2", len(outline))
+	}
+
+	start := outline[0]
+	if start.Title != "Start" || start.Page != 1 || !start.HasY || start.Y != 720 {
+		t.Errorf("entry 0 = %+v, want Start on page 1 at 720", start)
+	}
+	if len(start.Children) != 1 || start.Children[0].Title != "Install" || start.Children[0].HasY {
+		t.Errorf("children = %+v, want Install without a position", start.Children)
+	}
+
+	ref := outline[1]
+	if ref.Title != "Reference" || ref.Page != 2 || ref.Y != 650 {
+		t.Errorf("entry 1 = %+v, want Reference on page 2 at 650", ref)
+	}
+}
+
+func TestDocumentOutline(t *testing.T) {
+	doc, _, err := FromBytes(buildOutlinePDF(), "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	if len(doc.Outline) != 2 || doc.Outline[1].Page != 2 {
+		t.Errorf("doc.Outline = %+v, want the two bookmarks", doc.Outline)
+	}
+}
+
+func TestOutlineSectionsWithPageSelection(t *testing.T) {
+	// Page 2 alone is the document's first page, but its bookmark still
+	// names it page 2. The page's text is moved below the bookmark.
+	data := bytes.Replace(buildOutlinePDF(), []byte("72 700 Td (Reference material)"), []byte("72 600 Td (Reference material)"), 1)
+	doc, _, err := FromBytes(data, "").Pages(2).Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	if page := doc.Pages[0]; page.Number != 1 || page.SourcePage() != 2 {
+		t.Fatalf("got page %d from source page %d, want page 1 from page 2", page.Number, page.SourcePage())
+	}
+
+	config := rag.DefaultChunkerConfig()
+	config.UseOutline = true
+	chunks := rag.NewDocumentChunkerWithConfig(config, rag.DefaultSizeConfig()).ChunkDocument(doc)
+	if len(chunks.Chunks) == 0 {
+		t.Fatal("got no chunks")
+	}
+	if got := chunks.Chunks[0].Metadata.SectionPath; len(got) != 1 || got[0] != "Reference" {
+		t.Errorf("got section path %v, want [Reference]", got)
+	}
+}
+
+func TestOutlineFromHeadings(t *testing.T) {
+	toc := []model.TOCEntry{
+		{Level: 1, Text: "One", Page: 1},
+		{Level: 2, Text: "One.A", Page: 1},
+		{Level: 3, Text: "One.A.i", Page: 2},
+		{Level: 2, Text: "One.B", Page: 2},
+		{Level: 1, Text: "Two", Page: 3},
+	}
+
+	outline := outlineFromHeadings(toc)
+	if len(outline) != 2 || outline[1].Title != "Two" || outline[1].Page != 3 {
+		t.Fatalf("got %+v, want roots One and Two", outline)
+	}
+	one := outline[0]
+	if len(one.Children) != 2 || one.Children[1].Title != "One.B" {
+		t.Fatalf("One children = %+v, want One.A and One.B", one.Children)
+	}
+	if kids := one.Children[0].Children; len(kids) != 1 || kids[0].Title != "One.A.i" {
+		t.Errorf("One.A children = %+v, want One.A.i", kids)
+	}
+}
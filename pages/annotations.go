@@ -0,0 +1,196 @@
+package pages
+
+import (
+	"fmt"
+
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/font"
+)
+
+// Annotation is an annotation from a page's /Annots array
+// (PDF 32000-1:2008, 12.5)
+type Annotation struct {
+	Subtype    string     // Annotation subtype, e.g. "Link", "Text", "Highlight"
+	Rect       [4]float64 // Normalized rectangle: llx, lly, urx, ury
+	QuadPoints []float64  // Quadrilaterals covered by text markup annotations (8 numbers each)
+	Contents   string     // Text to display, or the comment for markup annotations
+	Author     string     // Author of a markup annotation (/T)
+	Subject    string     // Subject of a markup annotation (/Subj)
+	Name       string     // Icon name of a text annotation or stamp name (/Name)
+	URI        string     // Target of a URI action
+	Dest       Destination
+	HasDest    bool // Whether the annotation links to a destination in the document
+}
+
+// Annotations reads the page's annotations. Link destinations, including
+// named destinations, are resolved through catalog and pageIndex; catalog
+// may be nil, in which case only explicit destinations are resolved. Popup
+// annotations, which only display their parent's contents, are omitted.
+func (p *Page) Annotations(catalog *Catalog, pageIndex PageIndexFunc) ([]*Annotation, error) {
+	annotsObj := p.dict.Get("Annots")
+	if annotsObj == nil {
+		return nil, nil // Optional
+	}
+
+	resolved, err := p.resolver.Resolve(annotsObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve /Annots: %w", err)
+	}
+
+	annots, ok := resolved.(core.Array)
+	if !ok {
+		return nil, fmt.Errorf("invalid /Annots type: %T", resolved)
+	}
+
+	if catalog == nil {
+		catalog = NewCatalog(core.Dict{}, p.resolver)
+	}
+
+	var result []*Annotation
+	for _, annotObj := range annots {
+		dict, ok := catalog.resolve(annotObj).(core.Dict)
+		if !ok {
+			continue
+		}
+		subtype, _ := dict.Get("Subtype").(core.Name)
+		if subtype == "Popup" {
+			continue
+		}
+
+		annot := &Annotation{
+			Subtype:  string(subtype),
+			Contents: catalog.textString(dict.Get("Contents")),
+			Author:   catalog.textString(dict.Get("T")),
+			Subject:  catalog.textString(dict.Get("Subj")),
+		}
+		if name, ok := dict.Get("Name").(core.Name); ok {
+			annot.Name = string(name)
+		}
+
+		rect := catalog.numbers(dict.Get("Rect"))
+		if len(rect) != 4 {
+			continue
+		}
+		annot.Rect = normalizeRect(rect)
+
+		if quads := catalog.numbers(dict.Get("QuadPoints")); len(quads) >= 8 {
+			annot.QuadPoints = quads[:len(quads)/8*8]
+		}
+
+		if action, ok := catalog.resolve(dict.Get("A")).(core.Dict); ok {
+			if s, _ := action.Get("S").(core.Name); s == "URI" {
+				if uri, ok := catalog.resolve(action.Get("URI")).(core.String); ok {
+					annot.URI = string(uri)
+				}
+			}
+		}
+		if dest, ok := catalog.itemDestination(dict, pageIndex); ok {
+			annot.Dest = dest
+			annot.HasDest = true
+		}
+
+		result = append(result, annot)
+	}
+	return result, nil
+}
+
+// textString resolves and decodes a PDF text string
+func (c *Catalog) textString(obj core.Object) string {
+	s, ok := c.resolve(obj).(core.String)
+	if !ok {
+		return ""
+	}
+	return font.DecodeTextString([]byte(s))
+}
+
+// numbers resolves an array of numbers, skipping any other elements
+func (c *Catalog) numbers(obj core.Object) []float64 {
+	arr, ok := c.resolve(obj).(core.Array)
+	if !ok {
+		return nil
+	}
+	result := make([]float64, 0, len(arr))
+	for _, elem := range arr {
+		if n, ok := destNumber(c.resolve(elem)); ok {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// normalizeRect orders a rectangle's corners as llx, lly, urx, ury
+func normalizeRect(r []float64) [4]float64 {
+	rect := [4]float64{r[0], r[1], r[2], r[3]}
+	if rect[0] > rect[2] {
+		rect[0], rect[2] = rect[2], rect[0]
+	}
+	if rect[1] > rect[3] {
+		rect[1], rect[3] = rect[3], rect[1]
+	}
+	return rect
+}
+
+// Contains reports whether a point lies inside the annotation: inside one of
+// its quadrilaterals if it has QuadPoints, otherwise inside its rectangle
+func (a *Annotation) Contains(x, y float64) bool {
+	for _, quad := range a.Quads() {
+		if x >= quad[0] && x <= quad[2] && y >= quad[1] && y <= quad[3] {
+			return true
+		}
+	}
+	return false
+}
+
+// Quads returns the bounding rectangle (llx, lly, urx, ury) of each
+// quadrilateral in QuadPoints, or the annotation's rectangle if it has none
+func (a *Annotation) Quads() [][4]float64 {
+	if len(a.QuadPoints) < 8 {
+		return [][4]float64{a.Rect}
+	}
+
+	quads := make([][4]float64, 0, len(a.QuadPoints)/8)
+	for i := 0; i+8 <= len(a.QuadPoints); i += 8 {
+		q := a.QuadPoints[i : i+8]
+		rect := [4]float64{q[0], q[1], q[0], q[1]}
+		for j := 2; j < 8; j += 2 {
+			if q[j] < rect[0] {
+				rect[0] = q[j]
+			}
+			if q[j] > rect[2] {
+				rect[2] = q[j]
+			}
+			if q[j+1] < rect[1] {
+				rect[1] = q[j+1]
+			}
+			if q[j+1] > rect[3] {
+				rect[3] = q[j+1]
+			}
+		}
+		quads = append(quads, rect)
+	}
+	return quads
+}
+
+// AnnotationRefs returns the references in the page's /Annots array, for
+// finding the page of an annotation, such as a form field widget, that does
+// not name its page
+func (p *Page) AnnotationRefs() []core.IndirectRef {
+	var refs []core.IndirectRef
+	for _, annotObj := range p.AnnotationObjects() {
+		if ref, ok := annotObj.(core.IndirectRef); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// AnnotationObjects returns the entries of the page's /Annots array:
+// annotation dictionaries or references to them
+func (p *Page) AnnotationObjects() core.Array {
+	resolved, err := p.resolver.Resolve(p.dict.Get("Annots"))
+	if err != nil {
+		return nil
+	}
+	annots, _ := resolved.(core.Array)
+	return annots
+}
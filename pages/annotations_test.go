@@ -0,0 +1,94 @@
+package pages
+
+import (
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+func TestPageAnnotations(t *testing.T) {
+	resolver := newMockResolver()
+	page0 := core.IndirectRef{Number: 100}
+
+	resolver.AddObject(10, core.Dict{
+		"Subtype": core.Name("Link"),
+		"Rect":    core.Array{core.Int(200), core.Int(710), core.Int(72), core.Int(690)},
+		"A":       core.Dict{"S": core.Name("URI"), "URI": core.String("https://example.com")},
+	})
+	resolver.AddObject(11, core.Dict{
+		"Subtype": core.Name("Link"),
+		"Rect":    core.Array{core.Int(72), core.Int(600), core.Int(150), core.Int(615)},
+		"Dest":    core.String("intro"),
+	})
+	resolver.AddObject(12, core.Dict{
+		"Subtype":    core.Name("Highlight"),
+		"Rect":       core.Array{core.Int(70), core.Int(500), core.Int(300), core.Int(540)},
+		"QuadPoints": core.Array{core.Int(72), core.Int(535), core.Int(290), core.Int(535), core.Int(72), core.Int(522), core.Int(290), core.Int(522)},
+		"T":          core.String("\xfe\xff\x00A\x00n\x00n"),
+		"Contents":   core.String("Check this figure"),
+		"Popup":      core.IndirectRef{Number: 13},
+	})
+	resolver.AddObject(13, core.Dict{
+		"Subtype": core.Name("Popup"),
+		"Rect":    core.Array{core.Int(300), core.Int(500), core.Int(400), core.Int(600)},
+	})
+	resolver.AddObject(14, core.Dict{
+		"Subtype": core.Name("Stamp"),
+		"Rect":    core.Array{core.Int(400), core.Int(700), core.Int(500), core.Int(750)},
+		"Name":    core.Name("Approved"),
+	})
+	resolver.AddObject(15, core.Dict{"Subtype": core.Name("Text")}) // no /Rect
+
+	catalog := NewCatalog(core.Dict{
+		"Type":  core.Name("Catalog"),
+		"Dests": core.Dict{"intro": core.Dict{"D": core.Array{page0, core.Name("FitH"), core.Int(720)}}},
+	}, resolver)
+
+	page := NewPage(core.Dict{
+		"Type": core.Name("Page"),
+		"Annots": core.Array{
+			core.IndirectRef{Number: 10}, core.IndirectRef{Number: 11}, core.IndirectRef{Number: 12},
+			core.IndirectRef{Number: 13}, core.IndirectRef{Number: 14}, core.IndirectRef{Number: 15},
+		},
+	}, nil, resolver)
+
+	annots, err := page.Annotations(catalog, testPageIndex)
+	if err != nil {
+		t.Fatalf("Annotations failed: %v", err)
+	}
+	if len(annots) != 4 {
+		t.Fatalf("expected 4 annotations (popup and rectless note skipped), got %d", len(annots))
+	}
+
+	uri := annots[0]
+	if uri.URI != "https://example.com" || uri.HasDest {
+		t.Errorf("expected URI link, got %+v", uri)
+	}
+	if uri.Rect != [4]float64{72, 690, 200, 710} {
+		t.Errorf("expected normalized rect, got %v", uri.Rect)
+	}
+
+	if internal := annots[1]; !internal.HasDest || internal.Dest.PageIndex != 0 || internal.Dest.Y != 720 {
+		t.Errorf("expected named destination on page 0 at 720, got %+v", internal)
+	}
+
+	highlight := annots[2]
+	if highlight.Author != "Ann" || highlight.Contents != "Check this figure" {
+		t.Errorf("unexpected highlight author/contents: %q, %q", highlight.Author, highlight.Contents)
+	}
+	if !highlight.Contains(100, 528) || highlight.Contains(100, 510) {
+		t.Error("expected Contains to use the quadrilateral, not the rectangle")
+	}
+
+	if stamp := annots[3]; stamp.Subtype != "Stamp" || stamp.Name != "Approved" {
+		t.Errorf("expected Approved stamp, got %+v", stamp)
+	}
+}
+
+func TestPageAnnotationsNone(t *testing.T) {
+	page := NewPage(core.Dict{"Type": core.Name("Page")}, nil, newMockResolver())
+	annots, err := page.Annotations(nil, nil)
+	if err != nil || annots != nil {
+		t.Errorf("expected no annotations, got %v, %v", annots, err)
+	}
+}
@@ -33,6 +33,30 @@
 //
 // Resources can be inherited from parent page tree nodes.
 //
+// # Outline and Destinations
+//
+// [Catalog.Outline] reads the document outline (bookmarks) as a tree of
+// [OutlineItem] values. Each item's [Destination] is resolved to a page
+// index and, for XYZ, FitH, FitBH and FitR destinations, a vertical
+// position. Named destinations are looked up in the catalog's /Dests
+// dictionary and the /Dests name tree:
+//
+//	catalog := pages.NewCatalog(catalogDict, resolver)
+//	items, _ := catalog.Outline(pageIndex)
+//
+// # Page Labels
+//
+// [Catalog.PageLabels] reads the /PageLabels number tree and returns the
+// printed label of every page, such as "iv" for roman front matter or
+// "A-3" for an appendix, combining each range's prefix, numbering style
+// and starting number.
+//
+// # Annotations
+//
+// [Page.Annotations] reads a page's /Annots array: link targets (URIs and
+// resolved destinations), comment text and authors, and the QuadPoints of
+// text markup annotations, which [Annotation.Contains] tests points against.
+//
 // # Object Resolution
 //
 // The [ObjectResolver] interface abstracts object lookup:
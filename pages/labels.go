@@ -0,0 +1,146 @@
+package pages
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// Largest /St honored. Roman and letter numerals grow with their value, so
+// theirs is kept low enough that a malformed start can't make every label
+// of the range megabytes long: the letter label of 10000 is 385 characters.
+const (
+	maxLabelStart   = 1 << 30
+	maxNumeralStart = 10000
+)
+
+// labelRange is an entry of the /PageLabels number tree: the labeling
+// style that applies from a page index up to the next range
+type labelRange struct {
+	start  int    // Index of the range's first page
+	style  string // D, R, r, A or a; empty for prefix-only labels
+	prefix string
+	first  int // Numeric value of the first page's label
+}
+
+// PageLabels returns the printed label of each of the document's pages,
+// such as "iv" or "A-3", as defined by the catalog's /PageLabels number
+// tree (PDF 32000-1:2008, 12.4.2). Pages before the first range are
+// labeled with their decimal page numbers. It returns nil if the document
+// defines no labels.
+func (c *Catalog) PageLabels(pageCount int) []string {
+	root, ok := c.resolve(c.dict.Get("PageLabels")).(core.Dict)
+	if !ok || pageCount <= 0 {
+		return nil
+	}
+
+	var ranges []labelRange
+	c.collectLabelRanges(root, &ranges, 0)
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+
+	labels := make([]string, pageCount)
+	next := 0
+	var current *labelRange
+	for i := range labels {
+		for next < len(ranges) && ranges[next].start <= i {
+			current = &ranges[next]
+			next++
+		}
+		if current == nil {
+			labels[i] = strconv.Itoa(i + 1)
+			continue
+		}
+		labels[i] = current.prefix + formatLabelNumber(current.style, current.first+i-current.start)
+	}
+	return labels
+}
+
+// collectLabelRanges gathers the ranges of a number tree node and its kids
+func (c *Catalog) collectLabelRanges(node core.Dict, ranges *[]labelRange, depth int) {
+	if node == nil || depth > maxTreeDepth {
+		return
+	}
+
+	if nums, ok := c.resolve(node.Get("Nums")).(core.Array); ok {
+		for i := 0; i+1 < len(nums); i += 2 {
+			start, ok := c.resolve(nums[i]).(core.Int)
+			if !ok || start < 0 {
+				continue
+			}
+			dict, ok := c.resolve(nums[i+1]).(core.Dict)
+			if !ok {
+				continue
+			}
+			r := labelRange{start: int(start), prefix: c.textString(dict.Get("P")), first: 1}
+			if style, ok := c.resolve(dict.Get("S")).(core.Name); ok {
+				r.style = string(style)
+			}
+			if st, ok := c.resolve(dict.Get("St")).(core.Int); ok && st >= 1 {
+				limit := core.Int(maxLabelStart)
+				if r.style != "D" {
+					limit = maxNumeralStart
+				}
+				if st > limit {
+					st = limit
+				}
+				r.first = int(st)
+			}
+			*ranges = append(*ranges, r)
+		}
+	}
+
+	kids, _ := c.resolve(node.Get("Kids")).(core.Array)
+	for _, kidObj := range kids {
+		if kid, ok := c.resolve(kidObj).(core.Dict); ok {
+			c.collectLabelRanges(kid, ranges, depth+1)
+		}
+	}
+}
+
+// formatLabelNumber formats the numeric part of a page label in a
+// numbering style. Labels without a style have no numeric part.
+func formatLabelNumber(style string, n int) string {
+	switch style {
+	case "D":
+		return strconv.Itoa(n)
+	case "R":
+		return romanNumeral(n)
+	case "r":
+		return strings.ToLower(romanNumeral(n))
+	case "A":
+		return letterNumeral(n)
+	case "a":
+		return strings.ToLower(letterNumeral(n))
+	}
+	return ""
+}
+
+// romanNumeral formats a positive number as an uppercase roman numeral
+func romanNumeral(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+
+	var sb strings.Builder
+	for i, v := range values {
+		for n >= v {
+			sb.WriteString(symbols[i])
+			n -= v
+		}
+	}
+	return sb.String()
+}
+
+// letterNumeral formats a positive number in the letter style of page
+// labels: A to Z, then AA to ZZ, AAA to ZZZ and so on
+func letterNumeral(n int) string {
+	if n < 1 {
+		return ""
+	}
+	letter := string(rune('A' + (n-1)%26))
+	return strings.Repeat(letter, (n-1)/26+1)
+}
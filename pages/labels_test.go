@@ -0,0 +1,92 @@
+package pages
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+func TestCatalogPageLabels(t *testing.T) {
+	resolver := newMockResolver()
+	// A two-level number tree: roman front matter, arabic body, then an
+	// appendix with a prefix, a letter-numbered part and a prefix-only page
+	resolver.AddObject(10, core.Dict{"Kids": core.Array{core.IndirectRef{Number: 11}, core.IndirectRef{Number: 12}}})
+	resolver.AddObject(11, core.Dict{
+		"Limits": core.Array{core.Int(0), core.Int(3)},
+		"Nums": core.Array{
+			core.Int(0), core.Dict{"S": core.Name("r")},
+			core.Int(3), core.Dict{"S": core.Name("D")},
+		},
+	})
+	resolver.AddObject(12, core.Dict{
+		"Limits": core.Array{core.Int(5), core.Int(9)},
+		"Nums": core.Array{
+			core.Int(5), core.IndirectRef{Number: 13},
+			core.Int(7), core.Dict{"S": core.Name("A"), "St": core.Int(26)},
+			core.Int(9), core.Dict{"P": core.String("Cover")},
+		},
+	})
+	resolver.AddObject(13, core.Dict{"S": core.Name("D"), "P": core.String("A-"), "St": core.Int(3)})
+
+	catalog := NewCatalog(core.Dict{"PageLabels": core.IndirectRef{Number: 10}}, resolver)
+	labels := catalog.PageLabels(10)
+
+	want := []string{"i", "ii", "iii", "1", "2", "A-3", "A-4", "Z", "AA", "Cover"}
+	if len(labels) != len(want) {
+		t.Fatalf("Expected %d labels, got %v", len(want), labels)
+	}
+	for i, w := range want {
+		if labels[i] != w {
+			t.Errorf("Page %d: expected label %q, got %q", i, w, labels[i])
+		}
+	}
+}
+
+func TestCatalogPageLabelsNone(t *testing.T) {
+	catalog := NewCatalog(core.Dict{"Type": core.Name("Catalog")}, newMockResolver())
+	if labels := catalog.PageLabels(3); labels != nil {
+		t.Errorf("Expected no labels, got %v", labels)
+	}
+}
+
+func TestCatalogPageLabelsLargeStart(t *testing.T) {
+	// Roman and letter numbering can't start beyond maxNumeralStart;
+	// decimal numbering can
+	catalog := NewCatalog(core.Dict{"PageLabels": core.Dict{
+		"Nums": core.Array{
+			core.Int(0), core.Dict{"S": core.Name("R"), "St": core.Int(1 << 40)},
+			core.Int(1), core.Dict{"S": core.Name("a"), "St": core.Int(1 << 40)},
+			core.Int(2), core.Dict{"S": core.Name("D"), "St": core.Int(123456)},
+		},
+	}}, newMockResolver())
+	labels := catalog.PageLabels(3)
+
+	want := []string{strings.Repeat("M", 10), strings.Repeat("p", 385), "123456"}
+	for i, w := range want {
+		if labels[i] != w {
+			t.Errorf("Page %d: expected label of %d characters, got %d", i, len(w), len(labels[i]))
+		}
+	}
+}
+
+func TestFormatLabelNumber(t *testing.T) {
+	tests := []struct {
+		style string
+		n     int
+		want  string
+	}{
+		{"D", 42, "42"},
+		{"R", 1994, "MCMXCIV"},
+		{"r", 4, "iv"},
+		{"A", 1, "A"},
+		{"A", 27, "AA"},
+		{"a", 53, "aaa"},
+		{"", 5, ""},
+	}
+	for _, tt := range tests {
+		if got := formatLabelNumber(tt.style, tt.n); got != tt.want {
+			t.Errorf("formatLabelNumber(%q, %d): expected %q, got %q", tt.style, tt.n, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package pages
+
+import "github.com/tsawler/tabula/core"
+
+// maxTreeDepth limits how deep name and outline trees are followed
+const maxTreeDepth = 64
+
+// lookupNameTree finds a key in a name tree (PDF 32000-1:2008, 7.9.6),
+// using each intermediate node's /Limits to pick the kid to descend into
+func (c *Catalog) lookupNameTree(node core.Dict, key string, depth int) core.Object {
+	if node == nil || depth > maxTreeDepth {
+		return nil
+	}
+
+	if names, ok := c.resolve(node.Get("Names")).(core.Array); ok {
+		for i := 0; i+1 < len(names); i += 2 {
+			if name, ok := c.resolve(names[i]).(core.String); ok && string(name) == key {
+				return c.resolve(names[i+1])
+			}
+		}
+	}
+
+	kids, _ := c.resolve(node.Get("Kids")).(core.Array)
+	for _, kidObj := range kids {
+		kid, ok := c.resolve(kidObj).(core.Dict)
+		if !ok {
+			continue
+		}
+		if limits, ok := c.resolve(kid.Get("Limits")).(core.Array); ok && len(limits) == 2 {
+			low, _ := c.resolve(limits[0]).(core.String)
+			high, _ := c.resolve(limits[1]).(core.String)
+			if key < string(low) || key > string(high) {
+				continue
+			}
+		}
+		if value := c.lookupNameTree(kid, key, depth+1); value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// nameTree returns the root of one of the catalog's /Names trees, such as
+// Dests or EmbeddedFiles
+func (c *Catalog) nameTree(name string) core.Dict {
+	names, ok := c.resolve(c.dict.Get("Names")).(core.Dict)
+	if !ok {
+		return nil
+	}
+	root, _ := c.resolve(names.Get(name)).(core.Dict)
+	return root
+}
+
+// resolve follows an indirect reference, returning nil if it cannot be resolved
+func (c *Catalog) resolve(obj core.Object) core.Object {
+	if obj == nil {
+		return nil
+	}
+	resolved, err := c.resolver.Resolve(obj)
+	if err != nil {
+		return nil
+	}
+	return resolved
+}
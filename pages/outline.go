@@ -0,0 +1,203 @@
+package pages
+
+import (
+	"fmt"
+
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/font"
+)
+
+// maxOutlineItems bounds the number of outline items read, guarding against
+// malformed /Next chains
+const maxOutlineItems = 100000
+
+// PageIndexFunc maps a page object reference to its 0-based page index
+type PageIndexFunc func(ref core.IndirectRef) (int, bool)
+
+// Destination is a resolved position in the document (PDF 32000-1:2008, 12.3.2)
+type Destination struct {
+	PageIndex int     // 0-based page index, -1 if the page could not be resolved
+	Y         float64 // Vertical position on the page in PDF user space (if HasY)
+	HasY      bool    // Whether the destination specifies a vertical position
+}
+
+// OutlineItem is one entry in the document outline (bookmarks)
+type OutlineItem struct {
+	Title    string
+	Dest     Destination
+	Children []*OutlineItem
+}
+
+// Outline reads the document outline from the catalog's /Outlines entry.
+// Destinations are resolved to page indexes with pageIndex. It returns nil
+// and no error if the document has no outline.
+func (c *Catalog) Outline(pageIndex PageIndexFunc) ([]*OutlineItem, error) {
+	outlinesRef := c.dict.Get("Outlines")
+	if outlinesRef == nil {
+		return nil, nil // Optional
+	}
+
+	outlinesObj, err := c.resolver.Resolve(outlinesRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve /Outlines: %w", err)
+	}
+
+	outlines, ok := outlinesObj.(core.Dict)
+	if !ok {
+		return nil, fmt.Errorf("invalid /Outlines type: %T", outlinesObj)
+	}
+
+	w := &outlineWalker{
+		catalog:   c,
+		pageIndex: pageIndex,
+		visited:   make(map[int]bool),
+	}
+	return w.items(outlines.Get("First"), 0), nil
+}
+
+// outlineWalker reads outline items, remembering visited objects so that
+// cyclic /First and /Next links terminate
+type outlineWalker struct {
+	catalog   *Catalog
+	pageIndex PageIndexFunc
+	visited   map[int]bool
+	count     int
+}
+
+// items reads a sibling chain starting at first
+func (w *outlineWalker) items(first core.Object, depth int) []*OutlineItem {
+	if depth > maxTreeDepth {
+		return nil
+	}
+
+	var items []*OutlineItem
+	for obj := first; obj != nil && w.count < maxOutlineItems; {
+		if ref, ok := obj.(core.IndirectRef); ok {
+			if w.visited[ref.Number] {
+				break
+			}
+			w.visited[ref.Number] = true
+		}
+
+		dict, ok := w.catalog.resolve(obj).(core.Dict)
+		if !ok {
+			break
+		}
+		w.count++
+
+		item := &OutlineItem{Dest: Destination{PageIndex: -1}}
+		if title, ok := w.catalog.resolve(dict.Get("Title")).(core.String); ok {
+			item.Title = font.DecodeTextString([]byte(title))
+		}
+		if dest, ok := w.catalog.itemDestination(dict, w.pageIndex); ok {
+			item.Dest = dest
+		}
+		item.Children = w.items(dict.Get("First"), depth+1)
+
+		items = append(items, item)
+		obj = dict.Get("Next")
+	}
+	return items
+}
+
+// itemDestination returns the destination of an outline item or link
+// annotation, taken from /Dest or from a GoTo action in /A
+func (c *Catalog) itemDestination(dict core.Dict, pageIndex PageIndexFunc) (Destination, bool) {
+	if dest := dict.Get("Dest"); dest != nil {
+		return c.ResolveDestination(dest, pageIndex)
+	}
+
+	action, ok := c.resolve(dict.Get("A")).(core.Dict)
+	if !ok {
+		return Destination{}, false
+	}
+	if s, _ := action.Get("S").(core.Name); s != "GoTo" {
+		return Destination{}, false
+	}
+	return c.ResolveDestination(action.Get("D"), pageIndex)
+}
+
+// ResolveDestination resolves an explicit destination array or a named
+// destination (a name or string) to a page index and vertical position
+func (c *Catalog) ResolveDestination(dest core.Object, pageIndex PageIndexFunc) (Destination, bool) {
+	switch d := c.resolve(dest).(type) {
+	case core.Name:
+		dest = c.NamedDestination(string(d))
+	case core.String:
+		dest = c.NamedDestination(string(d))
+	}
+
+	arr, ok := c.resolve(dest).(core.Array)
+	if !ok || len(arr) == 0 {
+		return Destination{}, false
+	}
+
+	result := Destination{PageIndex: -1}
+	switch page := arr[0].(type) {
+	case core.IndirectRef:
+		if pageIndex != nil {
+			if index, ok := pageIndex(page); ok {
+				result.PageIndex = index
+			}
+		}
+	case core.Int:
+		// Remote go-to actions and some broken writers give the page number
+		result.PageIndex = int(page)
+	}
+
+	// The position of the top coordinate depends on the fit type
+	var fit core.Name
+	if len(arr) > 1 {
+		fit, _ = arr[1].(core.Name)
+	}
+	top := -1
+	switch fit {
+	case "XYZ":
+		top = 3
+	case "FitH", "FitBH":
+		top = 2
+	case "FitR":
+		top = 5
+	}
+	if top > 0 && top < len(arr) {
+		if y, ok := destNumber(arr[top]); ok {
+			result.Y = y
+			result.HasY = true
+		}
+	}
+
+	return result, true
+}
+
+// NamedDestination looks up a named destination in the catalog's /Dests
+// dictionary (PDF 1.1) or the /Dests name tree in /Names (PDF 1.2 and
+// later). It returns the explicit destination array, or nil if not found.
+func (c *Catalog) NamedDestination(name string) core.Object {
+	var value core.Object
+	if dests, ok := c.resolve(c.dict.Get("Dests")).(core.Dict); ok {
+		value = c.resolve(dests.Get(name))
+	}
+	if value == nil {
+		value = c.lookupNameTree(c.nameTree("Dests"), name, 0)
+	}
+
+	// The value may be a dictionary whose /D entry holds the destination
+	if dict, ok := value.(core.Dict); ok {
+		value = c.resolve(dict.Get("D"))
+	}
+	if _, ok := value.(core.Array); !ok {
+		return nil
+	}
+	return value
+}
+
+// destNumber converts a destination coordinate, which may be null, to a float
+func destNumber(obj core.Object) (float64, bool) {
+	switch v := obj.(type) {
+	case core.Int:
+		return float64(v), true
+	case core.Real:
+		return float64(v), true
+	}
+	return 0, false
+}
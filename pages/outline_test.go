@@ -0,0 +1,121 @@
+package pages
+
+import (
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// testPageIndex maps page objects 100 and 101 to pages 0 and 1
+func testPageIndex(ref core.IndirectRef) (int, bool) {
+	switch ref.Number {
+	case 100:
+		return 0, true
+	case 101:
+		return 1, true
+	}
+	return 0, false
+}
+
+func TestCatalogOutline(t *testing.T) {
+	resolver := newMockResolver()
+	page0 := core.IndirectRef{Number: 100}
+	page1 := core.IndirectRef{Number: 101}
+
+	resolver.AddObject(10, core.Dict{"First": core.IndirectRef{Number: 11}})
+	resolver.AddObject(11, core.Dict{
+		"Title": core.String("Introduction"),
+		"Dest":  core.Array{page0, core.Name("XYZ"), core.Int(72), core.Int(700), core.Null{}},
+		"First": core.IndirectRef{Number: 12},
+		"Next":  core.IndirectRef{Number: 14},
+	})
+	resolver.AddObject(12, core.Dict{
+		"Title": core.String("Background"),
+		"A":     core.Dict{"S": core.Name("GoTo"), "D": core.Array{page0, core.Name("FitH"), core.Real(400.5)}},
+		"Next":  core.IndirectRef{Number: 13},
+	})
+	resolver.AddObject(13, core.Dict{
+		"Title": core.String("Scope"),
+		"Dest":  core.Name("scope"),
+	})
+	resolver.AddObject(14, core.Dict{
+		"Title": core.String("\xfe\xff\x00R\x00e\x00s\x00u\x00l\x00t\x00s"),
+		"Dest":  core.String("results"),
+		"Next":  core.IndirectRef{Number: 15},
+	})
+	resolver.AddObject(15, core.Dict{
+		"Title": core.String("External"),
+		"A":     core.Dict{"S": core.Name("URI"), "URI": core.String("https://example.com")},
+		"Next":  core.IndirectRef{Number: 11}, // cycle back to the first item
+	})
+
+	// Named destinations: /Dests dictionary and a two-level /Names tree
+	resolver.AddObject(20, core.Dict{"Kids": core.Array{core.IndirectRef{Number: 21}, core.IndirectRef{Number: 22}}})
+	resolver.AddObject(21, core.Dict{
+		"Limits": core.Array{core.String("a"), core.String("m")},
+		"Names":  core.Array{core.String("intro"), core.Array{page0, core.Name("Fit")}},
+	})
+	resolver.AddObject(22, core.Dict{
+		"Limits": core.Array{core.String("n"), core.String("z")},
+		"Names": core.Array{
+			core.String("results"), core.Dict{"D": core.Array{page1, core.Name("FitR"), core.Int(0), core.Int(100), core.Int(500), core.Int(650)}},
+		},
+	})
+
+	catalog := NewCatalog(core.Dict{
+		"Type":     core.Name("Catalog"),
+		"Outlines": core.IndirectRef{Number: 10},
+		"Dests":    core.Dict{"scope": core.Array{page1, core.Name("XYZ"), core.Null{}, core.Int(300), core.Null{}}},
+		"Names":    core.Dict{"Dests": core.IndirectRef{Number: 20}},
+	}, resolver)
+
+	items, err := catalog.Outline(testPageIndex)
+	if err != nil {
+		t.Fatalf("Outline failed: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 top-level items, got %d", len(items))
+	}
+
+	intro := items[0]
+	if intro.Title != "Introduction" || intro.Dest.PageIndex != 0 || !intro.Dest.HasY || intro.Dest.Y != 700 {
+		t.Errorf("unexpected first item: %+v", intro)
+	}
+	if len(intro.Children) != 2 {
+		t.Fatalf("expected 2 children, got %d", len(intro.Children))
+	}
+	if got := intro.Children[0].Dest; got.PageIndex != 0 || got.Y != 400.5 {
+		t.Errorf("expected GoTo action destination on page 0 at 400.5, got %+v", got)
+	}
+	if got := intro.Children[1].Dest; got.PageIndex != 1 || got.Y != 300 {
+		t.Errorf("expected /Dests destination on page 1 at 300, got %+v", got)
+	}
+
+	results := items[1]
+	if results.Title != "Results" {
+		t.Errorf("expected UTF-16 title Results, got %q", results.Title)
+	}
+	if results.Dest.PageIndex != 1 || results.Dest.Y != 650 {
+		t.Errorf("expected name tree destination on page 1 at 650, got %+v", results.Dest)
+	}
+
+	if external := items[2]; external.Dest.PageIndex != -1 {
+		t.Errorf("expected unresolved destination for a URI action, got %+v", external.Dest)
+	}
+
+	dest, ok := catalog.ResolveDestination(core.String("intro"), testPageIndex)
+	if !ok || dest.PageIndex != 0 || dest.HasY {
+		t.Errorf("expected Fit destination on page 0 without Y, got %+v, %v", dest, ok)
+	}
+	if catalog.NamedDestination("missing") != nil {
+		t.Error("expected nil for a missing named destination")
+	}
+}
+
+func TestCatalogNoOutline(t *testing.T) {
+	catalog := NewCatalog(core.Dict{"Type": core.Name("Catalog")}, newMockResolver())
+	items, err := catalog.Outline(testPageIndex)
+	if err != nil || items != nil {
+		t.Errorf("expected no outline, got %v, %v", items, err)
+	}
+}
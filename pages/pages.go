@@ -92,7 +92,8 @@ func (c *Catalog) Version() string {
 type PageTree struct {
 	root     core.Dict
 	resolver ObjectResolver
-	pages    []*Page // Cached flattened page list
+	pages    []*Page     // Cached flattened page list
+	index    map[int]int // Page index by object number, built on demand
 }
 
 // NewPageTree creates a new page tree from the root pages dictionary
@@ -134,6 +135,27 @@ func (t *PageTree) GetPage(index int) (*Page, error) {
 	return t.pages[index], nil
 }
 
+// IndexOf returns the 0-based index of the page with the given object
+// reference, as used by structure elements, outlines and link destinations
+func (t *PageTree) IndexOf(ref core.IndirectRef) (int, bool) {
+	pages, err := t.Pages()
+	if err != nil {
+		return 0, false
+	}
+
+	if t.index == nil {
+		t.index = make(map[int]int, len(pages))
+		for i, page := range pages {
+			if page.ref != nil {
+				t.index[page.ref.Number] = i
+			}
+		}
+	}
+
+	i, ok := t.index[ref.Number]
+	return i, ok
+}
+
 // Pages returns all pages as a slice
 func (t *PageTree) Pages() ([]*Page, error) {
 	// Ensure pages are loaded
@@ -151,7 +173,7 @@ func (t *PageTree) loadPages() error {
 	t.pages = make([]*Page, 0)
 
 	// Start recursive traversal from root
-	if err := t.traversePageNode(t.root, nil); err != nil {
+	if err := t.traversePageNode(t.root, nil, nil); err != nil {
 		return fmt.Errorf("failed to traverse page tree: %w", err)
 	}
 
@@ -159,8 +181,9 @@ func (t *PageTree) loadPages() error {
 }
 
 // traversePageNode recursively traverses a page tree node
-// parent is the parent Pages dictionary for inheritable attributes
-func (t *PageTree) traversePageNode(node core.Dict, parent core.Dict) error {
+// parent is the parent Pages dictionary for inheritable attributes, and ref
+// the node's object reference if it was referenced indirectly
+func (t *PageTree) traversePageNode(node core.Dict, parent core.Dict, ref *core.IndirectRef) error {
 	// Get the type to determine if this is a Pages node or Page leaf
 	typeObj := node.Get("Type")
 	if typeObj == nil {
@@ -204,8 +227,13 @@ func (t *PageTree) traversePageNode(node core.Dict, parent core.Dict) error {
 				return fmt.Errorf("invalid kid type: %T", kidResolved)
 			}
 
+			var kidRef *core.IndirectRef
+			if r, ok := kidObj.(core.IndirectRef); ok {
+				kidRef = &r
+			}
+
 			// Recursively traverse child (passing current node as parent)
-			if err := t.traversePageNode(kidDict, node); err != nil {
+			if err := t.traversePageNode(kidDict, node, kidRef); err != nil {
 				return err
 			}
 		}
@@ -213,6 +241,7 @@ func (t *PageTree) traversePageNode(node core.Dict, parent core.Dict) error {
 	case "Page":
 		// Leaf node - create Page object
 		page := NewPage(node, parent, t.resolver)
+		page.ref = ref
 		t.pages = append(t.pages, page)
 
 	default:
@@ -227,6 +256,7 @@ type Page struct {
 	dict     core.Dict
 	parent   core.Dict // Parent Pages node (for inheritable attributes)
 	resolver ObjectResolver
+	ref      *core.IndirectRef // Object reference, if known
 }
 
 // NewPage creates a new page from a dictionary
@@ -238,6 +268,15 @@ func NewPage(dict core.Dict, parent core.Dict, resolver ObjectResolver) *Page {
 	}
 }
 
+// Ref returns the page's object reference, if the page tree referenced it
+// indirectly
+func (p *Page) Ref() (core.IndirectRef, bool) {
+	if p.ref == nil {
+		return core.IndirectRef{}, false
+	}
+	return *p.ref, true
+}
+
 // Type returns the page type (should be "Page")
 func (p *Page) Type() string {
 	if typeObj := p.dict.Get("Type"); typeObj != nil {
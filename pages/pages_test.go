@@ -276,6 +276,54 @@ func TestPageTreeNestedStructure(t *testing.T) {
 	}
 }
 
+// TestPageTreeIndexOf tests looking up pages by object reference
+func TestPageTreeIndexOf(t *testing.T) {
+	resolver := newMockResolver()
+
+	resolver.AddObject(10, core.Dict{"Type": core.Name("Page")})
+	resolver.AddObject(11, core.Dict{"Type": core.Name("Page")})
+	resolver.AddObject(20, core.Dict{
+		"Type":  core.Name("Pages"),
+		"Count": core.Int(1),
+		"Kids":  core.Array{core.IndirectRef{Number: 11}},
+	})
+
+	pagesRoot := core.Dict{
+		"Type":  core.Name("Pages"),
+		"Count": core.Int(3),
+		"Kids": core.Array{
+			core.IndirectRef{Number: 10},
+			core.IndirectRef{Number: 20},
+			core.Dict{"Type": core.Name("Page")}, // Direct page object
+		},
+	}
+
+	tree := NewPageTree(pagesRoot, resolver)
+
+	if idx, ok := tree.IndexOf(core.IndirectRef{Number: 11}); !ok || idx != 1 {
+		t.Errorf("expected page 11 at index 1, got %d (found %v)", idx, ok)
+	}
+	if _, ok := tree.IndexOf(core.IndirectRef{Number: 20}); ok {
+		t.Error("expected Pages node not to be found")
+	}
+
+	page, err := tree.GetPage(0)
+	if err != nil {
+		t.Fatalf("failed to get page: %v", err)
+	}
+	if ref, ok := page.Ref(); !ok || ref.Number != 10 {
+		t.Errorf("expected page ref 10, got %v (found %v)", ref, ok)
+	}
+
+	page, err = tree.GetPage(2)
+	if err != nil {
+		t.Fatalf("failed to get page: %v", err)
+	}
+	if _, ok := page.Ref(); ok {
+		t.Error("expected no ref for a direct page object")
+	}
+}
+
 // TestPageMediaBox tests getting MediaBox from page
 func TestPageMediaBox(t *testing.T) {
 	resolver := newMockResolver()
@@ -9,6 +9,7 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/tsawler/tabula/model"
 	"github.com/tsawler/tabula/rag"
@@ -16,12 +17,14 @@ import (
 
 // Reader provides access to PPTX document content.
 type Reader struct {
-	zipReader    *zip.ReadCloser
+	zipReader    *zip.Reader
+	closer       io.Closer // underlying archive when opened from a path
 	presentation *presentationXML
 	slides       []*Slide
 	slideRels    map[int]*relationshipsXML // Slide index -> relationships
 	coreProps    *corePropertiesXML
 	appProps     *appPropertiesXML
+	customProps  *customPropertiesXML
 	presRels     *relationshipsXML
 }
 
@@ -32,6 +35,30 @@ func Open(filename string) (*Reader, error) {
 		return nil, fmt.Errorf("opening ZIP archive: %w", err)
 	}
 
+	r, err := newReader(&zr.Reader)
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	r.closer = zr
+
+	return r, nil
+}
+
+// OpenReader opens a PPTX document from an io.ReaderAt of the given size,
+// such as a bytes.Reader over a file held in memory. The caller keeps
+// ownership of ra; Close does not close it.
+func OpenReader(ra io.ReaderAt, size int64) (*Reader, error) {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, fmt.Errorf("opening ZIP archive: %w", err)
+	}
+
+	return newReader(zr)
+}
+
+// newReader parses the document parts of an opened archive.
+func newReader(zr *zip.Reader) (*Reader, error) {
 	r := &Reader{
 		zipReader: zr,
 		slideRels: make(map[int]*relationshipsXML),
@@ -39,40 +66,37 @@ func Open(filename string) (*Reader, error) {
 
 	// Validate required files exist
 	if err := r.validate(); err != nil {
-		zr.Close()
 		return nil, err
 	}
 
 	// Parse presentation relationships first
 	if err := r.parseRelationships(); err != nil {
-		zr.Close()
 		return nil, fmt.Errorf("parsing relationships: %w", err)
 	}
 
 	// Parse presentation to get slide order
 	if err := r.parsePresentation(); err != nil {
-		zr.Close()
 		return nil, fmt.Errorf("parsing presentation: %w", err)
 	}
 
 	// Parse all slides
 	if err := r.parseSlides(); err != nil {
-		zr.Close()
 		return nil, fmt.Errorf("parsing slides: %w", err)
 	}
 
 	// Parse metadata (optional)
 	r.parseCoreProperties()
 	r.parseAppProperties()
+	r.parseCustomProperties()
 
 	return r, nil
 }
 
 // Close releases resources associated with the Reader.
 func (r *Reader) Close() error {
-	if r.zipReader != nil {
-		err := r.zipReader.Close()
-		r.zipReader = nil
+	if r.closer != nil {
+		err := r.closer.Close()
+		r.closer = nil
 		return err
 	}
 	return nil
@@ -521,6 +545,17 @@ func (r *Reader) parseAppProperties() {
 	xml.Unmarshal(data, r.appProps)
 }
 
+// parseCustomProperties parses custom document properties.
+func (r *Reader) parseCustomProperties() {
+	data, err := r.getFileContent("docProps/custom.xml")
+	if err != nil {
+		return
+	}
+
+	r.customProps = &customPropertiesXML{}
+	xml.Unmarshal(data, r.customProps)
+}
+
 // SlideCount returns the number of slides.
 func (r *Reader) SlideCount() int {
 	return len(r.slides)
@@ -784,9 +819,10 @@ func (r *Reader) MarkdownWithRAGOptions(extractOpts ExtractOptions, mdOpts rag.M
 	return result.String(), nil
 }
 
-// Metadata returns document metadata.
+// Metadata returns document metadata from the core, application and custom
+// document properties.
 func (r *Reader) Metadata() model.Metadata {
-	meta := model.Metadata{}
+	meta := model.Metadata{Custom: make(map[string]string)}
 	if r.coreProps != nil {
 		meta.Title = r.coreProps.Title
 		meta.Author = r.coreProps.Creator
@@ -797,10 +833,23 @@ func (r *Reader) Metadata() model.Metadata {
 				meta.Keywords[i] = strings.TrimSpace(kw)
 			}
 		}
+		meta.Language = r.coreProps.Language
+		if r.coreProps.Identifier != "" {
+			meta.Identifiers = []string{r.coreProps.Identifier}
+		}
+		meta.CreationDate, _ = time.Parse(time.RFC3339, r.coreProps.Created)
+		meta.ModDate, _ = time.Parse(time.RFC3339, r.coreProps.Modified)
 	}
 	if r.appProps != nil {
 		meta.Creator = r.appProps.Application
 	}
+	if r.customProps != nil {
+		for _, p := range r.customProps.Properties {
+			if p.Name != "" {
+				meta.Custom[p.Name] = strings.TrimSpace(p.Value.Text)
+			}
+		}
+	}
 	return meta
 }
 
@@ -809,20 +858,7 @@ func (r *Reader) Document() (*model.Document, error) {
 	doc := model.NewDocument()
 
 	// Set metadata
-	if r.coreProps != nil {
-		doc.Metadata.Title = r.coreProps.Title
-		doc.Metadata.Author = r.coreProps.Creator
-		doc.Metadata.Subject = r.coreProps.Subject
-		if r.coreProps.Keywords != "" {
-			doc.Metadata.Keywords = strings.Split(r.coreProps.Keywords, ",")
-			for i, kw := range doc.Metadata.Keywords {
-				doc.Metadata.Keywords[i] = strings.TrimSpace(kw)
-			}
-		}
-	}
-	if r.appProps != nil {
-		doc.Metadata.Creator = r.appProps.Application
-	}
+	doc.Metadata = r.Metadata()
 
 	// Each slide becomes a page
 	for _, slide := range r.slides {
@@ -2,6 +2,7 @@ package pptx
 
 import (
 	"archive/zip"
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
@@ -382,6 +383,26 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func TestOpenReader(t *testing.T) {
+	path := createMinimalPPTX(t)
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+
+	r, err := OpenReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenReader() failed: %v", err)
+	}
+	defer r.Close()
+
+	if r.SlideCount() != 1 {
+		t.Errorf("SlideCount() = %d, want 1", r.SlideCount())
+	}
+}
+
 func TestOpen_NotFound(t *testing.T) {
 	_, err := Open("/nonexistent/file.pptx")
 	if err == nil {
@@ -55,12 +55,30 @@ type ChunkMetadata struct {
 	// HeadingLevel is the level of the current section (1-6, 0 if no heading)
 	HeadingLevel int `json:"heading_level,omitempty"`
 
-	// PageStart is the starting page number (1-indexed)
+	// PageStart is the starting page number (1-indexed), by position in the
+	// extracted document: with a page selection, page 1 is the first page
+	// selected
 	PageStart int `json:"page_start"`
 
+	// SourcePageStart is the number of the starting page in the source file,
+	// as RenderPage and RenderRegion take it; 0 if unknown
+	SourcePageStart int `json:"source_page_start,omitempty"`
+
 	// PageEnd is the ending page number (1-indexed)
 	PageEnd int `json:"page_end"`
 
+	// PageLabelStart is the printed label of the starting page (e.g., "iv"),
+	// empty if the document defines no page labels
+	PageLabelStart string `json:"page_label_start,omitempty"`
+
+	// PageLabelEnd is the printed label of the ending page
+	PageLabelEnd string `json:"page_label_end,omitempty"`
+
+	// Attachment is the name of the embedded file the chunk was extracted
+	// from, empty for the document's own content. Files attached to an
+	// attachment are named by their path, e.g. "bundle.pdf/invoice.docx".
+	Attachment string `json:"attachment,omitempty"`
+
 	// ChunkIndex is the position of this chunk in the document (0-indexed)
 	ChunkIndex int `json:"chunk_index"`
 
@@ -97,8 +115,34 @@ type ChunkMetadata struct {
 	// EstimatedTokens is an estimated token count (chars/4 as rough approximation)
 	EstimatedTokens int `json:"estimated_tokens"`
 
-	// BBox is the bounding box of the chunk content on the page
+	// BBox is the bounding box of the chunk content on its first page
+	// (PageStart), in PDF points with the origin at the bottom left
 	BBox *model.BBox `json:"bbox,omitempty"`
+
+	// Links are the hyperlinks over the chunk's text, each at its place in
+	// Text; markdown output renders them as [text](uri)
+	Links []ChunkLink `json:"links,omitempty"`
+
+	// Styled are the runs of bold, italic or monospaced text in the chunk,
+	// each at its place in Text; markdown output renders them as **bold**,
+	// *italic* and `code`
+	Styled []ChunkStyled `json:"styled,omitempty"`
+}
+
+// ChunkLink is a hyperlink over a chunk's text
+type ChunkLink struct {
+	model.Link
+
+	// Offset is the byte offset of the link's text in the chunk's Text
+	Offset int `json:"offset"`
+}
+
+// ChunkStyled is a run of bold, italic or monospaced text in a chunk
+type ChunkStyled struct {
+	model.StyledText
+
+	// Offset is the byte offset of the run's text in the chunk's Text
+	Offset int `json:"offset"`
 }
 
 // Chunk represents a semantic unit of text extracted from a document for RAG
@@ -114,6 +158,10 @@ type Chunk struct {
 
 	// Metadata contains rich contextual information
 	Metadata ChunkMetadata `json:"metadata"`
+
+	// spans map ranges of Text to the page elements they came from, for
+	// placing links on the text under them
+	spans []textSpan
 }
 
 // NewChunk creates a new chunk with the given text and metadata
@@ -210,6 +258,13 @@ type ChunkerConfig struct {
 	// Default: true
 	DetectHeadings bool
 
+	// UseOutline builds section paths from the document outline (PDF
+	// bookmarks) instead of headings when the document has fewer than half
+	// as many headings as outline entries, as in PDFs whose headings are
+	// styled like body text.
+	// Default: false
+	UseOutline bool
+
 	// IDPrefix is a prefix for generated chunk IDs
 	// Default: "chunk"
 	IDPrefix string
@@ -372,6 +427,7 @@ func (c *Chunker) Chunk(doc *model.Document) (*ChunkResult, error) {
 	for _, chunk := range result.Chunks {
 		chunk.Metadata.TotalChunks = len(result.Chunks)
 	}
+	setPageInfo(result.Chunks, doc)
 
 	return result, nil
 }
@@ -212,3 +212,26 @@ func containsStr(s []string, v string) bool {
 	}
 	return false
 }
+
+func TestCoalesce_MergesBBoxesOnFirstPage(t *testing.T) {
+	dc := newTestChunker()
+	body := "This is a reasonably sized paragraph of body content that comfortably exceeds the minimum chunk size threshold so it is not itself merged away by the undersized pass."
+
+	heading := makeChunk("Chapter 1", []string{"Chapter 1"}, "heading")
+	heading.Metadata.BBox = &model.BBox{X: 72, Y: 700, Width: 100, Height: 20}
+	para := makeChunk(body, []string{"Chapter 1"}, "paragraph")
+	para.Metadata.BBox = &model.BBox{X: 72, Y: 600, Width: 400, Height: 80}
+	tail := makeChunk("A short tail.", []string{"Chapter 1"}, "paragraph")
+	tail.Metadata.PageStart, tail.Metadata.PageEnd = 2, 2
+	tail.Metadata.BBox = &model.BBox{X: 72, Y: 100, Width: 50, Height: 10}
+
+	got := dc.coalesceSmallChunks([]*Chunk{heading, para, tail})
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(got))
+	}
+	want := model.BBox{X: 72, Y: 600, Width: 400, Height: 120}
+	if b := got[0].Metadata.BBox; b == nil || *b != want {
+		t.Errorf("expected bbox %+v covering page 1, got %+v", want, b)
+	}
+}
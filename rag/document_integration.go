@@ -2,7 +2,9 @@ package rag
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"unicode"
 
 	"github.com/tsawler/tabula/model"
 )
@@ -55,13 +57,17 @@ func (dc *DocumentChunker) ChunkDocument(doc *model.Document) *ChunkCollection {
 	currentSection := []string{}
 	currentHeadingLevel := 0
 
+	// When the headings are weak but the PDF has bookmarks, the outline
+	// drives the section paths instead
+	outline := dc.outlineSectionsFor(doc)
+
 	// When the document carries no explicit heading structure, conservatively
 	// recover chapter-level headings from the text (e.g. scanned/OCR books).
-	promoteHeadings := dc.config.DetectHeadings && !documentHasHeadings(doc)
+	promoteHeadings := dc.config.DetectHeadings && outline == nil && !documentHasHeadings(doc)
 
 	// Process each page
 	for _, page := range doc.Pages {
-		pageChunks := dc.chunkPage(page, docTitle, &currentSection, &currentHeadingLevel, toc, &chunkIndex, promoteHeadings)
+		pageChunks := dc.chunkPage(page, docTitle, &currentSection, &currentHeadingLevel, toc, &chunkIndex, promoteHeadings, outline)
 		chunks = append(chunks, pageChunks...)
 	}
 
@@ -76,6 +82,7 @@ func (dc *DocumentChunker) ChunkDocument(doc *model.Document) *ChunkCollection {
 	for _, chunk := range chunks {
 		chunk.Metadata.TotalChunks = len(chunks)
 	}
+	setPageInfo(chunks, doc)
 
 	return NewChunkCollection(chunks)
 }
@@ -137,16 +144,32 @@ func (dc *DocumentChunker) mergeHeadingChunks(chunks []*Chunk, maxChars int) []*
 
 		if len(pending) > 0 {
 			var heads strings.Builder
-			for _, h := range pending {
+			headStarts := make([]int, len(pending))
+			for i, h := range pending {
 				if heads.Len() > 0 {
 					heads.WriteString("\n\n")
 				}
+				headStarts[i] = heads.Len()
 				heads.WriteString(strings.TrimSpace(h.Text))
 			}
 
 			if heads.Len()+2+len(c.Text) <= maxChars {
+				c.shiftOffsets(heads.Len() + 2 - leadingSpace(c.Text))
 				c.Text = heads.String() + "\n\n" + strings.TrimSpace(c.Text)
+				var links []ChunkLink
+				var styled []ChunkStyled
+				var spans []textSpan
+				for i, h := range pending {
+					h.shiftOffsets(headStarts[i] - leadingSpace(h.Text))
+					links = append(links, h.Metadata.Links...)
+					styled = append(styled, h.Metadata.Styled...)
+					spans = append(spans, h.spans...)
+				}
+				c.Metadata.Links = append(links, c.Metadata.Links...)
+				c.Metadata.Styled = append(styled, c.Metadata.Styled...)
+				c.spans = append(spans, c.spans...)
 				for _, h := range pending {
+					mergeChunkBBox(c, h)
 					for _, et := range h.Metadata.ElementTypes {
 						c.Metadata.ElementTypes = appendUnique(c.Metadata.ElementTypes, et)
 					}
@@ -261,12 +284,17 @@ func sameSectionPath(a, b []string) bool {
 func mergeChunkInto(dst, src *Chunk, srcAfter bool) {
 	dstText := strings.TrimSpace(dst.Text)
 	srcText := strings.TrimSpace(src.Text)
+	dst.shiftOffsets(-leadingSpace(dst.Text))
+	src.shiftOffsets(-leadingSpace(src.Text))
 	if srcAfter {
 		dst.Text = dstText + "\n\n" + srcText
+		src.shiftOffsets(len(dstText) + 2)
 	} else {
 		dst.Text = srcText + "\n\n" + dstText
+		dst.shiftOffsets(len(srcText) + 2)
 	}
 
+	mergeChunkBBox(dst, src)
 	if src.Metadata.PageStart != 0 && (dst.Metadata.PageStart == 0 || src.Metadata.PageStart < dst.Metadata.PageStart) {
 		dst.Metadata.PageStart = src.Metadata.PageStart
 	}
@@ -282,12 +310,108 @@ func mergeChunkInto(dst, src *Chunk, srcAfter bool) {
 		dst.Metadata.ElementTypes = appendUnique(dst.Metadata.ElementTypes, et)
 	}
 
+	if srcAfter {
+		dst.Metadata.Links = append(dst.Metadata.Links, src.Metadata.Links...)
+		dst.Metadata.Styled = append(dst.Metadata.Styled, src.Metadata.Styled...)
+		dst.spans = append(dst.spans, src.spans...)
+	} else {
+		dst.Metadata.Links = append(append([]ChunkLink{}, src.Metadata.Links...), dst.Metadata.Links...)
+		dst.Metadata.Styled = append(append([]ChunkStyled{}, src.Metadata.Styled...), dst.Metadata.Styled...)
+		dst.spans = append(append([]textSpan{}, src.spans...), dst.spans...)
+	}
+
 	recomputeChunkStats(dst)
 }
 
+// mergeChunkBBox combines src's bounding box into dst's. A chunk's box covers
+// its content on its first page, so a box on a later page than dst starts is
+// dropped. Call it before merging the page ranges.
+func mergeChunkBBox(dst, src *Chunk) {
+	if src.Metadata.BBox == nil {
+		return
+	}
+	switch {
+	case dst.Metadata.PageStart == 0 || src.Metadata.PageStart < dst.Metadata.PageStart,
+		src.Metadata.PageStart == dst.Metadata.PageStart && dst.Metadata.BBox == nil:
+		bbox := *src.Metadata.BBox
+		dst.Metadata.BBox = &bbox
+	case src.Metadata.PageStart == dst.Metadata.PageStart:
+		dst.Metadata.BBox = mergeBBox(dst.Metadata.BBox, src.Metadata.BBox)
+	}
+}
+
+// setElementBBox sets the box of a chunk made from a single element, which
+// all of its text comes from.
+func setElementBBox(c *Chunk, bbox *model.BBox) {
+	c.Metadata.BBox = bbox
+	c.spans = []textSpan{{start: 0, end: len(c.Text), bbox: bbox}}
+}
+
+// shiftOffsets moves the chunk's text offsets by delta bytes, for text added
+// or removed before them.
+func (c *Chunk) shiftOffsets(delta int) {
+	if delta == 0 {
+		return
+	}
+	c.spans = shiftSpans(c.spans, delta)
+	for i := range c.Metadata.Links {
+		c.Metadata.Links[i].Offset += delta
+	}
+	for i := range c.Metadata.Styled {
+		c.Metadata.Styled[i].Offset += delta
+	}
+}
+
+// shiftSpans returns the spans moved by delta bytes
+func shiftSpans(spans []textSpan, delta int) []textSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	shifted := make([]textSpan, len(spans))
+	for i, span := range spans {
+		shifted[i] = textSpan{start: span.start + delta, end: span.end + delta, bbox: span.bbox}
+	}
+	return shifted
+}
+
+// sliceSpans returns the parts of the spans within [start, end), relative to
+// start
+func sliceSpans(spans []textSpan, start, end int) []textSpan {
+	var sliced []textSpan
+	for _, span := range spans {
+		if span.end <= start || span.start >= end {
+			continue
+		}
+		s, e := span.start, span.end
+		if s < start {
+			s = start
+		}
+		if e > end {
+			e = end
+		}
+		sliced = append(sliced, textSpan{start: s - start, end: e - start, bbox: span.bbox})
+	}
+	return sliced
+}
+
+// leadingSpace returns the length in bytes of s's leading white space
+func leadingSpace(s string) int {
+	return len(s) - len(strings.TrimLeftFunc(s, unicode.IsSpace))
+}
+
+// elementBBox returns an element's bounding box, or nil if it has none.
+func elementBBox(el model.Element) *model.BBox {
+	bbox := el.BoundingBox()
+	if bbox.Width <= 0 && bbox.Height <= 0 {
+		return nil
+	}
+	return &bbox
+}
+
 // recomputeChunkStats refreshes derived statistics and contextual text after a
 // chunk's text has changed.
 func recomputeChunkStats(c *Chunk) {
+	c.shiftOffsets(-leadingSpace(c.Text))
 	c.Text = strings.TrimSpace(c.Text)
 	c.Metadata.CharCount = len(c.Text)
 	c.Metadata.WordCount = countWords(c.Text)
@@ -295,8 +419,9 @@ func recomputeChunkStats(c *Chunk) {
 	c.TextWithContext = c.generateContextualText()
 }
 
-// chunkPage chunks a single page
-func (dc *DocumentChunker) chunkPage(page *model.Page, docTitle string, currentSection *[]string, currentHeadingLevel *int, toc []model.TOCEntry, chunkIndex *int, promoteHeadings bool) []*Chunk {
+// chunkPage chunks a single page. When outline is non-nil, it sets the
+// section path before each element and headings no longer change it.
+func (dc *DocumentChunker) chunkPage(page *model.Page, docTitle string, currentSection *[]string, currentHeadingLevel *int, toc []model.TOCEntry, chunkIndex *int, promoteHeadings bool, outline *outlineSections) []*Chunk {
 	var chunks []*Chunk
 
 	if page == nil {
@@ -317,6 +442,15 @@ func (dc *DocumentChunker) chunkPage(page *model.Page, docTitle string, currentS
 	}
 
 	for _, elem := range page.Elements {
+		if outline != nil {
+			path := outline.sectionAt(page.SourcePage(), elem.BoundingBox().Top())
+			if !sameSectionPath(path, *currentSection) {
+				// A new outline section starts here
+				flushTextBlock()
+				*currentSection = append([]string{}, path...)
+			}
+		}
+
 		switch e := elem.(type) {
 		case *model.Paragraph:
 			// Recover a chapter heading buried in paragraph text when the
@@ -329,15 +463,13 @@ func (dc *DocumentChunker) chunkPage(page *model.Page, docTitle string, currentS
 					updateSectionPath(currentSection, currentHeadingLevel, level, headText)
 
 					chunk := dc.createHeadingChunk(headText, docTitle, *currentSection, level, page.Number, chunkIndex)
+					setElementBBox(chunk, elementBBox(e))
 					chunks = append(chunks, chunk)
 
 					// Keep the remaining body text (the heading portion has been
 					// split off, so there is no duplication).
 					if strings.TrimSpace(bodyText) != "" {
-						if currentBlock.text != "" {
-							currentBlock.text += "\n\n"
-						}
-						currentBlock.text += bodyText
+						currentBlock.add(bodyText, elementBBox(e))
 						currentBlock.sectionPath = append([]string{}, *currentSection...)
 						currentBlock.elementTypes = appendUnique(currentBlock.elementTypes, "paragraph")
 					}
@@ -352,17 +484,17 @@ func (dc *DocumentChunker) chunkPage(page *model.Page, docTitle string, currentS
 
 				// Update section path
 				headingLevel := getHeadingLevel(e.Text, toc, page.Number)
-				updateSectionPath(currentSection, currentHeadingLevel, headingLevel, e.Text)
+				if outline == nil {
+					updateSectionPath(currentSection, currentHeadingLevel, headingLevel, e.Text)
+				}
 
 				// Create heading chunk
 				chunk := dc.createHeadingChunk(e.Text, docTitle, *currentSection, headingLevel, page.Number, chunkIndex)
+				setElementBBox(chunk, elementBBox(e))
 				chunks = append(chunks, chunk)
 			} else {
 				// Accumulate text
-				if currentBlock.text != "" {
-					currentBlock.text += "\n\n"
-				}
-				currentBlock.text += e.Text
+				currentBlock.add(e.Text, elementBBox(e))
 				currentBlock.sectionPath = append([]string{}, *currentSection...)
 				currentBlock.elementTypes = appendUnique(currentBlock.elementTypes, "paragraph")
 			}
@@ -372,10 +504,13 @@ func (dc *DocumentChunker) chunkPage(page *model.Page, docTitle string, currentS
 			flushTextBlock()
 
 			// Update section path
-			updateSectionPath(currentSection, currentHeadingLevel, e.Level, e.Text)
+			if outline == nil {
+				updateSectionPath(currentSection, currentHeadingLevel, e.Level, e.Text)
+			}
 
 			// Create heading chunk
 			chunk := dc.createChunkFromHeading(e, docTitle, *currentSection, page.Number, chunkIndex)
+			setElementBBox(chunk, elementBBox(e))
 			chunks = append(chunks, chunk)
 
 		case *model.List:
@@ -384,6 +519,7 @@ func (dc *DocumentChunker) chunkPage(page *model.Page, docTitle string, currentS
 
 			// Create list chunk
 			chunk := dc.createListChunk(e, docTitle, *currentSection, page.Number, chunkIndex)
+			setElementBBox(chunk, elementBBox(e))
 			chunks = append(chunks, chunk)
 
 		case *model.Table:
@@ -392,6 +528,7 @@ func (dc *DocumentChunker) chunkPage(page *model.Page, docTitle string, currentS
 
 			// Create table chunk
 			chunk := dc.createTableChunk(e, docTitle, *currentSection, page.Number, chunkIndex)
+			setElementBBox(chunk, elementBBox(e))
 			chunks = append(chunks, chunk)
 
 		case *model.Image:
@@ -401,6 +538,18 @@ func (dc *DocumentChunker) chunkPage(page *model.Page, docTitle string, currentS
 			// Create image chunk if it has alt text
 			if e.AltText != "" {
 				chunk := dc.createImageChunk(e, docTitle, *currentSection, page.Number, chunkIndex)
+				setElementBBox(chunk, elementBBox(e))
+				chunks = append(chunks, chunk)
+			}
+
+		case *model.Comment:
+			// Flush current block before comment
+			flushTextBlock()
+
+			// Create comment chunk
+			if strings.TrimSpace(e.GetText()) != "" {
+				chunk := dc.createCommentChunk(e, docTitle, *currentSection, page.Number, chunkIndex)
+				setElementBBox(chunk, elementBBox(e))
 				chunks = append(chunks, chunk)
 			}
 		}
@@ -409,6 +558,10 @@ func (dc *DocumentChunker) chunkPage(page *model.Page, docTitle string, currentS
 	// Flush final block
 	flushTextBlock()
 
+	// Attach the page's hyperlinks to the chunks containing their text
+	attachLinks(chunks, page.Links)
+	attachStyled(chunks, page.Styled)
+
 	return chunks
 }
 
@@ -418,6 +571,25 @@ type textBlock struct {
 	sectionPath  []string
 	elementTypes []string
 	pageNum      int
+	bbox         *model.BBox // Union of the paragraphs' boxes, or nil
+	spans        []textSpan  // Where each paragraph's text is in text
+}
+
+// textSpan is a byte range of a chunk's or text block's text and the box
+// of the page element it came from
+type textSpan struct {
+	start, end int
+	bbox       *model.BBox
+}
+
+// add appends a paragraph's text to the block
+func (b *textBlock) add(text string, bbox *model.BBox) {
+	if b.text != "" {
+		b.text += "\n\n"
+	}
+	b.spans = append(b.spans, textSpan{start: len(b.text), end: len(b.text) + len(text), bbox: bbox})
+	b.text += text
+	b.bbox = mergeBBox(b.bbox, bbox)
 }
 
 // textBlockToChunks converts a text block to one or more chunks
@@ -433,8 +605,12 @@ func (dc *DocumentChunker) textBlockToChunks(block textBlock, docTitle string, c
 		return chunks
 	}
 
-	// Split into multiple chunks
+	// Split into multiple chunks. The pieces are trimmed runs of the
+	// block's text, so each is found after the one before. Each piece's box
+	// covers the paragraphs it holds text from; a piece that can't be found
+	// gets none.
 	texts := sizeCalc.SplitToSize(block.text, nil)
+	next := 0
 	for _, text := range texts {
 		subBlock := textBlock{
 			text:         text,
@@ -442,6 +618,14 @@ func (dc *DocumentChunker) textBlockToChunks(block textBlock, docTitle string, c
 			elementTypes: block.elementTypes,
 			pageNum:      block.pageNum,
 		}
+		if idx := strings.Index(block.text[next:], text); idx >= 0 {
+			start := next + idx
+			next = start + len(text)
+			subBlock.spans = sliceSpans(block.spans, start, next)
+			for _, span := range subBlock.spans {
+				subBlock.bbox = mergeBBox(subBlock.bbox, span.bbox)
+			}
+		}
 		chunk := dc.createTextChunk(subBlock, docTitle, chunkIndex)
 		chunks = append(chunks, chunk)
 	}
@@ -456,9 +640,16 @@ func (dc *DocumentChunker) createTextChunk(block textBlock, docTitle string, chu
 		sectionTitle = block.sectionPath[len(block.sectionPath)-1]
 	}
 
+	var bbox *model.BBox
+	if block.bbox != nil {
+		b := *block.bbox
+		bbox = &b
+	}
+
 	chunk := &Chunk{
-		ID:   fmt.Sprintf("chunk-%d", *chunkIndex),
-		Text: strings.TrimSpace(block.text),
+		ID:    fmt.Sprintf("chunk-%d", *chunkIndex),
+		Text:  strings.TrimSpace(block.text),
+		spans: shiftSpans(block.spans, -leadingSpace(block.text)),
 		Metadata: ChunkMetadata{
 			DocumentTitle: docTitle,
 			SectionPath:   block.sectionPath,
@@ -470,6 +661,7 @@ func (dc *DocumentChunker) createTextChunk(block textBlock, docTitle string, chu
 			ElementTypes:  block.elementTypes,
 			CharCount:     len(block.text),
 			WordCount:     countWords(block.text),
+			BBox:          bbox,
 		},
 	}
 
@@ -659,8 +851,163 @@ func (dc *DocumentChunker) createImageChunk(img *model.Image, docTitle string, s
 	return chunk
 }
 
+// createCommentChunk creates a chunk from a Comment element
+func (dc *DocumentChunker) createCommentChunk(c *model.Comment, docTitle string, sectionPath []string, pageNum int, chunkIndex *int) *Chunk {
+	// Format the comment as bracketed text, like image references
+	label := "Comment"
+	switch a := c.Annotation; {
+	case a.Type.IsTextMarkup():
+		label = a.Type.String()
+	case a.Type == model.AnnotationStamp:
+		label = "Stamp"
+	}
+	if c.Annotation.Author != "" {
+		label += " by " + c.Annotation.Author
+	}
+	text := "[" + label + ": " + strings.TrimSpace(c.GetText()) + "]"
+
+	sectionTitle := ""
+	if len(sectionPath) > 0 {
+		sectionTitle = sectionPath[len(sectionPath)-1]
+	}
+
+	chunk := &Chunk{
+		ID:   fmt.Sprintf("chunk-%d", *chunkIndex),
+		Text: text,
+		Metadata: ChunkMetadata{
+			DocumentTitle: docTitle,
+			SectionPath:   sectionPath,
+			SectionTitle:  sectionTitle,
+			PageStart:     pageNum,
+			PageEnd:       pageNum,
+			ChunkIndex:    *chunkIndex,
+			Level:         ChunkLevelParagraph,
+			ElementTypes:  []string{strings.ToLower(c.Type().String())},
+			CharCount:     len(text),
+			WordCount:     countWords(text),
+		},
+	}
+
+	*chunkIndex++
+	return chunk
+}
+
 // Helper functions
 
+// attachLinks records each link on the chunk whose text lies under it, at
+// the offset of that text
+func attachLinks(chunks []*Chunk, links []model.Link) {
+	for _, link := range links {
+		if chunk, offset, ok := locateText(chunks, link.Text, link.BBox); ok {
+			chunk.Metadata.Links = append(chunk.Metadata.Links, ChunkLink{Link: link, Offset: offset})
+		}
+	}
+}
+
+// locateText finds page text by position: in the chunk span whose element
+// box overlaps bbox most, at the occurrence of text nearest where bbox sits
+// in that box. It returns the chunk and the byte offset of text in its
+// Text, and false when no span overlaps bbox or holds text.
+func locateText(chunks []*Chunk, text string, bbox model.BBox) (*Chunk, int, bool) {
+	if text == "" {
+		return nil, 0, false
+	}
+
+	var best *Chunk
+	var bestSpan textSpan
+	bestArea := 0.0
+	for _, chunk := range chunks {
+		for _, span := range chunk.spans {
+			if span.bbox == nil {
+				continue
+			}
+			if area := span.bbox.Intersection(bbox).Area(); area > bestArea {
+				best, bestSpan, bestArea = chunk, span, area
+			}
+		}
+	}
+	if best == nil || bestSpan.end > len(best.Text) || bestSpan.start < 0 {
+		return nil, 0, false
+	}
+
+	offset := nearestOccurrence(best.Text, text, bestSpan.start, bestSpan.end, spanEstimate(bestSpan, bbox))
+	if offset < 0 {
+		return nil, 0, false
+	}
+	return best, offset, true
+}
+
+// spanEstimate estimates the byte offset of the text set at box within a
+// span's element box, reading its lines from the top
+func spanEstimate(span textSpan, box model.BBox) int {
+	b := span.bbox
+	if b.Width <= 0 || b.Height <= 0 {
+		return span.start
+	}
+	lines := 1.0
+	if box.Height > 0 {
+		lines = math.Max(1, math.Round(b.Height/box.Height))
+	}
+	down := math.Min(math.Max((b.Top()-box.Top())/b.Height, 0), 1)
+	across := math.Min(math.Max((box.X-b.X)/b.Width, 0), 1)
+	frac := math.Min(down+across/lines, 1)
+	return span.start + int(frac*float64(span.end-span.start))
+}
+
+// nearestOccurrence returns the byte offset of the occurrence of sub within
+// s[lo:hi] nearest to est, or -1 if there is none
+func nearestOccurrence(s, sub string, lo, hi, est int) int {
+	best := -1
+	for i := lo; i+len(sub) <= hi; {
+		idx := strings.Index(s[i:hi], sub)
+		if idx < 0 {
+			break
+		}
+		pos := i + idx
+		if best < 0 || absInt(pos-est) < absInt(best-est) {
+			best = pos
+		}
+		i = pos + 1
+	}
+	return best
+}
+
+// absInt returns the absolute value of n
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// attachStyled records each styled run on the chunk whose text lies under
+// it, at the offset of that text. A run that is a chunk's whole text, such
+// as a bold heading, adds nothing.
+func attachStyled(chunks []*Chunk, runs []model.StyledText) {
+	for _, run := range runs {
+		chunk, offset, ok := locateText(chunks, run.Text, run.BBox)
+		if !ok || strings.TrimSpace(chunk.Text) == run.Text {
+			continue
+		}
+		chunk.Metadata.Styled = append(chunk.Metadata.Styled, ChunkStyled{StyledText: run, Offset: offset})
+	}
+}
+
+// setPageInfo records on each chunk the number of its first page in the
+// source file and, for documents that define page labels, the printed
+// labels of its first and last pages
+func setPageInfo(chunks []*Chunk, doc *model.Document) {
+	for _, chunk := range chunks {
+		if page := doc.GetPage(chunk.Metadata.PageStart); page != nil {
+			chunk.Metadata.SourcePageStart = page.SourcePage()
+			chunk.Metadata.PageLabelStart = page.PageLabel
+		}
+		if page := doc.GetPage(chunk.Metadata.PageEnd); page != nil {
+			chunk.Metadata.PageLabelEnd = page.PageLabel
+		}
+	}
+}
+
 // isHeadingElement checks if text matches a TOC entry (is a heading)
 func isHeadingElement(text string, toc []model.TOCEntry, pageNum int) bool {
 	text = strings.TrimSpace(text)
@@ -274,6 +274,28 @@ func TestDocumentChunker_PageNumbers(t *testing.T) {
 	}
 }
 
+func TestDocumentChunker_PageLabels(t *testing.T) {
+	doc := createTestModelDocument()
+	for i, label := range []string{"xi", "xii", "1"} {
+		doc.Pages[i].PageLabel = label
+	}
+
+	collection := NewDocumentChunker().ChunkDocument(doc)
+	if len(collection.Chunks) == 0 {
+		t.Fatal("Expected chunks")
+	}
+	for _, chunk := range collection.Chunks {
+		want := doc.Pages[chunk.Metadata.PageStart-1].PageLabel
+		if chunk.Metadata.PageLabelStart != want {
+			t.Errorf("Expected PageLabelStart %q for page %d, got %q",
+				want, chunk.Metadata.PageStart, chunk.Metadata.PageLabelStart)
+		}
+		if chunk.Metadata.PageLabelEnd == "" {
+			t.Errorf("Expected PageLabelEnd to be set for chunk: %s", chunk.ID)
+		}
+	}
+}
+
 func TestDocumentChunker_TotalChunks(t *testing.T) {
 	doc := createTestModelDocument()
 	chunker := NewDocumentChunker()
@@ -544,3 +566,108 @@ func BenchmarkChunkDocument(b *testing.B) {
 		ChunkDocument(doc)
 	}
 }
+
+func TestDocumentChunker_BBox(t *testing.T) {
+	doc := model.NewDocument()
+	page := model.NewPage(612, 792)
+	page.Number = 1
+	page.AddElement(&model.Heading{Level: 1, Text: "Introduction", BBox: model.BBox{X: 72, Y: 700, Width: 150, Height: 24}})
+	page.AddElement(&model.Paragraph{Text: "First paragraph of the introduction.", BBox: model.BBox{X: 72, Y: 650, Width: 400, Height: 30}})
+	page.AddElement(&model.Paragraph{Text: "Second paragraph of the introduction.", BBox: model.BBox{X: 90, Y: 600, Width: 400, Height: 30}})
+	doc.AddPage(page)
+
+	sizeConfig := DefaultSizeConfig()
+	sizeConfig.MergeSmallChunks = false
+	collection := NewDocumentChunkerWithConfig(DefaultChunkerConfig(), sizeConfig).ChunkDocument(doc)
+
+	if len(collection.Chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", len(collection.Chunks))
+	}
+	if b := collection.Chunks[0].Metadata.BBox; b == nil || *b != (model.BBox{X: 72, Y: 700, Width: 150, Height: 24}) {
+		t.Errorf("Expected the heading's bbox, got %+v", b)
+	}
+	if b := collection.Chunks[1].Metadata.BBox; b == nil || *b != (model.BBox{X: 72, Y: 600, Width: 418, Height: 80}) {
+		t.Errorf("Expected the union of the paragraphs' bboxes, got %+v", b)
+	}
+}
+
+func TestDocumentChunker_SplitBBox(t *testing.T) {
+	// The two paragraphs are one text block, too long for one chunk; each
+	// chunk split from it gets the box of its own paragraph
+	doc := model.NewDocument()
+	page := model.NewPage(612, 792)
+	page.Number = 1
+	page.AddElement(&model.Paragraph{Text: "The first paragraph talks about installing the package.", BBox: model.BBox{X: 72, Y: 650, Width: 400, Height: 30}})
+	page.AddElement(&model.Paragraph{Text: "The second paragraph explains how to run the command.", BBox: model.BBox{X: 90, Y: 600, Width: 300, Height: 30}})
+	doc.AddPage(page)
+
+	sizeConfig := DefaultSizeConfig()
+	sizeConfig.Target = SizeLimit{Value: 60, Unit: SizeUnitCharacters, Type: LimitTypeSoft}
+	sizeConfig.Max = SizeLimit{Value: 80, Unit: SizeUnitCharacters, Type: LimitTypeHard}
+	sizeConfig.MergeSmallChunks = false
+	collection := NewDocumentChunkerWithConfig(DefaultChunkerConfig(), sizeConfig).ChunkDocument(doc)
+
+	if len(collection.Chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", len(collection.Chunks))
+	}
+	if b := collection.Chunks[0].Metadata.BBox; b == nil || *b != (model.BBox{X: 72, Y: 650, Width: 400, Height: 30}) {
+		t.Errorf("Expected the first paragraph's bbox, got %+v", b)
+	}
+	if b := collection.Chunks[1].Metadata.BBox; b == nil || *b != (model.BBox{X: 90, Y: 600, Width: 300, Height: 30}) {
+		t.Errorf("Expected the second paragraph's bbox, got %+v", b)
+	}
+}
+
+func TestDocumentChunker_Links(t *testing.T) {
+	// The same words appear three times; each link lies over one of them
+	doc := model.NewDocument()
+	page := model.NewPage(612, 792)
+	page.Number = 1
+	page.AddElement(&model.Heading{Level: 1, Text: "Getting help", BBox: model.BBox{X: 72, Y: 700, Width: 150, Height: 24}})
+	page.AddElement(&model.Paragraph{Text: "Read the manual first, then the manual appendix.", BBox: model.BBox{X: 72, Y: 650, Width: 400, Height: 14}})
+	page.AddElement(&model.Paragraph{Text: "See the manual for help.", BBox: model.BBox{X: 72, Y: 600, Width: 200, Height: 14}})
+	page.Links = []model.Link{
+		{Text: "the manual", URI: "https://example.com/b", BBox: model.BBox{X: 110, Y: 601, Width: 60, Height: 12}},
+		{Text: "the manual", URI: "https://example.com/a", BBox: model.BBox{X: 300, Y: 651, Width: 60, Height: 12}},
+	}
+	doc.AddPage(page)
+
+	collection := NewDocumentChunker().ChunkDocument(doc)
+	if len(collection.Chunks) != 1 {
+		t.Fatalf("Expected 1 chunk, got %d", len(collection.Chunks))
+	}
+	chunk := collection.Chunks[0]
+
+	for _, link := range chunk.Metadata.Links {
+		if got := chunk.Text[link.Offset : link.Offset+len(link.Text)]; got != link.Text {
+			t.Errorf("Expected %q at offset %d, got %q", link.Text, link.Offset, got)
+		}
+	}
+	want := "Getting help\n\nRead the manual first, then [the manual](https://example.com/a) appendix.\n\nSee [the manual](https://example.com/b) for help."
+	if got := chunk.markdownText(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestDocumentChunker_Styled(t *testing.T) {
+	// "Note" appears twice; only the one in the second paragraph is bold
+	doc := model.NewDocument()
+	page := model.NewPage(612, 792)
+	page.Number = 1
+	page.AddElement(&model.Paragraph{Text: "Note the defaults below.", BBox: model.BBox{X: 72, Y: 650, Width: 200, Height: 14}})
+	page.AddElement(&model.Paragraph{Text: "Note: these change often.", BBox: model.BBox{X: 72, Y: 600, Width: 200, Height: 14}})
+	page.Styled = []model.StyledText{
+		{Text: "Note", Style: model.TextStyle{Bold: true}, BBox: model.BBox{X: 72, Y: 601, Width: 30, Height: 12}},
+	}
+	doc.AddPage(page)
+
+	collection := NewDocumentChunker().ChunkDocument(doc)
+	if len(collection.Chunks) != 1 {
+		t.Fatalf("Expected 1 chunk, got %d", len(collection.Chunks))
+	}
+
+	want := "Note the defaults below.\n\n**Note**: these change often."
+	if got := collection.Chunks[0].markdownText(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
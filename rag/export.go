@@ -259,6 +259,10 @@ func chunkMetadataToMap(meta ChunkMetadata) map[string]interface{} {
 	if meta.PageEnd > 0 {
 		m["page_end"] = meta.PageEnd
 	}
+	if meta.PageLabelStart != "" {
+		m["page_label_start"] = meta.PageLabelStart
+		m["page_label_end"] = meta.PageLabelEnd
+	}
 	m["chunk_index"] = meta.ChunkIndex
 	if meta.TotalChunks > 0 {
 		m["total_chunks"] = meta.TotalChunks
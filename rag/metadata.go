@@ -3,6 +3,7 @@ package rag
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -90,6 +91,8 @@ func (m *ChunkMetadata) ToMap() map[string]interface{} {
 		"heading_level":    m.HeadingLevel,
 		"page_start":       m.PageStart,
 		"page_end":         m.PageEnd,
+		"page_label_start": m.PageLabelStart,
+		"page_label_end":   m.PageLabelEnd,
 		"chunk_index":      m.ChunkIndex,
 		"total_chunks":     m.TotalChunks,
 		"level":            m.Level.String(),
@@ -116,8 +119,15 @@ func (m *ChunkMetadata) GetSectionPathString(separator string) string {
 	return strings.Join(m.SectionPath, separator)
 }
 
-// GetPageRange returns a formatted page range string
+// GetPageRange returns a formatted page range string, using the printed
+// page labels when the document defines them
 func (m *ChunkMetadata) GetPageRange() string {
+	if m.PageLabelStart != "" && m.PageLabelEnd != "" {
+		if m.PageStart == m.PageEnd {
+			return fmt.Sprintf("p. %s", m.PageLabelStart)
+		}
+		return fmt.Sprintf("pp. %s-%s", m.PageLabelStart, m.PageLabelEnd)
+	}
 	if m.PageStart == m.PageEnd {
 		return fmt.Sprintf("p. %d", m.PageStart)
 	}
@@ -606,7 +616,7 @@ func (c *Chunk) ToMarkdownWithOptions(opts MarkdownOptions) string {
 
 	// Add the main content (skip if it's the same as section title for heading chunks)
 	if c.Text != c.Metadata.SectionTitle {
-		sb.WriteString(c.Text)
+		sb.WriteString(c.markdownText())
 	}
 
 	// Add page reference if requested
@@ -702,7 +712,7 @@ func (c *Chunk) contentToMarkdown(opts MarkdownOptions) string {
 	}
 
 	// Add the main content
-	sb.WriteString(c.Text)
+	sb.WriteString(c.markdownText())
 
 	// Add page reference if requested
 	if opts.IncludePageNumbers && c.Metadata.PageStart > 0 {
@@ -713,6 +723,121 @@ func (c *Chunk) contentToMarkdown(opts MarkdownOptions) string {
 	return sb.String()
 }
 
+// markdownText renders the chunk's text with its links as [text](uri) and
+// its styled runs in markdown emphasis
+func (c *Chunk) markdownText() string {
+	spans := markdownStyled(c.Text, c.Metadata.Styled)
+	spans = append(spans, markdownLinks(c.Text, c.Metadata.Links)...)
+	return renderMarkdownSpans(c.Text, spans)
+}
+
+// markdownSpan is markup around text[start:end]
+type markdownSpan struct {
+	start, end  int
+	open, close string
+}
+
+// markdownLinks returns the markup for links, each at its offset. A link
+// whose text is not at its offset, as after the chunk's text has been
+// edited, is left out.
+func markdownLinks(text string, links []ChunkLink) []markdownSpan {
+	var spans []markdownSpan
+	for _, link := range links {
+		end := link.Offset + len(link.Text)
+		if link.Text == "" || link.Offset < 0 || end > len(text) || text[link.Offset:end] != link.Text {
+			continue
+		}
+		uri := strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(link.URI)
+		spans = append(spans, markdownSpan{start: link.Offset, end: end, open: "[", close: "](" + uri + ")"})
+	}
+	return spans
+}
+
+// markdownStyled returns the markup for styled runs, each at its offset:
+// **bold**, *italic*, ***bold italic*** or `code` for monospaced text. A
+// run whose text is not at its offset is left out.
+func markdownStyled(text string, runs []ChunkStyled) []markdownSpan {
+	var spans []markdownSpan
+	for _, run := range runs {
+		end := run.Offset + len(run.Text)
+		if run.Text == "" || run.Offset < 0 || end > len(text) || text[run.Offset:end] != run.Text {
+			continue
+		}
+		var marker string
+		switch {
+		case run.Style.Monospace:
+			marker = "`"
+		case run.Style.Bold && run.Style.Italic:
+			marker = "***"
+		case run.Style.Bold:
+			marker = "**"
+		case run.Style.Italic:
+			marker = "*"
+		default:
+			continue
+		}
+		spans = append(spans, markdownSpan{start: run.Offset, end: end, open: marker, close: marker})
+	}
+	return spans
+}
+
+// renderMarkdownSpans wraps the spans of text in their markup. Spans outside
+// text, or crossing a span already placed, are dropped so the markup always
+// nests.
+func renderMarkdownSpans(text string, spans []markdownSpan) string {
+	if len(spans) == 0 {
+		return text
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+	var placed []markdownSpan
+	for _, span := range spans {
+		if span.start < 0 || span.end > len(text) || span.start >= span.end {
+			continue
+		}
+		crosses := false
+		for _, p := range placed {
+			if span.start < p.end && p.end < span.end {
+				crosses = true
+				break
+			}
+		}
+		if !crosses {
+			placed = append(placed, span)
+		}
+	}
+
+	// Spans are sorted by start, outer ones first, and nest, so a stack of
+	// the open spans closes them in order
+	var sb strings.Builder
+	var open []markdownSpan
+	pos := 0
+	closeUntil := func(limit int) {
+		for len(open) > 0 && open[len(open)-1].end <= limit {
+			last := open[len(open)-1]
+			sb.WriteString(text[pos:last.end])
+			sb.WriteString(last.close)
+			pos = last.end
+			open = open[:len(open)-1]
+		}
+	}
+	for _, span := range placed {
+		closeUntil(span.start)
+		sb.WriteString(text[pos:span.start])
+		sb.WriteString(span.open)
+		pos = span.start
+		open = append(open, span)
+	}
+	closeUntil(len(text))
+	sb.WriteString(text[pos:])
+	return sb.String()
+}
+
 // generateTableOfContents creates a markdown TOC from section titles
 func (cc *ChunkCollection) generateTableOfContents(opts MarkdownOptions) string {
 	var sb strings.Builder
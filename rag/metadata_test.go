@@ -3,6 +3,8 @@ package rag
 import (
 	"encoding/json"
 	"testing"
+
+	"github.com/tsawler/tabula/model"
 )
 
 func TestContextFormat_String(t *testing.T) {
@@ -198,6 +200,18 @@ func TestChunkMetadata_GetPageRange(t *testing.T) {
 	}
 }
 
+func TestChunkMetadata_GetPageRangeLabels(t *testing.T) {
+	meta := &ChunkMetadata{PageStart: 4, PageEnd: 4, PageLabelStart: "iv", PageLabelEnd: "iv"}
+	if got := meta.GetPageRange(); got != "p. iv" {
+		t.Errorf("GetPageRange() = %q, want %q", got, "p. iv")
+	}
+
+	meta = &ChunkMetadata{PageStart: 20, PageEnd: 21, PageLabelStart: "A-3", PageLabelEnd: "A-4"}
+	if got := meta.GetPageRange(); got != "pp. A-3-A-4" {
+		t.Errorf("GetPageRange() = %q, want %q", got, "pp. A-3-A-4")
+	}
+}
+
 func TestChunkMetadata_GetReadingTimeMinutes(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -1395,6 +1409,30 @@ func TestChunkCollection_ToMarkdown_Empty(t *testing.T) {
 	}
 }
 
+func TestChunk_ToMarkdown_Links(t *testing.T) {
+	chunk := &Chunk{
+		ID:   "1",
+		Text: "See the guide and the guide (v2) for details.",
+		Metadata: ChunkMetadata{
+			Links: []ChunkLink{
+				{Link: model.Link{Text: "the guide (v2)", URI: "https://example.com/guide (v2)"}, Offset: 18},
+				{Link: model.Link{Text: "the guide", URI: "https://example.com/guide"}, Offset: 4},
+				{Link: model.Link{Text: "missing", URI: "https://example.com/missing"}, Offset: 0},
+			},
+			Styled: []ChunkStyled{{StyledText: model.StyledText{Text: "details", Style: model.TextStyle{Bold: true}}, Offset: 37}},
+		},
+	}
+
+	md := chunk.ToMarkdown()
+	want := "See [the guide](https://example.com/guide) and [the guide (v2)](https://example.com/guide%20%28v2%29) for **details**."
+	if md != want {
+		t.Errorf("Expected %q, got %q", want, md)
+	}
+	if chunk.Text != "See the guide and the guide (v2) for details." {
+		t.Errorf("Expected chunk text to stay plain, got %q", chunk.Text)
+	}
+}
+
 func TestChunkCollection_ToMarkdownChunks(t *testing.T) {
 	chunks := []*Chunk{
 		{ID: "1", Text: "First.", Metadata: ChunkMetadata{SectionTitle: "A"}},
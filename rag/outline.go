@@ -0,0 +1,110 @@
+package rag
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tsawler/tabula/model"
+)
+
+// outlineTolerance is how far (in points) an element's top may sit above an
+// outline destination and still belong to that entry's section, since
+// destinations usually point just above or at the heading's top.
+const outlineTolerance = 2.0
+
+// outlineMark is a flattened outline entry: the position its section starts
+// at and the titles from the outline root down to it
+type outlineMark struct {
+	page int
+	y    float64
+	hasY bool
+	path []string
+}
+
+// outlineSections maps positions in the document to the outline section in
+// effect there. Lookups don't depend on the order elements are visited in,
+// since a page's elements need not be in reading order.
+type outlineSections struct {
+	marks []outlineMark
+}
+
+// newOutlineSections flattens an outline into marks sorted by page and then
+// top-down position. Entries with unresolved pages are dropped.
+func newOutlineSections(outline []*model.OutlineEntry) *outlineSections {
+	var marks []outlineMark
+	var flatten func(entries []*model.OutlineEntry, parent []string)
+	flatten = func(entries []*model.OutlineEntry, parent []string) {
+		for _, entry := range entries {
+			title := strings.TrimSpace(entry.Title)
+			path := append(append([]string{}, parent...), title)
+			if entry.Page > 0 && title != "" {
+				marks = append(marks, outlineMark{page: entry.Page, y: entry.Y, hasY: entry.HasY, path: path})
+			}
+			flatten(entry.Children, path)
+		}
+	}
+	flatten(outline, nil)
+
+	// A destination without a position is treated as the top of its page
+	sort.SliceStable(marks, func(i, j int) bool {
+		a, b := marks[i], marks[j]
+		if a.page != b.page {
+			return a.page < b.page
+		}
+		if a.hasY != b.hasY {
+			return !a.hasY
+		}
+		return a.y > b.y
+	})
+
+	return &outlineSections{marks: marks}
+}
+
+// sectionAt returns the section path in effect at the given position: that
+// of the last mark at or before it, or nil before the first mark
+func (o *outlineSections) sectionAt(page int, top float64) []string {
+	i := sort.Search(len(o.marks), func(i int) bool {
+		mark := o.marks[i]
+		return mark.page > page || (mark.page == page && mark.hasY && top > mark.y+outlineTolerance)
+	})
+	if i == 0 {
+		return nil
+	}
+	return o.marks[i-1].path
+}
+
+// outlineSectionsFor returns the document outline's sections when the
+// outline should drive section paths: it is enabled, present, and the
+// document's own headings are weak (fewer than half as many as there are
+// outline entries). Otherwise it returns nil.
+func (dc *DocumentChunker) outlineSectionsFor(doc *model.Document) *outlineSections {
+	if !dc.config.UseOutline || len(doc.Outline) == 0 {
+		return nil
+	}
+
+	sections := newOutlineSections(doc.Outline)
+	if len(sections.marks) == 0 {
+		return nil
+	}
+
+	headings := len(doc.TableOfContents())
+	elementHeadings := 0
+	for _, page := range doc.Pages {
+		if page == nil {
+			continue
+		}
+		for _, elem := range page.Elements {
+			if _, ok := elem.(*model.Heading); ok {
+				elementHeadings++
+			}
+		}
+	}
+	if elementHeadings > headings {
+		headings = elementHeadings
+	}
+
+	if headings*2 >= len(sections.marks) {
+		return nil
+	}
+	return sections
+}
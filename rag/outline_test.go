@@ -0,0 +1,138 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+)
+
+// createOutlineTestDocument builds a two-page document with no headings,
+// only paragraphs, and a bookmark outline that locates its sections
+func createOutlineTestDocument() *model.Document {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Manual"
+
+	paragraph := func(text string, top float64) *model.Paragraph {
+		return &model.Paragraph{Text: text, FontSize: 12, BBox: model.BBox{X: 72, Y: top - 14, Width: 400, Height: 14}}
+	}
+
+	doc.AddPage(&model.Page{
+		Width:  612,
+		Height: 792,
+		Elements: []model.Element{
+			paragraph("Preface text before any bookmark.", 760),
+			paragraph("Install the package before anything else.", 700),
+			paragraph("Run the command with your input file.", 401),
+		},
+	})
+	doc.AddPage(&model.Page{
+		Width:    612,
+		Height:   792,
+		Elements: []model.Element{paragraph("Every option is listed here.", 700)},
+	})
+
+	doc.Outline = []*model.OutlineEntry{
+		{Title: "Guide", Page: 1, Y: 710, HasY: true, Children: []*model.OutlineEntry{
+			{Title: "Setup", Page: 1, Y: 700, HasY: true},
+			{Title: "Usage", Page: 1, Y: 400, HasY: true},
+		}},
+		{Title: "Reference", Page: 2},
+		{Title: "External link"},
+	}
+	return doc
+}
+
+// sectionPathOf returns the section path of the chunk containing text
+func sectionPathOf(t *testing.T, collection *ChunkCollection, text string) string {
+	t.Helper()
+	for _, chunk := range collection.Chunks {
+		if strings.Contains(chunk.Text, text) {
+			return strings.Join(chunk.Metadata.SectionPath, " > ")
+		}
+	}
+	t.Fatalf("no chunk contains %q", text)
+	return ""
+}
+
+// outlineChunker returns a chunker with UseOutline on and no merging of
+// small chunks
+func outlineChunker() *DocumentChunker {
+	config := DefaultChunkerConfig()
+	config.UseOutline = true
+	sizeConfig := DefaultSizeConfig()
+	sizeConfig.MergeSmallChunks = false
+	return NewDocumentChunkerWithConfig(config, sizeConfig)
+}
+
+func TestDocumentChunker_OutlineSectionPath(t *testing.T) {
+	collection := outlineChunker().ChunkDocument(createOutlineTestDocument())
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Preface text", ""},
+		{"Install the package", "Guide > Setup"},
+		{"Run the command", "Guide > Usage"},
+		{"Every option", "Reference"},
+	}
+	for _, tt := range tests {
+		if got := sectionPathOf(t, collection, tt.text); got != tt.want {
+			t.Errorf("section path of %q = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDocumentChunker_OutlineElementOrder(t *testing.T) {
+	// Layout analysis emits a page's elements grouped by type, not in
+	// reading order: here the list in the Setup section comes last
+	doc := createOutlineTestDocument()
+	page := doc.Pages[0]
+	page.Elements = append(page.Elements, &model.List{
+		Items: []model.ListItem{{Text: "Download the installer"}, {Text: "Unpack it"}},
+		BBox:  model.BBox{X: 72, Y: 600, Width: 400, Height: 40},
+	})
+	page.Elements[0], page.Elements[2] = page.Elements[2], page.Elements[0]
+
+	collection := outlineChunker().ChunkDocument(doc)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Run the command", "Guide > Usage"},
+		{"Install the package", "Guide > Setup"},
+		{"Preface text", ""},
+		{"Download the installer", "Guide > Setup"},
+	}
+	for _, tt := range tests {
+		if got := sectionPathOf(t, collection, tt.text); got != tt.want {
+			t.Errorf("section path of %q = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDocumentChunker_OutlineOffByDefault(t *testing.T) {
+	sizeConfig := DefaultSizeConfig()
+	sizeConfig.MergeSmallChunks = false
+
+	collection := NewDocumentChunkerWithConfig(DefaultChunkerConfig(), sizeConfig).ChunkDocument(createOutlineTestDocument())
+	if got := sectionPathOf(t, collection, "Every option"); got != "" {
+		t.Errorf("Expected no section path with UseOutline off, got %q", got)
+	}
+}
+
+func TestDocumentChunker_OutlineIgnoredWithStrongHeadings(t *testing.T) {
+	doc := createTestModelDocument()
+	doc.Outline = []*model.OutlineEntry{{Title: "Bookmark", Page: 1}}
+
+	collection := outlineChunker().ChunkDocument(doc)
+	for _, chunk := range collection.Chunks {
+		for _, title := range chunk.Metadata.SectionPath {
+			if title == "Bookmark" {
+				t.Fatalf("Expected headings to drive section paths, got %v", chunk.Metadata.SectionPath)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package reader
+
+import (
+	"github.com/tsawler/tabula/pages"
+	"github.com/tsawler/tabula/text"
+)
+
+// Annotations reads a page's annotations, with link destinations resolved
+// to page indexes
+func (r *Reader) Annotations(page *pages.Page) ([]*pages.Annotation, error) {
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return page.Annotations(pages.NewCatalog(catalog, r), r.PageIndex)
+}
+
+// linkFragments sets the URI of each fragment whose center lies inside a
+// URI link annotation on the page. Unreadable annotations are ignored.
+func (r *Reader) linkFragments(page *pages.Page, fragments []text.TextFragment) {
+	if len(fragments) == 0 {
+		return
+	}
+
+	annots, err := page.Annotations(nil, nil)
+	if err != nil {
+		return
+	}
+
+	for _, annot := range annots {
+		if annot.Subtype != "Link" || annot.URI == "" {
+			continue
+		}
+		for i := range fragments {
+			f := &fragments[i]
+			if annot.Contains(f.X+f.Width/2, f.Y+f.Height/2) {
+				f.URI = annot.URI
+			}
+		}
+	}
+}
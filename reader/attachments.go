@@ -0,0 +1,30 @@
+package reader
+
+import (
+	"github.com/tsawler/tabula/attachments"
+	"github.com/tsawler/tabula/core"
+)
+
+// Attachments reads the document's embedded files, both those attached to
+// the document and those of file attachment annotations. It returns nil and
+// no error if the document has none.
+func (r *Reader) Attachments() ([]*attachments.File, error) {
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := r.PageCount()
+	if err != nil {
+		return nil, err
+	}
+	pageAnnots := make([]core.Array, count)
+	for i := range pageAnnots {
+		page, err := r.GetPage(i)
+		if err != nil {
+			continue
+		}
+		pageAnnots[i] = page.AnnotationObjects()
+	}
+	return attachments.Parse(catalog, pageAnnots, r.ResolveReference), nil
+}
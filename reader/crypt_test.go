@@ -1,6 +1,11 @@
 package reader
 
 import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rc4"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -43,6 +48,97 @@ func TestUnencryptedHasNoSecurity(t *testing.T) {
 	}
 }
 
+// TestOpenWithPassword verifies that a user or owner password unlocks a
+// password-protected PDF and that failures are reported with typed errors.
+func TestOpenWithPassword(t *testing.T) {
+	path := createTempPDF(t, string(buildRC4PDF(t, "user", "owner", "BT (Encrypted Hello World) Tj ET")))
+
+	for _, pw := range []string{"user", "owner"} {
+		r, err := OpenWithPassword(path, pw)
+		if err != nil {
+			t.Fatalf("%s password: %v", pw, err)
+		}
+		if got := pageContentText(t, r); !strings.Contains(got, "Encrypted Hello World") {
+			t.Errorf("%s password: decrypted content missing expected text; got %q", pw, got)
+		}
+		r.Close()
+	}
+
+	if _, err := Open(path); !errors.Is(err, ErrPasswordRequired) {
+		t.Errorf("no password: got %v, want ErrPasswordRequired", err)
+	}
+	if _, err := OpenWithPassword(path, "nope"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("wrong password: got %v, want ErrWrongPassword", err)
+	}
+}
+
+// buildRC4PDF builds a one-page PDF encrypted with 40-bit RC4 (R2) whose
+// page content stream is content.
+func buildRC4PDF(t *testing.T, userPw, ownerPw, content string) []byte {
+	t.Helper()
+	pad := func(pw string) []byte {
+		padding := []byte{
+			0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+			0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+		}
+		out := append([]byte(pw), padding...)
+		return out[:32]
+	}
+	crypt := func(key, data []byte) []byte {
+		c, err := rc4.NewCipher(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := make([]byte, len(data))
+		c.XORKeyStream(out, data)
+		return out
+	}
+
+	id := []byte("tabula-test-id-0")
+	p := int32(-4)
+
+	ownerKey := md5.Sum(pad(ownerPw))
+	o := crypt(ownerKey[:5], pad(userPw))
+
+	h := md5.New()
+	h.Write(pad(userPw))
+	h.Write(o)
+	h.Write([]byte{byte(p), byte(p >> 8), byte(p >> 16), byte(p >> 24)})
+	h.Write(id)
+	fileKey := h.Sum(nil)[:5]
+	u := crypt(fileKey, pad(""))
+
+	// Object 4 holds the content stream; its key is MD5(fileKey, num, gen).
+	objKey := md5.Sum(append(append([]byte(nil), fileKey...), 4, 0, 0, 0, 0))
+	stream := crypt(objKey[:10], []byte(content))
+
+	bodies := [][]byte{
+		[]byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		[]byte("<< /Type /Pages /Kids [3 0 R] /Count 1 >>"),
+		[]byte("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 200] /Contents 4 0 R >>"),
+		[]byte(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream)),
+		[]byte(fmt.Sprintf("<< /Filter /Standard /V 1 /R 2 /Length 40 /P %d /O <%x> /U <%x> >>", p, o, u)),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(bodies))
+	for i, body := range bodies {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		buf.Write(body)
+		buf.WriteString("\nendobj\n")
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(bodies)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Encrypt 5 0 R /ID [<%x> <%x>] >>\nstartxref\n%d\n%%%%EOF",
+		len(bodies)+1, id, id, xref)
+	return buf.Bytes()
+}
+
 // pageContentText returns the decoded content stream(s) of the first page.
 func pageContentText(t *testing.T, r *Reader) string {
 	t.Helper()
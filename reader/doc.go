@@ -13,7 +13,21 @@
 //	}
 //	defer reader.Close()
 //
-// Or use [NewReader] with an existing *os.File.
+// Or use [NewReader] with an existing *os.File, or [NewReaderAt] with any
+// io.ReaderAt, for example a document already held in memory:
+//
+//	reader, err := reader.NewReaderAt(bytes.NewReader(data), int64(len(data)))
+//
+// # Encrypted Files
+//
+// Files protected by the standard security handler (RC4, AES-128 and
+// AES-256) open transparently when the user password is empty. Otherwise
+// supply the user or owner password with [OpenWithPassword]:
+//
+//	reader, err := reader.OpenWithPassword("contract.pdf", "secret")
+//	if errors.Is(err, reader.ErrPasswordRequired) || errors.Is(err, reader.ErrWrongPassword) {
+//	    // prompt for a password and retry
+//	}
 //
 // # Document Information
 //
@@ -23,6 +37,9 @@
 //   - PageCount() - number of pages
 //   - GetCatalog() - document catalog dictionary
 //   - GetInfo() - document info dictionary (metadata)
+//   - Metadata() - title, authors, dates, language, identifiers and PDF/A
+//     conformance from the info dictionary and XMP metadata, decoded (see
+//     the metadata package)
 //   - Trailer() - trailer dictionary
 //
 // # Page Access
@@ -31,6 +48,38 @@
 //
 //	page, err := reader.GetPage(0)  // First page
 //
+// PageIndex maps a page object reference, as found in structure elements,
+// outlines and link destinations, back to its index. PageLabels returns
+// the printed label of each page, such as "iv" or "A-3", when the document
+// defines page labels.
+//
+// # Tagged PDF
+//
+// StructTree parses the logical structure tree of a tagged PDF; see the
+// structure package. It returns nil for untagged documents.
+//
+// # Outline
+//
+// Outline reads the document's bookmarks with their destinations resolved
+// to page indexes; see pages.Catalog.Outline.
+//
+// # Annotations
+//
+// Annotations reads a page's annotations. Text fragments extracted from a
+// page carry the URI of any link annotation covering them.
+//
+// # Forms
+//
+// FormFields reads the AcroForm fields with their values and widget
+// positions, and XFAFields the values held in an XFA form's datasets; see
+// the forms package.
+//
+// # Attachments
+//
+// Attachments reads the embedded files of the document, including those of
+// PDF portfolios, associated files and file attachment annotations; see the
+// attachments package.
+//
 // # Object Resolution
 //
 // The Reader resolves indirect object references:
@@ -0,0 +1,52 @@
+package reader
+
+import (
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/forms"
+)
+
+// FormFields reads the document's AcroForm fields. It returns nil and no
+// error if the document has no interactive form.
+func (r *Reader) FormFields() ([]*forms.Field, error) {
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return forms.Parse(catalog, r.ResolveReference, r.PageIndex, r.annotationPage)
+}
+
+// XFAFields reads the field values of the document's XFA form datasets. It
+// returns nil and no error if the document has no XFA form.
+func (r *Reader) XFAFields() ([]*forms.Field, error) {
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return forms.ParseXFA(catalog, r.ResolveReference)
+}
+
+// annotationPage returns the index of the page whose /Annots array holds
+// the annotation with the given reference
+func (r *Reader) annotationPage(ref core.IndirectRef) (int, bool) {
+	if r.annotPages == nil {
+		r.annotPages = make(map[int]int)
+		count, err := r.PageCount()
+		if err != nil {
+			return 0, false
+		}
+		for i := 0; i < count; i++ {
+			page, err := r.GetPage(i)
+			if err != nil {
+				continue
+			}
+			for _, annotRef := range page.AnnotationRefs() {
+				if _, ok := r.annotPages[annotRef.Number]; !ok {
+					r.annotPages[annotRef.Number] = i
+				}
+			}
+		}
+	}
+
+	idx, ok := r.annotPages[ref.Number]
+	return idx, ok
+}
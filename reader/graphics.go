@@ -0,0 +1,41 @@
+package reader
+
+import (
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/graphicsstate"
+	"github.com/tsawler/tabula/pages"
+)
+
+// ExtractGraphics walks a page's content stream and returns the lines and
+// rectangles it draws, for use in ruled-table detection. Paths drawn inside
+// Form XObjects are not included. A page without content yields an empty
+// extractor.
+func (r *Reader) ExtractGraphics(page *pages.Page) (*graphicsstate.GraphicsExtractor, error) {
+	ge := graphicsstate.NewGraphicsExtractor()
+
+	contents, err := page.Contents()
+	if err != nil || contents == nil {
+		return ge, nil
+	}
+	var data []byte
+	for _, contentObj := range contents {
+		stream, ok := contentObj.(*core.Stream)
+		if !ok {
+			continue
+		}
+		decoded, err := stream.Decode()
+		if err != nil {
+			continue // Skip undecodable streams rather than failing the page
+		}
+		data = append(data, decoded...)
+		data = append(data, '\n')
+	}
+	if len(data) == 0 {
+		return ge, nil
+	}
+
+	if err := ge.ExtractFromBytes(data); err != nil {
+		return nil, err
+	}
+	return ge, nil
+}
@@ -18,7 +18,8 @@ import (
 
 // PageImage represents an extracted image from a PDF page.
 type PageImage struct {
-	Name             string // XObject name (e.g., "Im1")
+	Name             string // XObject name (e.g., "Im1"); empty for inline images
+	Inline           bool   // Drawn inline in the content stream (BI/ID/EI)
 	Width            int
 	Height           int
 	ColorSpace       string // DeviceGray, DeviceRGB, DeviceCMYK, Indexed, etc.
@@ -50,14 +51,16 @@ type PageImage struct {
 const maxFormDepth = 12
 
 // ExtractPageImages extracts all image XObjects reachable from a page, including
-// those nested inside Form XObjects, in deterministic order.
+// those nested inside Form XObjects, in deterministic order, followed by the
+// inline images (BI/ID/EI) in the page's content stream in draw order.
 func (r *Reader) ExtractPageImages(page *pages.Page) ([]PageImage, error) {
 	resources, err := page.Resources()
 	if err != nil {
-		return nil, nil // Page has no resources
+		resources = nil // Inline images need no resources
 	}
 	var images []PageImage
 	r.collectImages(resources, &images, 0)
+	r.collectInlineImages(page, resources, &images)
 	return images, nil
 }
 
@@ -140,6 +143,12 @@ func (r *Reader) resolveInt(obj core.Object) (int, bool) {
 	return 0, false
 }
 
+// ImageFromStream reads an image XObject stream, such as one named by a Do
+// operator or an image's /SMask.
+func (r *Reader) ImageFromStream(stream *core.Stream) (*PageImage, error) {
+	return r.extractImage("", stream)
+}
+
 // extractImage extracts a single image from a stream.
 func (r *Reader) extractImage(name string, stream *core.Stream) (*PageImage, error) {
 	dict := stream.Dict
@@ -455,6 +464,12 @@ func (img *PageImage) decode() (image.Image, error) {
 	return goImg, nil
 }
 
+// Image decodes the pixel data to a Go image. Stencil masks decode as black
+// (painted) on white (transparent).
+func (img *PageImage) Image() (image.Image, error) {
+	return img.decode()
+}
+
 // ToPNG converts the decoded pixel data to PNG format, suitable for OCR engines.
 func (img *PageImage) ToPNG() ([]byte, error) {
 	goImg, err := img.decode()
@@ -15,26 +15,50 @@ import (
 // the Do operator. Coordinates are in PDF user space (points, origin
 // bottom-left) in the page's content-stream coordinate system.
 type PlacedPageImage struct {
-	Name        string  // XObject name (e.g. "Im1")
+	Name        string  // XObject name (e.g. "Im1"); empty for inline images
+	Inline      bool    // drawn inline in the content stream (BI/ID/EI)
 	PixelWidth  int     // intrinsic image width in pixels
 	PixelHeight int     // intrinsic image height in pixels
 	ColorSpace  string  // base color-space name (DeviceRGB, DeviceGray, ...)
 	X, Y        float64 // bottom-left of the drawn bounding box, in points
 	Width       float64 // drawn width in points
 	Height      float64 // drawn height in points
+
+	// CTM maps the image's unit square onto the page, as in the PDF image
+	// model: (0, 0) is the image's bottom-left corner and (1, 1) its
+	// top-right
+	CTM model.Matrix
 }
 
 // ExtractPlacedImages walks a page's content stream, tracking graphics state,
 // and reports every image XObject painted via Do together with the axis-aligned
 // bounding box it occupies on the page. Images nested inside Form XObjects are
-// reported with the composed CTM (including the form's /Matrix). Images are
-// returned in draw order. Inline images (BI/ID/EI) are not reported.
+// reported with the composed CTM (including the form's /Matrix), and inline
+// images (BI/ID/EI) with Inline set. Images are returned in draw order.
 func (r *Reader) ExtractPlacedImages(page *pages.Page) ([]PlacedPageImage, error) {
-	// Decode and concatenate the page's content streams (same approach as
-	// extractTextWithFragments).
+	data := pageContentData(page)
+	if len(data) == 0 {
+		return nil, nil // No content / no resources => no images
+	}
+
+	resources, err := page.Resources()
+	if err != nil {
+		resources = nil
+	}
+
+	gs := graphicsstate.NewGraphicsState()
+	var out []PlacedPageImage
+	r.walkImageContent(data, resources, gs, 0, &out)
+	return out, nil
+}
+
+// pageContentData decodes and concatenates a page's content streams (same
+// approach as extractTextWithFragments), skipping streams that cannot be
+// decoded. It returns nil for a page without content.
+func pageContentData(page *pages.Page) []byte {
 	contents, err := page.Contents()
 	if err != nil || contents == nil {
-		return nil, nil // No content / no resources => no images
+		return nil
 	}
 	var data []byte
 	for _, contentObj := range contents {
@@ -48,24 +72,13 @@ func (r *Reader) ExtractPlacedImages(page *pages.Page) ([]PlacedPageImage, error
 		}
 		data = append(data, decoded...)
 	}
-	if len(data) == 0 {
-		return nil, nil
-	}
-
-	resources, err := page.Resources()
-	if err != nil {
-		resources = nil
-	}
-
-	gs := graphicsstate.NewGraphicsState()
-	var out []PlacedPageImage
-	r.walkImageContent(data, resources, gs, 0, &out)
-	return out, nil
+	return data
 }
 
 // walkImageContent processes a content stream's operations, tracking the CTM via
 // the graphics state and emitting a PlacedPageImage for each image XObject drawn
-// by Do. Form XObjects are recursed into with their /Matrix composed onto the CTM.
+// by Do and each inline image. Form XObjects are recursed into with their
+// /Matrix composed onto the CTM.
 func (r *Reader) walkImageContent(data []byte, resources core.Dict, gs *graphicsstate.GraphicsState, depth int, out *[]PlacedPageImage) {
 	if depth > maxFormDepth {
 		return
@@ -90,6 +103,12 @@ func (r *Reader) walkImageContent(data []byte, resources core.Dict, gs *graphics
 					r.doImageXObject(string(name), resources, gs, depth, out)
 				}
 			}
+		case "BI":
+			if stream, ok := r.inlineImageStream(op, resources); ok {
+				placed := r.placedImage("", stream, gs)
+				placed.Inline = true
+				*out = append(*out, placed)
+			}
 		}
 	}
 }
@@ -120,26 +139,7 @@ func (r *Reader) doImageXObject(name string, resources core.Dict, gs *graphicsst
 
 	switch subtype, _ := stream.Dict.Get("Subtype").(core.Name); string(subtype) {
 	case "Image":
-		x, y, w, h := imageBBoxFromCTM(gs.CTM)
-		pw, _ := r.resolveInt(stream.Dict.Get("Width"))
-		ph, _ := r.resolveInt(stream.Dict.Get("Height"))
-		cs := "DeviceGray"
-		if csObj := stream.Dict.Get("ColorSpace"); csObj != nil {
-			cs = r.parseColorSpace(csObj)
-		}
-		if b, ok := r.resolveBool(stream.Dict.Get("ImageMask")); ok && b {
-			cs = "DeviceGray"
-		}
-		*out = append(*out, PlacedPageImage{
-			Name:        name,
-			PixelWidth:  pw,
-			PixelHeight: ph,
-			ColorSpace:  cs,
-			X:           x,
-			Y:           y,
-			Width:       w,
-			Height:      h,
-		})
+		*out = append(*out, r.placedImage(name, stream, gs))
 
 	case "Form":
 		data, err := stream.Decode()
@@ -161,6 +161,31 @@ func (r *Reader) doImageXObject(name string, resources core.Dict, gs *graphicsst
 	}
 }
 
+// placedImage records the placement of an image drawn with the current CTM
+func (r *Reader) placedImage(name string, stream *core.Stream, gs *graphicsstate.GraphicsState) PlacedPageImage {
+	x, y, w, h := imageBBoxFromCTM(gs.CTM)
+	pw, _ := r.resolveInt(stream.Dict.Get("Width"))
+	ph, _ := r.resolveInt(stream.Dict.Get("Height"))
+	cs := "DeviceGray"
+	if csObj := stream.Dict.Get("ColorSpace"); csObj != nil {
+		cs = r.parseColorSpace(csObj)
+	}
+	if b, ok := r.resolveBool(stream.Dict.Get("ImageMask")); ok && b {
+		cs = "DeviceGray"
+	}
+	return PlacedPageImage{
+		Name:        name,
+		PixelWidth:  pw,
+		PixelHeight: ph,
+		ColorSpace:  cs,
+		X:           x,
+		Y:           y,
+		Width:       w,
+		Height:      h,
+		CTM:         gs.CTM,
+	}
+}
+
 // imageBBoxFromCTM maps the image's unit square [0,1]x[0,1] through the CTM and
 // returns the axis-aligned bounding box (x, y, width, height) in user space.
 func imageBBoxFromCTM(ctm model.Matrix) (x, y, w, h float64) {
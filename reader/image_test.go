@@ -4,6 +4,9 @@ import (
 	"image"
 	"image/color"
 	"testing"
+
+	"github.com/tsawler/tabula/contentstream"
+	"github.com/tsawler/tabula/core"
 )
 
 func TestPageImage_ToPNG_Grayscale8Bit(t *testing.T) {
@@ -472,3 +475,44 @@ func TestScaleImage(t *testing.T) {
 		t.Error("scale < 1 should return the source unchanged")
 	}
 }
+
+func TestReader_ImageFromStreamAndInlineImage(t *testing.T) {
+	r := &Reader{}
+	dict := core.Dict{
+		"Width":            core.Int(2),
+		"Height":           core.Int(1),
+		"ColorSpace":       core.Name("CS0"),
+		"BitsPerComponent": core.Int(8),
+	}
+	resources := core.Dict{"ColorSpace": core.Dict{"CS0": core.Name("DeviceRGB")}}
+	stream := &core.Stream{Dict: dict, Data: []byte{255, 0, 0, 0, 0, 255}}
+
+	img, err := r.InlineImage(contentstream.Operation{Operator: "BI", Operands: []core.Object{stream}}, resources)
+	if err != nil {
+		t.Fatalf("InlineImage failed: %v", err)
+	}
+	if !img.Inline || img.ColorSpace != "DeviceRGB" {
+		t.Errorf("got Inline=%v ColorSpace=%q, want true and DeviceRGB", img.Inline, img.ColorSpace)
+	}
+	goImg, err := img.Image()
+	if err != nil {
+		t.Fatalf("Image failed: %v", err)
+	}
+	if c := color.RGBAModel.Convert(goImg.At(1, 0)).(color.RGBA); c.B != 255 || c.R != 0 {
+		t.Errorf("pixel (1,0): got %v, want blue", c)
+	}
+
+	if _, err := r.InlineImage(contentstream.Operation{Operator: "BI"}, resources); err == nil {
+		t.Error("expected an error for a BI operation without an image")
+	}
+
+	dict["ColorSpace"] = core.Name("DeviceGray")
+	dict["Width"] = core.Int(6)
+	img, err = r.ImageFromStream(stream)
+	if err != nil {
+		t.Fatalf("ImageFromStream failed: %v", err)
+	}
+	if img.Inline || img.Width != 6 {
+		t.Errorf("got Inline=%v Width=%d, want false and 6", img.Inline, img.Width)
+	}
+}
@@ -0,0 +1,85 @@
+package reader
+
+import (
+	"fmt"
+
+	"github.com/tsawler/tabula/contentstream"
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/pages"
+)
+
+// inlineImageStream returns the image stream of a BI operation. An inline
+// image may name a color space from the resources' /ColorSpace dictionary
+// instead of giving one; that color space is substituted so the stream can
+// be read like an image XObject.
+func (r *Reader) inlineImageStream(op contentstream.Operation, resources core.Dict) (*core.Stream, bool) {
+	if len(op.Operands) != 1 {
+		return nil, false
+	}
+	stream, ok := op.Operands[0].(*core.Stream)
+	if !ok {
+		return nil, false
+	}
+
+	name, ok := stream.Dict.Get("ColorSpace").(core.Name)
+	if !ok || resources == nil {
+		return stream, true
+	}
+	switch name {
+	case "DeviceGray", "DeviceRGB", "DeviceCMYK", "Indexed":
+		return stream, true
+	}
+	colorSpaces, ok := r.resolveDict(resources.Get("ColorSpace"))
+	if !ok || colorSpaces.Get(string(name)) == nil {
+		return stream, true
+	}
+
+	dict := make(core.Dict, len(stream.Dict))
+	for k, v := range stream.Dict {
+		dict[k] = v
+	}
+	dict["ColorSpace"] = colorSpaces.Get(string(name))
+	return &core.Stream{Dict: dict, Data: stream.Data}, true
+}
+
+// InlineImage reads the image of a BI operation, looking up a named color
+// space in resources.
+func (r *Reader) InlineImage(op contentstream.Operation, resources core.Dict) (*PageImage, error) {
+	stream, ok := r.inlineImageStream(op, resources)
+	if !ok {
+		return nil, fmt.Errorf("invalid inline image")
+	}
+	img, err := r.extractImage("", stream)
+	if err != nil {
+		return nil, err
+	}
+	img.Inline = true
+	return img, nil
+}
+
+// collectInlineImages appends the inline images drawn by a page's content
+// stream, in draw order. Inline images inside Form XObjects are not included.
+func (r *Reader) collectInlineImages(page *pages.Page, resources core.Dict, out *[]PageImage) {
+	data := pageContentData(page)
+	if len(data) == 0 {
+		return
+	}
+	ops, err := contentstream.NewParser(data).Parse()
+	if err != nil {
+		return
+	}
+
+	for _, op := range ops {
+		if op.Operator != "BI" {
+			continue
+		}
+		stream, ok := r.inlineImageStream(op, resources)
+		if !ok {
+			continue
+		}
+		if img, err := r.extractImage("", stream); err == nil {
+			img.Inline = true
+			*out = append(*out, *img)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package reader
+
+import (
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/metadata"
+	"github.com/tsawler/tabula/model"
+)
+
+// Metadata reads the document's metadata from its information dictionary
+// and its XMP metadata stream, combined by metadata.Merge. The XMP stream is
+// read on a best-effort basis: one that cannot be decoded or parsed is
+// ignored. When the information dictionary cannot be read, the metadata
+// from XMP alone is returned together with the error.
+func (r *Reader) Metadata() (model.Metadata, error) {
+	var fromInfo model.Metadata
+	info, err := r.GetInfo()
+	if err == nil {
+		fromInfo = metadata.FromInfo(info, r.ResolveReference)
+	}
+
+	var fromXMP model.Metadata
+	if data := r.xmpPacket(); data != nil {
+		if meta, xmpErr := metadata.ParseXMP(data); xmpErr == nil {
+			fromXMP = meta
+		}
+	}
+	return metadata.Merge(fromXMP, fromInfo), err
+}
+
+// xmpPacket returns the decoded XMP metadata stream of the document
+// catalog, or nil if there is none
+func (r *Reader) xmpPacket() []byte {
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		return nil
+	}
+	obj, err := r.Resolve(catalog.Get("Metadata"))
+	if err != nil {
+		return nil
+	}
+	stream, ok := obj.(*core.Stream)
+	if !ok {
+		return nil
+	}
+	data, err := stream.Decode()
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,28 @@
+package reader
+
+import "github.com/tsawler/tabula/pages"
+
+// Outline reads the document outline (bookmarks). It returns nil and no
+// error if the document has no outline.
+func (r *Reader) Outline() ([]*pages.OutlineItem, error) {
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return pages.NewCatalog(catalog, r).Outline(r.PageIndex)
+}
+
+// PageLabels returns the printed label of each page, as defined by the
+// catalog's /PageLabels. It returns nil and no error if the document
+// defines no labels.
+func (r *Reader) PageLabels() ([]string, error) {
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		return nil, err
+	}
+	pageCount, err := r.PageCount()
+	if err != nil {
+		return nil, err
+	}
+	return pages.NewCatalog(catalog, r).PageLabels(pageCount), nil
+}
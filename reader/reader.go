@@ -28,7 +28,8 @@ func (v PDFVersion) String() string {
 
 // Reader represents a PDF file reader
 type Reader struct {
-	file        *os.File
+	file        *io.SectionReader // seekable view of the source, shared by parsers
+	closer      io.Closer         // closed by Close; nil when the caller owns the source
 	xrefTable   *core.XRefTable
 	trailer     core.Dict
 	version     PDFVersion
@@ -36,6 +37,7 @@ type Reader struct {
 	objStmCache map[int]*core.ObjectStream // Cache for object streams
 	fileSize    int64
 	pageTree    *pages.PageTree // Cached page tree
+	annotPages  map[int]int     // Annotation object number to page index, built on demand
 
 	security      *core.StdSecurityHandler // non-nil when the document is encrypted
 	encryptObjNum int                      // object number of the /Encrypt dict (not itself encrypted)
@@ -44,19 +46,54 @@ type Reader struct {
 // Ensure Reader implements pages.ObjectResolver
 var _ pages.ObjectResolver = (*Reader)(nil)
 
+var (
+	// ErrPasswordRequired is returned when opening an encrypted PDF that
+	// needs a password and none was supplied.
+	ErrPasswordRequired = core.ErrPasswordRequired
+
+	// ErrWrongPassword is returned when the supplied password is neither
+	// the user nor the owner password of an encrypted PDF.
+	ErrWrongPassword = core.ErrWrongPassword
+)
+
 // NewReader creates a new PDF reader for the given file
 func NewReader(file *os.File) (*Reader, error) {
+	return NewReaderWithPassword(file, "")
+}
+
+// NewReaderWithPassword creates a new PDF reader for the given file, using
+// password to decrypt it if the document is encrypted. The password may be
+// either the user or the owner password.
+func NewReaderWithPassword(file *os.File, password string) (*Reader, error) {
 	// Get file size
 	fileInfo, err := file.Stat()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file info: %w", err)
 	}
 
+	reader, err := NewReaderAtWithPassword(file, fileInfo.Size(), password)
+	if err != nil {
+		return nil, err
+	}
+	reader.closer = file
+	return reader, nil
+}
+
+// NewReaderAt creates a new PDF reader over size bytes of ra, such as a
+// bytes.Reader holding a document downloaded into memory. The caller keeps
+// ownership of ra; Close does not close it.
+func NewReaderAt(ra io.ReaderAt, size int64) (*Reader, error) {
+	return NewReaderAtWithPassword(ra, size, "")
+}
+
+// NewReaderAtWithPassword is like NewReaderAt but uses password to decrypt the
+// document if it is encrypted.
+func NewReaderAtWithPassword(ra io.ReaderAt, size int64, password string) (*Reader, error) {
 	reader := &Reader{
-		file:        file,
+		file:        io.NewSectionReader(ra, 0, size),
 		objCache:    make(map[int]core.Object),
 		objStmCache: make(map[int]*core.ObjectStream),
-		fileSize:    fileInfo.Size(),
+		fileSize:    size,
 	}
 
 	// Parse PDF header
@@ -77,7 +114,7 @@ func NewReader(file *os.File) (*Reader, error) {
 	// Set up decryption if the document is encrypted. Must happen before any
 	// object strings/streams are read (other than the xref/trailer themselves,
 	// which are never encrypted).
-	if err := reader.setupSecurity(); err != nil {
+	if err := reader.setupSecurity(password); err != nil {
 		return nil, err
 	}
 
@@ -87,7 +124,7 @@ func NewReader(file *os.File) (*Reader, error) {
 // setupSecurity builds the standard security handler when the trailer has an
 // /Encrypt entry. The Encrypt dictionary and document /ID are themselves
 // unencrypted, so resolving them here (before r.security is set) is safe.
-func (r *Reader) setupSecurity() error {
+func (r *Reader) setupSecurity(password string) error {
 	encObj := r.trailer.Get("Encrypt")
 	if encObj == nil {
 		return nil // not encrypted
@@ -111,7 +148,7 @@ func (r *Reader) setupSecurity() error {
 		}
 	}
 
-	sec, err := core.NewStdSecurityHandler(encDict, id)
+	sec, err := core.NewStdSecurityHandlerWithPassword(encDict, id, password)
 	if err != nil {
 		return err
 	}
@@ -170,12 +207,27 @@ func skipStreamDecrypt(s *core.Stream, sec *core.StdSecurityHandler) bool {
 
 // Open opens a PDF file and returns a Reader
 func Open(filename string) (*Reader, error) {
+	return OpenWithPassword(filename, "")
+}
+
+// OpenWithPassword opens an encrypted PDF file with the given user or owner
+// password and returns a Reader. Unencrypted files open normally and the
+// password is ignored.
+//
+// The returned error wraps ErrPasswordRequired or ErrWrongPassword when the
+// document cannot be decrypted, so callers can prompt for a password:
+//
+//	r, err := reader.OpenWithPassword("contract.pdf", pw)
+//	if errors.Is(err, reader.ErrWrongPassword) {
+//	    // ask again
+//	}
+func OpenWithPassword(filename, password string) (*Reader, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 
-	reader, err := NewReader(file)
+	reader, err := NewReaderWithPassword(file, password)
 	if err != nil {
 		file.Close()
 		return nil, err
@@ -184,10 +236,14 @@ func Open(filename string) (*Reader, error) {
 	return reader, nil
 }
 
-// Close closes the PDF file
+// Close closes the PDF file. Readers created with NewReaderAt leave the
+// underlying io.ReaderAt open.
 func (r *Reader) Close() error {
-	if r.file != nil {
-		return r.file.Close()
+	r.file = nil
+	if r.closer != nil {
+		err := r.closer.Close()
+		r.closer = nil
+		return err
 	}
 	return nil
 }
@@ -449,6 +505,10 @@ func (r *Reader) GetObject(objNum int) (core.Object, error) {
 
 // getUncompressedObject reads an object directly from the file
 func (r *Reader) getUncompressedObject(objNum int, entry *core.XRefEntry) (core.Object, error) {
+	if r.file == nil {
+		return nil, fmt.Errorf("reader is closed")
+	}
+
 	// Reading an object seeks the shared file handle. A caller in the middle of
 	// a sequential parse (e.g. parseStream resolving an indirect /Length before
 	// reading the stream body) relies on the file position being undisturbed, so
@@ -822,5 +882,38 @@ func (r *Reader) extractTextWithFragments(page *pages.Page) (*text.Extractor, []
 		return nil, nil, fmt.Errorf("failed to extract text: %w", err)
 	}
 
+	// Attach hyperlink targets
+	r.linkFragments(page, fragments)
+
+	markOffPage(page, fragments)
+
 	return extractor, fragments, nil
 }
+
+// offPageTolerance is the slack, in points, allowed when testing whether
+// text lies on the page
+const offPageTolerance = 1.0
+
+// markOffPage marks the fragments whose center lies outside the page's crop
+// box, which are never displayed, as not visible
+func markOffPage(page *pages.Page, fragments []text.TextFragment) {
+	box, err := page.CropBox()
+	if err != nil || len(box) != 4 {
+		return
+	}
+	x0, y0, x1, y1 := box[0], box[1], box[2], box[3]
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+
+	for i := range fragments {
+		f := &fragments[i]
+		cx, cy := f.X+f.Width/2, f.Y+f.Height/3
+		if cx < x0-offPageTolerance || cx > x1+offPageTolerance || cy < y0-offPageTolerance || cy > y1+offPageTolerance {
+			f.Visible = false
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package reader
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -514,6 +515,29 @@ func TestClearCache(t *testing.T) {
 	}
 }
 
+// TestNewReaderAt tests reading a PDF held in memory
+func TestNewReaderAt(t *testing.T) {
+	data := []byte(pdfWithInfo)
+
+	reader, err := NewReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewReaderAt failed: %v", err)
+	}
+	defer reader.Close()
+
+	if reader.FileSize() != int64(len(data)) {
+		t.Errorf("FileSize = %d, want %d", reader.FileSize(), len(data))
+	}
+
+	info, err := reader.GetInfo()
+	if err != nil {
+		t.Fatalf("GetInfo failed: %v", err)
+	}
+	if title, _ := info.Get("Title").(core.String); string(title) != "Test Document" {
+		t.Errorf("Title = %q, want %q", title, "Test Document")
+	}
+}
+
 // TestClose tests closing the reader
 func TestClose(t *testing.T) {
 	tmpFile := createTempPDF(t, minimalPDF)
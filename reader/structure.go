@@ -0,0 +1,26 @@
+package reader
+
+import (
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/structure"
+)
+
+// PageIndex returns the 0-based index of the page with the given object
+// reference, for resolving the page references of structure elements,
+// outline entries and link destinations
+func (r *Reader) PageIndex(ref core.IndirectRef) (int, bool) {
+	if err := r.ensurePageTree(); err != nil {
+		return 0, false
+	}
+	return r.pageTree.IndexOf(ref)
+}
+
+// StructTree parses the document's logical structure tree. It returns nil
+// and no error if the document is not tagged.
+func (r *Reader) StructTree() (*structure.Tree, error) {
+	catalog, err := r.GetCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return structure.Parse(catalog, r.ResolveReference, r.PageIndex)
+}
@@ -0,0 +1,183 @@
+package render
+
+import (
+	"math"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// Color space families, as far as rendering distinguishes them
+const (
+	csDevice  = iota // Gray, RGB or CMYK components, including ICC-based and calibrated spaces
+	csTint           // Separation and DeviceN: tints of colorants, drawn as gray
+	csIndexed        // Palette lookup
+	csLab            // CIE L*a*b*, drawn as gray by lightness
+	csPattern        // Patterns, which are not drawn
+)
+
+// maxColorSpaceDepth limits how deep color spaces may refer to each other
+const maxColorSpaceDepth = 4
+
+// colorSpace is a color space reduced to what is needed to turn color
+// components into RGB
+type colorSpace struct {
+	kind         int
+	comps        int    // Components of a device space
+	palette      []byte // Indexed lookup table
+	paletteComps int    // Components of each palette entry, in the base space
+}
+
+var deviceGray = colorSpace{kind: csDevice, comps: 1}
+
+// deviceSpace returns the device space with n components
+func deviceSpace(n int) colorSpace {
+	return colorSpace{kind: csDevice, comps: n}
+}
+
+// initial returns the initial color of the space: black, or the first
+// palette entry of an indexed space
+func (cs colorSpace) initial() [3]float64 {
+	if cs.kind == csIndexed {
+		if c, ok := cs.toRGB([]float64{0}); ok {
+			return c
+		}
+	}
+	return [3]float64{}
+}
+
+// toRGB converts color components to RGB
+func (cs colorSpace) toRGB(n []float64) ([3]float64, bool) {
+	switch cs.kind {
+	case csDevice:
+		return componentsToRGB(n)
+	case csTint:
+		if len(n) == 0 {
+			return [3]float64{}, false
+		}
+		ink := 0.0
+		for _, t := range n {
+			ink = math.Max(ink, t)
+		}
+		g := 1 - clamp01(ink)
+		return [3]float64{g, g, g}, true
+	case csLab:
+		if len(n) == 0 {
+			return [3]float64{}, false
+		}
+		g := clamp01(n[0] / 100)
+		return [3]float64{g, g, g}, true
+	case csIndexed:
+		if len(n) != 1 || cs.paletteComps == 0 {
+			return [3]float64{}, false
+		}
+		i := int(n[0]) * cs.paletteComps
+		if i < 0 || i+cs.paletteComps > len(cs.palette) {
+			return [3]float64{}, false
+		}
+		entry := make([]float64, cs.paletteComps)
+		for j := range entry {
+			entry[j] = float64(cs.palette[i+j]) / 255
+		}
+		return componentsToRGB(entry)
+	}
+	return [3]float64{}, false
+}
+
+// componentsToRGB converts gray, RGB or CMYK components to RGB
+func componentsToRGB(n []float64) ([3]float64, bool) {
+	switch len(n) {
+	case 1:
+		return [3]float64{n[0], n[0], n[0]}, true
+	case 3:
+		return [3]float64{n[0], n[1], n[2]}, true
+	case 4:
+		k := 1 - clamp01(n[3])
+		return [3]float64{(1 - clamp01(n[0])) * k, (1 - clamp01(n[1])) * k, (1 - clamp01(n[2])) * k}, true
+	}
+	return [3]float64{}, false
+}
+
+// colorSpace resolves a color space operand: a device space name, or a
+// name in the resources' /ColorSpace dictionary
+func (rd *renderer) colorSpace(obj core.Object, resources core.Dict) colorSpace {
+	if name, ok := obj.(core.Name); ok {
+		if cs, ok := deviceNamed(string(name)); ok {
+			return cs
+		}
+		if spaces, ok := rd.resolveDict(resources.Get("ColorSpace")); ok {
+			return rd.parseColorSpace(spaces.Get(string(name)), 0)
+		}
+		return deviceGray
+	}
+	return rd.parseColorSpace(obj, 0)
+}
+
+// deviceNamed returns the space of a color space family name, with the
+// abbreviations of inline images
+func deviceNamed(name string) (colorSpace, bool) {
+	switch name {
+	case "DeviceGray", "G", "CalGray":
+		return deviceGray, true
+	case "DeviceRGB", "RGB", "CalRGB":
+		return deviceSpace(3), true
+	case "DeviceCMYK", "CMYK":
+		return deviceSpace(4), true
+	case "Pattern":
+		return colorSpace{kind: csPattern}, true
+	case "Lab":
+		return colorSpace{kind: csLab}, true
+	}
+	return colorSpace{}, false
+}
+
+// parseColorSpace reads a color space object
+func (rd *renderer) parseColorSpace(obj core.Object, depth int) colorSpace {
+	obj = rd.resolve(obj)
+	if name, ok := obj.(core.Name); ok {
+		if cs, ok := deviceNamed(string(name)); ok {
+			return cs
+		}
+		return deviceGray
+	}
+	arr, ok := obj.(core.Array)
+	if !ok || len(arr) == 0 || depth > maxColorSpaceDepth {
+		return deviceGray
+	}
+	family, _ := rd.resolve(arr[0]).(core.Name)
+
+	switch family {
+	case "ICCBased":
+		if len(arr) > 1 {
+			if stream, ok := rd.resolve(arr[1]).(*core.Stream); ok {
+				if n, ok := number(rd.resolve(stream.Dict.Get("N"))); ok && (n == 1 || n == 3 || n == 4) {
+					return deviceSpace(int(n))
+				}
+			}
+		}
+	case "Separation", "DeviceN":
+		return colorSpace{kind: csTint}
+	case "Indexed", "I":
+		if len(arr) < 4 {
+			break
+		}
+		base := rd.parseColorSpace(arr[1], depth+1)
+		if base.kind != csDevice {
+			break
+		}
+		var lookup []byte
+		switch v := rd.resolve(arr[3]).(type) {
+		case core.String:
+			lookup = []byte(v)
+		case *core.Stream:
+			lookup, _ = v.Decode()
+		}
+		return colorSpace{kind: csIndexed, palette: lookup, paletteComps: base.comps}
+	case "Pattern":
+		return colorSpace{kind: csPattern}
+	default:
+		if cs, ok := deviceNamed(string(family)); ok {
+			return cs
+		}
+	}
+	return deviceGray
+}
@@ -0,0 +1,34 @@
+// Package render rasterizes PDF pages to images in pure Go.
+//
+// A page's content stream is interpreted with the graphics state of
+// [graphicsstate] and painted onto an RGBA image: paths are filled and
+// stroked, images and stencil masks are drawn through their placement
+// matrix, and text is drawn from the glyph outlines of the embedded
+// TrueType, CFF or Type 1 font program (see [font.GlyphSet]). Fonts that
+// are not embedded are drawn with a Go font of matching weight, slant and
+// pitch, scaled to the PDF's glyph widths. Form XObjects, clipping paths
+// (as their bounding box), constant alpha and the common color spaces are
+// supported.
+//
+// The renderer aims at images good enough for OCR and previews rather than
+// at full fidelity: shadings and patterns are not painted, Type 3 fonts are
+// skipped, even-odd fills are drawn with the non-zero winding rule, and
+// strokes have butt caps and no dashes or joins.
+//
+// # Rendering
+//
+// [Page] renders the page's CropBox (or MediaBox), turned by its /Rotate,
+// at the requested resolution:
+//
+//	img, err := render.Page(r, 0, render.Options{DPI: 300})
+//	if err != nil {
+//	    return err
+//	}
+//	png.Encode(w, img)
+//
+// At 72 DPI one pixel is one PDF point. [Region] renders only the part of a
+// page inside a box in user space, such as the bounding box of a heading or
+// paragraph found by layout analysis:
+//
+//	img, err := render.Region(r, 0, heading.BBox, render.Options{DPI: 150})
+package render
@@ -0,0 +1,97 @@
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/reader"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+)
+
+// drawImage paints an image into the unit square of user space, with an
+// optional soft mask. Stencil masks are painted with the fill color.
+func (rd *renderer) drawImage(img *reader.PageImage, smask *reader.PageImage) {
+	if img.Width <= 0 || img.Height <= 0 {
+		return
+	}
+	area := rd.clipRect()
+	if area.Empty() {
+		return
+	}
+	src, err := img.Image()
+	if err != nil {
+		return
+	}
+
+	alpha := rd.paint.fillAlpha
+	switch {
+	case img.ImageMask:
+		if rd.paint.fillSpace.kind == csPattern {
+			return
+		}
+		src = stencil(src, toColor(rd.gs.FillColor, alpha))
+	case smask != nil || alpha < 1:
+		var mask image.Image
+		if smask != nil {
+			mask, _ = smask.Image()
+		}
+		src = withAlpha(src, mask, alpha)
+	}
+
+	// Image pixels to the unit square, flipped so row 0 is at the top, then
+	// to pixels
+	b := src.Bounds()
+	unit := model.Matrix{1 / float64(b.Dx()), 0, 0, -1 / float64(b.Dy()), -float64(b.Min.X) / float64(b.Dx()), 1 + float64(b.Min.Y)/float64(b.Dy())}
+	m := unit.Multiply(rd.device())
+	if m[0]*m[3]-m[1]*m[2] == 0 {
+		return
+	}
+	s2d := f64.Aff3{m[0], m[2], m[4], m[1], m[3], m[5]}
+
+	dst, ok := rd.dst.SubImage(area).(*image.RGBA)
+	if !ok {
+		return
+	}
+	draw.ApproxBiLinear.Transform(dst, s2d, src, b, draw.Over, nil)
+}
+
+// stencil turns a decoded stencil mask, black where paint is applied, into
+// an image of the given color
+func stencil(mask image.Image, c color.NRGBA) *image.NRGBA {
+	b := mask.Bounds()
+	out := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			g := color.GrayModel.Convert(mask.At(x, y)).(color.Gray).Y
+			out.SetNRGBA(x, y, color.NRGBA{R: c.R, G: c.G, B: c.B, A: uint8(uint32(255-g) * uint32(c.A) / 255)})
+		}
+	}
+	return out
+}
+
+// withAlpha applies a soft mask, scaled to the image's size, and a constant
+// alpha to an image
+func withAlpha(src, mask image.Image, alpha float64) *image.NRGBA {
+	b := src.Bounds()
+	out := image.NewNRGBA(b)
+	var mb image.Rectangle
+	if mask != nil {
+		mb = mask.Bounds()
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+			a := float64(c.A) / 255 * alpha
+			if mask != nil && !mb.Empty() {
+				mx := mb.Min.X + (x-b.Min.X)*mb.Dx()/b.Dx()
+				my := mb.Min.Y + (y-b.Min.Y)*mb.Dy()/b.Dy()
+				a *= float64(color.GrayModel.Convert(mask.At(mx, my)).(color.Gray).Y) / 255
+			}
+			c.A = uint8(a*255 + 0.5)
+			out.SetNRGBA(x, y, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,319 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/tsawler/tabula/graphicsstate"
+	"github.com/tsawler/tabula/model"
+	"golang.org/x/image/vector"
+)
+
+// Path segment kinds
+const (
+	segMoveTo = iota
+	segLineTo
+	segQuadTo
+	segCubeTo
+	segClose
+)
+
+// segment is a path segment; a quadratic curve uses pts[0:2] and a cubic
+// curve pts[0:3]
+type segment struct {
+	kind int
+	pts  [3]model.Point
+}
+
+// path is a sequence of segments, in user space or in pixels
+type path []segment
+
+// box is an axis-aligned rectangle in pixels
+type box struct {
+	x0, y0, x1, y1 float64
+}
+
+// maxCoord bounds pixel coordinates converted to integers
+const maxCoord = 1 << 24
+
+// empty reports whether the box contains no points
+func (b box) empty() bool {
+	return !(b.x0 <= b.x1 && b.y0 <= b.y1)
+}
+
+// rect returns the whole pixels the box touches
+func (b box) rect() image.Rectangle {
+	if b.empty() {
+		return image.Rectangle{}
+	}
+	c := func(v float64) int { return int(math.Max(-maxCoord, math.Min(maxCoord, v))) }
+	return image.Rect(c(math.Floor(b.x0)), c(math.Floor(b.y0)), c(math.Ceil(b.x1)), c(math.Ceil(b.y1)))
+}
+
+// rectPath returns a closed rectangle
+func rectPath(x, y, w, h float64) path {
+	return path{
+		{kind: segMoveTo, pts: [3]model.Point{{X: x, Y: y}}},
+		{kind: segLineTo, pts: [3]model.Point{{X: x + w, Y: y}}},
+		{kind: segLineTo, pts: [3]model.Point{{X: x + w, Y: y + h}}},
+		{kind: segLineTo, pts: [3]model.Point{{X: x, Y: y + h}}},
+		{kind: segClose},
+	}
+}
+
+// fromGraphicsPath converts a path built by a PathExtractor
+func fromGraphicsPath(gp *graphicsstate.Path) path {
+	p := make(path, 0, len(gp.Segments))
+	for _, s := range gp.Segments {
+		var seg segment
+		switch s.Type {
+		case graphicsstate.PathMoveTo:
+			seg.kind = segMoveTo
+		case graphicsstate.PathLineTo:
+			seg.kind = segLineTo
+		case graphicsstate.PathCurveTo:
+			seg.kind = segCubeTo
+		case graphicsstate.PathClosePath:
+			seg.kind = segClose
+		default:
+			continue
+		}
+		copy(seg.pts[:], s.Points)
+		p = append(p, seg)
+	}
+	return p
+}
+
+// transform returns the path mapped through m
+func (p path) transform(m model.Matrix) path {
+	out := make(path, len(p))
+	for i, s := range p {
+		out[i].kind = s.kind
+		for j := range s.pts {
+			out[i].pts[j] = m.Transform(s.pts[j])
+		}
+	}
+	return out
+}
+
+// points returns the number of points a segment uses
+func (s segment) points() int {
+	switch s.kind {
+	case segMoveTo, segLineTo:
+		return 1
+	case segQuadTo:
+		return 2
+	case segCubeTo:
+		return 3
+	}
+	return 0
+}
+
+// bounds returns the bounding box of the path's points, which contains the
+// curves they control
+func (p path) bounds() box {
+	b := box{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, s := range p {
+		for _, pt := range s.pts[:s.points()] {
+			b.x0, b.y0 = math.Min(b.x0, pt.X), math.Min(b.y0, pt.Y)
+			b.x1, b.y1 = math.Max(b.x1, pt.X), math.Max(b.y1, pt.Y)
+		}
+	}
+	return b
+}
+
+// paintPath paints and ends the current path (S, s, f, F, f*, B, B*, b, b*
+// and n operators), then applies a pending clip
+func (rd *renderer) paintPath(op string) {
+	p := fromGraphicsPath(rd.pe.CurrentPath())
+	if op == "s" || op == "b" || op == "b*" {
+		p = append(p, segment{kind: segClose})
+	}
+	dp := p.transform(rd.device())
+
+	switch op {
+	case "f", "F", "f*":
+		rd.fill(dp, rd.gs.FillColor, rd.paint.fillAlpha, rd.paint.fillSpace)
+	case "S", "s":
+		rd.stroke(dp, rd.gs.LineWidth, rd.gs.StrokeColor, rd.paint.strokeAlpha, rd.paint.strokeSpace)
+	case "B", "B*", "b", "b*":
+		rd.fill(dp, rd.gs.FillColor, rd.paint.fillAlpha, rd.paint.fillSpace)
+		rd.stroke(dp, rd.gs.LineWidth, rd.gs.StrokeColor, rd.paint.strokeAlpha, rd.paint.strokeSpace)
+	}
+
+	if rd.clipPending {
+		rd.clipTo(dp.bounds())
+	}
+	rd.clipPending = false
+	rd.pe.EndPath()
+}
+
+// fill fills a path in pixels with the nonzero winding rule. The even-odd
+// rule is approximated by it.
+func (rd *renderer) fill(dp path, rgb [3]float64, alpha float64, cs colorSpace) {
+	if len(dp) == 0 || alpha <= 0 || cs.kind == csPattern {
+		return
+	}
+	area := dp.bounds().rect().Intersect(rd.clipRect())
+	if area.Empty() {
+		return
+	}
+
+	z := vector.NewRasterizer(area.Dx(), area.Dy())
+	ox, oy := float64(area.Min.X), float64(area.Min.Y)
+	pt := func(p model.Point) (float32, float32) {
+		return float32(p.X - ox), float32(p.Y - oy)
+	}
+	open := false
+	for _, s := range dp {
+		switch s.kind {
+		case segMoveTo:
+			if open {
+				z.ClosePath()
+			}
+			z.MoveTo(pt(s.pts[0]))
+			open = true
+		case segLineTo:
+			z.LineTo(pt(s.pts[0]))
+		case segQuadTo:
+			x1, y1 := pt(s.pts[0])
+			x2, y2 := pt(s.pts[1])
+			z.QuadTo(x1, y1, x2, y2)
+		case segCubeTo:
+			x1, y1 := pt(s.pts[0])
+			x2, y2 := pt(s.pts[1])
+			x3, y3 := pt(s.pts[2])
+			z.CubeTo(x1, y1, x2, y2, x3, y3)
+		case segClose:
+			if open {
+				z.ClosePath()
+			}
+		}
+	}
+	if open {
+		z.ClosePath()
+	}
+	z.DrawOp = draw.Over
+	z.Draw(rd.dst, area, image.NewUniform(toColor(rgb, alpha)), image.Point{})
+}
+
+// stroke strokes a path in pixels. Each flattened segment is drawn as a
+// rectangle of the line width, at least one pixel; joins, caps and dashes
+// are not drawn.
+func (rd *renderer) stroke(dp path, lineWidth float64, rgb [3]float64, alpha float64, cs colorSpace) {
+	if len(dp) == 0 || alpha <= 0 || cs.kind == csPattern {
+		return
+	}
+	m := rd.device()
+	half := math.Max(lineWidth*math.Sqrt(math.Abs(m[0]*m[3]-m[1]*m[2])), 1) / 2
+
+	var quads path
+	for _, line := range flatten(dp) {
+		for i := 0; i+1 < len(line); i++ {
+			a, b := line[i], line[i+1]
+			dx, dy := b.X-a.X, b.Y-a.Y
+			l := math.Hypot(dx, dy)
+			if l == 0 {
+				continue
+			}
+			// The normal always lies on the same side of the direction, so
+			// every rectangle winds the same way and overlaps add up
+			nx, ny := -dy/l*half, dx/l*half
+			quads = append(quads,
+				segment{kind: segMoveTo, pts: [3]model.Point{{X: a.X + nx, Y: a.Y + ny}}},
+				segment{kind: segLineTo, pts: [3]model.Point{{X: b.X + nx, Y: b.Y + ny}}},
+				segment{kind: segLineTo, pts: [3]model.Point{{X: b.X - nx, Y: b.Y - ny}}},
+				segment{kind: segLineTo, pts: [3]model.Point{{X: a.X - nx, Y: a.Y - ny}}},
+				segment{kind: segClose},
+			)
+		}
+	}
+	rd.fill(quads, rgb, alpha, cs)
+}
+
+// flatten approximates a path's curves with lines, returning each subpath
+// as a polyline; closed subpaths end at their start
+func flatten(p path) [][]model.Point {
+	var lines [][]model.Point
+	var cur []model.Point
+	finish := func() {
+		if len(cur) > 1 {
+			lines = append(lines, cur)
+		}
+		cur = nil
+	}
+	last := func() model.Point { return cur[len(cur)-1] }
+
+	for _, s := range p {
+		switch s.kind {
+		case segMoveTo:
+			finish()
+			cur = []model.Point{s.pts[0]}
+		case segLineTo:
+			if cur == nil {
+				cur = []model.Point{s.pts[0]}
+				continue
+			}
+			cur = append(cur, s.pts[0])
+		case segQuadTo, segCubeTo:
+			if cur == nil {
+				cur = []model.Point{s.pts[0]}
+			}
+			a := last()
+			n := curveSteps(a, s)
+			for i := 1; i <= n; i++ {
+				cur = append(cur, curvePoint(a, s, float64(i)/float64(n)))
+			}
+		case segClose:
+			if cur != nil {
+				start := cur[0]
+				cur = append(cur, start)
+				finish()
+				cur = []model.Point{start}
+			}
+		}
+	}
+	finish()
+	return lines
+}
+
+// curveSteps returns the number of lines approximating a curve from a,
+// about one per two pixels of its control polygon
+func curveSteps(a model.Point, s segment) int {
+	length := 0.0
+	prev := a
+	for _, p := range s.pts[:s.points()] {
+		length += math.Hypot(p.X-prev.X, p.Y-prev.Y)
+		prev = p
+	}
+	n := int(length/2) + 1
+	if n > 64 {
+		n = 64
+	}
+	return n
+}
+
+// curvePoint evaluates a quadratic or cubic curve from a at t
+func curvePoint(a model.Point, s segment, t float64) model.Point {
+	u := 1 - t
+	if s.kind == segQuadTo {
+		b, c := s.pts[0], s.pts[1]
+		return model.Point{
+			X: u*u*a.X + 2*u*t*b.X + t*t*c.X,
+			Y: u*u*a.Y + 2*u*t*b.Y + t*t*c.Y,
+		}
+	}
+	b, c, d := s.pts[0], s.pts[1], s.pts[2]
+	return model.Point{
+		X: u*u*u*a.X + 3*u*u*t*b.X + 3*u*t*t*c.X + t*t*t*d.X,
+		Y: u*u*u*a.Y + 3*u*u*t*b.Y + 3*u*t*t*c.Y + t*t*t*d.Y,
+	}
+}
+
+// toColor converts RGB components in [0, 1] and an alpha to a color
+func toColor(rgb [3]float64, alpha float64) color.NRGBA {
+	c := func(v float64) uint8 { return uint8(clamp01(v)*255 + 0.5) }
+	return color.NRGBA{R: c(rgb[0]), G: c(rgb[1]), B: c(rgb[2]), A: c(alpha)}
+}
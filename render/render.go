@@ -0,0 +1,603 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/tsawler/tabula/contentstream"
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/font"
+	"github.com/tsawler/tabula/graphicsstate"
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/pages"
+	"github.com/tsawler/tabula/reader"
+)
+
+// DefaultDPI is the resolution used when Options.DPI is not set
+const DefaultDPI = 150
+
+// maxFormDepth bounds recursion into nested Form XObjects
+const maxFormDepth = 12
+
+// maxPixels bounds the size of a rendered image, so a huge page or DPI
+// cannot exhaust memory
+const maxPixels = 1 << 28
+
+// Options configures rendering
+type Options struct {
+	// DPI is the resolution in pixels per inch; 0 means DefaultDPI
+	DPI float64
+
+	// Background is the color the page is painted with before its
+	// content; nil means white
+	Background color.Color
+}
+
+// Page renders a page, by 0-based index, to an image. The page's CropBox
+// (or MediaBox) is rendered, turned by its /Rotate.
+func Page(r *reader.Reader, index int, opts Options) (*image.RGBA, error) {
+	page, err := r.GetPage(index)
+	if err != nil {
+		return nil, err
+	}
+	g, err := pageGeometry(page, opts)
+	if err != nil {
+		return nil, err
+	}
+	return renderPage(r, page, g.base, g.width, g.height, opts)
+}
+
+// Region renders the part of a page, by 0-based index, inside a box given
+// in the page's user space (PDF points, origin bottom-left), such as the
+// bounding box of extracted text. The region is clipped to the page and
+// turned by the page's /Rotate like Page.
+func Region(r *reader.Reader, index int, area model.BBox, opts Options) (*image.RGBA, error) {
+	page, err := r.GetPage(index)
+	if err != nil {
+		return nil, err
+	}
+	g, err := pageGeometry(page, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The region's corners in pixels, which any rotation keeps axis-aligned
+	x0, y0 := math.Inf(1), math.Inf(1)
+	x1, y1 := math.Inf(-1), math.Inf(-1)
+	for _, p := range []model.Point{
+		{X: area.X, Y: area.Y},
+		{X: area.X + area.Width, Y: area.Y},
+		{X: area.X, Y: area.Y + area.Height},
+		{X: area.X + area.Width, Y: area.Y + area.Height},
+	} {
+		d := g.base.Transform(p)
+		x0, y0 = math.Min(x0, d.X), math.Min(y0, d.Y)
+		x1, y1 = math.Max(x1, d.X), math.Max(y1, d.Y)
+	}
+	rect := image.Rect(int(math.Floor(x0)), int(math.Floor(y0)), int(math.Ceil(x1)), int(math.Ceil(y1)))
+	rect = rect.Intersect(image.Rect(0, 0, g.width, g.height))
+	if rect.Empty() {
+		return nil, fmt.Errorf("region %v is outside the page", area)
+	}
+
+	base := g.base.Multiply(model.Translate(-float64(rect.Min.X), -float64(rect.Min.Y)))
+	return renderPage(r, page, base, rect.Dx(), rect.Dy(), opts)
+}
+
+// PageMatrix returns the matrix mapping a page's user space (PDF points,
+// origin bottom-left) to the pixels of the image Page renders with opts
+// (origin top-left). Its inverse maps positions found in the image, such
+// as the words OCR recognizes, back onto the page.
+func PageMatrix(page *pages.Page, opts Options) (model.Matrix, error) {
+	g, err := pageGeometry(page, opts)
+	if err != nil {
+		return model.Matrix{}, err
+	}
+	return g.base, nil
+}
+
+// geometry maps a page's user space to the pixels of its rendered image
+type geometry struct {
+	base          model.Matrix // User space to pixels, y down
+	width, height int          // Image size in pixels
+}
+
+// pageGeometry computes a page's mapping to pixels at the options' DPI
+func pageGeometry(page *pages.Page, opts Options) (geometry, error) {
+	box, err := page.CropBox()
+	if err != nil {
+		return geometry{}, fmt.Errorf("page box: %w", err)
+	}
+	x0, y0 := math.Min(box[0], box[2]), math.Min(box[1], box[3])
+	w, h := math.Abs(box[2]-box[0]), math.Abs(box[3]-box[1])
+	if w <= 0 || h <= 0 {
+		return geometry{}, fmt.Errorf("empty page box")
+	}
+
+	dpi := opts.DPI
+	if dpi <= 0 {
+		dpi = DefaultDPI
+	}
+	s := dpi / 72
+
+	var rot model.Matrix
+	width, height := w*s, h*s
+	switch ((page.Rotate() % 360) + 360) % 360 {
+	case 90:
+		rot = model.Matrix{0, s, s, 0, 0, 0}
+		width, height = height, width
+	case 180:
+		rot = model.Matrix{-s, 0, 0, s, w * s, 0}
+	case 270:
+		rot = model.Matrix{0, -s, -s, 0, h * s, w * s}
+		width, height = height, width
+	default:
+		rot = model.Matrix{s, 0, 0, -s, 0, h * s}
+	}
+
+	g := geometry{
+		base:   model.Translate(-x0, -y0).Multiply(rot),
+		width:  int(math.Ceil(width - 0.01)),
+		height: int(math.Ceil(height - 0.01)),
+	}
+	if g.width < 1 || g.height < 1 || float64(g.width)*float64(g.height) > maxPixels {
+		return geometry{}, fmt.Errorf("page size %dx%d pixels out of range", g.width, g.height)
+	}
+	return g, nil
+}
+
+// renderPage paints a page's content into a new image of the given size,
+// mapping user space to pixels with base
+func renderPage(r *reader.Reader, page *pages.Page, base model.Matrix, width, height int, opts Options) (*image.RGBA, error) {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	bg := opts.Background
+	if bg == nil {
+		bg = color.White
+	}
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+
+	resources, err := page.Resources()
+	if err != nil {
+		resources = nil
+	}
+	rd := newRenderer(r, dst, base)
+	rd.run(pageContentData(page), resources, 0)
+	return dst, nil
+}
+
+// pageContentData decodes and concatenates a page's content streams,
+// skipping streams that cannot be decoded
+func pageContentData(page *pages.Page) []byte {
+	contents, err := page.Contents()
+	if err != nil {
+		return nil
+	}
+	var data []byte
+	for _, contentObj := range contents {
+		stream, ok := contentObj.(*core.Stream)
+		if !ok {
+			continue
+		}
+		decoded, err := stream.Decode()
+		if err != nil {
+			continue
+		}
+		data = append(data, decoded...)
+		data = append(data, '\n')
+	}
+	return data
+}
+
+// paintState is the part of the graphics state that GraphicsState does not
+// track
+type paintState struct {
+	fillSpace, strokeSpace colorSpace
+	fillAlpha, strokeAlpha float64
+	font                   *font.GlyphSet
+}
+
+// renderer interprets content streams, painting into an image
+type renderer struct {
+	r    *reader.Reader
+	dst  *image.RGBA
+	base model.Matrix // User space to pixels
+
+	gs    *graphicsstate.GraphicsState
+	pe    *graphicsstate.PathExtractor
+	paint paintState
+	saved []paintState
+
+	clipPending bool
+	fonts       map[int]*font.GlyphSet // Glyph sets by font object number
+}
+
+func newRenderer(r *reader.Reader, dst *image.RGBA, base model.Matrix) *renderer {
+	gs := graphicsstate.NewGraphicsState()
+	gs.Clip(model.BBox{Width: float64(dst.Bounds().Dx()), Height: float64(dst.Bounds().Dy())})
+	return &renderer{
+		r:     r,
+		dst:   dst,
+		base:  base,
+		gs:    gs,
+		pe:    graphicsstate.NewPathExtractor(gs),
+		paint: paintState{fillSpace: deviceGray, strokeSpace: deviceGray, fillAlpha: 1, strokeAlpha: 1},
+		fonts: make(map[int]*font.GlyphSet),
+	}
+}
+
+// device returns the matrix from user space to pixels
+func (rd *renderer) device() model.Matrix {
+	return rd.gs.CTM.Multiply(rd.base)
+}
+
+// save pushes the graphics state (q operator)
+func (rd *renderer) save() {
+	rd.gs.Save()
+	rd.saved = append(rd.saved, rd.paint)
+}
+
+// restore pops the graphics state (Q operator), ignoring unbalanced Qs
+func (rd *renderer) restore() {
+	if len(rd.saved) == 0 {
+		return
+	}
+	_ = rd.gs.Restore()
+	rd.paint = rd.saved[len(rd.saved)-1]
+	rd.saved = rd.saved[:len(rd.saved)-1]
+}
+
+// run interprets a content stream with the given resources
+func (rd *renderer) run(data []byte, resources core.Dict, depth int) {
+	if depth > maxFormDepth || len(data) == 0 {
+		return
+	}
+	ops, err := contentstream.NewParser(data).Parse()
+	if err != nil && len(ops) == 0 {
+		return
+	}
+
+	// Unbalanced q operators inside the stream are undone at its end
+	level := len(rd.saved)
+	for _, op := range ops {
+		rd.operation(op, resources, depth)
+	}
+	for len(rd.saved) > level {
+		rd.restore()
+	}
+}
+
+// operation runs one content stream operator
+func (rd *renderer) operation(op contentstream.Operation, resources core.Dict, depth int) {
+	n := numbers(op.Operands)
+	switch op.Operator {
+	// Graphics state
+	case "q":
+		rd.save()
+	case "Q":
+		rd.restore()
+	case "cm":
+		if len(n) == 6 {
+			rd.gs.CTM = model.Matrix{n[0], n[1], n[2], n[3], n[4], n[5]}.Multiply(rd.gs.CTM)
+		}
+	case "w":
+		if len(n) == 1 {
+			rd.gs.SetLineWidth(n[0])
+		}
+	case "gs":
+		rd.extGState(op, resources)
+
+	// Color
+	case "g", "rg", "k":
+		rd.paint.fillSpace = deviceSpace(len(n))
+		rd.gs.SetFillColor(n...)
+	case "G", "RG", "K":
+		rd.paint.strokeSpace = deviceSpace(len(n))
+		rd.gs.SetStrokeColor(n...)
+	case "cs", "CS":
+		if len(op.Operands) != 1 {
+			return
+		}
+		cs := rd.colorSpace(op.Operands[0], resources)
+		if op.Operator == "cs" {
+			rd.paint.fillSpace = cs
+			rd.gs.FillColor = cs.initial()
+		} else {
+			rd.paint.strokeSpace = cs
+			rd.gs.StrokeColor = cs.initial()
+		}
+	case "sc", "scn":
+		if c, ok := rd.paint.fillSpace.toRGB(n); ok {
+			rd.gs.FillColor = c
+		}
+	case "SC", "SCN":
+		if c, ok := rd.paint.strokeSpace.toRGB(n); ok {
+			rd.gs.StrokeColor = c
+		}
+
+	// Path construction
+	case "m":
+		if len(n) == 2 {
+			rd.pe.MoveTo(n[0], n[1])
+		}
+	case "l":
+		if len(n) == 2 {
+			rd.pe.LineTo(n[0], n[1])
+		}
+	case "c":
+		if len(n) == 6 {
+			rd.pe.CurveTo(n[0], n[1], n[2], n[3], n[4], n[5])
+		}
+	case "v":
+		if len(n) == 4 {
+			rd.pe.CurveToV(n[0], n[1], n[2], n[3])
+		}
+	case "y":
+		if len(n) == 4 {
+			rd.pe.CurveToY(n[0], n[1], n[2], n[3])
+		}
+	case "h":
+		rd.pe.ClosePath()
+	case "re":
+		if len(n) == 4 {
+			rd.pe.Rectangle(n[0], n[1], n[2], n[3])
+		}
+
+	// Path painting
+	case "S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "n":
+		rd.paintPath(op.Operator)
+	case "W", "W*":
+		rd.clipPending = true
+
+	// Text
+	case "BT":
+		rd.gs.Text.TextMatrix = model.Identity()
+		rd.gs.Text.TextLineMatrix = model.Identity()
+	case "ET":
+	case "Tf":
+		if len(op.Operands) == 2 {
+			if size, ok := number(op.Operands[1]); ok {
+				name, _ := op.Operands[0].(core.Name)
+				rd.gs.SetFont(string(name), size)
+				rd.paint.font = rd.loadFont(string(name), resources)
+			}
+		}
+	case "Tc":
+		if len(n) == 1 {
+			rd.gs.SetCharSpacing(n[0])
+		}
+	case "Tw":
+		if len(n) == 1 {
+			rd.gs.SetWordSpacing(n[0])
+		}
+	case "Tz":
+		if len(n) == 1 {
+			rd.gs.SetHorizontalScaling(n[0])
+		}
+	case "TL":
+		if len(n) == 1 {
+			rd.gs.SetLeading(n[0])
+		}
+	case "Tr":
+		if len(n) == 1 {
+			rd.gs.SetRenderingMode(int(n[0]))
+		}
+	case "Ts":
+		if len(n) == 1 {
+			rd.gs.SetTextRise(n[0])
+		}
+	case "Td":
+		if len(n) == 2 {
+			rd.moveText(n[0], n[1])
+		}
+	case "TD":
+		if len(n) == 2 {
+			rd.gs.SetLeading(-n[1])
+			rd.moveText(n[0], n[1])
+		}
+	case "Tm":
+		if len(n) == 6 {
+			m := model.Matrix{n[0], n[1], n[2], n[3], n[4], n[5]}
+			rd.gs.Text.TextMatrix, rd.gs.Text.TextLineMatrix = m, m
+		}
+	case "T*":
+		rd.moveText(0, -rd.gs.Text.Leading)
+	case "Tj":
+		if len(op.Operands) == 1 {
+			rd.showOperand(op.Operands[0])
+		}
+	case "'":
+		if len(op.Operands) == 1 {
+			rd.moveText(0, -rd.gs.Text.Leading)
+			rd.showOperand(op.Operands[0])
+		}
+	case "\"":
+		if len(op.Operands) == 3 {
+			if aw, ok := number(op.Operands[0]); ok {
+				rd.gs.SetWordSpacing(aw)
+			}
+			if ac, ok := number(op.Operands[1]); ok {
+				rd.gs.SetCharSpacing(ac)
+			}
+			rd.moveText(0, -rd.gs.Text.Leading)
+			rd.showOperand(op.Operands[2])
+		}
+	case "TJ":
+		if len(op.Operands) == 1 {
+			if arr, ok := op.Operands[0].(core.Array); ok {
+				for _, item := range arr {
+					if adj, ok := number(item); ok {
+						rd.advanceText(-adj / 1000 * rd.gs.Text.FontSize * rd.gs.Text.HorizontalScaling / 100)
+					} else {
+						rd.showOperand(item)
+					}
+				}
+			}
+		}
+
+	// XObjects and inline images
+	case "Do":
+		if len(op.Operands) == 1 {
+			if name, ok := op.Operands[0].(core.Name); ok {
+				rd.xObject(string(name), resources, depth)
+			}
+		}
+	case "BI":
+		if img, err := rd.r.InlineImage(op, resources); err == nil {
+			rd.drawImage(img, nil)
+		}
+	}
+}
+
+// extGState applies the line width and constant alpha of a named graphics
+// state parameter dictionary (gs operator)
+func (rd *renderer) extGState(op contentstream.Operation, resources core.Dict) {
+	if len(op.Operands) != 1 {
+		return
+	}
+	name, ok := op.Operands[0].(core.Name)
+	if !ok {
+		return
+	}
+	states, ok := rd.resolveDict(resources.Get("ExtGState"))
+	if !ok {
+		return
+	}
+	state, ok := rd.resolveDict(states.Get(string(name)))
+	if !ok {
+		return
+	}
+	if lw, ok := number(rd.resolve(state.Get("LW"))); ok {
+		rd.gs.SetLineWidth(lw)
+	}
+	if ca, ok := number(rd.resolve(state.Get("ca"))); ok {
+		rd.paint.fillAlpha = clamp01(ca)
+	}
+	if ca, ok := number(rd.resolve(state.Get("CA"))); ok {
+		rd.paint.strokeAlpha = clamp01(ca)
+	}
+}
+
+// xObject draws a named image or form XObject (Do operator)
+func (rd *renderer) xObject(name string, resources core.Dict, depth int) {
+	xobjects, ok := rd.resolveDict(resources.Get("XObject"))
+	if !ok {
+		return
+	}
+	stream, ok := rd.resolve(xobjects.Get(name)).(*core.Stream)
+	if !ok {
+		return
+	}
+
+	switch subtype, _ := rd.resolve(stream.Dict.Get("Subtype")).(core.Name); subtype {
+	case "Image":
+		img, err := rd.r.ImageFromStream(stream)
+		if err != nil {
+			return
+		}
+		var smask *reader.PageImage
+		if s, ok := rd.resolve(stream.Dict.Get("SMask")).(*core.Stream); ok {
+			smask, _ = rd.r.ImageFromStream(s)
+		}
+		rd.drawImage(img, smask)
+
+	case "Form":
+		data, err := stream.Decode()
+		if err != nil {
+			return
+		}
+		formResources, ok := rd.resolveDict(stream.Dict.Get("Resources"))
+		if !ok {
+			formResources = resources
+		}
+
+		rd.save()
+		defer rd.restore()
+		if m := rd.numberArray(stream.Dict.Get("Matrix")); len(m) == 6 {
+			rd.gs.CTM = model.Matrix{m[0], m[1], m[2], m[3], m[4], m[5]}.Multiply(rd.gs.CTM)
+		}
+		if b := rd.numberArray(stream.Dict.Get("BBox")); len(b) == 4 {
+			rd.clipTo(rectPath(b[0], b[1], b[2]-b[0], b[3]-b[1]).transform(rd.device()).bounds())
+		}
+		rd.run(data, formResources, depth+1)
+	}
+}
+
+// clipTo intersects the clipping region with a box in pixels; an empty
+// box clips everything
+func (rd *renderer) clipTo(b box) {
+	if b.empty() {
+		b = box{}
+	}
+	rd.gs.Clip(model.BBox{X: b.x0, Y: b.y0, Width: b.x1 - b.x0, Height: b.y1 - b.y0})
+}
+
+// clipRect returns the clipping region in whole pixels
+func (rd *renderer) clipRect() image.Rectangle {
+	c := rd.gs.ClipBox
+	r := image.Rect(int(math.Floor(c.X)), int(math.Floor(c.Y)), int(math.Ceil(c.X+c.Width)), int(math.Ceil(c.Y+c.Height)))
+	return r.Intersect(rd.dst.Bounds())
+}
+
+func (rd *renderer) resolve(obj core.Object) core.Object {
+	if obj == nil {
+		return nil
+	}
+	resolved, err := rd.r.Resolve(obj)
+	if err != nil {
+		return nil
+	}
+	return resolved
+}
+
+func (rd *renderer) resolveDict(obj core.Object) (core.Dict, bool) {
+	d, ok := rd.resolve(obj).(core.Dict)
+	return d, ok
+}
+
+// numberArray resolves an array of numbers, returning nil if any element
+// is not a number
+func (rd *renderer) numberArray(obj core.Object) []float64 {
+	arr, ok := rd.resolve(obj).(core.Array)
+	if !ok {
+		return nil
+	}
+	out := make([]float64, len(arr))
+	for i, item := range arr {
+		v, ok := number(rd.resolve(item))
+		if !ok {
+			return nil
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// number converts an Int or Real operand to a float64
+func number(obj core.Object) (float64, bool) {
+	switch v := obj.(type) {
+	case core.Int:
+		return float64(v), true
+	case core.Real:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// numbers returns the numeric operands of an operation, in order,
+// skipping any others
+func numbers(operands []core.Object) []float64 {
+	out := make([]float64, 0, len(operands))
+	for _, o := range operands {
+		if v, ok := number(o); ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
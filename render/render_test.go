@@ -0,0 +1,273 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/reader"
+)
+
+// buildPDF assembles a one-page PDF with the given extra page entries,
+// content stream and extra objects, numbered from 5. Helvetica is
+// available as /F1, and object 5, if given, as the XObject /Im1.
+func buildPDF(pageEntries, content string, objects ...string) []byte {
+	bodies := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] %s /Contents 4 0 R "+
+			"/Resources << /Font << /F1 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica >> >> /XObject << /Im1 5 0 R >> >> >>", pageEntries),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	}
+	bodies = append(bodies, objects...)
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(bodies))
+	for i, body := range bodies {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(bodies)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(bodies)+1, xref)
+	return buf.Bytes()
+}
+
+// renderPDF renders the first page of a PDF
+func renderPDF(t *testing.T, data []byte, opts Options) *image.RGBA {
+	t.Helper()
+	r, err := reader.NewReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	img, err := Page(r, 0, opts)
+	if err != nil {
+		t.Fatalf("Page failed: %v", err)
+	}
+	return img
+}
+
+// darkPixels counts the pixels of a region darker than mid-gray
+func darkPixels(img *image.RGBA, r image.Rectangle) int {
+	n := 0
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y < 128 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func expectColor(t *testing.T, img *image.RGBA, x, y int, want color.RGBA) {
+	t.Helper()
+	if got := img.RGBAAt(x, y); got != want {
+		t.Errorf("Expected pixel (%d, %d) to be %v, got %v", x, y, want, got)
+	}
+}
+
+var (
+	white = color.RGBA{255, 255, 255, 255}
+	black = color.RGBA{0, 0, 0, 255}
+	blue  = color.RGBA{0, 0, 255, 255}
+)
+
+func TestPageFill(t *testing.T) {
+	img := renderPDF(t, buildPDF("", "0 0 1 rg 72 72 144 144 re f"), Options{DPI: 72})
+
+	if b := img.Bounds(); b.Dx() != 612 || b.Dy() != 792 {
+		t.Fatalf("Expected a 612x792 image, got %v", b)
+	}
+	expectColor(t, img, 100, 792-100, blue)
+	expectColor(t, img, 10, 10, white)
+	expectColor(t, img, 300, 792-100, white)
+}
+
+func TestPageDPI(t *testing.T) {
+	img := renderPDF(t, buildPDF("", "0 0 1 rg 72 72 144 144 re f"), Options{DPI: 144})
+
+	if b := img.Bounds(); b.Dx() != 1224 || b.Dy() != 1584 {
+		t.Fatalf("Expected a 1224x1584 image, got %v", b)
+	}
+	expectColor(t, img, 200, 1584-200, blue)
+}
+
+func TestPageRotate(t *testing.T) {
+	img := renderPDF(t, buildPDF("/Rotate 90", "0 0 1 rg 0 0 100 50 re f"), Options{DPI: 72})
+
+	if b := img.Bounds(); b.Dx() != 792 || b.Dy() != 612 {
+		t.Fatalf("Expected a 792x612 image, got %v", b)
+	}
+	// Turned clockwise, the page's bottom-left corner is at the top left
+	expectColor(t, img, 25, 90, blue)
+	expectColor(t, img, 90, 25, white)
+}
+
+func TestPageCropBox(t *testing.T) {
+	img := renderPDF(t, buildPDF("/CropBox [72 72 216 216]", "0 0 1 rg 72 72 72 72 re f"), Options{DPI: 72})
+
+	if b := img.Bounds(); b.Dx() != 144 || b.Dy() != 144 {
+		t.Fatalf("Expected a 144x144 image, got %v", b)
+	}
+	expectColor(t, img, 10, 134, blue)
+	expectColor(t, img, 100, 30, white)
+}
+
+func TestPageClip(t *testing.T) {
+	img := renderPDF(t, buildPDF("", "q 72 72 72 72 re W n 0 0 612 792 re f Q"), Options{DPI: 72})
+
+	expectColor(t, img, 100, 792-100, black)
+	expectColor(t, img, 300, 300, white)
+}
+
+func TestPageStroke(t *testing.T) {
+	img := renderPDF(t, buildPDF("", "4 w 72 400 m 540 400 l S"), Options{DPI: 72})
+
+	expectColor(t, img, 300, 792-400, black)
+	expectColor(t, img, 300, 792-410, white)
+	if n := darkPixels(img, image.Rect(0, 792-403, 612, 792-397)); n < 468*4 {
+		t.Errorf("Expected a line at least 468x4 pixels, got %d dark pixels", n)
+	}
+}
+
+func TestPageText(t *testing.T) {
+	img := renderPDF(t, buildPDF("", "BT /F1 48 Tf 72 700 Td (H) Tj ET"), Options{DPI: 72})
+
+	// Helvetica is not embedded, so a substitute draws the glyph
+	if n := darkPixels(img, image.Rect(72, 792-740, 120, 792-700)); n < 100 {
+		t.Errorf("Expected a glyph near the text position, got %d dark pixels", n)
+	}
+	if n := darkPixels(img, image.Rect(200, 0, 612, 792)); n != 0 {
+		t.Errorf("Expected nothing drawn away from the text, got %d dark pixels", n)
+	}
+}
+
+func TestPageInvisibleText(t *testing.T) {
+	img := renderPDF(t, buildPDF("", "BT 3 Tr /F1 48 Tf 72 700 Td (Hidden) Tj ET"), Options{DPI: 72})
+
+	if n := darkPixels(img, img.Bounds()); n != 0 {
+		t.Errorf("Expected invisible text not to be drawn, got %d dark pixels", n)
+	}
+}
+
+func TestPageImage(t *testing.T) {
+	pixels := "\x00\x00\xff\xff\xff\xff" // Blue, white
+	imageObj := fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width 2 /Height 1 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Length %d >>\nstream\n%s\nendstream", len(pixels), pixels)
+	img := renderPDF(t, buildPDF("", "q 200 0 0 100 100 100 cm /Im1 Do Q", imageObj), Options{DPI: 72})
+
+	expectColor(t, img, 120, 792-150, blue)
+	if c := img.RGBAAt(280, 792-150); c.R < 200 {
+		t.Errorf("Expected the image's right half to be white, got %v", c)
+	}
+	expectColor(t, img, 50, 792-150, white)
+}
+
+func TestPageStencilMask(t *testing.T) {
+	mask := "\x40" // 0 1 0 0 ...: paint, no paint
+	maskObj := fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width 2 /Height 1 /ImageMask true /Length %d >>\nstream\n%s\nendstream", len(mask), mask)
+	img := renderPDF(t, buildPDF("", "0 0 1 rg q 200 0 0 100 100 100 cm /Im1 Do Q", maskObj), Options{DPI: 72})
+
+	expectColor(t, img, 120, 792-150, blue)
+	expectColor(t, img, 280, 792-150, white)
+}
+
+func TestPageBackground(t *testing.T) {
+	img := renderPDF(t, buildPDF("", ""), Options{DPI: 36, Background: color.Black})
+
+	expectColor(t, img, 5, 5, black)
+}
+
+func TestPageIndexOutOfRange(t *testing.T) {
+	data := buildPDF("", "")
+	r, err := reader.NewReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	if _, err := Page(r, 1, Options{}); err == nil {
+		t.Error("Expected an error for a missing page")
+	}
+}
+
+func TestRegion(t *testing.T) {
+	data := buildPDF("", "0 0 1 rg 72 72 144 144 re f")
+	r, err := reader.NewReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+
+	img, err := Region(r, 0, model.BBox{X: 144, Y: 180, Width: 144, Height: 72}, Options{DPI: 72})
+	if err != nil {
+		t.Fatalf("Region failed: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 144 || b.Dy() != 72 {
+		t.Fatalf("Expected a 144x72 image, got %v", b)
+	}
+	// The rectangle covers the region's bottom-left quarter
+	expectColor(t, img, 10, 70, blue)
+	expectColor(t, img, 100, 70, white)
+	expectColor(t, img, 10, 2, white)
+
+	// Clipped to the page
+	img, err = Region(r, 0, model.BBox{X: 600, Y: -10, Width: 100, Height: 20}, Options{DPI: 72})
+	if err != nil {
+		t.Fatalf("Region failed: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 12 || b.Dy() != 10 {
+		t.Errorf("Expected a 12x10 image, got %v", b)
+	}
+
+	if _, err := Region(r, 0, model.BBox{X: 700, Y: 0, Width: 10, Height: 10}, Options{DPI: 72}); err == nil {
+		t.Error("Expected an error for a region outside the page")
+	}
+}
+
+func TestRegionRotate(t *testing.T) {
+	data := buildPDF("/Rotate 90", "0 0 1 rg 0 0 100 50 re f")
+	r, err := reader.NewReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+
+	img, err := Region(r, 0, model.BBox{X: 0, Y: 0, Width: 100, Height: 50}, Options{DPI: 72})
+	if err != nil {
+		t.Fatalf("Region failed: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 50 || b.Dy() != 100 {
+		t.Fatalf("Expected a 50x100 image, got %v", b)
+	}
+	expectColor(t, img, 25, 50, blue)
+}
+
+func TestPageMatrix(t *testing.T) {
+	data := buildPDF("/Rotate 90", "0 0 1 rg 0 0 100 50 re f")
+	r, err := reader.NewReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	page, err := r.GetPage(0)
+	if err != nil {
+		t.Fatalf("GetPage failed: %v", err)
+	}
+
+	m, err := PageMatrix(page, Options{DPI: 144})
+	if err != nil {
+		t.Fatalf("PageMatrix failed: %v", err)
+	}
+	img := renderPDF(t, data, Options{DPI: 144})
+
+	// The rectangle's middle lands on a blue pixel of the rendered page
+	p := m.Transform(model.Point{X: 50, Y: 25})
+	if p.X < 0 || p.Y < 0 || p.X >= float64(img.Bounds().Dx()) || p.Y >= float64(img.Bounds().Dy()) {
+		t.Fatalf("Expected (50, 25) to map inside the image, got %v", p)
+	}
+	expectColor(t, img, int(p.X), int(p.Y), blue)
+}
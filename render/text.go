@@ -0,0 +1,156 @@
+package render
+
+import (
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/font"
+	"github.com/tsawler/tabula/model"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/gomonobolditalic"
+	"golang.org/x/image/font/gofont/gomonoitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Text rendering modes (PDF 32000-1:2008, 9.3.6)
+const (
+	modeFill       = 0
+	modeStroke     = 1
+	modeFillStroke = 2
+	modeInvisible  = 3
+	modeClip       = 7
+)
+
+// loadFont returns the glyphs of a named font resource, or nil if the font
+// cannot be drawn. Fonts that are not embedded get a Go font of matching
+// style as a substitute.
+func (rd *renderer) loadFont(name string, resources core.Dict) *font.GlyphSet {
+	fonts, ok := rd.resolveDict(resources.Get("Font"))
+	if !ok {
+		return nil
+	}
+	obj := fonts.Get(name)
+	ref, isRef := obj.(core.IndirectRef)
+	if isRef {
+		if g, ok := rd.fonts[ref.Number]; ok {
+			return g
+		}
+	}
+
+	var g *font.GlyphSet
+	if dict, ok := rd.resolveDict(obj); ok {
+		g, _ = font.NewGlyphSet(dict, rd.r.ResolveReference)
+		if g != nil && !g.Embedded() {
+			_ = g.SetSubstitute(substituteProgram(g.Font()))
+		}
+	}
+	if isRef {
+		rd.fonts[ref.Number] = g
+	}
+	return g
+}
+
+// substituteProgram picks the Go font that best matches a font's style
+func substituteProgram(f *font.Font) []byte {
+	bold, italic := f.IsBold(), f.IsItalic()
+	if f.IsMonospace() {
+		switch {
+		case bold && italic:
+			return gomonobolditalic.TTF
+		case bold:
+			return gomonobold.TTF
+		case italic:
+			return gomonoitalic.TTF
+		}
+		return gomono.TTF
+	}
+	switch {
+	case bold && italic:
+		return gobolditalic.TTF
+	case bold:
+		return gobold.TTF
+	case italic:
+		return goitalic.TTF
+	}
+	return goregular.TTF
+}
+
+// moveText starts a new line offset from the start of the current one (Td
+// operator)
+func (rd *renderer) moveText(tx, ty float64) {
+	t := &rd.gs.Text
+	t.TextLineMatrix = model.Translate(tx, ty).Multiply(t.TextLineMatrix)
+	t.TextMatrix = t.TextLineMatrix
+}
+
+// advanceText moves the text position horizontally by tx, in unscaled text
+// space units
+func (rd *renderer) advanceText(tx float64) {
+	t := &rd.gs.Text
+	t.TextMatrix = model.Translate(tx, 0).Multiply(t.TextMatrix)
+}
+
+// showOperand shows the glyphs of a string operand (Tj, ', " and TJ
+// operators), drawing them unless the rendering mode hides them
+func (rd *renderer) showOperand(obj core.Object) {
+	s, ok := obj.(core.String)
+	if !ok || rd.paint.font == nil {
+		return
+	}
+	t := &rd.gs.Text
+	th := t.HorizontalScaling / 100
+	mode := t.RenderingMode % 8
+	draw := mode != modeInvisible && mode != modeClip
+	device := rd.device()
+
+	for _, g := range rd.paint.font.Glyphs([]byte(s)) {
+		if draw && len(g.Outline) > 0 {
+			// Text space to pixels: font size and horizontal scaling, rise,
+			// then the text matrix and CTM
+			m := model.Matrix{t.FontSize * th, 0, 0, t.FontSize, 0, t.Rise}.Multiply(t.TextMatrix).Multiply(device)
+			rd.drawGlyph(outlinePath(g.Outline).transform(m), mode)
+		}
+		tx := g.Width/1000*t.FontSize + t.CharSpacing
+		if g.Space {
+			tx += t.WordSpacing
+		}
+		rd.advanceText(tx * th)
+	}
+}
+
+// drawGlyph fills or strokes a glyph outline in pixels
+func (rd *renderer) drawGlyph(dp path, mode int) {
+	switch mode % 4 {
+	case modeFill:
+		rd.fill(dp, rd.gs.FillColor, rd.paint.fillAlpha, rd.paint.fillSpace)
+	case modeStroke:
+		rd.stroke(dp, rd.gs.LineWidth, rd.gs.StrokeColor, rd.paint.strokeAlpha, rd.paint.strokeSpace)
+	case modeFillStroke:
+		rd.fill(dp, rd.gs.FillColor, rd.paint.fillAlpha, rd.paint.fillSpace)
+		rd.stroke(dp, rd.gs.LineWidth, rd.gs.StrokeColor, rd.paint.strokeAlpha, rd.paint.strokeSpace)
+	}
+}
+
+// outlinePath converts a glyph outline to a path in text space
+func outlinePath(o font.Outline) path {
+	p := make(path, 0, len(o))
+	for _, s := range o {
+		seg := segment{pts: s.Points}
+		switch s.Op {
+		case font.OutlineMoveTo:
+			seg.kind = segMoveTo
+		case font.OutlineLineTo:
+			seg.kind = segLineTo
+		case font.OutlineQuadTo:
+			seg.kind = segQuadTo
+		case font.OutlineCubeTo:
+			seg.kind = segCubeTo
+		case font.OutlineClose:
+			seg.kind = segClose
+		}
+		p = append(p, seg)
+	}
+	return p
+}
@@ -0,0 +1,117 @@
+package tabula
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/tsawler/tabula/format"
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/render"
+)
+
+// ImageFormat selects how RenderPage and RenderRegion encode their images.
+type ImageFormat int
+
+const (
+	// ImagePNG encodes lossless PNG images. It is the default.
+	ImagePNG ImageFormat = iota
+
+	// ImageJPEG encodes JPEG images, smaller for thumbnails of scans and
+	// photographs.
+	ImageJPEG
+)
+
+// snapshotJPEGQuality is the JPEG quality of rendered snapshots
+const snapshotJPEGQuality = 90
+
+// RenderPage renders a page (1-based) to an image at the given resolution,
+// e.g. 150 for a preview or 36 for a thumbnail, encoded as PNG unless
+// SnapshotFormat selects JPEG. The page is rendered by the built-in renderer
+// (see the render package), upright per its /Rotate. Only PDFs can be
+// rendered. This is a terminal operation that closes the underlying reader.
+//
+// Example:
+//
+//	thumb, err := tabula.Open("report.pdf").SnapshotFormat(tabula.ImageJPEG).RenderPage(1, 36)
+func (e *Extractor) RenderPage(pageNum int, dpi float64) ([]byte, error) {
+	return e.renderSnapshot(pageNum, dpi, func(i int, opts render.Options) (*image.RGBA, error) {
+		return render.Page(e.reader, i, opts)
+	})
+}
+
+// RenderRegion renders the part of a page (1-based) inside a bounding box,
+// in PDF points with the origin at the bottom left, to an image at the given
+// resolution. The box is typically the BBox of a chunk or layout element, so
+// a cited passage can be shown as it appears on the page. pageNum is the
+// page's number in the file: a chunk's SourcePageStart, not its PageStart,
+// which counts only the selected pages. The image is encoded as PNG unless
+// SnapshotFormat selects JPEG. Only PDFs can be rendered. This is a
+// terminal operation that closes the underlying reader.
+//
+// Example:
+//
+//	for _, c := range chunks.Chunks {
+//	    if c.Metadata.BBox != nil {
+//	        snap, err := tabula.Open("report.pdf").RenderRegion(c.Metadata.SourcePageStart, *c.Metadata.BBox, 150)
+//	        if err == nil {
+//	            os.WriteFile(c.ID+".png", snap, 0o644)
+//	        }
+//	    }
+//	}
+func (e *Extractor) RenderRegion(pageNum int, bbox model.BBox, dpi float64) ([]byte, error) {
+	return e.renderSnapshot(pageNum, dpi, func(i int, opts render.Options) (*image.RGBA, error) {
+		return render.Region(e.reader, i, bbox, opts)
+	})
+}
+
+// renderSnapshot opens the reader, renders a page (1-based) with fn and
+// encodes the result
+func (e *Extractor) renderSnapshot(pageNum int, dpi float64, fn func(int, render.Options) (*image.RGBA, error)) ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if err := e.ensureReader(); err != nil {
+		return nil, err
+	}
+	defer e.Close()
+
+	if e.format != format.PDF {
+		return nil, fmt.Errorf("rendering is not supported for %s", e.format)
+	}
+	if dpi <= 0 {
+		return nil, fmt.Errorf("invalid resolution %v DPI", dpi)
+	}
+	pageCount, err := e.reader.PageCount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page count: %w", err)
+	}
+	if pageNum < 1 || pageNum > pageCount {
+		return nil, fmt.Errorf("page %d out of range (1-%d)", pageNum, pageCount)
+	}
+
+	img, err := fn(pageNum-1, render.Options{DPI: dpi})
+	if err != nil {
+		return nil, fmt.Errorf("page %d: %w", pageNum, err)
+	}
+	return encodeImage(img, e.options.snapshotFormat)
+}
+
+// encodeImage encodes an image as PNG or JPEG
+func encodeImage(img image.Image, f ImageFormat) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch f {
+	case ImageJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: snapshotJPEGQuality})
+	default:
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
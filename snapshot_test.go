@@ -0,0 +1,115 @@
+package tabula
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+)
+
+func TestRenderPage(t *testing.T) {
+	data := buildTextPDF("BT /F1 24 Tf 72 700 Td (Hello) Tj ET")
+
+	out, err := FromBytes(data, "").RenderPage(1, 72)
+	if err != nil {
+		t.Fatalf("RenderPage() error: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 612 || b.Dy() != 792 {
+		t.Errorf("got %dx%d, want 612x792", b.Dx(), b.Dy())
+	}
+
+	out, err = FromBytes(data, "").SnapshotFormat(ImageJPEG).RenderPage(1, 36)
+	if err != nil {
+		t.Fatalf("RenderPage() error: %v", err)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decoding JPEG: %v", err)
+	}
+	if cfg.Width != 306 || cfg.Height != 396 {
+		t.Errorf("got %dx%d, want 306x396", cfg.Width, cfg.Height)
+	}
+}
+
+func TestRenderPageErrors(t *testing.T) {
+	data := buildTextPDF("BT /F1 24 Tf 72 700 Td (Hello) Tj ET")
+
+	if _, err := FromBytes(data, "").RenderPage(2, 72); err == nil {
+		t.Error("got nil error for a page out of range")
+	}
+	if _, err := FromBytes(data, "").RenderPage(1, 0); err == nil {
+		t.Error("got nil error for a zero DPI")
+	}
+	if _, err := FromHTMLString("<p>Hello</p>").RenderPage(1, 72); err == nil {
+		t.Error("got nil error for an HTML document")
+	}
+}
+
+func TestRenderRegionFromChunkBBox(t *testing.T) {
+	data := buildTextPDF("BT /F1 24 Tf 72 700 Td (Citation) Tj ET")
+
+	chunks, _, err := FromBytes(data, "").Chunks()
+	if err != nil {
+		t.Fatalf("Chunks() error: %v", err)
+	}
+	var bbox *model.BBox
+	for _, c := range chunks.Chunks {
+		if c.Metadata.BBox != nil {
+			bbox = c.Metadata.BBox
+			break
+		}
+	}
+	if bbox == nil {
+		t.Fatal("no chunk has a bounding box")
+	}
+
+	out, err := FromBytes(data, "").RenderRegion(1, *bbox, 144)
+	if err != nil {
+		t.Fatalf("RenderRegion() error: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	b := img.Bounds()
+	if w, h := b.Dx(), b.Dy(); w < int(bbox.Width*2) || w > int(bbox.Width*2)+2 || h < int(bbox.Height*2) || h > int(bbox.Height*2)+2 {
+		t.Errorf("got %dx%d, want about %.0fx%.0f", w, h, bbox.Width*2, bbox.Height*2)
+	}
+
+	// The snapshot shows the chunk's text
+	dark := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y < 128 {
+				dark++
+			}
+		}
+	}
+	if dark == 0 {
+		t.Error("got a blank snapshot, want the chunk's text")
+	}
+
+	if _, err := FromBytes(data, "").RenderRegion(1, model.BBox{X: 1000, Y: 1000, Width: 10, Height: 10}, 72); err == nil {
+		t.Error("got nil error for a region outside the page")
+	}
+}
+
+func TestEncodeImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	out, err := encodeImage(img, ImagePNG)
+	if err != nil || !bytes.HasPrefix(out, []byte("\x89PNG")) {
+		t.Errorf("got %q, %v, want a PNG", out[:4], err)
+	}
+	out, err = encodeImage(img, ImageJPEG)
+	if err != nil || !bytes.HasPrefix(out, []byte{0xFF, 0xD8}) {
+		t.Errorf("got %q, %v, want a JPEG", out[:2], err)
+	}
+}
@@ -0,0 +1,156 @@
+package tabula
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tsawler/tabula/format"
+)
+
+// buildTextPDF assembles a one-page PDF whose content stream is content,
+// with Helvetica available as /F1.
+func buildTextPDF(content string) []byte {
+	return buildPagesPDF(content)
+}
+
+// buildPagesPDF assembles a PDF with one page per content stream, each with
+// Helvetica available as /F1.
+func buildPagesPDF(contents ...string) []byte {
+	// Objects: 1 catalog, 2 pages, 3 font, then a page and its content
+	// stream for each entry.
+	kids := make([]string, len(contents))
+	for i := range contents {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	bodies := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(contents)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+	for i, content := range contents {
+		bodies = append(bodies,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents %d 0 R /Resources << /Font << /F1 3 0 R >> >> >>", 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+	return buildObjectsPDF(bodies...)
+}
+
+// buildObjectsPDF assembles a PDF from object bodies numbered from 1, with
+// object 1 as the catalog.
+func buildObjectsPDF(bodies ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(bodies))
+	for i, body := range bodies {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(bodies)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(bodies)+1, xref)
+	return buf.Bytes()
+}
+
+func TestFromBytesPDF(t *testing.T) {
+	data := buildTextPDF("BT /F1 12 Tf 72 720 Td (Hello from memory) Tj ET")
+
+	ext := FromBytes(data, "")
+	if ext.format != format.PDF {
+		t.Fatalf("format = %s, want PDF", ext.format)
+	}
+
+	text, _, err := ext.Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if !strings.Contains(text, "Hello from memory") {
+		t.Errorf("Text() = %q, want it to contain %q", text, "Hello from memory")
+	}
+}
+
+func TestFromBytesDOCX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.docx")
+	if err := createMinimalDOCX(path, "Hello from memory"); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sniffing wins over a misleading hint.
+	ext := FromBytes(data, "download.pdf")
+	if ext.format != format.DOCX {
+		t.Fatalf("format = %s, want DOCX", ext.format)
+	}
+
+	text, _, err := ext.Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if !strings.Contains(text, "Hello from memory") {
+		t.Errorf("Text() = %q, want it to contain %q", text, "Hello from memory")
+	}
+}
+
+func TestFromReaderAtHTML(t *testing.T) {
+	html := "<html><body><h1>Title</h1><p>Hello from memory</p></body></html>"
+	r := strings.NewReader(html)
+
+	text, _, err := FromReaderAt(r, int64(len(html)), "").Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if !strings.Contains(text, "Hello from memory") {
+		t.Errorf("Text() = %q, want it to contain %q", text, "Hello from memory")
+	}
+}
+
+func TestFromBytesReusable(t *testing.T) {
+	ext := FromBytes(buildTextPDF("BT /F1 12 Tf 72 720 Td (Again) Tj ET"), "")
+
+	// Terminal operations close the reader; the in-memory source reopens.
+	for i := 0; i < 2; i++ {
+		text, _, err := ext.Text()
+		if err != nil {
+			t.Fatalf("Text() call %d error: %v", i+1, err)
+		}
+		if !strings.Contains(text, "Again") {
+			t.Errorf("Text() call %d = %q", i+1, text)
+		}
+	}
+}
+
+func TestDetectSourceFormatHint(t *testing.T) {
+	// Plain text has no magic bytes, so the hint decides.
+	data := []byte("just some bytes")
+	r := bytes.NewReader(data)
+
+	tests := []struct {
+		hint string
+		want format.Format
+	}{
+		{"notes.html", format.HTML},
+		{".docx", format.DOCX},
+		{"xlsx", format.XLSX},
+		{"PDF", format.PDF},
+		{"", format.Unknown},
+	}
+	for _, tt := range tests {
+		if got := detectSourceFormat(r, int64(len(data)), tt.hint); got != tt.want {
+			t.Errorf("detectSourceFormat(hint %q) = %s, want %s", tt.hint, got, tt.want)
+		}
+	}
+
+	if _, _, err := FromBytes(data, "").Text(); err == nil {
+		t.Error("expected error for unrecognized content without a hint")
+	}
+}
@@ -0,0 +1,250 @@
+package tabula
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tsawler/tabula/format"
+	"github.com/tsawler/tabula/layout"
+	"github.com/tsawler/tabula/model"
+)
+
+// headerFooterSampleSize caps the number of pages EachPage reads up front for
+// header/footer detection. Documents with more pages are sampled evenly, so
+// memory stays bounded however long the document is.
+const headerFooterSampleSize = 32
+
+// PageResult is a single page delivered by EachPage.
+type PageResult struct {
+	// Number is the 1-based page number. For non-PDF formats it is the
+	// slide, sheet, or chapter number.
+	Number int
+
+	// Text is the page's plain text.
+	Text string
+
+	// Page holds the page's layout elements (headings, paragraphs, lists,
+	// and tables).
+	Page *model.Page
+
+	// Warnings lists the warnings raised while processing this page.
+	// Document-level warnings, such as a format mismatch, are reported
+	// with the first page.
+	Warnings []Warning
+}
+
+// EachPage extracts the document one page at a time, calling fn for each page
+// as soon as it has been processed. Only the current page is held in memory,
+// which makes it suitable for very large PDFs and for emitting results before
+// the whole document has been read.
+//
+// If fn returns an error, iteration stops and EachPage returns that error.
+// Cancellation set with WithContext is checked between pages.
+//
+// For PDFs, header/footer exclusion runs detection over an evenly spaced
+// sample of at most 32 pages and applies the detected regions to every page.
+// Scanned pages fall back to OCR one page at a time. Other formats are parsed
+// in one step and their pages are then delivered in order.
+//
+// This is a terminal operation that closes the underlying reader.
+//
+// Example:
+//
+//	err := tabula.Open("huge.pdf").
+//	    ExcludeHeadersAndFooters().
+//	    EachPage(func(p tabula.PageResult) error {
+//	        return index.Add(p.Number, p.Text)
+//	    })
+func (e *Extractor) EachPage(fn func(PageResult) error) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	if err := e.ensureReader(); err != nil {
+		return err
+	}
+	defer e.Close()
+
+	if e.format != format.PDF {
+		return e.eachDocumentPage(fn)
+	}
+
+	pageIndices, err := e.resolvePages()
+	if err != nil {
+		return err
+	}
+
+	// Detect headers/footers from a bounded sample of pages
+	var headerFooterResult *layout.HeaderFooterResult
+	var sampled bool
+	if e.options.excludeHeaders || e.options.excludeFooters {
+		headerFooterResult, sampled, err = e.sampleHeaderFooter()
+		if err != nil && e.ctxErr() != nil {
+			return err
+		}
+	}
+
+	analyzers := newPageAnalyzers()
+	analyzers.tagged = e.loadTaggedContent()
+	labels := e.definedPageLabels()
+
+	for i, pageNum := range pageIndices {
+		if err := e.ctxErr(); err != nil {
+			return err
+		}
+		page, err := e.reader.GetPage(pageNum)
+		if err != nil {
+			return fmt.Errorf("page %d: %w", pageNum+1, err)
+		}
+
+		fragments, err := e.pageFragments(page, pageNum)
+		if err != nil {
+			return fmt.Errorf("page %d: %w", pageNum+1, err)
+		}
+
+		// Check for messy PDF traits on the first page processed
+		if i == 0 {
+			e.checkMessyPDF(fragments)
+		}
+
+		width, _ := page.Width()
+		height, _ := page.Height()
+
+		// Filter headers/footers if requested
+		if headerFooterResult != nil {
+			if sampled {
+				fragments = headerFooterResult.FilterFragmentsAnyPage(fragments, height)
+			} else {
+				fragments = headerFooterResult.FilterFragments(pageNum, fragments, height)
+			}
+		}
+
+		modelPage := model.NewPage(width, height)
+		modelPage.Number = pageNum + 1
+		modelPage.SourceNumber = pageNum + 1
+		modelPage.PageLabel = pageLabel(labels, pageNum)
+		e.analyzePDFPage(analyzers, modelPage, page, fragments)
+
+		pageText := e.nativePageText(fragments, page)
+
+		// Sparse or no native text: OCR this page on its own
+		if e.shouldTryOCR(fragments) {
+			if prepared := e.prepareOCRImages(page, pageNum+1); len(prepared) > 0 {
+				results := e.runOCRJobs([]ocrJob{{index: 0, pageNum: pageNum + 1, images: prepared}})
+				if err := e.ctxErr(); err != nil {
+					return err
+				}
+				if strings.TrimSpace(results[0].text) != "" {
+					pageText = mergeNativeAndOCR(fragments, results[0].text)
+					e.setOCRContent(analyzers, modelPage, page, fragments, results[0])
+					e.warnings = append(e.warnings, ocrWarnings(pageNum+1, results[0])...)
+				}
+			}
+		}
+
+		if err := fn(PageResult{
+			Number:   pageNum + 1,
+			Text:     pageText,
+			Page:     modelPage,
+			Warnings: e.takeWarnings(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eachDocumentPage implements EachPage for formats that are parsed in one step
+// by delivering the pages of the parsed document.
+func (e *Extractor) eachDocumentPage(fn func(PageResult) error) error {
+	doc, _, err := e.Document()
+	if err != nil {
+		return err
+	}
+
+	for _, page := range doc.Pages {
+		if err := e.ctxErr(); err != nil {
+			return err
+		}
+		if err := fn(PageResult{
+			Number:   page.Number,
+			Text:     strings.TrimRight(page.ExtractText(), "\n"),
+			Page:     page,
+			Warnings: e.takeWarnings(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// takeWarnings returns the warnings accumulated since the last call and
+// clears them.
+func (e *Extractor) takeWarnings() []Warning {
+	warnings := e.warnings
+	e.warnings = nil
+	return warnings
+}
+
+// sampleHeaderFooter runs header/footer detection over at most
+// headerFooterSampleSize pages spread evenly across the document. sampled
+// reports whether only part of the document was read, in which case the
+// result's page indices do not cover every page.
+func (e *Extractor) sampleHeaderFooter() (result *layout.HeaderFooterResult, sampled bool, err error) {
+	pageCount, err := e.reader.PageCount()
+	if err != nil {
+		return nil, false, err
+	}
+
+	indices := sampleIndices(pageCount, headerFooterSampleSize)
+	samples := make([]extractedPage, 0, len(indices))
+	for _, i := range indices {
+		if err := e.ctxErr(); err != nil {
+			return nil, false, err
+		}
+		page, err := e.reader.GetPage(i)
+		if err != nil {
+			continue // Skip pages that can't be read
+		}
+
+		fragments, _, err := e.filterPageFragments(page, i)
+		if err != nil {
+			continue // Skip pages that can't be extracted
+		}
+
+		samples = append(samples, extractedPage{
+			index:     i,
+			fragments: fragments,
+			page:      page,
+		})
+	}
+
+	if len(samples) == 0 {
+		return nil, false, nil
+	}
+	return e.detectHeaderFooter(samples), len(indices) < pageCount, nil
+}
+
+// sampleIndices returns up to n page indices spread evenly over [0, count),
+// always including the first and last page. All indices are returned when
+// count <= n.
+func sampleIndices(count, n int) []int {
+	if count <= n {
+		indices := make([]int, count)
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+	if n < 2 {
+		return []int{0}
+	}
+
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i * (count - 1) / (n - 1)
+	}
+	return indices
+}
@@ -0,0 +1,153 @@
+package tabula
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEachPage(t *testing.T) {
+	data := buildPagesPDF(
+		"BT /F1 12 Tf 72 720 Td (First page) Tj ET",
+		"BT /F1 12 Tf 72 720 Td (Second page) Tj ET",
+		"BT /F1 12 Tf 72 720 Td (Third page) Tj ET",
+	)
+
+	var numbers []int
+	var texts []string
+	err := FromBytes(data, "").EachPage(func(p PageResult) error {
+		numbers = append(numbers, p.Number)
+		texts = append(texts, p.Text)
+		if p.Page == nil || p.Page.Number != p.Number {
+			t.Errorf("page %d: model page = %+v, want Number %d", p.Number, p.Page, p.Number)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachPage() error: %v", err)
+	}
+
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(numbers, want) {
+		t.Errorf("page numbers = %v, want %v", numbers, want)
+	}
+	for i, want := range []string{"First page", "Second page", "Third page"} {
+		if !strings.Contains(texts[i], want) {
+			t.Errorf("page %d text = %q, want it to contain %q", i+1, texts[i], want)
+		}
+	}
+}
+
+func TestEachPageSelection(t *testing.T) {
+	data := buildPagesPDF(
+		"BT /F1 12 Tf 72 720 Td (First page) Tj ET",
+		"BT /F1 12 Tf 72 720 Td (Second page) Tj ET",
+	)
+
+	var numbers []int
+	err := FromBytes(data, "").Pages(2).EachPage(func(p PageResult) error {
+		numbers = append(numbers, p.Number)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachPage() error: %v", err)
+	}
+	if want := []int{2}; !reflect.DeepEqual(numbers, want) {
+		t.Errorf("page numbers = %v, want %v", numbers, want)
+	}
+}
+
+func TestEachPageStop(t *testing.T) {
+	data := buildPagesPDF(
+		"BT /F1 12 Tf 72 720 Td (One) Tj ET",
+		"BT /F1 12 Tf 72 720 Td (Two) Tj ET",
+		"BT /F1 12 Tf 72 720 Td (Three) Tj ET",
+	)
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := FromBytes(data, "").EachPage(func(p PageResult) error {
+		calls++
+		if p.Number == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("EachPage() error = %v, want %v", err, errStop)
+	}
+	if calls != 2 {
+		t.Errorf("callback called %d times, want 2", calls)
+	}
+}
+
+func TestEachPageHeadersSampled(t *testing.T) {
+	// More pages than the sample, so most pages are filtered using regions
+	// detected on other pages.
+	const pageCount = headerFooterSampleSize + 9
+	contents := make([]string, pageCount)
+	for i := range contents {
+		contents[i] = fmt.Sprintf("BT /F1 10 Tf 72 760 Td (ACME Quarterly Report) Tj ET "+
+			"BT /F1 12 Tf 72 400 Td (Body text of page %d) Tj ET", i+1)
+	}
+	data := buildPagesPDF(contents...)
+
+	seen := 0
+	err := FromBytes(data, "").ExcludeHeaders().EachPage(func(p PageResult) error {
+		seen++
+		if strings.Contains(p.Text, "ACME Quarterly Report") {
+			t.Errorf("page %d: header not removed: %q", p.Number, p.Text)
+		}
+		if want := fmt.Sprintf("Body text of page %d", p.Number); !strings.Contains(p.Text, want) {
+			t.Errorf("page %d text = %q, want it to contain %q", p.Number, p.Text, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachPage() error: %v", err)
+	}
+	if seen != pageCount {
+		t.Errorf("saw %d pages, want %d", seen, pageCount)
+	}
+}
+
+func TestEachPageDOCX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.docx")
+	if err := createMinimalDOCX(path, "Hello pages"); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	var texts []string
+	err := Open(path).EachPage(func(p PageResult) error {
+		texts = append(texts, p.Text)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachPage() error: %v", err)
+	}
+	if len(texts) == 0 || !strings.Contains(strings.Join(texts, "\n"), "Hello pages") {
+		t.Errorf("page texts = %q, want them to contain %q", texts, "Hello pages")
+	}
+}
+
+func TestSampleIndices(t *testing.T) {
+	tests := []struct {
+		count, n int
+		want     []int
+	}{
+		{0, 4, []int{}},
+		{3, 4, []int{0, 1, 2}},
+		{4, 4, []int{0, 1, 2, 3}},
+		{10, 4, []int{0, 3, 6, 9}},
+		{100, 5, []int{0, 24, 49, 74, 99}},
+	}
+
+	for _, tt := range tests {
+		got := sampleIndices(tt.count, tt.n)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sampleIndices(%d, %d) = %v, want %v", tt.count, tt.n, got, tt.want)
+		}
+	}
+}
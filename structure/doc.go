@@ -0,0 +1,30 @@
+// Package structure parses the logical structure tree of tagged PDFs.
+//
+// Tagged PDFs (PDF 32000-1:2008, 14.7 and 14.8) describe their content as a
+// tree of structure elements rooted at the catalog's /StructTreeRoot:
+// headings (H1-H6), paragraphs (P), lists (L, LI, Lbl, LBody), tables (Table,
+// TR, TH, TD), figures and so on. Leaf elements refer to page content through
+// marked-content IDs (MCIDs), which the text package records on each
+// TextFragment from the content stream's BDC operators.
+//
+// # Parsing
+//
+// [Parse] reads the tree from the document catalog:
+//
+//	tree, err := structure.Parse(catalog, resolver, pageIndex)
+//	if tree == nil {
+//	    // Not a tagged PDF
+//	}
+//
+// Each [Element] carries its structure type, mapped to a standard type
+// through the tree's /RoleMap, its /Alt, /ActualText and /E (expansion)
+// text, and its kids in logical order: child elements and [Kid] references
+// to marked content on a page. Object references (OBJR) to annotations and
+// marked content in Form XObject streams are not included.
+//
+// # Reading Order
+//
+// The order of an element's kids is the document's logical reading order,
+// which for multi-column layouts, sidebars and tables is usually more
+// reliable than any order derived from positions on the page.
+package structure
@@ -0,0 +1,309 @@
+package structure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/font"
+)
+
+// maxDepth limits how deep the structure tree is followed
+const maxDepth = 256
+
+// Resolver resolves indirect references
+type Resolver func(core.IndirectRef) (core.Object, error)
+
+// PageIndex returns the 0-based index of the page with the given reference
+type PageIndex func(core.IndirectRef) (int, bool)
+
+// Tree is the logical structure tree of a tagged PDF
+type Tree struct {
+	Root    []*Element        // Top-level elements, in logical order
+	RoleMap map[string]string // Custom structure types to standard types
+}
+
+// Element is a structure element
+type Element struct {
+	Type       string // Standard structure type, after role mapping (e.g. "H1", "P", "Table")
+	RawType    string // Structure type as written in the document
+	Title      string // /T
+	Alt        string // Alternate description, for figures and formulas
+	ActualText string // Replacement text for the element's content
+	Expansion  string // /E, the expansion of an abbreviation
+	Lang       string
+	Page       int // 0-based page index of the element's content, or -1 if unknown
+
+	Kids   []Kid
+	Parent *Element
+
+	attributes core.Dict
+}
+
+// Kid is an element's child: another element, or marked content on a page
+type Kid struct {
+	Element *Element // Child element, or nil for marked content
+	MCID    int      // Marked-content ID, when Element is nil
+	Page    int      // 0-based page index of the marked content, or -1 if unknown
+}
+
+// Attribute returns a standard attribute from the element's /A entry, such
+// as RowSpan, ColSpan, ListNumbering or BBox, or nil if it is not set
+func (el *Element) Attribute(name string) core.Object {
+	if el.attributes == nil {
+		return nil
+	}
+	return el.attributes.Get(name)
+}
+
+// IsTagged reports whether a document catalog has a structure tree
+func IsTagged(catalog core.Dict) bool {
+	return catalog.Get("StructTreeRoot") != nil
+}
+
+// Parse reads the structure tree from a document catalog. It returns nil and
+// no error if the document is not tagged. pageIndex maps the /Pg page
+// references of elements and marked content to page indexes.
+func Parse(catalog core.Dict, resolver Resolver, pageIndex PageIndex) (*Tree, error) {
+	rootObj := catalog.Get("StructTreeRoot")
+	if rootObj == nil {
+		return nil, nil
+	}
+
+	p := &parser{resolver: resolver, pageIndex: pageIndex, visited: make(map[int]bool)}
+
+	root, ok := p.resolve(rootObj).(core.Dict)
+	if !ok {
+		return nil, fmt.Errorf("StructTreeRoot is not a dictionary")
+	}
+
+	tree := &Tree{RoleMap: make(map[string]string)}
+	if roleMap, ok := p.resolve(root.Get("RoleMap")).(core.Dict); ok {
+		for name, target := range roleMap {
+			if n, ok := p.resolve(target).(core.Name); ok {
+				tree.RoleMap[name] = string(n)
+			}
+		}
+	}
+	p.roleMap = tree.RoleMap
+
+	for _, kid := range p.kids(root.Get("K")) {
+		if el := p.element(kid, nil, -1, 0); el != nil {
+			tree.Root = append(tree.Root, el)
+		}
+	}
+
+	return tree, nil
+}
+
+// Walk calls fn for each element in logical order, depth first. Returning
+// false from fn skips the element's descendants.
+func (t *Tree) Walk(fn func(*Element) bool) {
+	var walk func(elements []*Element)
+	walk = func(elements []*Element) {
+		for _, el := range elements {
+			if !fn(el) {
+				continue
+			}
+			var children []*Element
+			for _, kid := range el.Kids {
+				if kid.Element != nil {
+					children = append(children, kid.Element)
+				}
+			}
+			walk(children)
+		}
+	}
+	walk(t.Root)
+}
+
+// parser holds the state for a single Parse call
+type parser struct {
+	resolver  Resolver
+	pageIndex PageIndex
+	roleMap   map[string]string
+	visited   map[int]bool // Object numbers already parsed, to break cycles
+}
+
+// resolve follows an indirect reference, returning nil if it cannot be resolved
+func (p *parser) resolve(obj core.Object) core.Object {
+	if ref, ok := obj.(core.IndirectRef); ok && p.resolver != nil {
+		resolved, err := p.resolver(ref)
+		if err != nil {
+			return nil
+		}
+		return resolved
+	}
+	return obj
+}
+
+// kids returns a /K entry as a list, which may be a single object or an array
+func (p *parser) kids(k core.Object) []core.Object {
+	switch v := p.resolve(k).(type) {
+	case nil:
+		return nil
+	case core.Array:
+		return v
+	default:
+		return []core.Object{k}
+	}
+}
+
+// page returns the page index of a /Pg entry, or def if there is none
+func (p *parser) page(pg core.Object, def int) int {
+	ref, ok := pg.(core.IndirectRef)
+	if !ok || p.pageIndex == nil {
+		return def
+	}
+	if idx, ok := p.pageIndex(ref); ok {
+		return idx
+	}
+	return def
+}
+
+// element parses a structure element dictionary, which may be an indirect
+// reference. It returns nil for anything that is not a structure element.
+func (p *parser) element(obj core.Object, parent *Element, page, depth int) *Element {
+	if depth > maxDepth {
+		return nil
+	}
+	if ref, ok := obj.(core.IndirectRef); ok {
+		if p.visited[ref.Number] {
+			return nil
+		}
+		p.visited[ref.Number] = true
+	}
+
+	dict, ok := p.resolve(obj).(core.Dict)
+	if !ok {
+		return nil
+	}
+	s, ok := dict.Get("S").(core.Name)
+	if !ok {
+		return nil
+	}
+
+	el := &Element{
+		RawType:    string(s),
+		Type:       p.standardType(string(s)),
+		Title:      p.text(dict.Get("T")),
+		Alt:        p.text(dict.Get("Alt")),
+		ActualText: p.text(dict.Get("ActualText")),
+		Expansion:  p.text(dict.Get("E")),
+		Lang:       p.text(dict.Get("Lang")),
+		Page:       p.page(dict.Get("Pg"), page),
+		Parent:     parent,
+		attributes: p.attributes(dict.Get("A")),
+	}
+
+	for _, kidObj := range p.kids(dict.Get("K")) {
+		switch kid := p.resolve(kidObj).(type) {
+		case core.Int:
+			el.Kids = append(el.Kids, Kid{MCID: int(kid), Page: el.Page})
+		case core.Dict:
+			typeName, _ := kid.Get("Type").(core.Name)
+			switch {
+			case typeName == "OBJR":
+				continue // Annotations and XObjects
+			case typeName == "MCR" || kid.Get("S") == nil:
+				if kid.Get("Stm") != nil {
+					continue // Marked content in a Form XObject stream
+				}
+				if mcid, ok := kid.Get("MCID").(core.Int); ok {
+					el.Kids = append(el.Kids, Kid{MCID: int(mcid), Page: p.page(kid.Get("Pg"), el.Page)})
+				}
+			default:
+				if child := p.element(kidObj, el, el.Page, depth+1); child != nil {
+					el.Kids = append(el.Kids, Kid{Element: child, Page: child.Page})
+				}
+			}
+		}
+	}
+
+	return el
+}
+
+// standardType maps a structure type through the role map until it reaches
+// a standard type
+func (p *parser) standardType(s string) string {
+	for i := 0; i < 16 && !standardTypes[s]; i++ {
+		mapped, ok := p.roleMap[s]
+		if !ok || mapped == s {
+			break
+		}
+		s = mapped
+	}
+	return s
+}
+
+// text decodes a text string entry
+func (p *parser) text(obj core.Object) string {
+	if s, ok := p.resolve(obj).(core.String); ok {
+		return strings.TrimSpace(font.DecodeTextString([]byte(s)))
+	}
+	return ""
+}
+
+// attributes merges an element's attribute objects. /A is a dictionary or an
+// array of dictionaries, each optionally followed by a revision number; the
+// first occurrence of an attribute wins.
+func (p *parser) attributes(a core.Object) core.Dict {
+	var dicts []core.Dict
+	switch v := p.resolve(a).(type) {
+	case core.Dict:
+		dicts = append(dicts, v)
+	case core.Array:
+		for _, item := range v {
+			if d, ok := p.resolve(item).(core.Dict); ok {
+				dicts = append(dicts, d)
+			}
+		}
+	}
+	if len(dicts) == 0 {
+		return nil
+	}
+
+	merged := make(core.Dict)
+	for _, d := range dicts {
+		for k, v := range d {
+			if k == "O" {
+				continue // Attribute owner
+			}
+			if _, exists := merged[k]; !exists {
+				merged[k] = p.resolve(v)
+			}
+		}
+	}
+	return merged
+}
+
+// standardTypes are the standard structure types of PDF 1.7 and PDF 2.0
+var standardTypes = map[string]bool{
+	// Grouping
+	"Document": true, "DocumentFragment": true, "Part": true, "Art": true,
+	"Sect": true, "Div": true, "BlockQuote": true, "Caption": true,
+	"TOC": true, "TOCI": true, "Index": true, "NonStruct": true,
+	"Private": true, "Aside": true,
+
+	// Block-level
+	"P": true, "H": true, "H1": true, "H2": true, "H3": true, "H4": true,
+	"H5": true, "H6": true, "Title": true, "FENote": true,
+
+	// Lists
+	"L": true, "LI": true, "Lbl": true, "LBody": true,
+
+	// Tables
+	"Table": true, "TR": true, "TH": true, "TD": true, "THead": true,
+	"TBody": true, "TFoot": true,
+
+	// Inline
+	"Span": true, "Quote": true, "Note": true, "Reference": true,
+	"BibEntry": true, "Code": true, "Link": true, "Annot": true,
+	"Ruby": true, "RB": true, "RT": true, "RP": true, "Warichu": true,
+	"WT": true, "WP": true, "Em": true, "Strong": true, "Sub": true,
+
+	// Illustrations
+	"Figure": true, "Formula": true, "Form": true,
+
+	"Artifact": true,
+}
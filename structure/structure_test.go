@@ -0,0 +1,164 @@
+package structure
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// testDoc builds a resolver and page index over numbered objects; objects
+// 100 and 101 are pages 0 and 1
+func testDoc(objects map[int]core.Object) (Resolver, PageIndex) {
+	resolver := func(ref core.IndirectRef) (core.Object, error) {
+		if obj, ok := objects[ref.Number]; ok {
+			return obj, nil
+		}
+		return nil, fmt.Errorf("object %d not found", ref.Number)
+	}
+	pageIndex := func(ref core.IndirectRef) (int, bool) {
+		switch ref.Number {
+		case 100:
+			return 0, true
+		case 101:
+			return 1, true
+		}
+		return 0, false
+	}
+	return resolver, pageIndex
+}
+
+func TestParseNotTagged(t *testing.T) {
+	tree, err := Parse(core.Dict{"Type": core.Name("Catalog")}, nil, nil)
+	if err != nil || tree != nil {
+		t.Errorf("Expected nil tree and no error, got %v, %v", tree, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	page0 := core.IndirectRef{Number: 100}
+	page1 := core.IndirectRef{Number: 101}
+
+	objects := map[int]core.Object{
+		1: core.Dict{
+			"Type":    core.Name("StructTreeRoot"),
+			"K":       core.IndirectRef{Number: 2},
+			"RoleMap": core.Dict{"Heading1": core.Name("H1"), "Body": core.Name("Normal"), "Normal": core.Name("P")},
+		},
+		2: core.Dict{
+			"S":  core.Name("Document"),
+			"Pg": page0,
+			"K":  core.Array{core.IndirectRef{Number: 3}, core.IndirectRef{Number: 4}, core.IndirectRef{Number: 5}},
+		},
+		3: core.Dict{"S": core.Name("Heading1"), "K": core.Int(0), "T": core.String("Intro")},
+		4: core.Dict{
+			"S": core.Name("Body"),
+			"K": core.Array{
+				core.Int(1),
+				core.Dict{"Type": core.Name("MCR"), "Pg": page1, "MCID": core.Int(0)},
+				core.Dict{"Type": core.Name("OBJR"), "Obj": core.IndirectRef{Number: 50}},
+			},
+			"ActualText": core.String("\xfe\xff\x00H\x00i"),
+		},
+		5: core.Dict{
+			"S":   core.Name("Figure"),
+			"Alt": core.String("A chart"),
+			"A":   core.Array{core.Dict{"O": core.Name("Layout"), "BBox": core.Array{core.Int(0), core.Int(0), core.Int(10), core.Int(10)}}, core.Int(0)},
+			"K":   core.Int(2),
+		},
+	}
+	resolver, pageIndex := testDoc(objects)
+
+	tree, err := Parse(core.Dict{"StructTreeRoot": core.IndirectRef{Number: 1}}, resolver, pageIndex)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(tree.Root) != 1 || tree.Root[0].Type != "Document" {
+		t.Fatalf("Expected a Document root, got %+v", tree.Root)
+	}
+
+	doc := tree.Root[0]
+	if len(doc.Kids) != 3 {
+		t.Fatalf("Expected 3 kids, got %d", len(doc.Kids))
+	}
+
+	heading := doc.Kids[0].Element
+	if heading.Type != "H1" || heading.RawType != "Heading1" || heading.Title != "Intro" {
+		t.Errorf("Expected H1 'Intro' mapped from Heading1, got %s (%s) %q", heading.Type, heading.RawType, heading.Title)
+	}
+	if heading.Page != 0 || len(heading.Kids) != 1 || heading.Kids[0].MCID != 0 || heading.Kids[0].Page != 0 {
+		t.Errorf("Expected MCID 0 on inherited page 0, got %+v", heading.Kids)
+	}
+	if heading.Parent != doc {
+		t.Error("Expected heading's parent to be the document")
+	}
+
+	para := doc.Kids[1].Element
+	if para.Type != "P" {
+		t.Errorf("Expected role map chain to reach P, got %s", para.Type)
+	}
+	if para.ActualText != "Hi" {
+		t.Errorf("Expected ActualText 'Hi', got %q", para.ActualText)
+	}
+	if len(para.Kids) != 2 {
+		t.Fatalf("Expected 2 marked-content kids (OBJR skipped), got %d", len(para.Kids))
+	}
+	if para.Kids[1].MCID != 0 || para.Kids[1].Page != 1 {
+		t.Errorf("Expected MCR on page 1, got %+v", para.Kids[1])
+	}
+
+	figure := doc.Kids[2].Element
+	if figure.Alt != "A chart" {
+		t.Errorf("Expected Alt 'A chart', got %q", figure.Alt)
+	}
+	if _, ok := figure.Attribute("BBox").(core.Array); !ok {
+		t.Error("Expected BBox attribute")
+	}
+	if figure.Attribute("O") != nil {
+		t.Error("Expected attribute owner to be dropped")
+	}
+}
+
+func TestParseCycle(t *testing.T) {
+	objects := map[int]core.Object{
+		1: core.Dict{"K": core.IndirectRef{Number: 2}},
+		2: core.Dict{"S": core.Name("Sect"), "K": core.Array{core.IndirectRef{Number: 3}}},
+		3: core.Dict{"S": core.Name("Div"), "K": core.Array{core.IndirectRef{Number: 2}, core.Int(0)}},
+	}
+	resolver, pageIndex := testDoc(objects)
+
+	tree, err := Parse(core.Dict{"StructTreeRoot": core.IndirectRef{Number: 1}}, resolver, pageIndex)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var types []string
+	tree.Walk(func(el *Element) bool {
+		types = append(types, el.Type)
+		return true
+	})
+	if len(types) != 2 || types[0] != "Sect" || types[1] != "Div" {
+		t.Errorf("Expected Sect, Div, got %v", types)
+	}
+}
+
+func TestParseInvalidRoot(t *testing.T) {
+	if _, err := Parse(core.Dict{"StructTreeRoot": core.Int(1)}, nil, nil); err == nil {
+		t.Error("Expected error for a non-dictionary StructTreeRoot")
+	}
+}
+
+func TestWalkSkipsDescendants(t *testing.T) {
+	leaf := &Element{Type: "Span"}
+	para := &Element{Type: "P", Kids: []Kid{{Element: leaf}}}
+	tree := &Tree{Root: []*Element{para}}
+
+	count := 0
+	tree.Walk(func(el *Element) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("Expected 1 element visited, got %d", count)
+	}
+}
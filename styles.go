@@ -0,0 +1,38 @@
+package tabula
+
+import (
+	"github.com/tsawler/tabula/layout"
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/text"
+)
+
+// pageStyledText groups consecutive fragments on the same line that share a
+// bold, italic or monospaced style into runs. A style shared by most of the
+// page's text is its body style rather than emphasis, and is left out.
+func pageStyledText(fragments []text.TextFragment) []model.StyledText {
+	body := layout.DominantStyle(fragments)
+	emphasis := func(f text.TextFragment) model.TextStyle {
+		return model.TextStyle{
+			Bold:      f.IsBold && !body.Bold,
+			Italic:    f.IsItalic && !body.Italic,
+			Monospace: f.IsMonospace && !body.Monospace,
+		}
+	}
+
+	var runs []model.StyledText
+	for i := 0; i < len(fragments); {
+		style := emphasis(fragments[i])
+		j := i + 1
+		for j < len(fragments) && emphasis(fragments[j]) == style && !taggedNewLine(fragments[j-1], fragments[j]) {
+			j++
+		}
+		if style != (model.TextStyle{}) {
+			if runText := joinTagged(fragments[i:j]); runText != "" {
+				style.Color = layout.DominantStyle(fragments[i:j]).Color
+				runs = append(runs, model.StyledText{Text: runText, Style: style, BBox: taggedBBox(fragments[i:j])})
+			}
+		}
+		i = j
+	}
+	return runs
+}
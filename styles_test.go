@@ -0,0 +1,100 @@
+package tabula
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+)
+
+// buildStyledPDF assembles a one-page PDF whose body text is Helvetica with
+// a bold phrase, an italic word and a Courier function name, and a bold
+// heading
+func buildStyledPDF() []byte {
+	content := `BT /F2 18 Tf 72 720 Td (Installation) Tj ET
+BT /F1 12 Tf 72 690 Td (Download the archive for your platform and unpack it into a directory of your choice.) Tj ET
+BT /F1 12 Tf 72 675 Td (Then run the ) Tj /F3 12 Tf (setup) Tj /F1 12 Tf ( command once from that directory to create the default files.) Tj ET
+BT /F1 12 Tf 72 660 Td (This step is ) Tj /F2 12 Tf (not optional) Tj /F1 12 Tf (, and it usually takes less than a minute to finish.) Tj ET
+BT /F1 12 Tf 72 645 Td (Settings are read from the configuration file at startup, ) Tj /F4 12 Tf (always) Tj /F1 12 Tf (.) Tj ET`
+	return buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R /F2 6 0 R /F3 7 0 R /F4 8 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Oblique >>",
+	)
+}
+
+func TestFragmentsStyle(t *testing.T) {
+	fragments, _, err := FromBytes(buildStyledPDF(), "").Fragments()
+	if err != nil {
+		t.Fatalf("Fragments() error: %v", err)
+	}
+	styles := make(map[string]string)
+	for _, f := range fragments {
+		styles[strings.TrimSpace(f.Text)] = fmt.Sprintf("%v %v %v", f.IsBold, f.IsItalic, f.IsMonospace)
+	}
+	for text, want := range map[string]string{
+		"Installation": "true false false",
+		"setup":        "false false true",
+		"always":       "false true false",
+		"Then run the": "false false false",
+	} {
+		if got := styles[text]; got != want {
+			t.Errorf("fragment %q bold, italic, monospace = %s, want %s", text, got, want)
+		}
+	}
+}
+
+func TestDocumentStyle(t *testing.T) {
+	doc, _, err := FromBytes(buildStyledPDF(), "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	page := doc.Pages[0]
+
+	var heading *model.Heading
+	for _, elem := range page.Elements {
+		if h, ok := elem.(*model.Heading); ok {
+			heading = h
+		}
+	}
+	if heading == nil || heading.Text != "Installation" || !heading.Style.Bold {
+		t.Errorf("got heading %+v, want bold heading Installation", heading)
+	}
+
+	want := []model.StyledText{
+		{Text: "setup", Style: model.TextStyle{Monospace: true}},
+		{Text: "not optional", Style: model.TextStyle{Bold: true}},
+		{Text: "always", Style: model.TextStyle{Italic: true}},
+	}
+	var got []model.StyledText
+	for _, run := range page.Styled {
+		if run.Text != "Installation" {
+			run.BBox = model.BBox{}
+			got = append(got, run)
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got styled runs %+v, want %+v", got, want)
+	}
+}
+
+func TestToMarkdownStyles(t *testing.T) {
+	md, _, err := FromBytes(buildStyledPDF(), "").ToMarkdown()
+	if err != nil {
+		t.Fatalf("ToMarkdown() error: %v", err)
+	}
+	for _, want := range []string{"`setup`", "**not optional**", "*always*"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown is missing %s:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "**Installation**") {
+		t.Errorf("got emphasis inside the heading:\n%s", md)
+	}
+}
@@ -0,0 +1,177 @@
+package tabula
+
+import (
+	"github.com/tsawler/tabula/format"
+	"github.com/tsawler/tabula/layout"
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/pages"
+	"github.com/tsawler/tabula/tables"
+	"github.com/tsawler/tabula/text"
+)
+
+// Tables extracts the tables in the document, in page order.
+// This is a terminal operation that closes the underlying reader.
+//
+// For PDFs, ruled tables are found from the lines and rectangles drawn on each
+// page and borderless tables from the alignment of text into rows and
+// columns. Other formats return the tables declared in the document itself
+// (or, for XLSX, one table per sheet).
+//
+// Example:
+//
+//	tables, _, err := tabula.Open("report.pdf").Pages(3).Tables()
+//	for _, t := range tables {
+//	    fmt.Println(t.ToMarkdown())
+//	}
+func (e *Extractor) Tables() ([]*model.Table, []Warning, error) {
+	if e.err != nil {
+		return nil, nil, e.err
+	}
+
+	if err := e.ensureReader(); err != nil {
+		return nil, nil, err
+	}
+	defer e.Close()
+
+	if e.format != format.PDF {
+		doc, warnings, err := e.Document()
+		if err != nil {
+			return nil, warnings, err
+		}
+		return doc.ExtractTables(), warnings, nil
+	}
+
+	pageIndices, err := e.resolvePages()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result []*model.Table
+	err = e.eachLayoutPage(pageIndices, func(pd extractedPage) {
+		pageTables, _ := e.detectPDFTables(pd.page, pd.fragments)
+		result = append(result, pageTables...)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, e.warnings, nil
+}
+
+// analyzePDFPage fills modelPage from a PDF page's fragments. Tables are
+// detected first, from the page's drawn rules and from text alignment; the
+// fragments placed in table cells are kept out of the heading, paragraph and
+// list analysis that follows, and each table is placed among the paragraphs
+// according to its position on the page.
+//
+// Pages of tagged PDFs are built from the structure tree instead, when the
+// tags cover the page's text, with any untagged text analyzed heuristically
+// and placed after the tagged content; otherwise the heuristics run over the
+// page's non-artifact fragments. The page's links are collected from the
+// fragments' URIs and its runs of bold, italic and monospaced text from
+// their fonts, and its comments added when IncludeComments is set.
+func (e *Extractor) analyzePDFPage(a *pageAnalyzers, modelPage *model.Page, page *pages.Page, fragments []text.TextFragment) {
+	modelPage.Links = pageLinks(fragments)
+	modelPage.Styled = pageStyledText(fragments)
+
+	if a.tagged == nil {
+		e.analyzeFragments(a, modelPage, page, fragments)
+	} else if rest, built := a.tagged.buildPage(modelPage, modelPage.Number-1, fragments); !built {
+		e.analyzeFragments(a, modelPage, page, rest)
+	} else if len(rest) > 0 {
+		untagged := &model.Page{Width: modelPage.Width, Height: modelPage.Height}
+		e.analyzeFragments(a, untagged, page, rest)
+		appendPageContent(modelPage, untagged)
+	}
+
+	if e.options.includeComments {
+		e.addComments(modelPage, page, fragments)
+	}
+}
+
+// analyzeFragments fills modelPage from fragments by layout analysis.
+func (e *Extractor) analyzeFragments(a *pageAnalyzers, modelPage *model.Page, page *pages.Page, fragments []text.TextFragment) {
+	pageTables, rest := e.detectPDFTables(page, fragments)
+
+	a.analyze(modelPage, rest, modelPage.Width, modelPage.Height)
+
+	for _, table := range pageTables {
+		insertTable(modelPage, table)
+	}
+}
+
+// appendPageContent appends the elements and detected layout of src to p.
+func appendPageContent(p *model.Page, src *model.Page) {
+	p.Elements = append(p.Elements, src.Elements...)
+	if src.Layout == nil {
+		return
+	}
+	if p.Layout == nil {
+		p.Layout = &model.PageLayout{}
+	}
+	for _, para := range src.Layout.Paragraphs {
+		para.Index = len(p.Layout.Paragraphs)
+		p.Layout.Paragraphs = append(p.Layout.Paragraphs, para)
+	}
+	p.Layout.Headings = append(p.Layout.Headings, src.Layout.Headings...)
+	p.Layout.Lists = append(p.Layout.Lists, src.Layout.Lists...)
+	p.Layout.Stats.ParagraphCount = len(p.Layout.Paragraphs)
+	p.Layout.Stats.HeadingCount = len(p.Layout.Headings)
+	p.Layout.Stats.ListCount = len(p.Layout.Lists)
+}
+
+// detectPDFTables finds the tables on a PDF page and returns them together
+// with the fragments that are not part of any table.
+func (e *Extractor) detectPDFTables(page *pages.Page, fragments []text.TextFragment) ([]*model.Table, []text.TextFragment) {
+	if len(fragments) == 0 {
+		return nil, fragments
+	}
+
+	// Graphics are optional: without them only whitespace tables are found.
+	ge, _ := e.reader.ExtractGraphics(page)
+
+	modelFragments := make([]model.TextFragment, len(fragments))
+	for i, f := range fragments {
+		modelFragments[i] = model.TextFragment{
+			Text:     f.Text,
+			BBox:     model.BBox{X: f.X, Y: f.Y, Width: f.Width, Height: f.Height},
+			FontSize: f.FontSize,
+			FontName: f.FontName,
+			Style:    layout.FragmentStyle(f),
+		}
+	}
+
+	pageTables, used := tables.DetectPageTables(ge, modelFragments, tables.DefaultConfig())
+	if len(pageTables) == 0 {
+		return nil, fragments
+	}
+
+	rest := make([]text.TextFragment, 0, len(fragments))
+	for i, f := range fragments {
+		if !used[i] {
+			rest = append(rest, f)
+		}
+	}
+	return pageTables, rest
+}
+
+// insertTable adds table to the page before the first paragraph or list that
+// starts below the table's top edge, or at the end when none does.
+func insertTable(p *model.Page, table *model.Table) {
+	pos := len(p.Elements)
+	for i, elem := range p.Elements {
+		switch elem.(type) {
+		case *model.Paragraph, *model.List:
+		default:
+			continue
+		}
+		if elem.BoundingBox().Top() < table.BBox.Top() {
+			pos = i
+			break
+		}
+	}
+
+	p.Elements = append(p.Elements, nil)
+	copy(p.Elements[pos+1:], p.Elements[pos:])
+	p.Elements[pos] = table
+}
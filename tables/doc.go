@@ -10,6 +10,11 @@
 //
 //   - [GeometricDetector] - uses spatial analysis of text positions
 //
+// [GridDetector] finds ruled grids from the lines drawn on a page.
+// [DetectPageTables] combines the two: ruled tables first, then
+// whitespace-aligned tables among the remaining text. It reports which text
+// fragments landed in table cells so they can be kept out of paragraphs.
+//
 // Detectors are registered globally and can be retrieved by name:
 //
 //	detector := tables.GetDetector("geometric")
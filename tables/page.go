@@ -0,0 +1,344 @@
+package tables
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/tsawler/tabula/graphicsstate"
+	"github.com/tsawler/tabula/model"
+)
+
+// maxCellWords is the largest average number of words per filled cell a
+// whitespace table may have. Columns of running text, such as a two-column
+// article, align like a table but have far longer cells.
+const maxCellWords = 6.0
+
+// maxWordGap is the widest gap, as a fraction of the font size, between two
+// fragments on a line that are taken as words of the same phrase rather than
+// separate cells. A space is about a quarter to a third of an em; columns of
+// a borderless table are set wider apart.
+const maxWordGap = 0.8
+
+// maxRuleThickness is the largest extent (in points) a filled rectangle may
+// have across its short side to be treated as a drawn table rule.
+const maxRuleThickness = 3.0
+
+// DetectPageTables finds the tables on a page. Ruled tables are found first
+// from the lines and rectangles drawn on the page (ge may be nil when no
+// graphics are available); whitespace-aligned tables are then searched for
+// among the fragments that are not inside a ruled table.
+//
+// It returns the tables ordered top to bottom, and a slice parallel to
+// fragments reporting which fragments were placed in a table cell, so callers
+// can keep cell text out of the surrounding paragraphs.
+func DetectPageTables(ge *graphicsstate.GraphicsExtractor, fragments []model.TextFragment, config Config) ([]*model.Table, []bool) {
+	used := make([]bool, len(fragments))
+	var tables []*model.Table
+
+	if ge != nil && config.UseLines {
+		for _, table := range detectRuledTables(ge, fragments, config) {
+			markUsed(table.BBox, fragments, used)
+			tables = append(tables, table)
+		}
+	}
+
+	if config.UseWhitespace {
+		var remaining []model.TextFragment
+		for i, frag := range fragments {
+			if !used[i] {
+				remaining = append(remaining, frag)
+			}
+		}
+
+		var lines []model.Line
+		if ge != nil {
+			lines = ge.ToModelLines()
+		}
+		found := detectWhitespaceTables(remaining, lines, config)
+		for _, table := range found {
+			compactTable(table)
+			if !isPlausibleTable(table, config) || averageCellWords(table) > maxCellWords {
+				continue
+			}
+			markUsed(table.BBox, fragments, used)
+			tables = append(tables, table)
+		}
+	}
+
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].BBox.Top() > tables[j].BBox.Top()
+	})
+
+	return tables, used
+}
+
+// detectWhitespaceTables finds tables among fragments aligned into rows and
+// columns. It follows GeometricDetector.Detect, clustering the fragments by
+// vertical proximity and building a grid for each cluster, but takes column
+// boundaries from whitespaceColumns.
+func detectWhitespaceTables(fragments []model.TextFragment, lines []model.Line, config Config) []*model.Table {
+	if len(fragments) == 0 {
+		return nil
+	}
+	d := NewGeometricDetector()
+	_ = d.Configure(config)
+
+	var tables []*model.Table
+	for _, cluster := range d.clusterFragments(joinWords(fragments)) {
+		if len(cluster) < config.MinRows*config.MinCols {
+			continue
+		}
+		yCoords := d.extractRowBoundaries(cluster)
+		xCoords := whitespaceColumns(d, cluster)
+		if len(yCoords) < config.MinRows+1 || len(xCoords) < config.MinCols+1 {
+			continue
+		}
+		grid := model.NewTableGrid()
+		grid.Rows = yCoords
+		grid.Cols = xCoords
+		grid.HasHLines = d.detectHorizontalLines(yCoords, lines)
+		grid.HasVLines = d.detectVerticalLines(xCoords, lines)
+
+		confidence := d.calculateConfidence(grid, cluster, lines)
+		if confidence < config.MinConfidence {
+			continue
+		}
+		table := model.NewTable(grid.RowCount(), grid.ColCount())
+		d.assignFragmentsToCells(table, grid, cluster)
+		if config.DetectMergedCells {
+			d.detectMergedCells(table, grid)
+		}
+		table.BBox = d.calculateTableBBox(grid)
+		table.Confidence = confidence
+		table.HasGrid = d.hasVisibleGrid(grid, lines)
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// joinWords merges fragments that follow each other on a line with no more
+// than a word space between them into phrases, so that text drawn a word at
+// a time doesn't line up into a column per word.
+func joinWords(fragments []model.TextFragment) []model.TextFragment {
+	sorted := make([]model.TextFragment, len(fragments))
+	copy(sorted, fragments)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].BBox, sorted[j].BBox
+		if !sameLine(a, b) {
+			return a.Y > b.Y
+		}
+		return a.X < b.X
+	})
+
+	var joined []model.TextFragment
+	for _, frag := range sorted {
+		if n := len(joined); n > 0 {
+			last := &joined[n-1]
+			size := math.Max(last.FontSize, frag.FontSize)
+			if size <= 0 {
+				size = last.BBox.Height
+			}
+			gap := frag.BBox.Left() - last.BBox.Right()
+			if sameLine(last.BBox, frag.BBox) && gap < size*maxWordGap && gap > -size {
+				last.Text += " " + frag.Text
+				last.BBox = last.BBox.Union(frag.BBox)
+				continue
+			}
+		}
+		joined = append(joined, frag)
+	}
+	return joined
+}
+
+// sameLine reports whether two boxes share a baseline, within a third of
+// the taller one's height
+func sameLine(a, b model.BBox) bool {
+	return math.Abs(a.Y-b.Y) <= math.Max(a.Height, b.Height)/3
+}
+
+// whitespaceColumns returns the column boundaries of a borderless table: the
+// clustered left edges of its fragments, closed by the rightmost right edge.
+// Right edges are not clustered because they vary with the length of each
+// cell's text and would split every ragged column into several.
+func whitespaceColumns(d *GeometricDetector, fragments []model.TextFragment) []float64 {
+	xValues := make([]float64, 0, len(fragments))
+	right := fragments[0].BBox.Right()
+	for _, frag := range fragments {
+		xValues = append(xValues, frag.BBox.Left())
+		if frag.BBox.Right() > right {
+			right = frag.BBox.Right()
+		}
+	}
+	sort.Float64s(xValues)
+
+	clustered := d.clusterValues(xValues, d.config.AlignmentTolerance)
+	if right > clustered[len(clustered)-1]+d.config.AlignmentTolerance {
+		clustered = append(clustered, right)
+	}
+	return clustered
+}
+
+// detectRuledTables builds tables from grids formed by drawn lines and by the
+// edges of drawn rectangles.
+func detectRuledTables(ge *graphicsstate.GraphicsExtractor, fragments []model.TextFragment, config Config) []*model.Table {
+	gridLines := ge.GetGridLines()
+	horizontals, verticals := rectangleEdges(ge.GetFilteredRectangles())
+	horizontals = append(horizontals, gridLines.Horizontals...)
+	verticals = append(verticals, gridLines.Verticals...)
+
+	var tables []*model.Table
+	for _, h := range NewGridDetector().DetectFromLines(horizontals, verticals) {
+		if h.Rows < config.MinRows || h.Cols < config.MinCols {
+			continue
+		}
+		table := h.ToTable(fragments)
+		if !isPlausibleTable(table, config) {
+			continue
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// ToTable converts a grid hypothesis to a model.Table, placing each fragment
+// whose center falls inside the grid into the corresponding cell.
+func (h *GridHypothesis) ToTable(fragments []model.TextFragment) *model.Table {
+	grid := h.ToTableGrid()
+	table := model.NewTable(grid.RowCount(), grid.ColCount())
+
+	d := NewGeometricDetector()
+	d.assignFragmentsToCells(table, grid, fragments)
+
+	table.BBox = h.BBox
+	table.Confidence = h.Confidence
+	table.HasGrid = true
+	return table
+}
+
+// rectangleEdges converts drawn rectangles into grid lines. Thin filled
+// rectangles are treated as a single rule; stroked rectangles, typically
+// cell borders, contribute all four edges. Large filled rectangles are
+// shading and are ignored.
+func rectangleEdges(rects []graphicsstate.ExtractedRectangle) (horizontals, verticals []graphicsstate.ExtractedLine) {
+	for _, r := range rects {
+		b := r.BBox
+		switch {
+		case r.IsFilled && b.Height <= maxRuleThickness && b.Width > b.Height:
+			y := b.Y + b.Height/2
+			horizontals = append(horizontals, edgeLine(b.Left(), y, b.Right(), y, true))
+		case r.IsFilled && b.Width <= maxRuleThickness && b.Height > b.Width:
+			x := b.X + b.Width/2
+			verticals = append(verticals, edgeLine(x, b.Bottom(), x, b.Top(), false))
+		case r.IsStroked:
+			horizontals = append(horizontals,
+				edgeLine(b.Left(), b.Top(), b.Right(), b.Top(), true),
+				edgeLine(b.Left(), b.Bottom(), b.Right(), b.Bottom(), true))
+			verticals = append(verticals,
+				edgeLine(b.Left(), b.Bottom(), b.Left(), b.Top(), false),
+				edgeLine(b.Right(), b.Bottom(), b.Right(), b.Top(), false))
+		}
+	}
+	return horizontals, verticals
+}
+
+// edgeLine creates an axis-aligned ExtractedLine between two points.
+func edgeLine(x1, y1, x2, y2 float64, horizontal bool) graphicsstate.ExtractedLine {
+	return graphicsstate.ExtractedLine{
+		Start:        model.Point{X: x1, Y: y1},
+		End:          model.Point{X: x2, Y: y2},
+		IsHorizontal: horizontal,
+		IsVertical:   !horizontal,
+		BBox:         model.BBox{X: x1, Y: y1, Width: x2 - x1, Height: y2 - y1},
+	}
+}
+
+// compactTable removes rows and columns that have no text in any cell. Grids
+// built from fragment edges contain such gaps between real rows and columns.
+func compactTable(table *model.Table) {
+	var rows [][]model.Cell
+	for _, row := range table.Rows {
+		for _, cell := range row {
+			if cell.Text != "" {
+				rows = append(rows, row)
+				break
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		table.Rows = nil
+		return
+	}
+
+	var keep []int
+	for j := range rows[0] {
+		for _, row := range rows {
+			if j < len(row) && row[j].Text != "" {
+				keep = append(keep, j)
+				break
+			}
+		}
+	}
+
+	for i, row := range rows {
+		compacted := make([]model.Cell, 0, len(keep))
+		for _, j := range keep {
+			cell := row[j]
+			cell.RowSpan, cell.ColSpan = 1, 1
+			compacted = append(compacted, cell)
+		}
+		rows[i] = compacted
+	}
+	table.Rows = rows
+}
+
+// isPlausibleTable reports whether a table meets the minimum dimensions and
+// has at least two filled cells in most of its rows, which rejects boxes
+// drawn around ordinary text.
+func isPlausibleTable(table *model.Table, config Config) bool {
+	if table.RowCount() < config.MinRows || table.ColCount() < config.MinCols {
+		return false
+	}
+
+	multiCellRows := 0
+	for _, row := range table.Rows {
+		filled := 0
+		for _, cell := range row {
+			if cell.Text != "" {
+				filled++
+			}
+		}
+		if filled >= 2 {
+			multiCellRows++
+		}
+	}
+	return multiCellRows*2 >= table.RowCount()
+}
+
+// averageCellWords returns the mean number of words in the table's filled
+// cells.
+func averageCellWords(table *model.Table) float64 {
+	words, filled := 0, 0
+	for _, row := range table.Rows {
+		for _, cell := range row {
+			if cell.Text != "" {
+				words += len(strings.Fields(cell.Text))
+				filled++
+			}
+		}
+	}
+	if filled == 0 {
+		return 0
+	}
+	return float64(words) / float64(filled)
+}
+
+// markUsed flags the fragments whose center lies inside bbox.
+func markUsed(bbox model.BBox, fragments []model.TextFragment, used []bool) {
+	for i, frag := range fragments {
+		if bbox.Contains(frag.BBox.Center()) {
+			used[i] = true
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package tables
+
+import (
+	"testing"
+
+	"github.com/tsawler/tabula/graphicsstate"
+	"github.com/tsawler/tabula/model"
+)
+
+func TestDetectPageTables_Ruled(t *testing.T) {
+	ge := graphicsstate.NewGraphicsExtractor()
+	// 2x2 grid of stroked cell rectangles
+	content := "100 600 100 20 re S 200 600 100 20 re S 100 580 100 20 re S 200 580 100 20 re S"
+	if err := ge.ExtractFromBytes([]byte(content)); err != nil {
+		t.Fatalf("ExtractFromBytes: %v", err)
+	}
+
+	fragments := []model.TextFragment{
+		{Text: "A1", BBox: model.BBox{X: 105, Y: 605, Width: 20, Height: 10}},
+		{Text: "B1", BBox: model.BBox{X: 205, Y: 605, Width: 20, Height: 10}},
+		{Text: "A2", BBox: model.BBox{X: 105, Y: 585, Width: 20, Height: 10}},
+		{Text: "B2", BBox: model.BBox{X: 205, Y: 585, Width: 20, Height: 10}},
+		{Text: "Outside", BBox: model.BBox{X: 105, Y: 700, Width: 50, Height: 10}},
+	}
+
+	tables, used := DetectPageTables(ge, fragments, DefaultConfig())
+	if len(tables) != 1 {
+		t.Fatalf("Expected 1 table, got %d", len(tables))
+	}
+	table := tables[0]
+	if table.RowCount() != 2 || table.ColCount() != 2 {
+		t.Fatalf("Expected 2x2 table, got %dx%d", table.RowCount(), table.ColCount())
+	}
+	if got := table.GetCell(1, 1).Text; got != "B2" {
+		t.Errorf("Expected cell(1,1) = B2, got %q", got)
+	}
+	for i, want := range []bool{true, true, true, true, false} {
+		if used[i] != want {
+			t.Errorf("used[%d] = %v, want %v", i, used[i], want)
+		}
+	}
+}
+
+func TestDetectPageTables_NoGraphics(t *testing.T) {
+	fragments := []model.TextFragment{
+		{Text: "Just one line of ordinary text", BBox: model.BBox{X: 72, Y: 700, Width: 200, Height: 12}},
+	}
+
+	tables, used := DetectPageTables(nil, fragments, DefaultConfig())
+	if len(tables) != 0 {
+		t.Errorf("Expected no tables, got %d", len(tables))
+	}
+	if len(used) != len(fragments) || used[0] {
+		t.Errorf("Expected no fragments used, got %v", used)
+	}
+}
+
+func TestRectangleEdges(t *testing.T) {
+	rects := []graphicsstate.ExtractedRectangle{
+		// Thin filled rule
+		{BBox: model.BBox{X: 0, Y: 100, Width: 200, Height: 1}, IsFilled: true},
+		// Thin filled vertical rule
+		{BBox: model.BBox{X: 50, Y: 0, Width: 1, Height: 100}, IsFilled: true},
+		// Stroked cell
+		{BBox: model.BBox{X: 0, Y: 0, Width: 50, Height: 20}, IsStroked: true},
+		// Shading
+		{BBox: model.BBox{X: 0, Y: 0, Width: 200, Height: 100}, IsFilled: true},
+	}
+
+	h, v := rectangleEdges(rects)
+	if len(h) != 3 {
+		t.Errorf("Expected 3 horizontal lines, got %d", len(h))
+	}
+	if len(v) != 3 {
+		t.Errorf("Expected 3 vertical lines, got %d", len(v))
+	}
+	if h[0].Start.Y != 100.5 {
+		t.Errorf("Expected rule at Y=100.5, got %v", h[0].Start.Y)
+	}
+}
+
+func TestCompactTable(t *testing.T) {
+	table := model.NewTable(3, 3)
+	table.Rows[0][0].Text = "A"
+	table.Rows[0][2].Text = "B"
+	table.Rows[2][0].Text = "C"
+	table.Rows[2][2].Text = "D"
+
+	compactTable(table)
+
+	if table.RowCount() != 2 || table.ColCount() != 2 {
+		t.Fatalf("Expected 2x2 table, got %dx%d", table.RowCount(), table.ColCount())
+	}
+	if got := table.GetCell(1, 1).Text; got != "D" {
+		t.Errorf("Expected cell(1,1) = D, got %q", got)
+	}
+}
+
+func TestJoinWords(t *testing.T) {
+	fragments := []model.TextFragment{
+		{Text: "dozen", BBox: model.BBox{X: 120, Y: 700, Width: 30, Height: 10}, FontSize: 10},
+		{Text: "Six", BBox: model.BBox{X: 100, Y: 700, Width: 17, Height: 10}, FontSize: 10},
+		{Text: "eggs", BBox: model.BBox{X: 153, Y: 701, Width: 22, Height: 10}, FontSize: 10},
+		{Text: "4.20", BBox: model.BBox{X: 250, Y: 700, Width: 20, Height: 10}, FontSize: 10},
+		{Text: "Milk", BBox: model.BBox{X: 100, Y: 680, Width: 20, Height: 10}, FontSize: 10},
+	}
+
+	joined := joinWords(fragments)
+	var got []string
+	for _, f := range joined {
+		got = append(got, f.Text)
+	}
+	want := []string{"Six dozen eggs", "4.20", "Milk"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %q, got %q", want[i], got[i])
+		}
+	}
+	if b := joined[0].BBox; b.Left() != 100 || b.Right() != 175 {
+		t.Errorf("Expected the phrase to span 100 to 175, got %v to %v", b.Left(), b.Right())
+	}
+}
@@ -0,0 +1,262 @@
+package tabula
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+)
+
+// ruledTableContent draws a 3x3 ruled table with a paragraph above it.
+func ruledTableContent() string {
+	var b strings.Builder
+	b.WriteString("BT /F1 12 Tf 72 720 Td (Quarterly results are summarized below.) Tj ET\n")
+
+	// Rules: rows at y = 650, 630, 610, 590; columns at x = 72, 192, 312, 432.
+	b.WriteString("0.5 w\n")
+	for _, y := range []int{650, 630, 610, 590} {
+		fmt.Fprintf(&b, "72 %d m 432 %d l S\n", y, y)
+	}
+	for _, x := range []int{72, 192, 312, 432} {
+		fmt.Fprintf(&b, "%d 590 m %d 650 l S\n", x, x)
+	}
+
+	cells := [][]string{
+		{"Region", "Q1", "Q2"},
+		{"North", "120", "135"},
+		{"South", "98", "104"},
+	}
+	for r, row := range cells {
+		for c, cell := range row {
+			fmt.Fprintf(&b, "BT /F1 10 Tf %d %d Td (%s) Tj ET\n", 78+c*120, 636-r*20, cell)
+		}
+	}
+	return b.String()
+}
+
+func TestDocumentRuledTable(t *testing.T) {
+	data := buildTextPDF(ruledTableContent())
+
+	doc, _, err := FromBytes(data, "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+
+	tables := doc.ExtractTables()
+	if len(tables) != 1 {
+		t.Fatalf("got %d tables, want 1", len(tables))
+	}
+	table := tables[0]
+	if table.RowCount() != 3 || table.ColCount() != 3 {
+		t.Fatalf("table is %dx%d, want 3x3:\n%s", table.RowCount(), table.ColCount(), table.GetText())
+	}
+	if got := table.GetCell(1, 0).Text; got != "North" {
+		t.Errorf("cell(1,0) = %q, want %q", got, "North")
+	}
+	if got := table.GetCell(2, 2).Text; got != "104" {
+		t.Errorf("cell(2,2) = %q, want %q", got, "104")
+	}
+	if !table.HasGrid {
+		t.Error("ruled table should report HasGrid")
+	}
+
+	// Cell text belongs to the table only; the paragraph above precedes it.
+	page := doc.Pages[0]
+	tableIndex := -1
+	for i, elem := range page.Elements {
+		switch el := elem.(type) {
+		case *model.Table:
+			tableIndex = i
+		case *model.Paragraph:
+			if strings.Contains(el.Text, "North") || strings.Contains(el.Text, "Region") {
+				t.Errorf("paragraph contains table cell text: %q", el.Text)
+			}
+			if strings.Contains(el.Text, "Quarterly") && tableIndex >= 0 {
+				t.Error("paragraph above the table was placed after it")
+			}
+		}
+	}
+}
+
+func TestDocumentWhitespaceTable(t *testing.T) {
+	var b strings.Builder
+	rows := [][]string{
+		{"Name", "Qty", "Price"},
+		{"Apples", "4", "1.20"},
+		{"Pears", "10", "0.80"},
+		{"Plums", "7", "2.10"},
+	}
+	for r, row := range rows {
+		for c, cell := range row {
+			fmt.Fprintf(&b, "BT /F1 10 Tf %d %d Td (%s) Tj ET\n", 72+c*150, 600-r*16, cell)
+		}
+	}
+	data := buildTextPDF(b.String())
+
+	doc, _, err := FromBytes(data, "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+
+	tables := doc.ExtractTables()
+	if len(tables) != 1 {
+		t.Fatalf("got %d tables, want 1", len(tables))
+	}
+	table := tables[0]
+	if table.RowCount() != 4 || table.ColCount() != 3 {
+		t.Fatalf("table is %dx%d, want 4x3:\n%s", table.RowCount(), table.ColCount(), table.GetText())
+	}
+	if got := table.GetCell(2, 0).Text; got != "Pears" {
+		t.Errorf("cell(2,0) = %q, want %q", got, "Pears")
+	}
+}
+
+func TestDocumentNoTableInProse(t *testing.T) {
+	lines := []string{
+		"The committee met on Tuesday to review the budget",
+		"and agreed that spending on the new library wing",
+		"should be deferred until the next fiscal year, when",
+		"revenue from the bond issue becomes available.",
+	}
+	var b strings.Builder
+	for i, line := range lines {
+		fmt.Fprintf(&b, "BT /F1 12 Tf 72 %d Td (%s) Tj ET\n", 700-i*14, line)
+	}
+	data := buildTextPDF(b.String())
+
+	doc, _, err := FromBytes(data, "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	if tables := doc.ExtractTables(); len(tables) != 0 {
+		t.Errorf("got %d tables in plain prose, want 0:\n%s", len(tables), tables[0].GetText())
+	}
+}
+
+func TestDocumentNoTableInTwoColumns(t *testing.T) {
+	left := []string{
+		"The committee met on Tuesday to review",
+		"the budget and agreed that spending on",
+		"the new library wing should be deferred",
+		"until the next fiscal year begins again.",
+	}
+	right := []string{
+		"Revenue from the bond issue is expected",
+		"to arrive in the spring, at which point",
+		"the board will revisit the construction",
+		"schedule and publish an updated plan.",
+	}
+	var b strings.Builder
+	for i := range left {
+		fmt.Fprintf(&b, "BT /F1 10 Tf 72 %d Td (%s) Tj ET\n", 700-i*12, left[i])
+		fmt.Fprintf(&b, "BT /F1 10 Tf 320 %d Td (%s) Tj ET\n", 700-i*12, right[i])
+	}
+	data := buildTextPDF(b.String())
+
+	doc, _, err := FromBytes(data, "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	if tables := doc.ExtractTables(); len(tables) != 0 {
+		t.Errorf("got %d tables in two-column prose, want 0:\n%s", len(tables), tables[0].GetText())
+	}
+}
+
+func TestTablesPDF(t *testing.T) {
+	data := buildPagesPDF(
+		"BT /F1 12 Tf 72 720 Td (No tables here) Tj ET",
+		ruledTableContent(),
+	)
+
+	tables, _, err := FromBytes(data, "").Tables()
+	if err != nil {
+		t.Fatalf("Tables() error: %v", err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("got %d tables, want 1", len(tables))
+	}
+	if got := tables[0].GetCell(0, 1).Text; got != "Q1" {
+		t.Errorf("cell(0,1) = %q, want %q", got, "Q1")
+	}
+
+	// Page selection applies.
+	tables, _, err = FromBytes(data, "").Pages(1).Tables()
+	if err != nil {
+		t.Fatalf("Tables() error: %v", err)
+	}
+	if len(tables) != 0 {
+		t.Errorf("got %d tables on page 1, want 0", len(tables))
+	}
+}
+
+func TestTablesDOCX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "table.docx")
+	content := `<w:tbl>
+<w:tr><w:tc><w:p><w:r><w:t>A1</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>B1</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>A2</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>B2</w:t></w:r></w:p></w:tc></w:tr>
+</w:tbl>`
+	if err := createMinimalDOCXWithContent(path, content); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	tables, _, err := Open(path).Tables()
+	if err != nil {
+		t.Fatalf("Tables() error: %v", err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("got %d tables, want 1", len(tables))
+	}
+	if got := tables[0].GetCell(1, 1).Text; got != "B2" {
+		t.Errorf("cell(1,1) = %q, want %q", got, "B2")
+	}
+}
+
+func TestDocumentNoTableInWordSplitColumns(t *testing.T) {
+	// Three columns of prose set with word spacing in TJ arrays, as many
+	// producers write text: every word is its own fragment, and the
+	// columns' left edges line up like a table's
+	columns := [][]string{
+		{
+			"The committee met on Tuesday to review",
+			"the budget and agreed that spending on",
+			"the new library wing should be deferred",
+			"until the next fiscal year begins again",
+			"and the council has approved the plan.",
+			"Members also asked the treasurer to",
+		},
+		{
+			"Revenue from the bond issue is expected",
+			"to arrive in the spring, at which point",
+			"the board will revisit the construction",
+			"schedule and publish an updated plan",
+			"for the wing, the parking lot and the",
+			"renovation of the reading rooms above.",
+		},
+		{
+			"Residents may comment on the proposal",
+			"at the public hearing next month or by",
+			"writing to the clerk before the end of",
+			"the comment period, after which the",
+			"board will vote on the final budget",
+			"and publish the minutes of the meeting.",
+		},
+	}
+	var b strings.Builder
+	for c, lines := range columns {
+		for i, line := range lines {
+			words := strings.Join(strings.Fields(line), ") -280 (")
+			fmt.Fprintf(&b, "BT /F1 9 Tf %d %d Td [(%s)] TJ ET\n", 72+c*170, 700-i*11, words)
+		}
+	}
+	data := buildTextPDF(b.String())
+
+	doc, _, err := FromBytes(data, "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	if tables := doc.ExtractTables(); len(tables) != 0 {
+		t.Errorf("got %d tables in word-split columns, want 0:\n%s", len(tables), tables[0].GetText())
+	}
+}
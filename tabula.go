@@ -23,6 +23,14 @@
 //	    ExcludeFooters().
 //	    Text()
 //
+// Encrypted PDFs open with the user or owner password:
+//
+//	text, _, err := tabula.Open("contract.pdf").Password("secret").Text()
+//
+// Documents already in memory are read without temporary files:
+//
+//	text, _, err := tabula.FromBytes(data, "report.docx").Text()
+//
 // HTML content can be parsed from a string (useful for web scraping):
 //
 //	text, _, err := tabula.FromHTMLString(htmlContent).Text()
@@ -31,6 +39,7 @@
 package tabula
 
 import (
+	"bytes"
 	"io"
 	"strings"
 
@@ -39,14 +48,33 @@ import (
 	"github.com/tsawler/tabula/reader"
 )
 
-// Open opens a PDF or DOCX file and returns an Extractor for fluent configuration.
-// The file format is automatically detected based on the file extension.
+var (
+	// ErrPasswordRequired is returned when a PDF is encrypted and no
+	// password was supplied via Password.
+	ErrPasswordRequired = reader.ErrPasswordRequired
+
+	// ErrWrongPassword is returned when the password supplied via Password
+	// does not unlock an encrypted PDF.
+	ErrWrongPassword = reader.ErrWrongPassword
+)
+
+// Open opens a document file and returns an Extractor for fluent configuration.
+// The format is detected from the file content (magic bytes, and the archive
+// manifest for ZIP-based formats), so files with a wrong or missing extension
+// are still read correctly; the extension is used only when the content is
+// inconclusive. When extension and content disagree, the content wins and a
+// WarningFormatMismatch is returned. Use Format to force a specific format.
 // The returned Extractor must be closed when done, either explicitly via Close()
 // or implicitly when calling a terminal operation like Text().
 //
 // Supported formats:
 //   - PDF (.pdf)
 //   - DOCX (.docx)
+//   - ODT (.odt)
+//   - XLSX (.xlsx)
+//   - PPTX (.pptx)
+//   - HTML (.html, .htm)
+//   - EPUB (.epub)
 //
 // Example:
 //
@@ -75,12 +103,59 @@ func Open(filename string) *Extractor {
 //	text, warnings, err := tabula.FromReader(r).Text()
 func FromReader(r *reader.Reader) *Extractor {
 	return &Extractor{
-		reader:       r,
-		format:       format.PDF,
-		ownsReader:   false,
-		readerOpened: true,
-		options:      defaultOptions(),
+		reader:         r,
+		format:         format.PDF,
+		ownsReader:     false,
+		readerOpened:   true,
+		options:        defaultOptions(),
+		formatResolved: true,
+	}
+}
+
+// FromBytes creates an Extractor for a document held in memory, such as a file
+// downloaded from object storage. The format is detected from the content
+// (magic bytes and, for ZIP-based formats, the archive manifest); hint is
+// used only when the content is inconclusive and may be a filename
+// ("report.docx"), an extension (".docx") or a bare format name ("docx").
+// Pass "" when no hint is available.
+//
+// Example:
+//
+//	data, _ := io.ReadAll(obj.Body)
+//	text, warnings, err := tabula.FromBytes(data, obj.Key).Text()
+func FromBytes(data []byte, hint string) *Extractor {
+	return FromReaderAt(bytes.NewReader(data), int64(len(data)), hint)
+}
+
+// FromReaderAt creates an Extractor that reads size bytes from ra. Format
+// detection works as in FromBytes. All formats are read directly from ra
+// without writing temporary files. The caller keeps ownership of ra and
+// must keep it readable until extraction finishes; Close does not close it.
+//
+// Example:
+//
+//	text, _, err := tabula.FromReaderAt(ra, size, "").Text()
+func FromReaderAt(ra io.ReaderAt, size int64, hint string) *Extractor {
+	return &Extractor{
+		source:         ra,
+		sourceSize:     size,
+		format:         detectSourceFormat(ra, size, hint),
+		formatResolved: true,
+		options:        defaultOptions(),
+	}
+}
+
+// detectSourceFormat sniffs the format of an in-memory source, falling back to
+// the caller's hint when the content is not recognized.
+func detectSourceFormat(ra io.ReaderAt, size int64, hint string) format.Format {
+	if f, err := format.DetectFromReader(ra, size); err == nil && f != format.Unknown {
+		return f
+	}
+	if f := format.Detect(hint); f != format.Unknown {
+		return f
 	}
+	// Bare extensions and format names such as "docx" or "DOCX".
+	return format.Detect("." + strings.TrimPrefix(hint, "."))
 }
 
 // FromHTMLReader creates an Extractor from an io.Reader containing HTML content.
@@ -2,12 +2,15 @@ package tabula
 
 import (
 	"archive/zip"
+	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/tsawler/tabula/format"
 	"github.com/tsawler/tabula/layout"
 	"github.com/tsawler/tabula/model"
 	"github.com/tsawler/tabula/rag"
@@ -107,6 +110,73 @@ func TestPageRange(t *testing.T) {
 	}
 }
 
+// buildLabeledPDF assembles a three-page PDF whose pages are labeled i,
+// ii and A-1
+func buildLabeledPDF() []byte {
+	data := buildPagesPDF(
+		"BT /F1 12 Tf 72 720 Td (Preface text) Tj ET",
+		"BT /F1 12 Tf 72 720 Td (Contents text) Tj ET",
+		"BT /F1 12 Tf 72 720 Td (Appendix text) Tj ET",
+	)
+	return bytes.Replace(data, []byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		[]byte("<< /Type /Catalog /Pages 2 0 R /PageLabels << /Nums [0 << /S /r >> 2 << /S /D /P (A-) >>] >> >>"), 1)
+}
+
+func TestPagesByLabel(t *testing.T) {
+	text, _, err := FromBytes(buildLabeledPDF(), "").PagesByLabel("A-1", "ii").Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if strings.Contains(text, "Preface") || !strings.Contains(text, "Contents") || !strings.Contains(text, "Appendix") {
+		t.Errorf("got %q, want the pages labeled ii and A-1", text)
+	}
+
+	if _, _, err := FromBytes(buildLabeledPDF(), "").PagesByLabel("iv").Text(); err == nil {
+		t.Error("got no error for a missing page label")
+	}
+
+	// A repeated label selects its first page
+	data := bytes.Replace(buildLabeledPDF(), []byte("2 << /S /D /P (A-) >>"), []byte("2 << /S /r >>"), 1)
+	text, _, err = FromBytes(data, "").PagesByLabel("i").Text()
+	if err != nil || strings.TrimSpace(text) != "Preface text" {
+		t.Errorf("got %q, %v, want the first page labeled i", text, err)
+	}
+
+	// Without /PageLabels, labels are the decimal page numbers
+	text, _, err = FromBytes(buildPagesPDF("BT /F1 12 Tf 72 720 Td (One) Tj ET", "BT /F1 12 Tf 72 720 Td (Two) Tj ET"), "").PagesByLabel("2").Text()
+	if err != nil || strings.TrimSpace(text) != "Two" {
+		t.Errorf("got %q, %v, want page 2", text, err)
+	}
+}
+
+func TestDocumentPageLabels(t *testing.T) {
+	doc, _, err := FromBytes(buildLabeledPDF(), "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	want := []string{"i", "ii", "A-1"}
+	if len(doc.Pages) != len(want) {
+		t.Fatalf("got %d pages, want %d", len(doc.Pages), len(want))
+	}
+	for i, w := range want {
+		if doc.Pages[i].PageLabel != w {
+			t.Errorf("page %d label = %q, want %q", i+1, doc.Pages[i].PageLabel, w)
+		}
+	}
+
+	chunks, _, err := FromBytes(buildLabeledPDF(), "").PagesByLabel("A-1").Chunks()
+	if err != nil {
+		t.Fatalf("Chunks() error: %v", err)
+	}
+	if len(chunks.Chunks) == 0 || chunks.Chunks[0].Metadata.PageLabelStart != "A-1" {
+		t.Fatalf("got chunks %+v, want the first labeled A-1", chunks.Chunks)
+	}
+	// The selected page is the extract's page 1 and the file's page 3
+	if meta := chunks.Chunks[0].Metadata; meta.PageStart != 1 || meta.SourcePageStart != 3 {
+		t.Errorf("got page %d, source page %d, want 1 and 3", meta.PageStart, meta.SourcePageStart)
+	}
+}
+
 func TestInvalidPage(t *testing.T) {
 	pdfPath := testPDFPath("dinosaurs.pdf")
 	if _, err := os.Stat(pdfPath); os.IsNotExist(err) {
@@ -193,6 +263,21 @@ func TestChainImmutability(t *testing.T) {
 	}
 }
 
+func TestPasswordOption(t *testing.T) {
+	base := Open("contract.pdf")
+	withPw := base.Password("secret")
+
+	if base.options.password != "" {
+		t.Error("base extractor should have no password set")
+	}
+	if withPw.options.password != "secret" {
+		t.Errorf("password = %q, want %q", withPw.options.password, "secret")
+	}
+	if got := withPw.Pages(1).options.password; got != "secret" {
+		t.Errorf("password lost on clone: got %q", got)
+	}
+}
+
 func TestMust(t *testing.T) {
 	// Test Must with successful result
 	result := Must("hello", nil)
@@ -306,6 +391,76 @@ func TestDocument(t *testing.T) {
 	}
 }
 
+func TestDocumentMetadata(t *testing.T) {
+	xmp := `<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/" pdfaid:part="1" pdfaid:conformance="A">
+<dc:title><rdf:Alt><rdf:li xml:lang="x-default">XMP Title</rdf:li></rdf:Alt></dc:title>
+<dc:language><rdf:Bag><rdf:li>fr</rdf:li></rdf:Bag></dc:language>
+</rdf:Description></rdf:RDF></x:xmpmeta>`
+	content := "BT /F1 12 Tf 72 720 Td (Hello) Tj ET"
+	data := buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R /Metadata 6 0 R >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 3 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		fmt.Sprintf("<< /Type /Metadata /Subtype /XML /Length %d >>\nstream\n%s\nendstream", len(xmp), xmp),
+		"<< /Title (Info Title) /Producer (Tabula) /Department (Research) >>",
+	)
+	data = bytes.Replace(data, []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Info 7 0 R"), 1)
+
+	doc, _, err := FromBytes(data, "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	meta := doc.Metadata
+	if meta.Title != "XMP Title" {
+		t.Errorf("title = %q, want XMP Title", meta.Title)
+	}
+	if meta.Producer != "Tabula" || meta.Language != "fr" {
+		t.Errorf("producer, language = %q, %q, want Tabula, fr", meta.Producer, meta.Language)
+	}
+	if meta.PDFAConformance != "PDF/A-1a" {
+		t.Errorf("PDF/A conformance = %q, want PDF/A-1a", meta.PDFAConformance)
+	}
+	if meta.Custom["Department"] != "Research" {
+		t.Errorf("custom = %v, want Department = Research", meta.Custom)
+	}
+}
+
+func TestDocumentMetadataMalformedInfo(t *testing.T) {
+	xmp := `<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:title><rdf:Alt><rdf:li xml:lang="x-default">XMP Title</rdf:li></rdf:Alt></dc:title>
+</rdf:Description></rdf:RDF></x:xmpmeta>`
+	content := "BT /F1 12 Tf 72 720 Td (Hello) Tj ET"
+	data := buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R /Metadata 6 0 R >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 3 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		fmt.Sprintf("<< /Type /Metadata /Subtype /XML /Length %d >>\nstream\n%s\nendstream", len(xmp), xmp),
+		"(not a dictionary)",
+	)
+	data = bytes.Replace(data, []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Info 7 0 R"), 1)
+
+	doc, warnings, err := FromBytes(data, "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	if doc.Metadata.Title != "XMP Title" {
+		t.Errorf("title = %q, want XMP Title", doc.Metadata.Title)
+	}
+	found := false
+	for _, w := range warnings {
+		found = found || w.Code == WarningMetadata
+	}
+	if !found {
+		t.Errorf("got warnings %v, want a metadata warning", warnings)
+	}
+}
+
 func TestDocumentWithOptions(t *testing.T) {
 	pdfPath := testPDFPath("dinosaurs.pdf")
 	if _, err := os.Stat(pdfPath); os.IsNotExist(err) {
@@ -625,29 +780,30 @@ func TestDOCXUnsupportedFormat(t *testing.T) {
 	}
 }
 
-func TestFormatMismatchError(t *testing.T) {
+func TestFormatMismatchPDFAsDOCX(t *testing.T) {
 	// Create a temp file with PDF content but .docx extension
 	tmpDir := t.TempDir()
 	mismatchedFile := filepath.Join(tmpDir, "actually-pdf.docx")
 
-	// Write PDF magic bytes and minimal content
-	pdfContent := []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n%%EOF")
+	pdfContent := buildTextPDF("BT /F1 12 Tf 72 720 Td (Sniffed PDF) Tj ET")
 	if err := os.WriteFile(mismatchedFile, pdfContent, 0644); err != nil {
 		t.Fatalf("failed to create test file: %v", err)
 	}
 
-	// Try to open as DOCX (based on extension) - should fail with format mismatch
-	_, _, err := Open(mismatchedFile).Text()
-	if err == nil {
-		t.Error("expected error for format mismatch")
+	// Content wins over the extension, with a warning
+	text, warnings, err := Open(mismatchedFile).Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
 	}
-
-	if !strings.Contains(err.Error(), "format mismatch") {
-		t.Errorf("expected format mismatch error, got: %v", err)
+	if !strings.Contains(text, "Sniffed PDF") {
+		t.Errorf("Text() = %q, want it to contain %q", text, "Sniffed PDF")
 	}
-
-	if !strings.Contains(err.Error(), "DOCX") || !strings.Contains(err.Error(), "PDF") {
-		t.Errorf("error should mention both DOCX and PDF, got: %v", err)
+	if !hasWarning(warnings, WarningFormatMismatch) {
+		t.Fatalf("expected a format mismatch warning, got %v", warnings)
+	}
+	msg := FormatWarnings(warnings)
+	if !strings.Contains(msg, "DOCX") || !strings.Contains(msg, "PDF") {
+		t.Errorf("warning should mention both DOCX and PDF, got: %s", msg)
 	}
 }
 
@@ -661,15 +817,96 @@ func TestFormatMismatchDOCXAsPDF(t *testing.T) {
 		t.Fatalf("failed to create test file: %v", err)
 	}
 
-	// Try to open as PDF (based on extension) - should fail with format mismatch
-	_, _, err := Open(mismatchedFile).Text()
-	if err == nil {
-		t.Error("expected error for format mismatch")
+	text, warnings, err := Open(mismatchedFile).Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
 	}
+	if !strings.Contains(text, "test content") {
+		t.Errorf("Text() = %q, want it to contain %q", text, "test content")
+	}
+	if !hasWarning(warnings, WarningFormatMismatch) {
+		t.Errorf("expected a format mismatch warning, got %v", warnings)
+	}
+
+	// Markdown takes the DOCX path too, not the PDF chunking path that
+	// writes chunk IDs
+	opts := rag.DefaultMarkdownOptions()
+	opts.IncludeChunkIDs = true
+	md, warnings, err := Open(mismatchedFile).ToMarkdownWithOptions(opts)
+	if err != nil {
+		t.Fatalf("ToMarkdownWithOptions() error: %v", err)
+	}
+	if !strings.Contains(md, "test content") || strings.Contains(md, "<!--") {
+		t.Errorf("ToMarkdownWithOptions() = %q, want the DOCX markdown of %q", md, "test content")
+	}
+	if !hasWarning(warnings, WarningFormatMismatch) {
+		t.Errorf("expected a format mismatch warning from ToMarkdownWithOptions, got %v", warnings)
+	}
+}
+
+func TestFormatSniffedWithoutExtension(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"download", "report.bin", "archive.zip"} {
+		path := filepath.Join(tmpDir, name)
+		if err := createMinimalDOCX(path, "sniffed content"); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
 
-	if !strings.Contains(err.Error(), "format mismatch") {
-		t.Errorf("expected format mismatch error, got: %v", err)
+		text, warnings, err := Open(path).Text()
+		if err != nil {
+			t.Errorf("%s: Text() error: %v", name, err)
+			continue
+		}
+		if !strings.Contains(text, "sniffed content") {
+			t.Errorf("%s: Text() = %q", name, text)
+		}
+		// An unrecognized extension is not a disagreement
+		if hasWarning(warnings, WarningFormatMismatch) {
+			t.Errorf("%s: unexpected format mismatch warning", name)
+		}
+	}
+}
+
+func TestFormatOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "page.dat")
+	// No <html> tag, so the content alone is inconclusive
+	if err := os.WriteFile(path, []byte("<p>Override works</p>"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	if _, _, err := Open(path).Text(); err == nil {
+		t.Error("expected error for undetectable format")
+	}
+
+	text, _, err := Open(path).Format(format.HTML).Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if !strings.Contains(text, "Override works") {
+		t.Errorf("Text() = %q, want it to contain %q", text, "Override works")
+	}
+
+	// An explicit format is trusted without sniffing
+	pdfPath := filepath.Join(tmpDir, "doc.pdf")
+	if err := os.WriteFile(pdfPath, buildTextPDF("BT ET"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if _, warnings, err := Open(pdfPath).Format(format.DOCX).Text(); err == nil {
+		t.Error("expected DOCX reader error for PDF content")
+	} else if hasWarning(warnings, WarningFormatMismatch) {
+		t.Error("explicit format should not produce a mismatch warning")
+	}
+}
+
+// hasWarning reports whether warnings contains one with the given code.
+func hasWarning(warnings []Warning, code WarningCode) bool {
+	for _, w := range warnings {
+		if w.Code == code {
+			return true
+		}
 	}
+	return false
 }
 
 // createMinimalDOCX creates a minimal valid DOCX file with simple text content.
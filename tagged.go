@@ -0,0 +1,687 @@
+package tabula
+
+import (
+	"strings"
+
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/layout"
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/structure"
+	"github.com/tsawler/tabula/text"
+)
+
+// taggedContent builds model pages from a tagged PDF's structure tree, in
+// place of heuristic layout analysis. The tags give headings, paragraphs,
+// lists, tables and figures directly, in logical reading order, and mark
+// running headers, footers and page numbers as artifacts.
+type taggedContent struct {
+	blocks     map[int][]taggedBlock // Block-level elements by page index, in logical order
+	mcids      map[int]map[int]bool  // MCIDs referenced by the blocks, by page index
+	referenced map[int]map[int]bool  // MCIDs referenced anywhere in the tree, artifacts included, by page index
+}
+
+// taggedBlock is a block-level structure element. For a grouping element,
+// such as a Sect with loose marked content, elem stands in for one run of
+// that content between the element's children, and only its own content is
+// used.
+type taggedBlock struct {
+	elem   *structure.Element
+	direct bool
+}
+
+// loadTaggedContent parses the document's structure tree, returning nil if
+// the document is not tagged or the tree cannot be read.
+func (e *Extractor) loadTaggedContent() *taggedContent {
+	tree, err := e.reader.StructTree()
+	if err != nil || tree == nil {
+		return nil
+	}
+	return newTaggedContent(tree)
+}
+
+// newTaggedContent indexes a structure tree's block-level elements by page.
+func newTaggedContent(tree *structure.Tree) *taggedContent {
+	tc := &taggedContent{
+		blocks:     make(map[int][]taggedBlock),
+		mcids:      make(map[int]map[int]bool),
+		referenced: make(map[int]map[int]bool),
+	}
+	for _, el := range tree.Root {
+		tc.collect(el)
+		tc.reference(el)
+	}
+	return tc
+}
+
+// reference records the MCIDs of all of el's marked content, including
+// content inside Artifact elements.
+func (tc *taggedContent) reference(el *structure.Element) {
+	for _, kid := range el.Kids {
+		if kid.Element != nil {
+			tc.reference(kid.Element)
+			continue
+		}
+		if tc.referenced[kid.Page] == nil {
+			tc.referenced[kid.Page] = make(map[int]bool)
+		}
+		tc.referenced[kid.Page][kid.MCID] = true
+	}
+}
+
+// collect adds el, or for grouping elements its descendants, to the blocks
+// of each page its content appears on. A grouping element's own marked
+// content is added in runs between its children, so that it keeps its place
+// in the reading order.
+func (tc *taggedContent) collect(el *structure.Element) {
+	switch el.Type {
+	case "Artifact":
+		return
+	case "Figure":
+		pages := contentPages(el, false)
+		if len(pages) == 0 && el.Page >= 0 {
+			pages = map[int]bool{el.Page: true}
+		}
+		tc.add(taggedBlock{elem: el}, pages)
+		return
+	}
+
+	if !isGroupingType(el.Type) {
+		tc.add(taggedBlock{elem: el}, contentPages(el, false))
+		return
+	}
+
+	var run []structure.Kid
+	flush := func() {
+		if len(run) > 0 {
+			direct := &structure.Element{Type: el.Type, RawType: el.RawType, Page: el.Page, Kids: run, Parent: el}
+			tc.add(taggedBlock{elem: direct, direct: true}, contentPages(direct, true))
+			run = nil
+		}
+	}
+	for _, kid := range el.Kids {
+		if kid.Element == nil {
+			run = append(run, kid)
+			continue
+		}
+		flush()
+		tc.collect(kid.Element)
+	}
+	flush()
+}
+
+// add records a block on each of the given pages.
+func (tc *taggedContent) add(b taggedBlock, pages map[int]bool) {
+	for page := range pages {
+		tc.blocks[page] = append(tc.blocks[page], b)
+		if tc.mcids[page] == nil {
+			tc.mcids[page] = make(map[int]bool)
+		}
+		markMCIDs(b.elem, page, b.direct, tc.mcids[page])
+	}
+}
+
+// contentPages returns the pages holding an element's marked content, or
+// only its own (not its descendants') content when direct is set.
+func contentPages(el *structure.Element, direct bool) map[int]bool {
+	pages := make(map[int]bool)
+	var walk func(el *structure.Element)
+	walk = func(el *structure.Element) {
+		for _, kid := range el.Kids {
+			switch {
+			case kid.Element == nil && kid.Page >= 0:
+				pages[kid.Page] = true
+			case kid.Element != nil && !direct && kid.Element.Type != "Artifact":
+				walk(kid.Element)
+			}
+		}
+	}
+	walk(el)
+	return pages
+}
+
+// markMCIDs adds the MCIDs of an element's content on page to seen.
+func markMCIDs(el *structure.Element, page int, direct bool, seen map[int]bool) {
+	for _, kid := range el.Kids {
+		switch {
+		case kid.Element == nil && kid.Page == page:
+			seen[kid.MCID] = true
+		case kid.Element != nil && !direct && kid.Element.Type != "Artifact":
+			markMCIDs(kid.Element, page, false, seen)
+		}
+	}
+}
+
+// isGroupingType reports whether a structure type only groups other
+// elements. Types that are neither standard nor role-mapped are treated as
+// grouping elements too.
+func isGroupingType(t string) bool {
+	switch t {
+	case "Document", "DocumentFragment", "Part", "Art", "Sect", "Div", "NonStruct",
+		"Private", "TOC", "Index", "Aside", "BlockQuote", "Caption":
+		return true
+	case "P", "H", "H1", "H2", "H3", "H4", "H5", "H6", "Title", "FENote",
+		"L", "LI", "Lbl", "LBody", "Table", "TR", "TH", "TD", "THead", "TBody", "TFoot",
+		"Span", "Quote", "Note", "Reference", "BibEntry", "Code", "Link", "Annot",
+		"Ruby", "RB", "RT", "RP", "Warichu", "WT", "WP", "Em", "Strong", "Sub",
+		"Figure", "Formula", "Form", "TOCI":
+		return false
+	}
+	return true
+}
+
+// buildPage fills modelPage from the tagged content of the page at index
+// page, returning the page's text that the structure tree leaves out (other
+// than artifacts) for heuristic analysis. It returns false, leaving
+// modelPage untouched and returning all the non-artifact fragments, when the
+// page has no tagged content or the tagged content covers less than half of
+// the page's text, in which case the whole page should be analyzed
+// heuristically.
+func (tc *taggedContent) buildPage(modelPage *model.Page, page int, fragments []text.TextFragment) ([]text.TextFragment, bool) {
+	rest := withoutArtifacts(fragments)
+	blocks := tc.blocks[page]
+	if len(blocks) == 0 {
+		return rest, false
+	}
+
+	byMCID := make(map[int][]text.TextFragment)
+	var untagged []text.TextFragment
+	total, tagged := 0, 0
+	for _, f := range rest {
+		n := len(strings.TrimSpace(f.Text))
+		total += n
+		if f.HasMCID {
+			byMCID[f.MCID] = append(byMCID[f.MCID], f)
+			if tc.mcids[page][f.MCID] {
+				tagged += n
+			}
+		}
+		if n > 0 && (!f.HasMCID || !tc.referenced[page][f.MCID]) {
+			untagged = append(untagged, f)
+		}
+	}
+	if tagged*2 < total {
+		return rest, false
+	}
+
+	b := &taggedPageBuilder{page: page, byMCID: byMCID}
+	for _, block := range blocks {
+		b.addBlock(block)
+	}
+	if len(b.elements) == 0 && total > 0 {
+		return rest, false
+	}
+
+	modelPage.Elements = b.elements
+	modelPage.Layout = &model.PageLayout{
+		Paragraphs: b.paragraphs,
+		Headings:   b.headings,
+		Lists:      b.lists,
+		Stats: model.LayoutStats{
+			FragmentCount:  len(fragments),
+			ParagraphCount: len(b.paragraphs),
+			HeadingCount:   len(b.headings),
+			ListCount:      len(b.lists),
+		},
+	}
+	return untagged, true
+}
+
+// taggedPageBuilder converts a page's block-level structure elements to
+// model elements.
+type taggedPageBuilder struct {
+	page   int
+	byMCID map[int][]text.TextFragment
+
+	elements   []model.Element
+	paragraphs []model.ParagraphInfo
+	headings   []model.HeadingInfo
+	lists      []model.ListInfo
+}
+
+// addBlock converts one block-level element.
+func (b *taggedPageBuilder) addBlock(block taggedBlock) {
+	el := block.elem
+
+	if block.direct {
+		b.addParagraph(b.content(el, true, false))
+		return
+	}
+
+	switch el.Type {
+	case "H", "H1", "H2", "H3", "H4", "H5", "H6", "Title":
+		frags := b.content(el, false, false)
+		headingText := joinTagged(frags)
+		if headingText == "" {
+			return
+		}
+		bbox := taggedBBox(frags)
+		level := headingLevel(el)
+		fontSize := maxFontSize(frags)
+		b.elements = append(b.elements, &model.Heading{Text: headingText, Level: level, BBox: bbox, FontSize: fontSize, Style: layout.DominantStyle(frags)})
+		b.headings = append(b.headings, model.HeadingInfo{Level: level, Text: headingText, BBox: bbox, FontSize: fontSize, Confidence: 1.0})
+	case "L":
+		b.addList(el)
+	case "Table":
+		b.addTable(el)
+	case "Figure":
+		b.addFigure(el)
+	default:
+		b.addParagraph(b.content(el, false, false))
+	}
+}
+
+// addParagraph adds a paragraph of the given fragments, if they have text.
+func (b *taggedPageBuilder) addParagraph(frags []text.TextFragment) {
+	paraText := joinTagged(frags)
+	if paraText == "" {
+		return
+	}
+	bbox := taggedBBox(frags)
+	b.elements = append(b.elements, &model.Paragraph{Text: paraText, BBox: bbox, FontSize: maxFontSize(frags), Style: layout.DominantStyle(frags)})
+	b.paragraphs = append(b.paragraphs, model.ParagraphInfo{
+		Index:     len(b.paragraphs),
+		BBox:      bbox,
+		Text:      paraText,
+		LineCount: countLines(frags),
+	})
+}
+
+// content returns the fragments of an element's content on the page, in
+// logical order. The content of an element with /ActualText, or failing that
+// an /E expansion, is replaced by a single fragment with that text. direct
+// limits the content to the element's own marked content; skipLists leaves
+// out nested lists.
+func (b *taggedPageBuilder) content(el *structure.Element, direct, skipLists bool) []text.TextFragment {
+	var frags []text.TextFragment
+	for _, kid := range el.Kids {
+		switch {
+		case kid.Element == nil:
+			if kid.Page == b.page {
+				frags = append(frags, b.byMCID[kid.MCID]...)
+			}
+		case direct:
+		case kid.Element.Type == "Artifact":
+		case skipLists && kid.Element.Type == "L":
+		default:
+			frags = append(frags, b.content(kid.Element, false, skipLists)...)
+		}
+	}
+
+	replacement := el.ActualText
+	if replacement == "" {
+		replacement = el.Expansion
+	}
+	if replacement != "" && len(frags) > 0 && !direct {
+		merged := frags[0]
+		bbox := taggedBBox(frags)
+		merged.Text = replacement
+		merged.X, merged.Y, merged.Width, merged.Height = bbox.X, bbox.Y, bbox.Width, bbox.Height
+		return []text.TextFragment{merged}
+	}
+	return frags
+}
+
+// addList converts an L element, flattening nested lists into indented
+// items.
+func (b *taggedPageBuilder) addList(el *structure.Element) {
+	var items []model.ListItem
+	var frags []text.TextFragment
+	b.listItems(el, 0, &items, &frags)
+	if len(items) == 0 {
+		return
+	}
+
+	listType := taggedListType(el, items)
+	bbox := taggedBBox(frags)
+	ordered := listType == model.ListTypeNumbered || listType == model.ListTypeLettered || listType == model.ListTypeRoman
+	b.elements = append(b.elements, &model.List{Items: items, Ordered: ordered, BBox: bbox})
+
+	nested := false
+	for _, item := range items {
+		if item.Level > 0 {
+			nested = true
+		}
+	}
+	b.lists = append(b.lists, model.ListInfo{Type: listType, Items: items, BBox: bbox, Nested: nested})
+}
+
+// listItems appends the items of a list element at the given nesting level.
+func (b *taggedPageBuilder) listItems(list *structure.Element, level int, items *[]model.ListItem, all *[]text.TextFragment) {
+	for _, kid := range list.Kids {
+		if kid.Element == nil {
+			continue
+		}
+		li := kid.Element
+		switch li.Type {
+		case "L":
+			b.listItems(li, level+1, items, all)
+			continue
+		case "Artifact", "Caption":
+			continue
+		}
+
+		// An LI holds an Lbl and an LBody, though some producers put the
+		// content directly in the LI
+		var label, body []text.TextFragment
+		var nestedLists []*structure.Element
+		hasParts := false
+		for _, part := range li.Kids {
+			if part.Element == nil {
+				continue
+			}
+			switch part.Element.Type {
+			case "Lbl":
+				hasParts = true
+				label = append(label, b.content(part.Element, false, false)...)
+			case "LBody":
+				hasParts = true
+				body = append(body, b.content(part.Element, false, true)...)
+				nestedLists = append(nestedLists, childLists(part.Element)...)
+			case "L":
+				nestedLists = append(nestedLists, part.Element)
+			}
+		}
+		if !hasParts {
+			body = b.content(li, false, true)
+		}
+
+		itemText := joinTagged(body)
+		if itemText != "" || len(label) > 0 {
+			itemFrags := append(label, body...)
+			*items = append(*items, model.ListItem{
+				Text:   itemText,
+				BBox:   taggedBBox(itemFrags),
+				Bullet: joinTagged(label),
+				Level:  level,
+			})
+			*all = append(*all, itemFrags...)
+		}
+
+		for _, nested := range nestedLists {
+			b.listItems(nested, level+1, items, all)
+		}
+	}
+}
+
+// childLists returns the L elements directly inside el.
+func childLists(el *structure.Element) []*structure.Element {
+	var lists []*structure.Element
+	for _, kid := range el.Kids {
+		if kid.Element != nil && kid.Element.Type == "L" {
+			lists = append(lists, kid.Element)
+		}
+	}
+	return lists
+}
+
+// taggedListType returns the list's type from its ListNumbering attribute,
+// or from the first item's label.
+func taggedListType(el *structure.Element, items []model.ListItem) model.ListType {
+	if numbering, ok := el.Attribute("ListNumbering").(core.Name); ok {
+		switch numbering {
+		case "Decimal":
+			return model.ListTypeNumbered
+		case "UpperRoman", "LowerRoman":
+			return model.ListTypeRoman
+		case "UpperAlpha", "LowerAlpha":
+			return model.ListTypeLettered
+		case "Disc", "Circle", "Square":
+			return model.ListTypeBullet
+		}
+	}
+
+	label := strings.TrimRight(items[0].Bullet, ".)")
+	if label == "" {
+		return model.ListTypeBullet
+	}
+	digits := true
+	for _, r := range label {
+		if r < '0' || r > '9' {
+			digits = false
+			break
+		}
+	}
+	if digits {
+		return model.ListTypeNumbered
+	}
+	return model.ListTypeBullet
+}
+
+// addTable converts a Table element. Rows may be direct children or grouped
+// in THead, TBody and TFoot; cells are placed on a grid honouring their
+// RowSpan and ColSpan attributes.
+func (b *taggedPageBuilder) addTable(el *structure.Element) {
+	type tableRow struct {
+		elem   *structure.Element
+		header bool
+	}
+	var rows []tableRow
+	for _, kid := range el.Kids {
+		if kid.Element == nil {
+			continue
+		}
+		switch kid.Element.Type {
+		case "TR":
+			rows = append(rows, tableRow{elem: kid.Element})
+		case "THead", "TBody", "TFoot":
+			for _, rowKid := range kid.Element.Kids {
+				if rowKid.Element != nil && rowKid.Element.Type == "TR" {
+					rows = append(rows, tableRow{elem: rowKid.Element, header: kid.Element.Type == "THead"})
+				}
+			}
+		}
+	}
+
+	type placedCell struct {
+		row, col int
+		cell     model.Cell
+	}
+	var cells []placedCell
+	var frags []text.TextFragment
+	occupied := make(map[[2]int]bool)
+	numRows, numCols := 0, 0
+
+	for _, row := range rows {
+		if !elementOnPage(row.elem, b.page) {
+			continue
+		}
+		r := numRows
+		numRows++
+		col := 0
+		for _, kid := range row.elem.Kids {
+			if kid.Element == nil || (kid.Element.Type != "TH" && kid.Element.Type != "TD") {
+				continue
+			}
+			for occupied[[2]int{r, col}] {
+				col++
+			}
+
+			cellFrags := b.content(kid.Element, false, false)
+			frags = append(frags, cellFrags...)
+			rowSpan := spanAttribute(kid.Element, "RowSpan")
+			colSpan := spanAttribute(kid.Element, "ColSpan")
+			for i := 0; i < rowSpan; i++ {
+				for j := 0; j < colSpan; j++ {
+					occupied[[2]int{r + i, col + j}] = true
+				}
+			}
+			cells = append(cells, placedCell{row: r, col: col, cell: model.Cell{
+				Text:     joinTagged(cellFrags),
+				BBox:     taggedBBox(cellFrags),
+				RowSpan:  rowSpan,
+				ColSpan:  colSpan,
+				IsHeader: row.header || kid.Element.Type == "TH",
+			}})
+			col += colSpan
+			if col > numCols {
+				numCols = col
+			}
+		}
+	}
+	if numRows == 0 || numCols == 0 {
+		return
+	}
+
+	table := model.NewTable(numRows, numCols)
+	for _, c := range cells {
+		if c.row < numRows {
+			table.Rows[c.row][c.col] = c.cell
+		}
+	}
+	table.BBox = taggedBBox(frags)
+	b.elements = append(b.elements, table)
+}
+
+// spanAttribute returns a table cell's RowSpan or ColSpan, defaulting to 1.
+func spanAttribute(el *structure.Element, name string) int {
+	if n, ok := el.Attribute(name).(core.Int); ok && n > 1 {
+		return int(n)
+	}
+	return 1
+}
+
+// elementOnPage reports whether an element has content on the page or, if
+// it has no content at all, belongs to the page.
+func elementOnPage(el *structure.Element, page int) bool {
+	pages := contentPages(el, false)
+	if len(pages) == 0 {
+		return el.Page == page
+	}
+	return pages[page]
+}
+
+// addFigure converts a Figure element to an image carrying its alternate
+// description. The bounding box comes from the Layout BBox attribute, or
+// from any text drawn inside the figure.
+func (b *taggedPageBuilder) addFigure(el *structure.Element) {
+	alt := el.Alt
+	if alt == "" {
+		alt = el.ActualText
+	}
+
+	bbox := taggedBBox(b.content(el, false, false))
+	if arr, ok := el.Attribute("BBox").(core.Array); ok && len(arr) == 4 {
+		var v [4]float64
+		for i, obj := range arr {
+			switch n := obj.(type) {
+			case core.Int:
+				v[i] = float64(n)
+			case core.Real:
+				v[i] = float64(n)
+			}
+		}
+		bbox = model.BBox{X: v[0], Y: v[1], Width: v[2] - v[0], Height: v[3] - v[1]}
+	}
+
+	b.elements = append(b.elements, &model.Image{AltText: alt, BBox: bbox})
+}
+
+// headingLevel returns a heading's level: from H1-H6 directly, or for a
+// generic H from the number of enclosing sections.
+func headingLevel(el *structure.Element) int {
+	if len(el.Type) == 2 && el.Type[0] == 'H' && el.Type[1] >= '1' && el.Type[1] <= '6' {
+		return int(el.Type[1] - '0')
+	}
+	if el.Type != "H" {
+		return 1
+	}
+	level := 0
+	for p := el.Parent; p != nil; p = p.Parent {
+		if p.Type == "Sect" {
+			level++
+		}
+	}
+	if level < 1 {
+		level = 1
+	}
+	if level > 6 {
+		level = 6
+	}
+	return level
+}
+
+// joinTagged joins fragments in logical order, inserting a space between
+// fragments on different lines or separated by a gap on the same line.
+func joinTagged(frags []text.TextFragment) string {
+	var sb strings.Builder
+	for i, f := range frags {
+		if i > 0 && taggedNeedsSpace(frags[i-1], f) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(f.Text)
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// taggedNeedsSpace reports whether a space separates two consecutive
+// fragments.
+func taggedNeedsSpace(prev, next text.TextFragment) bool {
+	if strings.HasSuffix(prev.Text, " ") || strings.HasPrefix(next.Text, " ") {
+		return false
+	}
+	size := prev.FontSize
+	if next.FontSize > size {
+		size = next.FontSize
+	}
+	if taggedNewLine(prev, next) {
+		return true
+	}
+	gap := next.X - (prev.X + prev.Width)
+	return gap > size*0.15 || gap < -size
+}
+
+// taggedBBox returns the union of the fragments' bounding boxes.
+func taggedBBox(frags []text.TextFragment) model.BBox {
+	var bbox model.BBox
+	for i, f := range frags {
+		fb := model.BBox{X: f.X, Y: f.Y, Width: f.Width, Height: f.Height}
+		if i == 0 {
+			bbox = fb
+		} else {
+			bbox = bbox.Union(fb)
+		}
+	}
+	return bbox
+}
+
+// maxFontSize returns the largest font size among the fragments.
+func maxFontSize(frags []text.TextFragment) float64 {
+	size := 0.0
+	for _, f := range frags {
+		if f.FontSize > size {
+			size = f.FontSize
+		}
+	}
+	return size
+}
+
+// countLines counts the distinct baselines among the fragments.
+func countLines(frags []text.TextFragment) int {
+	lines := 0
+	for i, f := range frags {
+		if i == 0 || taggedNewLine(frags[i-1], f) {
+			lines++
+		}
+	}
+	return lines
+}
+
+// taggedNewLine reports whether next starts a new line after prev.
+func taggedNewLine(prev, next text.TextFragment) bool {
+	dy := next.Y - prev.Y
+	if dy < 0 {
+		dy = -dy
+	}
+	return dy > prev.FontSize*0.5
+}
+
+// withoutArtifacts returns the fragments that are not marked as artifacts.
+func withoutArtifacts(fragments []text.TextFragment) []text.TextFragment {
+	result := make([]text.TextFragment, 0, len(fragments))
+	for _, f := range fragments {
+		if !f.Artifact {
+			result = append(result, f)
+		}
+	}
+	return result
+}
@@ -0,0 +1,255 @@
+package tabula
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tsawler/tabula/model"
+	"github.com/tsawler/tabula/text"
+)
+
+// buildTaggedPDF assembles a one-page tagged PDF with the given content
+// stream and structure tree root element (object 7, on page object 4).
+func buildTaggedPDF(content, document string) []byte {
+	return buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R /StructTreeRoot 6 0 R /MarkInfo << /Marked true >> >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 3 0 R >> >> /StructParents 0 >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /StructTreeRoot /K 7 0 R >>",
+		document,
+	)
+}
+
+// taggedReportContent draws a running header, a heading, a two-line
+// paragraph, a numbered list, a table and a figure, each marked with an MCID.
+const taggedReportContent = `/Artifact <</Type /Pagination /Subtype /Header>> BDC BT /F1 9 Tf 72 760 Td (Running Header) Tj ET EMC
+/P <</MCID 1>> BDC BT /F1 12 Tf 72 670 Td (Revenue grew) Tj ET BT /F1 12 Tf 72 655 Td (this year.) Tj ET EMC
+/H1 <</MCID 0>> BDC BT /F1 18 Tf 72 700 Td (Annual Report) Tj ET EMC
+/Lbl <</MCID 2>> BDC BT /F1 12 Tf 72 630 Td (1.) Tj ET EMC
+/LBody <</MCID 3>> BDC BT /F1 12 Tf 90 630 Td (First point) Tj ET EMC
+/Lbl <</MCID 4>> BDC BT /F1 12 Tf 72 615 Td (2.) Tj ET EMC
+/LBody <</MCID 5>> BDC BT /F1 12 Tf 90 615 Td (Second point) Tj ET EMC
+/TH <</MCID 6>> BDC BT /F1 12 Tf 72 580 Td (Region) Tj ET EMC
+/TH <</MCID 7>> BDC BT /F1 12 Tf 200 580 Td (Sales) Tj ET EMC
+/TD <</MCID 8>> BDC BT /F1 12 Tf 72 565 Td (North) Tj ET EMC
+/TD <</MCID 9>> BDC BT /F1 12 Tf 200 565 Td (120) Tj ET EMC
+/Figure <</MCID 10>> BDC BT /F1 8 Tf 80 410 Td (Q1) Tj ET EMC
+/Artifact BMC BT /F1 9 Tf 300 30 Td (Page 1) Tj ET EMC`
+
+// taggedReportTree is the structure tree for taggedReportContent; the
+// heading comes first in logical order though it is drawn second.
+const taggedReportTree = `<< /Type /StructElem /S /Document /Pg 4 0 R /K [
+<< /S /H1 /K 0 >>
+<< /S /P /K 1 >>
+<< /S /L /A << /O /List /ListNumbering /Decimal >> /K [
+  << /S /LI /K [<< /S /Lbl /K 2 >> << /S /LBody /K 3 >>] >>
+  << /S /LI /K [<< /S /Lbl /K 4 >> << /S /LBody /K 5 >>] >> ] >>
+<< /S /Table /K [
+  << /S /TR /K [<< /S /TH /K 6 >> << /S /TH /K 7 >>] >>
+  << /S /TR /K [<< /S /TD /K 8 >> << /S /TD /K 9 >>] >> ] >>
+<< /S /Figure /Alt (Bar chart of sales) /A << /O /Layout /BBox [72 400 300 520] >> /K 10 >>
+] >>`
+
+func TestDocumentTagged(t *testing.T) {
+	data := buildTaggedPDF(taggedReportContent, taggedReportTree)
+
+	doc, _, err := FromBytes(data, "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+	if len(doc.Pages) != 1 {
+		t.Fatalf("got %d pages, want 1", len(doc.Pages))
+	}
+
+	elements := doc.Pages[0].Elements
+	if len(elements) != 5 {
+		t.Fatalf("got %d elements, want 5: %#v", len(elements), elements)
+	}
+
+	heading, ok := elements[0].(*model.Heading)
+	if !ok || heading.Text != "Annual Report" || heading.Level != 1 {
+		t.Errorf("element 0 = %#v, want H1 'Annual Report'", elements[0])
+	}
+
+	para, ok := elements[1].(*model.Paragraph)
+	if !ok || para.Text != "Revenue grew this year." {
+		t.Errorf("element 1 = %#v, want paragraph 'Revenue grew this year.'", elements[1])
+	}
+
+	list, ok := elements[2].(*model.List)
+	if !ok {
+		t.Fatalf("element 2 = %T, want *model.List", elements[2])
+	}
+	if !list.Ordered || len(list.Items) != 2 {
+		t.Fatalf("list = %+v, want 2 ordered items", list)
+	}
+	if list.Items[1].Text != "Second point" || list.Items[1].Bullet != "2." {
+		t.Errorf("item 1 = %+v, want '2.' 'Second point'", list.Items[1])
+	}
+
+	table, ok := elements[3].(*model.Table)
+	if !ok {
+		t.Fatalf("element 3 = %T, want *model.Table", elements[3])
+	}
+	if table.RowCount() != 2 || table.ColCount() != 2 {
+		t.Fatalf("table is %dx%d, want 2x2", table.RowCount(), table.ColCount())
+	}
+	if cell := table.GetCell(0, 1); cell.Text != "Sales" || !cell.IsHeader {
+		t.Errorf("cell(0,1) = %+v, want header 'Sales'", cell)
+	}
+	if got := table.GetCell(1, 1).Text; got != "120" {
+		t.Errorf("cell(1,1) = %q, want %q", got, "120")
+	}
+
+	image, ok := elements[4].(*model.Image)
+	if !ok || image.AltText != "Bar chart of sales" {
+		t.Errorf("element 4 = %#v, want figure with alt text", elements[4])
+	} else if image.BBox.X != 72 || image.BBox.Width != 228 || image.BBox.Height != 120 {
+		t.Errorf("figure bbox = %+v, want the Layout BBox attribute", image.BBox)
+	}
+
+	// Artifacts are skipped
+	for _, elem := range elements {
+		if te, ok := elem.(model.TextElement); ok && strings.Contains(te.GetText(), "Running Header") {
+			t.Errorf("artifact text in %T: %q", elem, te.GetText())
+		}
+	}
+
+	if headings := doc.Pages[0].Layout.Headings; len(headings) != 1 {
+		t.Errorf("got %d layout headings, want 1", len(headings))
+	}
+}
+
+func TestDocumentTaggedActualText(t *testing.T) {
+	content := `/P <</MCID 0>> BDC BT /F1 12 Tf 72 700 Td (Dr.) Tj ET EMC
+/P <</MCID 1>> BDC BT /F1 12 Tf 72 680 Td (x) Tj ET EMC`
+	tree := `<< /Type /StructElem /S /Document /Pg 4 0 R /K [
+<< /S /P /K [<< /S /Span /E (Doctor) /K 0 >>] >>
+<< /S /P /ActualText <FEFF00B2> /K 1 >>
+] >>`
+
+	doc, _, err := FromBytes(buildTaggedPDF(content, tree), "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+
+	var got []string
+	for _, elem := range doc.Pages[0].Elements {
+		got = append(got, elem.(model.TextElement).GetText())
+	}
+	if len(got) != 2 || got[0] != "Doctor" || got[1] != "²" {
+		t.Errorf("got %q, want [Doctor ²]", got)
+	}
+}
+
+func TestDocumentTaggedFallback(t *testing.T) {
+	// Only the first line is tagged, so the page is analyzed heuristically,
+	// still without the artifact
+	content := `/P <</MCID 0>> BDC BT /F1 12 Tf 72 700 Td (Tagged line) Tj ET EMC
+BT /F1 12 Tf 72 680 Td (Untagged content that makes up most of the page text) Tj ET
+BT /F1 12 Tf 72 665 Td (and continues on a second untagged line of text) Tj ET
+/Artifact BMC BT /F1 9 Tf 300 30 Td (Footer artifact) Tj ET EMC`
+	tree := `<< /Type /StructElem /S /Document /Pg 4 0 R /K [<< /S /P /K 0 >>] >>`
+
+	doc, _, err := FromBytes(buildTaggedPDF(content, tree), "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+
+	pageText := doc.Pages[0].ExtractText()
+	if !strings.Contains(pageText, "Untagged content") {
+		t.Errorf("page text %q is missing the untagged content", pageText)
+	}
+	if strings.Contains(pageText, "Footer artifact") {
+		t.Errorf("page text %q includes the artifact", pageText)
+	}
+}
+
+func TestDocumentTaggedUntaggedText(t *testing.T) {
+	// The tags cover most of the page, so it is built from them; the
+	// untagged line follows the tagged content rather than being dropped
+	content := `/P <</MCID 0>> BDC BT /F1 12 Tf 72 700 Td (Tagged paragraph that makes up most of the page text) Tj ET EMC
+/P <</MCID 1>> BDC BT /F1 12 Tf 72 680 Td (and a second tagged paragraph of similar length) Tj ET EMC
+BT /F1 12 Tf 72 640 Td (Untagged note) Tj ET
+/Artifact BMC BT /F1 9 Tf 300 30 Td (Footer artifact) Tj ET EMC`
+	tree := `<< /Type /StructElem /S /Document /Pg 4 0 R /K [<< /S /P /K 0 >> << /S /P /K 1 >>] >>`
+
+	doc, _, err := FromBytes(buildTaggedPDF(content, tree), "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+
+	var got []string
+	for _, elem := range doc.Pages[0].Elements {
+		got = append(got, elem.(model.TextElement).GetText())
+	}
+	want := []string{
+		"Tagged paragraph that makes up most of the page text",
+		"and a second tagged paragraph of similar length",
+		"Untagged note",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if n := len(doc.Pages[0].Layout.Paragraphs); n != 3 {
+		t.Errorf("got %d layout paragraphs, want 3", n)
+	}
+}
+
+func TestDocumentTaggedGroupingOrder(t *testing.T) {
+	// The Sect's own content comes before and after its P child
+	content := `/P <</MCID 1>> BDC BT /F1 12 Tf 72 680 Td (Middle paragraph) Tj ET EMC
+/Span <</MCID 0>> BDC BT /F1 12 Tf 72 700 Td (Opening text) Tj ET EMC
+/Span <</MCID 2>> BDC BT /F1 12 Tf 72 660 Td (Closing text) Tj ET EMC`
+	tree := `<< /Type /StructElem /S /Document /Pg 4 0 R /K [
+<< /S /Sect /Pg 4 0 R /K [0 << /S /P /K 1 >> 2] >>
+] >>`
+
+	doc, _, err := FromBytes(buildTaggedPDF(content, tree), "").Document()
+	if err != nil {
+		t.Fatalf("Document() error: %v", err)
+	}
+
+	var got []string
+	for _, elem := range doc.Pages[0].Elements {
+		got = append(got, elem.(model.TextElement).GetText())
+	}
+	want := []string{"Opening text", "Middle paragraph", "Closing text"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEachPageTagged(t *testing.T) {
+	data := buildTaggedPDF(taggedReportContent, taggedReportTree)
+
+	var page *model.Page
+	err := FromBytes(data, "").EachPage(func(p PageResult) error {
+		page = p.Page
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachPage() error: %v", err)
+	}
+	if page == nil || len(page.Elements) == 0 {
+		t.Fatal("got no page elements")
+	}
+	if heading, ok := page.Elements[0].(*model.Heading); !ok || heading.Text != "Annual Report" {
+		t.Errorf("first element = %#v, want the tagged heading", page.Elements[0])
+	}
+}
+
+func TestJoinTagged(t *testing.T) {
+	frags := []text.TextFragment{
+		{Text: "Hel", X: 72, Y: 700, Width: 18, FontSize: 12},
+		{Text: "lo", X: 90, Y: 700, Width: 12, FontSize: 12},
+		{Text: "world", X: 110, Y: 700, Width: 30, FontSize: 12},
+		{Text: "again", X: 72, Y: 685, Width: 30, FontSize: 12},
+	}
+	if got := joinTagged(frags); got != "Hello world again" {
+		t.Errorf("joinTagged() = %q, want %q", got, "Hello world again")
+	}
+}
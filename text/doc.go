@@ -13,7 +13,10 @@
 //	fragments, err := extractor.ExtractFromBytes(contentData)
 //
 // Each [TextFragment] contains the text along with position (X, Y), dimensions
-// (Width, Height), font information, and text direction.
+// (Width, Height), font information, and text direction. Its style (IsBold,
+// IsItalic, IsMonospace) comes from the font descriptor's flags, weight and
+// italic angle, falling back to the font's name, and its Color from the
+// graphics state.
 //
 // # Font Registration
 //
@@ -40,4 +43,11 @@
 //   - Word-level PDFs: Uses font space width metrics
 //   - Character-level PDFs: Uses adaptive gap detection
 //   - Explicit spaces: Respects space characters in the stream
+//
+// # Marked Content
+//
+// The BMC, BDC and EMC operators are tracked so that each fragment records
+// the marked-content ID (MCID) tying it to a tagged PDF's structure tree and
+// whether it is an Artifact, such as a running header or page number. Text
+// shown inside a sequence with /ActualText is replaced by that text.
 package text
@@ -28,6 +28,25 @@ type TextFragment struct {
 	FontName  string    // Name of the font used
 	FontSize  float64   // Font size in page units
 	Direction Direction // Text direction (LTR, RTL, Neutral)
+
+	// Style, from the font's descriptor flags, weight and italic angle or,
+	// failing those, its name
+	IsBold      bool // Bold, or filled and stroked (synthetic bold)
+	IsItalic    bool // Italic or oblique
+	IsMonospace bool // Fixed-pitch
+
+	// Marked content (tagged PDF)
+	MCID     int  // Marked-content ID of the innermost enclosing sequence with one
+	HasMCID  bool // Whether MCID is set
+	Artifact bool // Inside an Artifact sequence (headers, footers, page numbers)
+
+	// Hyperlink (set by the reader from the page's link annotations)
+	URI string // Target of a URI link covering the fragment
+
+	// Rendering
+	RenderMode int        // Text rendering mode (Tr): 0 fill, 1 stroke, 2 fill and stroke, 3 invisible, 4-7 the same and clip
+	Color      [3]float64 // RGB color in [0, 1]: the fill color, or the stroke color for stroked-only text
+	Visible    bool       // Whether the text can be seen when the page is displayed (see Extractor)
 }
 
 // Extractor extracts text fragments from PDF content streams.
@@ -43,6 +62,12 @@ type Extractor struct {
 	resolver        func(core.IndirectRef) (core.Object, error) // Reference resolver
 	xobjectDepth    int                                         // Current XObject nesting depth
 	maxXObjectDepth int                                         // Maximum nesting depth (prevents infinite recursion)
+
+	markedContent []markedContent // Open marked-content sequences (BMC/BDC ... EMC)
+
+	backgrounds []model.BBox // Device-space bounds of the images and non-white fills painted so far
+	fillSpace   string       // Name of the fill color space set by cs
+	strokeSpace string       // Name of the stroke color space set by CS
 }
 
 // NewExtractor creates a new text extractor with initialized graphics state.
@@ -146,6 +171,10 @@ func (e *Extractor) RegisterFontsFromResources(resources core.Dict, resolver fun
 			if t0Font, err := font.NewType0Font(fontDict, resolver); err == nil {
 				parsedFont = t0Font.Font
 			}
+		case "Type3":
+			if t3Font, err := font.NewType3Font(fontDict, resolver); err == nil {
+				parsedFont = t3Font.Font
+			}
 		}
 
 		// Register parsed font
@@ -172,6 +201,9 @@ func resolveIfRef(obj core.Object, resolver func(core.IndirectRef) (core.Object,
 // Extract extracts text fragments from parsed content stream operations.
 func (e *Extractor) Extract(operations []contentstream.Operation) ([]TextFragment, error) {
 	e.fragments = make([]TextFragment, 0)
+	e.markedContent = nil
+	e.backgrounds = nil
+	e.fillSpace, e.strokeSpace = "", ""
 
 	for i, op := range operations {
 		if err := e.processOperation(op); err != nil {
@@ -214,20 +246,50 @@ func (e *Extractor) processOperation(op contentstream.Operation) error {
 				e.gs.SetLineWidth(w)
 			}
 		}
-	case "RG":
-		if len(op.Operands) == 3 {
-			r, _ := toFloat(op.Operands[0])
-			g, _ := toFloat(op.Operands[1])
-			b, _ := toFloat(op.Operands[2])
-			e.gs.SetStrokeColorRGB(r, g, b)
+	case "RG", "G", "K":
+		e.gs.SetStrokeColor(colorComponents(op.Operands)...)
+	case "rg", "g", "k":
+		e.gs.SetFillColor(colorComponents(op.Operands)...)
+	case "SC", "SCN":
+		e.gs.SetStrokeColor(spaceColor(e.strokeSpace, op.Operands)...)
+	case "sc", "scn":
+		e.gs.SetFillColor(spaceColor(e.fillSpace, op.Operands)...)
+	case "CS":
+		e.strokeSpace = colorSpaceName(op.Operands)
+		e.gs.SetStrokeColor(0) // Initial color of most color spaces
+	case "cs":
+		e.fillSpace = colorSpaceName(op.Operands)
+		e.gs.SetFillColor(0)
+
+	// Paths, tracked for clipping and backgrounds
+	case "m", "l", "c", "v", "y":
+		for i := 0; i+1 < len(op.Operands); i += 2 {
+			x, _ := toFloat(op.Operands[i])
+			y, _ := toFloat(op.Operands[i+1])
+			e.gs.AddPathPoint(x, y)
 		}
-	case "rg":
-		if len(op.Operands) == 3 {
-			r, _ := toFloat(op.Operands[0])
-			g, _ := toFloat(op.Operands[1])
-			b, _ := toFloat(op.Operands[2])
-			e.gs.SetFillColorRGB(r, g, b)
+	case "re":
+		if len(op.Operands) == 4 {
+			x, _ := toFloat(op.Operands[0])
+			y, _ := toFloat(op.Operands[1])
+			w, _ := toFloat(op.Operands[2])
+			h, _ := toFloat(op.Operands[3])
+			e.gs.AddPathPoint(x, y)
+			e.gs.AddPathPoint(x+w, y+h)
+			e.gs.AddPathPoint(x+w, y)
+			e.gs.AddPathPoint(x, y+h)
 		}
+	case "W", "W*":
+		e.gs.ClipPath()
+	case "f", "F", "f*", "B", "B*", "b", "b*":
+		if bounds, ok := e.gs.PathBounds(); ok && !isWhite(e.gs.FillColor) {
+			e.backgrounds = append(e.backgrounds, bounds)
+		}
+		e.gs.EndPath()
+	case "S", "s", "n":
+		e.gs.EndPath()
+	case "BI":
+		e.addImageBackground()
 
 	// Text state
 	case "BT":
@@ -346,6 +408,18 @@ func (e *Extractor) processOperation(op contentstream.Operation) error {
 			}
 		}
 
+	// Marked content
+	case "BMC":
+		if len(op.Operands) == 1 {
+			e.beginMarkedContent(op.Operands[0], nil)
+		}
+	case "BDC":
+		if len(op.Operands) == 2 {
+			e.beginMarkedContent(op.Operands[0], op.Operands[1])
+		}
+	case "EMC":
+		e.endMarkedContent()
+
 	// XObject invocation
 	case "Do":
 		if len(op.Operands) == 1 {
@@ -420,8 +494,12 @@ func (e *Extractor) invokeXObject(name string) error {
 		return nil
 	}
 	subtypeName, ok := subtype.(core.Name)
+	if ok && string(subtypeName) == "Image" {
+		e.addImageBackground()
+		return nil
+	}
 	if !ok || string(subtypeName) != "Form" {
-		return nil // Not a Form XObject (might be Image)
+		return nil // Not a Form XObject
 	}
 
 	// Decode the XObject content stream
@@ -455,6 +533,7 @@ func (e *Extractor) invokeXObject(name string) error {
 	// Save current state
 	e.gs.Save()
 	e.xobjectDepth++
+	markedDepth := len(e.markedContent)
 
 	// Save current resources and set XObject's resources (or merged)
 	oldResources := e.resources
@@ -490,7 +569,8 @@ func (e *Extractor) invokeXObject(name string) error {
 		}
 	}
 
-	// Restore state
+	// Restore state, closing any marked content the XObject left open
+	e.closeMarkedContent(markedDepth)
 	e.resources = oldResources
 	e.xobjectDepth--
 	e.gs.Restore()
@@ -552,10 +632,15 @@ func (e *Extractor) showText(data []byte) {
 		decodedText = string(data)
 	}
 
-	// Calculate text width
+	// Calculate text width, preferring per-code widths (Type3 fonts) since
+	// the decoded text need not have one rune per code
 	width := 0.0
 	if f, ok := e.fonts[fontName]; ok {
-		width = f.GetStringWidth(decodedText) * fontSize / 1000.0
+		if codesWidth, ok := f.GetCodesWidth(data); ok {
+			width = codesWidth * fontSize / 1000.0
+		} else {
+			width = f.GetStringWidth(decodedText) * fontSize / 1000.0
+		}
 	} else {
 		// Estimate width if font not available
 		width = float64(len(decodedText)) * fontSize * 0.5
@@ -590,6 +675,16 @@ func (e *Extractor) showText(data []byte) {
 		FontSize:  deviceFontSize, // Use device font size for layout calculations
 		Direction: direction,
 	}
+	if f, ok := e.fonts[fontName]; ok {
+		fragment.IsBold = f.IsBold()
+		fragment.IsItalic = f.IsItalic()
+		fragment.IsMonospace = f.IsMonospace()
+	}
+	e.markFragment(&fragment)
+	e.markVisibility(&fragment)
+	if fragment.RenderMode == 2 || fragment.RenderMode == 6 {
+		fragment.IsBold = true
+	}
 
 	e.fragments = append(e.fragments, fragment)
 
@@ -1172,7 +1267,7 @@ func (e *Extractor) deduplicateFragments() []TextFragment {
 		text string // Text content
 	}
 
-	seen := make(map[fragKey]bool)
+	seen := make(map[fragKey]int) // Index in result
 	result := make([]TextFragment, 0, len(e.fragments))
 
 	for _, frag := range e.fragments {
@@ -1183,9 +1278,12 @@ func (e *Extractor) deduplicateFragments() []TextFragment {
 			text: frag.Text,
 		}
 
-		if !seen[key] {
-			seen[key] = true
+		if i, ok := seen[key]; !ok {
+			seen[key] = len(result)
 			result = append(result, frag)
+		} else if frag.Visible && !result[i].Visible {
+			// Keep the copy that is seen, e.g. over an invisible OCR layer
+			result[i] = frag
 		}
 	}
 
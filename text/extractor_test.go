@@ -398,6 +398,63 @@ func TestGetFonts(t *testing.T) {
 	}
 }
 
+// TestType3FontExtraction tests decoding and spacing with a Type3 font
+func TestType3FontExtraction(t *testing.T) {
+	resources := core.Dict{
+		"Font": core.Dict{
+			"T3": core.Dict{
+				"Type":       core.Name("Font"),
+				"Subtype":    core.Name("Type3"),
+				"FontMatrix": core.Array{core.Real(0.01), core.Int(0), core.Int(0), core.Real(0.01), core.Int(0), core.Int(0)},
+				"FirstChar":  core.Int(1),
+				"LastChar":   core.Int(3),
+				"Widths":     core.Array{core.Int(60), core.Int(40), core.Int(30)},
+				"Encoding": core.Dict{
+					"Differences": core.Array{core.Int(1), core.Name("O"), core.Name("k"), core.Name("space")},
+				},
+				"CharProcs": core.Dict{},
+			},
+		},
+	}
+
+	ex := NewExtractor()
+	if err := ex.RegisterFontsFromResources(resources, nil); err != nil {
+		t.Fatalf("RegisterFontsFromResources failed: %v", err)
+	}
+	if f := ex.GetFonts()["/T3"]; f == nil || f.Subtype != "Type3" {
+		t.Fatalf("expected Type3 font /T3 to be registered, got %+v", f)
+	}
+
+	operations := []contentstream.Operation{
+		{Operator: "BT", Operands: []core.Object{}},
+		{Operator: "Tf", Operands: []core.Object{core.Name("T3"), core.Int(10)}},
+		{Operator: "Td", Operands: []core.Object{core.Int(100), core.Int(700)}},
+		{Operator: "Tj", Operands: []core.Object{core.String([]byte{1, 2})}},
+		{Operator: "Tj", Operands: []core.Object{core.String([]byte{1})}},
+		{Operator: "ET", Operands: []core.Object{}},
+	}
+
+	fragments, err := ex.Extract(operations)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(fragments) != 2 {
+		t.Fatalf("expected 2 fragments, got %d", len(fragments))
+	}
+
+	if fragments[0].Text != "Ok" {
+		t.Errorf("expected text 'Ok', got %q", fragments[0].Text)
+	}
+
+	// (60 + 40) glyph units * 0.01 * 10pt = 10pt
+	if fragments[0].Width < 9.999 || fragments[0].Width > 10.001 {
+		t.Errorf("expected width 10, got %f", fragments[0].Width)
+	}
+	if fragments[1].X < 109.999 || fragments[1].X > 110.001 {
+		t.Errorf("expected second fragment at X=110, got %f", fragments[1].X)
+	}
+}
+
 // TestToFloat tests the toFloat helper function
 func TestToFloat(t *testing.T) {
 	tests := []struct {
@@ -1034,3 +1091,87 @@ func TestMaxXObjectDepth(t *testing.T) {
 		t.Errorf("expected maxXObjectDepth to be 10, got %d", ex.maxXObjectDepth)
 	}
 }
+
+// TestFragmentVisibility tests rendering mode, clipping and white text
+func TestFragmentVisibility(t *testing.T) {
+	input := []byte(`BT /F1 12 Tf 72 700 Td (shown) Tj ET
+q BT /F1 12 Tf 3 Tr 72 680 Td (ocr layer) Tj ET Q
+q 0 0 100 100 re W n BT /F1 12 Tf 72 660 Td (clipped) Tj ET Q
+1 1 1 rg BT /F1 12 Tf 72 640 Td (white) Tj ET
+0 0 1 rg 60 610 200 20 re f
+1 1 1 rg BT /F1 12 Tf 72 615 Td (banner) Tj ET
+/CS0 cs 1 scn BT /F1 12 Tf 72 590 Td (spot) Tj ET`)
+
+	ex := NewExtractor()
+	fragments, err := ex.ExtractFromBytes(input)
+	if err != nil {
+		t.Fatalf("ExtractFromBytes failed: %v", err)
+	}
+
+	want := map[string]bool{"shown": true, "ocr layer": false, "clipped": false, "white": false, "banner": true, "spot": true}
+	if len(fragments) != len(want) {
+		t.Fatalf("expected %d fragments, got %d", len(want), len(fragments))
+	}
+	for _, f := range fragments {
+		if f.Visible != want[f.Text] {
+			t.Errorf("fragment %q: expected visible %v, got %v", f.Text, want[f.Text], f.Visible)
+		}
+	}
+	if fragments[1].RenderMode != 3 {
+		t.Errorf("expected render mode 3, got %d", fragments[1].RenderMode)
+	}
+	if fragments[4].Color != [3]float64{1, 1, 1} {
+		t.Errorf("expected white color, got %v", fragments[4].Color)
+	}
+}
+
+// TestDeduplicatePrefersVisible tests that a visible copy of duplicated text
+// replaces an invisible one
+func TestDeduplicatePrefersVisible(t *testing.T) {
+	input := []byte(`BT /F1 12 Tf 3 Tr 72 700 Td (Invoice) Tj ET
+BT /F1 12 Tf 0 Tr 72 700 Td (Invoice) Tj ET`)
+
+	fragments, err := NewExtractor().ExtractFromBytes(input)
+	if err != nil {
+		t.Fatalf("ExtractFromBytes failed: %v", err)
+	}
+	if len(fragments) != 1 || !fragments[0].Visible {
+		t.Errorf("expected one visible fragment, got %+v", fragments)
+	}
+}
+
+// TestFragmentStyle tests that fragments take bold, italic and monospace
+// from their fonts, and bold from fill-and-stroke rendering
+func TestFragmentStyle(t *testing.T) {
+	input := []byte(`BT /F1 12 Tf 72 700 Td (plain) Tj ET
+BT /F2 12 Tf 72 680 Td (bold italic) Tj ET
+BT /F3 12 Tf 72 660 Td (code) Tj ET
+BT /F1 12 Tf 2 Tr 72 640 Td (stroked) Tj ET`)
+
+	ex := NewExtractor()
+	ex.RegisterFont("/F1", "Helvetica", "Type1")
+	ex.RegisterFont("/F2", "Helvetica-BoldOblique", "Type1")
+	ex.RegisterFont("/F3", "Courier", "Type1")
+
+	fragments, err := ex.ExtractFromBytes(input)
+	if err != nil {
+		t.Fatalf("ExtractFromBytes failed: %v", err)
+	}
+	if len(fragments) != 4 {
+		t.Fatalf("expected 4 fragments, got %d", len(fragments))
+	}
+
+	want := []struct{ bold, italic, monospace bool }{
+		{false, false, false},
+		{true, true, false},
+		{false, false, true},
+		{true, false, false},
+	}
+	for i, w := range want {
+		f := fragments[i]
+		if f.IsBold != w.bold || f.IsItalic != w.italic || f.IsMonospace != w.monospace {
+			t.Errorf("fragment %q: expected bold=%v italic=%v monospace=%v, got %v %v %v",
+				f.Text, w.bold, w.italic, w.monospace, f.IsBold, f.IsItalic, f.IsMonospace)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package text
+
+import (
+	"strings"
+
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/font"
+)
+
+// markedContent is an open marked-content sequence (PDF 32000-1:2008, 14.6).
+// Tagged PDFs use the sequences' MCIDs to tie content to structure elements
+// and the Artifact tag for content outside the logical structure.
+type markedContent struct {
+	tag        string // Tag, e.g. "P", "Span" or "Artifact"
+	mcid       int    // Marked-content ID, if hasMCID
+	hasMCID    bool
+	actualText string // Replacement text, if hasActual
+	hasActual  bool
+	start      int // Index of the first fragment shown inside the sequence
+}
+
+// beginMarkedContent handles BMC and BDC. props is the BDC property list,
+// either an inline dictionary or the name of an entry in the resources'
+// /Properties dictionary.
+func (e *Extractor) beginMarkedContent(tagObj, props core.Object) {
+	mc := markedContent{start: len(e.fragments)}
+	if tag, ok := tagObj.(core.Name); ok {
+		mc.tag = strings.TrimPrefix(string(tag), "/")
+	}
+
+	if dict := e.propertyList(props); dict != nil {
+		if mcid, ok := dict.Get("MCID").(core.Int); ok {
+			mc.mcid = int(mcid)
+			mc.hasMCID = true
+		}
+		if actual, ok := dict.Get("ActualText").(core.String); ok {
+			mc.actualText = font.DecodeTextString([]byte(actual))
+			mc.hasActual = true
+		}
+	}
+
+	e.markedContent = append(e.markedContent, mc)
+}
+
+// endMarkedContent handles EMC. The fragments shown inside a sequence with
+// /ActualText are replaced by a single fragment carrying that text.
+func (e *Extractor) endMarkedContent() {
+	if len(e.markedContent) == 0 {
+		return // Unbalanced EMC
+	}
+	mc := e.markedContent[len(e.markedContent)-1]
+	e.markedContent = e.markedContent[:len(e.markedContent)-1]
+
+	if mc.hasActual && mc.start < len(e.fragments) {
+		e.fragments = append(e.fragments[:mc.start], mergeActualText(e.fragments[mc.start:], mc.actualText))
+	}
+}
+
+// closeMarkedContent ends open sequences until depth remain, for content
+// streams that leave marked content unbalanced
+func (e *Extractor) closeMarkedContent(depth int) {
+	for len(e.markedContent) > depth {
+		e.endMarkedContent()
+	}
+}
+
+// propertyList resolves a BDC property list operand to a dictionary
+func (e *Extractor) propertyList(props core.Object) core.Dict {
+	switch p := props.(type) {
+	case core.Dict:
+		return p
+	case core.Name:
+		if e.resources == nil {
+			return nil
+		}
+		propsObj, err := resolveIfRef(e.resources.Get("Properties"), e.resolver)
+		if err != nil {
+			return nil
+		}
+		properties, ok := propsObj.(core.Dict)
+		if !ok {
+			return nil
+		}
+		entry := properties.Get(strings.TrimPrefix(string(p), "/"))
+		if entry == nil {
+			return nil
+		}
+		resolved, err := resolveIfRef(entry, e.resolver)
+		if err != nil {
+			return nil
+		}
+		dict, _ := resolved.(core.Dict)
+		return dict
+	}
+	return nil
+}
+
+// markFragment sets a new fragment's MCID and Artifact flag from the open
+// marked-content sequences
+func (e *Extractor) markFragment(frag *TextFragment) {
+	for i := len(e.markedContent) - 1; i >= 0; i-- {
+		mc := e.markedContent[i]
+		if mc.hasMCID && !frag.HasMCID {
+			frag.MCID = mc.mcid
+			frag.HasMCID = true
+		}
+		if mc.tag == "Artifact" {
+			frag.Artifact = true
+		}
+	}
+}
+
+// mergeActualText combines the fragments of a sequence into one fragment
+// covering all of them, with the sequence's replacement text
+func mergeActualText(fragments []TextFragment, actualText string) TextFragment {
+	merged := fragments[0]
+	minX, minY := merged.X, merged.Y
+	maxX, maxY := merged.X+merged.Width, merged.Y+merged.Height
+	for _, f := range fragments[1:] {
+		if f.X < minX {
+			minX = f.X
+		}
+		if f.Y < minY {
+			minY = f.Y
+		}
+		if f.X+f.Width > maxX {
+			maxX = f.X + f.Width
+		}
+		if f.Y+f.Height > maxY {
+			maxY = f.Y + f.Height
+		}
+	}
+
+	merged.Text = actualText
+	merged.X, merged.Y = minX, minY
+	merged.Width, merged.Height = maxX-minX, maxY-minY
+	merged.Direction = DetectDirection(actualText)
+	return merged
+}
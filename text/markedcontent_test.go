@@ -0,0 +1,130 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/tsawler/tabula/core"
+)
+
+// TestMarkedContentMCID tests that fragments take the MCID of the innermost
+// enclosing sequence that has one
+func TestMarkedContentMCID(t *testing.T) {
+	ex := NewExtractor()
+	ex.RegisterFont("/F1", "Helvetica", "Type1")
+
+	content := []byte(`/P <</MCID 0>> BDC BT /F1 12 Tf 72 720 Td (First) Tj ET EMC
+/P <</MCID 1>> BDC /Span BMC BT /F1 12 Tf 72 700 Td (Second) Tj ET EMC EMC
+BT /F1 12 Tf 72 680 Td (Untagged) Tj ET`)
+
+	fragments, err := ex.ExtractFromBytes(content)
+	if err != nil {
+		t.Fatalf("ExtractFromBytes failed: %v", err)
+	}
+	if len(fragments) != 3 {
+		t.Fatalf("expected 3 fragments, got %d", len(fragments))
+	}
+
+	for i, want := range []int{0, 1} {
+		if !fragments[i].HasMCID || fragments[i].MCID != want {
+			t.Errorf("fragment %d: expected MCID %d, got %d (set %v)", i, want, fragments[i].MCID, fragments[i].HasMCID)
+		}
+	}
+	if fragments[2].HasMCID {
+		t.Error("expected untagged fragment to have no MCID")
+	}
+}
+
+// TestMarkedContentArtifact tests that Artifact content is flagged
+func TestMarkedContentArtifact(t *testing.T) {
+	ex := NewExtractor()
+	ex.RegisterFont("/F1", "Helvetica", "Type1")
+
+	content := []byte(`/Artifact <</Type /Pagination /Subtype /Header>> BDC BT /F1 10 Tf 72 760 Td (Running head) Tj ET EMC
+/Artifact BMC BT /F1 10 Tf 300 30 Td (7) Tj ET EMC
+/P <</MCID 0>> BDC BT /F1 12 Tf 72 700 Td (Body) Tj ET EMC`)
+
+	fragments, err := ex.ExtractFromBytes(content)
+	if err != nil {
+		t.Fatalf("ExtractFromBytes failed: %v", err)
+	}
+	if len(fragments) != 3 {
+		t.Fatalf("expected 3 fragments, got %d", len(fragments))
+	}
+
+	if !fragments[0].Artifact || !fragments[1].Artifact {
+		t.Error("expected header and page number to be artifacts")
+	}
+	if fragments[2].Artifact {
+		t.Error("expected body text not to be an artifact")
+	}
+}
+
+// TestMarkedContentActualText tests that /ActualText replaces the text shown
+// inside a sequence
+func TestMarkedContentActualText(t *testing.T) {
+	ex := NewExtractor()
+	ex.RegisterFont("/F1", "Helvetica", "Type1")
+
+	// A ligature drawn as two glyph runs, and a UTF-16 replacement
+	content := []byte(`/Span <</MCID 3 /ActualText (office)>> BDC BT /F1 12 Tf 72 700 Td (o) Tj (ce) Tj ET EMC
+/Span <</ActualText <FEFF00E9>>> BDC BT /F1 12 Tf 72 680 Td (e) Tj ET EMC`)
+
+	fragments, err := ex.ExtractFromBytes(content)
+	if err != nil {
+		t.Fatalf("ExtractFromBytes failed: %v", err)
+	}
+	if len(fragments) != 2 {
+		t.Fatalf("expected 2 fragments, got %d", len(fragments))
+	}
+
+	if fragments[0].Text != "office" {
+		t.Errorf("expected 'office', got %q", fragments[0].Text)
+	}
+	if fragments[0].MCID != 3 || !fragments[0].HasMCID {
+		t.Errorf("expected merged fragment to keep MCID 3, got %d", fragments[0].MCID)
+	}
+	if fragments[0].X != 72 || fragments[0].Width <= 0 {
+		t.Errorf("expected merged fragment to cover both runs, got X=%v Width=%v", fragments[0].X, fragments[0].Width)
+	}
+	if fragments[1].Text != "é" {
+		t.Errorf("expected 'é', got %q", fragments[1].Text)
+	}
+}
+
+// TestMarkedContentPropertiesResource tests BDC with a named property list
+func TestMarkedContentPropertiesResource(t *testing.T) {
+	ex := NewExtractor()
+	ex.RegisterFont("/F1", "Helvetica", "Type1")
+
+	resources := core.Dict{
+		"Properties": core.Dict{
+			"MC0": core.IndirectRef{Number: 5},
+		},
+	}
+	resolver := func(ref core.IndirectRef) (core.Object, error) {
+		return core.Dict{"MCID": core.Int(4)}, nil
+	}
+	ex.SetResourceContext(resources, resolver)
+
+	fragments, err := ex.ExtractFromBytes([]byte(`/P /MC0 BDC BT /F1 12 Tf 72 700 Td (Text) Tj ET EMC`))
+	if err != nil {
+		t.Fatalf("ExtractFromBytes failed: %v", err)
+	}
+	if len(fragments) != 1 || fragments[0].MCID != 4 || !fragments[0].HasMCID {
+		t.Errorf("expected a fragment with MCID 4, got %+v", fragments)
+	}
+}
+
+// TestMarkedContentUnbalanced tests that stray EMC operators are ignored
+func TestMarkedContentUnbalanced(t *testing.T) {
+	ex := NewExtractor()
+	ex.RegisterFont("/F1", "Helvetica", "Type1")
+
+	fragments, err := ex.ExtractFromBytes([]byte(`EMC BT /F1 12 Tf 72 700 Td (Text) Tj ET EMC`))
+	if err != nil {
+		t.Fatalf("ExtractFromBytes failed: %v", err)
+	}
+	if len(fragments) != 1 || fragments[0].HasMCID || fragments[0].Artifact {
+		t.Errorf("expected one unmarked fragment, got %+v", fragments)
+	}
+}
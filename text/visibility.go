@@ -0,0 +1,102 @@
+package text
+
+import (
+	"math"
+	"strings"
+
+	"github.com/tsawler/tabula/core"
+	"github.com/tsawler/tabula/model"
+)
+
+// clipTolerance is the slack, in points, allowed when testing whether text
+// lies inside the clipping region
+const clipTolerance = 1.0
+
+// whiteThreshold is the component value above which a color counts as white
+const whiteThreshold = 0.95
+
+// markVisibility records how a fragment is rendered and whether it can be
+// seen. Text in an invisible rendering mode (3, as used for the text layer
+// of OCRed scans, or 7), text whose center lies outside the clipping region,
+// and white text with no image or colored fill painted behind it are not
+// visible.
+func (e *Extractor) markVisibility(f *TextFragment) {
+	mode := e.gs.Text.RenderingMode
+	f.RenderMode = mode
+	f.Color = e.gs.FillColor
+	if mode == 1 || mode == 5 {
+		f.Color = e.gs.StrokeColor
+	}
+
+	cx, cy := f.X+f.Width/2, f.Y+f.Height/3
+	switch {
+	case mode == 3 || mode == 7:
+	case !e.gs.InClip(cx, cy, clipTolerance):
+	case isWhite(f.Color) && !e.onBackground(cx, cy):
+	default:
+		f.Visible = true
+	}
+}
+
+// addImageBackground records the area of an image painted with the current
+// CTM, which maps the image onto the unit square
+func (e *Extractor) addImageBackground() {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [4]model.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}} {
+		p := e.gs.CTM.Transform(corner)
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	e.backgrounds = append(e.backgrounds, model.BBox{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY})
+}
+
+// onBackground reports whether an image or colored fill was painted at a
+// device-space point
+func (e *Extractor) onBackground(x, y float64) bool {
+	for _, b := range e.backgrounds {
+		if x >= b.X && x <= b.X+b.Width && y >= b.Y && y <= b.Y+b.Height {
+			return true
+		}
+	}
+	return false
+}
+
+// isWhite reports whether an RGB color is white or nearly so
+func isWhite(c [3]float64) bool {
+	return c[0] >= whiteThreshold && c[1] >= whiteThreshold && c[2] >= whiteThreshold
+}
+
+// colorComponents returns the numeric operands of a color operator; the
+// pattern name of an SCN or scn operator is skipped
+func colorComponents(operands []core.Object) []float64 {
+	components := make([]float64, 0, len(operands))
+	for _, op := range operands {
+		if v, ok := toFloat(op); ok {
+			components = append(components, v)
+		}
+	}
+	return components
+}
+
+// spaceColor returns the components of an SC, SCN, sc or scn operator in
+// the named color space. A single component is a gray level only in
+// DeviceGray; in other spaces, such as Separation, where it is a tint, it
+// is taken as black so that the text is never wrongly judged invisible.
+func spaceColor(space string, operands []core.Object) []float64 {
+	components := colorComponents(operands)
+	if len(components) == 1 && space != "DeviceGray" && space != "G" {
+		return []float64{0}
+	}
+	return components
+}
+
+// colorSpaceName returns the color space named by a CS or cs operator
+func colorSpaceName(operands []core.Object) string {
+	if len(operands) == 1 {
+		if name, ok := operands[0].(core.Name); ok {
+			return strings.TrimPrefix(string(name), "/")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+package tabula
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildHiddenTextPDF assembles a one-page PDF with visible text, invisible
+// text under it, white-on-white text and text placed off the page
+func buildHiddenTextPDF() []byte {
+	content := `BT /F1 12 Tf 72 700 Td (Visible heading) Tj ET
+q BT /F1 12 Tf 3 Tr 72 700 Td (Invisible layer) Tj ET Q
+1 1 1 rg BT /F1 12 Tf 72 660 Td (White on white) Tj ET
+0 g BT /F1 12 Tf 700 640 Td (Off the page) Tj ET`
+	return buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 3 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	)
+}
+
+func TestVisibleTextOnly(t *testing.T) {
+	hidden := []string{"Invisible layer", "White on white", "Off the page"}
+
+	plain, warnings, err := FromBytes(buildHiddenTextPDF(), "").Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if !strings.Contains(plain, "Visible heading") {
+		t.Errorf("visible text is missing:\n%s", plain)
+	}
+	for _, s := range hidden {
+		if strings.Contains(plain, s) {
+			t.Errorf("got hidden text %q:\n%s", s, plain)
+		}
+	}
+
+	found := false
+	for _, w := range warnings {
+		if w.Code == WarningHiddenText {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("got warnings %v, want WarningHiddenText", warnings)
+	}
+}
+
+func TestVisibleTextOnlyDisabled(t *testing.T) {
+	fragments, warnings, err := FromBytes(buildHiddenTextPDF(), "").VisibleTextOnly(false).Fragments()
+	if err != nil {
+		t.Fatalf("Fragments() error: %v", err)
+	}
+	if len(fragments) != 4 {
+		t.Fatalf("got %d fragments, want 4", len(fragments))
+	}
+	for i, f := range fragments {
+		if want := i == 0; f.Visible != want {
+			t.Errorf("fragment %q visible = %v, want %v", f.Text, f.Visible, want)
+		}
+	}
+	for _, w := range warnings {
+		if w.Code == WarningHiddenText {
+			t.Errorf("got %v with VisibleTextOnly(false)", w)
+		}
+	}
+}
+
+func TestVisibleTextOnlySearchableScan(t *testing.T) {
+	// A searchable scan: the page image with the OCR text drawn invisibly
+	// over it, and no visible text
+	content := `q 612 0 0 792 0 0 cm /Im1 Do Q
+BT /F1 12 Tf 3 Tr 72 700 Td (Scanned invoice) Tj ET`
+	data := buildObjectsPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R /Resources << /Font << /F1 3 0 R >> /XObject << /Im1 6 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /XObject /Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8 /Length 1 >>\nstream\n\x80\nendstream",
+	)
+
+	plain, warnings, err := FromBytes(data, "").Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if !strings.Contains(plain, "Scanned invoice") {
+		t.Errorf("got %q, want the scan's text layer", plain)
+	}
+	for _, w := range warnings {
+		if w.Code == WarningHiddenText {
+			t.Errorf("got %v for a searchable scan", w)
+		}
+	}
+}
+
+func TestExcludeHeadersIgnoresHiddenText(t *testing.T) {
+	// Every page has the same line clipped away at the top, but only the
+	// second shows it, so it is not a running header
+	hiddenLine := "q 0 0 0 0 re W n BT /F1 10 Tf 72 760 Td (Internal draft) Tj ET Q\n"
+	body := "BT /F1 12 Tf 72 600 Td (Body text of page %d) Tj ET"
+	data := buildPagesPDF(
+		hiddenLine+fmt.Sprintf(body, 1),
+		hiddenLine+"BT /F1 10 Tf 72 760 Td (Internal draft) Tj ET\n"+fmt.Sprintf(body, 2),
+		hiddenLine+fmt.Sprintf(body, 3),
+	)
+
+	plain, _, err := FromBytes(data, "").ExcludeHeaders().Text()
+	if err != nil {
+		t.Fatalf("Text() error: %v", err)
+	}
+	if strings.Count(plain, "Internal draft") != 1 {
+		t.Errorf("got text %q, want the visible line once", plain)
+	}
+
+	lines, err := FromBytes(data, "").ExcludeHeaders().Lines()
+	if err != nil {
+		t.Fatalf("Lines() error: %v", err)
+	}
+	found := false
+	for _, line := range lines {
+		found = found || strings.Contains(line.Text, "Internal draft")
+	}
+	if !found {
+		t.Errorf("got lines %v, want the visible line", lines)
+	}
+
+	var streamed string
+	err = FromBytes(data, "").ExcludeHeaders().EachPage(func(p PageResult) error {
+		streamed += p.Text + "\n"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachPage() error: %v", err)
+	}
+	if !strings.Contains(streamed, "Internal draft") {
+		t.Errorf("got streamed text %q, want the visible line", streamed)
+	}
+}
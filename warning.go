@@ -15,6 +15,36 @@ const (
 	// a scanned page that contained no native PDF text. This typically means
 	// the page contains only images (e.g., a scanned document).
 	WarningOCRFallback
+
+	// WarningFormatMismatch indicates that the file extension named one
+	// format but the content is another. The content-detected format was
+	// used.
+	WarningFormatMismatch
+
+	// WarningHiddenText indicates that text on a page was discarded because
+	// it cannot be seen: clipped away, outside the page, white on white, or
+	// drawn invisibly under visible text. Use VisibleTextOnly(false) to keep
+	// it.
+	WarningHiddenText
+
+	// WarningAttachment indicates that an embedded file was left out of the
+	// output of IncludeAttachments, because its format is not supported or
+	// it could not be read.
+	WarningAttachment
+
+	// WarningOCRPreprocess records the cleanup applied to a scanned page's
+	// image before OCR (rotation, deskewing, binarization and so on), as
+	// configured with OCRPreprocess.
+	WarningOCRPreprocess
+
+	// WarningOCRFailed indicates that the OCR engine returned an error for a
+	// scanned page's image, so the page may be missing its scanned text.
+	WarningOCRFailed
+
+	// WarningMetadata indicates that the PDF's document information
+	// dictionary could not be read, so the metadata comes from its XMP
+	// stream alone.
+	WarningMetadata
 )
 
 // Warning represents a non-fatal issue encountered during PDF processing.
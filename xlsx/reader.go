@@ -8,6 +8,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tsawler/tabula/model"
 	"github.com/tsawler/tabula/rag"
@@ -15,13 +16,15 @@ import (
 
 // Reader provides access to XLSX document content.
 type Reader struct {
-	zipReader     *zip.ReadCloser
+	zipReader     *zip.Reader
+	closer        io.Closer // underlying archive when opened from a path
 	workbook      *workbookXML
 	sharedStrings []string
 	styles        *stylesXML
 	rels          *relationshipsXML
 	coreProps     *corePropertiesXML
 	appProps      *appPropertiesXML
+	customProps   *customPropertiesXML
 	sheets        []*Sheet
 	sheetRels     map[string]string // RID -> target path
 }
@@ -33,6 +36,30 @@ func Open(filename string) (*Reader, error) {
 		return nil, fmt.Errorf("opening ZIP archive: %w", err)
 	}
 
+	r, err := newReader(&zr.Reader)
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	r.closer = zr
+
+	return r, nil
+}
+
+// OpenReader opens an XLSX document from an io.ReaderAt of the given size,
+// such as a bytes.Reader over a file held in memory. The caller keeps
+// ownership of ra; Close does not close it.
+func OpenReader(ra io.ReaderAt, size int64) (*Reader, error) {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, fmt.Errorf("opening ZIP archive: %w", err)
+	}
+
+	return newReader(zr)
+}
+
+// newReader parses the document parts of an opened archive.
+func newReader(zr *zip.Reader) (*Reader, error) {
 	r := &Reader{
 		zipReader: zr,
 		sheetRels: make(map[string]string),
@@ -40,19 +67,16 @@ func Open(filename string) (*Reader, error) {
 
 	// Validate required files exist
 	if err := r.validate(); err != nil {
-		zr.Close()
 		return nil, err
 	}
 
 	// Parse relationships first
 	if err := r.parseRelationships(); err != nil {
-		zr.Close()
 		return nil, fmt.Errorf("parsing relationships: %w", err)
 	}
 
 	// Parse workbook to get sheet list
 	if err := r.parseWorkbook(); err != nil {
-		zr.Close()
 		return nil, fmt.Errorf("parsing workbook: %w", err)
 	}
 
@@ -64,22 +88,22 @@ func Open(filename string) (*Reader, error) {
 
 	// Parse all worksheets
 	if err := r.parseWorksheets(); err != nil {
-		zr.Close()
 		return nil, fmt.Errorf("parsing worksheets: %w", err)
 	}
 
 	// Parse metadata (optional)
 	r.parseCoreProperties()
 	r.parseAppProperties()
+	r.parseCustomProperties()
 
 	return r, nil
 }
 
 // Close releases resources associated with the Reader.
 func (r *Reader) Close() error {
-	if r.zipReader != nil {
-		err := r.zipReader.Close()
-		r.zipReader = nil
+	if r.closer != nil {
+		err := r.closer.Close()
+		r.closer = nil
 		return err
 	}
 	return nil
@@ -415,6 +439,17 @@ func (r *Reader) parseAppProperties() {
 	xml.Unmarshal(data, r.appProps)
 }
 
+// parseCustomProperties parses custom document properties.
+func (r *Reader) parseCustomProperties() {
+	data, err := r.getFileContent("docProps/custom.xml")
+	if err != nil {
+		return
+	}
+
+	r.customProps = &customPropertiesXML{}
+	xml.Unmarshal(data, r.customProps)
+}
+
 // SheetCount returns the number of sheets in the workbook.
 func (r *Reader) SheetCount() int {
 	return len(r.sheets)
@@ -668,9 +703,10 @@ func (r *Reader) findContentBounds(sheet *Sheet) (minRow, maxRow, minCol, maxCol
 	return minRow, maxRow, minCol, maxCol
 }
 
-// Metadata returns document metadata.
+// Metadata returns document metadata from the core, application and custom
+// document properties.
 func (r *Reader) Metadata() model.Metadata {
-	meta := model.Metadata{}
+	meta := model.Metadata{Custom: make(map[string]string)}
 	if r.coreProps != nil {
 		meta.Title = r.coreProps.Title
 		meta.Author = r.coreProps.Creator
@@ -681,10 +717,23 @@ func (r *Reader) Metadata() model.Metadata {
 				meta.Keywords[i] = strings.TrimSpace(kw)
 			}
 		}
+		meta.Language = r.coreProps.Language
+		if r.coreProps.Identifier != "" {
+			meta.Identifiers = []string{r.coreProps.Identifier}
+		}
+		meta.CreationDate, _ = time.Parse(time.RFC3339, r.coreProps.Created)
+		meta.ModDate, _ = time.Parse(time.RFC3339, r.coreProps.Modified)
 	}
 	if r.appProps != nil {
 		meta.Creator = r.appProps.Application
 	}
+	if r.customProps != nil {
+		for _, p := range r.customProps.Properties {
+			if p.Name != "" {
+				meta.Custom[p.Name] = strings.TrimSpace(p.Value.Text)
+			}
+		}
+	}
 	return meta
 }
 
@@ -693,20 +742,7 @@ func (r *Reader) Document() (*model.Document, error) {
 	doc := model.NewDocument()
 
 	// Set metadata
-	if r.coreProps != nil {
-		doc.Metadata.Title = r.coreProps.Title
-		doc.Metadata.Author = r.coreProps.Creator
-		doc.Metadata.Subject = r.coreProps.Subject
-		if r.coreProps.Keywords != "" {
-			doc.Metadata.Keywords = strings.Split(r.coreProps.Keywords, ",")
-			for i, kw := range doc.Metadata.Keywords {
-				doc.Metadata.Keywords[i] = strings.TrimSpace(kw)
-			}
-		}
-	}
-	if r.appProps != nil {
-		doc.Metadata.Creator = r.appProps.Application
-	}
+	doc.Metadata = r.Metadata()
 
 	// Each sheet becomes a page
 	for _, sheet := range r.sheets {
@@ -173,6 +173,26 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func TestOpenReader(t *testing.T) {
+	path := createMinimalXLSX(t)
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+
+	r, err := OpenReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenReader() failed: %v", err)
+	}
+	defer r.Close()
+
+	if r.SheetCount() != 1 {
+		t.Errorf("SheetCount() = %d, want 1", r.SheetCount())
+	}
+}
+
 func TestOpen_NotFound(t *testing.T) {
 	_, err := Open("/nonexistent/file.xlsx")
 	if err == nil {